@@ -0,0 +1,1256 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: skillspkg/v1/skillspkg.proto
+
+package skillspkgv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Progress is a progress message, as printed by the CLI.
+type Progress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Progress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListSkillsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSkillsRequest) Reset() {
+	*x = ListSkillsRequest{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSkillsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSkillsRequest) ProtoMessage() {}
+
+func (x *ListSkillsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSkillsRequest.ProtoReflect.Descriptor instead.
+func (*ListSkillsRequest) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{1}
+}
+
+type ListSkillsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skills        []*Skill               `protobuf:"bytes,1,rep,name=skills,proto3" json:"skills,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSkillsResponse) Reset() {
+	*x = ListSkillsResponse{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSkillsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSkillsResponse) ProtoMessage() {}
+
+func (x *ListSkillsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSkillsResponse.ProtoReflect.Descriptor instead.
+func (*ListSkillsResponse) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSkillsResponse) GetSkills() []*Skill {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+type Skill struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Targets       []*SkillTarget         `protobuf:"bytes,5,rep,name=targets,proto3" json:"targets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Skill) Reset() {
+	*x = Skill{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Skill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Skill) ProtoMessage() {}
+
+func (x *Skill) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Skill.ProtoReflect.Descriptor instead.
+func (*Skill) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Skill) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Skill) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Skill) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Skill) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Skill) GetTargets() []*SkillTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// SkillTarget is the state of a skill in an install target.
+type SkillTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// One of "up-to-date", "outdated", "modified", "not-installed", or "unknown".
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Version recorded in the lock file; empty when the skill is not installed.
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	InstalledAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=installed_at,json=installedAt,proto3" json:"installed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkillTarget) Reset() {
+	*x = SkillTarget{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkillTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkillTarget) ProtoMessage() {}
+
+func (x *SkillTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkillTarget.ProtoReflect.Descriptor instead.
+func (*SkillTarget) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SkillTarget) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SkillTarget) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SkillTarget) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SkillTarget) GetInstalledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InstalledAt
+	}
+	return nil
+}
+
+type InstallRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Names of the skills to install. All skills are installed when empty.
+	Skills        []string `protobuf:"bytes,1,rep,name=skills,proto3" json:"skills,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallRequest) Reset() {
+	*x = InstallRequest{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallRequest) ProtoMessage() {}
+
+func (x *InstallRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallRequest.ProtoReflect.Descriptor instead.
+func (*InstallRequest) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InstallRequest) GetSkills() []string {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+type InstallResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*InstallResponse_Progress
+	//	*InstallResponse_Result
+	Event         isInstallResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallResponse) Reset() {
+	*x = InstallResponse{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallResponse) ProtoMessage() {}
+
+func (x *InstallResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallResponse.ProtoReflect.Descriptor instead.
+func (*InstallResponse) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InstallResponse) GetEvent() isInstallResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *InstallResponse) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*InstallResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *InstallResponse) GetResult() *InstallResult {
+	if x != nil {
+		if x, ok := x.Event.(*InstallResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isInstallResponse_Event interface {
+	isInstallResponse_Event()
+}
+
+type InstallResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type InstallResponse_Result struct {
+	Result *InstallResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*InstallResponse_Progress) isInstallResponse_Event() {}
+
+func (*InstallResponse_Result) isInstallResponse_Event() {}
+
+type InstallResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Names of the installed skills; empty when all skills were installed.
+	Installed     []string `protobuf:"bytes,1,rep,name=installed,proto3" json:"installed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallResult) Reset() {
+	*x = InstallResult{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallResult) ProtoMessage() {}
+
+func (x *InstallResult) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallResult.ProtoReflect.Descriptor instead.
+func (*InstallResult) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InstallResult) GetInstalled() []string {
+	if x != nil {
+		return x.Installed
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Names of the skills to update. All skills are updated when empty.
+	Skills []string `protobuf:"bytes,1,rep,name=skills,proto3" json:"skills,omitempty"`
+	// Only check for updates without applying them.
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateRequest) GetSkills() []string {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpdateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*UpdateResponse_Progress
+	//	*UpdateResponse_Result
+	Event         isUpdateResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateResponse) GetEvent() isUpdateResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *UpdateResponse) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*UpdateResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *UpdateResponse) GetResult() *UpdateResult {
+	if x != nil {
+		if x, ok := x.Event.(*UpdateResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isUpdateResponse_Event interface {
+	isUpdateResponse_Event()
+}
+
+type UpdateResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type UpdateResponse_Result struct {
+	Result *UpdateResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*UpdateResponse_Progress) isUpdateResponse_Event() {}
+
+func (*UpdateResponse_Result) isUpdateResponse_Event() {}
+
+type UpdateResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updates       []*SkillUpdate         `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResult) Reset() {
+	*x = UpdateResult{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResult) ProtoMessage() {}
+
+func (x *UpdateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResult.ProtoReflect.Descriptor instead.
+func (*UpdateResult) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateResult) GetUpdates() []*SkillUpdate {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+// SkillUpdate has the fields of 'skills-pkg update --output json'.
+type SkillUpdate struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SkillName      string                 `protobuf:"bytes,1,opt,name=skill_name,json=skillName,proto3" json:"skill_name,omitempty"`
+	CurrentVersion string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	LatestVersion  string                 `protobuf:"bytes,3,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	HasUpdate      bool                   `protobuf:"varint,4,opt,name=has_update,json=hasUpdate,proto3" json:"has_update,omitempty"`
+	HeldBack       bool                   `protobuf:"varint,5,opt,name=held_back,json=heldBack,proto3" json:"held_back,omitempty"`
+	Canary         string                 `protobuf:"bytes,6,opt,name=canary,proto3" json:"canary,omitempty"`
+	// File-level diffs, populated in dry-run mode only.
+	FileDiffs     []*FileDiff `protobuf:"bytes,7,rep,name=file_diffs,json=fileDiffs,proto3" json:"file_diffs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkillUpdate) Reset() {
+	*x = SkillUpdate{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkillUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkillUpdate) ProtoMessage() {}
+
+func (x *SkillUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkillUpdate.ProtoReflect.Descriptor instead.
+func (*SkillUpdate) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SkillUpdate) GetSkillName() string {
+	if x != nil {
+		return x.SkillName
+	}
+	return ""
+}
+
+func (x *SkillUpdate) GetCurrentVersion() string {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return ""
+}
+
+func (x *SkillUpdate) GetLatestVersion() string {
+	if x != nil {
+		return x.LatestVersion
+	}
+	return ""
+}
+
+func (x *SkillUpdate) GetHasUpdate() bool {
+	if x != nil {
+		return x.HasUpdate
+	}
+	return false
+}
+
+func (x *SkillUpdate) GetHeldBack() bool {
+	if x != nil {
+		return x.HeldBack
+	}
+	return false
+}
+
+func (x *SkillUpdate) GetCanary() string {
+	if x != nil {
+		return x.Canary
+	}
+	return ""
+}
+
+func (x *SkillUpdate) GetFileDiffs() []*FileDiff {
+	if x != nil {
+		return x.FileDiffs
+	}
+	return nil
+}
+
+type FileDiff struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// One of "added", "removed", or "modified".
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Patch         string `protobuf:"bytes,3,opt,name=patch,proto3" json:"patch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileDiff) Reset() {
+	*x = FileDiff{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDiff) ProtoMessage() {}
+
+func (x *FileDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDiff.ProtoReflect.Descriptor instead.
+func (*FileDiff) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FileDiff) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileDiff) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FileDiff) GetPatch() string {
+	if x != nil {
+		return x.Patch
+	}
+	return ""
+}
+
+type VerifyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{13}
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Successful    int32                  `protobuf:"varint,2,opt,name=successful,proto3" json:"successful,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	Results       []*VerifyResult        `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	Drifts        []*ConfigDrift         `protobuf:"bytes,5,rep,name=drifts,proto3" json:"drifts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *VerifyResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetSuccessful() int32 {
+	if x != nil {
+		return x.Successful
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetResults() []*VerifyResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *VerifyResponse) GetDrifts() []*ConfigDrift {
+	if x != nil {
+		return x.Drifts
+	}
+	return nil
+}
+
+type VerifyResult struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SkillName  string                 `protobuf:"bytes,1,opt,name=skill_name,json=skillName,proto3" json:"skill_name,omitempty"`
+	InstallDir string                 `protobuf:"bytes,2,opt,name=install_dir,json=installDir,proto3" json:"install_dir,omitempty"`
+	Expected   string                 `protobuf:"bytes,3,opt,name=expected,proto3" json:"expected,omitempty"`
+	Actual     string                 `protobuf:"bytes,4,opt,name=actual,proto3" json:"actual,omitempty"`
+	Match      bool                   `protobuf:"varint,5,opt,name=match,proto3" json:"match,omitempty"`
+	// The hashes differ because of config drift, and the files match the lock file.
+	Drifted       bool `protobuf:"varint,6,opt,name=drifted,proto3" json:"drifted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResult) Reset() {
+	*x = VerifyResult{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResult) ProtoMessage() {}
+
+func (x *VerifyResult) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResult.ProtoReflect.Descriptor instead.
+func (*VerifyResult) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *VerifyResult) GetSkillName() string {
+	if x != nil {
+		return x.SkillName
+	}
+	return ""
+}
+
+func (x *VerifyResult) GetInstallDir() string {
+	if x != nil {
+		return x.InstallDir
+	}
+	return ""
+}
+
+func (x *VerifyResult) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+func (x *VerifyResult) GetActual() string {
+	if x != nil {
+		return x.Actual
+	}
+	return ""
+}
+
+func (x *VerifyResult) GetMatch() bool {
+	if x != nil {
+		return x.Match
+	}
+	return false
+}
+
+func (x *VerifyResult) GetDrifted() bool {
+	if x != nil {
+		return x.Drifted
+	}
+	return false
+}
+
+// ConfigDrift is a disagreement between .skillspkg.toml and .skillspkg.lock.
+type ConfigDrift struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Either "version" or "hash".
+	Kind          string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	SkillName     string `protobuf:"bytes,2,opt,name=skill_name,json=skillName,proto3" json:"skill_name,omitempty"`
+	Target        string `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	Configured    string `protobuf:"bytes,4,opt,name=configured,proto3" json:"configured,omitempty"`
+	Locked        string `protobuf:"bytes,5,opt,name=locked,proto3" json:"locked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigDrift) Reset() {
+	*x = ConfigDrift{}
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigDrift) ProtoMessage() {}
+
+func (x *ConfigDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_skillspkg_v1_skillspkg_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigDrift.ProtoReflect.Descriptor instead.
+func (*ConfigDrift) Descriptor() ([]byte, []int) {
+	return file_skillspkg_v1_skillspkg_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ConfigDrift) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ConfigDrift) GetSkillName() string {
+	if x != nil {
+		return x.SkillName
+	}
+	return ""
+}
+
+func (x *ConfigDrift) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ConfigDrift) GetConfigured() string {
+	if x != nil {
+		return x.Configured
+	}
+	return ""
+}
+
+func (x *ConfigDrift) GetLocked() string {
+	if x != nil {
+		return x.Locked
+	}
+	return ""
+}
+
+var File_skillspkg_v1_skillspkg_proto protoreflect.FileDescriptor
+
+const file_skillspkg_v1_skillspkg_proto_rawDesc = "" +
+	"\n" +
+	"\x1cskillspkg/v1/skillspkg.proto\x12\fskillspkg.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"$\n" +
+	"\bProgress\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x13\n" +
+	"\x11ListSkillsRequest\"A\n" +
+	"\x12ListSkillsResponse\x12+\n" +
+	"\x06skills\x18\x01 \x03(\v2\x13.skillspkg.v1.SkillR\x06skills\"\x94\x01\n" +
+	"\x05Skill\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x123\n" +
+	"\atargets\x18\x05 \x03(\v2\x19.skillspkg.v1.SkillTargetR\atargets\"\x92\x01\n" +
+	"\vSkillTarget\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12=\n" +
+	"\finstalled_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vinstalledAt\"(\n" +
+	"\x0eInstallRequest\x12\x16\n" +
+	"\x06skills\x18\x01 \x03(\tR\x06skills\"\x87\x01\n" +
+	"\x0fInstallResponse\x124\n" +
+	"\bprogress\x18\x01 \x01(\v2\x16.skillspkg.v1.ProgressH\x00R\bprogress\x125\n" +
+	"\x06result\x18\x02 \x01(\v2\x1b.skillspkg.v1.InstallResultH\x00R\x06resultB\a\n" +
+	"\x05event\"-\n" +
+	"\rInstallResult\x12\x1c\n" +
+	"\tinstalled\x18\x01 \x03(\tR\tinstalled\"@\n" +
+	"\rUpdateRequest\x12\x16\n" +
+	"\x06skills\x18\x01 \x03(\tR\x06skills\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\x85\x01\n" +
+	"\x0eUpdateResponse\x124\n" +
+	"\bprogress\x18\x01 \x01(\v2\x16.skillspkg.v1.ProgressH\x00R\bprogress\x124\n" +
+	"\x06result\x18\x02 \x01(\v2\x1a.skillspkg.v1.UpdateResultH\x00R\x06resultB\a\n" +
+	"\x05event\"C\n" +
+	"\fUpdateResult\x123\n" +
+	"\aupdates\x18\x01 \x03(\v2\x19.skillspkg.v1.SkillUpdateR\aupdates\"\x87\x02\n" +
+	"\vSkillUpdate\x12\x1d\n" +
+	"\n" +
+	"skill_name\x18\x01 \x01(\tR\tskillName\x12'\n" +
+	"\x0fcurrent_version\x18\x02 \x01(\tR\x0ecurrentVersion\x12%\n" +
+	"\x0elatest_version\x18\x03 \x01(\tR\rlatestVersion\x12\x1d\n" +
+	"\n" +
+	"has_update\x18\x04 \x01(\bR\thasUpdate\x12\x1b\n" +
+	"\theld_back\x18\x05 \x01(\bR\bheldBack\x12\x16\n" +
+	"\x06canary\x18\x06 \x01(\tR\x06canary\x125\n" +
+	"\n" +
+	"file_diffs\x18\a \x03(\v2\x16.skillspkg.v1.FileDiffR\tfileDiffs\"L\n" +
+	"\bFileDiff\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05patch\x18\x03 \x01(\tR\x05patch\"\x0f\n" +
+	"\rVerifyRequest\"\xc7\x01\n" +
+	"\x0eVerifyResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x1e\n" +
+	"\n" +
+	"successful\x18\x02 \x01(\x05R\n" +
+	"successful\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\x124\n" +
+	"\aresults\x18\x04 \x03(\v2\x1a.skillspkg.v1.VerifyResultR\aresults\x121\n" +
+	"\x06drifts\x18\x05 \x03(\v2\x19.skillspkg.v1.ConfigDriftR\x06drifts\"\xb2\x01\n" +
+	"\fVerifyResult\x12\x1d\n" +
+	"\n" +
+	"skill_name\x18\x01 \x01(\tR\tskillName\x12\x1f\n" +
+	"\vinstall_dir\x18\x02 \x01(\tR\n" +
+	"installDir\x12\x1a\n" +
+	"\bexpected\x18\x03 \x01(\tR\bexpected\x12\x16\n" +
+	"\x06actual\x18\x04 \x01(\tR\x06actual\x12\x14\n" +
+	"\x05match\x18\x05 \x01(\bR\x05match\x12\x18\n" +
+	"\adrifted\x18\x06 \x01(\bR\adrifted\"\x90\x01\n" +
+	"\vConfigDrift\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1d\n" +
+	"\n" +
+	"skill_name\x18\x02 \x01(\tR\tskillName\x12\x16\n" +
+	"\x06target\x18\x03 \x01(\tR\x06target\x12\x1e\n" +
+	"\n" +
+	"configured\x18\x04 \x01(\tR\n" +
+	"configured\x12\x16\n" +
+	"\x06locked\x18\x05 \x01(\tR\x06locked2\xb6\x02\n" +
+	"\rSkillsService\x12O\n" +
+	"\n" +
+	"ListSkills\x12\x1f.skillspkg.v1.ListSkillsRequest\x1a .skillspkg.v1.ListSkillsResponse\x12H\n" +
+	"\aInstall\x12\x1c.skillspkg.v1.InstallRequest\x1a\x1d.skillspkg.v1.InstallResponse0\x01\x12E\n" +
+	"\x06Update\x12\x1b.skillspkg.v1.UpdateRequest\x1a\x1c.skillspkg.v1.UpdateResponse0\x01\x12C\n" +
+	"\x06Verify\x12\x1b.skillspkg.v1.VerifyRequest\x1a\x1c.skillspkg.v1.VerifyResponseB<Z:github.com/mazrean/skills-pkg/api/skillspkg/v1;skillspkgv1b\x06proto3"
+
+var (
+	file_skillspkg_v1_skillspkg_proto_rawDescOnce sync.Once
+	file_skillspkg_v1_skillspkg_proto_rawDescData []byte
+)
+
+func file_skillspkg_v1_skillspkg_proto_rawDescGZIP() []byte {
+	file_skillspkg_v1_skillspkg_proto_rawDescOnce.Do(func() {
+		file_skillspkg_v1_skillspkg_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_skillspkg_v1_skillspkg_proto_rawDesc), len(file_skillspkg_v1_skillspkg_proto_rawDesc)))
+	})
+	return file_skillspkg_v1_skillspkg_proto_rawDescData
+}
+
+var file_skillspkg_v1_skillspkg_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_skillspkg_v1_skillspkg_proto_goTypes = []any{
+	(*Progress)(nil),              // 0: skillspkg.v1.Progress
+	(*ListSkillsRequest)(nil),     // 1: skillspkg.v1.ListSkillsRequest
+	(*ListSkillsResponse)(nil),    // 2: skillspkg.v1.ListSkillsResponse
+	(*Skill)(nil),                 // 3: skillspkg.v1.Skill
+	(*SkillTarget)(nil),           // 4: skillspkg.v1.SkillTarget
+	(*InstallRequest)(nil),        // 5: skillspkg.v1.InstallRequest
+	(*InstallResponse)(nil),       // 6: skillspkg.v1.InstallResponse
+	(*InstallResult)(nil),         // 7: skillspkg.v1.InstallResult
+	(*UpdateRequest)(nil),         // 8: skillspkg.v1.UpdateRequest
+	(*UpdateResponse)(nil),        // 9: skillspkg.v1.UpdateResponse
+	(*UpdateResult)(nil),          // 10: skillspkg.v1.UpdateResult
+	(*SkillUpdate)(nil),           // 11: skillspkg.v1.SkillUpdate
+	(*FileDiff)(nil),              // 12: skillspkg.v1.FileDiff
+	(*VerifyRequest)(nil),         // 13: skillspkg.v1.VerifyRequest
+	(*VerifyResponse)(nil),        // 14: skillspkg.v1.VerifyResponse
+	(*VerifyResult)(nil),          // 15: skillspkg.v1.VerifyResult
+	(*ConfigDrift)(nil),           // 16: skillspkg.v1.ConfigDrift
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+}
+var file_skillspkg_v1_skillspkg_proto_depIdxs = []int32{
+	3,  // 0: skillspkg.v1.ListSkillsResponse.skills:type_name -> skillspkg.v1.Skill
+	4,  // 1: skillspkg.v1.Skill.targets:type_name -> skillspkg.v1.SkillTarget
+	17, // 2: skillspkg.v1.SkillTarget.installed_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: skillspkg.v1.InstallResponse.progress:type_name -> skillspkg.v1.Progress
+	7,  // 4: skillspkg.v1.InstallResponse.result:type_name -> skillspkg.v1.InstallResult
+	0,  // 5: skillspkg.v1.UpdateResponse.progress:type_name -> skillspkg.v1.Progress
+	10, // 6: skillspkg.v1.UpdateResponse.result:type_name -> skillspkg.v1.UpdateResult
+	11, // 7: skillspkg.v1.UpdateResult.updates:type_name -> skillspkg.v1.SkillUpdate
+	12, // 8: skillspkg.v1.SkillUpdate.file_diffs:type_name -> skillspkg.v1.FileDiff
+	15, // 9: skillspkg.v1.VerifyResponse.results:type_name -> skillspkg.v1.VerifyResult
+	16, // 10: skillspkg.v1.VerifyResponse.drifts:type_name -> skillspkg.v1.ConfigDrift
+	1,  // 11: skillspkg.v1.SkillsService.ListSkills:input_type -> skillspkg.v1.ListSkillsRequest
+	5,  // 12: skillspkg.v1.SkillsService.Install:input_type -> skillspkg.v1.InstallRequest
+	8,  // 13: skillspkg.v1.SkillsService.Update:input_type -> skillspkg.v1.UpdateRequest
+	13, // 14: skillspkg.v1.SkillsService.Verify:input_type -> skillspkg.v1.VerifyRequest
+	2,  // 15: skillspkg.v1.SkillsService.ListSkills:output_type -> skillspkg.v1.ListSkillsResponse
+	6,  // 16: skillspkg.v1.SkillsService.Install:output_type -> skillspkg.v1.InstallResponse
+	9,  // 17: skillspkg.v1.SkillsService.Update:output_type -> skillspkg.v1.UpdateResponse
+	14, // 18: skillspkg.v1.SkillsService.Verify:output_type -> skillspkg.v1.VerifyResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_skillspkg_v1_skillspkg_proto_init() }
+func file_skillspkg_v1_skillspkg_proto_init() {
+	if File_skillspkg_v1_skillspkg_proto != nil {
+		return
+	}
+	file_skillspkg_v1_skillspkg_proto_msgTypes[6].OneofWrappers = []any{
+		(*InstallResponse_Progress)(nil),
+		(*InstallResponse_Result)(nil),
+	}
+	file_skillspkg_v1_skillspkg_proto_msgTypes[9].OneofWrappers = []any{
+		(*UpdateResponse_Progress)(nil),
+		(*UpdateResponse_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_skillspkg_v1_skillspkg_proto_rawDesc), len(file_skillspkg_v1_skillspkg_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_skillspkg_v1_skillspkg_proto_goTypes,
+		DependencyIndexes: file_skillspkg_v1_skillspkg_proto_depIdxs,
+		MessageInfos:      file_skillspkg_v1_skillspkg_proto_msgTypes,
+	}.Build()
+	File_skillspkg_v1_skillspkg_proto = out.File
+	file_skillspkg_v1_skillspkg_proto_goTypes = nil
+	file_skillspkg_v1_skillspkg_proto_depIdxs = nil
+}
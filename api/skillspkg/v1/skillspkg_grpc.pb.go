@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: skillspkg/v1/skillspkg.proto
+
+package skillspkgv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SkillsService_ListSkills_FullMethodName = "/skillspkg.v1.SkillsService/ListSkills"
+	SkillsService_Install_FullMethodName    = "/skillspkg.v1.SkillsService/Install"
+	SkillsService_Update_FullMethodName     = "/skillspkg.v1.SkillsService/Update"
+	SkillsService_Verify_FullMethodName     = "/skillspkg.v1.SkillsService/Verify"
+)
+
+// SkillsServiceClient is the client API for SkillsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SkillsService manages the skills of the project that 'skills-pkg serve --grpc' runs in.
+// Clients authenticate with the metadata "authorization: Bearer <token>".
+type SkillsServiceClient interface {
+	// ListSkills returns the configured skills and their state in each install target.
+	ListSkills(ctx context.Context, in *ListSkillsRequest, opts ...grpc.CallOption) (*ListSkillsResponse, error)
+	// Install installs skills. Progress messages are streamed while the skills are
+	// installed, and the last message carries the result.
+	Install(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstallResponse], error)
+	// Update updates skills to their latest versions. Progress messages are streamed
+	// while the skills are updated, and the last message carries the result.
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UpdateResponse], error)
+	// Verify verifies the installed skills against their recorded hashes.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+}
+
+type skillsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSkillsServiceClient(cc grpc.ClientConnInterface) SkillsServiceClient {
+	return &skillsServiceClient{cc}
+}
+
+func (c *skillsServiceClient) ListSkills(ctx context.Context, in *ListSkillsRequest, opts ...grpc.CallOption) (*ListSkillsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSkillsResponse)
+	err := c.cc.Invoke(ctx, SkillsService_ListSkills_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skillsServiceClient) Install(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstallResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SkillsService_ServiceDesc.Streams[0], SkillsService_Install_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InstallRequest, InstallResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SkillsService_InstallClient = grpc.ServerStreamingClient[InstallResponse]
+
+func (c *skillsServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UpdateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SkillsService_ServiceDesc.Streams[1], SkillsService_Update_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UpdateRequest, UpdateResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SkillsService_UpdateClient = grpc.ServerStreamingClient[UpdateResponse]
+
+func (c *skillsServiceClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, SkillsService_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SkillsServiceServer is the server API for SkillsService service.
+// All implementations must embed UnimplementedSkillsServiceServer
+// for forward compatibility.
+//
+// SkillsService manages the skills of the project that 'skills-pkg serve --grpc' runs in.
+// Clients authenticate with the metadata "authorization: Bearer <token>".
+type SkillsServiceServer interface {
+	// ListSkills returns the configured skills and their state in each install target.
+	ListSkills(context.Context, *ListSkillsRequest) (*ListSkillsResponse, error)
+	// Install installs skills. Progress messages are streamed while the skills are
+	// installed, and the last message carries the result.
+	Install(*InstallRequest, grpc.ServerStreamingServer[InstallResponse]) error
+	// Update updates skills to their latest versions. Progress messages are streamed
+	// while the skills are updated, and the last message carries the result.
+	Update(*UpdateRequest, grpc.ServerStreamingServer[UpdateResponse]) error
+	// Verify verifies the installed skills against their recorded hashes.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	mustEmbedUnimplementedSkillsServiceServer()
+}
+
+// UnimplementedSkillsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSkillsServiceServer struct{}
+
+func (UnimplementedSkillsServiceServer) ListSkills(context.Context, *ListSkillsRequest) (*ListSkillsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSkills not implemented")
+}
+func (UnimplementedSkillsServiceServer) Install(*InstallRequest, grpc.ServerStreamingServer[InstallResponse]) error {
+	return status.Error(codes.Unimplemented, "method Install not implemented")
+}
+func (UnimplementedSkillsServiceServer) Update(*UpdateRequest, grpc.ServerStreamingServer[UpdateResponse]) error {
+	return status.Error(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedSkillsServiceServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedSkillsServiceServer) mustEmbedUnimplementedSkillsServiceServer() {}
+func (UnimplementedSkillsServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeSkillsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SkillsServiceServer will
+// result in compilation errors.
+type UnsafeSkillsServiceServer interface {
+	mustEmbedUnimplementedSkillsServiceServer()
+}
+
+func RegisterSkillsServiceServer(s grpc.ServiceRegistrar, srv SkillsServiceServer) {
+	// If the following call panics, it indicates UnimplementedSkillsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SkillsService_ServiceDesc, srv)
+}
+
+func _SkillsService_ListSkills_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSkillsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkillsServiceServer).ListSkills(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SkillsService_ListSkills_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkillsServiceServer).ListSkills(ctx, req.(*ListSkillsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkillsService_Install_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InstallRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SkillsServiceServer).Install(m, &grpc.GenericServerStream[InstallRequest, InstallResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SkillsService_InstallServer = grpc.ServerStreamingServer[InstallResponse]
+
+func _SkillsService_Update_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UpdateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SkillsServiceServer).Update(m, &grpc.GenericServerStream[UpdateRequest, UpdateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SkillsService_UpdateServer = grpc.ServerStreamingServer[UpdateResponse]
+
+func _SkillsService_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkillsServiceServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SkillsService_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkillsServiceServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SkillsService_ServiceDesc is the grpc.ServiceDesc for SkillsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SkillsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "skillspkg.v1.SkillsService",
+	HandlerType: (*SkillsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSkills",
+			Handler:    _SkillsService_ListSkills_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _SkillsService_Verify_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Install",
+			Handler:       _SkillsService_Install_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Update",
+			Handler:       _SkillsService_Update_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "skillspkg/v1/skillspkg.proto",
+}
@@ -0,0 +1,55 @@
+package agent
+
+import "github.com/mazrean/skills-pkg/internal/port"
+
+// All returns an adapter instance for every supported agent.
+// It is used to recognize install targets that belong to an agent.
+func All() []port.AgentProvider {
+	return []port.AgentProvider{
+		NewClaude(),
+		NewClaudeCode(),
+		NewCodex(),
+		NewCursor(),
+		NewCopilot(),
+		NewGithubCopilot(),
+		NewGoose(),
+		NewOpencode(),
+		NewGemini(),
+		NewGeminiCLI(),
+		NewAmp(),
+		NewKimiCLI(),
+		NewReplit(),
+		NewUniversal(),
+		NewFactory(),
+		NewDroid(),
+		NewAntigravity(),
+		NewAugment(),
+		NewOpenclaw(),
+		NewCline(),
+		NewCodebuddy(),
+		NewCommandCode(),
+		NewContinueAgent(),
+		NewCortex(),
+		NewCrush(),
+		NewJunie(),
+		NewIflowCLI(),
+		NewKilo(),
+		NewKiroCLI(),
+		NewKode(),
+		NewMCPJam(),
+		NewMistralVibe(),
+		NewMux(),
+		NewOpenhands(),
+		NewPi(),
+		NewQoder(),
+		NewQwenCode(),
+		NewRoo(),
+		NewTrae(),
+		NewTraeCN(),
+		NewWindsurf(),
+		NewZencoder(),
+		NewNeovate(),
+		NewPochi(),
+		NewAdal(),
+	}
+}
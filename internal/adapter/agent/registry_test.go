@@ -0,0 +1,25 @@
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/agent"
+)
+
+func TestAll(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, provider := range agent.All() {
+		name := provider.AgentName()
+		if seen[name] {
+			t.Errorf("agent %s is listed more than once", name)
+		}
+		seen[name] = true
+
+		if _, err := provider.ResolveAgentDir(name); err != nil {
+			t.Errorf("ResolveAgentDir(%s) error = %v", name, err)
+		}
+		if provider.ProjectDir() == "" {
+			t.Errorf("ProjectDir() of %s is empty", name)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Package command runs the external commands that adapters delegate to, such as the job schedulers,
+// notification tools, and openers of the platform.
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run executes the command and includes its output in the returned error.
+func Run(ctx context.Context, name string, args ...string) error {
+	_, err := Output(ctx, nil, name, args...)
+	return err
+}
+
+// Output executes the command with stdin, unless it is nil, and returns its combined output.
+// Like Run, it includes the output in the returned error.
+func Output(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("%s failed: %w: %s", strings.Join(append([]string{name}, args...), " "), err, bytes.TrimSpace(out))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestOutput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	ctx := context.Background()
+
+	out, err := Output(ctx, []byte("input\n"), "sh", "-c", "cat")
+	if err != nil || string(out) != "input\n" {
+		t.Errorf("Output() = %q, %v, want the standard input", out, err)
+	}
+
+	err = Run(ctx, "sh", "-c", "echo broken >&2; exit 3")
+	if err == nil || !strings.Contains(err.Error(), "sh -c echo broken >&2; exit 3 failed") || !strings.HasSuffix(err.Error(), ": broken") {
+		t.Errorf("Run() of a failing command error = %v, want the command line and its output", err)
+	}
+}
@@ -0,0 +1,317 @@
+// Package filesystem provides implementations of the FileSystem interface.
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+var _ port.FileSystem = (*Memory)(nil)
+
+// errIsDir is returned when a directory is read or written as a file.
+var errIsDir = errors.New("is a directory")
+
+// errNotDir is returned when a file is used as a directory.
+var errNotDir = errors.New("not a directory")
+
+// Memory is an implementation of FileSystem that keeps files in memory.
+// It is meant for tests, and does not support symbolic links. Paths are cleaned with
+// filepath.Clean, and the root directories of all volumes always exist.
+type Memory struct {
+	nodes map[string]*memoryNode
+	now   func() time.Time
+	mu    sync.RWMutex
+}
+
+type memoryNode struct {
+	modTime time.Time
+	data    []byte
+	mode    fs.FileMode
+}
+
+// NewMemory creates a new empty Memory instance.
+func NewMemory() *Memory {
+	return &Memory{nodes: make(map[string]*memoryNode), now: time.Now}
+}
+
+// Open opens the named file for reading.
+func (m *Memory) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = filepath.Clean(name)
+	node, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := newMemoryFileInfo(name, node)
+	if node.mode.IsDir() {
+		entries, err := m.readDir("open", name)
+		if err != nil {
+			return nil, err
+		}
+		return &memoryDir{info: info, entries: entries}, nil
+	}
+	return &memoryFile{info: info, Reader: bytes.NewReader(slices.Clone(node.data))}, nil
+}
+
+// Stat returns the information of the named file.
+func (m *Memory) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = filepath.Clean(name)
+	node, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return newMemoryFileInfo(name, node), nil
+}
+
+// Lstat returns the information of the named file. It is the same as Stat, as links are not supported.
+func (m *Memory) Lstat(name string) (fs.FileInfo, error) {
+	return m.Stat(name)
+}
+
+// ReadDir returns the entries of the named directory sorted by name.
+func (m *Memory) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.readDir("readdir", filepath.Clean(name))
+}
+
+// ReadFile returns a copy of the contents of the named file.
+func (m *Memory) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = filepath.Clean(name)
+	node, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return slices.Clone(node.data), nil
+}
+
+// WriteFile writes a copy of data to the named file. The parent directory must exist.
+func (m *Memory) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	if err := m.checkParent("open", name); err != nil {
+		return err
+	}
+	if node, ok := m.nodes[name]; ok {
+		if node.mode.IsDir() {
+			return &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+		}
+		// Like os.WriteFile, the permissions of an existing file are kept
+		perm = node.mode.Perm()
+	}
+	m.nodes[name] = &memoryNode{data: slices.Clone(data), mode: perm.Perm(), modTime: m.now()}
+	return nil
+}
+
+// MkdirAll creates the directory and any missing parents.
+func (m *Memory) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	var missing []string
+	for dir := path; !isRoot(dir); dir = filepath.Dir(dir) {
+		node, ok := m.nodes[dir]
+		if ok {
+			if !node.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: errNotDir}
+			}
+			break
+		}
+		missing = append(missing, dir)
+	}
+	for _, dir := range slices.Backward(missing) {
+		m.nodes[dir] = &memoryNode{mode: fs.ModeDir | perm.Perm(), modTime: m.now()}
+	}
+	return nil
+}
+
+// RemoveAll removes the path and anything it contains.
+func (m *Memory) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	for name := range m.nodes {
+		if name == path || isWithin(path, name) {
+			delete(m.nodes, name)
+		}
+	}
+	return nil
+}
+
+// Rename moves oldpath and anything it contains to newpath.
+func (m *Memory) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	node, err := m.lookup("rename", oldpath)
+	if err != nil {
+		return err
+	}
+	if err := m.checkParent("rename", newpath); err != nil {
+		return err
+	}
+	if node.mode.IsDir() && isWithin(oldpath, newpath) {
+		return &fs.PathError{Op: "rename", Path: newpath, Err: fs.ErrInvalid}
+	}
+	if target, ok := m.nodes[newpath]; ok && target.mode.IsDir() {
+		if !node.mode.IsDir() {
+			return &fs.PathError{Op: "rename", Path: newpath, Err: errIsDir}
+		}
+		if entries, _ := m.readDir("rename", newpath); len(entries) > 0 {
+			return &fs.PathError{Op: "rename", Path: newpath, Err: fs.ErrExist}
+		}
+	}
+
+	moved := make(map[string]*memoryNode)
+	for name, n := range m.nodes {
+		if name == oldpath {
+			moved[newpath] = n
+		} else if isWithin(oldpath, name) {
+			moved[filepath.Join(newpath, strings.TrimPrefix(name, oldpath))] = n
+		} else {
+			continue
+		}
+		delete(m.nodes, name)
+	}
+	maps.Copy(m.nodes, moved)
+	return nil
+}
+
+// lookup returns the node of the cleaned name. The caller must hold the lock.
+func (m *Memory) lookup(op, name string) (*memoryNode, error) {
+	if isRoot(name) {
+		return &memoryNode{mode: fs.ModeDir | 0o755}, nil
+	}
+	node, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return node, nil
+}
+
+// checkParent returns an error unless the parent of the cleaned name is a directory. The caller must hold the lock.
+func (m *Memory) checkParent(op, name string) error {
+	parent, err := m.lookup(op, filepath.Dir(name))
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if !parent.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return nil
+}
+
+// readDir returns the entries of the cleaned directory name sorted by name. The caller must hold the lock.
+func (m *Memory) readDir(op, name string) ([]fs.DirEntry, error) {
+	node, err := m.lookup(op, name)
+	if err != nil {
+		return nil, err
+	}
+	if !node.mode.IsDir() {
+		return nil, &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+
+	var entries []fs.DirEntry
+	for child, childNode := range m.nodes {
+		if child != name && filepath.Dir(child) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(newMemoryFileInfo(child, childNode)))
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// isRoot reports whether the cleaned path is the root directory of its volume.
+func isRoot(path string) bool {
+	return filepath.Dir(path) == path
+}
+
+// isWithin reports whether the cleaned path name is inside the cleaned directory dir.
+func isWithin(dir, name string) bool {
+	rel, err := filepath.Rel(dir, name)
+	return err == nil && rel != "." && filepath.IsLocal(rel)
+}
+
+// memoryFileInfo is the fs.FileInfo of a node of a Memory file system.
+type memoryFileInfo struct {
+	modTime time.Time
+	name    string
+	size    int64
+	mode    fs.FileMode
+}
+
+func newMemoryFileInfo(name string, node *memoryNode) *memoryFileInfo {
+	return &memoryFileInfo{name: filepath.Base(name), size: int64(len(node.data)), mode: node.mode, modTime: node.modTime}
+}
+
+func (i *memoryFileInfo) Name() string       { return i.name }
+func (i *memoryFileInfo) Size() int64        { return i.size }
+func (i *memoryFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *memoryFileInfo) ModTime() time.Time { return i.modTime }
+func (i *memoryFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *memoryFileInfo) Sys() any           { return nil }
+
+// memoryFile is an open regular file of a Memory file system, holding a snapshot of its contents.
+type memoryFile struct {
+	*bytes.Reader
+	info *memoryFileInfo
+}
+
+func (f *memoryFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memoryFile) Close() error               { return nil }
+
+// memoryDir is an open directory of a Memory file system, holding a snapshot of its entries.
+type memoryDir struct {
+	info    *memoryFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memoryDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memoryDir) Close() error               { return nil }
+
+func (d *memoryDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errIsDir}
+}
+
+// ReadDir returns the next n entries, or all remaining entries when n <= 0, as fs.ReadDirFile specifies.
+func (d *memoryDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	m := NewMemory()
+	root := filepath.Join(string(filepath.Separator), "skills")
+
+	if err := m.WriteFile(filepath.Join(root, "SKILL.md"), []byte("skill"), 0o644); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("WriteFile() without parent error = %v, want fs.ErrNotExist", err)
+	}
+	if err := m.MkdirAll(filepath.Join(root, "a", "docs"), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	for name, content := range map[string]string{"a/SKILL.md": "skill", "a/docs/guide.md": "guide", "a/b.md": "b"} {
+		if err := m.WriteFile(filepath.Join(root, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+	}
+
+	entries, err := m.ReadDir(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"SKILL.md", "b.md", "docs"}; !slices.Equal(names, want) {
+		t.Errorf("ReadDir() = %v, want %v", names, want)
+	}
+
+	f, err := m.Open(filepath.Join(root, "a", "docs", "guide.md"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != "guide" {
+		t.Errorf("Open() read %q, %v, want %q", data, err, "guide")
+	}
+	_ = f.Close()
+
+	if err := m.Rename(filepath.Join(root, "a"), filepath.Join(root, "c")); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if data, err := m.ReadFile(filepath.Join(root, "c", "docs", "guide.md")); err != nil || string(data) != "guide" {
+		t.Errorf("ReadFile() after Rename = %q, %v", data, err)
+	}
+	if _, err := m.Stat(filepath.Join(root, "a")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat() of renamed directory error = %v, want fs.ErrNotExist", err)
+	}
+
+	if err := m.RemoveAll(filepath.Join(root, "c")); err != nil {
+		t.Fatalf("RemoveAll() error = %v", err)
+	}
+	if entries, err := m.ReadDir(root); err != nil || len(entries) != 0 {
+		t.Errorf("ReadDir() after RemoveAll = %v, %v, want no entries", entries, err)
+	}
+	if err := m.RemoveAll(filepath.Join(root, "missing")); err != nil {
+		t.Errorf("RemoveAll() of a missing path error = %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package network
+
+import (
+	"cmp"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// requestHeaders are set on every request, or nil before SetHeaders is called.
+var requestHeaders atomic.Pointer[headers]
+
+// headers are the User-Agent of every request and the extra headers of the requests to some hosts.
+type headers struct {
+	userAgent string
+	rules     []headerRule
+}
+
+// headerRule is a set of extra headers and the requests they are added to.
+type headerRule struct {
+	match  string // Host name, "*." wildcard of the subdomains of a domain, or URL prefix with a scheme
+	header http.Header
+}
+
+// matches reports whether the headers of the rule are added to a request to u.
+func (r *headerRule) matches(u *url.URL) bool {
+	if strings.Contains(r.match, "://") {
+		s := u.String()
+		if !strings.HasPrefix(s, r.match) {
+			return false
+		}
+		// A prefix without a trailing slash matches whole path segments only
+		return len(s) == len(r.match) || strings.HasSuffix(r.match, "/") || strings.ContainsRune("/?#", rune(s[len(r.match)]))
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if domain, ok := strings.CutPrefix(r.match, "*."); ok {
+		return strings.HasSuffix(host, "."+domain)
+	}
+	return host == r.match
+}
+
+// SetHeaders sets the User-Agent of every request to userAgent, and adds the extra headers to the
+// requests that do not set them themselves. extra is keyed by the host names, "*." wildcards, or URL
+// prefixes of the requests that get the headers, so that they are not sent to other hosts. The headers
+// of longer keys take precedence. An empty userAgent keeps the User-Agent of the adapter.
+func SetHeaders(userAgent string, extra map[string]map[string]string) {
+	h := &headers{userAgent: userAgent, rules: make([]headerRule, 0, len(extra))}
+	for match, values := range extra {
+		rule := headerRule{match: match, header: make(http.Header, len(values))}
+		if !strings.Contains(match, "://") {
+			rule.match = strings.ToLower(match)
+		}
+		for name, value := range values {
+			rule.header.Set(name, value)
+		}
+		h.rules = append(h.rules, rule)
+	}
+	slices.SortFunc(h.rules, func(a, b headerRule) int {
+		return cmp.Or(cmp.Compare(len(b.match), len(a.match)), cmp.Compare(a.match, b.match))
+	})
+	requestHeaders.Store(h)
+}
+
+// withHeaders returns req with the headers set by SetHeaders. The request is cloned when
+// headers are added, as a RoundTripper must not modify the request it is given.
+func withHeaders(req *http.Request) *http.Request {
+	h := requestHeaders.Load()
+	if h == nil {
+		return req
+	}
+	var rules []*headerRule
+	for i := range h.rules {
+		if h.rules[i].matches(req.URL) {
+			rules = append(rules, &h.rules[i])
+		}
+	}
+	if h.userAgent == "" && len(rules) == 0 {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	if h.userAgent != "" {
+		req.Header.Set("User-Agent", h.userAgent)
+	}
+	for _, rule := range rules {
+		for name, values := range rule.header {
+			if _, ok := req.Header[name]; !ok {
+				req.Header[name] = values
+			}
+		}
+	}
+	return req
+}
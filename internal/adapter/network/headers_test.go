@@ -0,0 +1,103 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSetHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+	t.Cleanup(func() { requestHeaders.Store(nil) })
+
+	get := func(header http.Header) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+		resp, err := Client().Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if len(req.Header) != len(header) {
+			t.Error("the headers of the request given to the client were modified")
+		}
+	}
+
+	host := serverURL(t, server).Hostname()
+	SetHeaders("skills-pkg/v1.2.3", map[string]map[string]string{host: {"x-proxy-route": "skills", "Accept": "*/*"}})
+	get(http.Header{"Accept": {"application/json"}, "User-Agent": {"go-git/5"}})
+	if ua := got.Get("User-Agent"); ua != "skills-pkg/v1.2.3" {
+		t.Errorf("User-Agent = %q, want skills-pkg/v1.2.3", ua)
+	}
+	if route := got.Get("X-Proxy-Route"); route != "skills" {
+		t.Errorf("X-Proxy-Route = %q, want skills", route)
+	}
+	if accept := got.Get("Accept"); accept != "application/json" {
+		t.Errorf("Accept = %q, want the header of the request to take precedence", accept)
+	}
+
+	SetHeaders("", map[string]map[string]string{"other.example.com": {"X-Proxy-Route": "skills"}})
+	get(http.Header{})
+	if got.Get("X-Proxy-Route") != "" {
+		t.Error("headers of another host were sent")
+	}
+
+	SetHeaders("", nil)
+	get(http.Header{"User-Agent": {"go-git/5"}})
+	if ua := got.Get("User-Agent"); ua != "go-git/5" {
+		t.Errorf("User-Agent without a configured one = %q, want the one of the request", ua)
+	}
+	if got.Get("X-Proxy-Route") != "" {
+		t.Error("headers are still added after they were cleared")
+	}
+}
+
+func serverURL(t *testing.T, server *httptest.Server) *url.URL {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestHeaderRule_Matches(t *testing.T) {
+	tests := []struct {
+		match string
+		url   string
+		want  bool
+	}{
+		{match: "registry.example.com", url: "https://registry.example.com/v2/", want: true},
+		{match: "registry.example.com", url: "https://registry.example.com:8443/v2/", want: true},
+		{match: "registry.example.com", url: "https://example.com/", want: false},
+		{match: "registry.example.com", url: "https://registry.example.com.evil.test/", want: false},
+		{match: "*.example.com", url: "https://npm.example.com/", want: true},
+		{match: "*.example.com", url: "https://example.com/", want: false},
+		{match: "https://example.com/private", url: "https://example.com/private/skill.tgz", want: true},
+		{match: "https://example.com/private", url: "https://example.com/private", want: true},
+		{match: "https://example.com/private", url: "https://example.com/private-other/", want: false},
+		{match: "https://example.com/private/", url: "https://example.com/private/skill.tgz", want: true},
+		{match: "https://example.com", url: "https://example.com.evil.test/", want: false},
+		{match: "https://example.com/", url: "http://example.com/", want: false},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule := &headerRule{match: tt.match}
+		if got := rule.matches(u); got != tt.want {
+			t.Errorf("headerRule{%q}.matches(%s) = %v, want %v", tt.match, tt.url, got, tt.want)
+		}
+	}
+}
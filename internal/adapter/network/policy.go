@@ -0,0 +1,82 @@
+package network
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// hostPolicy restricts the hosts downloads connect to, or is nil when every host is allowed.
+var hostPolicy atomic.Pointer[domain.NetworkPolicy]
+
+// SetPolicy restricts the hosts that downloads connect to. A nil policy allows every host.
+func SetPolicy(policy *domain.NetworkPolicy) {
+	hostPolicy.Store(policy)
+}
+
+// CheckHost returns a *domain.ErrorHostNotAllowed when the network policy refuses connecting to host.
+// Host names are resolved to check them against the address ranges of the policy; a name that does
+// not resolve is only allowed by its name. It fails early, before credentials are looked up; the
+// addresses are checked again by DialContext when connecting, as a name can resolve differently then.
+func CheckHost(ctx context.Context, host string) error {
+	policy := hostPolicy.Load()
+	if policy == nil {
+		return nil
+	}
+
+	host = strings.Trim(host, "[]")
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else if policy.MatchesAddresses() {
+		addrs, _ = net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	}
+	return policy.Check(host, addrs)
+}
+
+// ChecksAddresses reports whether the network policy has address ranges, which connections can
+// only be checked against when they are made with DialContext.
+func ChecksAddresses() bool {
+	policy := hostPolicy.Load()
+	return policy != nil && policy.MatchesAddresses()
+}
+
+// DialContext connects to address like net.Dialer.DialContext, refusing with a
+// *domain.ErrorHostNotAllowed each address the host resolved to that the network policy refuses.
+// The addresses are checked after they are resolved, right before connecting to them, so that the
+// host cannot resolve to other addresses between the check and the connection.
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			return checkAddress(host, addrPort.Addr())
+		},
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// checkAddress returns a *domain.ErrorHostNotAllowed when the network policy refuses connecting
+// to host at addr.
+func checkAddress(host string, addr netip.Addr) error {
+	policy := hostPolicy.Load()
+	if policy == nil {
+		return nil
+	}
+	return policy.Check(host, []netip.Addr{addr.Unmap()})
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestSetPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://blocked.invalid/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	t.Cleanup(func() { SetPolicy(nil) })
+
+	get := func(url string) error {
+		t.Helper()
+		resp, err := Client().Get(url)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	policy, err := (&domain.NetworkSettings{AllowedHosts: []string{"127.0.0.0/8", "::1"}, DenyByDefault: true}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetPolicy(policy)
+	if err := get(server.URL); err != nil {
+		t.Errorf("Get() of an allowed host error = %v", err)
+	}
+	if err := get(server.URL + "/redirect"); !isHostNotAllowed(err) {
+		t.Errorf("Get() redirected to a host that is not allowed error = %v, want ErrorHostNotAllowed", err)
+	}
+
+	policy, err = (&domain.NetworkSettings{DeniedHosts: []string{"127.0.0.1"}}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetPolicy(policy)
+	if err := get(server.URL); !isHostNotAllowed(err) {
+		t.Errorf("Get() of a denied host error = %v, want ErrorHostNotAllowed", err)
+	}
+
+	SetPolicy(nil)
+	if err := get(server.URL); err != nil {
+		t.Errorf("Get() without a policy error = %v", err)
+	}
+}
+
+func isHostNotAllowed(err error) bool {
+	_, ok := errors.AsType[*domain.ErrorHostNotAllowed](err)
+	return ok
+}
+
+func TestDialContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	t.Cleanup(func() { SetPolicy(nil) })
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	dial := func(host string) error {
+		t.Helper()
+		conn, err := DialContext(context.Background(), "tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	// The address localhost resolves to when connecting is checked, not only the name
+	policy, err := (&domain.NetworkSettings{DeniedHosts: []string{"127.0.0.0/8", "::1"}}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetPolicy(policy)
+	if err := dial("localhost"); !isHostNotAllowed(err) {
+		t.Errorf("DialContext() of a name resolving to a denied address error = %v, want ErrorHostNotAllowed", err)
+	}
+
+	// Hosts allowed by their name are connected to at any address
+	policy, err = (&domain.NetworkSettings{AllowedHosts: []string{"localhost"}, DeniedHosts: []string{"127.0.0.0/8", "::1"}}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetPolicy(policy)
+	if err := dial("localhost"); err != nil {
+		t.Errorf("DialContext() of a host allowed by name error = %v", err)
+	}
+	if err := dial("127.0.0.1"); !isHostNotAllowed(err) {
+		t.Errorf("DialContext() of a denied address error = %v, want ErrorHostNotAllowed", err)
+	}
+
+	SetPolicy(nil)
+	if err := dial("127.0.0.1"); err != nil {
+		t.Errorf("DialContext() without a policy error = %v", err)
+	}
+}
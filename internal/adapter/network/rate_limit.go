@@ -0,0 +1,73 @@
+package network
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// rateLimiter is the bandwidth limit shared by every download, or nil when downloads are not limited.
+var rateLimiter atomic.Pointer[limiter]
+
+// SetRateLimit limits the bandwidth of all downloads together to bytesPerSecond.
+// Values below 1 remove the limit.
+func SetRateLimit(bytesPerSecond int64) {
+	if bytesPerSecond < 1 {
+		rateLimiter.Store(nil)
+		return
+	}
+	rateLimiter.Store(&limiter{rate: float64(bytesPerSecond), chunk: chunkSize(bytesPerSecond)})
+}
+
+// limiter schedules reads so that the bytes read by all of them stay within rate bytes per second.
+type limiter struct {
+	next  time.Time // When the bytes reserved so far have been read at the rate
+	rate  float64
+	chunk int // Largest read, so that a download is throttled smoothly instead of in bursts
+	mu    sync.Mutex
+}
+
+// chunkSize returns the largest read for the rate: a tenth of a second of bandwidth, within 1 KiB and 64 KiB.
+func chunkSize(bytesPerSecond int64) int {
+	return int(min(max(bytesPerSecond/10, 1<<10), 64<<10))
+}
+
+// reserve reserves n bytes of bandwidth and returns how long the reader must wait before using them.
+func (l *limiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	return l.next.Sub(now)
+}
+
+// throttledBody reads a response body within the rate limit.
+type throttledBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *limiter
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	if len(p) > b.limiter.chunk {
+		p = p[:b.limiter.chunk]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		timer := time.NewTimer(b.limiter.reserve(n))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-b.ctx.Done():
+			return n, b.ctx.Err()
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,48 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetRateLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 20<<10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	download := func() time.Duration {
+		t.Helper()
+		start := time.Now()
+		resp, err := Client().Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(body, payload) {
+			t.Fatalf("downloaded %d bytes, want %d", len(body), len(payload))
+		}
+		return time.Since(start)
+	}
+
+	// 20 KiB at 40 KiB/s takes about half a second
+	SetRateLimit(40 << 10)
+	t.Cleanup(func() { SetRateLimit(0) })
+	if elapsed := download(); elapsed < 400*time.Millisecond {
+		t.Errorf("throttled download took %v, want about 500ms", elapsed)
+	}
+
+	SetRateLimit(0)
+	if elapsed := download(); elapsed > 300*time.Millisecond {
+		t.Errorf("download without a limit took %v", elapsed)
+	}
+}
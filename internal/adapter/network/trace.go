@@ -0,0 +1,123 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// traceOutput receives the trace of the requests and operations of the adapters, or is nil when
+// tracing is off.
+var traceOutput atomic.Pointer[tracer]
+
+// tracer writes trace lines to w, one at a time, as adapters trace concurrently.
+type tracer struct {
+	w    io.Writer
+	mu   sync.Mutex
+	dump bool // Also write the headers of requests and responses, and the output of operations
+}
+
+// SetTrace writes a line for every request and its response, and for every operation that adapters
+// trace with Tracef, such as git clones, to w. With dump, the headers of requests and responses
+// and the progress output of operations are written too, with the values of credentials redacted.
+// A nil w turns tracing off.
+func SetTrace(w io.Writer, dump bool) {
+	if w == nil {
+		traceOutput.Store(nil)
+		return
+	}
+	traceOutput.Store(&tracer{w: w, dump: dump})
+}
+
+// Tracef writes a line to the trace, such as the command line equivalent to an operation of an adapter.
+// It is a no-op when tracing is off.
+func Tracef(format string, args ...any) {
+	if t := traceOutput.Load(); t != nil {
+		t.printf(format, args...)
+	}
+}
+
+// TraceOutput returns the writer that operations write their progress output to, such as the messages
+// of a git server, or nil unless the trace dumps output.
+func TraceOutput() io.Writer {
+	t := traceOutput.Load()
+	if t == nil || !t.dump {
+		return nil
+	}
+	return &traceWriter{t: t}
+}
+
+func (t *tracer) printf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = fmt.Fprintf(t.w, "[TRACE] "+format+"\n", args...)
+}
+
+// request writes the request line, and its headers when dumping.
+func (t *tracer) request(req *http.Request) {
+	t.printf("> %s %s", req.Method, req.URL.Redacted())
+	if t.dump {
+		t.headers(">", req.Header)
+	}
+}
+
+// response writes the status of the response to req, or the error that prevented it, and the headers
+// of the response when dumping.
+func (t *tracer) response(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		t.printf("< %s %s failed after %s: %v", req.Method, req.URL.Redacted(), elapsed, err)
+		return
+	}
+	t.printf("< %s for %s %s in %s", resp.Status, req.Method, req.URL.Redacted(), elapsed)
+	if t.dump {
+		t.headers("<", resp.Header)
+	}
+}
+
+// headers writes the headers in name order, redacting credentials.
+func (t *tracer) headers(direction string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			if isCredentialHeader(name) {
+				value = "[REDACTED]"
+			}
+			t.printf("%s %s: %s", direction, name, value)
+		}
+	}
+}
+
+// isCredentialHeader reports whether the values of the header are credentials, such as Authorization,
+// cookies, and the tokens of registries and CI systems.
+func isCredentialHeader(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	return strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "api-key")
+}
+
+// traceWriter writes the output of an operation to the trace line by line.
+type traceWriter struct {
+	t *tracer
+}
+
+func (w *traceWriter) Write(p []byte) (int, error) {
+	// Progress output ends its updates with carriage returns, which are lines of their own in the trace
+	for line := range strings.FieldsFuncSeq(string(p), func(r rune) bool { return r == '\n' || r == '\r' }) {
+		w.t.printf("  %s", line)
+	}
+	return len(p), nil
+}
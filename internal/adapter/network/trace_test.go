@@ -0,0 +1,72 @@
+package network
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Request-Id", "42")
+	}))
+	defer server.Close()
+	t.Cleanup(func() { SetTrace(nil, false) })
+
+	get := func() {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, strings.Replace(server.URL, "http://", "http://user:password@", 1)+"/skill.tar.gz", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Private-Token", "secret")
+		resp, err := Client().Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	var out bytes.Buffer
+	SetTrace(&out, false)
+	get()
+	Tracef("git clone %s", "https://github.com/example/skills")
+	if TraceOutput() != nil {
+		t.Error("TraceOutput() is not nil without dump")
+	}
+	for _, want := range []string{"[TRACE] > GET http://user:xxxxx@", "[TRACE] < 200 OK for GET", "[TRACE] git clone https://github.com/example/skills"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("trace does not contain %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "X-Request-Id") {
+		t.Errorf("trace contains headers without dump:\n%s", out.String())
+	}
+
+	out.Reset()
+	SetTrace(&out, true)
+	get()
+	if _, err := TraceOutput().Write([]byte("Counting objects: 50%\rCounting objects: 100%\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"> Authorization: [REDACTED]", "> Private-Token: [REDACTED]", "< Set-Cookie: [REDACTED]", "< X-Request-Id: 42", "[TRACE]   Counting objects: 50%\n[TRACE]   Counting objects: 100%\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dump does not contain %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "secret") || strings.Contains(out.String(), "password") {
+		t.Errorf("dump contains credentials:\n%s", out.String())
+	}
+
+	out.Reset()
+	SetTrace(nil, false)
+	get()
+	Tracef("git clone")
+	if out.Len() != 0 {
+		t.Errorf("trace after turning it off = %q", out.String())
+	}
+}
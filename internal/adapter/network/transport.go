@@ -0,0 +1,97 @@
+// Package network provides the HTTP transport that adapters download through, so that the
+// settings of the process, such as the bandwidth limit, the hosts that may be connected to,
+// and the headers sent, apply to every download.
+package network
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// transport is shared by every client returned by Client.
+var transport http.RoundTripper = &sharedTransport{base: dialTransport()}
+
+// dialTransport returns a copy of http.DefaultTransport that connects with DialContext, so that the
+// network policy is checked against the addresses connected to. Proxies are connected to without it:
+// the hosts of the requests are checked instead, and the proxy resolves them.
+func dialTransport() http.RoundTripper {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	base.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if slices.Contains(proxyAddresses(), address) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return DialContext(ctx, network, address)
+	}
+	return base
+}
+
+// proxyAddresses are the addresses of the proxies set in the environment, which http.Transport
+// reads once like this.
+var proxyAddresses = sync.OnceValue(func() []string {
+	config := httpproxy.FromEnvironment()
+	var addresses []string
+	for _, proxy := range []string{config.HTTPProxy, config.HTTPSProxy} {
+		u, err := url.Parse(proxy)
+		if err != nil || u.Host == "" {
+			// http.Transport reads proxies without a scheme as http://
+			if u, err = url.Parse("http://" + proxy); err != nil || u.Host == "" {
+				continue
+			}
+		}
+		port := u.Port()
+		if port == "" {
+			port = map[string]string{"http": "80", "https": "443", "socks5": "1080", "socks5h": "1080"}[u.Scheme]
+		}
+		addresses = append(addresses, net.JoinHostPort(u.Hostname(), port))
+	}
+	return addresses
+})
+
+// Client returns an HTTP client that sends its requests through the shared transport.
+func Client() *http.Client {
+	return &http.Client{Transport: transport}
+}
+
+// sharedTransport applies the settings of the process to the requests of every client.
+type sharedTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with the configured headers unless the network policy refuses its host,
+// throttling the response body when a rate limit is set and tracing it when a trace is set. Redirects
+// are sent as requests of their own, so they are checked too.
+func (t *sharedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := CheckHost(req.Context(), req.URL.Hostname()); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+
+	req = withHeaders(req)
+	trace := traceOutput.Load()
+	if trace != nil {
+		trace.request(req)
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if trace != nil {
+		trace.response(req, resp, err, time.Since(start))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if limiter := rateLimiter.Load(); limiter != nil {
+		resp.Body = &throttledBody{ReadCloser: resp.Body, ctx: req.Context(), limiter: limiter}
+	}
+	return resp, nil
+}
@@ -0,0 +1,64 @@
+// Package notify provides implementations of the Notifier interface.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/command"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// Desktop shows desktop notifications using the platform's built-in tools:
+// osascript on macOS, PowerShell on Windows, and notify-send (libnotify) elsewhere.
+type Desktop struct {
+	// run executes a notification command; replaced in tests.
+	run  func(ctx context.Context, name string, args ...string) error
+	goos string
+}
+
+// NewDesktop creates a new desktop notifier for the current platform.
+func NewDesktop() port.Notifier {
+	return &Desktop{run: command.Run, goos: runtime.GOOS}
+}
+
+// Notify shows a desktop notification.
+func (d *Desktop) Notify(ctx context.Context, title, message string) error {
+	name, args := d.command(title, message)
+	if err := d.run(ctx, name, args...); err != nil {
+		return fmt.Errorf("failed to show desktop notification: %w", err)
+	}
+	return nil
+}
+
+// command returns the command line that shows the notification on the platform.
+func (d *Desktop) command(title, message string) (string, []string) {
+	switch d.goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptQuote(message), appleScriptQuote(title))
+		return "osascript", []string{"-e", script}
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms; `+
+			`$n = New-Object System.Windows.Forms.NotifyIcon; `+
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; `+
+			`$n.ShowBalloonTip(10000, %s, %s, 'Info'); Start-Sleep -Seconds 5; $n.Dispose()`,
+			powerShellQuote(title), powerShellQuote(message))
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	default:
+		return "notify-send", []string{"--app-name=skills-pkg", title, message}
+	}
+}
+
+// appleScriptQuote returns s as an AppleScript string literal.
+func appleScriptQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// powerShellQuote returns s as a single-quoted PowerShell string literal.
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
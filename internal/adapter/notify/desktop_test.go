@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDesktop_Notify(t *testing.T) {
+	tests := []struct {
+		runErr   error
+		name     string
+		goos     string
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name:     "macOS uses osascript",
+			goos:     "darwin",
+			wantName: "osascript",
+			wantArgs: []string{"-e", `display notification "Updated \"a\"" with title "skills-pkg"`},
+		},
+		{
+			name:     "Linux uses notify-send",
+			goos:     "linux",
+			wantName: "notify-send",
+			wantArgs: []string{"--app-name=skills-pkg", "skills-pkg", `Updated "a"`},
+		},
+		{
+			name:     "command failure is reported",
+			goos:     "linux",
+			runErr:   errors.New("notify-send: not found"),
+			wantName: "notify-send",
+			wantArgs: []string{"--app-name=skills-pkg", "skills-pkg", `Updated "a"`},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotArgs []string
+			d := &Desktop{
+				goos: tt.goos,
+				run: func(ctx context.Context, name string, args ...string) error {
+					gotName, gotArgs = name, args
+					return tt.runErr
+				},
+			}
+
+			err := d.Notify(context.Background(), "skills-pkg", `Updated "a"`)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotName != tt.wantName || !slices.Equal(gotArgs, tt.wantArgs) {
+				t.Errorf("command = %s %q, want %s %q", gotName, gotArgs, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestDesktop_WindowsQuoting(t *testing.T) {
+	d := &Desktop{goos: "windows"}
+
+	name, args := d.command("skills-pkg", "it's done")
+	if name != "powershell" {
+		t.Fatalf("command name = %s, want powershell", name)
+	}
+	if script := args[len(args)-1]; !strings.Contains(script, "'it''s done'") {
+		t.Errorf("script should escape single quotes, got %s", script)
+	}
+}
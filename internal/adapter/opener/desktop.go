@@ -0,0 +1,46 @@
+// Package opener provides implementations of the Opener interface.
+package opener
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/command"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// Desktop opens directories and URLs with the platform's default application:
+// open on macOS, the URL protocol handler on Windows, and xdg-open elsewhere.
+type Desktop struct {
+	// run executes an open command; replaced in tests.
+	run  func(ctx context.Context, name string, args ...string) error
+	goos string
+}
+
+// NewDesktop creates a new opener for the current platform.
+func NewDesktop() port.Opener {
+	return &Desktop{run: command.Run, goos: runtime.GOOS}
+}
+
+// Open opens the local path or URL.
+func (d *Desktop) Open(ctx context.Context, location string) error {
+	name, args := d.command(location)
+	if err := d.run(ctx, name, args...); err != nil {
+		return fmt.Errorf("failed to open %s: %w", location, err)
+	}
+	return nil
+}
+
+// command returns the command line that opens the location on the platform.
+func (d *Desktop) command(location string) (string, []string) {
+	switch d.goos {
+	case "darwin":
+		return "open", []string{location}
+	case "windows":
+		// Unlike "cmd /c start", this needs no quoting of the location
+		return "rundll32", []string{"url.dll,FileProtocolHandler", location}
+	default:
+		return "xdg-open", []string{location}
+	}
+}
@@ -0,0 +1,68 @@
+package opener
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDesktop_Open(t *testing.T) {
+	tests := []struct {
+		runErr   error
+		name     string
+		goos     string
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name:     "macOS uses open",
+			goos:     "darwin",
+			wantName: "open",
+			wantArgs: []string{"/skills/my skill"},
+		},
+		{
+			name:     "Windows uses the URL protocol handler",
+			goos:     "windows",
+			wantName: "rundll32",
+			wantArgs: []string{"url.dll,FileProtocolHandler", "/skills/my skill"},
+		},
+		{
+			name:     "Linux uses xdg-open",
+			goos:     "linux",
+			wantName: "xdg-open",
+			wantArgs: []string{"/skills/my skill"},
+		},
+		{
+			name:     "command failure is reported",
+			goos:     "linux",
+			runErr:   errors.New("xdg-open: not found"),
+			wantName: "xdg-open",
+			wantArgs: []string{"/skills/my skill"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotArgs []string
+			d := &Desktop{
+				goos: tt.goos,
+				run: func(ctx context.Context, name string, args ...string) error {
+					gotName, gotArgs = name, args
+					return tt.runErr
+				},
+			}
+
+			err := d.Open(context.Background(), "/skills/my skill")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotName != tt.wantName || !slices.Equal(gotArgs, tt.wantArgs) {
+				t.Errorf("command = %s %q, want %s %q", gotName, gotArgs, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
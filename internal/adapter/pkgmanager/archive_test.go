@@ -0,0 +1,213 @@
+package pkgmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// readTestFile returns the content of the file at path.
+func readTestFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestArchive_Download(t *testing.T) {
+	files := map[string][]byte{
+		"/releases/v1.0.0/skill.tar.gz": writeTestTarball(t, map[string]string{"skill-1.0.0/SKILL.md": "# Tarball\n", "skill-1.0.0/docs/usage.md": "usage\n"}),
+		"/skill.zip":                    readTestFile(t, writeTestZip(t, map[string]string{"SKILL.md": "# Zip\n", "scripts/run.sh": "run\n"})),
+		"/not-an-archive":               []byte("plain text"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	a := NewArchive()
+	read := func(dir, name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("failed to read %s: %v", name, err)
+		}
+		return string(data)
+	}
+
+	// The version replaces the placeholder, and the top-level directory of every entry is stripped
+	tarball := &port.Source{Type: "archive", URL: server.URL + "/releases/{version}/skill.tar.gz"}
+	result, err := a.Download(ctx, tarball, "v1.0.0")
+	if err != nil {
+		t.Fatalf("Download() of a tarball error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(result.Path) }()
+	if result.Version != "v1.0.0" {
+		t.Errorf("Download() version = %s, want v1.0.0", result.Version)
+	}
+	if got := read(result.Path, "SKILL.md"); got != "# Tarball\n" {
+		t.Errorf("SKILL.md = %q", got)
+	}
+	if got := read(result.Path, "docs/usage.md"); got != "usage\n" {
+		t.Errorf("docs/usage.md = %q", got)
+	}
+	if _, err := a.Download(ctx, tarball, ""); err == nil {
+		t.Error("Download() of a URL with a placeholder without a version succeeded")
+	}
+	if _, err := a.Download(ctx, tarball, "v2.0.0"); !errors.Is(err, domain.ErrSourceNotFound) {
+		t.Errorf("Download() of a missing version error = %v, want ErrSourceNotFound", err)
+	}
+
+	// Without a placeholder the digest of the file is the version; files at the top level are kept
+	digest := hexSHA256(files["/skill.zip"])
+	zipSource := &port.Source{Type: "archive", URL: server.URL + "/skill.zip"}
+	result, err = a.Download(ctx, zipSource, "")
+	if err != nil {
+		t.Fatalf("Download() of a zip file error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(result.Path) }()
+	if result.Version != "sha256:"+digest {
+		t.Errorf("Download() version = %s, want sha256:%s", result.Version, digest)
+	}
+	if got := read(result.Path, "scripts/run.sh"); got != "run\n" {
+		t.Errorf("scripts/run.sh = %q", got)
+	}
+
+	latest, err := a.GetLatestVersion(ctx, zipSource)
+	if err != nil || latest != "sha256:"+digest {
+		t.Errorf("GetLatestVersion() = %s, %v, want sha256:%s", latest, err, digest)
+	}
+
+	if _, err := a.Download(ctx, &port.Source{Type: "archive", URL: server.URL + "/not-an-archive"}, ""); err == nil || !strings.Contains(err.Error(), "unsupported archive format") {
+		t.Errorf("Download() of a file that is not an archive error = %v, want unsupported archive format", err)
+	}
+}
+
+func TestArchive_Download_SHA256(t *testing.T) {
+	archive := writeTestTarball(t, map[string]string{"skill/SKILL.md": "# Skill\n"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	a := NewArchive()
+	digest := hexSHA256(archive)
+	other := strings.Repeat("0", 64)
+
+	pinned := &port.Source{Type: "archive", URL: server.URL + "/skill.tar.gz", Options: map[string]string{port.SourceOptionSHA256: digest}}
+	result, err := a.Download(ctx, pinned, "")
+	if err != nil {
+		t.Fatalf("Download() with the right sha256 error = %v", err)
+	}
+	_ = os.RemoveAll(result.Path)
+
+	for name, check := range map[string]func() error{
+		"sha256 option": func() error {
+			_, err := a.Download(ctx, &port.Source{Type: "archive", URL: server.URL + "/skill.tar.gz", Options: map[string]string{port.SourceOptionSHA256: other}}, "")
+			return err
+		},
+		"digest version": func() error {
+			_, err := a.Download(ctx, &port.Source{Type: "archive", URL: server.URL + "/skill.tar.gz"}, "sha256:"+other)
+			return err
+		},
+	} {
+		if err := check(); err == nil || !strings.Contains(err.Error(), "integrity check failed") {
+			t.Errorf("Download() with a different %s error = %v, want an integrity check failure", name, err)
+		}
+	}
+}
+
+// TestArchive_Download_Limits tests that the extraction limits apply without hardened_extraction
+func TestArchive_Download_Limits(t *testing.T) {
+	tarball := writeTestTarball(t, map[string]string{"skill/SKILL.md": "# Skill\n", "skill/a.txt": strings.Repeat("a", 100), "skill/b.txt": strings.Repeat("b", 100)})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(tarball)
+	}))
+	defer server.Close()
+
+	defaults := defaultExtractionLimits
+	t.Cleanup(func() { defaultExtractionLimits = defaults })
+
+	tests := []struct {
+		limits  *extractionLimits
+		name    string
+		wantErr string
+	}{
+		{name: "too many entries", limits: &extractionLimits{maxEntries: 2, maxBytes: 1000, maxFileBytes: 1000}, wantErr: "more than 2 entries"},
+		{name: "too large", limits: &extractionLimits{maxEntries: 10, maxBytes: 150, maxFileBytes: 1000}, wantErr: "more than 150 bytes"},
+		{name: "file too large", limits: &extractionLimits{maxEntries: 10, maxBytes: 1000, maxFileBytes: 50}, wantErr: "file larger than 50 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultExtractionLimits = tt.limits
+			_, err := NewArchive().Download(context.Background(), &port.Source{Type: "archive", URL: server.URL + "/skill.tar.gz"}, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Download() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTopLevelDir(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{names: []string{"skill/", "skill/SKILL.md", "skill/docs/a.md"}, want: "skill"},
+		{names: []string{"./", "./skill/SKILL.md"}, want: "skill"},
+		{names: []string{"SKILL.md", "docs/a.md"}, want: ""},
+		{names: []string{"a/SKILL.md", "b/SKILL.md"}, want: ""},
+		{names: nil, want: ""},
+	}
+	for _, tt := range tests {
+		if got := topLevelDir(tt.names); got != tt.want {
+			t.Errorf("topLevelDir(%v) = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
+
+// TestExtractArchiveSandboxed tests that the sandboxed child strips the common top-level directory of either kind of archive
+func TestExtractArchiveSandboxed(t *testing.T) {
+	dir := t.TempDir()
+	tarball := filepath.Join(dir, "skill.tar.gz")
+	if err := os.WriteFile(tarball, writeTestTarball(t, map[string]string{"skill/SKILL.md": "# Tarball\n"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	zipPath := writeTestZip(t, map[string]string{"SKILL.md": "# Zip\n"})
+
+	for archive, want := range map[string]string{tarball: "# Tarball\n", zipPath: "# Zip\n"} {
+		targetDir := t.TempDir()
+		if err := extractArchiveSandboxed(context.Background(), archive, targetDir, commonPrefix); err != nil {
+			skipWithoutSandbox(t, err)
+			t.Fatalf("extractArchiveSandboxed(%s) error = %v", filepath.Base(archive), err)
+		}
+		data, err := os.ReadFile(filepath.Join(targetDir, "SKILL.md"))
+		if err != nil || string(data) != want {
+			t.Errorf("SKILL.md extracted from %s = %q, %v, want %q", filepath.Base(archive), data, err, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package pkgmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"golang.org/x/net/proxy"
+)
+
+// policyProxyScheme is the proxy scheme of the dialer that go-git connects to SSH servers with,
+// as go-git dials them itself and only lets a proxy replace its dialer.
+const policyProxyScheme = "skills-pkg-dial"
+
+func init() {
+	// SSH connections still go through the proxy of ALL_PROXY, like those go-git dials itself.
+	// The proxy resolves the hosts then, so they are checked by CheckHost before the proxy is asked
+	// to connect to them, like with HTTP proxies.
+	proxy.RegisterDialerType(policyProxyScheme, func(*url.URL, proxy.Dialer) (proxy.Dialer, error) {
+		if dialer := proxy.FromEnvironment(); dialer != proxy.Direct {
+			return proxiedDialer{dialer: dialer}, nil
+		}
+		return policyDialer{}, nil
+	})
+}
+
+// policyDialer connects with network.DialContext, so that the network policy is checked against
+// the addresses connected to.
+type policyDialer struct{}
+
+func (d policyDialer) Dial(proto, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), proto, addr)
+}
+
+func (policyDialer) DialContext(ctx context.Context, proto, addr string) (net.Conn, error) {
+	return network.DialContext(ctx, proto, addr)
+}
+
+// proxiedDialer connects through a proxy after checking the host it asks the proxy to connect to
+// against the network policy, as the addresses the proxy connects to cannot be checked.
+type proxiedDialer struct {
+	dialer proxy.Dialer
+}
+
+func (d proxiedDialer) Dial(proto, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), proto, addr)
+}
+
+func (d proxiedDialer) DialContext(ctx context.Context, proto, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := network.CheckHost(ctx, host); err != nil {
+		return nil, err
+	}
+	if dialer, ok := d.dialer.(proxy.ContextDialer); ok {
+		return dialer.DialContext(ctx, proto, addr)
+	}
+	return d.dialer.Dial(proto, addr)
+}
+
+// remoteOptions checks the host of repoURL against the network policy before credentials are looked
+// up, and returns the proxy options that make go-git connect to it with policyDialer when it is an
+// SSH server; clones over HTTP connect through the shared transport instead. The git protocol cannot
+// be checked when connecting, so it is refused when the network policy has address ranges. A URL
+// that cannot be parsed is refused too, as its host cannot be checked.
+func remoteOptions(ctx context.Context, repoURL string) (transport.ProxyOptions, error) {
+	endpoint, err := transport.NewEndpoint(repoURL)
+	if err != nil {
+		return transport.ProxyOptions{}, fmt.Errorf("invalid repository URL %s: %w", redactURL(repoURL), err)
+	}
+	if err := network.CheckHost(ctx, endpoint.Host); err != nil {
+		return transport.ProxyOptions{}, err
+	}
+
+	switch endpoint.Protocol {
+	case "ssh":
+		return transport.ProxyOptions{URL: policyProxyScheme + "://"}, nil
+	case "git":
+		if network.ChecksAddresses() {
+			return transport.ProxyOptions{}, &domain.ErrorHostNotAllowed{Host: endpoint.Host, Reason: "connections over the git protocol cannot be checked against the address ranges; use an https or ssh URL"}
+		}
+	}
+	return transport.ProxyOptions{}, nil
+}
@@ -0,0 +1,104 @@
+package pkgmanager
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"golang.org/x/net/proxy"
+)
+
+func TestRemoteOptions(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	policy, err := (&domain.NetworkSettings{DeniedHosts: []string{"127.0.0.0/8"}}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.SetPolicy(policy)
+	t.Cleanup(func() { network.SetPolicy(nil) })
+	ctx := context.Background()
+
+	// go-git connects to SSH servers with the dialer of the proxy options, which checks the address
+	options, err := remoteOptions(ctx, "ssh://git@git.example.com/skills.git")
+	if err != nil {
+		t.Fatalf("remoteOptions() error = %v", err)
+	}
+	proxyURL, err := url.Parse(options.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+	if err != nil {
+		t.Fatalf("proxy dialer of %s: %v", options.URL, err)
+	}
+	conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", listener.Addr().String())
+	if err == nil {
+		_ = conn.Close()
+	}
+	if _, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); !ok {
+		t.Errorf("DialContext() of a denied address error = %v, want ErrorHostNotAllowed", err)
+	}
+
+	// HTTP clones connect through the shared transport
+	if options, err := remoteOptions(ctx, "https://git.example.com/skills.git"); err != nil || options.URL != "" {
+		t.Errorf("remoteOptions() of an HTTPS URL = %+v, %v, want no proxy", options, err)
+	}
+
+	// The git protocol cannot be checked when connecting
+	_, err = remoteOptions(ctx, "git://git.example.com/skills.git")
+	if _, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); !ok {
+		t.Errorf("remoteOptions() of a git protocol URL error = %v, want ErrorHostNotAllowed", err)
+	}
+
+	// A URL whose host cannot be parsed cannot be checked either
+	if _, err := remoteOptions(ctx, "ssh://[::1/skills.git"); err == nil {
+		t.Error("remoteOptions() of an invalid URL error = nil, want an error")
+	}
+}
+
+func TestProxiedDialer(t *testing.T) {
+	policy, err := (&domain.NetworkSettings{DeniedHosts: []string{"denied.example.com", "127.0.0.0/8"}}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.SetPolicy(policy)
+	t.Cleanup(func() { network.SetPolicy(nil) })
+
+	var dialed []string
+	dialer := proxiedDialer{dialer: dialerFunc(func(_, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, errors.New("proxy unavailable")
+	})}
+
+	// Hosts the policy refuses are not sent to the proxy
+	for _, addr := range []string{"denied.example.com:22", "127.0.0.1:22"} {
+		_, err := dialer.DialContext(context.Background(), "tcp", addr)
+		if _, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); !ok {
+			t.Errorf("DialContext(%s) error = %v, want ErrorHostNotAllowed", addr, err)
+		}
+	}
+	if len(dialed) != 0 {
+		t.Errorf("the proxy was asked to connect to %v, want no connections", dialed)
+	}
+
+	// Other hosts are
+	if _, err := dialer.DialContext(context.Background(), "tcp", "192.0.2.1:22"); err == nil || len(dialed) != 1 {
+		t.Errorf("DialContext() of an allowed host = %v, dialed %v, want the proxy to be asked", err, dialed)
+	}
+}
+
+// dialerFunc is a proxy.Dialer that connects with a function.
+type dialerFunc func(proto, addr string) (net.Conn, error)
+
+func (f dialerFunc) Dial(proto, addr string) (net.Conn, error) {
+	return f(proto, addr)
+}
@@ -3,13 +3,19 @@ package pkgmanager
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
 	"github.com/mazrean/skills-pkg/internal/domain"
 	"github.com/mazrean/skills-pkg/internal/port"
 	"golang.org/x/mod/semver"
@@ -20,6 +26,13 @@ const (
 	defaultDirPerm = 0755
 )
 
+func init() {
+	// Clones over HTTP go through the shared transport, like the downloads of the other adapters
+	httpClient := githttp.NewClient(network.Client())
+	client.InstallProtocol("https", httpClient)
+	client.InstallProtocol("http", httpClient)
+}
+
 // Git implements the PackageManager interface for Git repositories.
 // It handles cloning repositories, checking out specific versions (tags or commits),
 // and retrieving the latest version.
@@ -119,6 +132,42 @@ func (a *Git) GetLatestVersion(ctx context.Context, source *port.Source) (string
 	return head.Hash().String(), nil
 }
 
+// ListVersions returns the tags of the Git repository.
+func (a *Git) ListVersions(ctx context.Context, source *port.Source) ([]string, error) {
+	if err := source.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid source configuration: %w", err)
+	}
+
+	if source.Type != "git" {
+		return nil, fmt.Errorf("source type must be 'git', got '%s'", source.Type)
+	}
+
+	tempDir, err := a.createTempDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	repo, err := a.cloneRepository(ctx, source.URL, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	var versions []string
+	if err := tags.ForEach(func(ref *plumbing.Reference) error {
+		versions = append(versions, ref.Name().Short())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+
+	return versions, nil
+}
+
 // createTempDir creates a temporary directory for cloning Git repositories.
 // It uses the SKILLSPKG_TEMP_DIR environment variable if set, otherwise uses os.TempDir().
 func (a *Git) createTempDir() (string, error) {
@@ -143,23 +192,34 @@ func (a *Git) createTempDir() (string, error) {
 // cloneRepository clones a Git repository from the given URL to the target directory.
 // Requirements: 3.1, 3.5, 12.2, 12.3
 func (a *Git) cloneRepository(ctx context.Context, url, targetDir string) (*git.Repository, error) {
+	proxyOptions, err := remoteOptions(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone repository %s: %w", url, err)
+	}
+
 	auth, err := buildAuthMethod(url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", domain.ErrNetworkFailure, err)
 	}
 
+	network.Tracef("git clone %s %s", redactURL(url), targetDir)
 	repo, err := git.PlainCloneContext(ctx, targetDir, false, &git.CloneOptions{
-		URL:      url,
-		Auth:     auth,
-		Progress: nil,
+		URL:          url,
+		Auth:         auth,
+		Progress:     network.TraceOutput(),
+		ProxyOptions: proxyOptions,
 	})
 	if err != nil {
+		// Hosts refused by the network policy are reported as such, not as network failures
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return nil, fmt.Errorf("failed to clone repository %s: %w", url, e)
+		}
 		// Classify the error for better user feedback
 		if strings.Contains(err.Error(), "authentication required") {
-			return nil, fmt.Errorf("%w: failed to clone repository %s: authentication required. Set GIT_TOKEN, GITHUB_TOKEN, or GIT_USERNAME/GIT_PASSWORD environment variables for HTTPS, or ensure SSH credentials are configured", domain.ErrNetworkFailure, url)
+			return nil, fmt.Errorf("%w: %w: failed to clone repository %s. Set GIT_TOKEN, GITHUB_TOKEN, or GIT_USERNAME/GIT_PASSWORD environment variables for HTTPS, or ensure SSH credentials are configured", domain.ErrNetworkFailure, domain.ErrAuthenticationRequired, url)
 		}
 		if strings.Contains(err.Error(), "repository not found") {
-			return nil, fmt.Errorf("%w: failed to clone repository %s: repository not found. Please verify the URL is correct", domain.ErrNetworkFailure, url)
+			return nil, fmt.Errorf("%w: %w: failed to clone repository %s. Please verify the URL is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, url)
 		}
 		if strings.Contains(err.Error(), "network") || strings.Contains(err.Error(), "connection") {
 			return nil, fmt.Errorf("%w: failed to clone repository %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, url)
@@ -192,6 +252,7 @@ func (a *Git) checkoutVersion(repo *git.Repository, version string) (string, err
 	tagRef := plumbing.NewTagReferenceName(version)
 	if _, err := repo.Reference(tagRef, true); err == nil {
 		// Tag exists, checkout the tag
+		network.Tracef("git checkout tags/%s", version)
 		if err := worktree.Checkout(&git.CheckoutOptions{
 			Branch: tagRef,
 		}); err != nil {
@@ -204,6 +265,7 @@ func (a *Git) checkoutVersion(repo *git.Repository, version string) (string, err
 	hash := plumbing.NewHash(version)
 	if _, err := repo.CommitObject(hash); err == nil {
 		// Commit exists, checkout the commit
+		network.Tracef("git checkout %s", version)
 		if err := worktree.Checkout(&git.CheckoutOptions{
 			Hash: hash,
 		}); err != nil {
@@ -216,6 +278,7 @@ func (a *Git) checkoutVersion(repo *git.Repository, version string) (string, err
 	branchRef := plumbing.NewBranchReferenceName(version)
 	if _, err := repo.Reference(branchRef, true); err == nil {
 		// Branch exists, checkout the branch
+		network.Tracef("git checkout %s", version)
 		if err := worktree.Checkout(&git.CheckoutOptions{
 			Branch: branchRef,
 		}); err != nil {
@@ -269,3 +332,165 @@ func (a *Git) getLatestTag(repo *git.Repository) (string, error) {
 	}
 	return latestPre, nil
 }
+
+// PublishTarget returns "git" to identify this adapter as a publisher of git tags.
+func (a *Git) PublishTarget() string {
+	return "git"
+}
+
+// Publish tags the commit checked out in the repository of the skill with the version and pushes the tag
+// to the remote named destination, "origin" by default. The skill must be committed, as the tag only
+// includes committed files. An existing tag is only pushed again when it points to the same commit,
+// and a tag created for the push is deleted again when the push fails.
+func (a *Git) Publish(ctx context.Context, artifact *port.PublishArtifact, destination string) (*port.PublishResult, error) {
+	if destination == "" {
+		destination = "origin"
+	}
+
+	repo, subDir, err := openSkillRepository(artifact.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCommitted(repo, subDir); err != nil {
+		return nil, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	remote, err := repo.Remote(destination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find remote '%s' of the repository of %s: %w", destination, artifact.Dir, err)
+	}
+	url := remote.Config().URLs[0]
+	tag := artifact.Version
+
+	proxyOptions, err := remoteOptions(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to push tag %s to %s: %w", tag, redactURL(url), err)
+	}
+	auth, err := buildAuthMethod(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrNetworkFailure, err)
+	}
+
+	created := false
+	if existing, err := repo.Tag(tag); err == nil {
+		commit, err := tagCommit(repo, existing)
+		if err != nil {
+			return nil, err
+		}
+		if commit != head.Hash() {
+			return nil, fmt.Errorf("tag %s already exists and points to commit %s, not to HEAD %s. Publish another version", tag, commit, head.Hash())
+		}
+	} else {
+		network.Tracef("git tag %s", tag)
+		if _, err := repo.CreateTag(tag, head.Hash(), nil); err != nil {
+			return nil, fmt.Errorf("failed to create tag %s: %w", tag, err)
+		}
+		created = true
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag))
+	network.Tracef("git push %s %s", redactURL(url), refSpec)
+	err = repo.PushContext(ctx, &git.PushOptions{
+		RemoteName:   destination,
+		RefSpecs:     []config.RefSpec{refSpec},
+		Auth:         auth,
+		Progress:     network.TraceOutput(),
+		ProxyOptions: proxyOptions,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		// A tag left behind would point the next publish of the version at this commit
+		if created {
+			if err := repo.DeleteTag(tag); err != nil {
+				network.Tracef("failed to delete tag %s: %v", tag, err)
+			}
+		}
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return nil, fmt.Errorf("failed to push tag %s to %s: %w", tag, redactURL(url), e)
+		}
+		if strings.Contains(err.Error(), "authentication required") {
+			return nil, fmt.Errorf("%w: %w: failed to push tag %s to %s. Set GIT_TOKEN, GITHUB_TOKEN, or GIT_USERNAME/GIT_PASSWORD environment variables for HTTPS, or ensure SSH credentials are configured", domain.ErrNetworkFailure, domain.ErrAuthenticationRequired, tag, redactURL(url))
+		}
+		return nil, fmt.Errorf("%w: failed to push tag %s to %s: %v", domain.ErrNetworkFailure, tag, redactURL(url), err)
+	}
+
+	return &port.PublishResult{
+		Location: fmt.Sprintf("tag %s of %s", tag, redactURL(url)),
+		Source:   &port.Source{Type: "git", URL: url},
+		Version:  tag,
+		SubDir:   subDir,
+	}, nil
+}
+
+// openSkillRepository opens the git repository that contains the skill directory dir, and returns
+// the slash-separated path of dir within the repository, "." when dir is its root.
+func openSkillRepository(dir string) (*git.Repository, string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve skill directory %s: %w", dir, err)
+	}
+	repo, err := git.PlainOpenWithOptions(absDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, "", fmt.Errorf("skill directory %s is not in a git repository: %w", dir, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	// The root is resolved like dir, so that links in the path, such as /tmp on macOS, do not matter
+	root, err := filepath.EvalSymlinks(worktree.Filesystem.Root())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve repository root: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	rel, err := filepath.Rel(root, absDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to locate %s in repository %s: %w", dir, root, err)
+	}
+	return repo, filepath.ToSlash(rel), nil
+}
+
+// checkCommitted returns an error when files in the directory subDir of the repository are modified or untracked.
+func checkCommitted(repo *git.Repository, subDir string) error {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get status of the repository: %w", err)
+	}
+
+	var changed []string
+	for file, s := range status {
+		if subDir != "." && !strings.HasPrefix(file, subDir+"/") {
+			continue
+		}
+		if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+			changed = append(changed, file)
+		}
+	}
+	if len(changed) > 0 {
+		slices.Sort(changed)
+		return fmt.Errorf("the skill has uncommitted changes in %s. Commit them first, as the tag only includes committed files", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// tagCommit returns the commit that the tag points to, through the tag object of an annotated tag.
+func tagCommit(repo *git.Repository, ref *plumbing.Reference) (plumbing.Hash, error) {
+	if tag, err := repo.TagObject(ref.Hash()); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to resolve tag %s: %w", ref.Name().Short(), err)
+		}
+		return commit.Hash, nil
+	}
+	return ref.Hash(), nil
+}
@@ -2,10 +2,19 @@ package pkgmanager
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
 	"github.com/mazrean/skills-pkg/internal/port"
 )
 
@@ -253,3 +262,109 @@ func TestGit_Download_CleansUpOnError(t *testing.T) {
 		})
 	}
 }
+
+func TestGit_Download_NetworkPolicy(t *testing.T) {
+	policy, err := (&domain.NetworkSettings{AllowedHosts: []string{"github.com"}, DenyByDefault: true}).Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.SetPolicy(policy)
+	t.Cleanup(func() { network.SetPolicy(nil) })
+	t.Setenv("SKILLSPKG_TEMP_DIR", t.TempDir())
+
+	for _, url := range []string{"https://git.example.com/skills.git", "ssh://git@git.example.com/skills.git", "git@git.example.com:skills.git"} {
+		_, err := NewGit().Download(context.Background(), &port.Source{Type: "git", URL: url}, "latest")
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); !ok || e.Host != "git.example.com" {
+			t.Errorf("Download(%s) error = %v, want ErrorHostNotAllowed for git.example.com", url, err)
+		}
+	}
+}
+
+func TestGit_Publish(t *testing.T) {
+	remote := t.TempDir()
+	if _, err := git.PlainInit(remote, true); err != nil {
+		t.Fatal(err)
+	}
+	work := t.TempDir()
+	repo, err := git.PlainInit(work, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remote}}); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	skillDir := filepath.Join(work, "skills", "code-review")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add("skills/code-review/SKILL.md"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Commit(content, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("# Code review\n")
+
+	a := NewGit()
+	artifact := &port.PublishArtifact{Name: "code-review", Version: "v1.0.0", Dir: skillDir}
+
+	if err := os.WriteFile(filepath.Join(skillDir, "draft.md"), []byte("draft\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Publish(context.Background(), artifact, ""); err == nil || !strings.Contains(err.Error(), "uncommitted changes in skills/code-review/draft.md") {
+		t.Errorf("Publish() of an uncommitted skill error = %v", err)
+	}
+	if err := os.Remove(filepath.Join(skillDir, "draft.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := a.Publish(context.Background(), artifact, "")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if result.Source.Type != "git" || result.Source.URL != remote || result.Version != "v1.0.0" || result.SubDir != "skills/code-review" {
+		t.Errorf("Publish() = %+v, source %+v", result, result.Source)
+	}
+	pushed, err := git.PlainOpen(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag, err := pushed.Tag("v1.0.0"); err != nil || tag.Hash() != head.Hash() {
+		t.Errorf("remote tag v1.0.0 = %v, %v, want HEAD %s", tag, err, head.Hash())
+	}
+
+	// Publishing the same commit again is a no-op, but a tag is never moved to another commit
+	if _, err := a.Publish(context.Background(), artifact, ""); err != nil {
+		t.Errorf("Publish() of the same commit again error = %v", err)
+	}
+	commit("# Code review v2\n")
+	if _, err := a.Publish(context.Background(), artifact, ""); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Publish() of an existing tag error = %v", err)
+	}
+
+	// A failed push leaves no tag behind, so the version can be published once the remote is fixed
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "missing", URLs: []string{filepath.Join(t.TempDir(), "missing")}}); err != nil {
+		t.Fatal(err)
+	}
+	artifact.Version = "v2.0.0"
+	if _, err := a.Publish(context.Background(), artifact, "missing"); err == nil {
+		t.Error("Publish() to a missing remote succeeded")
+	}
+	if _, err := repo.Tag("v2.0.0"); !errors.Is(err, git.ErrTagNotFound) {
+		t.Errorf("local tag v2.0.0 after a failed push: %v, want ErrTagNotFound", err)
+	}
+}
@@ -0,0 +1,258 @@
+package pkgmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// defaultGitHubAPI is the GitHub API that releases are created with, unless GITHUB_API_URL is set.
+const defaultGitHubAPI = "https://api.github.com"
+
+// GitHubRelease implements the Publisher interface for GitHub releases. The archive and the manifest
+// of a skill are uploaded as assets of the release of its version, which the archive source installs.
+type GitHubRelease struct {
+	httpClient *http.Client
+	apiBase    string // GitHub API, such as https://api.github.com
+}
+
+// NewGitHubRelease creates a new GitHub release publisher instance.
+// It uses the API in GITHUB_API_URL, which GitHub Actions sets for GitHub Enterprise Server, if set.
+func NewGitHubRelease() *GitHubRelease {
+	apiBase := os.Getenv("GITHUB_API_URL")
+	if apiBase == "" {
+		apiBase = defaultGitHubAPI
+	}
+	return &GitHubRelease{
+		httpClient: network.Client(),
+		apiBase:    strings.TrimSuffix(apiBase, "/"),
+	}
+}
+
+// PublishTarget returns "github" to identify this publisher as a publisher of GitHub releases.
+func (a *GitHubRelease) PublishTarget() string {
+	return "github"
+}
+
+// githubRelease is a release returned by the GitHub API.
+type githubRelease struct {
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+	Assets    []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+// githubAsset is a release asset returned by the GitHub API.
+type githubAsset struct {
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// Publish uploads the archive and the manifest to the release of the version in the repository
+// destination, "owner/repo", creating the release and its tag when they do not exist.
+// An empty destination is the GitHub repository of the "origin" remote of the skill's repository.
+// It authenticates with GITHUB_TOKEN or GH_TOKEN, which must be allowed to write to the repository.
+func (a *GitHubRelease) Publish(ctx context.Context, artifact *port.PublishArtifact, destination string) (*port.PublishResult, error) {
+	if destination == "" {
+		repository, err := originRepository(artifact.Dir)
+		if err != nil {
+			return nil, err
+		}
+		destination = repository
+	}
+	owner, repo, ok := strings.Cut(destination, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("invalid GitHub repository %q: expected owner/repo", destination)
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("%w: creating a release in %s requires a token. Set GITHUB_TOKEN or GH_TOKEN to a token that can write to the repository", domain.ErrAuthenticationRequired, destination)
+	}
+
+	release, err := a.release(ctx, destination, artifact, token)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveName := filepath.Base(artifact.Archive)
+	for _, asset := range release.Assets {
+		if asset.Name == archiveName || asset.Name == filepath.Base(artifact.Manifest) {
+			return nil, fmt.Errorf("release %s of %s already has asset %s. Publish another version", artifact.Version, destination, asset.Name)
+		}
+	}
+	uploaded, err := a.upload(ctx, release, artifact.Archive, "application/gzip", token)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.upload(ctx, release, artifact.Manifest, "application/json", token); err != nil {
+		return nil, err
+	}
+
+	// The download URL of the archive, with the version replaced, installs any version published the same way
+	suffix := "/" + url.PathEscape(artifact.Version) + "/" + archiveName
+	base, found := strings.CutSuffix(uploaded.BrowserDownloadURL, suffix)
+	if !found {
+		return nil, fmt.Errorf("unexpected download URL %s of asset %s", uploaded.BrowserDownloadURL, archiveName)
+	}
+	downloadURL := base + "/" + archiveVersionPlaceholder + "/" + strings.ReplaceAll(archiveName, artifact.Version, archiveVersionPlaceholder)
+
+	return &port.PublishResult{
+		Location: release.HTMLURL,
+		Source:   &port.Source{Type: "archive", URL: downloadURL},
+		Version:  artifact.Version,
+		SubDir:   ".",
+	}, nil
+}
+
+// release returns the release of the artifact's version in the repository, creating it when there is none.
+// A new tag points to the commit checked out in the repository of the skill, or to the head of the
+// default branch when the skill is not in a git repository.
+func (a *GitHubRelease) release(ctx context.Context, repository string, artifact *port.PublishArtifact, token string) (*githubRelease, error) {
+	var release githubRelease
+	status, err := a.request(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/releases/tags/%s", a.apiBase, repository, url.PathEscape(artifact.Version)), "", nil, token, &release)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusOK {
+		return &release, nil
+	}
+	if status != http.StatusNotFound {
+		return nil, a.statusError(repository, "release "+artifact.Version, status)
+	}
+
+	fields := map[string]string{
+		"tag_name": artifact.Version,
+		"name":     fmt.Sprintf("%s %s", artifact.Name, artifact.Version),
+		"body":     fmt.Sprintf("%s\n\nContent hash: `%s`", artifact.Description, artifact.Hash),
+	}
+	// The tag is created at the commit published, not at the head of the default branch
+	if commit := headCommit(artifact.Dir); commit != "" {
+		fields["target_commitish"] = commit
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal release: %w", err)
+	}
+	status, err = a.request(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/releases", a.apiBase, repository), "application/json", body, token, &release)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusCreated {
+		return nil, a.statusError(repository, "release "+artifact.Version, status)
+	}
+	return &release, nil
+}
+
+// upload uploads the file at path as an asset of the release.
+func (a *GitHubRelease) upload(ctx context.Context, release *githubRelease, path, contentType, token string) (*githubAsset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// The upload URL is a URI template, such as https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}
+	uploadURL, _, _ := strings.Cut(release.UploadURL, "{")
+	name := filepath.Base(path)
+	var asset githubAsset
+	status, err := a.request(ctx, http.MethodPost, uploadURL+"?name="+url.QueryEscape(name), contentType, data, token, &asset)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusCreated {
+		return nil, fmt.Errorf("%w: failed to upload %s to %s: HTTP status %d", domain.ErrNetworkFailure, name, release.HTMLURL, status)
+	}
+	return &asset, nil
+}
+
+// request sends a request to the GitHub API and decodes a successful response into result.
+func (a *GitHubRelease) request(ctx context.Context, method, rawURL, contentType string, body []byte, token string, result any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return 0, fmt.Errorf("failed to reach GitHub: %w", e)
+		}
+		return 0, fmt.Errorf("%w: failed to reach GitHub: network error. Please check your internet connection and try again", domain.ErrNetworkFailure)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(result); err != nil {
+			return 0, fmt.Errorf("failed to parse the response of %s: %w", rawURL, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// statusError returns the error of an unexpected HTTP status of a request for what in the repository.
+func (a *GitHubRelease) statusError(repository, what string, status int) error {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return fmt.Errorf("%w: %w: GitHub refused access to %s. Check that GITHUB_TOKEN or GH_TOKEN can write to the repository", domain.ErrNetworkFailure, domain.ErrAuthenticationRequired, repository)
+	}
+	return fmt.Errorf("%w: failed to create %s of %s: HTTP status %d", domain.ErrNetworkFailure, what, repository, status)
+}
+
+// headCommit returns the commit checked out in the git repository of dir, or an empty string when
+// dir is not in a git repository.
+func headCommit(dir string) string {
+	repo, _, err := openSkillRepository(dir)
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
+
+// originRepository returns the repository, "owner/repo", of the "origin" remote of the git repository
+// that contains dir.
+func originRepository(dir string) (string, error) {
+	repo, _, err := openSkillRepository(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the GitHub repository of %s, set it with --repository: %w", dir, err)
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the GitHub repository of %s, set it with --repository: %w", dir, err)
+	}
+	endpoint, err := transport.NewEndpoint(remote.Config().URLs[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the URL of remote 'origin': %w", err)
+	}
+	repository := strings.TrimSuffix(strings.Trim(endpoint.Path, "/"), ".git")
+	if strings.Count(repository, "/") != 1 {
+		return "", fmt.Errorf("remote 'origin' of %s is not a GitHub repository, set it with --repository", dir)
+	}
+	return repository, nil
+}
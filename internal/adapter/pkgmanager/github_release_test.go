@@ -0,0 +1,114 @@
+package pkgmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestGitHubRelease_Publish(t *testing.T) {
+	work := t.TempDir()
+	repo, err := git.PlainInit(work, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := worktree.Commit("initial", &git.CommitOptions{AllowEmptyCommits: true, Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploads := map[string]string{} // Content types of uploaded assets by name
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	release := func(w http.ResponseWriter, status int) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"html_url":   server.URL + "/org/skills/releases/tag/v1.0.0",
+			"upload_url": server.URL + "/uploads/repos/org/skills/releases/1/assets{?name,label}",
+			"assets":     []any{},
+		})
+	}
+	mux.HandleFunc("GET /repos/org/skills/releases/tags/v1.0.0", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("POST /repos/org/skills/releases", func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body["tag_name"] != "v1.0.0" || body["target_commitish"] != head.String() {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		release(w, http.StatusCreated)
+	})
+	mux.HandleFunc("POST /uploads/repos/org/skills/releases/1/assets", func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		name := req.URL.Query().Get("name")
+		_, _ = io.Copy(io.Discard, req.Body)
+		uploads[name] = req.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"browser_download_url": server.URL + "/org/skills/releases/download/v1.0.0/" + name,
+		})
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	artifact := &port.PublishArtifact{
+		Dir:      work,
+		Name:     "code-review",
+		Version:  "v1.0.0",
+		Archive:  filepath.Join(dir, "code-review-v1.0.0.tar.gz"),
+		Manifest: filepath.Join(dir, "code-review-v1.0.0.json"),
+	}
+	for _, path := range []string{artifact.Archive, artifact.Manifest} {
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := &GitHubRelease{httpClient: server.Client(), apiBase: server.URL}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	if _, err := a.Publish(context.Background(), artifact, "org/skills"); !errors.Is(err, domain.ErrAuthenticationRequired) {
+		t.Errorf("Publish() without a token error = %v, want ErrAuthenticationRequired", err)
+	}
+
+	t.Setenv("GH_TOKEN", "test-token")
+	result, err := a.Publish(context.Background(), artifact, "org/skills")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	wantURL := server.URL + "/org/skills/releases/download/{version}/code-review-{version}.tar.gz"
+	if result.Source.Type != "archive" || result.Source.URL != wantURL || result.Version != "v1.0.0" || result.SubDir != "." {
+		t.Errorf("Publish() = %+v, source %+v, want archive source %s", result, result.Source, wantURL)
+	}
+	if result.Location != server.URL+"/org/skills/releases/tag/v1.0.0" {
+		t.Errorf("Publish() location = %s", result.Location)
+	}
+	if uploads["code-review-v1.0.0.tar.gz"] != "application/gzip" || uploads["code-review-v1.0.0.json"] != "application/json" {
+		t.Errorf("uploaded assets = %v", uploads)
+	}
+
+	if _, err := a.Publish(context.Background(), artifact, "org"); err == nil {
+		t.Error("Publish() to an invalid repository succeeded")
+	}
+}
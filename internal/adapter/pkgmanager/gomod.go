@@ -1,5 +1,5 @@
 // Package pkgmanager provides implementations of port interfaces for package manager integrations.
-// It includes adapters for Go Module proxy and Git repositories.
+// It includes adapters for Go Module proxy, Git repositories, and the npm registry.
 package pkgmanager
 
 import (
@@ -7,8 +7,10 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -18,6 +20,7 @@ import (
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
 	"github.com/mazrean/skills-pkg/internal/domain"
 	"github.com/mazrean/skills-pkg/internal/port"
 	"golang.org/x/mod/modfile"
@@ -99,7 +102,7 @@ func NewGoMod() *GoMod {
 
 	return &GoMod{
 		proxies:    proxies,
-		httpClient: &http.Client{},
+		httpClient: network.Client(),
 	}
 }
 
@@ -204,7 +207,9 @@ func (a *GoMod) Download(ctx context.Context, source *port.Source, version strin
 	}
 
 	// Try downloading with each proxy
-	err = a.downloadWithProxies(ctx, proxies, source.URL, resolvedVersion, tempDir)
+	// Module zips are extracted in a sandbox when the configuration asks for hardened extraction
+	hardened := source.Options[port.SourceOptionHardenedExtraction] == "true"
+	err = a.downloadWithProxies(ctx, proxies, source.URL, resolvedVersion, tempDir, hardened)
 	if err != nil {
 		// Clean up on error
 		_ = os.RemoveAll(tempDir)
@@ -239,6 +244,82 @@ func (a *GoMod) GetLatestVersion(ctx context.Context, source *port.Source) (stri
 	return a.fetchLatestVersionWithProxies(ctx, proxies, source.URL)
 }
 
+// ListVersions returns the versions of the module from the first proxy that lists them,
+// or the tags of its repository for a "direct" entry.
+func (a *GoMod) ListVersions(ctx context.Context, source *port.Source) ([]string, error) {
+	if err := source.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid source configuration: %w", err)
+	}
+
+	if source.Type != "go-mod" {
+		return nil, fmt.Errorf("source type must be 'go-mod', got '%s'", source.Type)
+	}
+
+	proxies := a.proxies
+	if url, ok := source.Options["proxy"]; ok && url != "" {
+		proxies = parseGOPROXY(url)
+	}
+
+	var lastErr error
+	for _, proxy := range proxies {
+		var (
+			versions []string
+			err      error
+		)
+		switch proxy.url {
+		case "off":
+			return nil, fmt.Errorf("%w: GOPROXY is set to 'off', downloads are disabled", domain.ErrNetworkFailure)
+		case "direct":
+			versions, err = a.listVersionsDirect(ctx, source.URL)
+		default:
+			versions, err = a.listVersions(ctx, proxy.url, source.URL)
+		}
+		if err == nil {
+			return versions, nil
+		}
+		lastErr = err
+	}
+
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%w: failed to list versions for %s from any proxy", domain.ErrNetworkFailure, source.URL)
+}
+
+// listVersions fetches the versions of the module from the @v/list endpoint of the proxy.
+func (a *GoMod) listVersions(ctx context.Context, proxyURL, modulePath string) ([]string, error) {
+	url := fmt.Sprintf("%s/%s/@v/list", strings.TrimSuffix(proxyURL, "/"), modulePath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return nil, fmt.Errorf("failed to list versions for %s: %w", modulePath, e)
+		}
+		return nil, fmt.Errorf("%w: failed to list versions for %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, modulePath)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: %w: module %s does not exist. Please verify the module path is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, modulePath)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: failed to list versions for %s: HTTP status %d", domain.ErrNetworkFailure, modulePath, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read the versions of %s: %w", domain.ErrNetworkFailure, modulePath, err)
+	}
+	return strings.Fields(string(body)), nil
+}
+
 // goModuleLatestInfo represents the response from the @latest endpoint.
 type goModuleLatestInfo struct {
 	Version string `json:"Version"`
@@ -291,7 +372,7 @@ func (a *GoMod) fetchLatestVersionWithProxies(ctx context.Context, proxies []pro
 
 // downloadWithProxies tries to download the module using the configured proxies.
 // It tries each proxy in order until one succeeds or all fail.
-func (a *GoMod) downloadWithProxies(ctx context.Context, proxies []proxyEntry, modulePath, version, targetDir string) error {
+func (a *GoMod) downloadWithProxies(ctx context.Context, proxies []proxyEntry, modulePath, version, targetDir string, hardened bool) error {
 	var lastErr error
 
 	for _, proxy := range proxies {
@@ -314,7 +395,7 @@ func (a *GoMod) downloadWithProxies(ctx context.Context, proxies []proxyEntry, m
 
 		// Try proxy
 		zipURL := fmt.Sprintf("%s/%s/@v/%s.zip", strings.TrimSuffix(proxy.url, "/"), modulePath, version)
-		err := a.downloadAndExtractZip(ctx, zipURL, targetDir, modulePath, version)
+		err := a.downloadAndExtractZip(ctx, zipURL, targetDir, modulePath, version, hardened)
 		if err == nil {
 			return nil
 		}
@@ -346,6 +427,9 @@ func (a *GoMod) fetchLatestVersion(ctx context.Context, proxyURL, modulePath str
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return "", fmt.Errorf("failed to fetch latest version for %s: %w", modulePath, e)
+		}
 		return "", fmt.Errorf("%w: failed to fetch latest version for %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, modulePath)
 	}
 	defer func() {
@@ -353,11 +437,11 @@ func (a *GoMod) fetchLatestVersion(ctx context.Context, proxyURL, modulePath str
 	}()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("%w: module %s not found. Please verify the module path is correct", domain.ErrNetworkFailure, modulePath)
+		return "", fmt.Errorf("%w: %w: module %s does not exist. Please verify the module path is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, modulePath)
 	}
 
 	if resp.StatusCode == http.StatusGone {
-		return "", fmt.Errorf("%w: module %s has been removed from the proxy", domain.ErrNetworkFailure, modulePath)
+		return "", fmt.Errorf("%w: %w: module %s has been removed from the proxy", domain.ErrNetworkFailure, domain.ErrSourceNotFound, modulePath)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -378,7 +462,7 @@ func (a *GoMod) fetchLatestVersion(ctx context.Context, proxyURL, modulePath str
 
 // downloadAndExtractZip downloads a zip file and extracts it to the target directory.
 // Requirements: 4.2, 4.5, 12.2, 12.3
-func (a *GoMod) downloadAndExtractZip(ctx context.Context, zipURL, targetDir, modulePath, version string) error {
+func (a *GoMod) downloadAndExtractZip(ctx context.Context, zipURL, targetDir, modulePath, version string, hardened bool) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, zipURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %w", err)
@@ -386,6 +470,9 @@ func (a *GoMod) downloadAndExtractZip(ctx context.Context, zipURL, targetDir, mo
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return fmt.Errorf("failed to download module from %s: %w", zipURL, e)
+		}
 		return fmt.Errorf("%w: failed to download module from %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, zipURL)
 	}
 	defer func() {
@@ -393,7 +480,7 @@ func (a *GoMod) downloadAndExtractZip(ctx context.Context, zipURL, targetDir, mo
 	}()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("%w: version %s not found for module %s. Please verify the version is correct", domain.ErrNetworkFailure, version, modulePath)
+		return fmt.Errorf("%w: %w: version %s does not exist for module %s. Please verify the version is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, version, modulePath)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -416,7 +503,7 @@ func (a *GoMod) downloadAndExtractZip(ctx context.Context, zipURL, targetDir, mo
 	}
 
 	// Extract zip file
-	if err := a.extractZip(tmpFile.Name(), targetDir, modulePath, version); err != nil {
+	if err := a.extractZip(ctx, tmpFile.Name(), targetDir, modulePath, version, hardened); err != nil {
 		return fmt.Errorf("failed to extract zip file: %w", err)
 	}
 
@@ -426,8 +513,16 @@ func (a *GoMod) downloadAndExtractZip(ctx context.Context, zipURL, targetDir, mo
 // extractZip extracts a zip file to the target directory.
 // Go Module zip files have a prefix directory with the module path and version,
 // which is stripped during extraction.
+// With hardened set, the zip file is parsed and extracted by a sandboxed child process.
 // Requirements: 4.2
-func (a *GoMod) extractZip(zipPath, targetDir, modulePath, version string) error {
+func (a *GoMod) extractZip(ctx context.Context, zipPath, targetDir, modulePath, version string, hardened bool) error {
+	// Go Module zip files have a prefix directory: <module>@<version>/
+	prefix := fmt.Sprintf("%s@%s/", modulePath, version)
+
+	if hardened {
+		return extractArchiveSandboxed(ctx, zipPath, targetDir, prefix)
+	}
+
 	r, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return fmt.Errorf("failed to open zip file: %w", err)
@@ -436,9 +531,14 @@ func (a *GoMod) extractZip(zipPath, targetDir, modulePath, version string) error
 		_ = r.Close()
 	}()
 
-	// Go Module zip files have a prefix directory: <module>@<version>/
-	prefix := fmt.Sprintf("%s@%s/", modulePath, version)
+	return extractZipEntries(&r.Reader, targetDir, prefix, defaultExtractionLimits)
+}
 
+// extractZipEntries extracts the files under prefix in the zip archive to the target directory,
+// stopping with an error when the limits are exceeded.
+func extractZipEntries(r *zip.Reader, targetDir, prefix string, limits *extractionLimits) error {
+	var entries int
+	var written int64
 	for _, f := range r.File {
 		// Strip the prefix directory from the path
 		name, found := strings.CutPrefix(f.Name, prefix)
@@ -452,13 +552,15 @@ func (a *GoMod) extractZip(zipPath, targetDir, modulePath, version string) error
 			continue
 		}
 
-		target := filepath.Join(targetDir, name)
+		if entries++; entries > limits.maxEntries {
+			return fmt.Errorf("zip file has more than %d entries", limits.maxEntries)
+		}
 
 		// Ensure the target is within the target directory (security check)
-		if !strings.HasPrefix(target, filepath.Clean(targetDir)+string(os.PathSeparator)) &&
-			target != filepath.Clean(targetDir) {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
 			return fmt.Errorf("invalid file path in zip: %s", f.Name)
 		}
+		target := filepath.Join(targetDir, name)
 
 		if f.FileInfo().IsDir() {
 			// Create directory
@@ -482,11 +584,24 @@ func (a *GoMod) extractZip(zipPath, targetDir, modulePath, version string) error
 				return fmt.Errorf("failed to open file in zip: %w", err)
 			}
 
-			if _, err := io.Copy(outFile, rc); err != nil {
+			// Read one byte more than allowed to detect archives that expand beyond the limits
+			n, err := io.Copy(outFile, io.LimitReader(rc, min(limits.maxBytes-written, limits.maxFileBytes)+1))
+			if err != nil {
 				_ = rc.Close()
 				_ = outFile.Close()
 				return fmt.Errorf("failed to write file %s: %w", target, err)
 			}
+			if n > limits.maxFileBytes {
+				_ = rc.Close()
+				_ = outFile.Close()
+				return fmt.Errorf("zip file has a file larger than %d bytes: %s", limits.maxFileBytes, f.Name)
+			}
+			written += n
+			if written > limits.maxBytes {
+				_ = rc.Close()
+				_ = outFile.Close()
+				return fmt.Errorf("zip file expands to more than %d bytes", limits.maxBytes)
+			}
 
 			_ = rc.Close()
 			_ = outFile.Close()
@@ -518,6 +633,31 @@ func (a *GoMod) createTempDir() (string, error) {
 	return tempDir, nil
 }
 
+// listVersionsDirect returns the tags of the repository of the module.
+func (a *GoMod) listVersionsDirect(ctx context.Context, modulePath string) ([]string, error) {
+	repoURL := "https://" + modulePath
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoURL},
+	})
+
+	auth, _ := buildAuthMethod(repoURL)
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch tags from %s: %w", domain.ErrNetworkFailure, repoURL, err)
+	}
+
+	var versions []string
+	for _, ref := range refs {
+		if tag, found := strings.CutPrefix(string(ref.Name()), "refs/tags/"); found {
+			versions = append(versions, tag)
+		}
+	}
+	return versions, nil
+}
+
 // fetchLatestVersionDirect fetches the latest version directly from the version control system.
 // It uses go-git to query the repository for the latest tag without requiring the git command.
 func (a *GoMod) fetchLatestVersionDirect(ctx context.Context, modulePath string) (string, error) {
@@ -545,7 +685,7 @@ func (a *GoMod) fetchLatestVersionDirect(ctx context.Context, modulePath string)
 	}
 
 	if latestVersion == "" {
-		return "", fmt.Errorf("%w: no version tags found for module %s", domain.ErrNetworkFailure, modulePath)
+		return "", fmt.Errorf("%w: %w: no version tags found for module %s", domain.ErrNetworkFailure, domain.ErrSourceNotFound, modulePath)
 	}
 
 	return latestVersion, nil
@@ -598,9 +738,18 @@ func (a *GoMod) downloadDirect(ctx context.Context, modulePath, version, targetD
 	}
 
 	// Copy files from clone directory to target directory, excluding .git
-	entries, err := os.ReadDir(cloneDir)
+	if err := copyWorkingTree(cloneDir, targetDir); err != nil {
+		return fmt.Errorf("failed to copy clone directory: %w", err)
+	}
+
+	return nil
+}
+
+// copyWorkingTree copies the files of the working tree in srcDir into targetDir, leaving out the .git directory.
+func copyWorkingTree(srcDir, targetDir string) error {
+	entries, err := os.ReadDir(srcDir)
 	if err != nil {
-		return fmt.Errorf("failed to read clone directory: %w", err)
+		return err
 	}
 
 	for _, entry := range entries {
@@ -609,15 +758,20 @@ func (a *GoMod) downloadDirect(ctx context.Context, modulePath, version, targetD
 			continue
 		}
 
-		src := filepath.Join(cloneDir, entry.Name())
+		src := filepath.Join(srcDir, entry.Name())
 		dst := filepath.Join(targetDir, entry.Name())
 
-		if entry.IsDir() {
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			if err := copySymlink(src, dst); err != nil {
+				return fmt.Errorf("failed to copy symbolic link %s: %w", entry.Name(), err)
+			}
+		case entry.IsDir():
 			if err := copyDir(src, dst); err != nil {
 				return fmt.Errorf("failed to copy directory %s: %w", entry.Name(), err)
 			}
-		} else {
-			if err := copyFile(src, dst); err != nil {
+		default:
+			if err := domain.CopyFile(src, dst, nil); err != nil {
 				return fmt.Errorf("failed to copy file %s: %w", entry.Name(), err)
 			}
 		}
@@ -626,38 +780,6 @@ func (a *GoMod) downloadDirect(ctx context.Context, modulePath, version, targetD
 	return nil
 }
 
-// copyFile copies a single file from src to dst.
-func copyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = srcFile.Close()
-	}()
-
-	dstFile, err := os.Create(dst)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = dstFile.Close()
-	}()
-
-	_, err = io.Copy(dstFile, srcFile)
-	if err != nil {
-		return err
-	}
-
-	// Copy permissions
-	srcInfo, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
-
-	return os.Chmod(dst, srcInfo.Mode())
-}
-
 // copyDir recursively copies a directory from src to dst.
 func copyDir(src, dst string) error {
 	// Create destination directory
@@ -681,18 +803,28 @@ func copyDir(src, dst string) error {
 		srcPath := filepath.Join(src, entry.Name())
 		dstPath := filepath.Join(dst, entry.Name())
 
-		if entry.IsDir() {
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			err = copySymlink(srcPath, dstPath)
+		case entry.IsDir():
 			err = copyDir(srcPath, dstPath)
-			if err != nil {
-				return err
-			}
-		} else {
-			err = copyFile(srcPath, dstPath)
-			if err != nil {
-				return err
-			}
+		default:
+			err = domain.CopyFile(srcPath, dstPath, nil)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// copySymlink recreates the symbolic link src at dst with the same target.
+// Links are not followed here, so that the skill manager can apply its symlink policy to them.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(target, dst)
+}
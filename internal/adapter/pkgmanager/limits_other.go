@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package pkgmanager
+
+// setSandboxLimits sets no resource limits: rlimits are only set on Linux and macOS.
+// The extraction limits still bound the number of entries and the total size.
+func setSandboxLimits() error {
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin
+
+package pkgmanager
+
+import "golang.org/x/sys/unix"
+
+// setSandboxLimits limits the size of each written file, the number of open files, and the CPU time.
+func setSandboxLimits() error {
+	for resource, limit := range map[int]uint64{
+		unix.RLIMIT_FSIZE:  256 << 20,
+		unix.RLIMIT_NOFILE: 64,
+		unix.RLIMIT_CPU:    60,
+	} {
+		if err := unix.Setrlimit(resource, &unix.Rlimit{Cur: limit, Max: limit}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package pkgmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// Local implements the PackageManager interface for directories on the local file system,
+// so that skill authors can install a skill they are working on without publishing it.
+// The version of a directory is the hash of its files, which changes whenever they are edited.
+type Local struct{}
+
+// NewLocal creates a new local directory adapter instance.
+func NewLocal() *Local {
+	return &Local{}
+}
+
+// SourceType returns "local" to identify this adapter as a local directory reader.
+func (a *Local) SourceType() string {
+	return "local"
+}
+
+// Download copies the directory, leaving out its .git directory, and returns the hash of the files as the version.
+// The directory has no history, so the current files are copied whatever version is requested.
+func (a *Local) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	dir, err := a.directory(source)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := a.createTempDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := copyWorkingTree(dir, tempDir); err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to copy local directory %s: %w", dir, err)
+	}
+
+	// The copy is hashed, so that the version matches the files that are installed
+	contentVersion, err := hashWorkingTree(tempDir)
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, err
+	}
+
+	return &port.DownloadResult{
+		Path:    tempDir,
+		Version: contentVersion,
+	}, nil
+}
+
+// GetLatestVersion returns the hash of the files in the directory, leaving out its .git directory.
+func (a *Local) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	dir, err := a.directory(source)
+	if err != nil {
+		return "", err
+	}
+	return hashWorkingTree(dir)
+}
+
+// directory validates the source and returns the directory it refers to.
+// Relative paths are relative to the working directory, which is the directory of the configuration file.
+func (a *Local) directory(source *port.Source) (string, error) {
+	if err := source.Validate(); err != nil {
+		return "", fmt.Errorf("invalid source configuration: %w", err)
+	}
+	if source.Type != "local" {
+		return "", fmt.Errorf("source type must be 'local', got '%s'", source.Type)
+	}
+
+	info, err := os.Stat(source.URL)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: local directory %s does not exist. Please verify the path is correct", domain.ErrSourceNotFound, source.URL)
+		}
+		return "", fmt.Errorf("failed to access local directory %s: %w", source.URL, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("local source %s is not a directory", source.URL)
+	}
+	return source.URL, nil
+}
+
+// createTempDir creates a new temporary directory for the copy of a local directory.
+// It uses the SKILLSPKG_TEMP_DIR environment variable if set, otherwise uses os.TempDir().
+func (a *Local) createTempDir() (string, error) {
+	baseDir := os.Getenv("SKILLSPKG_TEMP_DIR")
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(baseDir, dirPerms); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(baseDir, "skills-pkg-local-")
+}
+
+// hashWorkingTree returns the h1: hash of the files in dir, leaving out the .git directory.
+func hashWorkingTree(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list files of local directory %s: %w", dir, err)
+	}
+
+	hash, err := dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash local directory %s: %w", dir, err)
+	}
+	return hash, nil
+}
@@ -0,0 +1,99 @@
+package pkgmanager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestLocal_Download(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"SKILL.md":      "# Local\n",
+		"docs/usage.md": "usage\n",
+		".git/HEAD":     "ref: refs/heads/main\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	a := NewLocal()
+	source := &port.Source{Type: "local", URL: dir}
+
+	result, err := a.Download(ctx, source, "")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(result.Path) }()
+
+	if result.Path == dir {
+		t.Error("Download() returned the local directory instead of a copy")
+	}
+	if data, err := os.ReadFile(filepath.Join(result.Path, "docs", "usage.md")); err != nil || string(data) != "usage\n" {
+		t.Errorf("docs/usage.md = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(result.Path, ".git")); !os.IsNotExist(err) {
+		t.Errorf("Download() copied the .git directory: %v", err)
+	}
+	if !strings.HasPrefix(result.Version, "h1:") {
+		t.Errorf("Download() version = %s, want a content hash", result.Version)
+	}
+
+	latest, err := a.GetLatestVersion(ctx, source)
+	if err != nil {
+		t.Fatalf("GetLatestVersion() error = %v", err)
+	}
+	if latest != result.Version {
+		t.Errorf("GetLatestVersion() = %s, want the version of Download() %s", latest, result.Version)
+	}
+
+	// Editing the files changes the version, while the .git directory does not count
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/dev\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := a.GetLatestVersion(ctx, source); err != nil || got != latest {
+		t.Errorf("GetLatestVersion() after a .git change = %s, %v, want %s", got, err, latest)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# Edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := a.GetLatestVersion(ctx, source); err != nil || got == latest {
+		t.Errorf("GetLatestVersion() after an edit = %s, %v, want a new version", got, err)
+	}
+}
+
+func TestLocal_Errors(t *testing.T) {
+	ctx := context.Background()
+	a := NewLocal()
+
+	missing := &port.Source{Type: "local", URL: filepath.Join(t.TempDir(), "missing")}
+	if _, err := a.Download(ctx, missing, ""); !errors.Is(err, domain.ErrSourceNotFound) {
+		t.Errorf("Download() of a missing directory error = %v, want ErrSourceNotFound", err)
+	}
+	if _, err := a.GetLatestVersion(ctx, missing); !errors.Is(err, domain.ErrSourceNotFound) {
+		t.Errorf("GetLatestVersion() of a missing directory error = %v, want ErrSourceNotFound", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "SKILL.md")
+	if err := os.WriteFile(file, []byte("# Skill\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Download(ctx, &port.Source{Type: "local", URL: file}, ""); err == nil {
+		t.Error("Download() of a file succeeded")
+	}
+	if _, err := a.Download(ctx, &port.Source{Type: "git", URL: file}, ""); err == nil {
+		t.Error("Download() of a git source succeeded")
+	}
+}
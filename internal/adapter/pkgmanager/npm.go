@@ -0,0 +1,459 @@
+package pkgmanager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// defaultNpmRegistry is the registry used when neither the source options nor the environment name one.
+const defaultNpmRegistry = "https://registry.npmjs.org"
+
+// Npm implements the PackageManager interface for the npm registry.
+// It fetches package metadata from the registry, downloads the tarball of a version,
+// and extracts it without the package/ directory that npm tarballs wrap their files in.
+type Npm struct {
+	httpClient *http.Client
+	registry   string
+	token      string // Bearer token sent to the registry; empty for anonymous access
+}
+
+// NewNpm creates a new npm adapter instance.
+// It uses the public npm registry unless overridden by the "registry" source option or the
+// NPM_CONFIG_REGISTRY environment variable, and authenticates with NPM_TOKEN when it is set.
+func NewNpm() *Npm {
+	registry := os.Getenv("NPM_CONFIG_REGISTRY")
+	if registry == "" {
+		registry = os.Getenv("npm_config_registry")
+	}
+	if registry == "" {
+		registry = defaultNpmRegistry
+	}
+
+	return &Npm{
+		httpClient: network.Client(),
+		registry:   registry,
+		token:      os.Getenv("NPM_TOKEN"),
+	}
+}
+
+// SourceType returns "npm" to identify this adapter as an npm package manager.
+func (a *Npm) SourceType() string {
+	return "npm"
+}
+
+// Download downloads a skill from the npm registry.
+// If version is "latest" or empty, it uses the version of the latest dist-tag.
+// Other dist-tags, such as "next", are resolved to their versions too.
+func (a *Npm) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	if err := a.validateSource(source); err != nil {
+		return nil, err
+	}
+
+	registry := a.registryOf(source)
+	packument, err := a.fetchPackument(ctx, registry, source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedVersion, dist, err := packument.resolve(source.URL, version)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := a.createTempDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	// Tarballs are extracted in a sandbox when the configuration asks for hardened extraction
+	hardened := source.Options[port.SourceOptionHardenedExtraction] == "true"
+	if err := a.downloadAndExtractTarball(ctx, registry, dist, tempDir, source.URL, resolvedVersion, hardened); err != nil {
+		// Clean up on error
+		_ = os.RemoveAll(tempDir)
+		return nil, err
+	}
+
+	return &port.DownloadResult{
+		Path:    tempDir,
+		Version: resolvedVersion,
+	}, nil
+}
+
+// GetLatestVersion retrieves the version of the latest dist-tag from the npm registry.
+func (a *Npm) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	if err := a.validateSource(source); err != nil {
+		return "", err
+	}
+
+	packument, err := a.fetchPackument(ctx, a.registryOf(source), source.URL)
+	if err != nil {
+		return "", err
+	}
+
+	version, _, err := packument.resolve(source.URL, "latest")
+	return version, err
+}
+
+// ListVersions returns the published versions of the package.
+func (a *Npm) ListVersions(ctx context.Context, source *port.Source) ([]string, error) {
+	if err := a.validateSource(source); err != nil {
+		return nil, err
+	}
+
+	packument, err := a.fetchPackument(ctx, a.registryOf(source), source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Collect(maps.Keys(packument.Versions)), nil
+}
+
+// validateSource checks that source is a valid npm source.
+func (a *Npm) validateSource(source *port.Source) error {
+	if err := source.Validate(); err != nil {
+		return fmt.Errorf("invalid source configuration: %w", err)
+	}
+
+	if source.Type != "npm" {
+		return fmt.Errorf("source type must be 'npm', got '%s'", source.Type)
+	}
+
+	return nil
+}
+
+// registryOf returns the registry URL for source, preferring the "registry" source option.
+func (a *Npm) registryOf(source *port.Source) string {
+	if registry, ok := source.Options["registry"]; ok && registry != "" {
+		return strings.TrimSuffix(registry, "/")
+	}
+	return strings.TrimSuffix(a.registry, "/")
+}
+
+// npmPackument represents the abbreviated package metadata returned by the registry.
+type npmPackument struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Versions map[string]struct {
+		Dist npmDist `json:"dist"`
+	} `json:"versions"`
+}
+
+// npmDist describes the tarball of a package version.
+type npmDist struct {
+	Tarball   string `json:"tarball"`
+	Integrity string `json:"integrity"` // Subresource Integrity string, e.g. "sha512-<base64>"
+	Shasum    string `json:"shasum"`    // Hex SHA-1 of the tarball, published by older clients
+}
+
+// resolve returns the version that version names, either a dist-tag or an exact version, and its tarball.
+// An empty version resolves to the latest dist-tag.
+func (p *npmPackument) resolve(packageName, version string) (string, *npmDist, error) {
+	if version == "" {
+		version = "latest"
+	}
+	if tagged, ok := p.DistTags[version]; ok {
+		version = tagged
+	}
+
+	v, ok := p.Versions[version]
+	if !ok {
+		// Versions are often written with a "v" prefix, as for Git tags
+		if v, ok = p.Versions[strings.TrimPrefix(version, "v")]; !ok {
+			return "", nil, fmt.Errorf("%w: %w: version %s does not exist for package %s. Please verify the version is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, version, packageName)
+		}
+		version = strings.TrimPrefix(version, "v")
+	}
+	if v.Dist.Tarball == "" {
+		return "", nil, fmt.Errorf("no tarball found for version %s of package %s", version, packageName)
+	}
+
+	return version, &v.Dist, nil
+}
+
+// fetchPackument fetches the metadata of the package from the registry.
+func (a *Npm) fetchPackument(ctx context.Context, registry, packageName string) (*npmPackument, error) {
+	// Scoped packages are requested as @scope%2fname
+	metadataURL := registry + "/" + url.PathEscape(packageName)
+
+	req, err := a.newRequest(ctx, registry, metadataURL)
+	if err != nil {
+		return nil, err
+	}
+	// The abbreviated metadata holds everything needed to install, and is much smaller
+	req.Header.Set("Accept", "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return nil, fmt.Errorf("failed to fetch package metadata for %s: %w", packageName, e)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch package metadata for %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, packageName)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %w: package %s does not exist in %s. Please verify the package name is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, packageName, registry)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: failed to fetch package metadata for %s: HTTP status %d", domain.ErrNetworkFailure, packageName, resp.StatusCode)
+	}
+
+	var packument npmPackument
+	if err := json.NewDecoder(resp.Body).Decode(&packument); err != nil {
+		return nil, fmt.Errorf("failed to parse package metadata for %s: %w", packageName, err)
+	}
+
+	return &packument, nil
+}
+
+// downloadAndExtractTarball downloads the tarball of dist, checks it against the published
+// integrity, and extracts it to the target directory.
+func (a *Npm) downloadAndExtractTarball(ctx context.Context, registry string, dist *npmDist, targetDir, packageName, version string, hardened bool) error {
+	req, err := a.newRequest(ctx, registry, dist.Tarball)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return fmt.Errorf("failed to download package from %s: %w", dist.Tarball, e)
+		}
+		return fmt.Errorf("%w: failed to download package from %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, dist.Tarball)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %w: tarball of version %s of package %s does not exist", domain.ErrNetworkFailure, domain.ErrSourceNotFound, version, packageName)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: failed to download package from %s: HTTP status %d", domain.ErrNetworkFailure, dist.Tarball, resp.StatusCode)
+	}
+
+	// Create a temporary file to store the tarball
+	tmpFile, err := os.CreateTemp("", "skills-pkg-npm-*.tgz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	check, err := newIntegrityCheck(dist)
+	if err != nil {
+		return fmt.Errorf("invalid integrity of version %s of package %s: %w", version, packageName, err)
+	}
+
+	// Download to temp file
+	if _, err := io.Copy(io.MultiWriter(tmpFile, check.hash), resp.Body); err != nil {
+		return fmt.Errorf("failed to download tarball: %w", err)
+	}
+	if err := check.verify(); err != nil {
+		return fmt.Errorf("tarball of version %s of package %s: %w", version, packageName, err)
+	}
+
+	if hardened {
+		err = extractArchiveSandboxed(ctx, tmpFile.Name(), targetDir, "")
+	} else {
+		if _, err = tmpFile.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to read tarball: %w", err)
+		}
+		err = extractTarGzEntries(tmpFile, targetDir, defaultExtractionLimits)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract tarball: %w", err)
+	}
+
+	return nil
+}
+
+// newRequest creates a GET request for rawURL, with the token of the adapter when rawURL is on the registry host.
+// Tarballs may be served from other hosts, which must not receive the token.
+func (a *Npm) newRequest(ctx context.Context, registry, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if a.token != "" {
+		if registryURL, err := url.Parse(registry); err == nil && registryURL.Host == req.URL.Host {
+			req.Header.Set("Authorization", "Bearer "+a.token)
+		}
+	}
+
+	return req, nil
+}
+
+// integrityCheck compares the hash of a downloaded tarball with the published one.
+type integrityCheck struct {
+	hash hash.Hash
+	want []byte
+}
+
+// newIntegrityCheck returns the check of the strongest hash published for dist.
+// Tarballs without a published hash are accepted unchecked.
+func newIntegrityCheck(dist *npmDist) (*integrityCheck, error) {
+	// Integrity strings may list several hashes, separated by spaces
+	for entry := range strings.FieldsSeq(dist.Integrity) {
+		digest, found := strings.CutPrefix(entry, "sha512-")
+		if !found {
+			continue
+		}
+		// Options such as "?foo" may follow the digest
+		digest, _, _ = strings.Cut(digest, "?")
+		want, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			return nil, fmt.Errorf("malformed sha512 digest: %w", err)
+		}
+		return &integrityCheck{hash: sha512.New(), want: want}, nil
+	}
+
+	if dist.Shasum != "" {
+		want, err := hex.DecodeString(dist.Shasum)
+		if err != nil {
+			return nil, fmt.Errorf("malformed shasum: %w", err)
+		}
+		return &integrityCheck{hash: sha1.New(), want: want}, nil
+	}
+
+	return &integrityCheck{hash: sha512.New()}, nil
+}
+
+// verify reports an error when the hash of the written data differs from the published one.
+func (c *integrityCheck) verify() error {
+	if c.want == nil {
+		return nil
+	}
+	if got := c.hash.Sum(nil); !bytes.Equal(got, c.want) {
+		return fmt.Errorf("integrity check failed: the downloaded tarball does not match the hash published by the registry")
+	}
+	return nil
+}
+
+// extractTarGzEntries extracts the files of the gzipped tar archive in r to the target directory,
+// stopping with an error when the limits are exceeded.
+// The top-level directory of every entry, package/ in tarballs packed by npm, is stripped.
+// Like npm, entries other than files and directories, such as links, are skipped.
+func extractTarGzEntries(r io.Reader, targetDir string, limits *extractionLimits) error {
+	return extractTarGz(r, targetDir, true, limits)
+}
+
+// extractTarGz extracts the files of the gzipped tar archive in r to the target directory like
+// extractTarGzEntries, stripping the top-level directory of every entry only when strip is set.
+func extractTarGz(r io.Reader, targetDir string, strip bool, limits *extractionLimits) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	tr := tar.NewReader(gz)
+	var entries int
+	var written int64
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if strip {
+			// Strip the top-level directory from the path
+			var found bool
+			if _, name, found = strings.Cut(name, "/"); !found {
+				continue
+			}
+		}
+		if name == "" {
+			continue
+		}
+
+		if entries++; entries > limits.maxEntries {
+			return fmt.Errorf("tarball has more than %d entries", limits.maxEntries)
+		}
+
+		// Ensure the target is within the target directory (security check)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("invalid file path in tarball: %s", header.Name)
+		}
+		target := filepath.Join(targetDir, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, dirPerms); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), dirPerms); err != nil {
+				return fmt.Errorf("failed to create directory for file %s: %w", target, err)
+			}
+
+			// npm makes every file readable, whatever the mode in the tarball
+			outFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm()|0o644)
+			if err != nil {
+				return fmt.Errorf("failed to create file %s: %w", target, err)
+			}
+
+			// Read one byte more than allowed to detect archives that expand beyond the limits
+			n, err := io.Copy(outFile, io.LimitReader(tr, min(limits.maxBytes-written, limits.maxFileBytes)+1))
+			_ = outFile.Close()
+			if err != nil {
+				return fmt.Errorf("failed to write file %s: %w", target, err)
+			}
+			if n > limits.maxFileBytes {
+				return fmt.Errorf("tarball has a file larger than %d bytes: %s", limits.maxFileBytes, header.Name)
+			}
+			written += n
+			if written > limits.maxBytes {
+				return fmt.Errorf("tarball expands to more than %d bytes", limits.maxBytes)
+			}
+		}
+	}
+}
+
+// createTempDir creates a new temporary directory for a package.
+// It uses the SKILLSPKG_TEMP_DIR environment variable if set, otherwise uses os.TempDir().
+func (a *Npm) createTempDir() (string, error) {
+	baseDir := os.Getenv("SKILLSPKG_TEMP_DIR")
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(baseDir, dirPerms); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(baseDir, "skills-pkg-npm-")
+}
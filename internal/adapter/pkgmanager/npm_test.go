@@ -0,0 +1,263 @@
+package pkgmanager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// writeTestTarball returns a gzipped tar archive with the given entries.
+func writeTestTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write tar entry: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newTestRegistry serves the package name with a 1.0.0 and a 2.0.0-beta.1 version, whose tarballs are tarball.
+// integrity overrides the published integrity of the tarballs when it is not empty.
+// The Authorization headers of the requests are recorded in auth.
+func newTestRegistry(t *testing.T, name string, tarball []byte, integrity string, auth *[]string) *httptest.Server {
+	t.Helper()
+
+	if integrity == "" {
+		sum := sha512.Sum512(tarball)
+		integrity = "sha512-" + base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if auth != nil {
+			*auth = append(*auth, r.Header.Get("Authorization"))
+		}
+		if strings.HasSuffix(r.URL.Path, ".tgz") {
+			_, _ = w.Write(tarball)
+			return
+		}
+		if r.URL.EscapedPath() != "/"+strings.ReplaceAll(name, "/", "%2F") {
+			http.NotFound(w, r)
+			return
+		}
+
+		versions := map[string]any{}
+		for _, version := range []string{"1.0.0", "2.0.0-beta.1"} {
+			versions[version] = map[string]any{"dist": map[string]string{
+				"tarball":   server.URL + "/" + name + "/-/skill-" + version + ".tgz",
+				"integrity": integrity,
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"dist-tags": map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.1"},
+			"versions":  versions,
+		})
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNpm_SourceType(t *testing.T) {
+	if got := NewNpm().SourceType(); got != "npm" {
+		t.Errorf("SourceType() = %v, want npm", got)
+	}
+}
+
+func TestNpm_Download(t *testing.T) {
+	tarball := writeTestTarball(t, map[string]string{
+		"package/SKILL.md":       "# Skill\n",
+		"package/scripts/run.sh": "echo hello\n",
+	})
+	registry := newTestRegistry(t, "@example/skill", tarball, "", nil)
+
+	tests := []struct {
+		name        string
+		version     string
+		wantVersion string
+	}{
+		{name: "empty version resolves to latest", version: "", wantVersion: "1.0.0"},
+		{name: "latest", version: "latest", wantVersion: "1.0.0"},
+		{name: "dist-tag", version: "next", wantVersion: "2.0.0-beta.1"},
+		{name: "exact version", version: "1.0.0", wantVersion: "1.0.0"},
+		{name: "version with v prefix", version: "v1.0.0", wantVersion: "1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SKILLSPKG_TEMP_DIR", t.TempDir())
+			source := &port.Source{Type: "npm", URL: "@example/skill", Options: map[string]string{"registry": registry.URL}}
+
+			result, err := NewNpm().Download(context.Background(), source, tt.version)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			if result.Version != tt.wantVersion {
+				t.Errorf("Download() version = %v, want %v", result.Version, tt.wantVersion)
+			}
+
+			data, err := os.ReadFile(filepath.Join(result.Path, "scripts", "run.sh"))
+			if err != nil || string(data) != "echo hello\n" {
+				t.Errorf("scripts/run.sh = %q, %v; the package/ prefix should be stripped", data, err)
+			}
+		})
+	}
+}
+
+func TestNpm_DownloadErrors(t *testing.T) {
+	tarball := writeTestTarball(t, map[string]string{"package/SKILL.md": "# Skill\n"})
+
+	tests := []struct {
+		name      string
+		pkg       string
+		version   string
+		integrity string
+		wantErr   string
+		notFound  bool
+	}{
+		{name: "package not found", pkg: "missing", notFound: true},
+		{name: "version not found", pkg: "skill", version: "9.9.9", notFound: true},
+		{name: "integrity mismatch", pkg: "skill", integrity: "sha512-" + base64.StdEncoding.EncodeToString(make([]byte, sha512.Size)), wantErr: "integrity check failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SKILLSPKG_TEMP_DIR", t.TempDir())
+			registry := newTestRegistry(t, "skill", tarball, tt.integrity, nil)
+			source := &port.Source{Type: "npm", URL: tt.pkg, Options: map[string]string{"registry": registry.URL}}
+
+			_, err := NewNpm().Download(context.Background(), source, tt.version)
+			if err == nil {
+				t.Fatal("Download() should fail")
+			}
+			if tt.notFound && !errors.Is(err, domain.ErrSourceNotFound) {
+				t.Errorf("Download() error = %v, want ErrSourceNotFound", err)
+			}
+			if tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Download() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("wrong source type", func(t *testing.T) {
+		if _, err := NewNpm().Download(context.Background(), &port.Source{Type: "git", URL: "skill"}, ""); err == nil {
+			t.Error("Download() should fail for a git source")
+		}
+	})
+}
+
+func TestNpm_GetLatestVersion(t *testing.T) {
+	registry := newTestRegistry(t, "skill", nil, "", nil)
+	t.Setenv("NPM_CONFIG_REGISTRY", registry.URL)
+
+	version, err := NewNpm().GetLatestVersion(context.Background(), &port.Source{Type: "npm", URL: "skill"})
+	if err != nil {
+		t.Fatalf("GetLatestVersion() error = %v", err)
+	}
+	if version != "1.0.0" {
+		t.Errorf("GetLatestVersion() = %v, want 1.0.0", version)
+	}
+}
+
+func TestNpm_Token(t *testing.T) {
+	t.Setenv("SKILLSPKG_TEMP_DIR", t.TempDir())
+	t.Setenv("NPM_TOKEN", "secret")
+
+	var auth []string
+	tarball := writeTestTarball(t, map[string]string{"package/SKILL.md": "# Skill\n"})
+	registry := newTestRegistry(t, "skill", tarball, "", &auth)
+
+	source := &port.Source{Type: "npm", URL: "skill", Options: map[string]string{"registry": registry.URL}}
+	if _, err := NewNpm().Download(context.Background(), source, ""); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	if len(auth) != 2 {
+		t.Fatalf("registry received %d requests, want metadata and tarball", len(auth))
+	}
+	for _, header := range auth {
+		if header != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the token for the registry host", header)
+		}
+	}
+}
+
+// TestExtractTarGzSandboxed tests extraction of tarballs in the sandboxed child process
+func TestExtractTarGzSandboxed(t *testing.T) {
+	tarballPath := filepath.Join(t.TempDir(), "skill.tgz")
+	tarball := writeTestTarball(t, map[string]string{"package/SKILL.md": "# Skill\n"})
+	if err := os.WriteFile(tarballPath, tarball, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	targetDir := t.TempDir()
+
+	if err := extractArchiveSandboxed(context.Background(), tarballPath, targetDir, ""); err != nil {
+		skipWithoutSandbox(t, err)
+		t.Fatalf("extractArchiveSandboxed() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(targetDir, "SKILL.md"))
+	if err != nil || string(data) != "# Skill\n" {
+		t.Errorf("SKILL.md = %q, %v", data, err)
+	}
+}
+
+// TestExtractTarGzEntries tests path checks and extraction limits of tarballs
+func TestExtractTarGzEntries(t *testing.T) {
+	tests := []struct {
+		files   map[string]string
+		limits  *extractionLimits
+		name    string
+		wantErr string
+	}{
+		{name: "within limits", files: map[string]string{"package/a.txt": strings.Repeat("a", 100), "package/b.txt": strings.Repeat("b", 100)}, limits: &extractionLimits{maxEntries: 2, maxBytes: 200, maxFileBytes: 100}},
+		{name: "too many entries", files: map[string]string{"package/a.txt": "a", "package/b.txt": "b"}, limits: &extractionLimits{maxEntries: 1, maxBytes: 200, maxFileBytes: 100}, wantErr: "more than 1 entries"},
+		{name: "too large", files: map[string]string{"package/a.txt": strings.Repeat("a", 100), "package/b.txt": strings.Repeat("b", 100)}, limits: &extractionLimits{maxEntries: 2, maxBytes: 150, maxFileBytes: 100}, wantErr: "more than 150 bytes"},
+		{name: "file too large", files: map[string]string{"package/a.txt": strings.Repeat("a", 200)}, limits: &extractionLimits{maxEntries: 2, maxBytes: 400, maxFileBytes: 150}, wantErr: "file larger than 150 bytes"},
+		{name: "path traversal", files: map[string]string{"package/../../escape.txt": "x"}, limits: defaultExtractionLimits, wantErr: "invalid file path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := extractTarGzEntries(bytes.NewReader(writeTestTarball(t, tt.files)), t.TempDir(), tt.limits)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("extractTarGzEntries() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("extractTarGzEntries() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
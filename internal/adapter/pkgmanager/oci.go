@@ -0,0 +1,827 @@
+package pkgmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+	"golang.org/x/mod/semver"
+)
+
+// Media types of the OCI and Docker manifests that the OCI adapter accepts.
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	ociIndexMediaType       = "application/vnd.oci.image.index.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	dockerListMediaType     = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// Media types of the artifacts that 'skills-pkg publish' pushes: the archive of the skill is the
+// layer, as ORAS pushes a directory, and the manifest of the skill is the config.
+const (
+	ociLayerMediaType        = "application/vnd.oci.image.layer.v1.tar+gzip"
+	ociSkillConfigMediaType  = "application/vnd.skills-pkg.skill.config.v1+json"
+	ociVersionAnnotation     = "org.opencontainers.image.version"
+	ociDescriptionAnnotation = "org.opencontainers.image.description"
+)
+
+// Annotations that ORAS sets on the layers it pushes.
+const (
+	ociTitleAnnotation  = "org.opencontainers.image.title" // File or directory name of the layer
+	ociUnpackAnnotation = "io.deis.oras.content.unpack"    // "true" for directories packed as gzipped tar files
+)
+
+// maxManifestSize bounds the size of manifests and tag lists read from a registry.
+const maxManifestSize = 4 << 20
+
+// ociDigestPattern matches the sha256 digests that manifests and blobs are addressed by.
+var ociDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ociTagPattern matches the tags that the OCI distribution specification allows.
+var ociTagPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+// OCI implements the PackageManager interface for skills published as OCI artifacts in a
+// container registry, such as with 'oras push ghcr.io/org/skill:1.0.0 ./skill'.
+// The URL of a source is the repository, such as ghcr.io/org/skill, and versions are tags or digests.
+// Every manifest and layer is checked against its digest.
+type OCI struct {
+	httpClient *http.Client
+	tokens     map[string]string // Bearer tokens by registry host and repository
+	tokensMu   sync.Mutex
+}
+
+// NewOCI creates a new OCI adapter instance.
+// It authenticates with the credentials that 'docker login' and 'oras login' store in the Docker
+// configuration file, and pulls anonymously from registries without credentials.
+func NewOCI() *OCI {
+	return &OCI{
+		httpClient: network.Client(),
+		tokens:     make(map[string]string),
+	}
+}
+
+// SourceType returns "oci" to identify this adapter as an OCI registry package manager.
+func (a *OCI) SourceType() string {
+	return "oci"
+}
+
+// ociReference is a repository in a registry.
+type ociReference struct {
+	host       string // Registry host, with the port if any
+	repository string // Repository path within the registry
+	scheme     string // "https", or "http" for registries on the local machine
+}
+
+// Download pulls the artifact tagged version, or the one with the digest version, from the registry.
+// If version is empty, the "latest" tag is pulled. A tag is resolved to the digest of its manifest,
+// which is checked like every layer, and the layers are extracted in order.
+// The version returned is the digest, as tags can be moved to other manifests.
+func (a *OCI) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	ref, err := a.parseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		version = "latest"
+	}
+
+	manifest, digest, err := a.fetchManifest(ctx, ref, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("artifact %s:%s has no layers", source.URL, version)
+	}
+
+	tempDir, err := a.createTempDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	// Layers are extracted in a sandbox when the configuration asks for hardened extraction
+	hardened := source.Options[port.SourceOptionHardenedExtraction] == "true"
+	for _, layer := range manifest.Layers {
+		if err := a.extractLayer(ctx, ref, layer, tempDir, hardened); err != nil {
+			// Clean up on error
+			_ = os.RemoveAll(tempDir)
+			return nil, fmt.Errorf("failed to pull layer %s of %s:%s: %w", layer.Digest, source.URL, version, err)
+		}
+	}
+
+	return &port.DownloadResult{
+		Path:    tempDir,
+		Version: digest,
+	}, nil
+}
+
+// GetLatestVersion returns the highest semantic version among the tags of the repository,
+// preferring releases over prereleases. Tags with and without the "v" prefix are compared alike.
+// It returns "latest" when no tag is a semantic version but the repository has a latest tag.
+func (a *OCI) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	ref, err := a.parseSource(source)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := a.listTags(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	var latestRelease, latestPre, latestReleaseTag, latestPreTag string
+	hasLatest := false
+	for _, tag := range tags {
+		if tag == "latest" {
+			hasLatest = true
+		}
+		v := tag
+		if !strings.HasPrefix(v, "v") {
+			v = "v" + v
+		}
+		if !semver.IsValid(v) {
+			continue
+		}
+		if semver.Prerelease(v) == "" {
+			if latestRelease == "" || semver.Compare(v, latestRelease) > 0 {
+				latestRelease, latestReleaseTag = v, tag
+			}
+		} else if latestPre == "" || semver.Compare(v, latestPre) > 0 {
+			latestPre, latestPreTag = v, tag
+		}
+	}
+
+	switch {
+	case latestReleaseTag != "":
+		return latestReleaseTag, nil
+	case latestPreTag != "":
+		return latestPreTag, nil
+	case hasLatest:
+		return "latest", nil
+	}
+	return "", fmt.Errorf("%w: %w: no semantic version tags found in %s. Please pin a version", domain.ErrNetworkFailure, domain.ErrSourceNotFound, source.URL)
+}
+
+// ListVersions returns the tags of the repository.
+func (a *OCI) ListVersions(ctx context.Context, source *port.Source) ([]string, error) {
+	ref, err := a.parseSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.listTags(ctx, ref)
+}
+
+// parseSource checks that source is a valid OCI source and returns the repository it names.
+// Repositories without a registry host, such as library/alpine, are on Docker Hub.
+func (a *OCI) parseSource(source *port.Source) (*ociReference, error) {
+	if err := source.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid source configuration: %w", err)
+	}
+	if source.Type != "oci" {
+		return nil, fmt.Errorf("source type must be 'oci', got '%s'", source.Type)
+	}
+
+	name := strings.TrimPrefix(source.URL, "oci://")
+	if strings.ContainsAny(name, "@") || strings.Contains(name[strings.LastIndex(name, "/")+1:], ":") {
+		return nil, fmt.Errorf("invalid OCI repository %s: give the tag or digest as the version, not in the URL", source.URL)
+	}
+
+	host, repository, found := strings.Cut(name, "/")
+	if !found || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		// Docker Hub repositories are written without the registry, and official images without their namespace
+		host, repository = "registry-1.docker.io", name
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	if host == "docker.io" || host == "index.docker.io" {
+		host = "registry-1.docker.io"
+	}
+	if repository == "" || repository != strings.ToLower(repository) {
+		return nil, fmt.Errorf("invalid OCI repository %s: repository names are lowercase", source.URL)
+	}
+
+	// Registries on the local machine, such as those started for testing, are served over plain HTTP
+	scheme := "https"
+	if hostname := hostWithoutPort(host); hostname == "localhost" || isLoopback(hostname) {
+		scheme = "http"
+	}
+
+	return &ociReference{host: host, repository: repository, scheme: scheme}, nil
+}
+
+// hostWithoutPort returns host without its port, if any.
+func hostWithoutPort(host string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+	return host
+}
+
+// isLoopback reports whether host is a loopback IP address.
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ociDescriptor points to a manifest or a blob by its digest.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an image manifest or an index of manifests.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"` // Set for indexes
+}
+
+// fetchManifest fetches the manifest that reference, a tag or a digest, points to, checking it
+// against the digest, and returns it with its digest. An index is resolved to the first manifest it
+// lists, as skills are the same on every platform, and the digest returned is that of the index.
+func (a *OCI) fetchManifest(ctx context.Context, ref *ociReference, reference string) (*ociManifest, string, error) {
+	resp, err := a.get(ctx, ref, "/manifests/"+reference, strings.Join([]string{ociManifestMediaType, ociIndexMediaType, dockerManifestMediaType, dockerListMediaType}, ", "))
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", fmt.Errorf("%w: %w: %s does not exist in repository %s/%s. Please verify the version is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, reference, ref.host, ref.repository)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", a.statusError(ref, "manifest "+reference, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: failed to read manifest %s of %s/%s: %w", domain.ErrNetworkFailure, reference, ref.host, ref.repository, err)
+	}
+	if len(body) > maxManifestSize {
+		return nil, "", fmt.Errorf("manifest %s of %s/%s is larger than %d bytes", reference, ref.host, ref.repository, maxManifestSize)
+	}
+
+	// A digest reference must match the content; a tag must match the digest the registry reports for it
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if ociDigestPattern.MatchString(reference) && reference != digest {
+		return nil, "", fmt.Errorf("integrity check failed: manifest %s of %s/%s has digest %s", reference, ref.host, ref.repository, digest)
+	}
+	if reported := resp.Header.Get("Docker-Content-Digest"); ociDigestPattern.MatchString(reported) && reported != digest {
+		return nil, "", fmt.Errorf("integrity check failed: manifest %s of %s/%s has digest %s, but the registry reports %s", reference, ref.host, ref.repository, digest, reported)
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse manifest %s of %s/%s: %w", reference, ref.host, ref.repository, err)
+	}
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType, _, _ = strings.Cut(resp.Header.Get("Content-Type"), ";")
+	}
+
+	if mediaType == ociIndexMediaType || mediaType == dockerListMediaType || (len(manifest.Manifests) > 0 && len(manifest.Layers) == 0) {
+		if len(manifest.Manifests) == 0 {
+			return nil, "", fmt.Errorf("index %s of %s/%s lists no manifests", reference, ref.host, ref.repository)
+		}
+		next := manifest.Manifests[0].Digest
+		if !ociDigestPattern.MatchString(next) {
+			return nil, "", fmt.Errorf("index %s of %s/%s lists a manifest with an unsupported digest %s", reference, ref.host, ref.repository, next)
+		}
+		resolved, _, err := a.fetchManifest(ctx, ref, next)
+		return resolved, digest, err
+	}
+
+	return &manifest, digest, nil
+}
+
+// extractLayer downloads the blob of layer, checks it against its digest, and extracts it to targetDir.
+// Directories pushed by ORAS are gzipped tar files of the directory, which is stripped so that its
+// content is at the root. Other layers with a title are single files, written under their title.
+func (a *OCI) extractLayer(ctx context.Context, ref *ociReference, layer ociDescriptor, targetDir string, hardened bool) error {
+	if !ociDigestPattern.MatchString(layer.Digest) {
+		return fmt.Errorf("unsupported digest %s: only sha256 digests are supported", layer.Digest)
+	}
+	title := layer.Annotations[ociTitleAnnotation]
+	unpack := layer.Annotations[ociUnpackAnnotation] == "true" || strings.HasSuffix(layer.MediaType, "tar+gzip") || strings.HasSuffix(layer.MediaType, ".tar.gzip")
+	if !unpack && title == "" {
+		return fmt.Errorf("layer of type %s has neither a file name nor a gzipped tar file of a directory", layer.MediaType)
+	}
+
+	resp, err := a.get(ctx, ref, "/blobs/"+layer.Digest, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return a.statusError(ref, "blob "+layer.Digest, resp.StatusCode)
+	}
+
+	if !unpack {
+		if !filepath.IsLocal(filepath.FromSlash(title)) {
+			return fmt.Errorf("invalid file name %s", title)
+		}
+		target := filepath.Join(targetDir, filepath.FromSlash(title))
+		if err := os.MkdirAll(filepath.Dir(target), dirPerms); err != nil {
+			return fmt.Errorf("failed to create directory for file %s: %w", target, err)
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to create file %s: %w", target, err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		return downloadBlob(file, resp.Body, layer.Digest)
+	}
+
+	tmpFile, err := os.CreateTemp("", "skills-pkg-oci-*.tgz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+	if err := downloadBlob(tmpFile, resp.Body, layer.Digest); err != nil {
+		return err
+	}
+
+	if hardened {
+		return extractArchiveSandboxed(ctx, tmpFile.Name(), targetDir, "")
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read blob: %w", err)
+	}
+	return extractTarGzEntries(tmpFile, targetDir, defaultExtractionLimits)
+}
+
+// downloadBlob copies the blob in body to w, checking it against digest.
+func downloadBlob(w io.Writer, body io.Reader, digest string) error {
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), body); err != nil {
+		return fmt.Errorf("%w: failed to download blob: %w", domain.ErrNetworkFailure, err)
+	}
+	if got := "sha256:" + hex.EncodeToString(hash.Sum(nil)); got != digest {
+		return fmt.Errorf("integrity check failed: the downloaded blob has digest %s", got)
+	}
+	return nil
+}
+
+// listTags returns every tag of the repository, following the pages of the tag list.
+func (a *OCI) listTags(ctx context.Context, ref *ociReference) ([]string, error) {
+	var tags []string
+	path := "/tags/list"
+	for path != "" {
+		resp, err := a.get(ctx, ref, path, "application/json")
+		if err != nil {
+			return nil, err
+		}
+
+		page, next, err := a.readTags(ref, resp)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page...)
+		path = next
+	}
+	return tags, nil
+}
+
+// readTags reads a page of the tag list and returns the path of the next page, if any.
+func (a *OCI) readTags(ref *ociReference, resp *http.Response) ([]string, string, error) {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", fmt.Errorf("%w: %w: repository %s/%s does not exist. Please verify the URL is correct", domain.ErrNetworkFailure, domain.ErrSourceNotFound, ref.host, ref.repository)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", a.statusError(ref, "tags", resp.StatusCode)
+	}
+
+	var list struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&list); err != nil {
+		return nil, "", fmt.Errorf("failed to parse the tags of %s/%s: %w", ref.host, ref.repository, err)
+	}
+
+	// The next page is linked as </v2/<repository>/tags/list?n=100&last=tag>; rel="next"
+	next := ""
+	if link := resp.Header.Get("Link"); strings.Contains(link, `rel="next"`) {
+		if start, end := strings.Index(link, "<"), strings.Index(link, ">"); start >= 0 && end > start {
+			if u, err := url.Parse(link[start+1 : end]); err == nil {
+				prefix := "/v2/" + ref.repository
+				if rest, ok := strings.CutPrefix(u.Path, prefix); ok {
+					next = rest
+					if u.RawQuery != "" {
+						next += "?" + u.RawQuery
+					}
+				}
+			}
+		}
+	}
+	return list.Tags, next, nil
+}
+
+// get sends a GET request for path under the repository, authenticating as the registry asks
+// with a 401 response: with a bearer token from its token service, or with basic credentials.
+func (a *OCI) get(ctx context.Context, ref *ociReference, path, accept string) (*http.Response, error) {
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	return a.do(ctx, ref, http.MethodGet, fmt.Sprintf("%s://%s/v2/%s%s", ref.scheme, ref.host, ref.repository, path), header, nil)
+}
+
+// do sends a request to rawURL in the registry of the repository, authenticating as get does.
+// Requests other than GET and HEAD push to the repository, so they are sent with a token that
+// allows pushing, kept apart from the tokens for pulling.
+func (a *OCI) do(ctx context.Context, ref *ociReference, method, rawURL string, header http.Header, body []byte) (*http.Response, error) {
+	tokenKey := ref.host + "/" + ref.repository
+	push := method != http.MethodGet && method != http.MethodHead
+	action := "pull from"
+	if push {
+		tokenKey += ":push"
+		action = "push to"
+	}
+
+	send := func(authorization string) (*http.Response, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+				return nil, fmt.Errorf("failed to %s %s/%s: %w", action, ref.host, ref.repository, e)
+			}
+			return nil, fmt.Errorf("%w: failed to %s %s/%s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, action, ref.host, ref.repository)
+		}
+		return resp, nil
+	}
+
+	a.tokensMu.Lock()
+	token := a.tokens[tokenKey]
+	a.tokensMu.Unlock()
+	authorization := ""
+	if token != "" {
+		authorization = "Bearer " + token
+	}
+
+	resp, err := send(authorization)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	_ = resp.Body.Close()
+
+	username, password := ociCredentials(ref.host)
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		if push {
+			params["scope"] = "repository:" + ref.repository + ":pull,push"
+		}
+		token, err := a.fetchToken(ctx, ref, params, username, password)
+		if err != nil {
+			return nil, err
+		}
+		a.tokensMu.Lock()
+		a.tokens[tokenKey] = token
+		a.tokensMu.Unlock()
+		return send("Bearer " + token)
+	case "basic":
+		if username == "" {
+			return nil, a.statusError(ref, "repository", http.StatusUnauthorized)
+		}
+		return send("Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	}
+	return nil, a.statusError(ref, "repository", http.StatusUnauthorized)
+}
+
+// fetchToken requests a bearer token for the scope the registry asks for, or for pulling the repository,
+// from the token service of the registry, with the credentials when there are any.
+func (a *OCI) fetchToken(ctx context.Context, ref *ociReference, params map[string]string, username, password string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || (realm.Scheme != "https" && realm.Scheme != "http") {
+		return "", fmt.Errorf("%w: registry %s asks for a token from an invalid service %q", domain.ErrNetworkFailure, ref.host, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+			return "", fmt.Errorf("failed to authenticate to %s: %w", ref.host, e)
+		}
+		return "", fmt.Errorf("%w: failed to authenticate to %s: network error. Please check your internet connection and try again", domain.ErrNetworkFailure, ref.host)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", a.statusError(ref, "token", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse the token of %s: %w", ref.host, err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("%w: the token service of %s returned no token", domain.ErrNetworkFailure, ref.host)
+}
+
+// statusError returns the error of an unexpected HTTP status of a request for what in the repository.
+func (a *OCI) statusError(ref *ociReference, what string, status int) error {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return fmt.Errorf("%w: %w: registry %s refused access to %s/%s. Log in with 'docker login %s' or 'oras login %s'", domain.ErrNetworkFailure, domain.ErrAuthenticationRequired, ref.host, ref.host, ref.repository, ref.host, ref.host)
+	}
+	return fmt.Errorf("%w: failed to fetch %s of %s/%s: HTTP status %d", domain.ErrNetworkFailure, what, ref.host, ref.repository, status)
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:org/skill:pull".
+func parseAuthChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return scheme, params
+}
+
+// ociCredentials returns the credentials of the registry host stored in the Docker configuration file,
+// $DOCKER_CONFIG/config.json or ~/.docker/config.json, or empty strings when there are none.
+// Credentials kept by credential helpers are not read.
+func ociCredentials(host string) (string, string) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", ""
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return "", ""
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", ""
+	}
+
+	// Docker Hub credentials are stored under the URL of its index
+	keys := []string{host, "https://" + host, "http://" + host}
+	if host == "registry-1.docker.io" {
+		keys = append(keys, "https://index.docker.io/v1/", "docker.io", "index.docker.io")
+	}
+	for _, key := range keys {
+		entry, ok := config.Auths[key]
+		if !ok || entry.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			continue
+		}
+		if username, password, found := strings.Cut(string(decoded), ":"); found {
+			return username, password
+		}
+	}
+	return "", ""
+}
+
+// createTempDir creates a new temporary directory for an artifact.
+// It uses the SKILLSPKG_TEMP_DIR environment variable if set, otherwise uses os.TempDir().
+func (a *OCI) createTempDir() (string, error) {
+	baseDir := os.Getenv("SKILLSPKG_TEMP_DIR")
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(baseDir, dirPerms); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(baseDir, "skills-pkg-oci-")
+}
+
+// PublishTarget returns "oci" to publish skills as OCI artifacts, which the oci source installs.
+func (a *OCI) PublishTarget() string {
+	return "oci"
+}
+
+// Publish pushes the artifact to the repository destination, such as ghcr.io/org/skill, tagged with
+// its version. The archive is the layer, annotated as ORAS annotates directories, so that the oci
+// source installs it like any artifact pushed with 'oras push', and the manifest of the skill is the config.
+// Credentials are read from the Docker configuration file, as for pulling.
+func (a *OCI) Publish(ctx context.Context, artifact *port.PublishArtifact, destination string) (*port.PublishResult, error) {
+	if destination == "" {
+		return nil, errors.New("an OCI repository to publish to is required, such as ghcr.io/org/skill")
+	}
+	ref, err := a.parseSource(&port.Source{Type: "oci", URL: destination})
+	if err != nil {
+		return nil, err
+	}
+	if !ociTagPattern.MatchString(artifact.Version) {
+		return nil, fmt.Errorf("version %q is not a valid OCI tag: tags are letters, digits, '_', '.', and '-', up to 128 characters", artifact.Version)
+	}
+
+	layer, err := os.ReadFile(artifact.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", artifact.Archive, err)
+	}
+	config, err := os.ReadFile(artifact.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", artifact.Manifest, err)
+	}
+	layerDescriptor := ociDescriptor{
+		MediaType: ociLayerMediaType,
+		Digest:    ociBlobDigest(layer),
+		Size:      int64(len(layer)),
+		Annotations: map[string]string{
+			ociTitleAnnotation:  artifact.Name,
+			ociUnpackAnnotation: "true",
+		},
+	}
+	configDescriptor := ociDescriptor{
+		MediaType: ociSkillConfigMediaType,
+		Digest:    ociBlobDigest(config),
+		Size:      int64(len(config)),
+	}
+	if err := a.pushBlob(ctx, ref, configDescriptor.Digest, config); err != nil {
+		return nil, err
+	}
+	if err := a.pushBlob(ctx, ref, layerDescriptor.Digest, layer); err != nil {
+		return nil, err
+	}
+
+	manifest, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     ociManifestMediaType,
+		"config":        configDescriptor,
+		"layers":        []ociDescriptor{layerDescriptor},
+		"annotations": map[string]string{
+			ociTitleAnnotation:       artifact.Name,
+			ociDescriptionAnnotation: artifact.Description,
+			ociVersionAnnotation:     artifact.Version,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OCI manifest: %w", err)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", ociManifestMediaType)
+	resp, err := a.do(ctx, ref, http.MethodPut, fmt.Sprintf("%s://%s/v2/%s/manifests/%s", ref.scheme, ref.host, ref.repository, artifact.Version), header, manifest)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, a.pushStatusError(ref, "manifest "+artifact.Version, resp.StatusCode)
+	}
+
+	return &port.PublishResult{
+		Location: fmt.Sprintf("%s/%s:%s@%s", ref.host, ref.repository, artifact.Version, ociBlobDigest(manifest)),
+		Source:   &port.Source{Type: "oci", URL: destination},
+		Version:  artifact.Version,
+		SubDir:   ".",
+	}, nil
+}
+
+// pushBlob uploads data to the repository as the blob with digest in a single request,
+// unless the registry already has it.
+func (a *OCI) pushBlob(ctx context.Context, ref *ociReference, digest string, data []byte) error {
+	base := fmt.Sprintf("%s://%s/v2/%s/blobs/", ref.scheme, ref.host, ref.repository)
+	resp, err := a.do(ctx, ref, http.MethodHead, base+digest, nil, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = a.do(ctx, ref, http.MethodPost, base+"uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return a.pushStatusError(ref, "blob "+digest, resp.StatusCode)
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("%w: registry %s did not return where to upload blob %s", domain.ErrNetworkFailure, ref.host, digest)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+	resp, err = a.do(ctx, ref, http.MethodPut, location.String(), header, data)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return a.pushStatusError(ref, "blob "+digest, resp.StatusCode)
+	}
+	return nil
+}
+
+// pushStatusError returns the error of an unexpected HTTP status of a request pushing what to the repository.
+func (a *OCI) pushStatusError(ref *ociReference, what string, status int) error {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return a.statusError(ref, what, status)
+	}
+	return fmt.Errorf("%w: failed to push %s to %s/%s: HTTP status %d", domain.ErrNetworkFailure, what, ref.host, ref.repository, status)
+}
+
+// ociBlobDigest returns the sha256 digest that data is addressed by.
+func ociBlobDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
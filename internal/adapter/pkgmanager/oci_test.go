@@ -0,0 +1,330 @@
+package pkgmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// testOCIRegistry is a registry serving the repository org/skill with the tags of manifests,
+// which requires a bearer token from its token service.
+type testOCIRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte // Manifests by tag
+	blobs     map[string][]byte // Blobs by digest
+	digests   map[string]string // Docker-Content-Digest reported for a tag, overriding the real digest
+	tags      []string
+	uploads   int // Number of blobs uploaded
+}
+
+func ociDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newTestOCIRegistry(t *testing.T) *testOCIRegistry {
+	t.Helper()
+
+	r := &testOCIRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}, digests: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("scope") {
+		case "repository:org/skill:pull":
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "pull-token"})
+		case "repository:org/skill:pull,push":
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "push-token"})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("/v2/org/skill/", func(w http.ResponseWriter, req *http.Request) {
+		authorization := req.Header.Get("Authorization")
+		pull := req.Method == http.MethodGet || req.Method == http.MethodHead
+		if authorization != "Bearer push-token" && (!pull || authorization != "Bearer pull-token") {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+r.server.URL+`/token",service="test",scope="repository:org/skill:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		rest := strings.TrimPrefix(req.URL.Path, "/v2/org/skill/")
+		switch {
+		case req.Method == http.MethodPost && rest == "blobs/uploads/":
+			w.Header().Set("Location", "/v2/org/skill/blobs/uploads/session?state=1")
+			w.WriteHeader(http.StatusAccepted)
+		case req.Method == http.MethodPut && rest == "blobs/uploads/session":
+			body, _ := io.ReadAll(req.Body)
+			digest := req.URL.Query().Get("digest")
+			if req.URL.Query().Get("state") != "1" || ociDigest(body) != digest {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			r.blobs[digest] = body
+			r.uploads++
+			w.WriteHeader(http.StatusCreated)
+		case req.Method == http.MethodPut && strings.HasPrefix(rest, "manifests/"):
+			body, _ := io.ReadAll(req.Body)
+			if req.Header.Get("Content-Type") != ociManifestMediaType {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			tag := strings.TrimPrefix(rest, "manifests/")
+			r.manifests[tag] = body
+			r.tags = append(r.tags, tag)
+			w.WriteHeader(http.StatusCreated)
+		case rest == "tags/list":
+			// Serve the tags in pages of two
+			start := 0
+			if last := req.URL.Query().Get("last"); last != "" {
+				for i, tag := range r.tags {
+					if tag == last {
+						start = i + 1
+					}
+				}
+			}
+			end := min(start+2, len(r.tags))
+			if end < len(r.tags) {
+				w.Header().Set("Link", `</v2/org/skill/tags/list?n=2&last=`+r.tags[end-1]+`>; rel="next"`)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "org/skill", "tags": r.tags[start:end]})
+		case strings.HasPrefix(rest, "manifests/"):
+			reference := strings.TrimPrefix(rest, "manifests/")
+			manifest, ok := r.manifests[reference]
+			if !ok {
+				for _, m := range r.manifests {
+					if ociDigest(m) == reference {
+						manifest, ok = m, true
+					}
+				}
+			}
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			digest := ociDigest(manifest)
+			if reported, ok := r.digests[reference]; ok {
+				digest = reported
+			}
+			w.Header().Set("Content-Type", ociManifestMediaType)
+			w.Header().Set("Docker-Content-Digest", digest)
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(rest, "blobs/"):
+			blob, ok := r.blobs[strings.TrimPrefix(rest, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	r.server = httptest.NewServer(mux)
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// push stores an artifact with the layers under tag, as ORAS pushes a directory and a file.
+func (r *testOCIRegistry) push(t *testing.T, tag string, layers ...ociDescriptor) {
+	t.Helper()
+	manifest, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     ociManifestMediaType,
+		"layers":        layers,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.manifests[tag] = manifest
+	r.tags = append(r.tags, tag)
+}
+
+// layer stores blob and returns its descriptor with the annotations.
+func (r *testOCIRegistry) layer(mediaType string, blob []byte, annotations map[string]string) ociDescriptor {
+	digest := ociDigest(blob)
+	r.blobs[digest] = blob
+	return ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(blob)), Annotations: annotations}
+}
+
+func (r *testOCIRegistry) source() *port.Source {
+	return &port.Source{Type: "oci", URL: strings.TrimPrefix(r.server.URL, "http://") + "/org/skill"}
+}
+
+func TestOCI_Download(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	registry := newTestOCIRegistry(t)
+	directory := registry.layer("application/vnd.oci.image.layer.v1.tar+gzip", writeTestTarball(t, map[string]string{
+		"skill/SKILL.md":         "# Skill\n",
+		"skill/scripts/setup.sh": "echo setup\n",
+	}), map[string]string{ociTitleAnnotation: "skill", ociUnpackAnnotation: "true"})
+	file := registry.layer("text/markdown", []byte("# Notes\n"), map[string]string{ociTitleAnnotation: "NOTES.md"})
+	registry.push(t, "1.0.0", directory, file)
+
+	a := NewOCI()
+	result, err := a.Download(context.Background(), registry.source(), "1.0.0")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(result.Path) }()
+
+	// The tag is resolved to the digest of its manifest, which keeps pointing to the same content
+	digest := ociDigest(registry.manifests["1.0.0"])
+	if result.Version != digest {
+		t.Errorf("Download() version = %s, want %s", result.Version, digest)
+	}
+	for name, want := range map[string]string{"SKILL.md": "# Skill\n", "scripts/setup.sh": "echo setup\n", "NOTES.md": "# Notes\n"} {
+		got, err := os.ReadFile(filepath.Join(result.Path, name))
+		if err != nil || string(got) != want {
+			t.Errorf("downloaded %s = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	// The artifact can be pulled by the digest of its manifest too
+	byDigest, err := a.Download(context.Background(), registry.source(), digest)
+	if err != nil {
+		t.Fatalf("Download() by digest error = %v", err)
+	}
+	_ = os.RemoveAll(byDigest.Path)
+	if byDigest.Version != digest {
+		t.Errorf("Download() by digest version = %s, want %s", byDigest.Version, digest)
+	}
+
+	if _, err := a.Download(context.Background(), registry.source(), "9.9.9"); !errors.Is(err, domain.ErrSourceNotFound) {
+		t.Errorf("Download() of a missing tag error = %v, want ErrSourceNotFound", err)
+	}
+}
+
+func TestOCI_Download_IntegrityErrors(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	registry := newTestOCIRegistry(t)
+	file := registry.layer("text/markdown", []byte("# Skill\n"), map[string]string{ociTitleAnnotation: "SKILL.md"})
+	registry.push(t, "reported", file)
+	registry.digests["reported"] = ociDigest([]byte("other manifest"))
+
+	tampered := file
+	tampered.Digest = ociDigest([]byte("# Original skill\n"))
+	registry.blobs[tampered.Digest] = []byte("# Tampered skill\n")
+	registry.push(t, "tampered", tampered)
+
+	a := NewOCI()
+	for _, version := range []string{"reported", "tampered"} {
+		if _, err := a.Download(context.Background(), registry.source(), version); err == nil || !strings.Contains(err.Error(), "integrity check failed") {
+			t.Errorf("Download(%s) error = %v, want an integrity check failure", version, err)
+		}
+	}
+}
+
+func TestOCI_GetLatestVersion(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	registry := newTestOCIRegistry(t)
+	registry.tags = []string{"latest", "1.0.0", "v1.2.0", "1.10.0-rc.1", "main"}
+
+	version, err := NewOCI().GetLatestVersion(context.Background(), registry.source())
+	if err != nil {
+		t.Fatalf("GetLatestVersion() error = %v", err)
+	}
+	if version != "v1.2.0" {
+		t.Errorf("GetLatestVersion() = %s, want v1.2.0", version)
+	}
+}
+
+func TestOCI_Publish(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	registry := newTestOCIRegistry(t)
+	dir := t.TempDir()
+	artifact := &port.PublishArtifact{
+		Name:     "code-review",
+		Version:  "v1.0.0",
+		Archive:  filepath.Join(dir, "code-review-v1.0.0.tar.gz"),
+		Manifest: filepath.Join(dir, "code-review-v1.0.0.json"),
+	}
+	if err := os.WriteFile(artifact.Archive, writeTestTarball(t, map[string]string{"code-review/SKILL.md": "# Code review\n"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(artifact.Manifest, []byte(`{"name":"code-review"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewOCI()
+	result, err := a.Publish(context.Background(), artifact, registry.source().URL)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if result.Source.Type != "oci" || result.Source.URL != registry.source().URL || result.Version != "v1.0.0" || result.SubDir != "." {
+		t.Errorf("Publish() = %+v, source %+v", result, result.Source)
+	}
+
+	// The published artifact installs like one pushed with ORAS
+	download, err := a.Download(context.Background(), result.Source, result.Version)
+	if err != nil {
+		t.Fatalf("Download() of the published artifact error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(download.Path) }()
+	if got, err := os.ReadFile(filepath.Join(download.Path, "SKILL.md")); err != nil || string(got) != "# Code review\n" {
+		t.Errorf("downloaded SKILL.md = %q, %v", got, err)
+	}
+
+	// Blobs the registry has are not uploaded again
+	artifact.Version = "v1.0.1"
+	if _, err := a.Publish(context.Background(), artifact, registry.source().URL); err != nil {
+		t.Fatalf("Publish() of the same files error = %v", err)
+	}
+	if registry.uploads != 2 {
+		t.Errorf("%d blobs were uploaded, want only the config and the layer of the first version", registry.uploads)
+	}
+
+	artifact.Version = "not+a+tag"
+	if _, err := a.Publish(context.Background(), artifact, registry.source().URL); err == nil || !strings.Contains(err.Error(), "not a valid OCI tag") {
+		t.Errorf("Publish() of an invalid tag error = %v", err)
+	}
+	if _, err := a.Publish(context.Background(), artifact, ""); err == nil {
+		t.Error("Publish() without a repository succeeded")
+	}
+}
+
+func TestOCI_ParseSource(t *testing.T) {
+	tests := []struct {
+		url        string
+		host       string
+		repository string
+		scheme     string
+		wantErr    bool
+	}{
+		{url: "ghcr.io/org/skill", host: "ghcr.io", repository: "org/skill", scheme: "https"},
+		{url: "oci://registry.example.com:5000/team/skills/review", host: "registry.example.com:5000", repository: "team/skills/review", scheme: "https"},
+		{url: "localhost:5000/skill", host: "localhost:5000", repository: "skill", scheme: "http"},
+		{url: "org/skill", host: "registry-1.docker.io", repository: "org/skill", scheme: "https"},
+		{url: "skill", host: "registry-1.docker.io", repository: "library/skill", scheme: "https"},
+		{url: "docker.io/org/skill", host: "registry-1.docker.io", repository: "org/skill", scheme: "https"},
+		{url: "ghcr.io/org/skill:1.0.0", wantErr: true},
+		{url: "ghcr.io/org/skill@sha256:abc", wantErr: true},
+		{url: "ghcr.io/Org/Skill", wantErr: true},
+	}
+	for _, tt := range tests {
+		ref, err := NewOCI().parseSource(&port.Source{Type: "oci", URL: tt.url})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSource(%s) succeeded, want an error", tt.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSource(%s) error = %v", tt.url, err)
+			continue
+		}
+		if ref.host != tt.host || ref.repository != tt.repository || ref.scheme != tt.scheme {
+			t.Errorf("parseSource(%s) = %+v, want %s %s %s", tt.url, ref, tt.scheme, tt.host, tt.repository)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package pkgmanager
+
+import "github.com/mazrean/skills-pkg/internal/port"
+
+// All returns an adapter instance for every supported source type.
+// Under sudo, the adapters download as the user who ran sudo.
+func All() []port.PackageManager {
+	return []port.PackageManager{
+		Unprivileged(NewGit()),
+		Unprivileged(NewGoMod()),
+		Unprivileged(NewNpm()),
+		Unprivileged(NewOCI()),
+		Unprivileged(NewArchive()),
+		NewLocal(),
+	}
+}
+
+// Publishers returns a publisher instance for every supported publish target.
+func Publishers() []port.Publisher {
+	return []port.Publisher{
+		NewGit(),
+		NewGitHubRelease(),
+		NewOCI(),
+	}
+}
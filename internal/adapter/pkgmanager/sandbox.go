@@ -0,0 +1,152 @@
+package pkgmanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// extractionSandboxEnv marks a process started by extractZipSandboxed.
+const extractionSandboxEnv = "SKILLSPKG_EXTRACTION_SANDBOX"
+
+// sandboxTimeout is how long the sandboxed extraction may run before it is killed.
+const sandboxTimeout = 2 * time.Minute
+
+// defaultExtractionLimits bound what every extraction may write, so that archives that expand to
+// exhaust the disk are rejected even without the sandbox.
+var defaultExtractionLimits = &extractionLimits{
+	maxEntries:   100000,
+	maxBytes:     2 << 30,
+	maxFileBytes: 1 << 30,
+}
+
+// sandboxLimits tighten the default limits for a sandboxed extraction.
+// The size of each file matches the file size limit of the sandbox process.
+var sandboxLimits = &extractionLimits{
+	maxEntries:   10000,
+	maxBytes:     512 << 20,
+	maxFileBytes: 256 << 20,
+}
+
+// extractionLimits bound the number of entries, the total size, and the size of each file extracted from an archive.
+type extractionLimits struct {
+	maxEntries   int
+	maxBytes     int64
+	maxFileBytes int64
+}
+
+// IsExtractionSandbox reports whether the process was started to extract an archive in a sandbox.
+// The main function must call RunExtractionSandbox instead of running a command when it returns true.
+func IsExtractionSandbox() bool {
+	return os.Getenv(extractionSandboxEnv) == "1"
+}
+
+// RunExtractionSandbox extracts the zip file or gzipped tar file on standard input into the directory given
+// as the first argument, stripping the prefix given as the second argument from zip entries and the top-level
+// directory from tar entries, and returns the exit code of the process. A "*" prefix strips the top-level
+// directory that contains every entry of either kind of archive, if there is one.
+func RunExtractionSandbox() int {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: <target-dir> <prefix> < archive")
+		return 2
+	}
+
+	if err := runExtractionSandbox(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runExtractionSandbox confines the process before the archive is parsed,
+// so that exploits of the archive parsers are contained to the target directory.
+func runExtractionSandbox(targetDir, prefix string) error {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return errors.New("archive must be passed as a regular file on standard input")
+	}
+
+	debug.SetMemoryLimit(256 << 20)
+	if err := setSandboxLimits(); err != nil {
+		return fmt.Errorf("failed to set resource limits: %w", err)
+	}
+	dir, err := confine(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to confine the sandbox to %s: %w", targetDir, err)
+	}
+
+	if prefix == commonPrefix {
+		return extractArchiveEntries(os.Stdin, info.Size(), dir, sandboxLimits)
+	}
+
+	// Gzipped tar files start with the gzip magic number, which zip files never do
+	magic := make([]byte, 2)
+	if _, err := os.Stdin.ReadAt(magic, 0); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		return extractTarGzEntries(os.Stdin, dir, sandboxLimits)
+	}
+
+	r, err := zip.NewReader(os.Stdin, info.Size())
+	if err != nil {
+		return fmt.Errorf("failed to open zip file: %w", err)
+	}
+	return extractZipEntries(r, dir, prefix, sandboxLimits)
+}
+
+// extractArchiveSandboxed extracts the zip file or gzipped tar file in a child process that runs this executable again.
+// The child has an empty environment and resource limits. On Linux it also has no network,
+// and sees nothing of the filesystem but the target directory.
+func extractArchiveSandboxed(ctx context.Context, archivePath, targetDir, prefix string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the executable for the extraction sandbox: %w", err)
+	}
+	targetDir, err = filepath.Abs(targetDir)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer func() {
+		_ = archive.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, sandboxTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, exe, targetDir, prefix)
+	cmd.Env = []string{extractionSandboxEnv + "=1"}
+	cmd.Dir = targetDir
+	cmd.Stdin = archive
+	cmd.Stderr = &stderr
+	cmd.SysProcAttr = sandboxSysProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the extraction sandbox: %w. %s", err, sandboxRequirement)
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("sandboxed extraction did not finish within %s", sandboxTimeout)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("sandboxed extraction failed: %s", message)
+		}
+		return fmt.Errorf("sandboxed extraction failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package pkgmanager
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxRequirement explains what the extraction sandbox needs from the system.
+const sandboxRequirement = "hardened_extraction requires unprivileged user namespaces"
+
+// errSandboxCgo is returned by confine in binaries that use cgo, whose threads cannot all be confined.
+var errSandboxCgo = errors.New("the extraction sandbox cannot drop the capabilities of every thread in a skills-pkg built with cgo; build it with CGO_ENABLED=0")
+
+// sandboxSysProcAttr starts the child in new user, mount, network, IPC, and UTS namespaces.
+// The current user is mapped to root in the user namespace, so that the child can chroot
+// into the target directory, and files it creates belong to the current user outside.
+func sandboxSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+		Pdeathsig:   syscall.SIGKILL,
+	}
+}
+
+// confine changes the root directory of the process to dir and drops all capabilities,
+// so that the chroot cannot be escaped. It returns the path of dir inside the new root.
+func confine(dir string) (string, error) {
+	if err := unix.Chroot(dir); err != nil {
+		return "", err
+	}
+	if err := unix.Chdir("/"); err != nil {
+		return "", err
+	}
+	// No new privileges and capabilities are per thread, so they are changed on every thread of the
+	// runtime at once; a thread left with its capabilities could chroot again to escape
+	if _, _, errno := syscall.AllThreadsSyscall(unix.SYS_PRCTL, unix.PR_SET_NO_NEW_PRIVS, 1, 0); errno != 0 {
+		if errno == syscall.ENOTSUP {
+			return "", errSandboxCgo
+		}
+		return "", errno
+	}
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	if _, _, errno := syscall.AllThreadsSyscall(unix.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return "", errno
+	}
+
+	return "/", nil
+}
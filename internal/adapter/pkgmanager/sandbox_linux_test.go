@@ -0,0 +1,88 @@
+package pkgmanager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// confineProbeEnv makes the test binary confine itself to the directory in the variable
+// and print the effective capabilities of each of its threads.
+const confineProbeEnv = "SKILLSPKG_TEST_CONFINE_PROBE"
+
+func init() {
+	if dir := os.Getenv(confineProbeEnv); dir != "" {
+		os.Exit(runConfineProbe(dir))
+	}
+}
+
+// runConfineProbe prints the CapEff line of /proc/self/task/*/status of each thread after confine,
+// reading them through the directory opened before the root directory changed.
+func runConfineProbe(dir string) int {
+	tasks, err := os.Open("/proc/self/task")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if _, err := confine(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	tids, err := tasks.Readdirnames(-1)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, tid := range tids {
+		fd, err := unix.Openat(int(tasks.Fd()), tid+"/status", unix.O_RDONLY, 0)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		status := os.NewFile(uintptr(fd), tid)
+		scanner := bufio.NewScanner(status)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "CapEff:") {
+				fmt.Println(tid, scanner.Text())
+			}
+		}
+		_ = status.Close()
+	}
+	return 0
+}
+
+// TestConfine_DropsCapabilitiesOfEveryThread tests that no thread of the confined process keeps
+// the capabilities it has in its user namespace, with which it could escape the chroot.
+func TestConfine_DropsCapabilitiesOfEveryThread(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = []string{confineProbeEnv + "=" + t.TempDir()}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.SysProcAttr = sandboxSysProcAttr()
+
+	if err := cmd.Run(); err != nil {
+		if cmd.ProcessState == nil {
+			t.Skipf("sandbox is not available: %v", err)
+		}
+		skipWithoutSandbox(t, fmt.Errorf("%s", stderr.String()))
+		t.Fatalf("confine probe failed: %v: %s", err, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("probe reported %d threads, want the threads of the Go runtime: %q", len(lines), stdout.String())
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, "CapEff:\t0000000000000000") {
+			t.Errorf("thread kept capabilities after confine: %s", line)
+		}
+	}
+}
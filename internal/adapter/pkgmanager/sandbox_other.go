@@ -0,0 +1,18 @@
+//go:build !linux
+
+package pkgmanager
+
+import "syscall"
+
+// sandboxRequirement explains what the extraction sandbox needs from the system.
+const sandboxRequirement = "hardened_extraction requires the skills-pkg executable to be runnable"
+
+// sandboxSysProcAttr returns no process attributes: namespaces are only available on Linux.
+func sandboxSysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// confine leaves the filesystem view of the process unchanged: chroot requires privileges outside Linux user namespaces.
+func confine(dir string) (string, error) {
+	return dir, nil
+}
@@ -0,0 +1,143 @@
+package pkgmanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain lets the test binary act as the extraction sandbox, as the skills-pkg executable does.
+func TestMain(m *testing.M) {
+	if IsExtractionSandbox() {
+		os.Exit(RunExtractionSandbox())
+	}
+	if IsDownloadHelper() {
+		os.Exit(RunDownloadHelper())
+	}
+	os.Exit(m.Run())
+}
+
+// skipWithoutSandbox skips the test when err shows that the extraction sandbox cannot run here:
+// user namespaces are unavailable, or the test binary uses cgo, as -race does.
+func skipWithoutSandbox(t *testing.T, err error) {
+	t.Helper()
+	if strings.Contains(err.Error(), "failed to start the extraction sandbox") || strings.Contains(err.Error(), "built with cgo") {
+		t.Skipf("sandbox is not available: %v", err)
+	}
+}
+
+// writeTestZip writes a zip file with the given entries and returns its path.
+func writeTestZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write zip entry: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip writer: %v", err)
+	}
+
+	zipPath := filepath.Join(t.TempDir(), "module.zip")
+	if err := os.WriteFile(zipPath, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("Failed to write zip file: %v", err)
+	}
+	return zipPath
+}
+
+// TestExtractZipSandboxed tests extraction in the sandboxed child process
+func TestExtractZipSandboxed(t *testing.T) {
+	prefix := "example.com/skill@v1.0.0/"
+
+	t.Run("success: extracts files under the prefix", func(t *testing.T) {
+		zipPath := writeTestZip(t, map[string]string{
+			prefix + "SKILL.md":        "# Skill\n",
+			prefix + "scripts/run.sh":  "echo hello\n",
+			"other@v1.0.0/ignored.txt": "ignored",
+		})
+		targetDir := t.TempDir()
+
+		if err := extractArchiveSandboxed(context.Background(), zipPath, targetDir, prefix); err != nil {
+			skipWithoutSandbox(t, err)
+			t.Fatalf("extractArchiveSandboxed() error = %v", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(targetDir, "scripts", "run.sh"))
+		if err != nil || string(data) != "echo hello\n" {
+			t.Errorf("scripts/run.sh = %q, %v", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(targetDir, "ignored.txt")); !os.IsNotExist(err) {
+			t.Errorf("files outside the prefix should not be extracted: %v", err)
+		}
+	})
+
+	t.Run("error: reports failures of the child", func(t *testing.T) {
+		zipPath := filepath.Join(t.TempDir(), "broken.zip")
+		if err := os.WriteFile(zipPath, []byte("not a zip file"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		err := extractArchiveSandboxed(context.Background(), zipPath, t.TempDir(), prefix)
+		if err == nil {
+			t.Fatal("extractArchiveSandboxed() should fail for a broken zip file")
+		}
+		skipWithoutSandbox(t, err)
+		if !strings.Contains(err.Error(), "failed to open zip file") {
+			t.Errorf("error should contain the message of the child, got %v", err)
+		}
+	})
+}
+
+// TestExtractZipEntries_Limits tests that archives beyond the extraction limits are rejected
+func TestExtractZipEntries_Limits(t *testing.T) {
+	prefix := "example.com/skill@v1.0.0/"
+	zipPath := writeTestZip(t, map[string]string{
+		prefix + "a.txt": strings.Repeat("a", 100),
+		prefix + "b.txt": strings.Repeat("b", 100),
+	})
+
+	tests := []struct {
+		limits  *extractionLimits
+		name    string
+		wantErr string
+	}{
+		{name: "within limits", limits: &extractionLimits{maxEntries: 2, maxBytes: 200, maxFileBytes: 100}},
+		{name: "too many entries", limits: &extractionLimits{maxEntries: 1, maxBytes: 200, maxFileBytes: 100}, wantErr: "more than 1 entries"},
+		{name: "too large", limits: &extractionLimits{maxEntries: 2, maxBytes: 150, maxFileBytes: 100}, wantErr: "more than 150 bytes"},
+		{name: "file too large", limits: &extractionLimits{maxEntries: 2, maxBytes: 200, maxFileBytes: 99}, wantErr: "file larger than 99 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := zip.OpenReader(zipPath)
+			if err != nil {
+				t.Fatalf("Failed to open zip file: %v", err)
+			}
+			defer func() {
+				_ = r.Close()
+			}()
+
+			err = extractZipEntries(&r.Reader, t.TempDir(), prefix, tt.limits)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("extractZipEntries() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("extractZipEntries() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
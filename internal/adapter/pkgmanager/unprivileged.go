@@ -0,0 +1,295 @@
+package pkgmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// downloadHelperEnv marks a process started by the unprivileged package manager to run one of its operations.
+const downloadHelperEnv = "SKILLSPKG_DOWNLOAD_HELPER"
+
+// Operations of the download helper.
+const (
+	helperDownload      = "download"
+	helperLatestVersion = "latest-version"
+	helperListVersions  = "list-versions"
+)
+
+// helperNetwork is the [network] settings the download helper applies, set by SetDownloadHelperNetwork.
+var helperNetwork atomic.Pointer[domain.NetworkSettings]
+
+// SetDownloadHelperNetwork records the [network] settings in effect, with the bandwidth limit and User-Agent
+// already resolved, so that download helpers started as the invoking user apply them too.
+func SetDownloadHelperNetwork(settings *domain.NetworkSettings) {
+	helperNetwork.Store(settings)
+}
+
+// helperRequest is the operation a download helper runs, read from its standard input.
+type helperRequest struct {
+	Network   *domain.NetworkSettings `json:"network,omitempty"`
+	Source    *port.Source            `json:"source"`
+	Operation string                  `json:"operation"`
+	Version   string                  `json:"version,omitempty"`
+}
+
+// helperResponse is the result of a download helper, written to its standard output.
+type helperResponse struct {
+	Result   *port.DownloadResult `json:"result,omitempty"`
+	Error    *helperError         `json:"error,omitempty"`
+	Latest   string               `json:"latest,omitempty"`
+	Versions []string             `json:"versions,omitempty"`
+}
+
+// helperError carries an error of the download helper back to the parent, with the
+// sentinel errors it wrapped, so that the parent classifies it as it would its own.
+type helperError struct {
+	HostNotAllowed *domain.ErrorHostNotAllowed `json:"host_not_allowed,omitempty"`
+	Message        string                      `json:"message"`
+	Network        bool                        `json:"network,omitempty"`
+	Authentication bool                        `json:"authentication,omitempty"`
+	NotFound       bool                        `json:"not_found,omitempty"`
+}
+
+func newHelperError(err error) *helperError {
+	e := &helperError{
+		Message:        err.Error(),
+		Network:        errors.Is(err, domain.ErrNetworkFailure),
+		Authentication: errors.Is(err, domain.ErrAuthenticationRequired),
+		NotFound:       errors.Is(err, domain.ErrSourceNotFound),
+	}
+	if hostErr, ok := errors.AsType[*domain.ErrorHostNotAllowed](err); ok {
+		e.HostNotAllowed = hostErr
+	}
+	return e
+}
+
+func (e *helperError) Error() string {
+	return e.Message
+}
+
+func (e *helperError) Unwrap() []error {
+	var errs []error
+	if e.Network {
+		errs = append(errs, domain.ErrNetworkFailure)
+	}
+	if e.Authentication {
+		errs = append(errs, domain.ErrAuthenticationRequired)
+	}
+	if e.NotFound {
+		errs = append(errs, domain.ErrSourceNotFound)
+	}
+	if e.HostNotAllowed != nil {
+		errs = append(errs, e.HostNotAllowed)
+	}
+	return errs
+}
+
+// IsDownloadHelper reports whether the process was started to download a skill as the user who ran sudo.
+// The main function must call RunDownloadHelper instead of running a command when it returns true.
+func IsDownloadHelper() bool {
+	return os.Getenv(downloadHelperEnv) == "1"
+}
+
+// RunDownloadHelper runs the package manager operation on standard input, writes its result to
+// standard output, and returns the exit code of the process.
+func RunDownloadHelper() int {
+	var req helperRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the download request: %v\n", err)
+		return 2
+	}
+
+	resp := runDownloadHelper(context.Background(), &req)
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write the download result: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runDownloadHelper(ctx context.Context, req *helperRequest) *helperResponse {
+	if req.Source == nil {
+		return &helperResponse{Error: &helperError{Message: "the download request has no source"}}
+	}
+	if err := applyHelperNetwork(req.Network); err != nil {
+		return &helperResponse{Error: newHelperError(err)}
+	}
+
+	var pm port.PackageManager
+	for _, candidate := range All() {
+		if candidate.SourceType() == req.Source.Type {
+			pm = candidate
+			break
+		}
+	}
+	if pm == nil {
+		return &helperResponse{Error: &helperError{Message: "unsupported source type: " + req.Source.Type}}
+	}
+
+	var resp helperResponse
+	var err error
+	switch req.Operation {
+	case helperDownload:
+		resp.Result, err = pm.Download(ctx, req.Source, req.Version)
+	case helperLatestVersion:
+		resp.Latest, err = pm.GetLatestVersion(ctx, req.Source)
+	case helperListVersions:
+		lister, ok := pm.(port.VersionLister)
+		if !ok {
+			err = fmt.Errorf("source type %s cannot list versions", req.Source.Type)
+			break
+		}
+		resp.Versions, err = lister.ListVersions(ctx, req.Source)
+	default:
+		err = fmt.Errorf("unknown download helper operation: %s", req.Operation)
+	}
+	if err != nil {
+		return &helperResponse{Error: newHelperError(err)}
+	}
+	return &resp
+}
+
+// applyHelperNetwork applies the [network] settings of the parent to the downloads of the helper.
+func applyHelperNetwork(settings *domain.NetworkSettings) error {
+	if settings == nil {
+		return nil
+	}
+	policy, err := settings.Policy()
+	if err != nil {
+		return err
+	}
+	network.SetPolicy(policy)
+	network.SetHeaders(settings.UserAgent, settings.Headers)
+	if settings.LimitRate != "" {
+		rate, err := domain.ParseByteRate(settings.LimitRate)
+		if err != nil {
+			return err
+		}
+		network.SetRateLimit(rate)
+	}
+	return nil
+}
+
+// helperCredential is the user and group the download helper runs as.
+type helperCredential struct {
+	uid, gid uint32
+}
+
+// unprivileged runs the operations of a package manager in a child process of this executable
+// that runs as the user who started skills-pkg through sudo, so that root does not parse
+// responses from the network, and downloaded files do not belong to root.
+type unprivileged struct {
+	pm         port.PackageManager
+	credential *helperCredential
+	executable string // This executable by default
+}
+
+// unprivilegedLister is an unprivileged package manager that can list versions.
+type unprivilegedLister struct {
+	*unprivileged
+}
+
+// Unprivileged returns pm itself, unless skills-pkg runs as root through sudo, in which case it returns
+// a package manager that downloads as the user who ran sudo. Local sources are always read by pm itself.
+func Unprivileged(pm port.PackageManager) port.PackageManager {
+	return newUnprivileged(pm, invokingUser())
+}
+
+func newUnprivileged(pm port.PackageManager, credential *helperCredential) port.PackageManager {
+	if credential == nil || pm.SourceType() == "local" {
+		return pm
+	}
+	u := &unprivileged{pm: pm, credential: credential}
+	if _, ok := pm.(port.VersionLister); ok {
+		return &unprivilegedLister{unprivileged: u}
+	}
+	return u
+}
+
+// SourceType returns the source type of the wrapped package manager.
+func (u *unprivileged) SourceType() string {
+	return u.pm.SourceType()
+}
+
+// Download downloads the skill as the invoking user.
+func (u *unprivileged) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	resp, err := u.run(ctx, &helperRequest{Operation: helperDownload, Source: source, Version: version})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, errors.New("the download helper returned no result")
+	}
+	return resp.Result, nil
+}
+
+// GetLatestVersion retrieves the latest version of the skill as the invoking user.
+func (u *unprivileged) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	resp, err := u.run(ctx, &helperRequest{Operation: helperLatestVersion, Source: source})
+	if err != nil {
+		return "", err
+	}
+	return resp.Latest, nil
+}
+
+// ListVersions lists the versions of the skill as the invoking user.
+func (u *unprivilegedLister) ListVersions(ctx context.Context, source *port.Source) ([]string, error) {
+	resp, err := u.run(ctx, &helperRequest{Operation: helperListVersions, Source: source})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Versions, nil
+}
+
+func (u *unprivileged) run(ctx context.Context, req *helperRequest) (*helperResponse, error) {
+	exe := u.executable
+	if exe == "" {
+		var err error
+		if exe, err = os.Executable(); err != nil {
+			return nil, fmt.Errorf("failed to locate the executable for downloading as the invoking user: %w", err)
+		}
+	}
+	req.Network = helperNetwork.Load()
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Env = append(os.Environ(), downloadHelperEnv+"=1")
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.SysProcAttr = u.credential.sysProcAttr()
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("failed to download as the invoking user: %s", message)
+		}
+		return nil, fmt.Errorf("failed to download as the invoking user: %w", err)
+	}
+
+	var resp helperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to read the result of the download helper: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &resp, nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package pkgmanager
+
+import "syscall"
+
+// invokingUser returns nil: privileges are only dropped on Unix systems, where sudo sets SUDO_UID.
+func invokingUser() *helperCredential {
+	return nil
+}
+
+// sysProcAttr returns no process attributes: invokingUser never returns a credential here.
+func (c *helperCredential) sysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package pkgmanager
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// invokingUser returns the user and group in SUDO_UID and SUDO_GID when the process runs as root,
+// or nil when it does not run through sudo, or sudo was run by root itself.
+func invokingUser() *helperCredential {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+	uid, err := strconv.ParseUint(os.Getenv("SUDO_UID"), 10, 32)
+	if err != nil || uid == 0 {
+		return nil
+	}
+	gid, err := strconv.ParseUint(os.Getenv("SUDO_GID"), 10, 32)
+	if err != nil {
+		return nil
+	}
+	return &helperCredential{uid: uint32(uid), gid: uint32(gid)}
+}
+
+// sysProcAttr starts the child as the user and group, without the supplementary groups of root.
+func (c *helperCredential) sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: c.uid, Gid: c.gid, Groups: []uint32{}},
+	}
+}
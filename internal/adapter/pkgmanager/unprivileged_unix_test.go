@@ -0,0 +1,129 @@
+//go:build unix
+
+package pkgmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestInvokingUser(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		gid  string
+		want *helperCredential
+	}{
+		{name: "sudo by a regular user", uid: "1000", gid: "1001", want: &helperCredential{uid: 1000, gid: 1001}},
+		{name: "not run through sudo", uid: "", gid: ""},
+		{name: "sudo by root", uid: "0", gid: "0"},
+		{name: "invalid group", uid: "1000", gid: "staff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SUDO_UID", tt.uid)
+			t.Setenv("SUDO_GID", tt.gid)
+
+			got := invokingUser()
+			if os.Geteuid() != 0 {
+				// Privileges are only dropped by root
+				if got != nil {
+					t.Errorf("invokingUser() without root = %+v, want nil", got)
+				}
+				return
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("invokingUser() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUnprivileged_Download downloads an archive through the helper running as nobody.
+func TestUnprivileged_Download(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("dropping privileges requires root")
+	}
+
+	tarball := writeTestTarball(t, map[string]string{"skill/SKILL.md": "# Skill\n"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/skill.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(tarball)
+	}))
+	defer server.Close()
+
+	// The helper is a copy of the test binary in a directory that nobody can reach, as installed executables are
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.MkdirTemp("", "skills-pkg-helper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	helper := filepath.Join(dir, "skills-pkg")
+	if err := os.WriteFile(helper, readTestFile(t, exe), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for parent := filepath.Dir(dir); parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+		if info, err := os.Stat(parent); err == nil && info.Mode().Perm()&0o001 == 0 {
+			t.Skipf("nobody cannot reach %s", dir)
+		}
+	}
+
+	const nobody = 65534
+	pm := newUnprivileged(NewArchive(), &helperCredential{uid: nobody, gid: nobody})
+	u, ok := pm.(*unprivileged)
+	if !ok {
+		t.Fatalf("newUnprivileged() = %T, want *unprivileged", pm)
+	}
+	u.executable = helper
+
+	ctx := context.Background()
+	result, err := pm.Download(ctx, &port.Source{Type: "archive", URL: server.URL + "/skill.tar.gz"}, "")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(result.Path) }()
+
+	info, err := os.Stat(filepath.Join(result.Path, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("Download() did not extract SKILL.md: %v", err)
+	}
+	if stat := info.Sys().(*syscall.Stat_t); stat.Uid != nobody || stat.Gid != nobody {
+		t.Errorf("SKILL.md is owned by %d:%d, want %d:%d", stat.Uid, stat.Gid, nobody, nobody)
+	}
+
+	// Errors of the helper keep the sentinel errors they wrapped
+	_, err = pm.Download(ctx, &port.Source{Type: "archive", URL: server.URL + "/missing.tar.gz"}, "")
+	if !errors.Is(err, domain.ErrSourceNotFound) || !errors.Is(err, domain.ErrNetworkFailure) {
+		t.Errorf("Download() of a missing archive error = %v, want ErrSourceNotFound", err)
+	}
+
+	// Local sources are read by root itself
+	if local := newUnprivileged(NewLocal(), &helperCredential{uid: nobody, gid: nobody}); !isLocal(local) {
+		t.Errorf("newUnprivileged() of a local source = %T, want *Local", local)
+	}
+}
+
+func isLocal(pm port.PackageManager) bool {
+	_, ok := pm.(*Local)
+	return ok
+}
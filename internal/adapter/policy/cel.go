@@ -0,0 +1,88 @@
+// Package policy provides policy engines that decide whether a skill may be installed.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/types"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// defaultDenyReason is reported when a boolean policy evaluates to false.
+const defaultDenyReason = "the skill does not satisfy the policy"
+
+// CEL is an implementation of PolicyEngine for policies written in the Common Expression Language.
+// A policy is a single expression over the variable skill, which evaluates to either
+// a bool (true allows the skill) or a string (empty allows the skill, otherwise the reason it is denied).
+type CEL struct {
+	env *cel.Env
+}
+
+// NewCEL creates a new CEL instance.
+func NewCEL() (*CEL, error) {
+	env, err := cel.NewEnv(cel.Variable("skill", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	return &CEL{env: env}, nil
+}
+
+// Compile parses and type-checks the expression.
+func (c *CEL) Compile(source string) (port.Policy, error) {
+	ast, issues := c.env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.OutputType(); !t.IsExactType(types.BoolType) && !t.IsExactType(types.StringType) && !t.IsExactType(types.DynType) {
+		return nil, fmt.Errorf("policy must evaluate to a bool or a string, not %s", t)
+	}
+
+	program, err := c.env.Program(ast, cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, err
+	}
+	return &celPolicy{program: program}, nil
+}
+
+type celPolicy struct {
+	program cel.Program
+}
+
+// Evaluate evaluates the expression with the skill bound to the variable skill.
+func (p *celPolicy) Evaluate(ctx context.Context, input *port.PolicyInput) (string, error) {
+	files := make([]any, 0, len(input.Files))
+	for _, file := range input.Files {
+		files = append(files, file)
+	}
+
+	out, _, err := p.program.ContextEval(ctx, map[string]any{
+		"skill": map[string]any{
+			"name":    input.Name,
+			"source":  input.Source,
+			"url":     input.URL,
+			"version": input.Version,
+			"license": input.License,
+			"files":   files,
+			"size":    input.Size,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch v := out.Value().(type) {
+	case bool:
+		if v {
+			return "", nil
+		}
+		return defaultDenyReason, nil
+	case string:
+		return v, nil
+	default:
+		return "", errors.New("policy must evaluate to a bool or a string")
+	}
+}
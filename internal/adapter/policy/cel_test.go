@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TestCEL_Evaluate tests policies that evaluate to a bool or a deny reason
+func TestCEL_Evaluate(t *testing.T) {
+	input := &port.PolicyInput{
+		Name:    "code-review",
+		Source:  "git",
+		URL:     "https://github.com/example/skills.git",
+		Version: "v1.2.0",
+		License: "MIT",
+		Files:   []string{"SKILL.md", "scripts/run.sh"},
+		Size:    2048,
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		wantReason string
+	}{
+		{
+			name:   "bool: allowed",
+			policy: `skill.source == "git" && skill.url.startsWith("https://github.com/example/")`,
+		},
+		{
+			name:       "bool: denied",
+			policy:     `skill.license in ["Apache-2.0"]`,
+			wantReason: defaultDenyReason,
+		},
+		{
+			name:   "string: allowed",
+			policy: `skill.size < 1024 * 1024 ? "" : "skill is larger than 1 MiB"`,
+		},
+		{
+			name:       "string: denied",
+			policy:     `skill.files.exists(f, f.endsWith(".sh")) ? "shell scripts are not allowed" : ""`,
+			wantReason: "shell scripts are not allowed",
+		},
+	}
+
+	engine, err := NewCEL()
+	if err != nil {
+		t.Fatalf("NewCEL() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := engine.Compile(tt.policy)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			reason, err := policy.Evaluate(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("Evaluate() = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+// TestCEL_Compile tests that invalid policies are rejected before evaluation
+func TestCEL_Compile(t *testing.T) {
+	engine, err := NewCEL()
+	if err != nil {
+		t.Fatalf("NewCEL() error = %v", err)
+	}
+
+	for _, policy := range []string{
+		`skill.source ==`, // Syntax error
+		`unknown.field`,   // Undeclared variable
+		`1 + 2`,           // Neither a bool nor a string
+	} {
+		if _, err := engine.Compile(policy); err == nil {
+			t.Errorf("Compile(%q) should fail", policy)
+		}
+	}
+}
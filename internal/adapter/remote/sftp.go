@@ -0,0 +1,300 @@
+// Package remote provides implementations of the RemoteInstaller interface
+// for install targets on other machines.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/port"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// stagingSuffix is appended to the skill directory while files are uploaded,
+// so that an interrupted upload never leaves a partially installed skill in place.
+const stagingSuffix = ".skills-pkg-staging"
+
+// replacedSuffix is appended to the installed skill directory when it is moved aside for the new one,
+// so that the skill is only missing between two renames instead of while it is deleted.
+const replacedSuffix = ".skills-pkg-replaced"
+
+// SFTP installs skills to targets of the form ssh://[user@]host[:port]/path over SFTP.
+// Paths starting with /~/ are resolved relative to the remote user's home directory.
+// Authentication uses the SSH agent and key files in ~/.ssh/, and host keys are checked
+// against ~/.ssh/known_hosts.
+type SFTP struct {
+	// connect opens an SFTP session for the target; replaced in tests.
+	connect func(ctx context.Context, target *url.URL) (*sftp.Client, func(), error)
+}
+
+// NewSFTP creates a new SFTP remote installer instance.
+func NewSFTP() port.RemoteInstaller {
+	return &SFTP{connect: dialSFTP}
+}
+
+// Scheme returns the URL scheme handled by the installer.
+func (s *SFTP) Scheme() string {
+	return "ssh"
+}
+
+// Install uploads the skill files into a staging directory and then swaps it into place.
+func (s *SFTP) Install(ctx context.Context, target, skillName, sourceDir string, files []string) error {
+	targetURL, remoteDir, err := parseTarget(target)
+	if err != nil {
+		return err
+	}
+
+	client, closeFn, err := s.connect(ctx, targetURL)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	skillDir := path.Join(remoteDir, skillName)
+	staging := skillDir + stagingSuffix
+
+	replaced := skillDir + replacedSuffix
+	for _, dir := range []string{staging, replaced} {
+		if err := client.RemoveAll(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to clean up directory %s on %s: %w", dir, targetURL.Host, err)
+		}
+	}
+	if err := client.MkdirAll(staging); err != nil {
+		return fmt.Errorf("failed to create directory %s on %s: %w", staging, targetURL.Host, err)
+	}
+
+	for _, name := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := uploadFile(client, filepath.Join(sourceDir, filepath.FromSlash(name)), path.Join(staging, name)); err != nil {
+			return fmt.Errorf("failed to upload %s to %s: %w", name, targetURL.Host, err)
+		}
+	}
+
+	// SFTP cannot exchange paths, so the installed skill is moved aside and deleted once the new one is in place
+	if err := client.Rename(skillDir, replaced); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to move existing skill directory %s aside on %s: %w", skillDir, targetURL.Host, err)
+	}
+	if err := client.Rename(staging, skillDir); err != nil {
+		_ = client.Rename(replaced, skillDir)
+		return fmt.Errorf("failed to move skill into place at %s on %s: %w", skillDir, targetURL.Host, err)
+	}
+	if err := client.RemoveAll(replaced); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove replaced skill directory %s on %s: %w", replaced, targetURL.Host, err)
+	}
+
+	return nil
+}
+
+// Remove deletes the skill directory on the remote host if it exists.
+func (s *SFTP) Remove(ctx context.Context, target, skillName string) error {
+	targetURL, remoteDir, err := parseTarget(target)
+	if err != nil {
+		return err
+	}
+
+	client, closeFn, err := s.connect(ctx, targetURL)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	skillDir := path.Join(remoteDir, skillName)
+	if err := client.RemoveAll(skillDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove skill directory %s on %s: %w", skillDir, targetURL.Host, err)
+	}
+
+	return nil
+}
+
+// parseTarget parses an ssh:// install target and returns the remote directory.
+func parseTarget(target string) (*url.URL, string, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid remote install target %s: %w", target, err)
+	}
+	if targetURL.Scheme != "ssh" {
+		return nil, "", fmt.Errorf("unsupported remote install target %s: only ssh:// is supported", target)
+	}
+	if targetURL.Hostname() == "" {
+		return nil, "", fmt.Errorf("invalid remote install target %s: host is required", target)
+	}
+
+	remoteDir := targetURL.Path
+	switch {
+	case remoteDir == "" || remoteDir == "/":
+		return nil, "", fmt.Errorf("invalid remote install target %s: path is required", target)
+	case remoteDir == "/~" || strings.HasPrefix(remoteDir, "/~/"):
+		// SFTP resolves relative paths against the remote user's home directory
+		remoteDir = strings.TrimPrefix(strings.TrimPrefix(remoteDir, "/~"), "/")
+		if remoteDir == "" {
+			remoteDir = "."
+		}
+	}
+
+	return targetURL, path.Clean(remoteDir), nil
+}
+
+// uploadFile copies a local file to the remote path, preserving its permission bits.
+func uploadFile(client *sftp.Client, src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if err := client.MkdirAll(path.Dir(dst)); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := client.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return client.Chmod(dst, info.Mode().Perm())
+}
+
+// dialSFTP connects to the target host over SSH and starts an SFTP session.
+func dialSFTP(ctx context.Context, target *url.URL) (*sftp.Client, func(), error) {
+	username := target.User.Username()
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to determine SSH user for %s: %w", target.Host, err)
+		}
+		username = current.Username
+	}
+
+	authMethods, closeAgent := sshAuthMethods()
+	if len(authMethods) == 0 {
+		return nil, nil, fmt.Errorf("SSH authentication unavailable for %s: SSH agent not running and no usable key files found in ~/.ssh/", target.Host)
+	}
+
+	hostKeyCallback, err := knownHostsCallback()
+	if err != nil {
+		closeAgent()
+		return nil, nil, err
+	}
+
+	sshPort := target.Port()
+	if sshPort == "" {
+		sshPort = "22"
+	}
+	addr := net.JoinHostPort(target.Hostname(), sshPort)
+
+	conn, err := network.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		closeAgent()
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            username,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
+	})
+	if err != nil {
+		_ = conn.Close()
+		closeAgent()
+		return nil, nil, fmt.Errorf("SSH handshake with %s failed: %w", addr, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		_ = sshClient.Close()
+		closeAgent()
+		return nil, nil, fmt.Errorf("failed to start SFTP session on %s: %w", addr, err)
+	}
+
+	return client, func() {
+		_ = client.Close()
+		_ = sshClient.Close()
+		closeAgent()
+	}, nil
+}
+
+// sshAuthMethods returns the SSH agent and unencrypted key files in ~/.ssh/ as auth methods.
+// The returned function closes the agent connection.
+func sshAuthMethods() ([]ssh.AuthMethod, func()) {
+	var methods []ssh.AuthMethod
+	closeAgent := func() {}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			closeAgent = func() {
+				_ = conn.Close()
+			}
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return methods, closeAgent
+	}
+
+	var signers []ssh.Signer
+	for _, keyFile := range []string{"id_ed25519", "id_rsa", "id_ecdsa"} {
+		data, err := os.ReadFile(filepath.Join(home, ".ssh", keyFile))
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	return methods, closeAgent
+}
+
+// knownHostsCallback verifies host keys against ~/.ssh/known_hosts.
+func knownHostsCallback() (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	knownHostsPath := filepath.Join(home, ".ssh", "known_hosts")
+	callback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w. Connect to the host once with ssh to record its host key", knownHostsPath, err)
+	}
+
+	return callback, nil
+}
@@ -0,0 +1,211 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"github.com/pkg/sftp"
+)
+
+// newInMemorySFTP returns an SFTP installer whose sessions all share one in-memory server.
+func newInMemorySFTP(t *testing.T) (*SFTP, *sftp.Client) {
+	t.Helper()
+
+	handlers := sftp.InMemHandler()
+	dial := func() *sftp.Client {
+		serverConn, clientConn := net.Pipe()
+		server := sftp.NewRequestServer(serverConn, handlers)
+		go func() {
+			_ = server.Serve()
+		}()
+
+		client, err := sftp.NewClientPipe(clientConn, clientConn)
+		if err != nil {
+			t.Fatalf("failed to start SFTP client: %v", err)
+		}
+		return client
+	}
+
+	inspect := dial()
+	t.Cleanup(func() {
+		_ = inspect.Close()
+	})
+
+	return &SFTP{
+		connect: func(context.Context, *url.URL) (*sftp.Client, func(), error) {
+			client := dial()
+			return client, func() {
+				_ = client.Close()
+			}, nil
+		},
+	}, inspect
+}
+
+func TestSFTP_ImplementsInterface(t *testing.T) {
+	var _ port.RemoteInstaller = (*SFTP)(nil)
+}
+
+func TestSFTP_InstallAndRemove(t *testing.T) {
+	tests := []struct {
+		files     map[string]string
+		name      string
+		stale     string
+		wantFiles []string
+	}{
+		{
+			name: "uploads files into the skill directory",
+			files: map[string]string{
+				"SKILL.md":       "# Skill",
+				"scripts/run.sh": "echo",
+			},
+			wantFiles: []string{"SKILL.md", "scripts/run.sh"},
+		},
+		{
+			name: "replaces a previous installation",
+			files: map[string]string{
+				"SKILL.md": "# Skill v2",
+			},
+			stale:     "old.md",
+			wantFiles: []string{"SKILL.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installer, inspect := newInMemorySFTP(t)
+			ctx := context.Background()
+			target := "ssh://deploy@example.com/srv/skills"
+
+			if tt.stale != "" {
+				if err := inspect.MkdirAll("/srv/skills/my-skill"); err != nil {
+					t.Fatalf("failed to create stale directory: %v", err)
+				}
+				f, err := inspect.Create("/srv/skills/my-skill/" + tt.stale)
+				if err != nil {
+					t.Fatalf("failed to create stale file: %v", err)
+				}
+				_ = f.Close()
+			}
+
+			sourceDir := t.TempDir()
+			files := make([]string, 0, len(tt.files))
+			for name, content := range tt.files {
+				path := filepath.Join(sourceDir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+				files = append(files, name)
+			}
+			sort.Strings(files)
+
+			if err := installer.Install(ctx, target, "my-skill", sourceDir, files); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			var got []string
+			walker := inspect.Walk("/srv/skills/my-skill")
+			for walker.Step() {
+				if err := walker.Err(); err != nil {
+					t.Fatalf("failed to walk remote directory: %v", err)
+				}
+				if walker.Stat().IsDir() {
+					continue
+				}
+				rel, _ := filepath.Rel("/srv/skills/my-skill", walker.Path())
+				got = append(got, filepath.ToSlash(rel))
+
+				f, err := inspect.Open(walker.Path())
+				if err != nil {
+					t.Fatalf("failed to open remote file: %v", err)
+				}
+				content, _ := io.ReadAll(f)
+				_ = f.Close()
+				if string(content) != tt.files[filepath.ToSlash(rel)] {
+					t.Errorf("remote %s = %q, want %q", rel, content, tt.files[filepath.ToSlash(rel)])
+				}
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.wantFiles) {
+				t.Fatalf("remote files = %v, want %v", got, tt.wantFiles)
+			}
+			for i := range got {
+				if got[i] != tt.wantFiles[i] {
+					t.Errorf("remote files = %v, want %v", got, tt.wantFiles)
+				}
+			}
+
+			if _, err := inspect.Stat("/srv/skills/my-skill" + stagingSuffix); err == nil {
+				t.Error("staging directory should not remain after install")
+			}
+
+			if err := installer.Remove(ctx, target, "my-skill"); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+			if _, err := inspect.Stat("/srv/skills/my-skill"); err == nil {
+				t.Error("skill directory should not exist after Remove()")
+			}
+
+			// Removing a skill that is not installed is not an error
+			if err := installer.Remove(ctx, target, "my-skill"); err != nil {
+				t.Errorf("Remove() of missing skill error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		wantDir string
+		wantErr bool
+	}{
+		{
+			name:    "absolute path",
+			target:  "ssh://deploy@example.com:2222/srv/skills",
+			wantDir: "/srv/skills",
+		},
+		{
+			name:    "home-relative path",
+			target:  "ssh://example.com/~/.claude/skills",
+			wantDir: ".claude/skills",
+		},
+		{
+			name:    "missing path",
+			target:  "ssh://example.com",
+			wantErr: true,
+		},
+		{
+			name:    "missing host",
+			target:  "ssh:///srv/skills",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			target:  "ftp://example.com/srv/skills",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, dir, err := parseTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if dir != tt.wantDir {
+				t.Errorf("parseTarget() dir = %q, want %q", dir, tt.wantDir)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package scanner provides implementations of the ContentScanner interface.
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// maxOutput is how much of the scanner output is kept, from the end, to explain a rejection.
+const maxOutput = 4096
+
+// Command runs a scanner command, passing the skill directory as its last argument.
+// The command rejects the skill by exiting with a non-zero status.
+type Command struct{}
+
+// NewCommand creates a new Command instance.
+func NewCommand() *Command {
+	return &Command{}
+}
+
+// Scan runs the command with the target directory appended to its arguments. The skill is also
+// described by the SKILLSPKG_SKILL_DIR, SKILLSPKG_SKILL_NAME, SKILLSPKG_SKILL_SOURCE,
+// SKILLSPKG_SKILL_URL, and SKILLSPKG_SKILL_VERSION environment variables.
+func (c *Command) Scan(ctx context.Context, command []string, target *port.ScanTarget) (*port.ScanResult, error) {
+	if len(command) == 0 {
+		return nil, errors.New("scanner command is empty")
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], append(command[1:], target.Dir)...)
+	cmd.Env = append(os.Environ(),
+		"SKILLSPKG_SKILL_DIR="+target.Dir,
+		"SKILLSPKG_SKILL_NAME="+target.Name,
+		"SKILLSPKG_SKILL_SOURCE="+target.Source,
+		"SKILLSPKG_SKILL_URL="+target.URL,
+		"SKILLSPKG_SKILL_VERSION="+target.Version,
+	)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if _, ok := errors.AsType[*exec.ExitError](err); ok && ctx.Err() == nil {
+		out := bytes.TrimSpace(output.Bytes())
+		if len(out) > maxOutput {
+			out = append([]byte("..."), out[len(out)-maxOutput:]...)
+		}
+		return &port.ScanResult{Output: string(out)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run scanner %s: %w", command[0], err)
+	}
+
+	return &port.ScanResult{Output: string(bytes.TrimSpace(output.Bytes())), Clean: true}, nil
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TestCommand_Scan tests that the exit status of the scanner decides the verdict
+func TestCommand_Scan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test scanners are shell scripts")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("EICAR\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	target := &port.ScanTarget{Dir: dir, Name: "test-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0"}
+
+	tests := []struct {
+		name       string
+		command    []string
+		wantOutput string
+		wantClean  bool
+		wantErr    bool
+	}{
+		{
+			name:      "clean: exit status 0",
+			command:   []string{"sh", "-c", `test -d "$0" && test "$SKILLSPKG_SKILL_NAME" = test-skill`},
+			wantClean: true,
+		},
+		{
+			name:       "rejected: non-zero exit status",
+			command:    []string{"sh", "-c", `if grep -rq EICAR "$0"; then echo "$SKILLSPKG_SKILL_VERSION: infected"; exit 1; fi`},
+			wantOutput: "v1.0.0: infected",
+		},
+		{
+			name:    "error: command not found",
+			command: []string{"skills-pkg-no-such-scanner"},
+			wantErr: true,
+		},
+		{
+			name:    "error: empty command",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewCommand().Scan(context.Background(), tt.command, target)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Scan() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if result.Clean != tt.wantClean {
+				t.Errorf("Scan().Clean = %v, want %v (output: %s)", result.Clean, tt.wantClean, result.Output)
+			}
+			if !strings.Contains(result.Output, tt.wantOutput) {
+				t.Errorf("Scan().Output = %q, want %q", result.Output, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/command"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// cronSchedules maps schedule intervals to crontab schedule expressions.
+var cronSchedules = map[string]string{
+	port.ScheduleHourly: "@hourly",
+	port.ScheduleDaily:  "@daily",
+	port.ScheduleWeekly: "@weekly",
+}
+
+// Cron schedules jobs in the current user's crontab.
+// Each job is stored between marker comments so that it can be replaced or removed
+// without touching other entries.
+type Cron struct {
+	run commandRunner
+}
+
+// NewCron creates a new cron scheduler instance.
+func NewCron() port.Scheduler {
+	return &Cron{run: command.Output}
+}
+
+// Name returns the name of the scheduler.
+func (c *Cron) Name() string {
+	return "cron"
+}
+
+// Install adds the job to the crontab, replacing an existing entry with the same name.
+func (c *Cron) Install(ctx context.Context, job *port.ScheduledJob) error {
+	schedule, ok := cronSchedules[job.Interval]
+	if !ok {
+		return fmt.Errorf("unsupported schedule interval: %s", job.Interval)
+	}
+
+	lines, err := c.readCrontab(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := make([]string, 0, len(job.Command))
+	for _, arg := range job.Command {
+		args = append(args, shellQuote(arg))
+	}
+	command := fmt.Sprintf("cd %s && %s >> %s 2>&1", shellQuote(job.WorkDir), strings.Join(args, " "), shellQuote(job.LogPath))
+	// cron treats unescaped % in the command as a newline
+	entry := schedule + " " + strings.ReplaceAll(command, "%", `\%`)
+
+	lines = removeCronEntry(lines, job.Name)
+	lines = append(lines, cronBeginMarker(job.Name), entry, cronEndMarker(job.Name))
+
+	return c.writeCrontab(ctx, lines)
+}
+
+// Remove deletes the job from the crontab.
+func (c *Cron) Remove(ctx context.Context, name string) error {
+	lines, err := c.readCrontab(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := removeCronEntry(lines, name)
+	if len(remaining) == len(lines) {
+		return nil
+	}
+
+	return c.writeCrontab(ctx, remaining)
+}
+
+// readCrontab returns the lines of the current crontab. A missing crontab is treated as empty.
+func (c *Cron) readCrontab(ctx context.Context) ([]string, error) {
+	out, err := c.run(ctx, nil, "crontab", "-l")
+	if err != nil {
+		if strings.Contains(string(out), "no crontab") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read crontab: %w", err)
+	}
+
+	content := strings.TrimRight(string(out), "\n")
+	if content == "" {
+		return nil, nil
+	}
+	return strings.Split(content, "\n"), nil
+}
+
+func (c *Cron) writeCrontab(ctx context.Context, lines []string) error {
+	content := strings.Join(lines, "\n") + "\n"
+	if _, err := c.run(ctx, []byte(content), "crontab", "-"); err != nil {
+		return fmt.Errorf("failed to write crontab: %w", err)
+	}
+	return nil
+}
+
+// removeCronEntry returns lines without the marked block of the named job.
+func removeCronEntry(lines []string, name string) []string {
+	result := make([]string, 0, len(lines))
+	inEntry := false
+	for _, line := range lines {
+		switch {
+		case line == cronBeginMarker(name):
+			inEntry = true
+		case line == cronEndMarker(name):
+			inEntry = false
+		case !inEntry:
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func cronBeginMarker(name string) string {
+	return "# BEGIN " + name + " (managed by skills-pkg)"
+}
+
+func cronEndMarker(name string) string {
+	return "# END " + name
+}
+
+// shellQuote quotes s for POSIX sh using single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
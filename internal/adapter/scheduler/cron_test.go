@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCron_InstallAndRemove(t *testing.T) {
+	ctx := context.Background()
+	runner := &fakeRunner{crontab: "0 * * * * /usr/bin/backup\n"}
+	c := &Cron{run: runner.run}
+	job := testJob(t)
+
+	// Installing twice must replace the entry instead of duplicating it
+	for range 2 {
+		if err := c.Install(ctx, job); err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+	}
+
+	want := "0 * * * * /usr/bin/backup\n" +
+		"# BEGIN skills-pkg-autoupdate-test (managed by skills-pkg)\n" +
+		"@daily cd '/home/user/my project' && '/usr/local/bin/skills-pkg' 'update' '--minor' >> '/home/user/.cache/skills-pkg/autoupdate.log' 2>&1\n" +
+		"# END skills-pkg-autoupdate-test\n"
+	if runner.crontab != want {
+		t.Errorf("crontab = %q, want %q", runner.crontab, want)
+	}
+
+	if err := c.Remove(ctx, job.Name); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if runner.crontab != "0 * * * * /usr/bin/backup\n" {
+		t.Errorf("crontab after Remove() = %q, want only the unrelated entry", runner.crontab)
+	}
+}
+
+func TestCron_InstallEscapesPercent(t *testing.T) {
+	runner := &fakeRunner{}
+	c := &Cron{run: runner.run}
+	job := testJob(t)
+	job.WorkDir = "/home/user/100%"
+
+	if err := c.Install(context.Background(), job); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !strings.Contains(runner.crontab, `cd '/home/user/100\%'`) {
+		t.Errorf("crontab should escape %%, got %q", runner.crontab)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellQuote("it's"), `'it'\''s'`; got != want {
+		t.Errorf("shellQuote() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/command"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// launchdIntervals maps schedule intervals to launchd StartInterval seconds.
+var launchdIntervals = map[string]int{
+	port.ScheduleHourly: 60 * 60,
+	port.ScheduleDaily:  24 * 60 * 60,
+	port.ScheduleWeekly: 7 * 24 * 60 * 60,
+}
+
+// Launchd schedules jobs as macOS launch agents in ~/Library/LaunchAgents.
+// The job name is used as the agent label.
+type Launchd struct {
+	run commandRunner
+}
+
+// NewLaunchd creates a new launchd scheduler instance.
+func NewLaunchd() port.Scheduler {
+	return &Launchd{run: command.Output}
+}
+
+// Name returns the name of the scheduler.
+func (l *Launchd) Name() string {
+	return "launchd"
+}
+
+// Install writes the launch agent property list and loads it.
+func (l *Launchd) Install(ctx context.Context, job *port.ScheduledJob) error {
+	interval, ok := launchdIntervals[job.Interval]
+	if !ok {
+		return fmt.Errorf("unsupported schedule interval: %s", job.Interval)
+	}
+
+	plistPath, err := launchAgentPath(job.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(plistPath), err)
+	}
+	if err := os.WriteFile(plistPath, []byte(launchdPlist(job, interval)), 0o644); err != nil {
+		return fmt.Errorf("failed to write launch agent %s: %w", plistPath, err)
+	}
+
+	// Unload a previous version of the agent; it fails harmlessly when none is loaded
+	_, _ = l.run(ctx, nil, "launchctl", "unload", plistPath)
+	if _, err := l.run(ctx, nil, "launchctl", "load", "-w", plistPath); err != nil {
+		return fmt.Errorf("failed to load launch agent %s: %w", plistPath, err)
+	}
+
+	return nil
+}
+
+// Remove unloads the launch agent and deletes its property list.
+func (l *Launchd) Remove(ctx context.Context, name string) error {
+	plistPath, err := launchAgentPath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(plistPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	_, _ = l.run(ctx, nil, "launchctl", "unload", "-w", plistPath)
+	if err := os.Remove(plistPath); err != nil {
+		return fmt.Errorf("failed to remove launch agent %s: %w", plistPath, err)
+	}
+
+	return nil
+}
+
+// launchAgentPath returns the property list path of the launch agent with the given label.
+func launchAgentPath(label string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", label+".plist"), nil
+}
+
+// launchdPlist renders the launch agent property list for the job.
+func launchdPlist(job *port.ScheduledJob, interval int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(job.Name))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range job.Command {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	fmt.Fprintf(&b, "\t<key>WorkingDirectory</key>\n\t<string>%s</string>\n", xmlEscape(job.WorkDir))
+	fmt.Fprintf(&b, "\t<key>StartInterval</key>\n\t<integer>%d</integer>\n", interval)
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(job.LogPath))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(job.LogPath))
+	b.WriteString("</dict>\n</plist>\n")
+
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
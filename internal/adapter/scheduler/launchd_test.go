@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLaunchd_InstallAndRemove(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	ctx := context.Background()
+	runner := &fakeRunner{}
+	l := &Launchd{run: runner.run}
+	job := testJob(t)
+	job.Command = append(job.Command, "--exclude", "a&b")
+
+	if err := l.Install(ctx, job); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	plistPath := filepath.Join(home, "Library", "LaunchAgents", job.Name+".plist")
+	plist, err := os.ReadFile(plistPath)
+	if err != nil {
+		t.Fatalf("failed to read launch agent: %v", err)
+	}
+	for _, want := range []string{
+		"<string>skills-pkg-autoupdate-test</string>",
+		"<string>/usr/local/bin/skills-pkg</string>",
+		"<string>a&amp;b</string>",
+		"<integer>86400</integer>",
+	} {
+		if !strings.Contains(string(plist), want) {
+			t.Errorf("launch agent should contain %q, got:\n%s", want, plist)
+		}
+	}
+	if !slices.Contains(runner.calls, "launchctl load -w "+plistPath) {
+		t.Errorf("Install() should load the agent, calls: %v", runner.calls)
+	}
+
+	if err := l.Remove(ctx, job.Name); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(plistPath); !os.IsNotExist(err) {
+		t.Errorf("launch agent should be removed, stat error = %v", err)
+	}
+}
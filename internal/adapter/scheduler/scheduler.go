@@ -0,0 +1,56 @@
+// Package scheduler provides implementations of the Scheduler interface
+// for user-level job schedulers (launchd, systemd timers, and cron).
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// commandRunner runs an external command with optional stdin and returns its combined output.
+// It is replaced in tests.
+type commandRunner func(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error)
+
+// New returns the scheduler with the given name ("launchd", "systemd", or "cron").
+func New(name string) (port.Scheduler, error) {
+	switch name {
+	case "launchd":
+		return NewLaunchd(), nil
+	case "systemd":
+		return NewSystemd(), nil
+	case "cron":
+		return NewCron(), nil
+	default:
+		return nil, fmt.Errorf("unsupported scheduler: %s (supported: launchd, systemd, cron)", name)
+	}
+}
+
+// Detect returns the scheduler available on the current platform:
+// launchd on macOS, systemd user timers on Linux systems booted with systemd, and cron elsewhere.
+func Detect() (port.Scheduler, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return NewLaunchd(), nil
+	case "windows":
+		return nil, fmt.Errorf("no supported scheduler on windows: create a Task Scheduler task that runs 'skills-pkg update' instead")
+	}
+
+	if runtime.GOOS == "linux" {
+		if _, err := os.Stat("/run/systemd/system"); err == nil {
+			if _, err := exec.LookPath("systemctl"); err == nil {
+				return NewSystemd(), nil
+			}
+		}
+	}
+
+	if _, err := exec.LookPath("crontab"); err == nil {
+		return NewCron(), nil
+	}
+
+	return nil, fmt.Errorf("no supported scheduler found: install cron or use a system with systemd")
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// fakeRunner records executed commands and emulates a crontab.
+type fakeRunner struct {
+	calls   []string
+	crontab string
+}
+
+func (f *fakeRunner) run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	call := strings.Join(append([]string{name}, args...), " ")
+	f.calls = append(f.calls, call)
+
+	switch call {
+	case "crontab -l":
+		return []byte(f.crontab), nil
+	case "crontab -":
+		f.crontab = string(stdin)
+	}
+	return nil, nil
+}
+
+func testJob(t *testing.T) *port.ScheduledJob {
+	t.Helper()
+	return &port.ScheduledJob{
+		Name:     "skills-pkg-autoupdate-test",
+		WorkDir:  "/home/user/my project",
+		Interval: port.ScheduleDaily,
+		LogPath:  "/home/user/.cache/skills-pkg/autoupdate.log",
+		Command:  []string{"/usr/local/bin/skills-pkg", "update", "--minor"},
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "launchd", want: "launchd"},
+		{name: "systemd", want: "systemd"},
+		{name: "cron", want: "cron"},
+		{name: "schtasks", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.Name() != tt.want {
+				t.Errorf("Name() = %s, want %s", s.Name(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/command"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// systemdCalendars maps schedule intervals to OnCalendar expressions.
+var systemdCalendars = map[string]string{
+	port.ScheduleHourly: "hourly",
+	port.ScheduleDaily:  "daily",
+	port.ScheduleWeekly: "weekly",
+}
+
+// Systemd schedules jobs as systemd user timers in $XDG_CONFIG_HOME/systemd/user.
+// Each job consists of <name>.service and <name>.timer units.
+type Systemd struct {
+	run commandRunner
+}
+
+// NewSystemd creates a new systemd scheduler instance.
+func NewSystemd() port.Scheduler {
+	return &Systemd{run: command.Output}
+}
+
+// Name returns the name of the scheduler.
+func (s *Systemd) Name() string {
+	return "systemd"
+}
+
+// Install writes the service and timer units and enables the timer.
+func (s *Systemd) Install(ctx context.Context, job *port.ScheduledJob) error {
+	calendar, ok := systemdCalendars[job.Interval]
+	if !ok {
+		return fmt.Errorf("unsupported schedule interval: %s", job.Interval)
+	}
+
+	unitDir, err := systemdUserUnitDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(unitDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", unitDir, err)
+	}
+
+	units := map[string]string{
+		job.Name + ".service": systemdService(job),
+		job.Name + ".timer":   systemdTimer(job, calendar),
+	}
+	for name, content := range units {
+		unitPath := filepath.Join(unitDir, name)
+		if err := os.WriteFile(unitPath, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write systemd unit %s: %w", unitPath, err)
+		}
+	}
+
+	if _, err := s.run(ctx, nil, "systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	if _, err := s.run(ctx, nil, "systemctl", "--user", "enable", "--now", job.Name+".timer"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Remove disables the timer and deletes the job's units.
+func (s *Systemd) Remove(ctx context.Context, name string) error {
+	unitDir, err := systemdUserUnitDir()
+	if err != nil {
+		return err
+	}
+
+	timerPath := filepath.Join(unitDir, name+".timer")
+	if _, err := os.Stat(timerPath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	// Disabling fails harmlessly when the timer was never loaded
+	_, _ = s.run(ctx, nil, "systemctl", "--user", "disable", "--now", name+".timer")
+
+	for _, unit := range []string{name + ".timer", name + ".service"} {
+		if err := os.Remove(filepath.Join(unitDir, unit)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove systemd unit %s: %w", unit, err)
+		}
+	}
+
+	if _, err := s.run(ctx, nil, "systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// systemdUserUnitDir returns the directory for user units, honoring XDG_CONFIG_HOME.
+func systemdUserUnitDir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "systemd", "user"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// systemdService renders the oneshot service unit that runs the job command.
+func systemdService(job *port.ScheduledJob) string {
+	args := make([]string, 0, len(job.Command))
+	for _, arg := range job.Command {
+		args = append(args, systemdQuote(arg))
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=skills-pkg scheduled job %s
+
+[Service]
+Type=oneshot
+WorkingDirectory=%s
+ExecStart=%s
+StandardOutput=append:%s
+StandardError=append:%s
+`, job.Name, job.WorkDir, strings.Join(args, " "), job.LogPath, job.LogPath)
+}
+
+// systemdTimer renders the timer unit. Persistent runs jobs missed while the machine was off.
+func systemdTimer(job *port.ScheduledJob, calendar string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Run skills-pkg scheduled job %s %s
+
+[Timer]
+OnCalendar=%s
+Persistent=true
+RandomizedDelaySec=10min
+
+[Install]
+WantedBy=timers.target
+`, job.Name, job.Interval, calendar)
+}
+
+// systemdQuote quotes a command line argument for ExecStart, escaping specifiers.
+func systemdQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, `\`, `\\`)
+	arg = strings.ReplaceAll(arg, `"`, `\"`)
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	return `"` + arg + `"`
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSystemd_InstallAndRemove(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	ctx := context.Background()
+	runner := &fakeRunner{}
+	s := &Systemd{run: runner.run}
+	job := testJob(t)
+
+	if err := s.Install(ctx, job); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	unitDir := filepath.Join(configHome, "systemd", "user")
+	service, err := os.ReadFile(filepath.Join(unitDir, job.Name+".service"))
+	if err != nil {
+		t.Fatalf("failed to read service unit: %v", err)
+	}
+	for _, want := range []string{
+		`WorkingDirectory=/home/user/my project`,
+		`ExecStart="/usr/local/bin/skills-pkg" "update" "--minor"`,
+		`StandardOutput=append:/home/user/.cache/skills-pkg/autoupdate.log`,
+	} {
+		if !strings.Contains(string(service), want) {
+			t.Errorf("service unit should contain %q, got:\n%s", want, service)
+		}
+	}
+
+	timer, err := os.ReadFile(filepath.Join(unitDir, job.Name+".timer"))
+	if err != nil {
+		t.Fatalf("failed to read timer unit: %v", err)
+	}
+	if !strings.Contains(string(timer), "OnCalendar=daily") {
+		t.Errorf("timer unit should run daily, got:\n%s", timer)
+	}
+	if !slices.Contains(runner.calls, "systemctl --user enable --now "+job.Name+".timer") {
+		t.Errorf("Install() should enable the timer, calls: %v", runner.calls)
+	}
+
+	if err := s.Remove(ctx, job.Name); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(unitDir, job.Name+".timer")); !os.IsNotExist(err) {
+		t.Errorf("timer unit should be removed, stat error = %v", err)
+	}
+	if !slices.Contains(runner.calls, "systemctl --user disable --now "+job.Name+".timer") {
+		t.Errorf("Remove() should disable the timer, calls: %v", runner.calls)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	if got, want := systemdQuote(`a "b" 100%`), `"a \"b\" 100%%"`; got != want {
+		t.Errorf("systemdQuote() = %s, want %s", got, want)
+	}
+}
@@ -3,34 +3,69 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"golang.org/x/mod/sumdb/dirhash"
 
+	"github.com/mazrean/skills-pkg/internal/domain"
 	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 // Dirhash is an implementation of HashService using golang.org/x/mod/sumdb/dirhash.
 // It calculates directory hashes using SHA-256 algorithm.
 // Requirements: 5.1
-type Dirhash struct{}
+type Dirhash struct {
+	fsys port.FileSystem // The local file system when nil
+}
 
-// NewDirhash creates a new Dirhash instance.
+// NewDirhash creates a new Dirhash instance that hashes directories of the local file system.
 func NewDirhash() *Dirhash {
 	return &Dirhash{}
 }
 
+// NewDirhashFS creates a new Dirhash instance that hashes directories of fsys.
+func NewDirhashFS(fsys port.FileSystem) *Dirhash {
+	return &Dirhash{fsys: fsys}
+}
+
+// fileSystem returns the file system directories are hashed from.
+func (s *Dirhash) fileSystem() port.FileSystem {
+	if s.fsys == nil {
+		return port.OSFileSystem{}
+	}
+	return s.fsys
+}
+
 // CalculateHash calculates the hash of a directory recursively.
 // It includes both file names and file contents in the hash calculation.
-// The hash is calculated using the SHA-256 algorithm via golang.org/x/mod/sumdb/dirhash.HashDir.
+// Files excluded by the skill's .gitignore/.skillignore or by the options are not included,
+// nor is the banner of SKILL.md when the options strip it.
+// The hash is calculated using the SHA-256 algorithm via golang.org/x/mod/sumdb/dirhash.Hash1.
+// With port.HashAlgorithmN1, text file contents are normalized before hashing.
 // Requirements: 5.1, 12.2, 12.3
-func (s *Dirhash) CalculateHash(ctx context.Context, dirPath string) (*port.HashResult, error) {
+func (s *Dirhash) CalculateHash(ctx context.Context, dirPath string, algorithm string, opts ...port.HashOption) (*port.HashResult, error) {
+	if algorithm == "" {
+		algorithm = port.HashAlgorithmH1
+	}
+	if algorithm != port.HashAlgorithmH1 && algorithm != port.HashAlgorithmN1 {
+		return nil, fmt.Errorf("unsupported hash algorithm '%s'. Supported algorithms: %s, %s", algorithm, port.HashAlgorithmH1, port.HashAlgorithmN1)
+	}
+
+	fsys := s.fileSystem()
+	options := port.NewHashOptions(opts...)
+
 	// Verify that the directory exists
-	info, err := os.Stat(dirPath)
+	info, err := fsys.Stat(dirPath)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("directory does not exist: %s: %w", dirPath, err)
 		}
 		return nil, fmt.Errorf("failed to access directory %s: %w", dirPath, err)
@@ -41,15 +76,80 @@ func (s *Dirhash) CalculateHash(ctx context.Context, dirPath string) (*port.Hash
 		return nil, fmt.Errorf("path is not a directory: %s", dirPath)
 	}
 
-	// Calculate hash using dirhash.HashDir (SHA-256 based)
-	// HashDir returns format "h1:<base64-encoded-sha256>" which is the standard Go module hash format
-	hashValue, err := dirhash.HashDir(dirPath, "", dirhash.Hash1)
+	// Collect files, skipping paths excluded by the skill's .gitignore/.skillignore
+	files, err := domain.ListSkillFilesFS(fsys, dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in directory %s: %w", dirPath, err)
+	}
+	if ignore := domain.NewSkillIgnore(options.Exclude); ignore != nil {
+		files = slices.DeleteFunc(files, func(name string) bool {
+			return ignore.Match(name, false)
+		})
+	}
+
+	var hashed int64
+	open := func(name string) (io.ReadCloser, error) {
+		path := filepath.Join(dirPath, filepath.FromSlash(name))
+		if algorithm != port.HashAlgorithmN1 && (name != "SKILL.md" || !options.StripBanner) {
+			f, err := fsys.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			return &countingReadCloser{ReadCloser: f, n: &hashed}, nil
+		}
+
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		// The banner inserted into installed SKILL.md files is not part of the skill
+		if name == "SKILL.md" && options.StripBanner {
+			data = domain.StripBanner(data)
+		}
+		if algorithm == port.HashAlgorithmN1 {
+			data = normalizeContent(data)
+		}
+		hashed += int64(len(data))
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	// Calculate hash using dirhash.Hash1 (SHA-256 based), as dirhash.HashDir does
+	// Hash1 returns format "h1:<base64-encoded-sha256>" which is the standard Go module hash format
+	hashValue, err := dirhash.Hash1(files, open)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate hash for directory %s: %w", dirPath, err)
 	}
 
-	// HashDir returns format "h1:<base64>" - we use this as-is for consistency with Go module ecosystem
+	// Hash1 returns format "h1:<base64>" - we use this as-is for consistency with Go module ecosystem,
+	// replacing the prefix when contents were normalized so the two modes are never confused
+	if algorithm != port.HashAlgorithmH1 {
+		hashValue = algorithm + strings.TrimPrefix(hashValue, port.HashAlgorithmH1)
+	}
+
 	return &port.HashResult{
 		Value: hashValue,
+		Bytes: hashed,
 	}, nil
 }
+
+// countingReadCloser adds the number of bytes read to n.
+type countingReadCloser struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	*r.n += int64(n)
+	return n, err
+}
+
+// normalizeContent converts CRLF line endings to LF for text content.
+// Content containing a NUL byte is treated as binary and returned unchanged.
+// File permissions are never part of the hash, so they need no normalization.
+func normalizeContent(data []byte) []byte {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/mazrean/skills-pkg/internal/adapter/filesystem"
+	"github.com/mazrean/skills-pkg/internal/domain"
 	"github.com/mazrean/skills-pkg/internal/port"
 )
 
@@ -41,6 +44,38 @@ func TestDirhash_CalculateHash(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success: files excluded by .skillignore do not affect hash",
+			setupFunc: func(t *testing.T) string {
+				return ""
+			},
+			wantErr: false,
+			checkFunc: func(t *testing.T, result *port.HashResult, err error) {
+				tmpDir1 := t.TempDir()
+				tmpDir2 := t.TempDir()
+				for _, dir := range []string{tmpDir1, tmpDir2} {
+					if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("skill"), 0644); err != nil {
+						t.Fatalf("Failed to create test file: %v", err)
+					}
+					if err := os.WriteFile(filepath.Join(dir, ".skillignore"), []byte("*.log\n"), 0644); err != nil {
+						t.Fatalf("Failed to create .skillignore: %v", err)
+					}
+				}
+				if err := os.WriteFile(filepath.Join(tmpDir2, "debug.log"), []byte("runtime output"), 0644); err != nil {
+					t.Fatalf("Failed to create ignored file: %v", err)
+				}
+
+				svc := NewDirhash()
+				result1, err1 := svc.CalculateHash(context.Background(), tmpDir1, port.HashAlgorithmH1)
+				result2, err2 := svc.CalculateHash(context.Background(), tmpDir2, port.HashAlgorithmH1)
+				if err1 != nil || err2 != nil {
+					t.Fatalf("Unexpected errors: %v, %v", err1, err2)
+				}
+				if result1.Value != result2.Value {
+					t.Errorf("Expected ignored file not to change hash: %s != %s", result1.Value, result2.Value)
+				}
+			},
+		},
 		{
 			name: "success: same content produces same hash",
 			setupFunc: func(t *testing.T) string {
@@ -63,8 +98,8 @@ func TestDirhash_CalculateHash(t *testing.T) {
 
 				svc := NewDirhash()
 				ctx := context.Background()
-				result1, err1 := svc.CalculateHash(ctx, tmpDir1)
-				result2, err2 := svc.CalculateHash(ctx, tmpDir2)
+				result1, err1 := svc.CalculateHash(ctx, tmpDir1, port.HashAlgorithmH1)
+				result2, err2 := svc.CalculateHash(ctx, tmpDir2, port.HashAlgorithmH1)
 
 				if err1 != nil || err2 != nil {
 					t.Fatalf("Expected no errors, got: %v, %v", err1, err2)
@@ -95,8 +130,8 @@ func TestDirhash_CalculateHash(t *testing.T) {
 
 				svc := NewDirhash()
 				ctx := context.Background()
-				result1, err1 := svc.CalculateHash(ctx, tmpDir1)
-				result2, err2 := svc.CalculateHash(ctx, tmpDir2)
+				result1, err1 := svc.CalculateHash(ctx, tmpDir1, port.HashAlgorithmH1)
+				result2, err2 := svc.CalculateHash(ctx, tmpDir2, port.HashAlgorithmH1)
 
 				if err1 != nil || err2 != nil {
 					t.Fatalf("Expected no errors, got: %v, %v", err1, err2)
@@ -159,7 +194,8 @@ func TestDirhash_CalculateHash(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Special cases that handle their own execution
-			if tt.name == "success: same content produces same hash" || tt.name == "success: different content produces different hash" {
+			if tt.name == "success: same content produces same hash" || tt.name == "success: different content produces different hash" ||
+				tt.name == "success: files excluded by .skillignore do not affect hash" {
 				tt.checkFunc(t, nil, nil)
 				return
 			}
@@ -168,7 +204,7 @@ func TestDirhash_CalculateHash(t *testing.T) {
 
 			svc := NewDirhash()
 			ctx := context.Background()
-			result, err := svc.CalculateHash(ctx, dirPath)
+			result, err := svc.CalculateHash(ctx, dirPath, port.HashAlgorithmH1)
 
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("Expected error: %v, got: %v", tt.wantErr, err)
@@ -181,8 +217,229 @@ func TestDirhash_CalculateHash(t *testing.T) {
 	}
 }
 
+// TestDirhash_CalculateHash_Algorithms tests how line endings affect each hash algorithm
+func TestDirhash_CalculateHash_Algorithms(t *testing.T) {
+	tests := []struct {
+		name         string
+		algorithm    string
+		wantPrefix   string
+		wantSameHash bool
+		wantErr      bool
+	}{
+		{
+			name:         "h1: CRLF and LF content produce different hashes",
+			algorithm:    port.HashAlgorithmH1,
+			wantPrefix:   "h1:",
+			wantSameHash: false,
+		},
+		{
+			name:         "n1: CRLF and LF content produce the same hash",
+			algorithm:    port.HashAlgorithmN1,
+			wantPrefix:   "n1:",
+			wantSameHash: true,
+		},
+		{
+			name:      "error: unsupported algorithm",
+			algorithm: "md5",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lfDir := t.TempDir()
+			crlfDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(lfDir, "SKILL.md"), []byte("# Skill\nline\n"), 0o644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(crlfDir, "SKILL.md"), []byte("# Skill\r\nline\r\n"), 0o600); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			svc := NewDirhash()
+			ctx := context.Background()
+			lfResult, lfErr := svc.CalculateHash(ctx, lfDir, tt.algorithm)
+			crlfResult, crlfErr := svc.CalculateHash(ctx, crlfDir, tt.algorithm)
+
+			if tt.wantErr {
+				if lfErr == nil || crlfErr == nil {
+					t.Fatalf("Expected errors for algorithm %q, got: %v, %v", tt.algorithm, lfErr, crlfErr)
+				}
+				return
+			}
+			if lfErr != nil || crlfErr != nil {
+				t.Fatalf("Unexpected errors: %v, %v", lfErr, crlfErr)
+			}
+
+			if !strings.HasPrefix(lfResult.Value, tt.wantPrefix) {
+				t.Errorf("Expected hash with prefix %s, got: %s", tt.wantPrefix, lfResult.Value)
+			}
+			if (lfResult.Value == crlfResult.Value) != tt.wantSameHash {
+				t.Errorf("Expected same hash: %v, got: %s and %s", tt.wantSameHash, lfResult.Value, crlfResult.Value)
+			}
+		})
+	}
+}
+
+func TestDirhash_CalculateHash_IgnoresBanner(t *testing.T) {
+	skill := &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git"}
+	content := []byte("---\nname: my-skill\n---\n# Skill\n")
+
+	for _, algorithm := range []string{port.HashAlgorithmH1, port.HashAlgorithmN1} {
+		t.Run(algorithm, func(t *testing.T) {
+			plainDir := t.TempDir()
+			bannerDir := t.TempDir()
+			editedDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(plainDir, "SKILL.md"), content, 0o644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			withBanner := domain.InsertBanner(content, domain.Banner(skill, "v1.0.0"))
+			if err := os.WriteFile(filepath.Join(bannerDir, "SKILL.md"), withBanner, 0o644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(editedDir, "SKILL.md"), append(withBanner, "edited\n"...), 0o644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			svc := NewDirhash()
+			ctx := context.Background()
+			hashes := make([]string, 0, 3)
+			for _, dir := range []string{plainDir, bannerDir, editedDir} {
+				result, err := svc.CalculateHash(ctx, dir, algorithm, port.StripBanner())
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				hashes = append(hashes, result.Value)
+			}
+			// Without the banner enabled, the line is part of the skill like any other
+			kept, err := svc.CalculateHash(ctx, bannerDir, algorithm)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if hashes[0] != hashes[1] {
+				t.Errorf("Expected the banner to be excluded from the hash, got %s and %s", hashes[0], hashes[1])
+			}
+			if hashes[0] == hashes[2] {
+				t.Errorf("Expected edits besides the banner to change the hash")
+			}
+			if kept.Value == hashes[0] {
+				t.Errorf("Expected the banner to be hashed when it is not stripped")
+			}
+		})
+	}
+}
+
+func TestDirhash_CalculateHash_Exclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		changed  string
+		exclude  []string
+		wantSame bool
+	}{
+		{name: "no patterns", changed: "state.lock", wantSame: false},
+		{name: "extension pattern", changed: "state.lock", exclude: []string{"*.lock"}, wantSame: true},
+		{name: "nested extension pattern", changed: "data/state.lock", exclude: []string{"*.lock"}, wantSame: true},
+		{name: "directory glob", changed: "cache/a/b.json", exclude: []string{"cache/**"}, wantSame: true},
+		{name: "directory pattern", changed: "cache/a/b.json", exclude: []string{"cache/"}, wantSame: true},
+		{name: "unmatched file", changed: "SKILL.md", exclude: []string{"*.lock", "cache/**"}, wantSame: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range []string{"SKILL.md", "state.lock", "data/state.lock", "cache/a/b.json"} {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte("original\n"), 0o644); err != nil {
+					t.Fatalf("Failed to create test file: %v", err)
+				}
+			}
+
+			svc := NewDirhash()
+			ctx := context.Background()
+			before, err := svc.CalculateHash(ctx, dir, port.HashAlgorithmH1, port.ExcludeFiles(tt.exclude...))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(tt.changed)), []byte("changed at runtime\n"), 0o644); err != nil {
+				t.Fatalf("Failed to change test file: %v", err)
+			}
+			after, err := svc.CalculateHash(ctx, dir, port.HashAlgorithmH1, port.ExcludeFiles(tt.exclude...))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if (before.Value == after.Value) != tt.wantSame {
+				t.Errorf("Expected same hash: %v, got: %s and %s", tt.wantSame, before.Value, after.Value)
+			}
+		})
+	}
+}
 
 // TestDirhash_ImplementsInterface verifies that Dirhash implements HashService
+func TestDirhash_CalculateHash_FileSystem(t *testing.T) {
+	files := map[string]string{"SKILL.md": "# Skill\n", "docs/guide.md": "guide\n", ".skillignore": "*.log\n", "debug.log": "log"}
+
+	dir := t.TempDir()
+	memory := filesystem.NewMemory()
+	memoryDir := filepath.Join(string(filepath.Separator), "skill")
+	for name, content := range files {
+		for _, fsys := range []port.FileSystem{port.OSFileSystem{}, memory} {
+			root := dir
+			if fsys == memory {
+				root = memoryDir
+			}
+			path := filepath.Join(root, filepath.FromSlash(name))
+			if err := fsys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsys.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	want, err := NewDirhash().CalculateHash(ctx, dir, port.HashAlgorithmH1)
+	if err != nil {
+		t.Fatalf("CalculateHash() error = %v", err)
+	}
+	got, err := NewDirhashFS(memory).CalculateHash(ctx, memoryDir, port.HashAlgorithmH1)
+	if err != nil {
+		t.Fatalf("CalculateHash() on memory error = %v", err)
+	}
+	if got.Value != want.Value {
+		t.Errorf("CalculateHash() on memory = %s, want %s as on disk", got.Value, want.Value)
+	}
+}
+
+func TestDirhash_CalculateHash_Bytes(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"SKILL.md": "# Skill\n", "docs/guide.md": "guide\r\n"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// With n1, the normalized contents are hashed
+	for algorithm, want := range map[string]int64{port.HashAlgorithmH1: 15, port.HashAlgorithmN1: 14} {
+		result, err := NewDirhash().CalculateHash(context.Background(), dir, algorithm)
+		if err != nil {
+			t.Fatalf("CalculateHash(%s) error = %v", algorithm, err)
+		}
+		if result.Bytes != want {
+			t.Errorf("CalculateHash(%s) hashed %d bytes, want %d", algorithm, result.Bytes, want)
+		}
+	}
+}
+
 func TestDirhash_ImplementsInterface(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// NarHash calculates the Nix hash of a directory: the SHA-256 of its Nix Archive (NAR) serialization,
+// in the SRI format used by the hash attribute of Nix fetchers (e.g., "sha256-<base64>").
+// Top-level entries named in exclude are left out, such as the .git directory
+// that pkgs.fetchgit removes from a checkout.
+func NarHash(dirPath string, exclude ...string) (string, error) {
+	info, err := os.Lstat(dirPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to access directory %s: %w", dirPath, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("path is not a directory: %s", dirPath)
+	}
+
+	h := sha256.New()
+	w := &narWriter{h: h}
+	w.str("nix-archive-1")
+	if err := w.directory(dirPath, exclude); err != nil {
+		return "", err
+	}
+
+	return "sha256-" + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// narWriter writes the NAR serialization of a file tree to a hash.
+type narWriter struct {
+	h hash.Hash
+}
+
+// str writes a NAR string: its length as a little-endian uint64, followed by its bytes padded to 8 bytes.
+func (w *narWriter) str(s string) {
+	w.length(uint64(len(s)))
+	_, _ = io.WriteString(w.h, s)
+	w.pad(uint64(len(s)))
+}
+
+func (w *narWriter) length(n uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], n)
+	_, _ = w.h.Write(buf[:])
+}
+
+func (w *narWriter) pad(n uint64) {
+	if rem := n % 8; rem != 0 {
+		_, _ = w.h.Write(make([]byte, 8-rem))
+	}
+}
+
+// node writes the file, symbolic link, or directory at path.
+func (w *narWriter) node(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("failed to access %s: %w", path, err)
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return fmt.Errorf("failed to read symbolic link %s: %w", path, err)
+		}
+		w.str("(")
+		w.str("type")
+		w.str("symlink")
+		w.str("target")
+		w.str(target)
+		w.str(")")
+		return nil
+	case info.IsDir():
+		return w.directory(path, nil)
+	case info.Mode().IsRegular():
+		return w.regular(path, info)
+	default:
+		return fmt.Errorf("unsupported file type at %s", path)
+	}
+}
+
+func (w *narWriter) regular(path string, info os.FileInfo) error {
+	w.str("(")
+	w.str("type")
+	w.str("regular")
+	if info.Mode()&0o111 != 0 {
+		w.str("executable")
+		w.str("")
+	}
+	w.str("contents")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	w.length(uint64(info.Size()))
+	n, err := io.Copy(w.h, f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if n != info.Size() {
+		return fmt.Errorf("file %s changed while hashing", path)
+	}
+	w.pad(uint64(n))
+	w.str(")")
+
+	return nil
+}
+
+func (w *narWriter) directory(path string, exclude []string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", path, err)
+	}
+	// NAR entries are sorted by the bytes of their names
+	slices.SortFunc(entries, func(a, b os.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+
+	w.str("(")
+	w.str("type")
+	w.str("directory")
+	for _, entry := range entries {
+		if slices.Contains(exclude, entry.Name()) {
+			continue
+		}
+		w.str("entry")
+		w.str("(")
+		w.str("name")
+		w.str(entry.Name())
+		w.str("node")
+		if err := w.node(filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+		w.str(")")
+	}
+	w.str(")")
+
+	return nil
+}
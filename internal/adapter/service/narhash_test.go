@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// narString encodes s as a NAR string.
+func narString(s string) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(s)))
+	buf.WriteString(s)
+	if rem := len(s) % 8; rem != 0 {
+		buf.Write(make([]byte, 8-rem))
+	}
+	return buf.Bytes()
+}
+
+func narStrings(parts ...string) []byte {
+	var buf bytes.Buffer
+	for _, p := range parts {
+		buf.Write(narString(p))
+	}
+	return buf.Bytes()
+}
+
+func TestNarHash(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits and symbolic links are not portable to Windows")
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# Skill\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("SKILL.md", filepath.Join(dir, "README.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries are sorted by name: README.md, SKILL.md, scripts
+	nar := narStrings("nix-archive-1", "(", "type", "directory",
+		"entry", "(", "name", "README.md", "node",
+		"(", "type", "symlink", "target", "SKILL.md", ")",
+		")",
+		"entry", "(", "name", "SKILL.md", "node",
+		"(", "type", "regular", "contents", "# Skill\n", ")",
+		")",
+		"entry", "(", "name", "scripts", "node",
+		"(", "type", "directory",
+		"entry", "(", "name", "run.sh", "node",
+		"(", "type", "regular", "executable", "", "contents", "echo\n", ")",
+		")",
+		")",
+		")",
+		")",
+	)
+	sum := sha256.Sum256(nar)
+	want := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	got, err := NarHash(dir, ".git")
+	if err != nil {
+		t.Fatalf("NarHash() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("NarHash() = %s, want %s", got, want)
+	}
+
+	withGit, err := NarHash(dir)
+	if err != nil {
+		t.Fatalf("NarHash() error = %v", err)
+	}
+	if withGit == got {
+		t.Error("NarHash() without exclusions should include the .git directory")
+	}
+}
+
+func TestNarHash_NotDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NarHash(file); err == nil {
+		t.Error("NarHash() on a file should return an error")
+	}
+	if _, err := NarHash(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("NarHash() on a missing directory should return an error")
+	}
+}
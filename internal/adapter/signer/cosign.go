@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// Cosign signs and verifies blobs with the cosign tool, which must be on the PATH.
+// Keys are anything 'cosign sign-blob --key' accepts, such as key files and KMS URIs;
+// cosign reads the password of encrypted keys from COSIGN_PASSWORD.
+type Cosign struct {
+	command string
+}
+
+// NewCosign creates a new Cosign instance.
+func NewCosign() *Cosign {
+	return &Cosign{command: "cosign"}
+}
+
+// Method returns "cosign".
+func (c *Cosign) Method() string {
+	return "cosign"
+}
+
+// SignatureExt returns ".sig".
+func (c *Cosign) SignatureExt() string {
+	return ".sig"
+}
+
+// Sign runs 'cosign sign-blob' on message and returns the base64 signature it writes.
+func (c *Cosign) Sign(ctx context.Context, message []byte, key string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "skills-pkg-cosign-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	blob := filepath.Join(dir, "blob")
+	if err := os.WriteFile(blob, message, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write the content to sign: %w", err)
+	}
+	signature := filepath.Join(dir, "blob.sig")
+
+	if _, err := c.run(ctx, "sign-blob", "--yes", "--tlog-upload=false", "--key", key, "--output-signature", signature, blob); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the signature written by cosign: %w", err)
+	}
+	return data, nil
+}
+
+// Verify runs 'cosign verify-blob' with the public key.
+func (c *Cosign) Verify(ctx context.Context, message, signature []byte, publicKey string) error {
+	dir, err := os.MkdirTemp("", "skills-pkg-cosign-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	blob := filepath.Join(dir, "blob")
+	if err := os.WriteFile(blob, message, 0o600); err != nil {
+		return fmt.Errorf("failed to write the signed content: %w", err)
+	}
+	signatureFile := filepath.Join(dir, "blob.sig")
+	if err := os.WriteFile(signatureFile, signature, 0o600); err != nil {
+		return fmt.Errorf("failed to write the signature: %w", err)
+	}
+
+	output, err := c.run(ctx, "verify-blob", "--insecure-ignore-tlog=true", "--key", publicKey, "--signature", signatureFile, blob)
+	if _, ok := errors.AsType[*exec.ExitError](err); ok && ctx.Err() == nil {
+		return fmt.Errorf("%w: %s", port.ErrSignatureInvalid, output)
+	}
+	return err
+}
+
+// run runs cosign with the arguments and returns its combined output.
+func (c *Cosign) run(ctx context.Context, args ...string) (string, error) {
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.command, args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("cosign is not installed: install it from https://docs.sigstore.dev/cosign/system_config/installation/ or use minisign signing: %w", err)
+		}
+		if _, ok := errors.AsType[*exec.ExitError](err); ok {
+			return string(bytes.TrimSpace(output.Bytes())), fmt.Errorf("cosign %s failed: %s: %w", args[0], bytes.TrimSpace(output.Bytes()), err)
+		}
+		return "", fmt.Errorf("failed to run cosign: %w", err)
+	}
+
+	return string(bytes.TrimSpace(output.Bytes())), nil
+}
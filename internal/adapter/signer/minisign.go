@@ -0,0 +1,246 @@
+// Package signer provides implementations of the Signer interface.
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/scrypt"
+)
+
+// MinisignPasswordEnv is the environment variable holding the password of encrypted minisign secret keys.
+const MinisignPasswordEnv = "SKILLSPKG_SIGNING_PASSWORD"
+
+// Signature algorithms of minisign: Ed25519 over the message, or over its BLAKE2b-512 hash.
+const (
+	minisignAlgorithm       = "Ed"
+	minisignHashedAlgorithm = "ED"
+)
+
+// Sizes of the fields of minisign keys and signatures.
+const (
+	minisignKeyIDSize     = 8
+	minisignPublicKeySize = 2 + minisignKeyIDSize + ed25519.PublicKeySize
+	minisignSignatureSize = 2 + minisignKeyIDSize + ed25519.SignatureSize
+	minisignSaltSize      = 32
+	minisignChecksumSize  = 32
+	// Key ID, secret key, and checksum, encrypted with the key derived from the password
+	minisignKeynumSize    = minisignKeyIDSize + ed25519.PrivateKeySize + minisignChecksumSize
+	minisignSecretKeySize = 2 + 2 + 2 + minisignSaltSize + 8 + 8 + minisignKeynumSize
+)
+
+// Minisign signs and verifies with minisign keys, without needing the minisign tool.
+// Signatures are compatible with 'minisign -V'.
+type Minisign struct{}
+
+// NewMinisign creates a new Minisign instance.
+func NewMinisign() *Minisign {
+	return &Minisign{}
+}
+
+// Method returns "minisign".
+func (m *Minisign) Method() string {
+	return "minisign"
+}
+
+// SignatureExt returns ".minisig".
+func (m *Minisign) SignatureExt() string {
+	return ".minisig"
+}
+
+// Sign signs the BLAKE2b-512 hash of message with the minisign secret key file at key.
+// Encrypted keys are decrypted with the password in SKILLSPKG_SIGNING_PASSWORD.
+func (m *Minisign) Sign(ctx context.Context, message []byte, key string) ([]byte, error) {
+	keyID, secretKey, err := readMinisignSecretKey(key, os.Getenv(MinisignPasswordEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	hash := blake2b.Sum512(message)
+	sig := ed25519.Sign(secretKey, hash[:])
+	trustedComment := fmt.Sprintf("timestamp:%d\tsigned by skills-pkg", time.Now().Unix())
+	globalSig := ed25519.Sign(secretKey, append(bytes.Clone(sig), trustedComment...))
+
+	signature := make([]byte, 0, minisignSignatureSize)
+	signature = append(signature, minisignHashedAlgorithm...)
+	signature = append(signature, keyID...)
+	signature = append(signature, sig...)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "untrusted comment: signature from skills-pkg secret key\n%s\n", base64.StdEncoding.EncodeToString(signature))
+	fmt.Fprintf(&out, "trusted comment: %s\n%s\n", trustedComment, base64.StdEncoding.EncodeToString(globalSig))
+	return out.Bytes(), nil
+}
+
+// Verify checks a minisign signature of message. publicKey is a public key file, or the base64
+// public key itself as printed by 'minisign -G'.
+func (m *Minisign) Verify(ctx context.Context, message, signature []byte, publicKey string) error {
+	keyID, key, err := readMinisignPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	lines := minisignLines(signature)
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("%w: not a minisign signature", port.ErrSignatureInvalid)
+	}
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != minisignSignatureSize {
+		return fmt.Errorf("%w: malformed minisign signature", port.ErrSignatureInvalid)
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed trusted comment signature", port.ErrSignatureInvalid)
+	}
+
+	if !bytes.Equal(sig[2:2+minisignKeyIDSize], keyID) {
+		return fmt.Errorf("%w: signed with key %X, not with the configured key %X", port.ErrSignatureInvalid, reverse(sig[2:2+minisignKeyIDSize]), reverse(keyID))
+	}
+	signed := message
+	switch string(sig[:2]) {
+	case minisignAlgorithm:
+	case minisignHashedAlgorithm:
+		hash := blake2b.Sum512(message)
+		signed = hash[:]
+	default:
+		return fmt.Errorf("%w: unsupported signature algorithm %q", port.ErrSignatureInvalid, sig[:2])
+	}
+	if !ed25519.Verify(key, signed, sig[2+minisignKeyIDSize:]) {
+		return fmt.Errorf("%w: the signature does not match the signed content", port.ErrSignatureInvalid)
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(key, append(bytes.Clone(sig[2+minisignKeyIDSize:]), trustedComment...), globalSig) {
+		return fmt.Errorf("%w: the trusted comment was modified", port.ErrSignatureInvalid)
+	}
+
+	return nil
+}
+
+// readMinisignPublicKey returns the key ID and the key of a minisign public key file, or of the
+// base64 public key in publicKey.
+func readMinisignPublicKey(publicKey string) ([]byte, ed25519.PublicKey, error) {
+	encoded := publicKey
+	if data, err := os.ReadFile(publicKey); err == nil {
+		lines := minisignLines(data)
+		if len(lines) == 0 {
+			return nil, nil, fmt.Errorf("minisign public key file %s is empty", publicKey)
+		}
+		encoded = lines[len(lines)-1]
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != minisignPublicKeySize || string(key[:2]) != minisignAlgorithm {
+		return nil, nil, fmt.Errorf("%s is neither a minisign public key nor a file containing one", publicKey)
+	}
+	return key[2 : 2+minisignKeyIDSize], ed25519.PublicKey(key[2+minisignKeyIDSize:]), nil
+}
+
+// readMinisignSecretKey returns the key ID and the secret key of the minisign secret key file at path,
+// decrypting it with password when it is encrypted.
+func readMinisignSecretKey(path, password string) ([]byte, ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read minisign secret key: %w", err)
+	}
+	lines := minisignLines(data)
+	if len(lines) == 0 {
+		return nil, nil, fmt.Errorf("minisign secret key file %s is empty", path)
+	}
+	key, err := base64.StdEncoding.DecodeString(lines[len(lines)-1])
+	if err != nil || len(key) != minisignSecretKeySize || string(key[:2]) != minisignAlgorithm {
+		return nil, nil, fmt.Errorf("%s is not a minisign secret key", path)
+	}
+
+	kdfAlgorithm := key[2:4]
+	salt := key[6 : 6+minisignSaltSize]
+	opsLimit := binary.LittleEndian.Uint64(key[6+minisignSaltSize:])
+	memLimit := binary.LittleEndian.Uint64(key[6+minisignSaltSize+8:])
+	keynum := bytes.Clone(key[6+minisignSaltSize+16:])
+
+	switch string(kdfAlgorithm) {
+	case "\x00\x00":
+	case "Sc":
+		if password == "" {
+			return nil, nil, fmt.Errorf("minisign secret key %s is encrypted: set %s to its password", path, MinisignPasswordEnv)
+		}
+		stream, err := minisignKDF(password, salt, opsLimit, memLimit)
+		if err != nil {
+			return nil, nil, err
+		}
+		subtle.XORBytes(keynum, keynum, stream)
+	default:
+		return nil, nil, fmt.Errorf("minisign secret key %s uses an unsupported key derivation %q", path, kdfAlgorithm)
+	}
+
+	keyID := keynum[:minisignKeyIDSize]
+	secretKey := keynum[minisignKeyIDSize : minisignKeyIDSize+ed25519.PrivateKeySize]
+	checksum := blake2b.Sum256(bytes.Join([][]byte{key[:2], keyID, secretKey}, nil))
+	if subtle.ConstantTimeCompare(checksum[:], keynum[minisignKeyIDSize+ed25519.PrivateKeySize:]) != 1 {
+		return nil, nil, fmt.Errorf("failed to decrypt minisign secret key %s: wrong password or corrupted key", path)
+	}
+
+	return keyID, ed25519.PrivateKey(secretKey), nil
+}
+
+// minisignKDF derives the stream that encrypts minisign secret keys, choosing the scrypt parameters
+// from the limits like libsodium's crypto_pwhash_scryptsalsa208sha256.
+func minisignKDF(password string, salt []byte, opsLimit, memLimit uint64) ([]byte, error) {
+	const r = 8
+	opsLimit = max(opsLimit, 32768)
+
+	var nLog2, p uint64
+	if opsLimit < memLimit/32 {
+		p = 1
+		maxN := opsLimit / (r * 4)
+		for nLog2 = 1; nLog2 < 63; nLog2++ {
+			if uint64(1)<<nLog2 > maxN/2 {
+				break
+			}
+		}
+	} else {
+		maxN := memLimit / (r * 128)
+		for nLog2 = 1; nLog2 < 63; nLog2++ {
+			if uint64(1)<<nLog2 > maxN/2 {
+				break
+			}
+		}
+		maxRP := min((opsLimit/4)/(uint64(1)<<nLog2), 0x3fffffff)
+		p = maxRP / r
+	}
+
+	stream, err := scrypt.Key([]byte(password), salt, 1<<nLog2, r, int(p), minisignKeynumSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the key of the minisign secret key: %w", err)
+	}
+	return stream, nil
+}
+
+// minisignLines returns the non-empty lines of a minisign key or signature file.
+func minisignLines(data []byte) []string {
+	var lines []string
+	for line := range strings.SplitSeq(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// reverse returns b in reverse order, as minisign prints key IDs as little-endian numbers.
+func reverse(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i, c := range b {
+		reversed[len(b)-1-i] = c
+	}
+	return reversed
+}
@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"golang.org/x/crypto/blake2b"
+)
+
+// writeMinisignKeys writes a new minisign key pair to dir, encrypting the secret key with password
+// unless it is empty, and returns the paths of the secret and public key files.
+func writeMinisignKeys(t *testing.T, dir, password string) (string, string) {
+	t.Helper()
+
+	publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := make([]byte, minisignKeyIDSize)
+	salt := make([]byte, minisignSaltSize)
+	_, _ = rand.Read(keyID)
+	_, _ = rand.Read(salt)
+
+	checksum := blake2b.Sum256(bytes.Join([][]byte{[]byte(minisignAlgorithm), keyID, secretKey}, nil))
+	keynum := bytes.Join([][]byte{keyID, secretKey, checksum[:]}, nil)
+	kdfAlgorithm := []byte{0, 0}
+	// Small limits keep the key derivation fast
+	opsLimit, memLimit := uint64(32768), uint64(32768*32+1)
+	if password != "" {
+		kdfAlgorithm = []byte("Sc")
+		stream, err := minisignKDF(password, salt, opsLimit, memLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subtle.XORBytes(keynum, keynum, stream)
+	}
+
+	secret := bytes.Join([][]byte{[]byte(minisignAlgorithm), kdfAlgorithm, []byte("B2"), salt,
+		binary.LittleEndian.AppendUint64(nil, opsLimit), binary.LittleEndian.AppendUint64(nil, memLimit), keynum}, nil)
+	public := bytes.Join([][]byte{[]byte(minisignAlgorithm), keyID, publicKey}, nil)
+
+	secretPath := filepath.Join(dir, "minisign.key")
+	publicPath := filepath.Join(dir, "minisign.pub")
+	if err := os.WriteFile(secretPath, []byte("untrusted comment: test secret key\n"+base64.StdEncoding.EncodeToString(secret)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, []byte("untrusted comment: test public key\n"+base64.StdEncoding.EncodeToString(public)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return secretPath, publicPath
+}
+
+func TestMinisign_SignAndVerify(t *testing.T) {
+	ctx := context.Background()
+	m := NewMinisign()
+	message := []byte("skills-pkg lock attestation v1\nskill\tgit\thttps://github.com/example/skill\tv1.0.0\th1:abc\n")
+
+	secretPath, publicPath := writeMinisignKeys(t, t.TempDir(), "")
+	signature, err := m.Sign(ctx, message, secretPath)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if err := m.Verify(ctx, message, signature, publicPath); err != nil {
+		t.Errorf("Verify() with the public key file error = %v", err)
+	}
+	publicFile, _ := os.ReadFile(publicPath)
+	inline := strings.TrimSpace(string(publicFile[bytes.IndexByte(publicFile, '\n')+1:]))
+	if err := m.Verify(ctx, message, signature, inline); err != nil {
+		t.Errorf("Verify() with the inline public key error = %v", err)
+	}
+
+	_, otherPublic := writeMinisignKeys(t, t.TempDir(), "")
+	tampered := bytes.Replace(signature, []byte("signed by skills-pkg"), []byte("signed by someone"), 1)
+	for name, check := range map[string]func() error{
+		"modified message":         func() error { return m.Verify(ctx, append(bytes.Clone(message), 'x'), signature, publicPath) },
+		"other key":                func() error { return m.Verify(ctx, message, signature, otherPublic) },
+		"modified trusted comment": func() error { return m.Verify(ctx, message, tampered, publicPath) },
+		"not a signature":          func() error { return m.Verify(ctx, message, []byte("garbage"), publicPath) },
+	} {
+		if err := check(); !errors.Is(err, port.ErrSignatureInvalid) {
+			t.Errorf("Verify() with %s error = %v, want ErrSignatureInvalid", name, err)
+		}
+	}
+}
+
+func TestMinisign_EncryptedKey(t *testing.T) {
+	ctx := context.Background()
+	m := NewMinisign()
+	message := []byte("message")
+	secretPath, publicPath := writeMinisignKeys(t, t.TempDir(), "correct horse")
+
+	t.Setenv(MinisignPasswordEnv, "")
+	if _, err := m.Sign(ctx, message, secretPath); err == nil || !strings.Contains(err.Error(), MinisignPasswordEnv) {
+		t.Errorf("Sign() without a password error = %v, want it to ask for %s", err, MinisignPasswordEnv)
+	}
+
+	t.Setenv(MinisignPasswordEnv, "wrong")
+	if _, err := m.Sign(ctx, message, secretPath); err == nil {
+		t.Error("Sign() with a wrong password succeeded")
+	}
+
+	t.Setenv(MinisignPasswordEnv, "correct horse")
+	signature, err := m.Sign(ctx, message, secretPath)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if err := m.Verify(ctx, message, signature, publicPath); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
-	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
@@ -19,12 +18,27 @@ import (
 
 // AddCmd represents the add command
 type AddCmd struct {
-	Name           string `arg:"" help:"Skill name"`
-	Source         string `default:"git" enum:"git,go-mod" help:"Source type"`
-	URL            string `required:"" help:"Source URL (Git URL or Go module path)"`
-	Version        string `default:"" help:"Version (tag, commit hash, or semantic version; defaults to version from go.mod for go-module, otherwise latest)"`
-	SubDir         string `help:"Subdirectory within the source to extract (default: skills/{name})"`
-	PrintSkillInfo bool   `name:"print-skill-info" help:"After installation, print skill metadata in agent-readable format"`
+	Name           string   `arg:"" help:"Skill name"`
+	Source         string   `default:"git" enum:"git,go-mod,npm,oci,archive,local" help:"Source type"`
+	URL            string   `required:"" help:"Source URL (Git URL, Go module path, npm package name, OCI repository, archive URL, or local directory)"`
+	Version        string   `default:"" help:"Version (tag, commit hash, or semantic version; defaults follow the [defaults] section of the configuration)"`
+	SubDir         string   `xor:"subdir" help:"Subdirectory within the source to extract (default: skills/{name}, or the directory itself for local sources)"`
+	SubDirs        []string `name:"sub-dirs" xor:"subdir" help:"Subdirectories or glob patterns within the source, each installed as a separate skill from one download (repeatable)"`
+	VerifyIgnore   []string `name:"verify-ignore" help:"Gitignore-style pattern of files the agent changes at runtime, left out of hash verification (repeatable)"`
+	Preserve       []string `help:"Gitignore-style pattern of files the agent writes into the installed skill, kept across updates and left out of hash verification (repeatable)"`
+	InstallAs      string   `name:"install-as" help:"Directory name in the install targets (default: the skill name)"`
+	SHA256         string   `name:"sha256" help:"SHA-256 digest of the file an archive source downloads, in hexadecimal"`
+	Link           bool     `help:"Install a local skill as symbolic links to its directory, so that edits apply without reinstalling"`
+	Insecure       bool     `help:"Exempt the skill from the hash_mismatch policy and frozen and signed lock files, for trusted sources that cannot satisfy them"`
+	PrintSkillInfo bool     `name:"print-skill-info" xor:"install" help:"After installation, print skill metadata in agent-readable format"`
+	NoInstall      bool     `name:"no-install" xor:"install" help:"Only record the skill in the configuration; install it later with 'skills-pkg install --only-new'"`
+	IfAbsent       bool     `name:"if-absent" xor:"existing" help:"Succeed without changes when the skill already exists with the same source, URL, subdirectory, and version"`
+	Force          bool     `xor:"existing" help:"Replace an existing skill with the same name and reinstall it"`
+	WithDeps       bool     `name:"with-dependencies" help:"Add the skills that the skill declares as dependencies in its SKILL.md to the configuration without asking"`
+
+	allowRoot     bool      // Set from the global --allow-root flag
+	downloadCache bool      // Set by Run to reuse downloads from the user cache directory
+	stdin         io.Reader // Set by Run when the user can answer confirmation prompts
 }
 
 // Run executes the add command
@@ -34,11 +48,18 @@ func (c *AddCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+	c.stdin = confirmInput()
+	if c.Source == "local" {
+		c.URL = projectPath(c.URL)
+	}
+
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -48,10 +69,7 @@ func (c *AddCmd) Run(ctx *kong.Context) error {
 func (c *AddCmd) run(configPath string, verbose bool) error {
 	// Create default dependencies
 	hashService := service.NewDirhash()
-	packageManagers := []port.PackageManager{
-		pkgmanager.NewGit(),
-		pkgmanager.NewGoMod(),
-	}
+	packageManagers := pkgmanager.All()
 
 	return c.runWithDeps(configPath, verbose, hashService, packageManagers)
 }
@@ -70,33 +88,49 @@ func (c *AddCmd) runWithDeps(configPath string, verbose bool, hashService port.H
 	// Note: Source type validation is now handled by kong's enum tag (requirement 6.3)
 
 	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
+	configManager := newConfigManager(configPath)
 
-	// Determine SubDir (default: skills/{name})
+	// Determine SubDir (default: skills/{name}, or the directory itself for local sources)
 	subDir := c.SubDir
-	if subDir == "" {
+	if subDir == "" && len(c.SubDirs) == 0 && c.Source != "local" {
 		subDir = fmt.Sprintf("skills/%s", c.Name)
 		logger.Verbose("Using default subdirectory: %s", subDir)
 	}
 
 	// Create skill entry
 	skill := &domain.Skill{
-		Name:      c.Name,
-		Source:    c.Source,
-		URL:       c.URL,
-		Version:   c.Version,
-		HashValue: "", // Hash will be set during installation
-		SubDir:    subDir,
+		Name:         c.Name,
+		Source:       c.Source,
+		URL:          c.URL,
+		Version:      c.Version,
+		HashValue:    "", // Hash will be set during installation
+		SubDir:       subDir,
+		SubDirs:      c.SubDirs,
+		VerifyIgnore: c.VerifyIgnore,
+		Preserve:     c.Preserve,
+		InstallAs:    c.InstallAs,
+		SHA256:       c.SHA256,
+		Insecure:     c.Insecure,
+		Link:         c.Link,
 	}
 
 	logger.Verbose("Created skill entry: %+v", skill)
 
-	// Install the skill after adding to configuration
-	logger.Info("Installing skill '%s'", c.Name)
-	logger.Verbose("Starting installation process")
-
 	// Add skill to config in memory (requirement 6.3)
-	config, err := configManager.AddSkillToConfig(context.Background(), skill)
+	addSkillToConfig := configManager.AddSkillToConfig
+	if c.Force {
+		addSkillToConfig = configManager.ReplaceSkillInConfig
+		// Keep the verification exemptions and preserved files of the replaced entry unless new ones are given
+		if existing := c.existingSkill(configManager); existing != nil {
+			if len(skill.VerifyIgnore) == 0 {
+				skill.VerifyIgnore = existing.VerifyIgnore
+			}
+			if len(skill.Preserve) == 0 {
+				skill.Preserve = existing.Preserve
+			}
+		}
+	}
+	config, err := addSkillToConfig(context.Background(), skill)
 	if err != nil {
 		// Handle different error types with appropriate messages (requirements 12.2, 12.3)
 		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
@@ -107,16 +141,32 @@ func (c *AddCmd) runWithDeps(configPath string, verbose bool, hashService port.H
 		}
 
 		if e, ok := errors.AsType[*domain.ErrorSkillExists](err); ok {
+			if c.IfAbsent && c.existingSatisfies(configManager, skill) {
+				logger.Verbose("Skill '%s' already exists in configuration with the same source", e.SkillName)
+				return nil
+			}
+
 			// Duplicate skill name (requirement 6.3)
-			logger.Error("Skill '%s' already exists in configuration", e.SkillName)
-			logger.Error("Use 'skills-pkg update' to update an existing skill or choose a different name")
+			if c.IfAbsent {
+				logger.Error("Skill '%s' already exists in configuration with a different source, URL, subdirectory, or version", e.SkillName)
+			} else {
+				logger.Error("Skill '%s' already exists in configuration", e.SkillName)
+			}
+			logger.Error("Use --force to replace it, 'skills-pkg update' to update it, or choose a different name")
 			return err
 		}
 
 		if e, ok := errors.AsType[*domain.ErrorInvalidSource](err); ok {
 			// Invalid source type
 			logger.Error("Invalid source type '%s'", e.SourceType)
-			logger.Error("Supported source types: git, go-mod")
+			logger.Error("Supported source types: git, go-mod, npm, oci, archive, local")
+			return err
+		}
+
+		if e, ok := errors.AsType[*domain.ErrorInstallDirConflict](err); ok {
+			// Another skill is installed into the same directory
+			logger.Error("%v", e)
+			logger.Error("Use --install-as to install the skill into another directory")
 			return err
 		}
 
@@ -126,15 +176,34 @@ func (c *AddCmd) runWithDeps(configPath string, verbose bool, hashService port.H
 		return err
 	}
 
+	// Record the skill without installing it, leaving the installation to a later step
+	if c.NoInstall {
+		if err := configManager.Save(context.Background(), config); err != nil {
+			logger.Error("Failed to save configuration: %v", err)
+			logger.Error("Check file permissions and try again")
+			return err
+		}
+		logger.Info("Added skill '%s' to configuration without installing it", c.Name)
+		logger.Info("Run 'skills-pkg install --only-new' to install it")
+		return nil
+	}
+
+	// Install the skill after adding to configuration
+	logger.Info("Installing skill '%s'", c.Name)
+	logger.Verbose("Starting installation process")
+
 	// Create SkillManager
-	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers)
+	opts := append(skillManagerOptions(c.allowRoot, c.downloadCache), dependencyApproval(logger, c.stdin, c.WithDeps))
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, opts...)
 
 	// Install the specific skill (this will save the configuration with hash values)
 	if err := skillManager.InstallSingleSkill(context.Background(), config, skill, true); err != nil {
 		// Handle installation errors (requirements 12.2, 12.3)
 		logger.Error("Failed to install skill '%s': %v", c.Name, err)
 		logger.Error("The skill has NOT been added to configuration due to installation failure")
-		logger.Error("Please check the error and try again")
+		if !handlePermissionError(logger, err) {
+			logger.Error("Please check the error and try again")
+		}
 		return fmt.Errorf("installation failed: %w", err)
 	}
 
@@ -142,8 +211,8 @@ func (c *AddCmd) runWithDeps(configPath string, verbose bool, hashService port.H
 	logger.Info("Successfully installed skill '%s'", c.Name)
 
 	// Print skill info for agent awareness if requested
-	if c.PrintSkillInfo && len(config.InstallTargets) > 0 {
-		skillMDPath := filepath.Join(config.InstallTargets[0], c.Name, "SKILL.md")
+	if localTargets := config.LocalInstallTargets(); c.PrintSkillInfo && len(localTargets) > 0 {
+		skillMDPath := filepath.Join(localTargets[0], c.Name, "SKILL.md")
 		if err := printSkillAgentInfo(os.Stdout, c.Name, skillMDPath); err != nil {
 			logger.Verbose("Could not read SKILL.md for agent info: %v", err)
 		}
@@ -152,6 +221,25 @@ func (c *AddCmd) runWithDeps(configPath string, verbose bool, hashService port.H
 	return nil
 }
 
+// existingSatisfies reports whether the configured skill with the same name fulfills the requested skill.
+func (c *AddCmd) existingSatisfies(configManager *domain.ConfigManager, skill *domain.Skill) bool {
+	config, err := configManager.Load(context.Background())
+	if err != nil {
+		return false
+	}
+	existing := config.FindSkillByName(skill.Name)
+	return existing != nil && existing.Satisfies(skill)
+}
+
+// existingSkill returns the configured skill with the same name, or nil if there is none.
+func (c *AddCmd) existingSkill(configManager *domain.ConfigManager) *domain.Skill {
+	config, err := configManager.Load(context.Background())
+	if err != nil {
+		return nil
+	}
+	return config.FindSkillByName(c.Name)
+}
+
 // skillAgentInfoHowToUse is the "How to use skills" guidelines from Codex's render_skills_section.
 const skillAgentInfoHowToUse = `- Discovery: The list above is the skills available in this session (name + description + file path). Skill bodies live on disk at the listed paths.
 - Trigger rules: If the user names a skill (with $SkillName or plain text) OR the task clearly matches a skill's description shown above, you must use that skill for that turn. Multiple mentions mean use them all. Do not carry skills across turns unless re-mentioned.
@@ -178,38 +266,15 @@ func printSkillAgentInfo(w io.Writer, skillName, skillMDPath string) error {
 		return fmt.Errorf("read SKILL.md: %w", err)
 	}
 
-	name, description := parseSkillFrontmatter(string(data))
-	if name == "" {
-		name = skillName
+	name, description := skillName, ""
+	if metadata, ok, err := domain.ParseSkillFrontmatter(data); ok && err == nil {
+		if metadata.Name != "" {
+			name = metadata.Name
+		}
+		description = metadata.Description
 	}
 
 	_, err = fmt.Fprintf(w, "\n## Skills\n\n### Installed skill\n\n- %s: %s (file: %s)\n\n### How to use skills\n%s\n",
 		name, description, skillMDPath, skillAgentInfoHowToUse)
 	return err
 }
-
-// parseSkillFrontmatter extracts name and description from SKILL.md YAML frontmatter.
-func parseSkillFrontmatter(content string) (name, description string) {
-	const delim = "---\n"
-	if !strings.HasPrefix(content, delim) {
-		return
-	}
-	rest := content[len(delim):]
-	front, _, ok := strings.Cut(rest, "\n---")
-	if !ok {
-		return
-	}
-	for line := range strings.SplitSeq(front, "\n") {
-		key, val, ok := strings.Cut(line, ": ")
-		if !ok {
-			continue
-		}
-		switch key {
-		case "name":
-			name = val
-		case "description":
-			description = val
-		}
-	}
-	return
-}
@@ -14,9 +14,10 @@ import (
 
 // AddInstallTargetCmd represents the add-install-target command
 type AddInstallTargetCmd struct {
-	Target []string `arg:"" optional:"" help:"Install target directory path (can be specified multiple times)"`
-	Agent  []string `help:"Agent name to use default directory (can be specified multiple times)" short:"a" enum:"claude,codex,cursor,copilot,goose,opencode,gemini,amp,factory"`
-	Global bool     `help:"Use user-level directory instead of project-level directory (requires --agent)" short:"g" default:"false"`
+	Target       []string `arg:"" optional:"" help:"Install target directory path (can be specified multiple times)"`
+	Agent        []string `help:"Agent name to use default directory (can be specified multiple times)" short:"a" enum:"claude,codex,cursor,copilot,goose,opencode,gemini,amp,factory"`
+	Global       bool     `help:"Use user-level directory instead of project-level directory (requires --agent)" short:"g" default:"false"`
+	CheckTargets bool     `help:"Warn about install targets that do not look like the skills directory of an installed agent" name:"check-targets" default:"false"`
 }
 
 // Run executes the add-install-target command
@@ -25,11 +26,14 @@ func (c *AddInstallTargetCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	for i, target := range c.Target {
+		c.Target[i] = projectPath(target)
+	}
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -48,7 +52,11 @@ func (c *AddInstallTargetCmd) run(configPath string, verbose bool) error {
 
 	logger.Verbose("Install targets to add: %v", targets)
 
-	configManager := domain.NewConfigManager(configPath)
+	if c.CheckTargets {
+		warnTargets(logger, targets, agent.All())
+	}
+
+	configManager := newConfigManager(configPath)
 
 	for _, target := range targets {
 		logger.Info("Adding install target '%s' to configuration", target)
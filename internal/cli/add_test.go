@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
@@ -14,7 +15,7 @@ import (
 // mockHashService is a mock implementation of port.HashService for testing
 type mockHashService struct{}
 
-func (m *mockHashService) CalculateHash(ctx context.Context, path string) (*port.HashResult, error) {
+func (m *mockHashService) CalculateHash(ctx context.Context, path string, algorithm string, opts ...port.HashOption) (*port.HashResult, error) {
 	return &port.HashResult{
 		Value:     "mock-hash-value",
 	}, nil
@@ -328,3 +329,180 @@ func TestAddCmd_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestAddCmd_Run_ExistingSkill(t *testing.T) {
+	t.Parallel()
+
+	existing := &domain.Skill{
+		Name:         "example-skill",
+		Source:       "git",
+		URL:          "https://github.com/example/skill.git",
+		Version:      "v1.0.0",
+		HashValue:    "h1:existing",
+		SubDir:       "skills/example-skill",
+		VerifyIgnore: []string{"*.lock"},
+		Preserve:     []string{"state/"},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		version     string
+		wantVersion string
+		wantHash    string
+		ifAbsent    bool
+		force       bool
+		wantErr     bool
+	}{
+		{
+			name:    "error: duplicate without flags",
+			url:     existing.URL,
+			version: "v1.0.0",
+			wantErr: true,
+		},
+		{
+			name:        "if-absent: identical entry succeeds without changes",
+			url:         existing.URL,
+			version:     "v1.0.0",
+			ifAbsent:    true,
+			wantVersion: "v1.0.0",
+			wantHash:    "h1:existing",
+		},
+		{
+			name:        "if-absent: omitted version accepts the pinned version",
+			url:         existing.URL,
+			ifAbsent:    true,
+			wantVersion: "v1.0.0",
+			wantHash:    "h1:existing",
+		},
+		{
+			name:     "if-absent: different version fails",
+			url:      existing.URL,
+			version:  "v2.0.0",
+			ifAbsent: true,
+			wantErr:  true,
+		},
+		{
+			name:     "if-absent: different URL fails",
+			url:      "https://github.com/other/skill.git",
+			version:  "v1.0.0",
+			ifAbsent: true,
+			wantErr:  true,
+		},
+		{
+			name:        "force: replaces the entry and reinstalls",
+			url:         "https://github.com/other/skill.git",
+			version:     "v2.0.0",
+			force:       true,
+			wantVersion: "v2.0.0",
+			wantHash:    "mock-hash-value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath, cleanup := setupTestConfig(t)
+			defer cleanup()
+
+			cm := domain.NewConfigManager(configPath)
+			other := &domain.Skill{Name: "other-skill", Source: "git", URL: "https://github.com/example/other.git", Version: "v1.0.0"}
+			skill := *existing
+			if err := cm.AddSkill(context.Background(), &skill); err != nil {
+				t.Fatalf("failed to add existing skill: %v", err)
+			}
+			if err := cm.AddSkill(context.Background(), other); err != nil {
+				t.Fatalf("failed to add other skill: %v", err)
+			}
+
+			tmpDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(tmpDir, existing.SubDir), 0o755); err != nil {
+				t.Fatalf("failed to create subdirectory: %v", err)
+			}
+
+			cmd := &AddCmd{
+				Name:     existing.Name,
+				Source:   "git",
+				URL:      tt.url,
+				Version:  tt.version,
+				IfAbsent: tt.ifAbsent,
+				Force:    tt.force,
+			}
+			err := cmd.runWithDeps(configPath, false, &mockHashService{}, []port.PackageManager{
+				&mockPackageManager{sourceType: "git", tmpDir: tmpDir},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithDeps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if _, ok := errors.AsType[*domain.ErrorSkillExists](err); !ok {
+					t.Errorf("expected ErrorSkillExists, got %v", err)
+				}
+				return
+			}
+
+			config, err := cm.Load(context.Background())
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			if len(config.Skills) != 2 || config.Skills[0].Name != existing.Name {
+				t.Fatalf("expected the skill to stay first of 2 skills, got %+v", config.Skills)
+			}
+			got := config.Skills[0]
+			if got.URL != tt.url {
+				t.Errorf("URL = %s, want %s", got.URL, tt.url)
+			}
+			if got.Version != tt.wantVersion {
+				t.Errorf("Version = %s, want %s", got.Version, tt.wantVersion)
+			}
+			if got.HashValue != tt.wantHash {
+				t.Errorf("HashValue = %s, want %s", got.HashValue, tt.wantHash)
+			}
+			if !slices.Equal(got.VerifyIgnore, existing.VerifyIgnore) {
+				t.Errorf("VerifyIgnore = %v, want %v", got.VerifyIgnore, existing.VerifyIgnore)
+			}
+			if !slices.Equal(got.Preserve, existing.Preserve) {
+				t.Errorf("Preserve = %v, want %v", got.Preserve, existing.Preserve)
+			}
+		})
+	}
+}
+
+func TestAddCmd_Run_NoInstall(t *testing.T) {
+	t.Parallel()
+
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cmd := &AddCmd{
+		Name:      "example-skill",
+		Source:    "git",
+		URL:       "https://github.com/example/skill.git",
+		Version:   "v1.0.0",
+		NoInstall: true,
+	}
+	// Without package managers, installing the skill would fail
+	err := cmd.runWithDeps(configPath, false, &mockHashService{}, nil)
+	if err != nil {
+		t.Fatalf("runWithDeps() error = %v", err)
+	}
+
+	config, err := domain.NewConfigManager(configPath).Load(context.Background())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	skill := config.FindSkillByName("example-skill")
+	if skill == nil {
+		t.Fatal("expected the skill to be recorded in the configuration")
+	}
+	if skill.HashValue != "" {
+		t.Errorf("HashValue = %s, want empty", skill.HashValue)
+	}
+	if skill.SubDir != "skills/example-skill" {
+		t.Errorf("SubDir = %s, want skills/example-skill", skill.SubDir)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "install", "example-skill")); !os.IsNotExist(err) {
+		t.Errorf("expected the skill not to be installed, stat error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// ApplyCmd represents the apply command
+type ApplyCmd struct {
+	Plan string `arg:"" type:"path" help:"Plan file written by 'skills-pkg plan --out'"`
+
+	allowRoot     bool // Set from the global --allow-root flag
+	downloadCache bool // Set by Run to reuse downloads from the user cache directory
+}
+
+// Run executes the apply command
+func (c *ApplyCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *ApplyCmd) run(configPath string, verbose bool) error {
+	packageManagers := pkgmanager.All()
+
+	return c.runWithDeps(configPath, NewLogger(verbose), service.NewDirhash(), packageManagers)
+}
+
+// runWithDeps executes the saved plan with the given dependencies (for testing)
+func (c *ApplyCmd) runWithDeps(configPath string, logger *Logger, hashService port.HashService, packageManagers []port.PackageManager) error {
+	ctx := context.Background()
+	notifier := newOperationNotifier(logger)
+
+	plan, err := domain.ReadPlan(c.Plan)
+	if err != nil {
+		logger.Error("%v", err)
+		logger.Error("Run 'skills-pkg plan --out %s' to create a plan", c.Plan)
+		return err
+	}
+
+	// Refuse to run anything but the reviewed changes
+	configManager := newConfigManager(configPath)
+	current, err := domain.NewPlanner(configManager, hashService).Plan(ctx)
+	if err != nil {
+		c.handleApplyError(logger, err)
+		return err
+	}
+	if !plan.Matches(current) {
+		err := &domain.ErrorPlanStale{Path: c.Plan}
+		logger.Error("%v", err)
+		logger.Error("Run 'skills-pkg plan --out %s' again and review the new plan", c.Plan)
+		return err
+	}
+
+	if len(plan.Changes) == 0 {
+		logger.Info("No changes. The install targets match the configuration.")
+		return nil
+	}
+
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, skillManagerOptions(c.allowRoot, c.downloadCache)...)
+
+	// Install targets that the plan leaves unchanged are up to date, so installation skips them
+	for _, skillName := range plan.Skills() {
+		logger.Verbose("Installing skill: %s", skillName)
+		if err := skillManager.Install(ctx, skillName); err != nil {
+			logger.Error("Failed to install skill '%s'", skillName)
+			c.handleApplyError(logger, err)
+			notifier.completed("skills-pkg apply failed", err.Error())
+			return err
+		}
+	}
+
+	if plan.Count(domain.PlanRemove) > 0 {
+		logger.Verbose("Removing skills that are no longer in the configuration")
+		if _, err := skillManager.Prune(ctx); err != nil {
+			c.handleApplyError(logger, err)
+			notifier.completed("skills-pkg apply failed", err.Error())
+			return err
+		}
+	}
+
+	summary := fmt.Sprintf("Apply complete: %d installed, %d updated, %d repaired, %d removed",
+		plan.Count(domain.PlanInstall), plan.Count(domain.PlanUpdate), plan.Count(domain.PlanRepair), plan.Count(domain.PlanRemove))
+	logger.Info("%s", summary)
+	notifier.completed("skills-pkg apply", summary)
+
+	return nil
+}
+
+// handleApplyError reports a failed apply with its cause and a recommended action.
+func (c *ApplyCmd) handleApplyError(logger *Logger, err error) {
+	if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+		logger.Error("Configuration file not found at %s", err.Path)
+		logger.Error("Run 'skills-pkg init' to create a configuration file")
+		return
+	}
+
+	if handlePermissionError(logger, err) {
+		return
+	}
+
+	logger.Error("Failed to apply the plan: %v", err)
+	logger.Error("Check network connection, file permissions, and try again")
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	installDir := filepath.Join(filepath.Dir(configPath), "install")
+	planPath := filepath.Join(t.TempDir(), "skills.plan")
+
+	downloadDir := t.TempDir()
+	skill := &domain.Skill{Name: "new-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0", SubDir: "skills/new-skill"}
+	if err := os.MkdirAll(filepath.Join(downloadDir, skill.SubDir), 0o755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	if err := domain.NewConfigManager(configPath).AddSkill(ctx, skill); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+
+	// An installation of a skill that was removed from the configuration
+	if err := os.MkdirAll(filepath.Join(installDir, "removed-skill"), 0o755); err != nil {
+		t.Fatalf("failed to create skill directory: %v", err)
+	}
+	err := domain.NewLockManager(domain.LockPathFor(configPath)).Update(ctx, func(lock *domain.LockFile) {
+		lock.RecordInstall("removed-skill", &domain.TargetStatus{Path: installDir, Version: "v0.1.0"})
+	})
+	if err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	if err := (&PlanCmd{Out: planPath}).runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("plan error = %v, stderr = %s", err, errOut.String())
+	}
+	for _, want := range []string{
+		"+ new-skill in " + installDir + " (v1.0.0)",
+		"- removed-skill in " + installDir,
+		"Plan: 1 to install, 0 to update, 0 to repair, 1 to remove",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("plan output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	apply := func() error {
+		t.Helper()
+		return (&ApplyCmd{Plan: planPath}).runWithDeps(configPath, logger, &mockHashService{}, []port.PackageManager{
+			&mockPackageManager{sourceType: "git", tmpDir: downloadDir},
+		})
+	}
+	if err := apply(); err != nil {
+		t.Fatalf("apply error = %v, stderr = %s", err, errOut.String())
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "new-skill")); err != nil {
+		t.Errorf("new-skill should be installed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "removed-skill")); !os.IsNotExist(err) {
+		t.Errorf("removed-skill should be removed, stat error = %v", err)
+	}
+
+	// The plan no longer describes the install targets, so it is not applied again
+	err = apply()
+	if _, ok := errors.AsType[*domain.ErrorPlanStale](err); !ok {
+		t.Errorf("second apply error = %v, want ErrorPlanStale", err)
+	}
+}
+
+func TestPlanCmd_NoChanges(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	if err := (&PlanCmd{}).runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("plan error = %v", err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "No changes.") {
+		t.Errorf("plan output = %q, want no changes", got)
+	}
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/scheduler"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// AutoupdateCmd represents the autoupdate command group
+type AutoupdateCmd struct {
+	Install   AutoupdateInstallCmd   `cmd:"" help:"Register a scheduled job that keeps this project's skills up to date"`
+	Uninstall AutoupdateUninstallCmd `cmd:"" help:"Remove the scheduled update job of this project"`
+}
+
+// AutoupdateInstallCmd represents the autoupdate install command
+type AutoupdateInstallCmd struct {
+	Interval  string `help:"How often to update skills (hourly, daily, weekly)" default:"daily" enum:"hourly,daily,weekly"`
+	Policy    string `help:"Largest version change applied automatically (major, minor, patch)" default:"minor" enum:"major,minor,patch"`
+	Scheduler string `help:"Scheduler to register the job with (launchd, systemd, cron). Detected from the platform when omitted"`
+}
+
+// AutoupdateUninstallCmd represents the autoupdate uninstall command
+type AutoupdateUninstallCmd struct {
+	Scheduler string `help:"Scheduler the job was registered with (launchd, systemd, cron). Detected from the platform when omitted"`
+}
+
+// Run executes the autoupdate install command
+func (c *AutoupdateInstallCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+// This method registers a scheduled `update` run for the project containing the configuration file.
+func (c *AutoupdateInstallCmd) run(configPath string, verbose bool) error {
+	logger := NewLogger(verbose)
+
+	sched, err := selectScheduler(c.Scheduler)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		logger.Error("Failed to determine the skills-pkg executable path: %v", err)
+		return err
+	}
+
+	return c.runWithScheduler(configPath, logger, sched, executable)
+}
+
+// runWithScheduler registers the job with the given scheduler (for testing)
+func (c *AutoupdateInstallCmd) runWithScheduler(configPath string, logger *Logger, sched port.Scheduler, executable string) error {
+	// The scheduled job fails on every run without a configuration, so check it up front
+	if _, err := newConfigManager(configPath).Load(context.Background()); err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	job, err := newAutoupdateJob(configPath, executable, dirs.LogDir(), c.Interval, c.Policy)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(job.LogPath), 0o755); err != nil {
+		logger.Error("Failed to create log directory %s: %v", filepath.Dir(job.LogPath), err)
+		return err
+	}
+
+	logger.Verbose("Registering job %s with %s: %v", job.Name, sched.Name(), job.Command)
+	if err := sched.Install(context.Background(), job); err != nil {
+		logger.Error("Failed to register scheduled job with %s: %v", sched.Name(), err)
+		return err
+	}
+
+	logger.Info("Scheduled %s skill updates for %s with %s (policy: %s)", job.Interval, job.WorkDir, sched.Name(), c.Policy)
+	logger.Info("Update output is appended to %s", job.LogPath)
+	logger.Info("Run 'skills-pkg autoupdate uninstall' to stop automatic updates")
+
+	return nil
+}
+
+// Run executes the autoupdate uninstall command
+func (c *AutoupdateUninstallCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+// This method removes the scheduled job of the project containing the configuration file.
+func (c *AutoupdateUninstallCmd) run(configPath string, verbose bool) error {
+	logger := NewLogger(verbose)
+
+	sched, err := selectScheduler(c.Scheduler)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	return c.runWithScheduler(configPath, logger, sched)
+}
+
+// runWithScheduler removes the job from the given scheduler (for testing)
+func (c *AutoupdateUninstallCmd) runWithScheduler(configPath string, logger *Logger, sched port.Scheduler) error {
+	projectDir, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		logger.Error("Failed to resolve project directory: %v", err)
+		return err
+	}
+	name := autoupdateJobName(projectDir)
+
+	logger.Verbose("Removing job %s from %s", name, sched.Name())
+	if err := sched.Remove(context.Background(), name); err != nil {
+		logger.Error("Failed to remove scheduled job from %s: %v", sched.Name(), err)
+		return err
+	}
+
+	logger.Info("Automatic skill updates for %s are disabled", projectDir)
+
+	return nil
+}
+
+// selectScheduler returns the named scheduler, or the platform's scheduler when name is empty.
+func selectScheduler(name string) (port.Scheduler, error) {
+	if name == "" {
+		return scheduler.Detect()
+	}
+	return scheduler.New(name)
+}
+
+// newAutoupdateJob builds the scheduled job that runs `skills-pkg update` in the project directory.
+func newAutoupdateJob(configPath, executable, logDir, interval, policy string) (*port.ScheduledJob, error) {
+	projectDir, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	name := autoupdateJobName(projectDir)
+
+	return &port.ScheduledJob{
+		Name:     name,
+		WorkDir:  projectDir,
+		Interval: interval,
+		LogPath:  filepath.Join(logDir, name+".log"),
+		Command:  []string{executable, "update", "--" + policy},
+	}, nil
+}
+
+// autoupdateJobName derives a stable job name from the project directory,
+// so that each project can have its own job.
+func autoupdateJobName(projectDir string) string {
+	sum := sha256.Sum256([]byte(projectDir))
+	return "skills-pkg-autoupdate-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// recordingScheduler records the jobs it is asked to install and remove.
+type recordingScheduler struct {
+	installed *port.ScheduledJob
+	removed   string
+}
+
+func (s *recordingScheduler) Name() string {
+	return "recording"
+}
+
+func (s *recordingScheduler) Install(ctx context.Context, job *port.ScheduledJob) error {
+	s.installed = job
+	return nil
+}
+
+func (s *recordingScheduler) Remove(ctx context.Context, name string) error {
+	s.removed = name
+	return nil
+}
+
+func TestAutoupdateInstallCmd_Run(t *testing.T) {
+	tests := []struct {
+		wantErrCheck func(error) bool
+		name         string
+		policy       string
+		wantCommand  []string
+		initConfig   bool
+		wantErr      bool
+	}{
+		{
+			name:        "success: registers update job with policy",
+			policy:      "patch",
+			initConfig:  true,
+			wantCommand: []string{"/opt/bin/skills-pkg", "update", "--patch"},
+		},
+		{
+			name:       "error: configuration file not found",
+			policy:     "minor",
+			initConfig: false,
+			wantErr:    true,
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorConfigNotFound](err)
+				return ok
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateHome := t.TempDir()
+			t.Setenv("XDG_STATE_HOME", stateHome)
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			if tt.initConfig {
+				if err := domain.NewConfigManager(configPath).Initialize(context.Background(), nil); err != nil {
+					t.Fatalf("failed to initialize config: %v", err)
+				}
+			}
+
+			sched := &recordingScheduler{}
+			var buf bytes.Buffer
+			logger := &Logger{out: &buf, dataOut: &buf, errOut: &buf}
+			cmd := &AutoupdateInstallCmd{Interval: port.ScheduleWeekly, Policy: tt.policy}
+
+			err := cmd.runWithScheduler(configPath, logger, sched, "/opt/bin/skills-pkg")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithScheduler() error = %v, wantErr %v\noutput: %s", err, tt.wantErr, buf.String())
+			}
+			if tt.wantErrCheck != nil && !tt.wantErrCheck(err) {
+				t.Errorf("runWithScheduler() error check failed, got %v", err)
+			}
+			if tt.wantErr {
+				if sched.installed != nil {
+					t.Error("no job should be registered on error")
+				}
+				return
+			}
+
+			job := sched.installed
+			if job == nil {
+				t.Fatal("no job was registered")
+			}
+			if !slices.Equal(job.Command, tt.wantCommand) {
+				t.Errorf("Command = %v, want %v", job.Command, tt.wantCommand)
+			}
+			if job.WorkDir != tmpDir {
+				t.Errorf("WorkDir = %s, want %s", job.WorkDir, tmpDir)
+			}
+			if job.Interval != port.ScheduleWeekly {
+				t.Errorf("Interval = %s, want %s", job.Interval, port.ScheduleWeekly)
+			}
+			if want := filepath.Join(stateHome, "skills-pkg", "logs", job.Name+".log"); job.LogPath != want {
+				t.Errorf("LogPath = %s, want %s", job.LogPath, want)
+			}
+
+			// Uninstalling from the same project removes the same job
+			uninstall := &AutoupdateUninstallCmd{}
+			if err := uninstall.runWithScheduler(configPath, logger, sched); err != nil {
+				t.Fatalf("uninstall error = %v", err)
+			}
+			if sched.removed != job.Name {
+				t.Errorf("removed job = %s, want %s", sched.removed, job.Name)
+			}
+		})
+	}
+}
+
+func TestAutoupdateJobName(t *testing.T) {
+	a := autoupdateJobName("/home/user/project-a")
+	b := autoupdateJobName("/home/user/project-b")
+
+	if a == b {
+		t.Errorf("job names of different projects should differ, both are %s", a)
+	}
+	if a != autoupdateJobName("/home/user/project-a") {
+		t.Error("job name should be stable for the same project")
+	}
+	if !strings.HasPrefix(a, "skills-pkg-autoupdate-") {
+		t.Errorf("job name = %s, want skills-pkg-autoupdate- prefix", a)
+	}
+}
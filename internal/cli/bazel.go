@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"golang.org/x/mod/module"
+)
+
+// BazelCmd represents the bazel command
+type BazelCmd struct {
+	Output string `name:"out" short:"o" type:"path" help:"Write the Starlark file to a file instead of stdout"`
+}
+
+//go:embed templates/bazel.bzl.tmpl
+var bazelRulesTemplate string
+
+var bazelRules = template.Must(template.New("bazel").Funcs(template.FuncMap{"starlark": starlarkString}).Parse(bazelRulesTemplate))
+
+// bazelSkill is a skill in the generated Starlark file.
+type bazelSkill struct {
+	Name        string
+	Repository  string // Bazel repository name
+	URL         string // Archive URL
+	SHA256      string // Hex-encoded SHA-256 of the archive
+	StripPrefix string // Directory of the skill in the archive
+}
+
+// Run executes the bazel command
+func (c *BazelCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *BazelCmd) run(configPath string, verbose bool) error {
+	return c.runWithClient(configPath, NewLogger(verbose), network.Client())
+}
+
+// runWithClient generates the Bazel rules, downloading archives with the given HTTP client (for testing)
+func (c *BazelCmd) runWithClient(configPath string, logger *Logger, client *http.Client) error {
+	ctx := context.Background()
+
+	config, err := newConfigManager(configPath).Load(ctx)
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	installed := config.InstalledSkills()
+	repositories := make(map[string]string, len(installed))
+	for _, skill := range installed {
+		repository := bazelRepositoryName(skill.Name)
+		if other, ok := repositories[repository]; ok {
+			err := fmt.Errorf("skills '%s' and '%s' map to the same Bazel repository %s", other, skill.Name, repository)
+			logger.Error("%v", err)
+			logger.Error("Rename one of the skills with 'skills-pkg uninstall' and 'skills-pkg add'")
+			return err
+		}
+		repositories[repository] = skill.Name
+	}
+
+	skills := make([]*bazelSkill, 0, len(installed))
+	for _, skill := range installed {
+		logger.Info("Pinning skill '%s'...", skill.Name)
+		s, err := c.pin(ctx, client, skill, lock)
+		if err != nil {
+			logger.Error("Failed to pin skill '%s': %v", skill.Name, err)
+			return err
+		}
+		skills = append(skills, s)
+	}
+
+	var buf bytes.Buffer
+	if err := bazelRules.Execute(&buf, struct{ Skills []*bazelSkill }{Skills: skills}); err != nil {
+		err = fmt.Errorf("failed to render Bazel rules: %w", err)
+		logger.Error("%v", err)
+		return err
+	}
+
+	if c.Output == "" {
+		_, err = logger.dataOut.Write(buf.Bytes())
+		return err
+	}
+
+	if err := os.WriteFile(c.Output, buf.Bytes(), setupCIFilePerm); err != nil {
+		logger.Error("Failed to write %s: %v", c.Output, err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+	logger.Info("Created %s", c.Output)
+
+	return nil
+}
+
+// pin downloads the archive of the skill at its pinned version, hashes it,
+// and locates the skill directory in it.
+func (c *BazelCmd) pin(ctx context.Context, client *http.Client, skill *domain.Skill, lock *domain.LockFile) (*bazelSkill, error) {
+	version := pinnedVersion(skill, lock)
+	if version == "" {
+		return nil, fmt.Errorf("no version is pinned. Run 'skills-pkg install %s' to record the installed version first", skill.Name)
+	}
+
+	archiveURL, err := skillArchiveURL(skill, version)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to download %s: %w", domain.ErrNetworkFailure, archiveURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: failed to download %s: status %d", domain.ErrNetworkFailure, archiveURL, resp.StatusCode)
+	}
+	archive, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to download %s: %w", domain.ErrNetworkFailure, archiveURL, err)
+	}
+
+	// Module zips hold the files under "<module>@<version>"; repository archives
+	// under a directory named after the repository and revision
+	root := skill.URL + "@" + version
+	if skill.Source == "git" {
+		root, err = archiveRoot(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", archiveURL, err)
+		}
+	}
+
+	sum := sha256.Sum256(archive)
+	return &bazelSkill{
+		Name:        skill.Name,
+		Repository:  bazelRepositoryName(skill.Name),
+		URL:         archiveURL,
+		SHA256:      hex.EncodeToString(sum[:]),
+		StripPrefix: strings.TrimSuffix(path.Join(root, strings.Trim(skill.SubDir, "/")), "/"),
+	}, nil
+}
+
+// skillArchiveURL returns the URL of an archive of the skill's source at the version.
+// Git repositories are supported when they are hosted on GitHub or GitLab, which serve archives of any revision.
+func skillArchiveURL(skill *domain.Skill, version string) (string, error) {
+	switch skill.Source {
+	case "git":
+		repo, host, ok := repoWebURL(skill.URL)
+		if !ok {
+			return "", fmt.Errorf("cannot determine an archive URL for repository %s", skill.URL)
+		}
+		switch host {
+		case "github.com":
+			if commitHashPattern.MatchString(version) {
+				return repo + "/archive/" + version + ".tar.gz", nil
+			}
+			return repo + "/archive/refs/tags/" + version + ".tar.gz", nil
+		case "gitlab.com":
+			return repo + "/-/archive/" + version + "/" + path.Base(repo) + "-" + version + ".tar.gz", nil
+		}
+		return "", fmt.Errorf("repository %s is not hosted on GitHub or GitLab, which are required for archive downloads", skill.URL)
+	case "go-mod":
+		escapedPath, err := module.EscapePath(skill.URL)
+		if err != nil {
+			return "", fmt.Errorf("invalid module path %s: %w", skill.URL, err)
+		}
+		escapedVersion, err := module.EscapeVersion(version)
+		if err != nil {
+			return "", fmt.Errorf("invalid module version %s: %w", version, err)
+		}
+		return fmt.Sprintf("%s/%s/@v/%s.zip", goProxyURL(), escapedPath, escapedVersion), nil
+	default:
+		return "", &domain.ErrorInvalidSource{SourceType: skill.Source}
+	}
+}
+
+// archiveRoot returns the top-level directory shared by the files in a gzipped tar archive.
+func archiveRoot(archive []byte) (string, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", err
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		// GitHub archives start with a global header holding the commit hash
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		names = append(names, header.Name)
+	}
+
+	root := ""
+	for _, name := range names {
+		dir, _, ok := strings.Cut(strings.TrimPrefix(name, "./"), "/")
+		if !ok || (root != "" && dir != root) {
+			return "", errors.New("files are not in a single top-level directory")
+		}
+		root = dir
+	}
+	if root == "" {
+		return "", errors.New("archive is empty")
+	}
+	return root, nil
+}
+
+// bazelRepositoryName returns the Bazel repository name for the skill.
+func bazelRepositoryName(skillName string) string {
+	var b strings.Builder
+	b.WriteString("skill_")
+	for _, r := range skillName {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// starlarkString returns s as a Starlark string literal.
+func starlarkString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// redirectTransport sends every request to the test server, keeping its path.
+type redirectTransport struct {
+	server *url.URL
+}
+
+func (rt *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.server.Scheme
+	req.URL.Host = rt.server.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header", PAXRecords: map[string]string{"comment": "abc"}}); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBazelCmd_Run(t *testing.T) {
+	t.Setenv("GOPROXY", "")
+
+	tagArchive := buildTarGz(t, map[string]string{"skills-1.0.0/skills/tagged/SKILL.md": "# Tagged\n"})
+	commit := strings.Repeat("b2", 20)
+	commitArchive := buildTarGz(t, map[string]string{"other-" + commit + "/SKILL.md": "# Locked\n"})
+	moduleZip := buildZip(t, map[string]string{"github.com/Example/mod@v0.2.0/SKILL.md": "# Module\n"})
+	archives := map[string][]byte{
+		"/example/skills/archive/refs/tags/v1.0.0.tar.gz": tagArchive,
+		"/example/other/archive/" + commit + ".tar.gz":    commitArchive,
+		"/github.com/!example/mod/@v/v0.2.0.zip":          moduleZip,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archive, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	config := &domain.Config{
+		Skills: []*domain.Skill{
+			{Name: "tagged", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0", SubDir: "skills/tagged"},
+			{Name: "locked-skill", Source: "git", URL: "git@github.com:example/other.git"},
+			{Name: "module", Source: "go-mod", URL: "github.com/Example/mod", Version: "v0.2.0"},
+		},
+		InstallTargets: []string{filepath.Join(tmpDir, "install")},
+	}
+	if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	err = domain.NewLockManager(domain.LockPathFor(configPath)).Update(context.Background(), func(lock *domain.LockFile) {
+		lock.RecordInstall("locked-skill", &domain.TargetStatus{Path: config.InstallTargets[0], Version: commit})
+	})
+	if err != nil {
+		t.Fatalf("failed to record install: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	cmd := &BazelCmd{}
+	if err := cmd.runWithClient(configPath, logger, &http.Client{Transport: &redirectTransport{server: serverURL}}); err != nil {
+		t.Fatalf("runWithClient() error = %v, stderr: %s", err, errOut.String())
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		`load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")`,
+		`use_repo(skills, "skill_tagged", "skill_locked_skill", "skill_module")`,
+		`"locked-skill": "@skill_locked_skill//:files",`,
+		`urls = ["https://github.com/example/skills/archive/refs/tags/v1.0.0.tar.gz"],`,
+		`sha256 = "` + sha256Hex(tagArchive) + `",`,
+		`strip_prefix = "skills-1.0.0/skills/tagged",`,
+		`urls = ["https://github.com/example/other/archive/` + commit + `.tar.gz"],`,
+		`strip_prefix = "other-` + commit + `",`,
+		`urls = ["https://proxy.golang.org/github.com/!example/mod/@v/v0.2.0.zip"],`,
+		`sha256 = "` + sha256Hex(moduleZip) + `",`,
+		`strip_prefix = "github.com/Example/mod@v0.2.0",`,
+		"skills = module_extension(implementation = _skills_impl)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestBazelCmd_Run_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantErrMsg string
+		skills     []*domain.Skill
+	}{
+		{
+			name:       "unsupported git host",
+			skills:     []*domain.Skill{{Name: "self-hosted", Source: "git", URL: "https://git.example.com/skills.git", Version: "v1.0.0"}},
+			wantErrMsg: "not hosted on GitHub or GitLab",
+		},
+		{
+			name:       "no pinned version",
+			skills:     []*domain.Skill{{Name: "unpinned", Source: "git", URL: "https://github.com/example/skills.git"}},
+			wantErrMsg: "skills-pkg install unpinned",
+		},
+		{
+			name: "repository name collision",
+			skills: []*domain.Skill{
+				{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0"},
+				{Name: "my_skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0"},
+			},
+			wantErrMsg: "same Bazel repository skill_my_skill",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			config := &domain.Config{Skills: tt.skills, InstallTargets: []string{filepath.Join(tmpDir, "install")}}
+			if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+
+			// Any download fails, so that no test reaches the network
+			server := httptest.NewServer(http.NotFoundHandler())
+			defer server.Close()
+			serverURL, err := url.Parse(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+			cmd := &BazelCmd{}
+			if err := cmd.runWithClient(configPath, logger, &http.Client{Transport: &redirectTransport{server: serverURL}}); err == nil {
+				t.Fatal("runWithClient() expected error")
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrMsg) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrMsg)
+			}
+			if out.Len() != 0 {
+				t.Errorf("expected no output, got %s", out.String())
+			}
+		})
+	}
+}
+
+func TestArchiveRoot(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "single root", files: map[string]string{"repo-1.0.0/SKILL.md": "a", "repo-1.0.0/docs/x.md": "b"}, want: "repo-1.0.0"},
+		{name: "multiple roots", files: map[string]string{"a/SKILL.md": "a", "b/SKILL.md": "b"}, wantErr: true},
+		{name: "file at top level", files: map[string]string{"SKILL.md": "a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := archiveRoot(buildTarGz(t, tt.files))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("archiveRoot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("archiveRoot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// bundleIndexVersion is the format version of the bundle index this binary can read.
+const bundleIndexVersion = 1
+
+//go:embed bundles/index.toml
+var bundleIndexTOML []byte
+
+// bundleIndex is the curated list of skill bundles offered by 'init --bundle'.
+type bundleIndex struct {
+	Bundles []*skillBundle `toml:"bundles"`
+	Version int            `toml:"version"`
+}
+
+// skillBundle is a vetted set of skills for a kind of project.
+type skillBundle struct {
+	Name        string          `toml:"name"`
+	Description string          `toml:"description"`
+	Skills      []*domain.Skill `toml:"skills"`
+}
+
+// loadBundles parses the bundle index baked into the binary.
+func loadBundles() ([]*skillBundle, error) {
+	var index bundleIndex
+	if err := toml.Unmarshal(bundleIndexTOML, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle index: %w", err)
+	}
+	if index.Version != bundleIndexVersion {
+		return nil, fmt.Errorf("bundle index version %d is not supported; expected %d", index.Version, bundleIndexVersion)
+	}
+	return index.Bundles, nil
+}
+
+// bundleSkills returns the skills of the named bundles in order. A skill in several bundles is
+// returned once. Unknown bundle names are reported together with the available bundles.
+func bundleSkills(names []string) ([]*domain.Skill, error) {
+	bundles, err := loadBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	var skills []*domain.Skill
+	seen := make(map[string]bool)
+	for _, name := range names {
+		var bundle *skillBundle
+		for _, b := range bundles {
+			if b.Name == name {
+				bundle = b
+				break
+			}
+		}
+		if bundle == nil {
+			available := make([]string, 0, len(bundles))
+			for _, b := range bundles {
+				available = append(available, fmt.Sprintf("%s (%s)", b.Name, b.Description))
+			}
+			return nil, fmt.Errorf("unknown bundle '%s'. Available bundles: %s", name, strings.Join(available, ", "))
+		}
+
+		for _, skill := range bundle.Skills {
+			if seen[skill.Name] {
+				continue
+			}
+			seen[skill.Name] = true
+			skills = append(skills, skill)
+		}
+	}
+
+	return skills, nil
+}
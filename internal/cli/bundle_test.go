@@ -0,0 +1,39 @@
+package cli
+
+import "testing"
+
+// TestBundleIndex checks that every bundle baked into the binary can be added to a configuration.
+func TestBundleIndex(t *testing.T) {
+	t.Parallel()
+
+	bundles, err := loadBundles()
+	if err != nil {
+		t.Fatalf("failed to load bundles: %v", err)
+	}
+	if len(bundles) == 0 {
+		t.Fatal("bundle index is empty")
+	}
+
+	names := make(map[string]bool)
+	for _, bundle := range bundles {
+		if names[bundle.Name] {
+			t.Errorf("bundle %s is defined more than once", bundle.Name)
+		}
+		names[bundle.Name] = true
+
+		if bundle.Description == "" {
+			t.Errorf("bundle %s has no description", bundle.Name)
+		}
+		if len(bundle.Skills) == 0 {
+			t.Errorf("bundle %s has no skills", bundle.Name)
+		}
+		for _, skill := range bundle.Skills {
+			if err := skill.Validate(); err != nil {
+				t.Errorf("bundle %s: %v", bundle.Name, err)
+			}
+			if skill.Name == managingSkillsName {
+				t.Errorf("bundle %s includes %s, which init always installs", bundle.Name, managingSkillsName)
+			}
+		}
+	}
+}
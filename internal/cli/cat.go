@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// CatCmd represents the cat command
+type CatCmd struct {
+	Skill  string `arg:"" help:"Name of the skill to print"`
+	File   string `arg:"" optional:"" help:"File to print, relative to the skill directory" default:"SKILL.md"`
+	Target string `help:"Install target to read the skill from (defaults to the first local install target containing it)"`
+	Color  string `help:"Highlight Markdown files (auto, always, never). auto highlights only when writing to a terminal and NO_COLOR is not set" default:"auto" enum:"auto,always,never"`
+}
+
+// Run executes the cat command
+func (c *CatCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *CatCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger prints the file of the installed skill (for testing)
+func (c *CatCmd) runWithLogger(configPath string, logger *Logger) error {
+	config, err := newConfigManager(configPath).Load(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	skill := config.FindInstalledSkill(c.Skill)
+	if skill == nil {
+		err := &domain.ErrorSkillsNotFound{SkillNames: []string{c.Skill}}
+		logger.Error("Skill '%s' not found in configuration", c.Skill)
+		logger.Error("Run 'skills-pkg list' to see the configured skills")
+		return err
+	}
+
+	file := c.File
+	if file == "" {
+		file = "SKILL.md"
+	}
+	if !filepath.IsLocal(filepath.FromSlash(file)) {
+		err := fmt.Errorf("file %s is outside the skill directory", file)
+		logger.Error("%v", err)
+		logger.Error("Specify the file relative to the skill directory, for example 'references/guide.md'")
+		return err
+	}
+
+	dir, err := installedSkillDir(config, skill, c.Target)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	path := filepath.Join(dir, filepath.FromSlash(file))
+	logger.Verbose("Reading %s", path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			logger.Error("File %s not found in skill '%s'", file, skill.Name)
+		} else {
+			logger.Error("Failed to read %s: %v", path, err)
+		}
+		if files, listErr := domain.ListSkillFiles(dir); listErr == nil && len(files) > 0 {
+			logger.Error("Files in %s: %s", dir, strings.Join(files, ", "))
+		}
+		return err
+	}
+
+	if c.useColor(logger.dataOut) && isMarkdownFile(path) {
+		content = highlightMarkdown(content)
+	}
+
+	_, err = logger.dataOut.Write(content)
+	return err
+}
+
+// useColor reports whether output written to w should be highlighted.
+func (c *CatCmd) useColor(w io.Writer) bool {
+	switch c.Color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// isMarkdownFile reports whether path has a Markdown file extension.
+func isMarkdownFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestCatCmd_Run(t *testing.T) {
+	tests := []struct {
+		cmd     *CatCmd
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "prints SKILL.md by default",
+			cmd:  &CatCmd{Skill: "my-skill", Color: "auto"},
+			want: "# My Skill\n\nUse `tool`.\n",
+		},
+		{
+			name: "prints another file of the skill",
+			cmd:  &CatCmd{Skill: "my-skill", File: "references/guide.txt", Color: "auto"},
+			want: "guide\n",
+		},
+		{
+			name: "highlights Markdown when color is always",
+			cmd:  &CatCmd{Skill: "my-skill", Color: "always"},
+			want: ansiBold + ansiMagenta + "# My Skill" + ansiReset + "\n\nUse " + ansiCyan + "`tool`" + ansiReset + ".\n",
+		},
+		{
+			name: "does not highlight other files",
+			cmd:  &CatCmd{Skill: "my-skill", File: "references/guide.txt", Color: "always"},
+			want: "guide\n",
+		},
+		{
+			name:    "file outside the skill directory",
+			cmd:     &CatCmd{Skill: "my-skill", File: "../other/SKILL.md", Color: "auto"},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			cmd:     &CatCmd{Skill: "my-skill", File: "missing.md", Color: "auto"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown skill",
+			cmd:     &CatCmd{Skill: "missing", Color: "auto"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			configPath := filepath.Join(projectDir, ".skillspkg.toml")
+			target := filepath.Join(projectDir, "skills")
+
+			config := &domain.Config{
+				Skills:         []*domain.Skill{{Name: "my-skill", Source: "git", URL: "https://github.com/example/skill.git"}},
+				InstallTargets: []string{target},
+			}
+			if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+
+			skillDir := filepath.Join(target, "my-skill")
+			if err := os.MkdirAll(filepath.Join(skillDir, "references"), 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# My Skill\n\nUse `tool`.\n"), 0o644); err != nil {
+				t.Fatalf("failed to write SKILL.md: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(skillDir, "references", "guide.txt"), []byte("guide\n"), 0o644); err != nil {
+				t.Fatalf("failed to write guide: %v", err)
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+
+			err := tt.cmd.runWithLogger(configPath, logger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithLogger() error = %v, wantErr %v\nstderr: %s", err, tt.wantErr, errOut.String())
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatCmd_UseColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		color   string
+		noColor string
+		want    bool
+	}{
+		{name: "always", color: "always", noColor: "1", want: true},
+		{name: "never", color: "never"},
+		{name: "auto without a terminal", color: "auto"},
+		{name: "auto with NO_COLOR", color: "auto", noColor: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			cmd := &CatCmd{Color: tt.color}
+			if got := cmd.useColor(&bytes.Buffer{}); got != tt.want {
+				t.Errorf("useColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
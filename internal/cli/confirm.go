@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// errCancelled is returned when the user declines to confirm a destructive operation.
+var errCancelled = fmt.Errorf("cancelled by the user: %w", context.Canceled)
+
+// confirmInput returns the input that confirmations of destructive operations are read from.
+// Scripts and CI jobs cannot answer prompts, so it returns nil unless standard input and
+// standard error are terminals, and they proceed without being asked.
+func confirmInput() io.Reader {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		return nil
+	}
+	return os.Stdin
+}
+
+// confirm asks a yes/no question and reports whether the user answered yes.
+// An empty answer, or the end of the input as on Ctrl+D, is no.
+func confirm(logger *Logger, in io.Reader, question string) bool {
+	_, _ = fmt.Fprintf(logger.out, "%s [y/N] ", question)
+	answers := bufio.NewScanner(in)
+	if !answers.Scan() {
+		_, _ = fmt.Fprintln(logger.out)
+		return false
+	}
+	return isYes(answers.Text(), false)
+}
+
+// modifiedInstalls returns the installations of the skills whose files were modified after
+// installation, which are lost when the skills are replaced or removed. Installations that
+// cannot be checked are left out, as the operation itself reports the problem.
+func modifiedInstalls(configPath string, skillNames []string) []*domain.PlannedChange {
+	plan, err := domain.NewPlanner(newConfigManager(configPath), service.NewDirhash()).Plan(context.Background())
+	if err != nil {
+		return nil
+	}
+
+	var modified []*domain.PlannedChange
+	for _, change := range plan.Changes {
+		if change.Action == domain.PlanRepair && slices.Contains(skillNames, change.Skill) {
+			modified = append(modified, change)
+		}
+	}
+	return modified
+}
+
+// warnModified warns that the local modifications of the installations will be lost.
+func warnModified(logger *Logger, modified []*domain.PlannedChange) {
+	for _, change := range modified {
+		logger.Error("WARNING: %s in %s has local modifications that will be lost", change.Skill, change.Target)
+	}
+}
+
+// dependencyApproval returns the option that decides whether the skills that a SKILL.md declares as
+// dependencies are added to the configuration: all of them with --with-dependencies, those the user
+// confirms when in can answer prompts, and none otherwise.
+func dependencyApproval(logger *Logger, in io.Reader, withDependencies bool) domain.SkillManagerOption {
+	return domain.WithDependencyApproval(func(dependent string, dependency *domain.Skill) bool {
+		if withDependencies {
+			return true
+		}
+		if in == nil {
+			return false
+		}
+		return confirm(logger, in, fmt.Sprintf("Skill '%s' depends on '%s' from %s (%s). Add it to the configuration?", dependent, dependency.Name, dependency.URL, dependency.Source))
+	})
+}
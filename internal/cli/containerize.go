@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// ContainerizeCmd represents the containerize command
+type ContainerizeCmd struct {
+	Format  string `help:"Output format: dockerfile (multi-stage snippet) or devcontainer (devcontainer.json fragment)" enum:"dockerfile,devcontainer" default:"dockerfile"`
+	Output  string `name:"out" short:"o" type:"path" help:"Write the snippet to a file instead of stdout"`
+	Home    string `help:"Home directory inside the container, used for user-level install targets" default:"/root"`
+	Version string `help:"skills-pkg version installed in the container" default:"latest"`
+}
+
+// Run executes the containerize command
+func (c *ContainerizeCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *ContainerizeCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger generates a snippet that installs the project's skills into a container image.
+func (c *ContainerizeCmd) runWithLogger(configPath string, logger *Logger) error {
+	logger.Verbose("Loading configuration from %s", configPath)
+
+	configManager := newConfigManager(configPath)
+	config, err := configManager.Load(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	var snippet []byte
+	switch c.Format {
+	case "devcontainer":
+		snippet, err = c.devcontainerSnippet(config, logger)
+	default:
+		snippet, err = c.dockerfileSnippet(config, filepath.Dir(configPath))
+	}
+	if err != nil {
+		logger.Error("Failed to generate %s snippet: %v", c.Format, err)
+		return err
+	}
+
+	if c.Output == "" {
+		_, err = logger.dataOut.Write(snippet)
+		return err
+	}
+
+	if err := os.WriteFile(c.Output, snippet, setupCIFilePerm); err != nil {
+		logger.Error("Failed to write %s: %v", c.Output, err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+	logger.Info("Created %s", c.Output)
+
+	return nil
+}
+
+//go:embed templates/containerize.Dockerfile.tmpl
+var containerizeDockerfileTemplate string
+
+var containerizeDockerfile = template.Must(template.New("Dockerfile").Parse(containerizeDockerfileTemplate))
+
+// containerTarget maps an install target in the build stage to its location in the final image.
+type containerTarget struct {
+	Source string
+	Dest   string
+}
+
+// dockerfileSnippet renders a multi-stage Dockerfile snippet that runs skills-pkg install
+// in a builder stage and copies every install target into the final image.
+func (c *ContainerizeCmd) dockerfileSnippet(config *domain.Config, projectDir string) ([]byte, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+
+	localTargets := config.LocalInstallTargets()
+	targets := make([]containerTarget, 0, len(localTargets))
+	for _, target := range localTargets {
+		targets = append(targets, c.containerTarget(target, home))
+	}
+
+	var buf bytes.Buffer
+	err = containerizeDockerfile.Execute(&buf, struct {
+		Version    string
+		Targets    []containerTarget
+		GoModFiles []string
+	}{
+		Version:    c.Version,
+		Targets:    targets,
+		GoModFiles: goModFiles(config, projectDir),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Dockerfile snippet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// containerTarget resolves where an install target is written in the builder stage
+// and where it belongs in the final image.
+// Relative targets follow the final image's WORKDIR, and targets under the local home
+// directory are moved under the container's home directory.
+func (c *ContainerizeCmd) containerTarget(target, home string) containerTarget {
+	if !filepath.IsAbs(target) {
+		rel := path.Clean(filepath.ToSlash(target))
+		return containerTarget{Source: path.Join("/src", rel), Dest: "./" + rel}
+	}
+
+	source := filepath.ToSlash(target)
+	dest := source
+	if home != "" {
+		if rel, err := filepath.Rel(home, target); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			dest = path.Join(c.Home, filepath.ToSlash(rel))
+		}
+	}
+
+	return containerTarget{Source: source, Dest: dest}
+}
+
+// devcontainerSnippet renders a devcontainer.json fragment that installs skills-pkg
+// and the project's skills after the container is created.
+func (c *ContainerizeCmd) devcontainerSnippet(config *domain.Config, logger *Logger) ([]byte, error) {
+	for _, target := range config.InstallTargets {
+		if filepath.IsAbs(target) {
+			logger.Error("Warning: install target %s is an absolute path and may not exist inside the devcontainer", target)
+		}
+	}
+
+	snippet := struct {
+		Features          map[string]map[string]any `json:"features"`
+		PostCreateCommand string                    `json:"postCreateCommand"`
+	}{
+		Features: map[string]map[string]any{
+			"ghcr.io/devcontainers/features/go:1": {},
+		},
+		PostCreateCommand: fmt.Sprintf("go install github.com/mazrean/skills-pkg@%s && \"$(go env GOPATH)/bin/skills-pkg\" install", c.Version),
+	}
+
+	// Keep shell operators such as && readable instead of HTML-escaping them
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snippet); err != nil {
+		return nil, fmt.Errorf("failed to marshal devcontainer snippet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// goModFiles returns the go.mod and go.sum files the builder stage needs when installing
+// go-mod skills without a pinned version, which resolve their version from go.mod.
+func goModFiles(config *domain.Config, projectDir string) []string {
+	if !config.Defaults.UseGoMod() {
+		return nil
+	}
+
+	needed := false
+	for _, skill := range config.Skills {
+		if skill.Source == "go-mod" && skill.Version == "" {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	var files []string
+	for _, name := range []string{"go.mod", "go.sum"} {
+		if _, err := os.Stat(filepath.Join(projectDir, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+	return files
+}
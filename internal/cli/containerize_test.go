@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestContainerizeCmd_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErrCheck func(error) bool
+		setupFunc    func(t *testing.T) (configPath string)
+		checkFunc    func(t *testing.T, output string)
+		name         string
+		format       string
+		wantErr      bool
+	}{
+		{
+			name:   "success: dockerfile snippet copies install targets",
+			format: "dockerfile",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return setupContainerizeConfig(t, []string{"./.claude/skills"}, true)
+			},
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+				for _, want := range []string{
+					"FROM golang:1 AS skills",
+					"go install github.com/mazrean/skills-pkg@latest",
+					"COPY .skillspkg.toml ./",
+					"COPY go.mod ./",
+					"RUN skills-pkg install",
+					"COPY --from=skills /src/.claude/skills ./.claude/skills",
+				} {
+					if !strings.Contains(output, want) {
+						t.Errorf("output should contain %q, got:\n%s", want, output)
+					}
+				}
+			},
+		},
+		{
+			name:   "success: go.mod is not copied when no skill resolves from it",
+			format: "dockerfile",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return setupContainerizeConfig(t, []string{"./.skills"}, false)
+			},
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+				if strings.Contains(output, "go.mod") {
+					t.Errorf("output should not copy go.mod, got:\n%s", output)
+				}
+			},
+		},
+		{
+			name:   "success: devcontainer snippet is valid JSON",
+			format: "devcontainer",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return setupContainerizeConfig(t, []string{"./.claude/skills"}, false)
+			},
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+				var snippet map[string]any
+				if err := json.Unmarshal([]byte(output), &snippet); err != nil {
+					t.Fatalf("output is not valid JSON: %v\n%s", err, output)
+				}
+				command, _ := snippet["postCreateCommand"].(string)
+				if !strings.Contains(command, "skills-pkg\" install") || !strings.Contains(command, "&&") {
+					t.Errorf("postCreateCommand should install skills, got: %s", command)
+				}
+			},
+		},
+		{
+			name:   "error: configuration file not found",
+			format: "dockerfile",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return filepath.Join(t.TempDir(), ".skillspkg.toml")
+			},
+			wantErr: true,
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorConfigNotFound](err)
+				return ok
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := tt.setupFunc(t)
+
+			cmd := &ContainerizeCmd{Format: tt.format, Home: "/root", Version: "latest"}
+			var outBuf, dataBuf bytes.Buffer
+			logger := &Logger{out: &outBuf, dataOut: &dataBuf, errOut: &outBuf}
+
+			err := cmd.runWithLogger(configPath, logger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrCheck != nil && !tt.wantErrCheck(err) {
+				t.Errorf("runWithLogger() error check failed, got %v", err)
+			}
+			if tt.checkFunc != nil {
+				tt.checkFunc(t, dataBuf.String())
+			}
+		})
+	}
+}
+
+func TestContainerizeCmd_containerTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		home   string
+		want   containerTarget
+	}{
+		{
+			name:   "relative target follows WORKDIR",
+			target: "./.codex/skills",
+			home:   "/home/alice",
+			want:   containerTarget{Source: "/src/.codex/skills", Dest: "./.codex/skills"},
+		},
+		{
+			name:   "user-level target moves to container home",
+			target: "/home/alice/.claude/skills",
+			home:   "/home/alice",
+			want:   containerTarget{Source: "/home/alice/.claude/skills", Dest: "/root/.claude/skills"},
+		},
+		{
+			name:   "system-wide target keeps its path",
+			target: "/usr/local/share/skills-pkg/skills",
+			home:   "/home/alice",
+			want:   containerTarget{Source: "/usr/local/share/skills-pkg/skills", Dest: "/usr/local/share/skills-pkg/skills"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" {
+				t.Skip("Unix-style absolute paths are not absolute on this platform")
+			}
+
+			cmd := &ContainerizeCmd{Home: "/root"}
+			if got := cmd.containerTarget(tt.target, tt.home); got != tt.want {
+				t.Errorf("containerTarget() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func setupContainerizeConfig(t *testing.T, installTargets []string, withGoModSkill bool) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".skillspkg.toml")
+
+	configManager := domain.NewConfigManager(configPath)
+	if err := configManager.Initialize(context.Background(), installTargets); err != nil {
+		t.Fatalf("failed to initialize config: %v", err)
+	}
+
+	skill := &domain.Skill{
+		Name:    "test-skill",
+		Source:  "git",
+		URL:     "https://example.com/test.git",
+		Version: "v1.0.0",
+	}
+	if withGoModSkill {
+		skill = &domain.Skill{
+			Name:   "test-skill",
+			Source: "go-mod",
+			URL:    "example.com/skills",
+		}
+	}
+	if err := configManager.AddSkill(context.Background(), skill); err != nil {
+		t.Fatalf("failed to add test skill: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/project\n"), 0o644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	return configPath
+}
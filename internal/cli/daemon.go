@@ -0,0 +1,422 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// daemonKeepWarm is how long the daemon keeps refreshing a version after it was last requested.
+const daemonKeepWarm = 24 * time.Hour
+
+// DaemonCmd represents the daemon command
+type DaemonCmd struct {
+	TTL     time.Duration `help:"How long a latest version is served from memory before it is looked up again" default:"10m" name:"ttl"`
+	Metrics string        `help:"Loopback address to serve Prometheus metrics on at /metrics, e.g. ':9100' for 127.0.0.1:9100" name:"metrics" placeholder:"ADDR"`
+}
+
+// Run executes the daemon command
+func (c *DaemonCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+// It serves the socket in the user cache directory until the process receives an interrupt or termination signal.
+func (c *DaemonCmd) run(verbose bool) error {
+	logger := NewLogger(verbose)
+
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		logger.Error("Failed to resolve user directories: %v", err)
+		return err
+	}
+	socket := dirs.DaemonSocket()
+
+	// A socket that accepts connections belongs to a running daemon; any other file is left over
+	if conn, err := net.DialTimeout("unix", socket, time.Second); err == nil {
+		_ = conn.Close()
+		err := fmt.Errorf("a daemon is already listening on %s", socket)
+		logger.Error("%v", err)
+		return err
+	}
+	_ = os.Remove(socket)
+	if err := os.MkdirAll(filepath.Dir(socket), 0o700); err != nil {
+		logger.Error("Failed to create directory for %s: %v", socket, err)
+		return err
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		logger.Error("Failed to listen on %s: %v", socket, err)
+		return err
+	}
+	defer func() { _ = os.Remove(socket) }()
+	// Only the current user may talk to the daemon
+	if err := os.Chmod(socket, 0o600); err != nil {
+		_ = listener.Close()
+		logger.Error("Failed to restrict access to %s: %v", socket, err)
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Without a cache directory, only the latest versions are kept warm
+	var downloads *domain.DownloadCache
+	if dirs, err := domain.ResolveUserDirs(); err == nil {
+		downloads = domain.NewDownloadCache(dirs.DownloadCacheDir())
+	}
+	daemon := newVersionDaemon(c.TTL, logger, pkgmanager.All(), downloads)
+	go daemon.refreshLoop(ctx)
+
+	if c.Metrics != "" {
+		// The daemon has no TLS settings, so its metrics are only served on loopback
+		metricsListener, err := listenMetrics(c.Metrics, nil)
+		if err != nil {
+			_ = listener.Close()
+			logger.Error("Failed to listen on %s: %v", c.Metrics, err)
+			logger.Error("Check that the address is valid and not in use")
+			return err
+		}
+		server := newMetricsServer(metricsHandler(daemon.metricFamilies))
+		defer func() { _ = server.Close() }()
+
+		logger.Info("Serving metrics on %s", metricsListener.Addr())
+		go func() {
+			if err := server.Serve(metricsListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics server failed: %v", err)
+			}
+		}()
+	}
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	logger.Info("Daemon listening on %s", socket)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info("Daemon stopped")
+				return nil
+			}
+			logger.Error("Failed to accept connection: %v", err)
+			return err
+		}
+		go daemon.serveConn(ctx, conn)
+	}
+}
+
+// latestVersionParams are the params of the "latest_version" method.
+type latestVersionParams struct {
+	Source string `json:"source"`
+	URL    string `json:"url"`
+}
+
+type latestVersionResult struct {
+	Version string `json:"version"`
+}
+
+// versionDaemon answers latest version lookups from memory, refreshing the versions
+// that were requested recently in the background so that they stay warm. Each new latest
+// version is also downloaded into the download cache, so that installing it does not download it again.
+type versionDaemon struct {
+	logger          *Logger
+	entries         map[latestVersionParams]*versionEntry
+	packageManagers []port.PackageManager
+	downloads       *domain.DownloadCache // Nil when downloads are not prefetched
+	ttl             time.Duration
+	lookups         atomic.Int64 // Latest version requests from clients
+	memoryHits      atomic.Int64 // Requests answered from memory
+	fetchFailures   atomic.Int64 // Lookups with the package manager that failed, including refreshes
+	mu              sync.Mutex
+}
+
+type versionEntry struct {
+	fetchedAt   time.Time
+	requestedAt time.Time
+	version     string
+	prefetched  string // Version downloaded into the download cache, or being downloaded
+}
+
+func newVersionDaemon(ttl time.Duration, logger *Logger, packageManagers []port.PackageManager, downloads *domain.DownloadCache) *versionDaemon {
+	return &versionDaemon{
+		logger:          logger,
+		entries:         make(map[latestVersionParams]*versionEntry),
+		packageManagers: packageManagers,
+		downloads:       downloads,
+		ttl:             ttl,
+	}
+}
+
+// latestVersion returns the latest version of the source, looking it up when it is not in memory or expired.
+func (d *versionDaemon) latestVersion(ctx context.Context, params latestVersionParams) (string, error) {
+	d.lookups.Add(1)
+
+	d.mu.Lock()
+	entry, ok := d.entries[params]
+	if ok {
+		entry.requestedAt = time.Now()
+		if time.Since(entry.fetchedAt) < d.ttl {
+			d.mu.Unlock()
+			d.memoryHits.Add(1)
+			return entry.version, nil
+		}
+	}
+	d.mu.Unlock()
+
+	return d.fetch(ctx, params)
+}
+
+// fetch looks up the latest version with the package manager and keeps it in memory.
+func (d *versionDaemon) fetch(ctx context.Context, params latestVersionParams) (string, error) {
+	i := -1
+	for j, pm := range d.packageManagers {
+		if pm.SourceType() == params.Source {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return "", fmt.Errorf("unsupported source type '%s'", params.Source)
+	}
+
+	pm := d.packageManagers[i]
+	version, err := pm.GetLatestVersion(ctx, &port.Source{Type: params.Source, URL: params.URL})
+	if err != nil {
+		d.fetchFailures.Add(1)
+		return "", err
+	}
+
+	d.mu.Lock()
+	now := time.Now()
+	entry, ok := d.entries[params]
+	if !ok {
+		entry = &versionEntry{requestedAt: now}
+		d.entries[params] = entry
+	}
+	entry.version, entry.fetchedAt = version, now
+	// Local directories are read in place, so there is nothing to download ahead of time
+	prefetch := d.downloads != nil && params.Source != "local" && entry.prefetched != version
+	if prefetch {
+		entry.prefetched = version
+	}
+	d.mu.Unlock()
+
+	if prefetch {
+		go d.prefetch(ctx, pm, params, version)
+	}
+	return version, nil
+}
+
+// prefetch downloads the source at version into the download cache, unless it is already there.
+func (d *versionDaemon) prefetch(ctx context.Context, pm port.PackageManager, params latestVersionParams, version string) {
+	source := &port.Source{Type: params.Source, URL: params.URL}
+	if _, ok := d.downloads.Get(source, version); ok {
+		return
+	}
+
+	result, err := pm.Download(ctx, source, version)
+	if err == nil {
+		_, err = d.downloads.Put(source, version, result)
+		// Downloads from the Go module cache are shared with the go command
+		if !result.FromGoMod {
+			_ = os.RemoveAll(result.Path)
+		}
+	}
+	if err != nil {
+		d.logger.Verbose("Failed to download %s %s ahead of time: %v", params.URL, version, err)
+		// The next lookup tries again
+		d.mu.Lock()
+		if entry, ok := d.entries[params]; ok && entry.prefetched == version {
+			entry.prefetched = ""
+		}
+		d.mu.Unlock()
+	}
+}
+
+// metricFamilies returns the current values of the metrics of the daemon.
+func (d *versionDaemon) metricFamilies() []*metricFamily {
+	d.mu.Lock()
+	entries := len(d.entries)
+	d.mu.Unlock()
+
+	return []*metricFamily{
+		{
+			name: "skillspkg_daemon_lookups_total", help: "Latest version lookups requested by clients.", kind: "counter",
+			samples: []metricSample{{value: float64(d.lookups.Load())}},
+		},
+		{
+			name: "skillspkg_daemon_memory_hits_total", help: "Latest version lookups answered from memory.", kind: "counter",
+			samples: []metricSample{{value: float64(d.memoryHits.Load())}},
+		},
+		{
+			name: "skillspkg_daemon_fetch_failures_total", help: "Latest version lookups with the package manager that failed, including background refreshes.", kind: "counter",
+			samples: []metricSample{{value: float64(d.fetchFailures.Load())}},
+		},
+		{
+			name: "skillspkg_daemon_versions", help: "Latest versions kept in memory.", kind: "gauge",
+			samples: []metricSample{{value: float64(entries)}},
+		},
+	}
+}
+
+// refreshLoop looks up the versions that expire soon again, and forgets the versions
+// that were not requested for a day.
+func (d *versionDaemon) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(max(d.ttl/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var stale []latestVersionParams
+		d.mu.Lock()
+		for params, entry := range d.entries {
+			switch {
+			case time.Since(entry.requestedAt) > daemonKeepWarm:
+				delete(d.entries, params)
+			case time.Since(entry.fetchedAt) > d.ttl/2:
+				stale = append(stale, params)
+			}
+		}
+		d.mu.Unlock()
+
+		for _, params := range stale {
+			if _, err := d.fetch(ctx, params); err != nil {
+				d.logger.Verbose("Failed to refresh the latest version of %s: %v", params.URL, err)
+			}
+		}
+	}
+}
+
+// serveConn answers the JSON-RPC requests of a client until it disconnects.
+func (d *versionDaemon) serveConn(ctx context.Context, netConn net.Conn) {
+	defer func() { _ = netConn.Close() }()
+	conn := newRPCConn(netConn, netConn)
+
+	for {
+		body, err := conn.read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				d.logger.Verbose("Closing connection: %v", err)
+			}
+			return
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			_ = conn.write(&rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			continue
+		}
+
+		resp := &rpcResponse{JSONRPC: "2.0", ID: idOrNull(req.ID)}
+		switch req.Method {
+		case "latest_version":
+			var params latestVersionParams
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				resp.Error = &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+				break
+			}
+			version, err := d.latestVersion(ctx, params)
+			if err != nil {
+				resp.Error = &rpcError{Code: rpcInternalError, Message: err.Error()}
+				break
+			}
+			resp.Result = &latestVersionResult{Version: version}
+		default:
+			resp.Error = &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method '%s' not found", req.Method)}
+		}
+		if err := conn.write(resp); err != nil {
+			return
+		}
+	}
+}
+
+// daemonClient looks up latest versions through a running daemon.
+// Requests are sent one at a time over a single connection.
+type daemonClient struct {
+	conn   net.Conn
+	rpc    *rpcConn
+	nextID int
+	mu     sync.Mutex
+}
+
+// dialDaemon connects to the daemon listening on socket.
+func dialDaemon(socket string) (*daemonClient, error) {
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &daemonClient{conn: conn, rpc: newRPCConn(conn, conn)}, nil
+}
+
+// LatestVersion returns the latest version of the source known to the daemon.
+func (c *daemonClient) LatestVersion(source *port.Source) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	params, err := json.Marshal(&latestVersionParams{Source: source.Type, URL: source.URL})
+	if err != nil {
+		return "", err
+	}
+	if err := c.rpc.write(&rpcRequest{JSONRPC: "2.0", ID: json.RawMessage(fmt.Sprint(c.nextID)), Method: "latest_version", Params: params}); err != nil {
+		return "", fmt.Errorf("failed to send request to the daemon: %w", err)
+	}
+
+	body, err := c.rpc.read()
+	if err != nil {
+		return "", fmt.Errorf("failed to read response from the daemon: %w", err)
+	}
+	var resp struct {
+		Result *latestVersionResult `json:"result"`
+		Error  *rpcError            `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("invalid response from the daemon: %w", err)
+	}
+	if resp.Error != nil {
+		return "", errors.New(resp.Error.Message)
+	}
+	if resp.Result == nil {
+		return "", errors.New("empty response from the daemon")
+	}
+	return resp.Result.Version, nil
+}
+
+// Close closes the connection to the daemon.
+func (c *daemonClient) Close() error {
+	return c.conn.Close()
+}
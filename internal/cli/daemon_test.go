@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// countingPackageManager returns a fixed latest version and counts the lookups and downloads.
+type countingPackageManager struct {
+	version   string
+	calls     atomic.Int32
+	downloads atomic.Int32
+}
+
+func (m *countingPackageManager) SourceType() string {
+	return "git"
+}
+
+func (m *countingPackageManager) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	m.downloads.Add(1)
+	dir, err := os.MkdirTemp("", "skills-pkg-test-")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# Skill\n"), 0o644); err != nil {
+		return nil, err
+	}
+	return &port.DownloadResult{Path: dir, Version: version}, nil
+}
+
+func (m *countingPackageManager) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	m.calls.Add(1)
+	return m.version, nil
+}
+
+func TestVersionDaemon_LatestVersion(t *testing.T) {
+	t.Parallel()
+
+	pm := &countingPackageManager{version: "v1.2.0"}
+	var buf bytes.Buffer
+	daemon := newVersionDaemon(time.Hour, &Logger{out: &buf, dataOut: &buf, errOut: &buf}, []port.PackageManager{pm}, nil)
+
+	serverConn, clientConn := net.Pipe()
+	go daemon.serveConn(t.Context(), serverConn)
+	client := &daemonClient{conn: clientConn, rpc: newRPCConn(clientConn, clientConn)}
+	t.Cleanup(func() { _ = client.Close() })
+
+	source := &port.Source{Type: "git", URL: "https://github.com/example/skill.git"}
+	for range 2 {
+		version, err := client.LatestVersion(source)
+		if err != nil {
+			t.Fatalf("LatestVersion() error = %v", err)
+		}
+		if version != "v1.2.0" {
+			t.Errorf("LatestVersion() = %q, want %q", version, "v1.2.0")
+		}
+	}
+	if got := pm.calls.Load(); got != 1 {
+		t.Errorf("GetLatestVersion() called %d times, want 1 as the second lookup is served from memory", got)
+	}
+
+	if _, err := client.LatestVersion(&port.Source{Type: "npm", URL: "example"}); err == nil {
+		t.Error("LatestVersion() of an unsupported source type should fail")
+	}
+
+	var metrics bytes.Buffer
+	if err := writeMetrics(&metrics, daemon.metricFamilies()); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	for _, want := range []string{"skillspkg_daemon_lookups_total 3\n", "skillspkg_daemon_memory_hits_total 1\n", "skillspkg_daemon_versions 1\n"} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("metrics should contain %q, got:\n%s", want, metrics.String())
+		}
+	}
+}
+
+func TestVersionDaemon_PrefetchesLatestVersion(t *testing.T) {
+	t.Parallel()
+
+	pm := &countingPackageManager{version: "v1.2.0"}
+	var buf bytes.Buffer
+	cache := domain.NewDownloadCache(t.TempDir())
+	daemon := newVersionDaemon(time.Hour, &Logger{out: &buf, dataOut: &buf, errOut: &buf}, []port.PackageManager{pm}, cache)
+
+	params := latestVersionParams{Source: "git", URL: "https://github.com/example/skill.git"}
+	for range 2 {
+		if _, err := daemon.fetch(t.Context(), params); err != nil {
+			t.Fatalf("fetch() error = %v", err)
+		}
+	}
+
+	source := &port.Source{Type: params.Source, URL: params.URL}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if cached, ok := cache.Get(source, "v1.2.0"); ok {
+			if _, err := os.Stat(filepath.Join(cached.Path, "SKILL.md")); err != nil {
+				t.Errorf("prefetched download is missing SKILL.md: %v", err)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the latest version was not downloaded into the cache")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := pm.downloads.Load(); got != 1 {
+		t.Errorf("Download() called %d times, want 1 as the version did not change", got)
+	}
+}
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// debugLogTailLimit is the number of bytes kept from the end of each log of scheduled jobs.
+const debugLogTailLimit = 64 << 10
+
+// debugEnvPrefixes are the prefixes of the environment variables that affect skills-pkg and its downloads.
+var debugEnvPrefixes = []string{"SKILLSPKG_", "XDG_", "GOPROXY", "GOPRIVATE", "GONOSUMDB", "GONOPROXY", "GOFLAGS", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "DOCKER_CONFIG", "NPM_CONFIG_REGISTRY"}
+
+// DebugCmd represents the debug command group
+type DebugCmd struct {
+	Bundle DebugBundleCmd `cmd:"" help:"Collect the sanitized configuration, lock file, recent commands, environment, and logs into a zip file to attach to bug reports"`
+}
+
+// DebugBundleCmd represents the debug bundle command
+type DebugBundleCmd struct {
+	Output string `name:"out" short:"o" type:"path" placeholder:"FILE" help:"Path of the zip file to write (default: skills-pkg-debug-<time>.zip)"`
+
+	Version string `kong:"-"` // Set by Run from the version of the executable
+	Commit  string `kong:"-"` // Set by Run from the commit of the executable
+	Date    string `kong:"-"` // Set by Run from the build date of the executable
+}
+
+// bundleFile is a file written into the debug bundle.
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// Run executes the debug bundle command
+func (c *DebugBundleCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	if model := ctx.Model; model != nil {
+		vars := model.Vars()
+		c.Version, c.Commit, c.Date = vars["version"], vars["commit"], vars["date"]
+	}
+	return c.runWithLogger(defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger writes the debug bundle of the configuration at configPath (for testing)
+func (c *DebugBundleCmd) runWithLogger(configPath string, logger *Logger) error {
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	projectConfig, err := filepath.Abs(configPath)
+	if err != nil {
+		logger.Error("Failed to resolve configuration path: %v", err)
+		return err
+	}
+
+	files := []*bundleFile{{name: "environment.txt", data: c.environment(projectConfig, dirs)}}
+	for _, f := range []struct {
+		name     string
+		path     string
+		sanitize func([]byte) []byte
+	}{
+		{name: "project/" + filepath.Base(projectConfig), path: projectConfig, sanitize: sanitizeTOML},
+		{name: "project/" + filepath.Base(domain.LockPathFor(projectConfig)), path: domain.LockPathFor(projectConfig), sanitize: sanitizeTOML},
+		{name: "user/config.toml", path: dirs.ConfigFile(), sanitize: sanitizeTOML},
+		{name: "history.jsonl", path: dirs.HistoryFile(), sanitize: redactSecretBytes},
+		{name: "last-operation.log", path: dirs.LastOperationLog(), sanitize: redactSecretBytes},
+	} {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				logger.Verbose("Skipping %s: not found", f.path)
+				continue
+			}
+			logger.Error("Failed to read %s: %v", f.path, err)
+			return err
+		}
+		files = append(files, &bundleFile{name: f.name, data: f.sanitize(data)})
+	}
+
+	logs, err := filepath.Glob(filepath.Join(dirs.LogDir(), "*.log"))
+	if err != nil {
+		logger.Error("Failed to find logs: %v", err)
+		return err
+	}
+	for _, path := range logs {
+		data, err := readTail(path, debugLogTailLimit)
+		if err != nil {
+			logger.Error("Failed to read %s: %v", path, err)
+			return err
+		}
+		files = append(files, &bundleFile{name: "logs/" + filepath.Base(path), data: redactSecretBytes(data)})
+	}
+
+	output := c.Output
+	if output == "" {
+		output = fmt.Sprintf("skills-pkg-debug-%s.zip", time.Now().UTC().Format("20060102T150405Z"))
+	}
+	if err := writeDebugBundle(output, files); err != nil {
+		logger.Error("Failed to write debug bundle: %v", err)
+		return err
+	}
+
+	if abs, err := filepath.Abs(output); err == nil {
+		output = abs
+	}
+	logger.Info("Wrote debug bundle: %s", output)
+	for _, f := range files {
+		logger.Info("  %s", f.name)
+	}
+	logger.Info("Credentials in URLs and [network] headers are redacted, and secret keys are left out.")
+	logger.Info("Review the files before attaching the bundle to an issue.")
+	return nil
+}
+
+// environment describes the executable, the platform, the files and directories skills-pkg uses,
+// and the environment variables that affect it.
+func (c *DebugBundleCmd) environment(projectConfig string, dirs *domain.UserDirs) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "skills-pkg %s (commit %s, built %s)\n", c.Version, c.Commit, c.Date)
+	fmt.Fprintf(&b, "go: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if wd, err := os.Getwd(); err == nil {
+		fmt.Fprintf(&b, "working directory: %s\n", wd)
+	}
+
+	b.WriteString("\nFiles and directories:\n")
+	for _, v := range userDirVars(projectConfig, dirs) {
+		fmt.Fprintf(&b, "%s=%s\n", v.name, v.value)
+	}
+
+	b.WriteString("\nEnvironment variables:\n")
+	environ := os.Environ()
+	slices.Sort(environ)
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if !slices.ContainsFunc(debugEnvPrefixes, func(prefix string) bool { return strings.HasPrefix(strings.ToUpper(name), prefix) }) {
+			continue
+		}
+		if isSecretName(name) {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(&b, "%s=%s\n", name, redactSecrets(value))
+	}
+	return b.Bytes()
+}
+
+// isSecretName reports whether a setting or environment variable named name holds a credential.
+// Names of files and directories, such as SKILLSPKG_SECRET_KEY_FILE, only hold paths.
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, "_file") || strings.HasSuffix(name, "_dir") {
+		return false
+	}
+	for _, word := range []string{"token", "secret", "password", "passphrase", "api_key", "apikey", "signing_key"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeTOML redacts the credentials in a TOML file line by line, keeping its comments and layout:
+// the values of [network] headers and of keys named like credentials, and credentials in URLs.
+func sanitizeTOML(data []byte) []byte {
+	var b strings.Builder
+	table := ""
+	for line := range strings.Lines(string(data)) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "["):
+			if end := strings.LastIndex(trimmed, "]"); end > 0 {
+				table = strings.Trim(trimmed[:end], "[] ")
+			}
+		case strings.HasPrefix(trimmed, "#"):
+		default:
+			key, _, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				break
+			}
+			key = strings.TrimSpace(key)
+			path := strings.Split(table+"."+strings.ReplaceAll(key, `"`, ""), ".")
+			if slices.Contains(path, "headers") || isSecretName(path[len(path)-1]) {
+				indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+				line = indent + key + ` = "[REDACTED]"` + "\n"
+			}
+		}
+		b.WriteString(redactSecrets(line))
+	}
+	return []byte(b.String())
+}
+
+// redactSecretBytes replaces the credentials in URLs in data.
+func redactSecretBytes(data []byte) []byte {
+	return []byte(redactSecrets(string(data)))
+}
+
+// readTail returns the last limit bytes of the file at path.
+func readTail(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if offset := info.Size() - limit; offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
+
+// writeDebugBundle writes the files into a zip file at output, readable only by the current user,
+// removing it if writing fails.
+func writeDebugBundle(output string, files []*bundleFile) (err error) {
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close %s: %w", output, closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(output)
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	modified := time.Now()
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+	}
+	return zw.Close()
+}
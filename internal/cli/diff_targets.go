@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// DiffTargetsCmd represents the diff-targets command
+type DiffTargetsCmd struct {
+	Skill   string `arg:"" help:"Name of the skill to compare"`
+	TargetA string `arg:"" name:"target-a" help:"First install target directory"`
+	TargetB string `arg:"" name:"target-b" help:"Second install target directory"`
+
+	Output string `kong:"-"` // Set by Run from the global --output flag: text or json
+}
+
+// diffTargetsOutput is the JSON-serializable structure for diff-targets results.
+type diffTargetsOutput struct {
+	Skill     string               `json:"skill"`
+	A         *diffTargetsCopy     `json:"a"`
+	B         *diffTargetsCopy     `json:"b"`
+	FileDiffs []*diffTargetsChange `json:"file_diffs"`
+}
+
+// diffTargetsCopy describes the copy of the skill in one install target.
+type diffTargetsCopy struct {
+	Dir           string `json:"dir"`
+	Hash          string `json:"hash"`
+	MatchesConfig bool   `json:"matches_config"`
+}
+
+type diffTargetsChange struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Patch  string `json:"patch,omitempty"`
+}
+
+// Run executes the diff-targets command
+func (c *DiffTargetsCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.Output = outputFlag(ctx)
+	c.TargetA, c.TargetB = projectPath(c.TargetA), projectPath(c.TargetB)
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *DiffTargetsCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger compares the skill in the two install targets (for testing)
+func (c *DiffTargetsCmd) runWithLogger(configPath string, logger *Logger) error {
+	ctx := context.Background()
+
+	config, err := newConfigManager(configPath).Load(ctx)
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	skill := config.FindInstalledSkill(c.Skill)
+	if skill == nil {
+		err := &domain.ErrorSkillsNotFound{SkillNames: []string{c.Skill}}
+		logger.Error("Skill '%s' not found in configuration", c.Skill)
+		logger.Error("Run 'skills-pkg list' to see the configured skills")
+		return err
+	}
+
+	hashService := service.NewDirhash()
+	copies := make([]*diffTargetsCopy, 0, 2)
+	for _, target := range []string{c.TargetA, c.TargetB} {
+		copied, err := c.inspect(ctx, hashService, config, skill, target)
+		if err != nil {
+			logger.Error("%v", err)
+			return err
+		}
+		copies = append(copies, copied)
+	}
+
+	logger.Verbose("Comparing %s with %s", copies[0].Dir, copies[1].Dir)
+	fileDiffs, err := domain.DiffSkillDirs(copies[0].Dir, copies[1].Dir, config.GeneratedFiles()...)
+	if err != nil {
+		logger.Error("Failed to compare skill '%s': %v", skill.Name, err)
+		return err
+	}
+
+	if c.Output == "json" {
+		changes := make([]*diffTargetsChange, 0, len(fileDiffs))
+		for _, fd := range fileDiffs {
+			changes = append(changes, &diffTargetsChange{
+				Path:   filepath.ToSlash(fd.Path),
+				Status: string(fd.Status),
+				Patch:  fd.Patch,
+			})
+		}
+		data, err := json.MarshalIndent(diffTargetsOutput{Skill: skill.Name, A: copies[0], B: copies[1], FileDiffs: changes}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON output: %w", err)
+		}
+		_, err = fmt.Fprintln(logger.dataOut, string(data))
+		return err
+	}
+
+	return printTargetDiff(logger, skill, copies, fileDiffs)
+}
+
+// inspect resolves the skill directory in the install target and hashes it
+// with the algorithm of the hash recorded in the configuration.
+func (c *DiffTargetsCmd) inspect(ctx context.Context, hashService port.HashService, config *domain.Config, skill *domain.Skill, target string) (*diffTargetsCopy, error) {
+	if domain.IsRemoteTarget(target) {
+		return nil, fmt.Errorf("install target %s is on another machine and cannot be compared locally", target)
+	}
+
+	dir, err := filepath.Abs(filepath.Join(target, skill.DirName()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", target, err)
+	}
+	if !dirExists(dir) {
+		return nil, fmt.Errorf("skill '%s' is not installed in %s", skill.Name, target)
+	}
+
+	hash, err := hashService.CalculateHash(ctx, dir, port.HashAlgorithmOf(skill.HashValue), config.HashOptions(skill)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate hash of %s: %w", dir, err)
+	}
+
+	return &diffTargetsCopy{
+		Dir:           dir,
+		Hash:          hash.Value,
+		MatchesConfig: skill.HashValue != "" && hash.Value == skill.HashValue,
+	}, nil
+}
+
+// printTargetDiff prints a human-readable comparison. Files only in the first target are
+// marked with "-", files only in the second with "+", and modified files with "~".
+func printTargetDiff(logger *Logger, skill *domain.Skill, copies []*diffTargetsCopy, fileDiffs []*domain.FileDiff) error {
+	var b strings.Builder
+	for i, copied := range copies {
+		state := "differs from the hash in the configuration"
+		switch {
+		case skill.HashValue == "":
+			state = "no hash in the configuration"
+		case copied.MatchesConfig:
+			state = "matches the configuration"
+		}
+		fmt.Fprintf(&b, "%c: %s (%s)\n", 'A'+i, copied.Dir, state)
+	}
+
+	for _, fd := range fileDiffs {
+		switch fd.Status {
+		case domain.FileDiffRemoved:
+			fmt.Fprintf(&b, "  - %s (only in A)\n", filepath.ToSlash(fd.Path))
+		case domain.FileDiffAdded:
+			fmt.Fprintf(&b, "  + %s (only in B)\n", filepath.ToSlash(fd.Path))
+		case domain.FileDiffModified:
+			fmt.Fprintf(&b, "  ~ %s\n", filepath.ToSlash(fd.Path))
+			if fd.Patch == "" {
+				b.WriteString("      (binary files differ)\n")
+			}
+			for line := range strings.SplitSeq(strings.TrimRight(fd.Patch, "\n"), "\n") {
+				if line != "" {
+					fmt.Fprintf(&b, "      %s\n", line)
+				}
+			}
+		}
+	}
+
+	if len(fileDiffs) == 0 {
+		b.WriteString("No differences\n")
+	} else {
+		fmt.Fprintf(&b, "%d file(s) differ\n", len(fileDiffs))
+		if !copies[0].MatchesConfig || !copies[1].MatchesConfig {
+			fmt.Fprintf(&b, "Run 'skills-pkg install %s' to restore copies that differ from the configuration\n", skill.Name)
+		}
+	}
+
+	_, err := fmt.Fprint(logger.dataOut, b.String())
+	return err
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestDiffTargetsCmd_Run(t *testing.T) {
+	tests := []struct {
+		cmd          *DiffTargetsCmd
+		name         string
+		wantContains []string
+		wantErr      bool
+	}{
+		{
+			name: "identical copies",
+			cmd:  &DiffTargetsCmd{Skill: "my-skill", TargetA: "claude", TargetB: "agents", Output: "text"},
+			wantContains: []string{
+				"(matches the configuration)",
+				"No differences",
+			},
+		},
+		{
+			name: "stale copy",
+			cmd:  &DiffTargetsCmd{Skill: "my-skill", TargetA: "claude", TargetB: "codex", Output: "text"},
+			wantContains: []string{
+				"(differs from the hash in the configuration)",
+				"  ~ SKILL.md",
+				"      -Current instructions",
+				"      +Old instructions",
+				"  - references/guide.md (only in A)",
+				"2 file(s) differ",
+				"skills-pkg install my-skill",
+			},
+		},
+		{
+			name:    "skill not installed in a target",
+			cmd:     &DiffTargetsCmd{Skill: "my-skill", TargetA: "claude", TargetB: "empty", Output: "text"},
+			wantErr: true,
+		},
+		{
+			name:    "remote target",
+			cmd:     &DiffTargetsCmd{Skill: "my-skill", TargetA: "claude", TargetB: "ssh://host/skills", Output: "text"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown skill",
+			cmd:     &DiffTargetsCmd{Skill: "missing", TargetA: "claude", TargetB: "codex", Output: "text"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir, configPath := setupDiffTargetsProject(t)
+
+			cmd := *tt.cmd
+			for _, target := range []*string{&cmd.TargetA, &cmd.TargetB} {
+				if !domain.IsRemoteTarget(*target) {
+					*target = filepath.Join(projectDir, *target)
+				}
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+
+			err := cmd.runWithLogger(configPath, logger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithLogger() error = %v, wantErr %v\nstderr: %s", err, tt.wantErr, errOut.String())
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDiffTargetsCmd_Run_JSON(t *testing.T) {
+	projectDir, configPath := setupDiffTargetsProject(t)
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	cmd := &DiffTargetsCmd{
+		Skill:   "my-skill",
+		TargetA: filepath.Join(projectDir, "claude"),
+		TargetB: filepath.Join(projectDir, "codex"),
+		Output:  "json",
+	}
+	if err := cmd.runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("runWithLogger() error = %v\nstderr: %s", err, errOut.String())
+	}
+
+	var got diffTargetsOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if !got.A.MatchesConfig || got.B.MatchesConfig {
+		t.Errorf("matches_config = (%v, %v), want (true, false)", got.A.MatchesConfig, got.B.MatchesConfig)
+	}
+	if len(got.FileDiffs) != 2 {
+		t.Fatalf("got %d file diffs, want 2: %+v", len(got.FileDiffs), got.FileDiffs)
+	}
+	if got.FileDiffs[0].Path != "SKILL.md" || got.FileDiffs[0].Status != "modified" || got.FileDiffs[0].Patch == "" {
+		t.Errorf("file_diffs[0] = %+v, want a patch for the modified SKILL.md", got.FileDiffs[0])
+	}
+	if got.FileDiffs[1].Path != "references/guide.md" || got.FileDiffs[1].Status != "removed" {
+		t.Errorf("file_diffs[1] = %+v, want removed references/guide.md", got.FileDiffs[1])
+	}
+}
+
+// setupDiffTargetsProject creates a project whose skill is installed in claude and agents,
+// and an outdated copy in codex.
+func setupDiffTargetsProject(t *testing.T) (string, string) {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, ".skillspkg.toml")
+
+	files := map[string]map[string]string{
+		"claude": {"SKILL.md": "# My Skill\nCurrent instructions\n", "references/guide.md": "guide\n"},
+		"agents": {"SKILL.md": "# My Skill\nCurrent instructions\n", "references/guide.md": "guide\n"},
+		"codex":  {"SKILL.md": "# My Skill\nOld instructions\n"},
+	}
+	for target, contents := range files {
+		for name, content := range contents {
+			path := filepath.Join(projectDir, target, "my-skill", filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatalf("failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", path, err)
+			}
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(projectDir, "empty"), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	hash, err := service.NewDirhash().CalculateHash(context.Background(), filepath.Join(projectDir, "claude", "my-skill"), "")
+	if err != nil {
+		t.Fatalf("failed to calculate hash: %v", err)
+	}
+	config := &domain.Config{
+		Skills:         []*domain.Skill{{Name: "my-skill", Source: "git", URL: "https://github.com/example/skill.git", HashValue: hash.Value}},
+		InstallTargets: []string{filepath.Join(projectDir, "claude")},
+	}
+	if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	return projectDir, configPath
+}
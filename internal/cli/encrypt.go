@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// EncryptCmd represents the encrypt command
+type EncryptCmd struct {
+	Value     string   `arg:"" optional:"" help:"Value to encrypt; read from standard input when omitted, which keeps it out of the shell history"`
+	Skill     string   `help:"Encrypt the URL of this skill in .skillspkg.toml instead of printing an encrypted value" xor:"mode"`
+	Rotate    bool     `help:"Encrypt every encrypted value in .skillspkg.toml again to the current recipients, after recipients are added or removed" xor:"mode"`
+	Recipient []string `help:"Recipient to encrypt to in addition to the recipients in .skillspkg.toml (can be specified multiple times)" short:"r"`
+}
+
+// Run executes the encrypt command
+func (c *EncryptCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(context.Background(), defaultConfigPath, os.Stdin, NewLogger(verbose))
+}
+
+// runWithLogger encrypts the value, or the URL of the skill, to the recipients (for testing)
+func (c *EncryptCmd) runWithLogger(ctx context.Context, configPath string, stdin io.Reader, logger *Logger) error {
+	configManager := newConfigManager(configPath)
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		// A value can be encrypted without a configuration when the recipients are given
+		if _, ok := errors.AsType[*domain.ErrorConfigNotFound](err); !ok || c.Skill != "" || c.Rotate || len(c.Recipient) == 0 {
+			logger.Error("Failed to load configuration: %v", err)
+			return err
+		}
+		config = &domain.Config{}
+	}
+	recipients := slices.Concat(config.Recipients, c.Recipient)
+
+	if c.Skill != "" {
+		return c.encryptSkillURL(ctx, configManager, config, recipients, logger)
+	}
+	if c.Rotate {
+		return c.rotate(ctx, configManager, config, recipients, logger)
+	}
+
+	value := c.Value
+	if value == "" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read the value from standard input: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	if value == "" {
+		err := errors.New("no value to encrypt")
+		logger.Error("%v", err)
+		return err
+	}
+
+	encrypted, err := domain.EncryptValue(value, recipients)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	_, err = fmt.Fprintln(logger.dataOut, encrypted)
+	return err
+}
+
+// encryptSkillURL replaces the URL of the skill in the configuration with its encrypted form.
+func (c *EncryptCmd) encryptSkillURL(ctx context.Context, configManager *domain.ConfigManager, config *domain.Config, recipients []string, logger *Logger) error {
+	skill := config.FindSkillByName(c.Skill)
+	if skill == nil {
+		err := &domain.ErrorSkillsNotFound{SkillNames: []string{c.Skill}}
+		logger.Error("%v", err)
+		return err
+	}
+	// Encrypted URLs are decrypted on load, or left encrypted without a matching secret key
+	if domain.IsEncrypted(skill.URL) || config.Sealed(skill.URL) != skill.URL {
+		logger.Info("The URL of skill '%s' is already encrypted", skill.Name)
+		return nil
+	}
+
+	if err := config.Encrypt(skill.URL, recipients); err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	if err := configManager.Save(ctx, config); err != nil {
+		logger.Error("Failed to save configuration: %v", err)
+		return err
+	}
+	logger.Info("Encrypted the URL of skill '%s'", skill.Name)
+
+	return nil
+}
+
+// rotate encrypts every encrypted value in the configuration again to the recipients, so that added
+// recipients can decrypt them and removed ones cannot decrypt the new values.
+func (c *EncryptCmd) rotate(ctx context.Context, configManager *domain.ConfigManager, config *domain.Config, recipients []string, logger *Logger) error {
+	count, err := config.ReencryptSecrets(recipients)
+	if err != nil {
+		logger.Error("Failed to encrypt the values again: %v", err)
+		return err
+	}
+	if count == 0 {
+		logger.Info("No encrypted values in the configuration")
+		return nil
+	}
+
+	if err := configManager.Save(ctx, config); err != nil {
+		logger.Error("Failed to save configuration: %v", err)
+		return err
+	}
+	logger.Info("Encrypted %d value(s) again to %d recipient(s)", count, len(recipients))
+
+	return nil
+}
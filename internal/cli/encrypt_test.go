@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestKeygenAndEncrypt(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "skills-pkg", "keys.txt")
+	t.Setenv(domain.SecretKeyEnv, "")
+	t.Setenv(domain.SecretKeyFileEnv, keyFile)
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	if err := (&KeygenCmd{Output: keyFile}).runWithLogger(logger); err != nil {
+		t.Fatalf("keygen error = %v\nstderr: %s", err, errOut.String())
+	}
+	recipient := strings.TrimSpace(out.String())
+	if err := domain.ValidateRecipient(recipient); err != nil {
+		t.Fatalf("keygen printed %q: %v", recipient, err)
+	}
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 && filepath.Separator == '/' {
+		t.Errorf("key file permissions = %v, want readable by the owner only", perm)
+	}
+
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	configManager := domain.NewConfigManager(configPath)
+	const url = "https://token@git.example.com/team/skills.git"
+	if err := configManager.Save(ctx, &domain.Config{
+		Skills:         []*domain.Skill{{Name: "private", Source: "git", URL: url, Version: "v1.0.0"}},
+		InstallTargets: []string{".claude/skills"},
+		Recipients:     []string{recipient},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A value read from standard input
+	out.Reset()
+	if err := (&EncryptCmd{}).runWithLogger(ctx, configPath, strings.NewReader(url+"\n"), logger); err != nil {
+		t.Fatalf("encrypt error = %v\nstderr: %s", err, errOut.String())
+	}
+	keys, err := domain.LoadSecretKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := domain.DecryptValue(strings.TrimSpace(out.String()), keys); err != nil || got != url {
+		t.Errorf("encrypted value decrypts to %q, %v, want %q", got, err, url)
+	}
+
+	// The URL of a skill in the configuration
+	if err := (&EncryptCmd{Skill: "private"}).runWithLogger(ctx, configPath, strings.NewReader(""), logger); err != nil {
+		t.Fatalf("encrypt --skill error = %v\nstderr: %s", err, errOut.String())
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), url) || !strings.Contains(string(data), "ENC[v1,") {
+		t.Fatalf("saved configuration does not have the URL encrypted:\n%s", data)
+	}
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skill := config.FindSkillByName("private")
+	source, err := config.SourceOf(skill)
+	if err != nil {
+		t.Fatalf("SourceOf() error = %v", err)
+	}
+	if source.URL != url {
+		t.Errorf("SourceOf().URL = %q, want %q", source.URL, url)
+	}
+
+	// Rotating encrypts the value again, to the recipients of the configuration and the additional ones
+	other, err := domain.GenerateSecretKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&EncryptCmd{Rotate: true, Recipient: []string{other.Recipient()}}).runWithLogger(ctx, configPath, strings.NewReader(""), logger); err != nil {
+		t.Fatalf("encrypt --rotate error = %v\nstderr: %s", err, errOut.String())
+	}
+	t.Setenv(domain.SecretKeyFileEnv, filepath.Join(t.TempDir(), "none.txt"))
+	t.Setenv(domain.SecretKeyEnv, other.String())
+	config, err = configManager.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.FindSkillByName("private").URL; got != url {
+		t.Errorf("URL decrypted with the added recipient = %q, want %q", got, url)
+	}
+
+	if err := (&EncryptCmd{Skill: "missing"}).runWithLogger(ctx, configPath, strings.NewReader(""), logger); err == nil {
+		t.Error("encrypt --skill of a missing skill succeeded")
+	}
+}
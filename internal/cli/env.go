@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// EnvCmd represents the env command
+type EnvCmd struct {
+	Names []string `arg:"" optional:"" help:"Only print the values of these variables"`
+
+	Output string `kong:"-"` // Set by Run from the global --output flag: text or json
+}
+
+// envVar is a single entry printed by the env command.
+type envVar struct {
+	name  string
+	value string
+}
+
+// Run executes the env command
+func (c *EnvCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.Output = outputFlag(ctx)
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *EnvCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger prints the paths skills-pkg uses (for testing)
+func (c *EnvCmd) runWithLogger(configPath string, logger *Logger) error {
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	projectConfig, err := filepath.Abs(configPath)
+	if err != nil {
+		logger.Error("Failed to resolve configuration path: %v", err)
+		return err
+	}
+
+	vars := userDirVars(projectConfig, dirs)
+
+	if len(c.Names) > 0 {
+		selected := make([]envVar, 0, len(c.Names))
+		for _, name := range c.Names {
+			i := slices.IndexFunc(vars, func(v envVar) bool { return v.name == name })
+			if i < 0 {
+				err := fmt.Errorf("unknown variable %s", name)
+				logger.Error("%v", err)
+				logger.Error("Run 'skills-pkg env' to list all variables")
+				return err
+			}
+			selected = append(selected, vars[i])
+		}
+		vars = selected
+	}
+
+	switch {
+	case c.Output == "json":
+		values := make(map[string]string, len(vars))
+		for _, v := range vars {
+			values[v.name] = v.value
+		}
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON output: %w", err)
+		}
+		_, err = fmt.Fprintln(logger.dataOut, string(data))
+		return err
+	case len(c.Names) > 0:
+		// Plain values, so that a single variable can be used in scripts: $(skills-pkg env SKILLSPKG_CACHE_DIR)
+		for _, v := range vars {
+			if _, err := fmt.Fprintln(logger.dataOut, v.value); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, v := range vars {
+			if _, err := fmt.Fprintf(logger.dataOut, "%s='%s'\n", v.name, strings.ReplaceAll(v.value, "'", `'\''`)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// userDirVars returns the files and directories of the project configuration at projectConfig and of the user.
+func userDirVars(projectConfig string, dirs *domain.UserDirs) []envVar {
+	return []envVar{
+		{name: "SKILLSPKG_CONFIG", value: projectConfig},
+		{name: "SKILLSPKG_LOCK", value: domain.LockPathFor(projectConfig)},
+		{name: "SKILLSPKG_REVIEWS", value: domain.ReviewPathFor(projectConfig)},
+		{name: "SKILLSPKG_USER_CONFIG", value: dirs.ConfigFile()},
+		{name: "SKILLSPKG_CONFIG_DIR", value: dirs.Config},
+		{name: "SKILLSPKG_SECRET_KEY_FILE", value: dirs.SecretKeyFile()},
+		{name: "SKILLSPKG_DATA_DIR", value: dirs.Data},
+		{name: "SKILLSPKG_STORE_DIR", value: dirs.StoreDir()},
+		{name: "SKILLSPKG_CACHE_DIR", value: dirs.Cache},
+		{name: "SKILLSPKG_DOWNLOAD_CACHE_DIR", value: dirs.DownloadCacheDir()},
+		{name: "SKILLSPKG_DAEMON_SOCKET", value: dirs.DaemonSocket()},
+		{name: "SKILLSPKG_STATE_DIR", value: dirs.State},
+		{name: "SKILLSPKG_LOG_DIR", value: dirs.LogDir()},
+		{name: "SKILLSPKG_BACKUP_DIR", value: dirs.BackupDir()},
+		{name: "SKILLSPKG_TEMP_DIR", value: dirs.Temp},
+	}
+}
+
+// resolveUserDirs returns the user directories of skills-pkg, first moving files that
+// earlier versions left in legacy locations. A failed migration is reported but not fatal.
+func resolveUserDirs(logger *Logger) (*domain.UserDirs, error) {
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := domain.MigrateLegacyUserDirs(dirs)
+	for _, m := range moved {
+		logger.Info("Moved %s", m)
+	}
+	if err != nil {
+		logger.Error("Warning: failed to move files from their legacy location: %v", err)
+	}
+
+	return dirs, nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvCmd_Run(t *testing.T) {
+	stateHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	logDir := filepath.Join(stateHome, "skills-pkg", "logs")
+
+	tests := []struct {
+		checkFunc func(t *testing.T, output string)
+		name      string
+		output    string
+		names     []string
+		wantErr   bool
+	}{
+		{
+			name:   "all variables as text",
+			output: "text",
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+				for _, want := range []string{
+					"SKILLSPKG_CONFIG='" + configPath + "'",
+					"SKILLSPKG_LOCK='" + filepath.Join(filepath.Dir(configPath), ".skillspkg.lock") + "'",
+					"SKILLSPKG_LOG_DIR='" + logDir + "'",
+				} {
+					if !strings.Contains(output, want) {
+						t.Errorf("output missing %q:\n%s", want, output)
+					}
+				}
+			},
+		},
+		{
+			name:   "selected variable prints plain value",
+			output: "text",
+			names:  []string{"SKILLSPKG_STATE_DIR"},
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+				if want := filepath.Join(stateHome, "skills-pkg") + "\n"; output != want {
+					t.Errorf("output = %q, want %q", output, want)
+				}
+			},
+		},
+		{
+			name:   "json output",
+			output: "json",
+			names:  []string{"SKILLSPKG_LOG_DIR"},
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+				var values map[string]string
+				if err := json.Unmarshal([]byte(output), &values); err != nil {
+					t.Fatalf("invalid JSON output: %v\n%s", err, output)
+				}
+				if len(values) != 1 || values["SKILLSPKG_LOG_DIR"] != logDir {
+					t.Errorf("values = %v, want only SKILLSPKG_LOG_DIR=%s", values, logDir)
+				}
+			},
+		},
+		{
+			name:    "unknown variable",
+			output:  "text",
+			names:   []string{"SKILLSPKG_UNKNOWN"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+			cmd := &EnvCmd{Output: tt.output, Names: tt.names}
+
+			err := cmd.runWithLogger(configPath, logger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithLogger() error = %v, wantErr %v\nstderr: %s", err, tt.wantErr, errOut.String())
+			}
+			if tt.checkFunc != nil {
+				tt.checkFunc(t, out.String())
+			}
+		})
+	}
+}
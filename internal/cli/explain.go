@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// explainDocs holds the documentation printed by the explain command: remediation guidance
+// for each error code in codes, and descriptions of configuration keys and source types in
+// config and sources. The first line of each description is its summary.
+//
+//go:embed explain
+var explainDocs embed.FS
+
+// explainTopics are the kinds of documentation besides error codes, in the order they are listed.
+var explainTopics = []struct {
+	title string
+	dir   string
+}{
+	{title: "Configuration keys", dir: "explain/config"},
+	{title: "Source types", dir: "explain/sources"},
+}
+
+// ExplainCmd represents the explain command
+type ExplainCmd struct {
+	Topic string `arg:"" optional:"" help:"Error code (such as SKP1203), configuration key (such as hash_mismatch), or source type (such as git) to explain; lists all topics when omitted"`
+}
+
+// Run executes the explain command
+func (c *ExplainCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(NewLogger(verbose))
+}
+
+// runWithLogger prints the documentation of the topic, or the list of topics (for testing)
+func (c *ExplainCmd) runWithLogger(logger *Logger) error {
+	if c.Topic == "" {
+		return listExplainTopics(logger.dataOut)
+	}
+
+	if code := domain.LookupErrorCode(c.Topic); code != nil {
+		doc, err := explainDocs.ReadFile("explain/codes/" + code.Code + ".md")
+		if err != nil {
+			return fmt.Errorf("no guidance for error code %s: %w", code.Code, err)
+		}
+		_, err = fmt.Fprintf(logger.dataOut, "%s: %s (%s)\n\n%s", code.Code, code.Summary, retryAdvice(code), doc)
+		return err
+	}
+
+	// Nested configuration keys, such as copy.fsync, are described with their table
+	topic := strings.ToLower(c.Topic)
+	table, _, _ := strings.Cut(topic, ".")
+	for _, name := range []string{topic, table} {
+		for _, t := range explainTopics {
+			doc, err := explainDocs.ReadFile(path.Join(t.dir, name+".md"))
+			if err != nil {
+				continue
+			}
+			_, err = fmt.Fprintf(logger.dataOut, "%s: %s", name, doc)
+			return err
+		}
+	}
+
+	err := fmt.Errorf("nothing to explain for %s", c.Topic)
+	logger.Error("%v", err)
+	logger.Error("Run 'skills-pkg explain' to list all error codes, configuration keys, and source types")
+	return err
+}
+
+// listExplainTopics prints every topic with its summary, grouped by kind.
+func listExplainTopics(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "Error codes:"); err != nil {
+		return err
+	}
+	for _, code := range domain.ErrorCodes() {
+		if _, err := fmt.Fprintf(w, "  %-20s %s\n", code.Code, code.Summary); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range explainTopics {
+		entries, err := explainDocs.ReadDir(t.dir)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "\n%s:\n", t.title); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			doc, err := explainDocs.ReadFile(path.Join(t.dir, entry.Name()))
+			if err != nil {
+				return err
+			}
+			summary, _, _ := strings.Cut(string(doc), "\n")
+			if _, err := fmt.Fprintf(w, "  %-20s %s\n", strings.TrimSuffix(entry.Name(), ".md"), summary); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// retryAdvice tells whether running the command again may help with failures of the code.
+func retryAdvice(code *domain.ErrorCode) string {
+	if code.Retryable {
+		return "retrying may succeed"
+	}
+	return "retrying will not help until the cause is fixed"
+}
+
+// ReportError prints the error code of err and how to look up its remediation guidance. Commands
+// print their own error messages, so this only adds the code after them.
+func ReportError(w io.Writer, err error) {
+	code := domain.CodeOf(err)
+	if code == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Error code %s: %s. Run 'skills-pkg explain %s' for details\n", code.Code, retryAdvice(code), code.Code)
+}
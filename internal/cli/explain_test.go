@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestExplainCmd(t *testing.T) {
+	// Every code has guidance
+	for _, code := range domain.ErrorCodes() {
+		var out, errOut bytes.Buffer
+		logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+		if err := (&ExplainCmd{Topic: strings.ToLower(code.Code)}).runWithLogger(logger); err != nil {
+			t.Errorf("explain %s error = %v", code.Code, err)
+			continue
+		}
+		if !strings.HasPrefix(out.String(), code.Code+": "+code.Summary) || len(out.String()) < 100 {
+			t.Errorf("explain %s printed:\n%s", code.Code, out.String())
+		}
+	}
+
+	// Every top-level configuration key is described
+	configType := reflect.TypeFor[domain.Config]()
+	for i := range configType.NumField() {
+		if !configType.Field(i).IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(configType.Field(i).Tag.Get("toml"), ",")
+		var out, errOut bytes.Buffer
+		logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+		if err := (&ExplainCmd{Topic: key}).runWithLogger(logger); err != nil {
+			t.Errorf("explain %s error = %v", key, err)
+		} else if !strings.HasPrefix(out.String(), key+": ") {
+			t.Errorf("explain %s printed:\n%s", key, out.String())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		topic   string
+		want    string
+		wantErr bool
+	}{
+		{name: "list", topic: "", want: "  SKP1203              Repository, module, or version not found\n"},
+		{name: "list config keys", topic: "", want: "  hash_mismatch        What to do when skill content does not match its recorded hash_value\n"},
+		{name: "source type", topic: "go-mod", want: "go-mod: Download the skill as a Go module"},
+		{name: "nested key", topic: "defaults.git.version", want: "defaults: Per-source defaults"},
+		{name: "case-insensitive", topic: "GIT", want: "git: Clone the skill"},
+		{name: "unknown code", topic: "SKP0000", wantErr: true},
+		{name: "unknown topic", topic: "../explain.go", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+			err := (&ExplainCmd{Topic: tt.topic}).runWithLogger(logger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestReportError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: ""},
+		{err: fmt.Errorf("%w: timeout", domain.ErrNetworkFailure), want: "Error code SKP1201: retrying may succeed. Run 'skills-pkg explain SKP1201' for details\n"},
+		{err: errors.New("boom"), want: "Error code SKP1999: retrying will not help until the cause is fixed. Run 'skills-pkg explain SKP1999' for details\n"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		ReportError(&out, tt.err)
+		if out.String() != tt.want {
+			t.Errorf("ReportError(%v) = %q, want %q", tt.err, out.String(), tt.want)
+		}
+	}
+}
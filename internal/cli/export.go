@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// homePlaceholder stands for the home directory in exported install targets.
+// It is replaced with the home directory of the machine the skills are installed on.
+const homePlaceholder = "@@HOME@@"
+
+// ExportCmd represents the export command
+type ExportCmd struct {
+	Format  string `help:"Output format: chezmoi (run_onchange_ script) or home-manager (Nix module)" enum:"chezmoi,home-manager" default:"chezmoi"`
+	Output  string `name:"out" short:"o" type:"path" help:"Write the manifest to a file instead of stdout"`
+	Version string `help:"skills-pkg version installed by the chezmoi script when skills-pkg is missing" default:"latest"`
+}
+
+//go:embed templates/export.chezmoi.sh.tmpl
+var exportChezmoiTemplate string
+
+//go:embed templates/export.home-manager.nix.tmpl
+var exportHomeManagerTemplate string
+
+var (
+	exportChezmoi     = template.Must(template.New("chezmoi").Parse(exportChezmoiTemplate))
+	exportHomeManager = template.Must(template.New("home-manager").Parse(exportHomeManagerTemplate))
+)
+
+// Run executes the export command
+func (c *ExportCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *ExportCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger exports the skill set as a manifest for a dotfile manager (for testing)
+func (c *ExportCmd) runWithLogger(configPath string, logger *Logger) error {
+	logger.Verbose("Loading configuration from %s", configPath)
+
+	config, err := newConfigManager(configPath).Load(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+
+	manifest, err := c.manifest(c.portableConfig(config, home, logger))
+	if err != nil {
+		logger.Error("Failed to generate %s manifest: %v", c.Format, err)
+		return err
+	}
+
+	if c.Output == "" {
+		_, err = logger.dataOut.Write(manifest)
+		return err
+	}
+
+	perm := os.FileMode(setupCIFilePerm)
+	if c.Format == "chezmoi" {
+		perm = 0o755 // chezmoi runs the script directly
+	}
+	if err := os.WriteFile(c.Output, manifest, perm); err != nil {
+		logger.Error("Failed to write %s: %v", c.Output, err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+	logger.Info("Created %s", c.Output)
+
+	return nil
+}
+
+// portableConfig returns a copy of the configuration that can be installed on another machine.
+// Local install targets are made absolute, and those under the home directory are rewritten
+// relative to homePlaceholder, so that they follow the home directory of the other machine.
+func (c *ExportCmd) portableConfig(config *domain.Config, home string, logger *Logger) *domain.Config {
+	exported := *config
+
+	exported.InstallTargets = make([]string, 0, len(config.InstallTargets))
+	for _, target := range config.InstallTargets {
+		exported.InstallTargets = append(exported.InstallTargets, portableTarget(target, home))
+	}
+	if config.Targets != nil {
+		exported.Targets = make(map[string]*domain.TargetSettings, len(config.Targets))
+		for target, settings := range config.Targets {
+			exported.Targets[portableTarget(target, home)] = settings
+		}
+	}
+
+	for _, skill := range config.Skills {
+		if skill.Source == "go-mod" && skill.Version == "" && config.Defaults.UseGoMod() {
+			logger.Error("Warning: skill '%s' takes its version from the project's go.mod, which is not exported. Pin a version with 'skills-pkg update %s' to reproduce it", skill.Name, skill.Name)
+		}
+	}
+
+	return &exported
+}
+
+// portableTarget returns the install target rewritten relative to homePlaceholder when it is
+// under the home directory, and as an absolute path otherwise.
+func portableTarget(target, home string) string {
+	if domain.IsRemoteTarget(target) {
+		return target
+	}
+
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return target
+	}
+	if home != "" {
+		if rel, err := filepath.Rel(home, abs); err == nil && filepath.IsLocal(rel) {
+			return homePlaceholder + "/" + filepath.ToSlash(rel)
+		}
+	}
+	return abs
+}
+
+// manifest renders the manifest of the selected format embedding the configuration.
+func (c *ExportCmd) manifest(config *domain.Config) ([]byte, error) {
+	data, err := domain.EncodeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	encoded := string(data)
+	if !strings.HasSuffix(encoded, "\n") {
+		encoded += "\n"
+	}
+
+	tmpl := exportChezmoi
+	if c.Format == "home-manager" {
+		tmpl = exportHomeManager
+		encoded = nixIndentedString(encoded)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Config          string
+		HomePlaceholder string
+		Version         string
+	}{
+		Config:          encoded,
+		HomePlaceholder: homePlaceholder,
+		Version:         c.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s manifest: %w", c.Format, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// nixIndentedString escapes text for a Nix indented string literal, indents its lines,
+// and replaces homePlaceholder with the home directory of the home-manager user.
+func nixIndentedString(text string) string {
+	text = strings.ReplaceAll(text, "''", "'''")
+	text = strings.ReplaceAll(text, "${", "''${")
+	text = strings.ReplaceAll(text, homePlaceholder, "${config.home.homeDirectory}")
+
+	var b strings.Builder
+	for line := range strings.SplitSeq(strings.TrimSuffix(text, "\n"), "\n") {
+		if line != "" {
+			b.WriteString("    ")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
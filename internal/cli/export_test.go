@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestExportCmd_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       string
+		wantContains []string
+	}{
+		{
+			name:   "chezmoi script",
+			format: "chezmoi",
+			wantContains: []string{
+				"#!/bin/sh",
+				"install_targets = ['@@HOME@@/.claude/skills', '/opt/skills']",
+				"[targets.'@@HOME@@/.claude/skills']",
+				"url = 'https://github.com/example/skills.git'",
+				`sed "s|@@HOME@@|$HOME|g"`,
+				"go install github.com/mazrean/skills-pkg@latest",
+				"skills-pkg sync",
+			},
+		},
+		{
+			name:   "home-manager module",
+			format: "home-manager",
+			wantContains: []string{
+				"{ config, lib, ... }:",
+				"    install_targets = ['${config.home.homeDirectory}/.claude/skills', '/opt/skills']",
+				"    url = 'https://github.com/example/skills.git'",
+				"home.activation.skillsPkg",
+				"run ${lib.escapeShellArg skillsPkg} sync",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+
+			configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+			userTarget := filepath.Join(home, ".claude", "skills")
+			config := &domain.Config{
+				Skills:         []*domain.Skill{{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0"}},
+				InstallTargets: []string{userTarget, "/opt/skills"},
+				Targets:        map[string]*domain.TargetSettings{userTarget: {FileMode: "0644"}},
+			}
+			if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+			cmd := &ExportCmd{Format: tt.format, Version: "latest"}
+			if err := cmd.runWithLogger(configPath, logger); err != nil {
+				t.Fatalf("runWithLogger() error = %v\nstderr: %s", err, errOut.String())
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+			if strings.Contains(out.String(), home) {
+				t.Errorf("output contains the local home directory %s:\n%s", home, out.String())
+			}
+		})
+	}
+}
+
+func TestExportCmd_Run_OutputFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".skillspkg.toml")
+	if err := domain.NewConfigManager(configPath).Initialize(context.Background(), []string{filepath.Join(dir, "skills")}); err != nil {
+		t.Fatalf("failed to initialize config: %v", err)
+	}
+
+	output := filepath.Join(dir, "run_onchange_after_install-skills.sh")
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	cmd := &ExportCmd{Format: "chezmoi", Output: output, Version: "latest"}
+	if err := cmd.runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("runWithLogger() error = %v\nstderr: %s", err, errOut.String())
+	}
+
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("manifest was not written: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("nothing should be written to stdout with --output, got %q", out.String())
+	}
+}
+
+func TestNixIndentedString(t *testing.T) {
+	got := nixIndentedString("a = '${x}'\n\nb = \"''\" # @@HOME@@\n")
+	want := "    a = '''${x}'\n\n    b = \"'''\" # ${config.home.homeDirectory}\n"
+	if got != want {
+		t.Errorf("nixIndentedString() = %q, want %q", got, want)
+	}
+}
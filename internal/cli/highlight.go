@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// ANSI escape sequences used to highlight Markdown.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+	ansiLink    = "\x1b[34;4m"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^#{1,6}\s`)
+	listMarkerPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])(\s)`)
+	frontmatterKey     = regexp.MustCompile(`^(\s*-?\s*)([A-Za-z0-9_-]+)(:)`)
+	inlineCodePattern  = regexp.MustCompile("`[^`]+`")
+	boldPattern        = regexp.MustCompile(`\*\*[^*]+\*\*|__[^_]+__`)
+	linkPattern        = regexp.MustCompile(`\[[^\]]*\]\([^)]*\)`)
+	inlineSpanPatterns = []struct {
+		pattern *regexp.Regexp
+		style   string
+	}{
+		{pattern: inlineCodePattern, style: ansiCyan},
+		{pattern: linkPattern, style: ansiLink},
+		{pattern: boldPattern, style: ansiBold},
+	}
+)
+
+// highlightMarkdown returns src with ANSI escape sequences highlighting the YAML frontmatter,
+// headings, code blocks, list markers, block quotes, and inline code, links, and bold text.
+// Only the appearance changes: removing the escape sequences yields src again.
+func highlightMarkdown(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src) + len(src)/4)
+
+	lines := strings.SplitAfter(string(src), "\n")
+	frontmatterLines := domain.SkillFrontmatterLines(src)
+	inCode := false
+	fence := ""
+	for i, line := range lines {
+		text := strings.TrimRight(line, "\r\n")
+		eol := line[len(text):]
+		trimmed := strings.TrimSpace(text)
+
+		switch {
+		case i == 0 && frontmatterLines > 0, i == frontmatterLines-1:
+			out.WriteString(style(ansiDim, text))
+		case i < frontmatterLines:
+			if m := frontmatterKey.FindStringSubmatchIndex(text); m != nil && !strings.HasPrefix(trimmed, "#") {
+				out.WriteString(text[:m[4]])
+				out.WriteString(style(ansiYellow, text[m[4]:m[5]]))
+				out.WriteString(text[m[5]:])
+			} else {
+				out.WriteString(text)
+			}
+		case inCode:
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				inCode = false
+				out.WriteString(style(ansiDim, text))
+				break
+			}
+			out.WriteString(style(ansiGreen, text))
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inCode = true
+			fence = trimmed[:3]
+			out.WriteString(style(ansiDim, text))
+		case headingPattern.MatchString(trimmed):
+			out.WriteString(style(ansiBold+ansiMagenta, text))
+		case strings.HasPrefix(trimmed, ">"):
+			out.WriteString(style(ansiDim, text))
+		default:
+			if m := listMarkerPattern.FindStringSubmatchIndex(text); m != nil {
+				out.WriteString(text[:m[4]])
+				out.WriteString(style(ansiRed, text[m[4]:m[5]]))
+				text = text[m[5]:]
+			}
+			out.WriteString(highlightInline(text))
+		}
+		out.WriteString(eol)
+	}
+
+	return out.Bytes()
+}
+
+// highlightInline highlights inline code, links, and bold text of a single line.
+// Spans matched by an earlier pattern take precedence over overlapping later ones.
+func highlightInline(text string) string {
+	type span struct {
+		style      string
+		start, end int
+	}
+
+	var spans []span
+	for _, p := range inlineSpanPatterns {
+	matches:
+		for _, m := range p.pattern.FindAllStringIndex(text, -1) {
+			for _, s := range spans {
+				if m[0] < s.end && s.start < m[1] {
+					continue matches
+				}
+			}
+			spans = append(spans, span{style: p.style, start: m[0], end: m[1]})
+		}
+	}
+	if len(spans) == 0 {
+		return text
+	}
+
+	slices.SortFunc(spans, func(a, b span) int { return a.start - b.start })
+
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		b.WriteString(text[pos:s.start])
+		b.WriteString(style(s.style, text[s.start:s.end]))
+		pos = s.end
+	}
+	b.WriteString(text[pos:])
+
+	return b.String()
+}
+
+// style wraps text in the ANSI escape sequence, leaving empty text unchanged.
+func style(code, text string) string {
+	if text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestHighlightMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "frontmatter keys",
+			src:  "---\nname: my-skill\ndescription: Does things\n---\n",
+			want: ansiDim + "---" + ansiReset + "\n" +
+				ansiYellow + "name" + ansiReset + ": my-skill\n" +
+				ansiYellow + "description" + ansiReset + ": Does things\n" +
+				ansiDim + "---" + ansiReset + "\n",
+		},
+		{
+			name: "heading",
+			src:  "## Usage\n",
+			want: ansiBold + ansiMagenta + "## Usage" + ansiReset + "\n",
+		},
+		{
+			name: "code block is not highlighted as Markdown",
+			src:  "```sh\n# not a heading\n```\n",
+			want: ansiDim + "```sh" + ansiReset + "\n" +
+				ansiGreen + "# not a heading" + ansiReset + "\n" +
+				ansiDim + "```" + ansiReset + "\n",
+		},
+		{
+			name: "list marker and inline spans",
+			src:  "- Run `make` and see [docs](https://example.com) **first**",
+			want: ansiRed + "-" + ansiReset + " Run " + ansiCyan + "`make`" + ansiReset + " and see " +
+				ansiLink + "[docs](https://example.com)" + ansiReset + " " + ansiBold + "**first**" + ansiReset,
+		},
+		{
+			name: "bold inside inline code is left to the code span",
+			src:  "`**x**`\n",
+			want: ansiCyan + "`**x**`" + ansiReset + "\n",
+		},
+		{
+			name: "frontmatter closed by dots",
+			src:  "---\nname: my-skill\n...\n",
+			want: ansiDim + "---" + ansiReset + "\n" +
+				ansiYellow + "name" + ansiReset + ": my-skill\n" +
+				ansiDim + "..." + ansiReset + "\n",
+		},
+		{
+			name: "dashes after the first line are not frontmatter",
+			src:  "text\n---\nkey: value\n",
+			want: "text\n---\nkey: value\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(highlightMarkdown([]byte(tt.src))); got != tt.want {
+				t.Errorf("highlightMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightMarkdown_PreservesText(t *testing.T) {
+	src := "---\nname: x\n---\n# Title\r\n\n> quote\n1. step with `code`\n~~~\nraw\n~~~\n"
+	escapes := regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+	if got := escapes.ReplaceAllString(string(highlightMarkdown([]byte(src))), ""); got != src {
+		t.Errorf("highlightMarkdown() without escape sequences = %q, want %q", got, src)
+	}
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// historyLimit is the number of recent commands kept in the history file.
+const historyLimit = 50
+
+// operationLogLimit is the number of bytes of the messages of the last command that are kept.
+const operationLogLimit = 256 << 10
+
+// operationLog receives the messages of the running command, or is nil when the command is not recorded.
+var operationLog *operationBuffer
+
+// historyEntry is a command recorded in the history file.
+type historyEntry struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Version  string    `json:"version"`
+	Duration string    `json:"duration"`
+	Error    string    `json:"error,omitempty"`
+	Code     string    `json:"code,omitempty"`
+}
+
+// operationBuffer keeps the last operationLogLimit bytes written to it.
+type operationBuffer struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (b *operationBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if excess := len(b.data) - operationLogLimit; excess > 0 {
+		b.data = b.data[excess:]
+	}
+	return len(p), nil
+}
+
+func (b *operationBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.data)
+}
+
+// StartOperationLog keeps the messages that the command prints from now on, so that RecordOperation
+// can save them for 'debug bundle'.
+func StartOperationLog() {
+	operationLog = &operationBuffer{}
+}
+
+// withOperationLog returns a writer that writes to w and to the operation log, if one is started.
+func withOperationLog(w io.Writer) io.Writer {
+	if operationLog == nil {
+		return w
+	}
+	return io.MultiWriter(w, operationLog)
+}
+
+// RecordOperation adds the command to the history file and saves its messages as the log of the last
+// operation, for 'debug bundle' to collect. The debug commands themselves are not recorded, so that
+// the bundle shows the command being reported. Failures are ignored, as the history is only an aid.
+func RecordOperation(command, version string, start time.Time, err error) {
+	if operationLog == nil || strings.HasPrefix(command, "debug") {
+		return
+	}
+	dirs, dirsErr := domain.ResolveUserDirs()
+	if dirsErr != nil {
+		return
+	}
+	if mkdirErr := os.MkdirAll(dirs.State, 0o700); mkdirErr != nil {
+		return
+	}
+
+	entry := &historyEntry{
+		Time:     start.UTC().Truncate(time.Second),
+		Command:  command,
+		Version:  version,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		entry.Error = redactSecrets(err.Error())
+		if code := domain.CodeOf(err); code != nil {
+			entry.Code = code.Code
+		}
+	}
+	_ = appendHistory(dirs.HistoryFile(), entry)
+
+	var log bytes.Buffer
+	fmt.Fprintf(&log, "command: skills-pkg %s\nversion: %s\nstarted: %s\nduration: %s\n", entry.Command, entry.Version, entry.Time.Format(time.RFC3339), entry.Duration)
+	if entry.Error != "" {
+		fmt.Fprintf(&log, "error: %s\n", entry.Error)
+	}
+	log.WriteString("\n")
+	log.Write(operationLog.Bytes())
+	_ = os.WriteFile(dirs.LastOperationLog(), []byte(redactSecrets(log.String())), 0o600)
+}
+
+// appendHistory adds the entry to the history file at path, dropping the oldest entries beyond historyLimit.
+func appendHistory(path string, entry *historyEntry) error {
+	entries, err := readHistory(path)
+	if err != nil {
+		entries = nil
+	}
+	entries = append(entries, entry)
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".history-*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readHistory returns the entries of the history file at path, oldest first. A missing file has no entries,
+// and lines that cannot be parsed are skipped.
+func readHistory(path string) ([]*historyEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []*historyEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var entry historyEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, &entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+var (
+	// urlCredentials matches the user information of URLs, such as the token in https://token@example.com.
+	urlCredentials = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^\s/@'"]+@`)
+	// queryCredentials matches the values of query parameters named like credentials, such as ?access_token=.
+	queryCredentials = regexp.MustCompile(`(?i)([?&][^=&\s'"]*(?:token|secret|password|key|sig)[^=&\s'"]*=)[^&\s'"]+`)
+)
+
+// redactSecrets replaces the credentials in URLs in s, so that messages can be shared in bug reports.
+func redactSecrets(s string) string {
+	s = urlCredentials.ReplaceAllString(s, "${1}xxxxx@")
+	return queryCredentials.ReplaceAllString(s, "${1}xxxxx")
+}
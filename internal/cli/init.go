@@ -15,10 +15,11 @@ import (
 	"github.com/mazrean/skills-pkg/internal/port"
 )
 
-const (
-	// defaultConfigPath is the default path to the .skillspkg.toml configuration file
-	defaultConfigPath = ".skillspkg.toml"
+// defaultConfigPath is the path to the project configuration file, relative to the project directory.
+// EnterProject sets it to the name of the file selected with --config.
+var defaultConfigPath = domain.ConfigFileName
 
+const (
 	// managing-skills installation constants
 	managingSkillsName   = "managing-skills"
 	managingSkillsSource = "go-mod"
@@ -29,9 +30,18 @@ const (
 // InitCmd represents the init command
 // Requirements: 1.1, 1.2, 1.3, 1.4, 1.5, 12.1, 12.2, 12.3, 12.4
 type InitCmd struct {
-	Agent      []string `help:"Agent name to use default directory (can be specified multiple times)" short:"a" enum:"claude,claude-code,codex,cursor,copilot,github-copilot,goose,opencode,gemini,gemini-cli,amp,kimi-cli,replit,universal,factory,droid,antigravity,augment,openclaw,cline,codebuddy,command-code,continue,cortex,crush,junie,iflow-cli,kilo,kiro-cli,kode,mcpjam,mistral-vibe,mux,openhands,pi,qoder,qwen-code,roo,trae,trae-cn,windsurf,zencoder,neovate,pochi,adal"`
-	InstallDir []string `help:"Custom install directory (can be specified multiple times)" short:"d"`
-	Global     bool     `help:"Use user-level directory instead of project-level directory (requires --agent)" short:"g" default:"false"`
+	Agent           []string `help:"Agent name to use default directory (can be specified multiple times)" short:"a" enum:"claude,claude-code,codex,cursor,copilot,github-copilot,goose,opencode,gemini,gemini-cli,amp,kimi-cli,replit,universal,factory,droid,antigravity,augment,openclaw,cline,codebuddy,command-code,continue,cortex,crush,junie,iflow-cli,kilo,kiro-cli,kode,mcpjam,mistral-vibe,mux,openhands,pi,qoder,qwen-code,roo,trae,trae-cn,windsurf,zencoder,neovate,pochi,adal"`
+	InstallDir      []string `help:"Custom install directory (can be specified multiple times)" short:"d"`
+	Global          bool     `help:"Use user-level directory instead of project-level directory (requires --agent)" short:"g" default:"false"`
+	System          bool     `help:"Add the system-wide install directory shared by all users (usually requires sudo)" default:"false"`
+	CheckTargets    bool     `help:"Warn about install targets that do not look like the skills directory of an installed agent" name:"check-targets" default:"false"`
+	Bundle          []string `help:"Add a curated bundle of skills, such as web-dev or documents (can be specified multiple times)" short:"b"`
+	NoDefaultSkills bool     `help:"Do not install managing-skills, or the bootstrap skill of the user configuration" name:"no-default-skills" default:"false"`
+	Reconfigure     bool     `help:"Add the install targets to an existing configuration instead of failing, skipping those it already has" default:"false"`
+
+	allowRoot      bool   // Set from the global --allow-root flag
+	downloadCache  bool   // Set by Run to reuse downloads from the user cache directory
+	userConfigPath string // Set by Run to the user-level configuration file that may name a bootstrap skill
 }
 
 // Run executes the init command
@@ -43,11 +53,20 @@ func (c *InitCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+	if dirs, err := resolveUserDirs(NewLogger(verbose)); err == nil {
+		c.userConfigPath = dirs.ConfigFile()
+	}
+	for i, dir := range c.InstallDir {
+		c.InstallDir[i] = projectPath(dir)
+	}
+
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -55,10 +74,7 @@ func (c *InitCmd) Run(ctx *kong.Context) error {
 func (c *InitCmd) run(configPath string, verbose bool) error {
 	// Create default dependencies
 	hashService := service.NewDirhash()
-	packageManagers := []port.PackageManager{
-		pkgmanager.NewGit(),
-		pkgmanager.NewGoMod(),
-	}
+	packageManagers := pkgmanager.All()
 
 	return c.runWithDeps(configPath, verbose, hashService, packageManagers)
 }
@@ -81,8 +97,40 @@ func (c *InitCmd) runWithDeps(configPath string, verbose bool, hashService port.
 
 	logger.Verbose("Install targets: %v", installTargets)
 
+	if c.CheckTargets {
+		warnTargets(logger, installTargets, agent.All())
+	}
+
+	// Update the install targets of an existing configuration; without one, initialize as usual
+	if c.Reconfigure {
+		if _, err := os.Stat(configPath); err == nil {
+			return c.reconfigure(logger, configPath, installTargets)
+		}
+	}
+
+	// Resolve the skills before writing anything so that an unknown bundle or an invalid
+	// bootstrap skill leaves no configuration behind
+	var skills []*domain.Skill
+	if c.NoDefaultSkills {
+		logger.Verbose("Skipping the default skill")
+	} else {
+		skill, err := defaultSkill(c.userConfigPath)
+		if err != nil {
+			logger.Error("Failed to read the bootstrap skill: %v", err)
+			logger.Error("Fix bootstrap in the user configuration, or use --no-default-skills")
+			return err
+		}
+		skills = append(skills, skill)
+	}
+	bundled, err := bundleSkills(c.Bundle)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	skills = append(skills, bundled...)
+
 	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
+	configManager := newConfigManager(configPath)
 
 	// Initialize configuration file (requirement 1.1, 1.5)
 	if err = configManager.Initialize(context.Background(), installTargets); err != nil {
@@ -90,7 +138,7 @@ func (c *InitCmd) runWithDeps(configPath string, verbose bool, hashService port.
 		if e, ok := errors.AsType[*domain.ErrorConfigExists](err); ok {
 			// Configuration file already exists (requirement 1.4)
 			logger.Error("Configuration file already exists at %s", e.Path)
-			logger.Error("Remove the existing file or use a different path")
+			logger.Error("Remove the existing file, use a different path, or use --reconfigure to add install targets to it")
 			return err
 		}
 
@@ -100,39 +148,41 @@ func (c *InitCmd) runWithDeps(configPath string, verbose bool, hashService port.
 		return err
 	}
 
-	// Add managing-skills to configuration and install it.
+	// Add the default skill and the bundled skills to configuration and install them.
 	// On any failure below, roll back by removing the config file we just created
 	// so that re-running `init` is possible without hitting ErrorConfigExists.
-	logger.Info("Installing managing-skills...")
-	managingSkill := &domain.Skill{
-		Name:   managingSkillsName,
-		Source: managingSkillsSource,
-		URL:    managingSkillsURL,
-		SubDir: managingSkillsSubDir,
-	}
-
-	config, err := configManager.AddSkillToConfig(context.Background(), managingSkill)
+	config, err := configManager.Load(context.Background())
 	if err != nil {
 		rollback(logger, configPath)
-		logger.Error("Failed to add managing-skills to configuration: %v", err)
+		logger.Error("Failed to load configuration: %v", err)
 		return err
 	}
-
-	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers)
-	// Use saveConfig=false so the config is only persisted after a successful install.
-	if err := skillManager.InstallSingleSkill(context.Background(), config, managingSkill, false); err != nil {
+	config.Skills = append(config.Skills, skills...)
+	if err := config.Validate(); err != nil {
 		rollback(logger, configPath)
-		logger.Error("Failed to install managing-skills: %v", err)
-		return fmt.Errorf("managing-skills installation failed: %w", err)
+		logger.Error("Failed to add skills to configuration: %v", err)
+		return err
+	}
+
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, skillManagerOptions(c.allowRoot, c.downloadCache)...)
+	for _, skill := range skills {
+		logger.Info("Installing %s...", skill.Name)
+		// Use saveConfig=false so the config is only persisted after a successful install.
+		if err := skillManager.InstallSingleSkill(context.Background(), config, skill, false); err != nil {
+			rollback(logger, configPath)
+			logger.Error("Failed to install %s: %v", skill.Name, err)
+			handlePermissionError(logger, err)
+			return fmt.Errorf("%s installation failed: %w", skill.Name, err)
+		}
 	}
 
-	// Persist config with managing-skills only after successful installation.
+	// Persist config with the skills only after successful installation.
 	// Save uses os.WriteFile which truncates before writing; a mid-write failure can corrupt
 	// the file. Rolling back the config ensures the user can re-run init cleanly.
-	// Note: installed managing-skills files in the target directories are left in place.
+	// Note: installed skill files in the target directories are left in place.
 	// They will be overwritten on the next successful init run (copySkillToTargets removes
 	// the existing skill directory before copying). Users who do not re-run init will have
-	// orphaned managing-skills files without a corresponding config entry.
+	// orphaned skill files without a corresponding config entry.
 	if err := configManager.Save(context.Background(), config); err != nil {
 		rollback(logger, configPath)
 		logger.Error("Failed to save configuration: %v", err)
@@ -153,6 +203,66 @@ func (c *InitCmd) runWithDeps(configPath string, verbose bool, hashService port.
 	return nil
 }
 
+// reconfigure merges the install targets into the existing configuration at configPath.
+// Skills are not installed, so that the command stays quick and works offline.
+func (c *InitCmd) reconfigure(logger *Logger, configPath string, installTargets []string) error {
+	if len(c.Bundle) > 0 {
+		err := errors.New("--bundle cannot be used to reconfigure an existing configuration")
+		logger.Error("%v", err)
+		logger.Error("Add the skills with 'skills-pkg add' instead")
+		return err
+	}
+	// The default ./.skills target is only meant for new configurations
+	if len(c.InstallDir) == 0 && len(c.Agent) == 0 && !c.System {
+		err := errors.New("no install targets specified")
+		logger.Error("%v", err)
+		logger.Error("Use --agent, --install-dir, or --system to choose the install targets to add")
+		return err
+	}
+
+	logger.Info("Updating install targets in %s", configPath)
+	configManager := newConfigManager(configPath)
+	added, err := configManager.MergeInstallTargets(context.Background(), installTargets)
+	if err != nil {
+		logger.Error("Failed to update configuration: %v", err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+
+	if len(added) == 0 {
+		logger.Info("All install targets are already configured")
+		return nil
+	}
+	logger.Info("Added install targets:")
+	for _, target := range added {
+		logger.Info("  - %s", target)
+	}
+	logger.Info("Run 'skills-pkg install' to install the configured skills into them")
+
+	return nil
+}
+
+// defaultSkill returns the skill installed into every new configuration: the bootstrap skill of
+// the user-level configuration at userConfigPath when it names one, and managing-skills otherwise.
+func defaultSkill(userConfigPath string) (*domain.Skill, error) {
+	if userConfigPath != "" {
+		userConfig, err := domain.LoadUserConfig(userConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		if userConfig.Bootstrap != nil {
+			return userConfig.Bootstrap, nil
+		}
+	}
+
+	return &domain.Skill{
+		Name:   managingSkillsName,
+		Source: managingSkillsSource,
+		URL:    managingSkillsURL,
+		SubDir: managingSkillsSubDir,
+	}, nil
+}
+
 // rollback removes the config file created during init so the user can re-run init cleanly.
 // If removal itself fails, a warning is logged so the user can delete it manually.
 func rollback(logger *Logger, configPath string) {
@@ -204,6 +314,12 @@ func (c *InitCmd) buildInstallTargets(logger *Logger) ([]string, error) {
 		}
 	}
 
+	// Add the system-wide directory for shared workstations and CI images
+	if c.System {
+		logger.Verbose("Adding system-wide install directory: %s", domain.SystemInstallDir)
+		installTargets = append(installTargets, domain.SystemInstallDir)
+	}
+
 	// If no install targets specified, use default project-level directory
 	if len(installTargets) == 0 {
 		defaultDir := "./.skills"
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
@@ -440,3 +441,231 @@ func TestInitCmd_ManagingSkillsFromGoMod(t *testing.T) {
 		t.Errorf("expected empty hash value (go.mod is source of truth), got %q", skill.HashValue)
 	}
 }
+
+func TestInitCmd_Bundle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		bundles    []string
+		wantSkills []string
+		wantErr    bool
+	}{
+		{
+			name:       "single bundle",
+			bundles:    []string{"web-dev"},
+			wantSkills: []string{managingSkillsName, "frontend-design", "webapp-testing"},
+		},
+		{
+			name:       "repeated bundle adds its skills once",
+			bundles:    []string{"web-dev", "web-dev"},
+			wantSkills: []string{managingSkillsName, "frontend-design", "webapp-testing"},
+		},
+		{
+			name:    "unknown bundle",
+			bundles: []string{"no-such-bundle"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+
+			mockDownloadDir := t.TempDir()
+			for _, subDir := range []string{managingSkillsSubDir, "skills/frontend-design", "skills/webapp-testing"} {
+				if err := os.MkdirAll(filepath.Join(mockDownloadDir, subDir), 0o755); err != nil {
+					t.Fatalf("failed to create mock skill directory: %v", err)
+				}
+			}
+			packageManagers := []port.PackageManager{
+				&mockPackageManager{sourceType: "git", tmpDir: mockDownloadDir},
+				&mockPackageManager{sourceType: "go-mod", tmpDir: mockDownloadDir},
+			}
+
+			cmd := &InitCmd{InstallDir: []string{filepath.Join(tmpDir, "install")}, Bundle: tt.bundles}
+			err := cmd.runWithDeps(configPath, false, &mockHashService{}, packageManagers)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if _, statErr := os.Stat(configPath); !os.IsNotExist(statErr) {
+					t.Errorf("config file should not be created for an unknown bundle, but it exists at %s", configPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			config, err := domain.NewConfigManager(configPath).Load(context.Background())
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			var names []string
+			for _, skill := range config.Skills {
+				names = append(names, skill.Name)
+			}
+			if !slices.Equal(names, tt.wantSkills) {
+				t.Errorf("skills = %v, want %v", names, tt.wantSkills)
+			}
+		})
+	}
+}
+
+func TestInitCmd_DefaultSkills(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		userConfig      string
+		noDefaultSkills bool
+		wantSkills      []string
+		wantErr         bool
+	}{
+		{
+			name:       "managing-skills by default",
+			wantSkills: []string{managingSkillsName},
+		},
+		{
+			name:       "bootstrap skill from the user configuration",
+			userConfig: "[bootstrap]\nname = \"team-skills\"\nsource = \"git\"\nurl = \"https://git.example.com/skills\"\nsubdir = \"skills/team-skills\"\n",
+			wantSkills: []string{"team-skills"},
+		},
+		{
+			name:            "no default skills",
+			userConfig:      "[bootstrap]\nname = \"team-skills\"\nsource = \"git\"\nurl = \"https://git.example.com/skills\"\n",
+			noDefaultSkills: true,
+			wantSkills:      nil,
+		},
+		{
+			name:       "invalid bootstrap skill",
+			userConfig: "[bootstrap]\nname = \"team-skills\"\n",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			userConfigPath := filepath.Join(tmpDir, "config.toml")
+			if tt.userConfig != "" {
+				if err := os.WriteFile(userConfigPath, []byte(tt.userConfig), 0o644); err != nil {
+					t.Fatalf("failed to write user config: %v", err)
+				}
+			}
+
+			mockDownloadDir := t.TempDir()
+			for _, subDir := range []string{managingSkillsSubDir, "skills/team-skills"} {
+				if err := os.MkdirAll(filepath.Join(mockDownloadDir, subDir), 0o755); err != nil {
+					t.Fatalf("failed to create mock skill directory: %v", err)
+				}
+			}
+			packageManagers := []port.PackageManager{
+				&mockPackageManager{sourceType: "git", tmpDir: mockDownloadDir},
+				&mockPackageManager{sourceType: "go-mod", tmpDir: mockDownloadDir},
+			}
+
+			cmd := &InitCmd{
+				InstallDir:      []string{filepath.Join(tmpDir, "install")},
+				NoDefaultSkills: tt.noDefaultSkills,
+				userConfigPath:  userConfigPath,
+			}
+			err := cmd.runWithDeps(configPath, false, &mockHashService{}, packageManagers)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if _, statErr := os.Stat(configPath); !os.IsNotExist(statErr) {
+					t.Errorf("config file should not be created for an invalid bootstrap skill, but it exists at %s", configPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			config, err := domain.NewConfigManager(configPath).Load(context.Background())
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			var names []string
+			for _, skill := range config.Skills {
+				names = append(names, skill.Name)
+			}
+			if !slices.Equal(names, tt.wantSkills) {
+				t.Errorf("skills = %v, want %v", names, tt.wantSkills)
+			}
+		})
+	}
+}
+
+func TestInitCmd_Reconfigure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		cmd         *InitCmd
+		wantTargets []string
+		wantErr     bool
+	}{
+		{
+			name:        "adds new targets and skips existing ones",
+			cmd:         &InitCmd{InstallDir: []string{"existing/", "./new"}, Reconfigure: true},
+			wantTargets: []string{"./existing", "./new"},
+		},
+		{
+			name:    "requires install targets",
+			cmd:     &InitCmd{Reconfigure: true},
+			wantErr: true,
+		},
+		{
+			name:    "rejects bundles",
+			cmd:     &InitCmd{InstallDir: []string{"./new"}, Bundle: []string{"web-dev"}, Reconfigure: true},
+			wantErr: true,
+		},
+		{
+			name:    "fails without reconfigure",
+			cmd:     &InitCmd{InstallDir: []string{"./new"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+			configManager := domain.NewConfigManager(configPath)
+			if err := configManager.Initialize(context.Background(), []string{"./existing"}); err != nil {
+				t.Fatalf("failed to create config: %v", err)
+			}
+
+			// Reconfiguring installs nothing, so no package managers are needed
+			err := tt.cmd.runWithDeps(configPath, false, &mockHashService{}, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			targets, err := configManager.GetInstallTargets(context.Background())
+			if err != nil {
+				t.Fatalf("failed to get install targets: %v", err)
+			}
+			if !slices.Equal(targets, tt.wantTargets) {
+				t.Errorf("install targets = %v, want %v", targets, tt.wantTargets)
+			}
+		})
+	}
+}
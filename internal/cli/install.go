@@ -1,13 +1,17 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/agent"
 	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
@@ -16,7 +20,19 @@ import (
 
 // InstallCmd represents the install command
 type InstallCmd struct {
-	Skills []string `arg:"" optional:"" help:"Skill names to install (if not specified, installs all skills from configuration)"`
+	Skills       []string `arg:"" optional:"" help:"Skill names to install (if not specified, installs all skills from configuration)"`
+	CheckTargets bool     `help:"Warn about install targets that do not look like the skills directory of an installed agent" name:"check-targets" default:"false"`
+	OnlyNew      bool     `help:"Install only skills that have not been installed on this machine yet, leaving installed skills untouched" name:"only-new" default:"false"`
+	DryRun       bool     `help:"Show what would be downloaded, copied, and overwritten in each install target without making changes" name:"dry-run"`
+	Frozen       bool     `help:"Install only the versions resolved in .skillspkg.lock, failing when a skill's source, version, or content does not match it"`
+	Progress     string   `help:"Progress output format: text, or json to stream one JSON event per line to standard output" enum:"text,json" default:"text"`
+	Concurrency  int      `help:"Maximum number of skills downloaded and installed at the same time (default 8)" env:"SKILLSPKG_CONCURRENCY" placeholder:"N"`
+	WithDeps     bool     `help:"Add the skills that installed skills declare as dependencies in their SKILL.md to the configuration without asking" name:"with-dependencies"`
+
+	Output        string    `kong:"-"` // Set by Run from the global --output flag: text or json
+	allowRoot     bool      // Set from the global --allow-root flag
+	downloadCache bool      // Set by Run to reuse downloads from the user cache directory
+	stdin         io.Reader // Set by Run when the user can answer confirmation prompts
 }
 
 // Run executes the install command
@@ -26,11 +42,16 @@ func (c *InstallCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.Output = outputFlag(ctx)
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+	c.stdin = confirmInput()
+
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -38,46 +59,97 @@ func (c *InstallCmd) Run(ctx *kong.Context) error {
 // This method installs skills from the configuration file.
 // Requirements: 6.1, 6.2, 6.3, 12.1, 12.2, 12.3, 12.4
 func (c *InstallCmd) run(configPath string, verbose bool) error {
+	// Create HashService
+	hashService := service.NewDirhash()
+
+	// Create PackageManagers
+	packageManagers := pkgmanager.All()
+
+	return c.runWithDeps(configPath, verbose, hashService, packageManagers)
+}
+
+// runWithDeps is the internal implementation with dependency injection for testing
+func (c *InstallCmd) runWithDeps(configPath string, verbose bool, hashService port.HashService, packageManagers []port.PackageManager) error {
 	// Create logger with verbose setting (requirement 12.4)
 	logger := NewLogger(verbose)
+	notifier := newOperationNotifier(logger)
+
+	// Create ConfigManager
+	configManager := newConfigManager(configPath)
+
+	skillNames := c.Skills
+	if c.OnlyNew {
+		newSkills, err := c.newSkills(configManager, configPath)
+		if err != nil {
+			c.handleInstallError(logger, "", configPath, err)
+			notifier.completed("skills-pkg install failed", err.Error())
+			return err
+		}
+		if len(newSkills) == 0 {
+			logger.Info("No new skills to install")
+			return nil
+		}
+		skillNames = newSkills
+	}
 
 	// Display progress information (requirement 12.1)
-	if len(c.Skills) == 0 {
+	switch {
+	case len(skillNames) == 0:
 		logger.Info("Installing all skills from configuration")
-	} else {
-		logger.Info("Installing skills: %v", c.Skills)
+	case c.OnlyNew:
+		logger.Info("Installing new skills: %v", skillNames)
+	default:
+		logger.Info("Installing skills: %v", skillNames)
 	}
 
-	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
-
-	// Create HashService
-	hashService := service.NewDirhash()
+	// Catch typos in install targets before downloading anything.
+	// Configuration errors are reported by the installation itself.
+	if c.CheckTargets {
+		if config, err := configManager.Load(context.Background()); err == nil {
+			warnTargets(logger, config.InstallTargets, agent.All())
+		}
+	}
 
-	// Create PackageManagers
-	packageManagers := []port.PackageManager{
-		pkgmanager.NewGit(),
-		pkgmanager.NewGoMod(),
+	// A signed lock file limits the installation to the signed versions
+	signed, err := verifyLockSignature(context.Background(), configManager, logger)
+	if err != nil {
+		notifier.completed("skills-pkg install failed", err.Error())
+		return err
 	}
 
 	// Create SkillManager
-	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers)
+	progress, flushProgress := progressOptions(logger, c.Progress)
+	defer flushProgress()
+	opts := append(skillManagerOptions(c.allowRoot, c.downloadCache), progress...)
+	opts = append(opts, domain.WithConcurrency(c.Concurrency), dependencyApproval(logger, c.stdin, c.WithDeps))
+	if c.Frozen || signed {
+		opts = append(opts, domain.WithFrozenLock())
+	}
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, opts...)
+
+	if c.DryRun {
+		return c.dryRun(logger, skillManager, skillNames, configPath)
+	}
 
 	// Determine what to install (requirements 6.1, 6.2)
-	if len(c.Skills) == 0 {
+	if len(skillNames) == 0 {
 		// Install all skills (requirement 6.1)
 		logger.Verbose("Installing all skills")
 		if err := skillManager.Install(context.Background(), ""); err != nil {
 			c.handleInstallError(logger, "", configPath, err)
+			notifier.completed("skills-pkg install failed", err.Error())
+			c.printJSON(logger, configManager, configPath, nil, err)
 			return err
 		}
 		logger.Info("Successfully installed all skills")
 	} else {
 		// Install specific skills (requirement 6.2)
-		for _, skillName := range c.Skills {
+		for i, skillName := range skillNames {
 			logger.Verbose("Installing skill: %s", skillName)
 			if err := skillManager.Install(context.Background(), skillName); err != nil {
 				c.handleInstallError(logger, skillName, configPath, err)
+				notifier.completed("skills-pkg install failed", err.Error())
+				c.printJSON(logger, configManager, configPath, skillNames[:i], err)
 				return err
 			}
 			logger.Info("Successfully installed skill '%s'", skillName)
@@ -86,10 +158,220 @@ func (c *InstallCmd) run(configPath string, verbose bool) error {
 
 	// Success message (requirement 12.1)
 	logger.Info("Installation complete")
+	notifier.completed("skills-pkg install", "Installation complete")
+
+	c.printJSON(logger, configManager, configPath, skillNames, nil)
 
 	return nil
 }
 
+// installOutput is the JSON output of the install command.
+type installOutput struct {
+	Skills []*installOutputSkill `json:"skills"`
+	Error  string                `json:"error,omitempty"` // Why the installation failed
+}
+
+// installOutputSkill is an installed skill in the JSON output of the install command.
+type installOutputSkill struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version"`
+	Hash    string `json:"hash,omitempty"`
+	Status  string `json:"status"` // Always "installed"
+}
+
+// printJSON writes the skills of the configuration entries named entryNames, as recorded in the
+// configuration and the lock file after they were installed, with --output json. installErr is
+// the error that stopped the installation, if any. Without entryNames, the skills of every entry
+// are written unless the installation failed.
+func (c *InstallCmd) printJSON(logger *Logger, configManager *domain.ConfigManager, configPath string, entryNames []string, installErr error) {
+	if c.Output != "json" {
+		return
+	}
+
+	output := &installOutput{Skills: []*installOutputSkill{}}
+	if installErr != nil {
+		output.Error = installErr.Error()
+	}
+
+	ctx := context.Background()
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		config = &domain.Config{}
+	}
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		logger.Error("Warning: failed to read lock file, resolved versions are unavailable: %v", err)
+		lock = &domain.LockFile{}
+	}
+	entries := config.Skills
+	if len(entryNames) > 0 || installErr != nil {
+		entries = nil
+		for _, name := range entryNames {
+			if entry := config.FindSkillEntry(name); entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	for _, entry := range entries {
+		for _, skill := range entry.InstalledSkills() {
+			item := &installOutputSkill{Name: skill.Name, Source: skill.Source, Version: skill.Version, Hash: skill.HashValue, Status: "installed"}
+			if locked := lock.FindSkill(skill.Name); locked != nil {
+				item.Version = cmp.Or(locked.Version, item.Version)
+				item.Hash = cmp.Or(item.Hash, locked.HashValue)
+			}
+			output.Skills = append(output.Skills, item)
+		}
+	}
+
+	if err := writeJSON(logger, output); err != nil {
+		logger.Error("%v", err)
+	}
+}
+
+// dryRun prints the changes installing the skills would make, or installing all skills when none are named.
+func (c *InstallCmd) dryRun(logger *Logger, skillManager domain.SkillManager, skillNames []string, configPath string) error {
+	if len(skillNames) == 0 {
+		skillNames = []string{""}
+	}
+
+	var changes []*domain.DryRunChange
+	for _, skillName := range skillNames {
+		skillChanges, err := skillManager.InstallDryRun(context.Background(), skillName)
+		if err != nil {
+			c.handleInstallError(logger, skillName, configPath, err)
+			return err
+		}
+		changes = append(changes, skillChanges...)
+	}
+
+	if c.Output == "json" {
+		return writeJSON(logger, newDryRunChangesOutput(changes))
+	}
+	printDryRunChanges(logger, changes)
+	return nil
+}
+
+// dryRunChangesOutput is the JSON output of a dry run of install.
+type dryRunChangesOutput struct {
+	Changes []*dryRunChangeItem `json:"changes"`
+}
+
+// dryRunChangeItem is a single change in the JSON output of a dry run of install.
+type dryRunChangeItem struct {
+	Action       string `json:"action"`
+	Skill        string `json:"skill"`
+	Target       string `json:"target,omitempty"`
+	Version      string `json:"version,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	Size         int64  `json:"size"` // Negative when unknown
+	ReplacedSize int64  `json:"replaced_size,omitempty"`
+}
+
+// newDryRunChangesOutput converts dry-run changes into their JSON-serializable form.
+func newDryRunChangesOutput(changes []*domain.DryRunChange) *dryRunChangesOutput {
+	output := &dryRunChangesOutput{Changes: make([]*dryRunChangeItem, 0, len(changes))}
+	for _, change := range changes {
+		output.Changes = append(output.Changes, &dryRunChangeItem{
+			Action:       string(change.Action),
+			Skill:        change.Skill,
+			Target:       change.Target,
+			Version:      change.Version,
+			Reason:       change.Reason,
+			Size:         change.Size,
+			ReplacedSize: change.ReplacedSize,
+		})
+	}
+	return output
+}
+
+// printDryRunChanges prints the changes of a dry run of install or uninstall. Downloads are listed
+// with the skill, and copies into a target are marked with "+", overwrites with "~", removals with "-",
+// untouched targets with "=", and skipped targets with "!".
+func printDryRunChanges(logger *Logger, changes []*domain.DryRunChange) {
+	var additions, downloads, copies, overwrites, removals int
+	var downloadSize int64
+	for _, change := range changes {
+		switch change.Action {
+		case domain.DryRunDownload:
+			logger.Info("Download %s %s (%s)", change.Skill, planVersion(change.Version), dryRunSize(change.Size))
+			downloads++
+			downloadSize += change.Size
+		case domain.DryRunCopy:
+			logger.Info("  + %s in %s (%s)", change.Skill, change.Target, dryRunSize(change.Size))
+			copies++
+		case domain.DryRunOverwrite:
+			logger.Info("  ~ %s in %s (%s, replacing %s)", change.Skill, change.Target, dryRunSize(change.Size), dryRunSize(change.ReplacedSize))
+			overwrites++
+		case domain.DryRunRemove:
+			logger.Info("  - %s in %s (%s)", change.Skill, change.Target, dryRunSize(change.Size))
+			removals++
+		case domain.DryRunUpToDate:
+			logger.Info("  = %s in %s (%s, up to date)", change.Skill, change.Target, planVersion(change.Version))
+		case domain.DryRunSkip:
+			logger.Info("  ! %s in %s (skipped: the skill %s)", change.Skill, change.Target, change.Reason)
+		case domain.DryRunAdd:
+			logger.Info("Add %s %s to the configuration (a dependency of %s)", change.Skill, planVersion(change.Version), change.Reason)
+			additions++
+		}
+	}
+
+	if additions > 0 {
+		logger.Info("Dry run: %d skill(s) added to the configuration, %d download(s) (%s), %d copy(ies), %d overwrite(s), %d removal(s). No changes were made",
+			additions, downloads, formatBytes(downloadSize), copies, overwrites, removals)
+		return
+	}
+	logger.Info("Dry run: %d download(s) (%s), %d copy(ies), %d overwrite(s), %d removal(s). No changes were made",
+		downloads, formatBytes(downloadSize), copies, overwrites, removals)
+}
+
+// dryRunSize formats the size of a dry-run change, which is negative when it is unknown.
+func dryRunSize(size int64) string {
+	if size < 0 {
+		return "size unknown"
+	}
+	return formatBytes(size)
+}
+
+// newSkills returns the names of the requested skills, or of all configured skills when none
+// are requested, that have not been installed: the lock file records no installation of them, or
+// records another source, URL, or version than the configured one, as after 'skills-pkg add --no-install'.
+// Skills resolved from go.mod have no configured version, so any version recorded for them is installed.
+// Entries with sub_dirs are new until all their skills are installed.
+// Requested names missing from the configuration are kept so that the installation reports them.
+func (c *InstallCmd) newSkills(configManager *domain.ConfigManager, configPath string) ([]string, error) {
+	ctx := context.Background()
+
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := c.Skills
+	if len(candidates) == 0 {
+		candidates = make([]string, 0, len(config.Skills))
+		for _, skill := range config.Skills {
+			candidates = append(candidates, skill.Name)
+		}
+	}
+
+	var names []string
+	for _, name := range candidates {
+		entry := config.FindSkillEntry(name)
+		if entry == nil || slices.ContainsFunc(entry.InstalledSkills(), func(skill *domain.Skill) bool {
+			locked := lock.FindSkill(skill.Name)
+			return locked == nil || locked.ResolutionMismatch(skill) != ""
+		}) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
 // handleInstallError handles different types of errors that can occur during skill installation.
 // It provides appropriate error messages with causes and recommended actions.
 // Requirements: 6.3, 12.2, 12.3
@@ -113,6 +395,22 @@ func (c *InstallCmd) handleInstallError(logger *Logger, skillName string, config
 		return
 	}
 
+	// Skill that does not match the lock file in frozen mode
+	if err, ok := errors.AsType[*domain.ErrorLockMismatch](err); ok {
+		logger.Error("%v", err)
+		return
+	}
+
+	// Subdirectory removed or renamed in the source
+	if handleSubDirNotFound(logger, configPath, err) {
+		return
+	}
+
+	// Install target owned by another user (e.g., a system-wide directory)
+	if handlePermissionError(logger, err) {
+		return
+	}
+
 	// Network, file system, or other errors - distinguish and report (requirements 12.2, 12.3)
 	if skillName == "" {
 		logger.Error("Failed to install skills: %v", err)
@@ -121,3 +419,20 @@ func (c *InstallCmd) handleInstallError(logger *Logger, skillName string, config
 	}
 	logger.Error("Check network connection, file permissions, and try again")
 }
+
+// handleSubDirNotFound reports a subdirectory that no longer exists in the source of a skill,
+// suggesting the closest existing directories. It returns false when err is another error.
+func handleSubDirNotFound(logger *Logger, configPath string, err error) bool {
+	e, ok := errors.AsType[*domain.ErrorSubDirNotFound](err)
+	if !ok {
+		return false
+	}
+
+	logger.Error("%v", e)
+	if len(e.Suggestions) > 0 {
+		logger.Error("If the skill was moved upstream, set its subdirectory to '%s' in %s", e.Suggestions[0], configPath)
+	} else {
+		logger.Error("Check the source of skill '%s', or remove it with 'skills-pkg uninstall %s'", e.SkillName, e.SkillName)
+	}
+	return true
+}
@@ -3,10 +3,13 @@ package cli
 import (
 	"context"
 	"errors"
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 func TestInstallCmd_Run(t *testing.T) {
@@ -104,3 +107,81 @@ func TestInstallCmd_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallCmd_Run_OnlyNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		skills        []string
+		wantInstalled []string
+	}{
+		{
+			name:          "installs skills whose version or lock file entry is missing",
+			wantInstalled: []string{"recorded-skill", "unlocked-skill"},
+		},
+		{
+			name:          "restricts to the requested skills",
+			skills:        []string{"installed-skill", "unlocked-skill"},
+			wantInstalled: []string{"unlocked-skill"},
+		},
+		{
+			name:   "nothing new to install",
+			skills: []string{"installed-skill"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath, cleanup := setupTestConfig(t)
+			defer cleanup()
+			installDir := filepath.Join(filepath.Dir(configPath), "install")
+
+			ctx := context.Background()
+			cm := domain.NewConfigManager(configPath)
+			downloadDir := t.TempDir()
+			for _, skill := range []*domain.Skill{
+				{Name: "installed-skill", Source: "git", URL: "https://github.com/example/installed.git", Version: "v1.0.0", HashValue: "h1:installed"},
+				{Name: "recorded-skill", Source: "git", URL: "https://github.com/example/recorded.git", Version: "v1.0.0"},
+				{Name: "unlocked-skill", Source: "git", URL: "https://github.com/example/unlocked.git", Version: "v1.0.0", HashValue: "mock-hash-value"},
+				// Resolved from go.mod, so neither the configuration nor the lock file has a hash
+				{Name: "gomod-skill", Source: "go-mod", URL: "github.com/example/gomod"},
+			} {
+				skill.SubDir = "skills/" + skill.Name
+				if err := os.MkdirAll(filepath.Join(downloadDir, skill.SubDir), 0o755); err != nil {
+					t.Fatalf("failed to create subdirectory: %v", err)
+				}
+				if err := cm.AddSkill(ctx, skill); err != nil {
+					t.Fatalf("failed to add skill: %v", err)
+				}
+			}
+			err := domain.NewLockManager(domain.LockPathFor(configPath)).Update(ctx, func(lock *domain.LockFile) {
+				lock.RecordInstall("installed-skill", &domain.TargetStatus{Path: installDir, Version: "v1.0.0", HashValue: "h1:installed"})
+				lock.RecordInstall("recorded-skill", &domain.TargetStatus{Path: installDir, Version: "v0.9.0", HashValue: "h1:previous"})
+				lock.RecordResolved(&domain.Skill{Name: "gomod-skill", Source: "go-mod", URL: "github.com/example/gomod"}, "v1.2.0")
+				lock.RecordInstall("gomod-skill", &domain.TargetStatus{Path: installDir, Version: "v1.2.0"})
+			})
+			if err != nil {
+				t.Fatalf("failed to write lock file: %v", err)
+			}
+
+			cmd := &InstallCmd{Skills: tt.skills, OnlyNew: true}
+			err = cmd.runWithDeps(configPath, false, &mockHashService{}, []port.PackageManager{
+				&mockPackageManager{sourceType: "git", tmpDir: downloadDir},
+			})
+			if err != nil {
+				t.Fatalf("runWithDeps() error = %v", err)
+			}
+
+			for _, name := range []string{"installed-skill", "recorded-skill", "unlocked-skill", "gomod-skill"} {
+				_, statErr := os.Stat(filepath.Join(installDir, name))
+				want := slices.Contains(tt.wantInstalled, name)
+				if got := statErr == nil; got != want {
+					t.Errorf("skill %s installed = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// KeygenCmd represents the keygen command
+type KeygenCmd struct {
+	Output string `name:"out" help:"File to add the secret key to, or - for standard output (default: the user key file)" short:"o"`
+}
+
+// Run executes the keygen command
+func (c *KeygenCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	logger := NewLogger(verbose)
+
+	if c.Output != "-" {
+		c.Output = projectPath(c.Output)
+	}
+	if c.Output == "" {
+		dirs, err := resolveUserDirs(logger)
+		if err != nil {
+			logger.Error("%v", err)
+			return err
+		}
+		c.Output = dirs.SecretKeyFile()
+	}
+
+	return c.runWithLogger(logger)
+}
+
+// runWithLogger generates a secret key and prints its recipient (for testing)
+func (c *KeygenCmd) runWithLogger(logger *Logger) error {
+	key, err := domain.GenerateSecretKey()
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	if c.Output == "-" {
+		if _, err := fmt.Fprintln(logger.dataOut, key.String()); err != nil {
+			return err
+		}
+		logger.Info("Recipient: %s", key.Recipient())
+		return nil
+	}
+
+	if err := appendSecretKey(c.Output, key); err != nil {
+		logger.Error("Failed to save the secret key: %v", err)
+		return err
+	}
+	logger.Info("Added the secret key to %s", c.Output)
+	logger.Info("Add the recipient to 'recipients' in .skillspkg.toml, then encrypt values with 'skills-pkg encrypt'")
+
+	// The recipient is public, so it is the data output that scripts can capture
+	_, err = fmt.Fprintln(logger.dataOut, key.Recipient())
+	return err
+}
+
+// appendSecretKey adds the key to the key file at path, which only the current user can read.
+func appendSecretKey(path string, key *domain.SecretKey) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	_, err = fmt.Fprintf(f, "# created: %s\n# recipient: %s\n%s\n", time.Now().Format(time.RFC3339), key.Recipient(), key)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -3,14 +3,24 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+	"golang.org/x/sync/errgroup"
 )
 
 // ListCmd represents the list command
 type ListCmd struct {
+	Outdated bool `help:"Only list skills with a newer version available. Lookups are answered by 'skills-pkg daemon' when it is running"`
+
+	Output string `kong:"-"` // Set by Run from the global --output flag: text or json
 }
 
 // Run executes the list command
@@ -20,11 +30,12 @@ func (c *ListCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.Output = outputFlag(ctx)
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -41,14 +52,20 @@ func (c *ListCmd) run(configPath string, verbose bool) error {
 // runWithLogger executes the list command with a custom logger (for testing)
 // Requirements: 8.1, 8.2, 8.3, 8.4, 12.1, 12.2, 12.3
 func (c *ListCmd) runWithLogger(configPath string, logger *Logger) error {
+	if c.Outdated {
+		latest, closeLookup := newLatestVersionFunc(logger, pkgmanager.All())
+		defer closeLookup()
+		return c.runOutdated(configPath, logger, latest)
+	}
+
 	// Display progress information (requirement 12.1)
 	logger.Verbose("Loading skills from configuration")
 
 	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
+	configManager := newConfigManager(configPath)
 
 	// Load all skills (requirements 8.1, 8.2)
-	skills, err := configManager.ListSkills(context.Background())
+	config, err := configManager.Load(context.Background())
 	if err != nil {
 		// Handle different error types with appropriate messages (requirements 12.2, 12.3)
 		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
@@ -63,14 +80,31 @@ func (c *ListCmd) runWithLogger(configPath string, logger *Logger) error {
 		logger.Error("Check file permissions and try again")
 		return err
 	}
+	skills := config.InstalledSkills()
 
 	// Check if skills list is empty (requirement 8.4)
 	if len(skills) == 0 {
 		logger.Info("No skills installed")
 		logger.Info("Use 'skills-pkg add <name> --source <type> --url <url>' to add skills")
+		if c.Output == "json" {
+			return writeJSON(logger, &listOutput{Skills: []*listOutputSkill{}})
+		}
 		return nil
 	}
 
+	// Per-target freshness is read from the lock file written by install and update
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(context.Background())
+	if err != nil {
+		logger.Error("Warning: failed to read lock file, install target status is unavailable: %v", err)
+	}
+	hashService := service.NewDirhash()
+
+	if c.Output == "json" {
+		return writeJSON(logger, newListOutput(config, skills, lock, func(skill *domain.Skill, status *domain.TargetStatus) string {
+			return c.freshness(logger, hashService, config, skill, status)
+		}))
+	}
+
 	// Display skills in a table format (requirements 8.2, 8.3)
 	logger.Info("")
 	logger.Info("Installed Skills:")
@@ -78,7 +112,26 @@ func (c *ListCmd) runWithLogger(configPath string, logger *Logger) error {
 	logger.Info("%s", "--------------------------------------------------------------------------------")
 
 	for _, skill := range skills {
-		logger.Info("%-20s %-15s %-30s", skill.Name, skill.Source, skill.Version)
+		if skill.Canary != nil {
+			logger.Info("%-20s %-15s %-30s (canary %s in %s)", skill.Name, skill.Source, skill.Version, skill.Canary.Version, skill.Canary.Target)
+		} else {
+			logger.Info("%-20s %-15s %-30s", skill.Name, skill.Source, skill.Version)
+		}
+		if lock == nil {
+			continue
+		}
+
+		locked := lock.FindSkill(skill.Name)
+		for _, target := range config.InstallTargets {
+			status := locked.TargetStatus(target)
+			freshness := c.freshness(logger, hashService, config, skill, status)
+
+			if status == nil {
+				logger.Info("  %-40s %s", target, freshness)
+				continue
+			}
+			logger.Info("  %-40s %-14s installed %s at %s", target, freshness, status.Version, status.InstalledAt.Local().Format(time.DateTime))
+		}
 	}
 
 	logger.Info("")
@@ -86,3 +139,191 @@ func (c *ListCmd) runWithLogger(configPath string, logger *Logger) error {
 
 	return nil
 }
+
+// freshness returns the state of the installation of skill in an install target, or "unknown" when it cannot be checked.
+func (c *ListCmd) freshness(logger *Logger, hashService port.HashService, config *domain.Config, skill *domain.Skill, status *domain.TargetStatus) string {
+	freshness, err := domain.CheckTargetFreshness(context.Background(), hashService, config, skill, status)
+	if err != nil {
+		logger.Verbose("Failed to check %s in %s: %v", skill.Name, status.Path, err)
+		return "unknown"
+	}
+	return string(freshness)
+}
+
+// listOutput is the JSON output of the list command.
+type listOutput struct {
+	Skills []*listOutputSkill `json:"skills"`
+}
+
+// listOutputSkill is a configured skill in the JSON output of the list command.
+type listOutputSkill struct {
+	Name    string              `json:"name"`
+	Source  string              `json:"source"`
+	URL     string              `json:"url"`
+	Version string              `json:"version"`
+	Hash    string              `json:"hash,omitempty"`
+	Targets []*listOutputTarget `json:"targets,omitempty"`
+}
+
+// listOutputTarget is the installation of a skill in an install target in the JSON output of the list command.
+type listOutputTarget struct {
+	Target      string     `json:"target"`
+	Status      string     `json:"status"`
+	Version     string     `json:"version,omitempty"`
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+}
+
+// newListOutput converts the configured skills and their installations recorded in lock into
+// their JSON-serializable form. Installations are left out when the lock file could not be read.
+func newListOutput(config *domain.Config, skills []*domain.Skill, lock *domain.LockFile, freshness func(*domain.Skill, *domain.TargetStatus) string) *listOutput {
+	output := &listOutput{Skills: make([]*listOutputSkill, 0, len(skills))}
+	for _, skill := range skills {
+		item := &listOutputSkill{Name: skill.Name, Source: skill.Source, URL: config.Sealed(skill.URL), Version: skill.Version, Hash: skill.HashValue}
+		output.Skills = append(output.Skills, item)
+		if lock == nil {
+			continue
+		}
+
+		locked := lock.FindSkill(skill.Name)
+		if item.Hash == "" && locked != nil {
+			item.Hash = locked.HashValue
+		}
+		for _, target := range config.InstallTargets {
+			status := locked.TargetStatus(target)
+			installation := &listOutputTarget{Target: target, Status: freshness(skill, status)}
+			if status != nil {
+				installation.Version = status.Version
+				installation.InstalledAt = &status.InstalledAt
+			}
+			item.Targets = append(item.Targets, installation)
+		}
+	}
+	return output
+}
+
+// latestVersionFunc returns the latest version of a source.
+type latestVersionFunc func(ctx context.Context, source *port.Source) (string, error)
+
+// newLatestVersionFunc returns a lookup answered by the daemon when it is running,
+// or by the package managers otherwise, and a function that releases the daemon connection.
+func newLatestVersionFunc(logger *Logger, packageManagers []port.PackageManager) (latestVersionFunc, func()) {
+	if dirs, err := domain.ResolveUserDirs(); err == nil {
+		if client, err := dialDaemon(dirs.DaemonSocket()); err == nil {
+			logger.Verbose("Looking up latest versions with the daemon at %s", dirs.DaemonSocket())
+			return func(_ context.Context, source *port.Source) (string, error) {
+				return client.LatestVersion(source)
+			}, func() { _ = client.Close() }
+		}
+	}
+
+	return func(ctx context.Context, source *port.Source) (string, error) {
+		for _, pm := range packageManagers {
+			if pm.SourceType() == source.Type {
+				return pm.GetLatestVersion(ctx, source)
+			}
+		}
+		return "", fmt.Errorf("unsupported source type '%s'", source.Type)
+	}, func() {}
+}
+
+// outdatedOutput is the JSON output of 'list --outdated'.
+type outdatedOutput struct {
+	Skills []*outdatedOutputSkill `json:"skills"`
+}
+
+// outdatedOutputSkill is a skill with a newer version in the JSON output of 'list --outdated'.
+type outdatedOutputSkill struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"current_version"`
+	LatestVersion  string `json:"latest_version"`
+}
+
+// runOutdated lists the skills whose latest version differs from the configured version.
+func (c *ListCmd) runOutdated(configPath string, logger *Logger, latest latestVersionFunc) error {
+	ctx := context.Background()
+
+	config, err := newConfigManager(configPath).Load(ctx)
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load skills from configuration: %v", err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+	skills := config.InstalledSkills()
+
+	// Skills installed from the same source share one lookup
+	versions := make(map[latestVersionParams]string)
+	var mu sync.Mutex
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(8)
+	for _, skill := range skills {
+		key := latestVersionParams{Source: skill.Source, URL: config.Sealed(skill.URL)}
+		mu.Lock()
+		_, seen := versions[key]
+		versions[key] = ""
+		mu.Unlock()
+		if seen {
+			continue
+		}
+
+		eg.Go(func() error {
+			// Encrypted URLs are looked up by their plaintext, and reported encrypted
+			source, err := config.SourceOf(skill)
+			if err != nil {
+				logger.Error("Warning: failed to look up the latest version of %s: %v", key.URL, err)
+				return nil
+			}
+			version, err := latest(egCtx, source)
+			if err != nil {
+				logger.Error("Warning: failed to look up the latest version of %s: %v", key.URL, err)
+				return nil
+			}
+			mu.Lock()
+			versions[key] = version
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	if c.Output == "json" {
+		output := &outdatedOutput{Skills: []*outdatedOutputSkill{}}
+		for _, skill := range skills {
+			latestVersion := versions[latestVersionParams{Source: skill.Source, URL: config.Sealed(skill.URL)}]
+			if latestVersion != "" && latestVersion != skill.Version {
+				output.Skills = append(output.Skills, &outdatedOutputSkill{Name: skill.Name, CurrentVersion: skill.Version, LatestVersion: latestVersion})
+			}
+		}
+		return writeJSON(logger, output)
+	}
+
+	outdated := 0
+	for _, skill := range skills {
+		latestVersion := versions[latestVersionParams{Source: skill.Source, URL: config.Sealed(skill.URL)}]
+		if latestVersion == "" || latestVersion == skill.Version {
+			continue
+		}
+		if outdated == 0 {
+			logger.Info("%-20s %-15s %-15s", "NAME", "CURRENT", "LATEST")
+		}
+		current := skill.Version
+		if current == "" {
+			current = "-"
+		}
+		logger.Info("%-20s %-15s %-15s", skill.Name, current, latestVersion)
+		outdated++
+	}
+
+	if outdated == 0 {
+		logger.Info("All skills are up to date")
+		return nil
+	}
+	logger.Info("")
+	logger.Info("%d skill(s) can be updated. Run 'skills-pkg update' to apply updates.", outdated)
+
+	return nil
+}
@@ -3,12 +3,14 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 func TestListCmd_Run(t *testing.T) {
@@ -143,6 +145,49 @@ func TestListCmd_Run(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success: list shows install target status",
+			setupFunc: func(t *testing.T) (string, func()) {
+				t.Helper()
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+				installedTarget := filepath.Join(tmpDir, "installed")
+				missingTarget := filepath.Join(tmpDir, "missing")
+
+				cm := domain.NewConfigManager(configPath)
+				if err := cm.Initialize(context.Background(), []string{installedTarget, missingTarget}); err != nil {
+					t.Fatalf("failed to initialize config: %v", err)
+				}
+				if err := cm.AddSkill(context.Background(), &domain.Skill{
+					Name:    "git-skill",
+					Source:  "git",
+					URL:     "https://github.com/example/skill.git",
+					Version: "v1.0.0",
+				}); err != nil {
+					t.Fatalf("failed to add git skill: %v", err)
+				}
+
+				lockManager := domain.NewLockManager(domain.LockPathFor(configPath))
+				if err := lockManager.Update(context.Background(), func(lock *domain.LockFile) {
+					lock.RecordInstall("git-skill", &domain.TargetStatus{Path: installedTarget, Version: "v0.9.0"})
+				}); err != nil {
+					t.Fatalf("failed to write lock file: %v", err)
+				}
+
+				return configPath, func() {}
+			},
+			wantErr: false,
+			checkFunc: func(t *testing.T, output string) {
+				t.Helper()
+
+				if !strings.Contains(output, "outdated") || !strings.Contains(output, "v0.9.0") {
+					t.Errorf("output should show the outdated installed version, got: %s", output)
+				}
+				if !strings.Contains(output, "not-installed") {
+					t.Errorf("output should show the target without an installation, got: %s", output)
+				}
+			},
+		},
 		{
 			name: "error: configuration file not found",
 			setupFunc: func(t *testing.T) (string, func()) {
@@ -214,3 +259,95 @@ func TestListCmd_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestListCmd_RunOutdated(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	cm := domain.NewConfigManager(configPath)
+	if err := cm.Initialize(context.Background(), nil); err != nil {
+		t.Fatalf("failed to initialize config: %v", err)
+	}
+	for _, skill := range []*domain.Skill{
+		{Name: "old-skill", Source: "git", URL: "https://github.com/example/old.git", Version: "v1.0.0"},
+		{Name: "current-skill", Source: "git", URL: "https://github.com/example/current.git", Version: "v2.0.0"},
+	} {
+		if err := cm.AddSkill(context.Background(), skill); err != nil {
+			t.Fatalf("failed to add skill: %v", err)
+		}
+	}
+
+	latest := func(_ context.Context, source *port.Source) (string, error) {
+		if source.URL == "https://github.com/example/old.git" {
+			return "v1.1.0", nil
+		}
+		return "v2.0.0", nil
+	}
+
+	var buf bytes.Buffer
+	cmd := &ListCmd{Outdated: true}
+	if err := cmd.runOutdated(configPath, &Logger{out: &buf, dataOut: &buf, errOut: &buf}, latest); err != nil {
+		t.Fatalf("runOutdated() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "old-skill") || !strings.Contains(output, "v1.1.0") {
+		t.Errorf("output should list old-skill with its latest version, got:\n%s", output)
+	}
+	if strings.Contains(output, "current-skill") {
+		t.Errorf("output should not list up-to-date skills, got:\n%s", output)
+	}
+	if !strings.Contains(output, "1 skill(s) can be updated") {
+		t.Errorf("output should count outdated skills, got:\n%s", output)
+	}
+}
+
+func TestListCmd_RunJSON(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	cm := domain.NewConfigManager(configPath)
+	if err := cm.Initialize(context.Background(), []string{filepath.Join(tmpDir, "skills")}); err != nil {
+		t.Fatalf("failed to initialize config: %v", err)
+	}
+	skill := &domain.Skill{Name: "git-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0", HashValue: "abc123"}
+	if err := cm.AddSkill(context.Background(), skill); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+
+	var out, dataOut bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &dataOut, errOut: &out}
+	if err := (&ListCmd{Output: "json"}).runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("runWithLogger() error = %v", err)
+	}
+
+	var output listOutput
+	if err := json.Unmarshal(dataOut.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, dataOut.String())
+	}
+	if len(output.Skills) != 1 {
+		t.Fatalf("output has %d skills, want 1:\n%s", len(output.Skills), dataOut.String())
+	}
+	got := output.Skills[0]
+	if got.Name != "git-skill" || got.Version != "v1.0.0" || got.Hash != "abc123" {
+		t.Errorf("skill = %+v, want git-skill v1.0.0 with hash abc123", got)
+	}
+	if len(got.Targets) == 0 || got.Targets[0].Status != string(domain.TargetNotInstalled) {
+		t.Errorf("targets = %+v, want the install targets with status not-installed", got.Targets)
+	}
+
+	// Outdated skills are written with their current and latest versions
+	dataOut.Reset()
+	latest := func(context.Context, *port.Source) (string, error) { return "v1.1.0", nil }
+	if err := (&ListCmd{Outdated: true, Output: "json"}).runOutdated(configPath, logger, latest); err != nil {
+		t.Fatalf("runOutdated() error = %v", err)
+	}
+	var outdated outdatedOutput
+	if err := json.Unmarshal(dataOut.Bytes(), &outdated); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, dataOut.String())
+	}
+	if len(outdated.Skills) != 1 || outdated.Skills[0].LatestVersion != "v1.1.0" {
+		t.Errorf("outdated = %+v, want git-skill with latest version v1.1.0", outdated.Skills)
+	}
+}
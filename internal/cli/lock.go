@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/signer"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// LockCmd represents the lock command group
+type LockCmd struct {
+	Sign   LockSignCmd   `cmd:"" help:"Sign the versions in .skillspkg.lock with the secret key of the configured [signing] public key"`
+	Verify LockVerifyCmd `cmd:"" help:"Verify the signature of .skillspkg.lock"`
+}
+
+// LockSignCmd represents the lock sign command
+type LockSignCmd struct {
+	Key string `help:"Secret key to sign with: a minisign secret key file, or any key cosign accepts" env:"SKILLSPKG_SIGNING_KEY" placeholder:"KEY"`
+}
+
+// LockVerifyCmd represents the lock verify command
+type LockVerifyCmd struct{}
+
+// lockSigners returns the signers of every signing method.
+func lockSigners() []port.Signer {
+	return []port.Signer{signer.NewMinisign(), signer.NewCosign()}
+}
+
+// Run executes the lock sign command
+func (c *LockSignCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.Key = projectFile(c.Key)
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger signs the lock file of the configuration at configPath (for testing)
+func (c *LockSignCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger) error {
+	configManager := newConfigManager(configPath)
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+	if c.Key == "" {
+		err := errors.New("no signing key is given")
+		logger.Error("%v. Pass --key or set SKILLSPKG_SIGNING_KEY", err)
+		return err
+	}
+
+	path, err := domain.NewLockSigner(configManager, lockSigners()).Sign(ctx, config.Signing, c.Key)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	logger.Info("Signed lock file: %s", path)
+	logger.Info("Commit it with .skillspkg.lock; installs verify it and only install the signed versions")
+	return nil
+}
+
+// Run executes the lock verify command
+func (c *LockVerifyCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger verifies the signature of the lock file of the configuration at configPath (for testing)
+func (c *LockVerifyCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger) error {
+	configManager := newConfigManager(configPath)
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	if err := domain.NewLockSigner(configManager, lockSigners()).Verify(ctx, config.Signing); err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	logger.Info("Lock file signature is valid")
+	return nil
+}
+
+// verifyLockSignature verifies the signature of the lock file when the configuration has a [signing]
+// table. It reports whether the lock file is signed, in which case only the signed versions may be installed.
+func verifyLockSignature(ctx context.Context, configManager *domain.ConfigManager, logger *Logger) (bool, error) {
+	config, err := configManager.Load(ctx)
+	if err != nil || config.Signing == nil {
+		// Configuration errors are reported by the command itself
+		return false, nil
+	}
+
+	logger.Verbose("Verifying lock file signature")
+	if err := domain.NewLockSigner(configManager, lockSigners()).Verify(ctx, config.Signing); err != nil {
+		logger.Error("%v", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// signLockFile signs the lock file after it changed when the configuration has a [signing] table,
+// or warns that the signature is stale when no signing key is given.
+func signLockFile(ctx context.Context, configManager *domain.ConfigManager, key string, logger *Logger) error {
+	config, err := configManager.Load(ctx)
+	if err != nil || config.Signing == nil {
+		return err
+	}
+	if key == "" {
+		logger.Info("WARNING: .skillspkg.lock changed and its signature no longer matches. Run 'skills-pkg lock sign' to sign it")
+		return nil
+	}
+
+	path, err := domain.NewLockSigner(configManager, lockSigners()).Sign(ctx, config.Signing, key)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	logger.Info("Signed lock file: %s", path)
+	return nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestLockVerifyCmd(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".skillspkg.toml")
+	config := &domain.Config{InstallTargets: []string{filepath.Join(dir, "install")}}
+	if err := domain.NewConfigManager(configPath).Save(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+
+	if _, ok := errors.AsType[*domain.ErrorInvalidSigning]((&LockVerifyCmd{}).runWithLogger(ctx, configPath, logger)); !ok {
+		t.Errorf("lock verify without [signing] did not report the missing settings\noutput: %s", out.String())
+	}
+
+	config.Signing = &domain.SigningSettings{PublicKey: "minisign.pub"}
+	if err := domain.NewConfigManager(configPath).Save(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	err := (&LockVerifyCmd{}).runWithLogger(ctx, configPath, logger)
+	if _, ok := errors.AsType[*domain.ErrorSignatureInvalid](err); !ok {
+		t.Errorf("lock verify of an unsigned lock file error = %v, want ErrorSignatureInvalid", err)
+	}
+
+	// Installing refuses a lock file that is not signed
+	err = (&InstallCmd{}).runWithDeps(configPath, false, &mockHashService{}, nil)
+	if _, ok := errors.AsType[*domain.ErrorSignatureInvalid](err); !ok {
+		t.Errorf("install with an unsigned lock file error = %v, want ErrorSignatureInvalid", err)
+	}
+
+	if err := (&LockSignCmd{}).runWithLogger(ctx, configPath, logger); err == nil {
+		t.Error("lock sign without a key succeeded")
+	}
+}
@@ -18,9 +18,9 @@ type Logger struct {
 // NewLogger creates a new Logger instance
 func NewLogger(verbose bool) *Logger {
 	return &Logger{
-		out:     os.Stderr,
+		out:     withOperationLog(os.Stderr),
 		dataOut: os.Stdout,
-		errOut:  os.Stderr,
+		errOut:  withOperationLog(os.Stderr),
 		verbose: verbose,
 	}
 }
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// progressLevel is the lowest level of the progress messages of skills that are printed.
+var progressLevel = new(slog.LevelVar)
+
+// ConfigureLogging sets which progress messages of skills are printed before a command runs:
+// only warnings with --quiet, which takes precedence, and also the debug messages, such as
+// where downloads come from, with --verbose.
+func ConfigureLogging(verbose, quiet bool) {
+	switch {
+	case quiet:
+		progressLevel.Set(slog.LevelWarn)
+	case verbose:
+		progressLevel.Set(slog.LevelDebug)
+	default:
+		progressLevel.Set(slog.LevelInfo)
+	}
+}
+
+// configLogger returns the logger of the warnings about the configuration, which prints the messages
+// at or above the configured level to standard error.
+func configLogger() *slog.Logger {
+	return slog.New(domain.NewMessageHandler(withOperationLog(os.Stderr), progressLevel))
+}
+
+// progressLogger returns the logger of the progress messages of skills, which prints the messages
+// at or above the configured level to standard output.
+func progressLogger() *slog.Logger {
+	return slog.New(domain.NewMessageHandler(withOperationLog(os.Stdout), progressLevel))
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// apiOperations are the operations of the APIs counted by the metrics, in exposition order.
+var apiOperations = []string{"install", "update", "verify"}
+
+// metricFamily is a metric in the Prometheus text exposition format.
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string // "counter" or "gauge"
+	samples []metricSample
+}
+
+// metricSample is a value of a metric family with its labels.
+type metricSample struct {
+	labels [][2]string // Label names and values
+	value  float64
+}
+
+// writeMetrics writes the metric families in the Prometheus text exposition format.
+func writeMetrics(w io.Writer, families []*metricFamily) error {
+	var b strings.Builder
+	for _, family := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", family.name, family.kind)
+		for _, sample := range family.samples {
+			b.WriteString(family.name)
+			if len(sample.labels) > 0 {
+				pairs := make([]string, 0, len(sample.labels))
+				for _, label := range sample.labels {
+					pairs = append(pairs, fmt.Sprintf("%s=%q", label[0], label[1]))
+				}
+				b.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			b.WriteString(" " + strconv.FormatFloat(sample.value, 'g', -1, 64) + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// metricsHandler serves the metric families returned by families on every scrape.
+func metricsHandler(families func() []*metricFamily) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = writeMetrics(w, families())
+	})
+	return mux
+}
+
+// listenMetrics listens on the address of a metrics endpoint like listenAPI does: an address without
+// a host listens on 127.0.0.1 only. The metrics do not require the token, so other addresses than
+// loopback are refused unless they are served over TLS with tlsConfig.
+func listenMetrics(addr string, tlsConfig *tls.Config) (net.Listener, error) {
+	host, address, err := loopbackByDefault(addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil && !isLoopbackHost(host) {
+		return nil, fmt.Errorf("refusing to serve metrics on %s without TLS, as they are served without authentication", addr)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	return listener, nil
+}
+
+// newMetricsServer returns the HTTP server of a metrics endpoint.
+func newMetricsServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// apiMetrics counts the operations of the APIs served by 'serve'.
+type apiMetrics struct {
+	downloadCache       *domain.DownloadCache // Nil when downloads are not cached
+	operations          map[string]int64
+	failures            map[string]int64
+	verifyMismatches    int64
+	lastVerifyFailures  int64
+	lastVerifyTimestamp time.Time
+	mu                  sync.Mutex
+}
+
+func newAPIMetrics(downloadCache *domain.DownloadCache) *apiMetrics {
+	return &apiMetrics{
+		downloadCache: downloadCache,
+		operations:    make(map[string]int64, len(apiOperations)),
+		failures:      make(map[string]int64, len(apiOperations)),
+	}
+}
+
+// observe counts an operation and whether it failed.
+func (m *apiMetrics) observe(operation string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.operations[operation]++
+	if err != nil {
+		m.failures[operation]++
+	}
+}
+
+// observeVerify records the skills whose hashes did not match in a verification.
+func (m *apiMetrics) observeVerify(summary *domain.VerifySummary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.verifyMismatches += int64(summary.FailureCount)
+	m.lastVerifyFailures = int64(summary.FailureCount)
+	m.lastVerifyTimestamp = time.Now()
+}
+
+// families returns the current values of the metrics.
+func (m *apiMetrics) families() []*metricFamily {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	operations := &metricFamily{name: "skillspkg_operations_total", help: "Operations requested through the APIs.", kind: "counter"}
+	failures := &metricFamily{name: "skillspkg_operation_failures_total", help: "Operations requested through the APIs that failed.", kind: "counter"}
+	for _, operation := range apiOperations {
+		labels := [][2]string{{"operation", operation}}
+		operations.samples = append(operations.samples, metricSample{labels: labels, value: float64(m.operations[operation])})
+		failures.samples = append(failures.samples, metricSample{labels: labels, value: float64(m.failures[operation])})
+	}
+
+	families := []*metricFamily{
+		operations,
+		failures,
+		{
+			name: "skillspkg_verify_mismatches_total", help: "Installed skills whose hashes did not match the lock file, summed over all verifications.", kind: "counter",
+			samples: []metricSample{{value: float64(m.verifyMismatches)}},
+		},
+		{
+			name: "skillspkg_verify_last_mismatches", help: "Installed skills whose hashes did not match the lock file in the last verification.", kind: "gauge",
+			samples: []metricSample{{value: float64(m.lastVerifyFailures)}},
+		},
+	}
+	if !m.lastVerifyTimestamp.IsZero() {
+		families = append(families, &metricFamily{
+			name: "skillspkg_verify_last_timestamp_seconds", help: "Unix time of the last verification.", kind: "gauge",
+			samples: []metricSample{{value: float64(m.lastVerifyTimestamp.Unix())}},
+		})
+	}
+	if m.downloadCache != nil {
+		hits, misses := m.downloadCache.Stats()
+		families = append(families,
+			&metricFamily{
+				name: "skillspkg_download_cache_hits_total", help: "Downloads of fixed revisions served from the download cache.", kind: "counter",
+				samples: []metricSample{{value: float64(hits)}},
+			},
+			&metricFamily{
+				name: "skillspkg_download_cache_misses_total", help: "Downloads of fixed revisions that were not in the download cache.", kind: "counter",
+				samples: []metricSample{{value: float64(misses)}},
+			},
+		)
+	}
+
+	return families
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	t.Parallel()
+
+	families := []*metricFamily{
+		{
+			name: "skillspkg_operations_total", help: "Operations.", kind: "counter",
+			samples: []metricSample{
+				{labels: [][2]string{{"operation", "install"}}, value: 3},
+				{labels: [][2]string{{"operation", "verify"}, {"target", `C:\skills "x"`}}, value: 0},
+			},
+		},
+		{
+			name: "skillspkg_verify_last_timestamp_seconds", help: "Last verification.", kind: "gauge",
+			samples: []metricSample{{value: 1.7e9}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, families); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+
+	want := `# HELP skillspkg_operations_total Operations.
+# TYPE skillspkg_operations_total counter
+skillspkg_operations_total{operation="install"} 3
+skillspkg_operations_total{operation="verify",target="C:\\skills \"x\""} 0
+# HELP skillspkg_verify_last_timestamp_seconds Last verification.
+# TYPE skillspkg_verify_last_timestamp_seconds gauge
+skillspkg_verify_last_timestamp_seconds 1.7e+09
+`
+	if got := buf.String(); got != want {
+		t.Errorf("writeMetrics() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestListenMetrics(t *testing.T) {
+	certPath, keyPath := writeTestCertificate(t)
+	tlsConfig, err := (&ServeCmd{TLSCert: certPath, TLSKey: keyPath}).tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		addr      string
+		tlsConfig *tls.Config
+		wantErr   bool
+	}{
+		{name: "no host listens on loopback", addr: ":0"},
+		{name: "all interfaces without TLS", addr: "0.0.0.0:0", wantErr: true},
+		{name: "all interfaces with TLS", addr: "0.0.0.0:0", tlsConfig: tlsConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := listenMetrics(tt.addr, tt.tlsConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listenMetrics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer func() { _ = listener.Close() }()
+
+			host, _, _ := net.SplitHostPort(listener.Addr().String())
+			if ip := net.ParseIP(host); tt.tlsConfig == nil && (ip == nil || !ip.IsLoopback()) {
+				t.Errorf("listener address = %s, want loopback", listener.Addr())
+			}
+		})
+	}
+
+	// With a TLS configuration, the metrics are served over TLS
+	listener, err := listenMetrics("127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("listenMetrics() error = %v", err)
+	}
+	server := newMetricsServer(metricsHandler(func() []*metricFamily {
+		return []*metricFamily{{name: "skillspkg_test", help: "Test.", kind: "gauge", samples: []metricSample{{value: 1}}}}
+	}))
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + listener.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics over TLS error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || !strings.Contains(string(body), "skillspkg_test 1") {
+		t.Errorf("GET /metrics = %q, %v, want the metrics", body, err)
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// mirrorEnv names the mirror that commands installing skills read downloads from.
+const mirrorEnv = "SKILLSPKG_MIRROR"
+
+// MirrorCmd represents the mirror command
+type MirrorCmd struct {
+	Dest string `arg:"" help:"Directory to write the mirror to; skills already in it are kept" type:"path"`
+
+	allowRoot     bool // Set from the global --allow-root flag
+	downloadCache bool // Set by Run to reuse downloads from the user cache directory
+}
+
+// Run executes the mirror command
+func (c *MirrorCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+
+	packageManagers := pkgmanager.All()
+	return c.runWithDeps(defaultConfigPath, NewLogger(verbose), packageManagers)
+}
+
+// runWithDeps downloads the skills of the configuration at configPath into the mirror (for testing)
+func (c *MirrorCmd) runWithDeps(configPath string, logger *Logger, packageManagers []port.PackageManager) error {
+	logger.Info("Mirroring skills to %s", c.Dest)
+
+	progress, flushProgress := progressOptions(logger, "text")
+	defer flushProgress()
+	opts := append(skillManagerOptions(c.allowRoot, c.downloadCache), progress...)
+	skillManager := domain.NewSkillManager(newConfigManager(configPath), service.NewDirhash(), packageManagers, opts...)
+
+	mirrored, err := skillManager.Mirror(context.Background(), c.Dest)
+	if err != nil {
+		logger.Error("Failed to mirror skills: %v", err)
+		return err
+	}
+
+	count := 0
+	for _, m := range mirrored {
+		switch {
+		case m.Reason != "":
+			logger.Error("WARNING: skill '%s' was not mirrored: %s", m.SkillName, m.Reason)
+		case m.Existing:
+			count++
+			logger.Verbose("%s@%s is already in the mirror", m.SkillName, m.Version)
+		default:
+			count++
+			logger.Info("Mirrored %s@%s", m.SkillName, m.Version)
+		}
+	}
+
+	logger.Info("%d version(s) of skills are in %s", count, c.Dest)
+	logger.Info("Install from it without network access with: %s=%s skills-pkg install --frozen", mirrorEnv, c.Dest)
+	return nil
+}
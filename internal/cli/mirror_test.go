@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestMirrorCmd(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".skillspkg.toml")
+	downloadDir := filepath.Join(dir, "download")
+	if err := os.MkdirAll(filepath.Join(downloadDir, "skills", "my-skill"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := domain.NewConfigManager(configPath).Save(ctx, &domain.Config{
+		Skills: []*domain.Skill{
+			{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0"},
+			{Name: "unpinned", Source: "git", URL: "https://github.com/example/unpinned.git"},
+		},
+		InstallTargets: []string{filepath.Join(dir, "install")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+	cmd := &MirrorCmd{Dest: filepath.Join(dir, "mirror")}
+	if err := cmd.runWithDeps(configPath, logger, []port.PackageManager{&mockPackageManager{sourceType: "git", tmpDir: downloadDir}}); err != nil {
+		t.Fatalf("mirror error = %v\noutput: %s", err, out.String())
+	}
+
+	for _, want := range []string{"Mirrored my-skill@v1.0.0", "WARNING: skill 'unpinned' was not mirrored", "SKILLSPKG_MIRROR="} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output should contain %q, got: %s", want, out.String())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cmd.Dest, domain.MirrorIndexFileName)); err != nil {
+		t.Errorf("mirror index is missing: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// ConfigureNetwork applies the [network] settings of the user-level configuration, the bandwidth limit,
+// the hosts that may be connected to, and the headers sent, to the downloads of every source type before
+// a command runs. limitRate is the --limit-rate flag, which takes precedence over the configured limit,
+// and version the version of skills-pkg, sent in the User-Agent unless user_agent is configured.
+// From VerbosityTrace on, requests and git operations are traced to standard error.
+func ConfigureNetwork(limitRate, version string, verbosity Verbosity) error {
+	logger := NewLogger(verbosity >= VerbosityVerbose)
+	if verbosity >= VerbosityTrace {
+		network.SetTrace(withOperationLog(os.Stderr), verbosity >= VerbosityDump)
+	}
+
+	settings := userNetworkSettings(logger)
+	if settings == nil {
+		settings = &domain.NetworkSettings{}
+	}
+
+	// The settings were validated when the user-level configuration was loaded
+	policy, _ := settings.Policy()
+	network.SetPolicy(policy)
+	if policy != nil {
+		logger.Verbose("Restricting downloads to the hosts allowed by the [network] settings")
+	}
+
+	userAgent := settings.UserAgent
+	if userAgent == "" {
+		userAgent = "skills-pkg/" + version
+	}
+	network.SetHeaders(userAgent, settings.Headers)
+	if len(settings.Headers) > 0 {
+		logger.Verbose("Adding the headers of the [network] settings to the requests to their hosts")
+	}
+
+	if limitRate == "" {
+		limitRate = settings.LimitRate
+	}
+
+	// Downloads run as the invoking user under sudo apply the same settings
+	helperSettings := *settings
+	helperSettings.UserAgent = userAgent
+	helperSettings.LimitRate = limitRate
+	pkgmanager.SetDownloadHelperNetwork(&helperSettings)
+
+	if limitRate == "" {
+		return nil
+	}
+
+	rate, err := domain.ParseByteRate(limitRate)
+	if err != nil {
+		return fmt.Errorf("invalid --limit-rate: %w", err)
+	}
+	network.SetRateLimit(rate)
+	logger.Verbose("Limiting downloads to %s per second", limitRate)
+
+	return nil
+}
+
+// userNetworkSettings returns the [network] table of the user-level configuration, or nil when there is none.
+func userNetworkSettings(logger *Logger) *domain.NetworkSettings {
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		return nil
+	}
+	userConfig, err := domain.LoadUserConfig(dirs.ConfigFile())
+	if err != nil {
+		logger.Error("Warning: failed to load user configuration, downloads are not limited: %v", err)
+		return nil
+	}
+	return userConfig.Network
+}
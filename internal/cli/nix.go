@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+	"golang.org/x/mod/module"
+)
+
+// defaultGoProxy is the module proxy go-mod skills are fetched from when GOPROXY names none.
+const defaultGoProxy = "https://proxy.golang.org"
+
+// commitHashPattern matches a full Git commit hash.
+var commitHashPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// NixCmd represents the nix command
+type NixCmd struct {
+	Output string `name:"out" short:"o" type:"path" help:"Write the Nix expression to a file instead of stdout"`
+}
+
+//go:embed templates/nix.tmpl
+var nixExpressionTemplate string
+
+var nixExpression = template.Must(template.New("nix").Funcs(template.FuncMap{"nix": nixString}).Parse(nixExpressionTemplate))
+
+// nixSkill is a skill in the generated Nix expression.
+type nixSkill struct {
+	Name    string // Directory name of the skill in skillsDir
+	Fetcher string // pkgs.fetchgit or pkgs.fetchzip
+	URL     string
+	Rev     string // Git revision; empty for fetchzip
+	Hash    string // SRI hash of the fetched source
+	SubDir  string
+}
+
+// Run executes the nix command
+func (c *NixCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *NixCmd) run(configPath string, verbose bool) error {
+	return c.runWithPackageManagers(configPath, NewLogger(verbose), []port.PackageManager{
+		pkgmanager.Unprivileged(pkgmanager.NewGit()),
+		pkgmanager.Unprivileged(pkgmanager.NewGoMod()),
+	})
+}
+
+// runWithPackageManagers generates the Nix expression, downloading skills with the given package managers (for testing)
+func (c *NixCmd) runWithPackageManagers(configPath string, logger *Logger, packageManagers []port.PackageManager) error {
+	ctx := context.Background()
+
+	config, err := newConfigManager(configPath).Load(ctx)
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	installed := config.InstalledSkills()
+	skills := make([]*nixSkill, 0, len(installed))
+	for _, skill := range installed {
+		logger.Info("Pinning skill '%s'...", skill.Name)
+		s, err := c.pin(ctx, config, skill, lock, packageManagers)
+		if err != nil {
+			logger.Error("Failed to pin skill '%s': %v", skill.Name, err)
+			return err
+		}
+		skills = append(skills, s)
+	}
+
+	var buf bytes.Buffer
+	if err := nixExpression.Execute(&buf, struct{ Skills []*nixSkill }{Skills: skills}); err != nil {
+		err = fmt.Errorf("failed to render Nix expression: %w", err)
+		logger.Error("%v", err)
+		return err
+	}
+
+	if c.Output == "" {
+		_, err = logger.dataOut.Write(buf.Bytes())
+		return err
+	}
+
+	if err := os.WriteFile(c.Output, buf.Bytes(), setupCIFilePerm); err != nil {
+		logger.Error("Failed to write %s: %v", c.Output, err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+	logger.Info("Created %s", c.Output)
+
+	return nil
+}
+
+// pin downloads the skill at its pinned version and computes the hash Nix expects for its source.
+func (c *NixCmd) pin(ctx context.Context, config *domain.Config, skill *domain.Skill, lock *domain.LockFile, packageManagers []port.PackageManager) (*nixSkill, error) {
+	version := pinnedVersion(skill, lock)
+	if version == "" {
+		return nil, fmt.Errorf("no version is pinned. Run 'skills-pkg install %s' to record the installed version first", skill.Name)
+	}
+
+	s := &nixSkill{Name: skill.DirName(), SubDir: strings.Trim(skill.SubDir, "/")}
+	switch skill.Source {
+	case "git":
+		s.Fetcher = "fetchgit"
+		s.URL = config.Sealed(skill.URL)
+	case "go-mod":
+		escapedPath, err := module.EscapePath(skill.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module path %s: %w", skill.URL, err)
+		}
+		escapedVersion, err := module.EscapeVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module version %s: %w", version, err)
+		}
+		s.Fetcher = "fetchzip"
+		s.URL = fmt.Sprintf("%s/%s/@v/%s.zip", goProxyURL(), escapedPath, escapedVersion)
+	default:
+		return nil, &domain.ErrorInvalidSource{SourceType: skill.Source}
+	}
+
+	var pm port.PackageManager
+	for _, candidate := range packageManagers {
+		if candidate.SourceType() == skill.Source {
+			pm = candidate
+			break
+		}
+	}
+	if pm == nil {
+		return nil, &domain.ErrorInvalidSource{SourceType: skill.Source}
+	}
+
+	source, err := config.SourceOf(skill)
+	if err != nil {
+		return nil, err
+	}
+	result, err := pm.Download(ctx, source, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(result.Path) }()
+
+	// Branches resolve to the commit checked out, which is fetched by its hash like a pinned commit
+	if s.Fetcher == "fetchgit" {
+		s.Rev = result.Version
+		if !commitHashPattern.MatchString(result.Version) {
+			s.Rev = "refs/tags/" + result.Version
+		}
+	}
+
+	// pkgs.fetchgit removes the .git directory from the checkout
+	s.Hash, err = service.NarHash(result.Path, ".git")
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate Nix hash: %w", err)
+	}
+
+	return s, nil
+}
+
+// pinnedVersion returns the configured version of the skill, or the version recorded
+// in the lock file for skills whose version is resolved at install time.
+func pinnedVersion(skill *domain.Skill, lock *domain.LockFile) string {
+	if skill.Version != "" {
+		return skill.Version
+	}
+	if locked := lock.FindSkill(skill.Name); locked != nil {
+		for _, status := range locked.Targets {
+			if status.Version != "" {
+				return status.Version
+			}
+		}
+	}
+	return ""
+}
+
+// goProxyURL returns the first module proxy URL in GOPROXY, or the default proxy.
+func goProxyURL() string {
+	for entry := range strings.FieldsFuncSeq(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if entry = strings.TrimSpace(entry); strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return strings.TrimSuffix(entry, "/")
+		}
+	}
+	return defaultGoProxy
+}
+
+// nixString returns s as a Nix string literal.
+func nixString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// fixturePackageManager downloads a fresh copy of a fixture for every request,
+// since the nix command removes each download after hashing it.
+type fixturePackageManager struct {
+	files      map[string]string
+	resolved   map[string]string // Version downloaded for each requested version that is not a tag or commit
+	sourceType string
+	tmpRoot    string
+	versions   []string
+}
+
+func (m *fixturePackageManager) SourceType() string {
+	return m.sourceType
+}
+
+func (m *fixturePackageManager) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	m.versions = append(m.versions, version)
+	dir, err := os.MkdirTemp(m.tmpRoot, "download-")
+	if err != nil {
+		return nil, err
+	}
+	for name, content := range m.files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if resolved, ok := m.resolved[version]; ok {
+		version = resolved
+	}
+	return &port.DownloadResult{Path: dir, Version: version}, nil
+}
+
+func (m *fixturePackageManager) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	return "latest", nil
+}
+
+func TestNixCmd_Run(t *testing.T) {
+	t.Setenv("GOPROXY", "https://goproxy.example.com/,direct")
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	config := &domain.Config{
+		Skills: []*domain.Skill{
+			{Name: "tagged", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0", SubDir: "skills/tagged/"},
+			{Name: "locked", Source: "git", URL: "https://github.com/example/other.git"},
+			{Name: "branch", Source: "git", URL: "https://github.com/example/branch.git", Version: "main"},
+			{Name: "module", Source: "go-mod", URL: "github.com/Example/mod", Version: "v0.2.0"},
+		},
+		InstallTargets: []string{filepath.Join(tmpDir, "install")},
+	}
+	if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	commit := strings.Repeat("a1", 20)
+	branchCommit := strings.Repeat("b2", 20)
+	err := domain.NewLockManager(domain.LockPathFor(configPath)).Update(context.Background(), func(lock *domain.LockFile) {
+		lock.RecordInstall("locked", &domain.TargetStatus{Path: config.InstallTargets[0], Version: commit})
+	})
+	if err != nil {
+		t.Fatalf("failed to record install: %v", err)
+	}
+
+	git := &fixturePackageManager{
+		sourceType: "git",
+		tmpRoot:    t.TempDir(),
+		files:      map[string]string{"SKILL.md": "# Skill\n", ".git/HEAD": "ref: refs/heads/main\n"},
+		resolved:   map[string]string{"main": branchCommit},
+	}
+	goMod := &fixturePackageManager{sourceType: "go-mod", tmpRoot: t.TempDir(), files: map[string]string{"SKILL.md": "# Skill\n"}}
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	cmd := &NixCmd{}
+	if err := cmd.runWithPackageManagers(configPath, logger, []port.PackageManager{git, goMod}); err != nil {
+		t.Fatalf("runWithPackageManagers() error = %v, stderr: %s", err, errOut.String())
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"{ pkgs ? import <nixpkgs> { } }:",
+		`"tagged" = {`,
+		`url = "https://github.com/example/skills.git";`,
+		`rev = "refs/tags/v1.0.0";`,
+		`subdir = "skills/tagged";`,
+		`rev = "` + commit + `";`,
+		`rev = "` + branchCommit + `";`,
+		`src = pkgs.fetchzip {`,
+		`url = "https://goproxy.example.com/github.com/!example/mod/@v/v0.2.0.zip";`,
+		"skillsDir = pkgs.linkFarm",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "refs/tags/main") {
+		t.Errorf("branch is fetched as a tag:\n%s", got)
+	}
+
+	// The .git directory is excluded from the hash, so both fixtures hash alike
+	if n := strings.Count(got, "hash = \"sha256-"); n != 4 {
+		t.Errorf("output contains %d hashes, want 4:\n%s", n, got)
+	}
+	hashes := map[string]bool{}
+	for line := range strings.SplitSeq(got, "\n") {
+		if strings.Contains(line, "hash = ") {
+			hashes[strings.TrimSpace(line)] = true
+		}
+	}
+	if len(hashes) != 1 {
+		t.Errorf("got %d distinct hashes, want 1: %v", len(hashes), hashes)
+	}
+
+	if want := []string{"v1.0.0", commit, "main"}; strings.Join(git.versions, ",") != strings.Join(want, ",") {
+		t.Errorf("git downloads = %v, want %v", git.versions, want)
+	}
+	if entries, _ := os.ReadDir(git.tmpRoot); len(entries) != 0 {
+		t.Errorf("downloads were not removed: %d left", len(entries))
+	}
+}
+
+func TestNixCmd_Run_Unpinned(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	config := &domain.Config{
+		Skills:         []*domain.Skill{{Name: "unpinned", Source: "git", URL: "https://github.com/example/skills.git"}},
+		InstallTargets: []string{filepath.Join(tmpDir, "install")},
+	}
+	if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+	cmd := &NixCmd{}
+	err := cmd.runWithPackageManagers(configPath, logger, []port.PackageManager{&fixturePackageManager{sourceType: "git", tmpRoot: t.TempDir()}})
+	if err == nil {
+		t.Fatal("runWithPackageManagers() expected error for a skill without a pinned version")
+	}
+	if !strings.Contains(errOut.String(), "skills-pkg install unpinned") {
+		t.Errorf("stderr does not suggest installing the skill: %s", errOut.String())
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %s", out.String())
+	}
+}
+
+func TestNixString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "skill", want: `"skill"`},
+		{name: "quote and backslash", in: `a"b\c`, want: `"a\"b\\c"`},
+		{name: "interpolation", in: "${x}", want: `"\${x}"`},
+		{name: "newline", in: "a\nb", want: `"a\nb"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nixString(tt.in); got != tt.want {
+				t.Errorf("nixString(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
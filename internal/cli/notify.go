@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/notify"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// operationNotifier sends desktop notifications about a command according to the
+// [notifications] section of the user-level configuration.
+// Notification failures never fail the command; they are only logged in verbose mode.
+type operationNotifier struct {
+	start      time.Time
+	notifier   port.Notifier
+	logger     *Logger
+	userConfig *domain.UserConfig
+}
+
+// newOperationNotifier loads the user-level configuration and starts timing the current operation.
+func newOperationNotifier(logger *Logger) *operationNotifier {
+	n := &operationNotifier{
+		start:    time.Now(),
+		notifier: notify.NewDesktop(),
+		logger:   logger,
+	}
+
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		logger.Verbose("Desktop notifications disabled: %v", err)
+		return n
+	}
+	userConfig, err := domain.LoadUserConfig(dirs.ConfigFile())
+	if err != nil {
+		logger.Error("Warning: desktop notifications disabled: %v", err)
+		return n
+	}
+	n.userConfig = userConfig
+
+	return n
+}
+
+// completed notifies that the operation finished if it ran for at least notifications.min_duration.
+func (n *operationNotifier) completed(title, message string) {
+	if time.Since(n.start) < n.userConfig.NotificationMinDuration() {
+		return
+	}
+	n.alert(title, message)
+}
+
+// alert notifies regardless of how long the operation ran, e.g., for verification failures.
+func (n *operationNotifier) alert(title, message string) {
+	if !n.userConfig.NotificationsEnabled() {
+		return
+	}
+	if err := n.notifier.Notify(context.Background(), title, message); err != nil {
+		n.logger.Verbose("%v", err)
+	}
+}
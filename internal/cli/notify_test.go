@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// recordingNotifier records the titles of the notifications it shows.
+type recordingNotifier struct {
+	titles []string
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, title, message string) error {
+	n.titles = append(n.titles, title)
+	return nil
+}
+
+func TestOperationNotifier(t *testing.T) {
+	enabled := &domain.UserConfig{Notifications: &domain.NotificationSettings{Enabled: true, MinDuration: "1m"}}
+
+	tests := []struct {
+		userConfig *domain.UserConfig
+		notify     func(n *operationNotifier)
+		name       string
+		elapsed    time.Duration
+		wantCount  int
+	}{
+		{
+			name:       "long operation is notified",
+			userConfig: enabled,
+			elapsed:    2 * time.Minute,
+			notify:     func(n *operationNotifier) { n.completed("done", "") },
+			wantCount:  1,
+		},
+		{
+			name:       "short operation is not notified",
+			userConfig: enabled,
+			elapsed:    time.Second,
+			notify:     func(n *operationNotifier) { n.completed("done", "") },
+			wantCount:  0,
+		},
+		{
+			name:       "alert ignores duration",
+			userConfig: enabled,
+			elapsed:    time.Second,
+			notify:     func(n *operationNotifier) { n.alert("failed", "") },
+			wantCount:  1,
+		},
+		{
+			name:       "disabled without user config",
+			userConfig: nil,
+			elapsed:    2 * time.Minute,
+			notify:     func(n *operationNotifier) { n.alert("failed", "") },
+			wantCount:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &recordingNotifier{}
+			var buf bytes.Buffer
+			n := &operationNotifier{
+				start:      time.Now().Add(-tt.elapsed),
+				notifier:   notifier,
+				logger:     &Logger{out: &buf, dataOut: &buf, errOut: &buf},
+				userConfig: tt.userConfig,
+			}
+
+			tt.notify(n)
+
+			if len(notifier.titles) != tt.wantCount {
+				t.Errorf("notifications = %v, want %d", notifier.titles, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/agent"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// errOnboardingCancelled is returned when the input ends before all prompts are answered.
+var errOnboardingCancelled = fmt.Errorf("onboarding cancelled: %w", context.Canceled)
+
+// detectedAgent is an agent found on this machine or in the project, with the directory proposed for it.
+type detectedAgent struct {
+	names []string // Agents sharing the project-level directory
+	dir   string
+}
+
+// OnboardCmd runs when skills-pkg is started without a command.
+// On the first run in a project, it walks the user through creating .skillspkg.toml
+// instead of printing a bare usage error.
+type OnboardCmd struct {
+	allowRoot      bool   // Set from the global --allow-root flag
+	downloadCache  bool   // Set by Run to reuse downloads from the user cache directory
+	userConfigPath string // Set by Run to the user-level configuration file that may name a bootstrap skill
+}
+
+// Run starts the guided onboarding when there is no configuration file and the user can answer
+// prompts. Otherwise, it reports the missing command with the usage, as kong would.
+func (c *OnboardCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	// Scripts and CI jobs get the usage error, as they cannot answer the prompts
+	if _, err := os.Stat(defaultConfigPath); !errors.Is(err, os.ErrNotExist) || !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		ctx.FatalIfErrorf(errors.New("expected a command"))
+		return nil
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+	if dirs, err := resolveUserDirs(NewLogger(verbose)); err == nil {
+		c.userConfigPath = dirs.ConfigFile()
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *OnboardCmd) run(configPath string, verbose bool) error {
+	// Create default dependencies
+	hashService := service.NewDirhash()
+	packageManagers := pkgmanager.All()
+
+	return c.runWithDeps(configPath, os.Stdin, NewLogger(verbose), agent.All(), hashService, packageManagers)
+}
+
+// runWithDeps is the internal implementation with injectable dependencies for testing.
+// It reads the answers to the prompts from in, and prints the prompts with logger.
+func (c *OnboardCmd) runWithDeps(configPath string, in io.Reader, logger *Logger, providers []port.AgentProvider, hashService port.HashService, packageManagers []port.PackageManager) error {
+	answers := bufio.NewScanner(in)
+	// ask prints a prompt and returns the answer, or ok=false when the input ends, as on Ctrl+D
+	ask := func(prompt string) (answer string, ok bool) {
+		_, _ = fmt.Fprint(logger.out, prompt)
+		if !answers.Scan() {
+			_, _ = fmt.Fprintln(logger.out)
+			logger.Info("Onboarding cancelled. Nothing was changed")
+			return "", false
+		}
+		return strings.TrimSpace(answers.Text()), true
+	}
+
+	logger.Info("Welcome to skills-pkg! No %s was found, so let's create one.", filepath.Base(configPath))
+	logger.Info("Press Ctrl+C at any time to quit without changing anything, or run 'skills-pkg --help' for all commands.")
+	logger.Info("")
+
+	// Propose the project-level directories of the agents in use
+	var installTargets []string
+	if detected := detectAgents(providers); len(detected) > 0 {
+		logger.Info("Detected agents:")
+		for _, d := range detected {
+			logger.Info("  - %s: %s", strings.Join(d.names, ", "), d.dir)
+			installTargets = append(installTargets, d.dir)
+		}
+		answer, ok := ask("Install skills into these directories? [Y/n] ")
+		if !ok {
+			return errOnboardingCancelled
+		}
+		if !isYes(answer, true) {
+			installTargets = nil
+		}
+	} else {
+		logger.Info("No agents were detected.")
+	}
+	if len(installTargets) == 0 {
+		answer, ok := ask("Install directories, separated by commas [./.skills]: ")
+		if !ok {
+			return errOnboardingCancelled
+		}
+		for dir := range strings.SplitSeq(answer, ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				installTargets = append(installTargets, dir)
+			}
+		}
+		if len(installTargets) == 0 {
+			installTargets = []string{"./.skills"}
+		}
+	}
+	logger.Verbose("Install targets: %v", installTargets)
+	logger.Info("")
+
+	// Suggest the curated bundles of 'init --bundle' in addition to managing-skills, which teaches
+	// agents to use skills-pkg, or the bootstrap skill that replaces it
+	bootstrap, err := defaultSkill(c.userConfigPath)
+	if err != nil {
+		logger.Error("Failed to read the bootstrap skill: %v", err)
+		return err
+	}
+	bundles, err := loadBundles()
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	logger.Info("Starter bundles (%s is always installed):", bootstrap.Name)
+	for i, bundle := range bundles {
+		names := make([]string, 0, len(bundle.Skills))
+		for _, skill := range bundle.Skills {
+			names = append(names, skill.Name)
+		}
+		logger.Info("  %d. %s: %s (%s)", i+1, bundle.Name, bundle.Description, strings.Join(names, ", "))
+	}
+	answer, ok := ask("Bundles to install, as numbers separated by spaces or commas [none]: ")
+	if !ok {
+		return errOnboardingCancelled
+	}
+	selected, err := parseSelection(answer, len(bundles))
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	logger.Info("")
+
+	names := make([]string, 0, len(selected))
+	for _, i := range selected {
+		names = append(names, bundles[i].Name)
+	}
+	starters, err := bundleSkills(names)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	skills := append([]*domain.Skill{bootstrap}, starters...)
+
+	// Write the configuration, removing it again if any skill cannot be installed
+	// so that onboarding or init can be run again from scratch.
+	configManager := newConfigManager(configPath)
+	if err := configManager.Initialize(context.Background(), installTargets); err != nil {
+		logger.Error("Failed to create configuration file: %v", err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+
+	config, err := configManager.Load(context.Background())
+	if err != nil {
+		rollback(logger, configPath)
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	config.Skills = append(config.Skills, skills...)
+	if err := config.Validate(); err != nil {
+		rollback(logger, configPath)
+		logger.Error("Failed to add skills to configuration: %v", err)
+		return err
+	}
+
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, skillManagerOptions(c.allowRoot, c.downloadCache)...)
+	for _, skill := range skills {
+		logger.Info("Installing %s...", skill.Name)
+		// Use saveConfig=false so the config is only persisted after every skill is installed.
+		if err := skillManager.InstallSingleSkill(context.Background(), config, skill, false); err != nil {
+			rollback(logger, configPath)
+			logger.Error("Failed to install %s: %v", skill.Name, err)
+			handlePermissionError(logger, err)
+			return fmt.Errorf("%s installation failed: %w", skill.Name, err)
+		}
+	}
+
+	if err := configManager.Save(context.Background(), config); err != nil {
+		rollback(logger, configPath)
+		logger.Error("Failed to save configuration: %v", err)
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	logger.Info("")
+	logger.Info("Successfully created %s", configPath)
+	logger.Info("Install targets:")
+	for _, target := range installTargets {
+		logger.Info("  - %s", target)
+	}
+	logger.Info("Add more skills with 'skills-pkg search' and 'skills-pkg add', and commit %s to share them", filepath.Base(configPath))
+
+	return nil
+}
+
+// detectAgents returns the agents that appear to be in use, either because their user-level
+// directory exists or because the project already has their configuration directory.
+// Agents sharing a project-level directory are reported together.
+func detectAgents(providers []port.AgentProvider) []*detectedAgent {
+	var detected []*detectedAgent
+	for _, provider := range providers {
+		name := provider.AgentName()
+		dir := provider.ProjectDir()
+
+		// Agents whose skills directory is directly in the project, like "skills", cannot be detected from it
+		inUse := filepath.Dir(dir) != "." && dirExists(filepath.Dir(dir))
+		if userDir, err := provider.ResolveAgentDir(name); err == nil && dirExists(filepath.Dir(userDir)) {
+			inUse = true
+		}
+		if !inUse {
+			continue
+		}
+
+		i := slices.IndexFunc(detected, func(d *detectedAgent) bool { return filepath.Clean(d.dir) == filepath.Clean(dir) })
+		if i < 0 {
+			detected = append(detected, &detectedAgent{dir: dir})
+			i = len(detected) - 1
+		}
+		detected[i].names = append(detected[i].names, name)
+	}
+
+	return detected
+}
+
+// isYes reports whether answer to a yes/no prompt is yes, or returns def for an empty answer.
+func isYes(answer string, def bool) bool {
+	switch strings.ToLower(answer) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseSelection parses 1-based numbers separated by spaces or commas into 0-based indexes below n,
+// in the order given and without duplicates.
+func parseSelection(answer string, n int) ([]int, error) {
+	var selected []int
+	for field := range strings.FieldsFuncSeq(answer, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > n {
+			return nil, fmt.Errorf("invalid selection '%s': enter numbers from 1 to %d", field, n)
+		}
+		if !slices.Contains(selected, number-1) {
+			selected = append(selected, number-1)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestOnboardCmd_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		downloadErr error
+		wantSkills  []string
+		wantTargets func(installDir string) []string
+		wantErr     bool
+	}{
+		{
+			name:        "custom directory and starter bundles",
+			input:       "{dir}\n1, 3\n",
+			wantSkills:  []string{managingSkillsName, "frontend-design", "webapp-testing", "skill-creator", "mcp-builder"},
+			wantTargets: func(installDir string) []string { return []string{installDir} },
+		},
+		{
+			name:        "several directories without starter bundles",
+			input:       "{dir}, {dir}2\n\n",
+			wantSkills:  []string{managingSkillsName},
+			wantTargets: func(installDir string) []string { return []string{installDir, installDir + "2"} },
+		},
+		{
+			name:    "invalid selection",
+			input:   "{dir}\n9\n",
+			wantErr: true,
+		},
+		{
+			name:    "input ends before all prompts are answered",
+			input:   "{dir}\n",
+			wantErr: true,
+		},
+		{
+			name:        "installation failure rolls back the configuration",
+			input:       "{dir}\n\n",
+			downloadErr: fmt.Errorf("network error"),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			installDir := filepath.Join(tmpDir, "install")
+
+			mockDownloadDir := t.TempDir()
+			for _, subDir := range []string{managingSkillsSubDir, "skills/frontend-design", "skills/webapp-testing", "skills/skill-creator", "skills/mcp-builder"} {
+				if err := os.MkdirAll(filepath.Join(mockDownloadDir, subDir), 0o755); err != nil {
+					t.Fatalf("failed to create mock skill directory: %v", err)
+				}
+			}
+			packageManagers := []port.PackageManager{
+				&mockPackageManagerWithOptions{sourceType: "git", tmpDir: mockDownloadDir, downloadErr: tt.downloadErr},
+				&mockPackageManagerWithOptions{sourceType: "go-mod", tmpDir: mockDownloadDir, downloadErr: tt.downloadErr},
+			}
+
+			var out bytes.Buffer
+			logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+			input := strings.NewReader(strings.ReplaceAll(tt.input, "{dir}", installDir))
+
+			// No agents are detected, so the install directories are asked for
+			cmd := &OnboardCmd{}
+			err := cmd.runWithDeps(configPath, input, logger, nil, &mockHashService{}, packageManagers)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if _, statErr := os.Stat(configPath); !os.IsNotExist(statErr) {
+					t.Errorf("config file should not exist after a failed onboarding, but it exists at %s", configPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v\noutput:\n%s", err, out.String())
+			}
+
+			config, err := domain.NewConfigManager(configPath).Load(context.Background())
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			if want := tt.wantTargets(installDir); !slices.Equal(config.InstallTargets, want) {
+				t.Errorf("install targets = %v, want %v", config.InstallTargets, want)
+			}
+			var names []string
+			for _, skill := range config.Skills {
+				names = append(names, skill.Name)
+			}
+			if !slices.Equal(names, tt.wantSkills) {
+				t.Errorf("skills = %v, want %v", names, tt.wantSkills)
+			}
+		})
+	}
+}
+
+func TestOnboardCmd_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+
+	err := (&OnboardCmd{}).runWithDeps(configPath, strings.NewReader(""), logger, nil, &mockHashService{}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if got := domain.CodeOf(err); got != domain.CodeInterrupted {
+		t.Errorf("error code = %v, want %s", got, domain.CodeInterrupted.Code)
+	}
+}
+
+func TestDetectAgents(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".fake"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	missing := t.TempDir()
+
+	// Agents sharing a project-level directory are reported together, and agents
+	// without a user-level directory are skipped.
+	detected := detectAgents([]port.AgentProvider{&fakeAgent{home: home}, &fakeAgent{home: home}, &fakeAgent{home: missing + "/none"}})
+	if len(detected) != 1 {
+		t.Fatalf("detected %d agents, want 1", len(detected))
+	}
+	if detected[0].dir != ".fake/skills" || !slices.Equal(detected[0].names, []string{"fake", "fake"}) {
+		t.Errorf("detected = %+v, want fake, fake in .fake/skills", detected[0])
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		answer  string
+		want    []int
+		wantErr bool
+	}{
+		{answer: "", want: nil},
+		{answer: "2", want: []int{1}},
+		{answer: "3, 1 3", want: []int{2, 0}},
+		{answer: "0", wantErr: true},
+		{answer: "5", wantErr: true},
+		{answer: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSelection(tt.answer, 4)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSelection(%q) error = %v, wantErr %v", tt.answer, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/opener"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// OpenCmd represents the open command
+type OpenCmd struct {
+	Skill  string `arg:"" help:"Name of the skill to open"`
+	Target string `help:"Install target to open the skill in (defaults to the first local install target containing it)"`
+	Web    bool   `help:"Open the skill's upstream repository in the web browser instead" default:"false"`
+	Print  bool   `help:"Print the location instead of opening it" default:"false"`
+}
+
+// Run executes the open command
+func (c *OpenCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *OpenCmd) run(configPath string, verbose bool) error {
+	return c.runWithOpener(configPath, NewLogger(verbose), opener.NewDesktop())
+}
+
+// runWithOpener opens the skill with the given opener (for testing)
+func (c *OpenCmd) runWithOpener(configPath string, logger *Logger, o port.Opener) error {
+	config, err := newConfigManager(configPath).Load(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	skill := config.FindInstalledSkill(c.Skill)
+	if skill == nil {
+		err := &domain.ErrorSkillsNotFound{SkillNames: []string{c.Skill}}
+		logger.Error("Skill '%s' not found in configuration", c.Skill)
+		logger.Error("Run 'skills-pkg list' to see the configured skills")
+		return err
+	}
+
+	var location string
+	if c.Web {
+		location, err = skillWebURL(skill)
+	} else {
+		location, err = installedSkillDir(config, skill, c.Target)
+	}
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	if c.Print {
+		_, err := fmt.Fprintln(logger.dataOut, location)
+		return err
+	}
+
+	logger.Info("Opening %s", location)
+	if err := o.Open(context.Background(), location); err != nil {
+		logger.Error("%v", err)
+		logger.Error("Use --print to show the location without opening it")
+		return err
+	}
+
+	return nil
+}
+
+// installedSkillDir returns the absolute directory the skill is installed in, within the given
+// install target, or within the first local install target containing it when target is empty.
+func installedSkillDir(config *domain.Config, skill *domain.Skill, target string) (string, error) {
+	targets := config.LocalInstallTargets()
+	if target != "" {
+		if !slices.Contains(config.InstallTargets, target) {
+			return "", fmt.Errorf("install target %s is not in the configuration. Configured targets: %s", target, strings.Join(config.InstallTargets, ", "))
+		}
+		if domain.IsRemoteTarget(target) {
+			return "", fmt.Errorf("install target %s is on another machine and cannot be accessed locally", target)
+		}
+		targets = []string{target}
+	}
+
+	for _, t := range targets {
+		dir, err := filepath.Abs(filepath.Join(t, skill.DirName()))
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", t, err)
+		}
+		if dirExists(dir) {
+			return dir, nil
+		}
+	}
+
+	if target != "" {
+		return "", fmt.Errorf("skill '%s' is not installed in %s. Run 'skills-pkg install %s' first", skill.Name, target, skill.Name)
+	}
+	return "", fmt.Errorf("skill '%s' is not installed in any local install target. Run 'skills-pkg install %s' first", skill.Name, skill.Name)
+}
+
+// skillWebURL returns the web page of the skill's upstream source.
+// For GitHub and GitLab repositories, it links to the skill's subdirectory at its version.
+func skillWebURL(skill *domain.Skill) (string, error) {
+	switch skill.Source {
+	case "go-mod":
+		page := "https://pkg.go.dev/" + skill.URL
+		if skill.Version != "" {
+			page += "@" + skill.Version
+		}
+		return page, nil
+	case "npm":
+		page := "https://www.npmjs.com/package/" + skill.URL
+		if skill.Version != "" {
+			page += "/v/" + skill.Version
+		}
+		return page, nil
+	case "git":
+		repo, host, ok := repoWebURL(skill.URL)
+		if !ok {
+			return "", fmt.Errorf("cannot determine a web page for repository %s", skill.URL)
+		}
+		if skill.SubDir == "" {
+			return repo, nil
+		}
+
+		ref := skill.Version
+		if ref == "" {
+			ref = "HEAD"
+		}
+		switch host {
+		case "github.com":
+			return repo + "/tree/" + ref + "/" + strings.Trim(skill.SubDir, "/"), nil
+		case "gitlab.com":
+			return repo + "/-/tree/" + ref + "/" + strings.Trim(skill.SubDir, "/"), nil
+		}
+		return repo, nil
+	default:
+		return "", fmt.Errorf("cannot determine a web page for skills from source '%s'", skill.Source)
+	}
+}
+
+// repoWebURL converts a Git remote URL (http(s), ssh://, or scp-like git@host:path) to
+// the repository's web URL, and returns the host name.
+func repoWebURL(remote string) (string, string, bool) {
+	scheme, host, repoPath := "https", "", ""
+	if user, rest, ok := strings.Cut(remote, "@"); ok && !strings.Contains(user, "/") && !strings.Contains(remote, "://") {
+		// scp-like syntax: git@github.com:owner/repo.git
+		host, repoPath, ok = strings.Cut(rest, ":")
+		if !ok {
+			return "", "", false
+		}
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil || u.Host == "" {
+			return "", "", false
+		}
+		switch u.Scheme {
+		case "http":
+			scheme = "http"
+		case "https", "ssh", "git":
+		default:
+			return "", "", false
+		}
+		host, repoPath = u.Hostname(), u.Path
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return "", "", false
+	}
+	return scheme + "://" + host + "/" + repoPath, host, true
+}
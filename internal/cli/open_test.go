@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// recordingOpener records the locations it is asked to open.
+type recordingOpener struct {
+	opened []string
+}
+
+func (o *recordingOpener) Open(ctx context.Context, location string) error {
+	o.opened = append(o.opened, location)
+	return nil
+}
+
+func TestOpenCmd_Run(t *testing.T) {
+	tests := []struct {
+		cmd          *OpenCmd
+		name         string
+		wantLocation string // Relative to the project directory unless it is a URL
+		wantPrinted  bool
+		wantErr      bool
+	}{
+		{
+			name:         "opens the first target containing the skill",
+			cmd:          &OpenCmd{Skill: "my-skill"},
+			wantLocation: "second/my-skill",
+		},
+		{
+			name:    "selected target without the skill",
+			cmd:     &OpenCmd{Skill: "my-skill", Target: "first"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown target",
+			cmd:     &OpenCmd{Skill: "my-skill", Target: "elsewhere"},
+			wantErr: true,
+		},
+		{
+			name:         "web page of the upstream repository",
+			cmd:          &OpenCmd{Skill: "my-skill", Web: true},
+			wantLocation: "https://github.com/example/skills/tree/v1.0.0/skills/my-skill",
+		},
+		{
+			name:         "print instead of opening",
+			cmd:          &OpenCmd{Skill: "my-skill", Print: true},
+			wantLocation: "second/my-skill",
+			wantPrinted:  true,
+		},
+		{
+			name:    "unknown skill",
+			cmd:     &OpenCmd{Skill: "missing"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			configPath := filepath.Join(projectDir, ".skillspkg.toml")
+			first := filepath.Join(projectDir, "first")
+			second := filepath.Join(projectDir, "second")
+
+			config := &domain.Config{
+				Skills: []*domain.Skill{{
+					Name:    "my-skill",
+					Source:  "git",
+					URL:     "git@github.com:example/skills.git",
+					Version: "v1.0.0",
+					SubDir:  "skills/my-skill",
+				}},
+				InstallTargets: []string{first, second, "ssh://host/skills"},
+			}
+			if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+			if err := os.MkdirAll(filepath.Join(second, "my-skill"), 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+
+			cmd := tt.cmd
+			if cmd.Target != "" && cmd.Target != "elsewhere" {
+				cmd.Target = filepath.Join(projectDir, cmd.Target)
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &errOut, dataOut: &out, errOut: &errOut}
+			o := &recordingOpener{}
+
+			err := cmd.runWithOpener(configPath, logger, o)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithOpener() error = %v, wantErr %v\nstderr: %s", err, tt.wantErr, errOut.String())
+			}
+			if tt.wantErr {
+				if len(o.opened) != 0 {
+					t.Errorf("nothing should be opened on error, opened %v", o.opened)
+				}
+				return
+			}
+
+			want := tt.wantLocation
+			if !strings.Contains(want, "://") {
+				want = filepath.Join(projectDir, want)
+			}
+
+			if tt.wantPrinted {
+				if got := strings.TrimSpace(out.String()); got != want {
+					t.Errorf("printed %q, want %q", got, want)
+				}
+				if len(o.opened) != 0 {
+					t.Errorf("--print should not open anything, opened %v", o.opened)
+				}
+				return
+			}
+			if len(o.opened) != 1 || o.opened[0] != want {
+				t.Errorf("opened %v, want [%s]", o.opened, want)
+			}
+		})
+	}
+}
+
+func TestSkillWebURL(t *testing.T) {
+	tests := []struct {
+		skill   *domain.Skill
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "https repository without subdirectory",
+			skill: &domain.Skill{Source: "git", URL: "https://github.com/example/skill.git"},
+			want:  "https://github.com/example/skill",
+		},
+		{
+			name:  "GitLab subdirectory at default branch",
+			skill: &domain.Skill{Source: "git", URL: "ssh://git@gitlab.com/group/skills.git", SubDir: "/my-skill/"},
+			want:  "https://gitlab.com/group/skills/-/tree/HEAD/my-skill",
+		},
+		{
+			name:  "other host links to the repository",
+			skill: &domain.Skill{Source: "git", URL: "git@git.example.com:team/skills.git", SubDir: "my-skill", Version: "v1.0.0"},
+			want:  "https://git.example.com/team/skills",
+		},
+		{
+			name:  "go module",
+			skill: &domain.Skill{Source: "go-mod", URL: "github.com/example/skills", Version: "v1.2.0"},
+			want:  "https://pkg.go.dev/github.com/example/skills@v1.2.0",
+		},
+		{
+			name:    "local path repository",
+			skill:   &domain.Skill{Source: "git", URL: "/srv/git/skills.git"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := skillWebURL(tt.skill)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("skillWebURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("skillWebURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// OrgCmd represents the org command group
+type OrgCmd struct {
+	Sync OrgSyncCmd `cmd:"" help:"Add the skills the organization policy requires and remove skills from the sources it bans"`
+}
+
+// OrgSyncCmd represents the org sync command
+type OrgSyncCmd struct {
+	Policy string `arg:"" optional:"" help:"Organization policy: a local file, or <repository>@<version> for its skillspkg-org.toml. Recorded as 'org' in .skillspkg.toml; defaults to the recorded policy"`
+	Check  bool   `help:"Report whether the configuration complies with the policy without changing it, exiting with an error if it does not"`
+}
+
+// Run executes the org sync command
+func (c *OrgSyncCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.Policy = projectFile(c.Policy)
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger applies the organization policy to the configuration at configPath (for testing)
+func (c *OrgSyncCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger) error {
+	configManager := newConfigManager(configPath)
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	ref := c.Policy
+	if ref == "" {
+		ref = config.Org
+	}
+	if ref == "" {
+		err := &domain.ErrorInvalidOrgPolicy{Ref: "", Reason: "no policy is given and .skillspkg.toml has no 'org' setting"}
+		logger.Error("%v", err)
+		return err
+	}
+
+	logger.Verbose("Loading organization policy %s", ref)
+	policy, err := configManager.LoadOrgPolicy(ctx, ref)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	result, err := policy.Apply(config)
+	if err != nil {
+		logger.Error("Failed to apply organization policy: %v", err)
+		return err
+	}
+
+	if c.Check {
+		if !result.Changed() {
+			logger.Info("Configuration complies with organization policy %s", ref)
+			return nil
+		}
+		err := &domain.ErrorOrgNonCompliant{Missing: slices.Concat(result.Added, result.Replaced)}
+		for _, banned := range result.Removed {
+			err.Banned = append(err.Banned, banned.Name)
+		}
+		logger.Error("%v", err)
+		return err
+	}
+
+	config.Org = ref
+	if err := configManager.Save(ctx, config); err != nil {
+		logger.Error("Failed to save configuration: %v", err)
+		return err
+	}
+
+	for _, banned := range result.Removed {
+		logger.Info("Removed skill '%s': %s is banned by %s", banned.Name, banned.URL, banned.Pattern)
+	}
+	for _, name := range result.Replaced {
+		logger.Info("Replaced skill '%s' with the required skill from the organization policy", name)
+	}
+	for _, name := range result.Added {
+		logger.Info("Added required skill '%s'", name)
+	}
+	if !result.Changed() {
+		logger.Info("Configuration already complies with organization policy %s", ref)
+		return nil
+	}
+	logger.Info("Run 'skills-pkg sync' to install the required skills and remove the banned ones from the install targets")
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestOrgSync(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".skillspkg.toml")
+	if err := domain.NewConfigManager(configPath).Save(ctx, &domain.Config{
+		Skills: []*domain.Skill{
+			{Name: "own", Source: "git", URL: "https://github.com/team/own.git"},
+			{Name: "banned", Source: "npm", URL: "some-skill"},
+		},
+		InstallTargets: []string{".claude/skills"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	policy := `banned_sources = ["npm"]
+
+[[skills]]
+name = "security-review"
+source = "git"
+url = "https://github.com/org/skills.git"
+`
+	if err := os.WriteFile(filepath.Join(dir, "org.toml"), []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+
+	// --check reports the violations without changing the configuration
+	err := (&OrgSyncCmd{Policy: "./org.toml", Check: true}).runWithLogger(ctx, configPath, logger)
+	if nonCompliant, ok := errors.AsType[*domain.ErrorOrgNonCompliant](err); !ok || len(nonCompliant.Missing) != 1 || len(nonCompliant.Banned) != 1 {
+		t.Fatalf("org sync --check error = %v, want ErrorOrgNonCompliant with one missing and one banned skill", err)
+	}
+
+	if err := (&OrgSyncCmd{Policy: "./org.toml"}).runWithLogger(ctx, configPath, logger); err != nil {
+		t.Fatalf("org sync error = %v\noutput: %s", err, out.String())
+	}
+	config, err := domain.NewConfigManager(configPath).Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.FindSkillByName("banned") != nil || config.FindSkillByName("security-review") == nil || config.FindSkillByName("own") == nil {
+		t.Errorf("skills after org sync = %+v", config.Skills)
+	}
+	if config.Org != "./org.toml" {
+		t.Errorf("org = %q, want the policy to be recorded", config.Org)
+	}
+
+	// The recorded policy is used without an argument
+	if err := (&OrgSyncCmd{Check: true}).runWithLogger(ctx, configPath, logger); err != nil {
+		t.Errorf("org sync --check after sync error = %v", err)
+	}
+}
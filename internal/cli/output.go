@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+)
+
+// outputFlag reads the global --output flag from the parsed CLI model.
+func outputFlag(ctx *kong.Context) string {
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		if field := model.Target.FieldByName("Output"); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return field.String()
+		}
+	}
+	return "text"
+}
+
+// writeJSON writes v to standard output as indented JSON.
+func writeJSON(logger *Logger, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON output: %w", err)
+	}
+	if _, err := fmt.Fprintln(logger.dataOut, string(data)); err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return nil
+}
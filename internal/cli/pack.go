@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// PackCmd represents the pack command
+type PackCmd struct {
+	SkillName    string `arg:"" help:"Name of the installed skill to pack"`
+	Output       string `name:"out" short:"o" type:"path" help:"Path of the archive to write (default: <skill>-<version>.tar.gz)"`
+	Reproducible bool   `help:"Produce a byte-identical archive for identical skill contents (fixed timestamps, sorted entries, normalized modes)"`
+}
+
+// Run executes the pack command
+func (c *PackCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+// This method archives the installed copy of a skill from the first install target.
+func (c *PackCmd) run(configPath string, verbose bool) error {
+	logger := NewLogger(verbose)
+
+	logger.Info("Packing skill '%s'", c.SkillName)
+	logger.Verbose("Loading configuration from %s", configPath)
+
+	configManager := newConfigManager(configPath)
+	config, err := configManager.Load(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	skill := config.FindInstalledSkill(c.SkillName)
+	if skill == nil {
+		err := &domain.ErrorSkillsNotFound{SkillNames: []string{c.SkillName}}
+		logger.Error("Skill '%s' not found in configuration", c.SkillName)
+		logger.Error("Use 'skills-pkg list' to see available skills")
+		return err
+	}
+
+	localTargets := config.LocalInstallTargets()
+	if len(localTargets) == 0 {
+		err := fmt.Errorf("no local install targets configured")
+		logger.Error("No local install targets configured")
+		logger.Error("Use 'skills-pkg add-install-target <path>' to add one")
+		return err
+	}
+
+	skillDir := filepath.Join(localTargets[0], skill.DirName())
+	if _, err := os.Stat(skillDir); err != nil {
+		logger.Error("Skill '%s' is not installed in %s", skill.Name, localTargets[0])
+		logger.Error("Run 'skills-pkg install %s' first", skill.Name)
+		return err
+	}
+
+	output := c.Output
+	if output == "" {
+		output = skill.Name + ".tar.gz"
+		if skill.Version != "" {
+			output = fmt.Sprintf("%s-%s.tar.gz", skill.Name, skill.Version)
+		}
+	}
+
+	logger.Verbose("Writing archive of %s to %s (reproducible: %v)", skillDir, output, c.Reproducible)
+	if err := writeSkillArchive(output, skillDir, skill.Name, c.Reproducible, config.GeneratedFiles()); err != nil {
+		logger.Error("Failed to pack skill '%s': %v", skill.Name, err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+
+	logger.Info("Successfully packed skill '%s' into %s", skill.Name, output)
+
+	return nil
+}
+
+// writeSkillArchive creates the archive file, removing it if packing fails.
+func writeSkillArchive(output, skillDir, skillName string, reproducible bool, exclude []string) (err error) {
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create archive %s: %w", output, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close archive %s: %w", output, closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(output)
+		}
+	}()
+
+	return domain.PackSkill(f, skillDir, domain.PackOptions{
+		Prefix:       skillName,
+		Reproducible: reproducible,
+		Exclude:      exclude,
+	})
+}
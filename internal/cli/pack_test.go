@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestPackCmd_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantErrCheck func(error) bool
+		setupFunc    func(t *testing.T) (configPath string)
+		name         string
+		skillName    string
+		wantErr      bool
+	}{
+		{
+			name:      "success: installed skill is packed",
+			skillName: "test-skill",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, ".skillspkg.toml")
+				installDir := filepath.Join(tempDir, "skills")
+
+				configManager := domain.NewConfigManager(configPath)
+				if err := configManager.Initialize(context.Background(), []string{installDir}); err != nil {
+					t.Fatalf("failed to initialize config: %v", err)
+				}
+				skill := &domain.Skill{
+					Name:    "test-skill",
+					Source:  "git",
+					URL:     "https://example.com/test.git",
+					Version: "v1.0.0",
+				}
+				if err := configManager.AddSkill(context.Background(), skill); err != nil {
+					t.Fatalf("failed to add test skill: %v", err)
+				}
+
+				skillDir := filepath.Join(installDir, "test-skill")
+				if err := os.MkdirAll(skillDir, 0o755); err != nil {
+					t.Fatalf("failed to create skill directory: %v", err)
+				}
+				if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Test"), 0o644); err != nil {
+					t.Fatalf("failed to write test file: %v", err)
+				}
+
+				return configPath
+			},
+			wantErr: false,
+		},
+		{
+			name:      "error: skill not in configuration",
+			skillName: "missing-skill",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, ".skillspkg.toml")
+
+				configManager := domain.NewConfigManager(configPath)
+				if err := configManager.Initialize(context.Background(), []string{filepath.Join(tempDir, "skills")}); err != nil {
+					t.Fatalf("failed to initialize config: %v", err)
+				}
+
+				return configPath
+			},
+			wantErr: true,
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorSkillsNotFound](err)
+				return ok
+			},
+		},
+		{
+			name:      "error: configuration file not found",
+			skillName: "test-skill",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return filepath.Join(t.TempDir(), ".skillspkg.toml")
+			},
+			wantErr: true,
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorConfigNotFound](err)
+				return ok
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := tt.setupFunc(t)
+			output := filepath.Join(filepath.Dir(configPath), "out.tar.gz")
+
+			cmd := &PackCmd{
+				SkillName:    tt.skillName,
+				Output:       output,
+				Reproducible: true,
+			}
+			err := cmd.run(configPath, false)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrCheck != nil && !tt.wantErrCheck(err) {
+				t.Errorf("run() error check failed, got %v", err)
+			}
+
+			_, statErr := os.Stat(output)
+			if tt.wantErr && statErr == nil {
+				t.Errorf("archive %s should not exist after a failure", output)
+			}
+			if !tt.wantErr && statErr != nil {
+				t.Errorf("archive %s was not created: %v", output, statErr)
+			}
+		})
+	}
+}
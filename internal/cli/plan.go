@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// PlanCmd represents the plan command
+type PlanCmd struct {
+	Out string `short:"o" type:"path" help:"Write the plan to a file that 'skills-pkg apply' executes"`
+}
+
+// Run executes the plan command
+func (c *PlanCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *PlanCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger computes the pending changes and prints them (for testing)
+func (c *PlanCmd) runWithLogger(configPath string, logger *Logger) error {
+	logger.Verbose("Comparing %s with the install targets", configPath)
+
+	plan, err := domain.NewPlanner(newConfigManager(configPath), service.NewDirhash()).Plan(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to compute the plan: %v", err)
+		return err
+	}
+
+	if _, err := fmt.Fprint(logger.dataOut, formatPlan(plan)); err != nil {
+		return err
+	}
+
+	if c.Out == "" {
+		return nil
+	}
+	if err := domain.WritePlan(c.Out, plan); err != nil {
+		logger.Error("Failed to save the plan: %v", err)
+		return err
+	}
+	logger.Info("Saved the plan to %s", c.Out)
+	logger.Info("Run 'skills-pkg apply %s' to execute it", c.Out)
+
+	return nil
+}
+
+// formatPlan returns a human-readable description of the plan. Installs are marked with "+",
+// updates with "~", repairs with "!", and removals with "-".
+func formatPlan(plan *domain.Plan) string {
+	if len(plan.Changes) == 0 {
+		return "No changes. The install targets match the configuration.\n"
+	}
+
+	var b strings.Builder
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case domain.PlanInstall:
+			fmt.Fprintf(&b, "  + %s in %s (%s)\n", change.Skill, change.Target, planVersion(change.ToVersion))
+		case domain.PlanUpdate:
+			fmt.Fprintf(&b, "  ~ %s in %s (%s -> %s)\n", change.Skill, change.Target, planVersion(change.FromVersion), planVersion(change.ToVersion))
+		case domain.PlanRepair:
+			fmt.Fprintf(&b, "  ! %s in %s (modified files)\n", change.Skill, change.Target)
+		case domain.PlanRemove:
+			fmt.Fprintf(&b, "  - %s in %s\n", change.Skill, change.Target)
+		}
+	}
+	fmt.Fprintf(&b, "Plan: %d to install, %d to update, %d to repair, %d to remove\n",
+		plan.Count(domain.PlanInstall), plan.Count(domain.PlanUpdate), plan.Count(domain.PlanRepair), plan.Count(domain.PlanRemove))
+
+	return b.String()
+}
+
+// planVersion returns the version for display, naming versions resolved at installation.
+func planVersion(version string) string {
+	if version == "" {
+		return "unpinned"
+	}
+	return version
+}
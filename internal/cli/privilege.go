@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/agent"
+	"github.com/mazrean/skills-pkg/internal/adapter/policy"
+	"github.com/mazrean/skills-pkg/internal/adapter/remote"
+	"github.com/mazrean/skills-pkg/internal/adapter/scanner"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// allowRootFlag reads the global --allow-root flag from the parsed CLI model.
+func allowRootFlag(ctx *kong.Context) bool {
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		if field := model.Target.FieldByName("AllowRoot"); field.IsValid() && field.Kind() == reflect.Bool {
+			return field.Bool()
+		}
+	}
+	return false
+}
+
+// skillManagerOptions returns the SkillManager options shared by the commands that install skills.
+// Downloads are shared through the user cache directory, and replaced skills backed up in the user
+// state directory, only when downloadCache is set, which commands do outside of tests.
+func skillManagerOptions(allowRoot, downloadCache bool) []domain.SkillManagerOption {
+	opts := []domain.SkillManagerOption{
+		domain.WithLogger(progressLogger()),
+		domain.WithRemoteInstallers(remote.NewSFTP()),
+		domain.WithContentScanner(scanner.NewCommand()),
+		domain.WithTargetAgents(targetAgents(agent.All())),
+	}
+	// The CEL environment has no custom declarations, so creating it only fails on programming errors
+	if engine, err := policy.NewCEL(); err == nil {
+		opts = append(opts, domain.WithPolicyEngine(engine))
+	}
+	if allowRoot {
+		opts = append(opts, domain.WithAllowRoot())
+	}
+	if mirror := os.Getenv(mirrorEnv); mirror != "" {
+		opts = append(opts, domain.WithMirror(mirror))
+	}
+	if downloadCache {
+		// Without a cache directory, downloads are still shared within the command
+		if dirs, err := domain.ResolveUserDirs(); err == nil {
+			opts = append(opts, domain.WithDownloadCache(domain.NewDownloadCache(dirs.DownloadCacheDir())))
+		}
+		opts = append(opts, backupOptions()...)
+	}
+	return opts
+}
+
+// backupOptions returns the option that backs up replaced and removed skills, or none when
+// userBackups returns nil and skills are deleted as before.
+func backupOptions() []domain.SkillManagerOption {
+	backups := userBackups()
+	if backups == nil {
+		return nil
+	}
+	return []domain.SkillManagerOption{domain.WithBackups(backups)}
+}
+
+// userBackups returns the backups in the user state directory, configured by the [backups] section of
+// the user-level configuration. It returns nil when backups are disabled or the directories cannot be resolved.
+func userBackups() *domain.Backups {
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		return nil
+	}
+	userConfig, err := domain.LoadUserConfig(dirs.ConfigFile())
+	if err != nil || !userConfig.BackupsEnabled() {
+		return nil
+	}
+	return domain.NewBackups(dirs.BackupDir(), userConfig.BackupKeep(), userConfig.BackupMaxAge())
+}
+
+// handlePermissionError reports an install target the current user cannot write to
+// and suggests re-running the command with elevated privileges.
+// It returns false when err is not a permission error so callers can fall through.
+func handlePermissionError(logger *Logger, err error) bool {
+	// Running as root (e.g., via sudo) against a target owned by a regular user
+	if e, ok := errors.AsType[*domain.ErrorRootWriteToUserTarget](err); ok {
+		logger.Error("Refusing to write to install target %s as root because it is owned by another user (uid %d)", e.Target, e.OwnerUID)
+		logger.Error("Re-run the command without sudo, or pass --allow-root to install anyway; installed files are then given to the target's owner")
+		return true
+	}
+
+	if e, ok := errors.AsType[*domain.ErrorTargetNotWritable](err); ok {
+		logger.Error("Permission denied writing to install target %s", e.Target)
+	} else if errors.Is(err, fs.ErrPermission) {
+		logger.Error("Permission denied: %v", err)
+	} else {
+		return false
+	}
+
+	logger.Error("The target may be a system-wide directory owned by another user")
+	logger.Error("%s", escalationHint())
+	return true
+}
+
+// escalationHint returns a platform-specific suggestion for re-running the current command with elevated privileges.
+func escalationHint() string {
+	if runtime.GOOS == "windows" {
+		return "Re-run the command from an elevated (Administrator) terminal"
+	}
+
+	args := []string{"skills-pkg", "<command>"}
+	if len(os.Args) > 0 && strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") == "skills-pkg" {
+		args = append([]string{"skills-pkg"}, os.Args[1:]...)
+	}
+	return "Re-run with elevated privileges: sudo " + strings.Join(args, " ")
+}
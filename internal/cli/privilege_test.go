@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestHandlePermissionError(t *testing.T) {
+	tests := []struct {
+		err         error
+		name        string
+		wantOutput  string
+		wantHandled bool
+	}{
+		{
+			name:        "target not writable",
+			err:         fmt.Errorf("install failed: %w", &domain.ErrorTargetNotWritable{Target: "/usr/local/share/skills-pkg/skills", Err: fs.ErrPermission}),
+			wantOutput:  "/usr/local/share/skills-pkg/skills",
+			wantHandled: true,
+		},
+		{
+			name:        "wrapped permission error",
+			err:         fmt.Errorf("failed to copy: %w", fs.ErrPermission),
+			wantOutput:  "Permission denied",
+			wantHandled: true,
+		},
+		{
+			name:        "unrelated error",
+			err:         errors.New("network unreachable"),
+			wantHandled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errBuf bytes.Buffer
+			logger := &Logger{out: &bytes.Buffer{}, errOut: &errBuf}
+
+			if got := handlePermissionError(logger, tt.err); got != tt.wantHandled {
+				t.Fatalf("handlePermissionError() = %v, want %v", got, tt.wantHandled)
+			}
+
+			output := errBuf.String()
+			if !tt.wantHandled {
+				if output != "" {
+					t.Errorf("expected no output for unhandled error, got: %s", output)
+				}
+				return
+			}
+			if !strings.Contains(output, tt.wantOutput) {
+				t.Errorf("output should contain %q, got: %s", tt.wantOutput, output)
+			}
+			if !strings.Contains(output, "elevated") {
+				t.Errorf("output should suggest elevated privileges, got: %s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// progressOptions returns the SkillManager options that render progress in format, and a function
+// that prints the progress not printed yet. Call it before prompting the user and when the operation is done.
+// Drawing status lines on a terminal redirects the log messages of logger above them.
+// With "json", each progress event is written to standard output as a JSON object on its own line,
+// for tools that display or record the progress. With "text" on a terminal, a status line is drawn for
+// every skill in progress, and the messages of each skill are printed as one block once it is done.
+// Otherwise the SkillManager prints the messages of each skill as one block itself.
+func progressOptions(logger *Logger, format string) ([]domain.SkillManagerOption, func()) {
+	switch {
+	case format == "json":
+		var mu sync.Mutex
+		encoder := json.NewEncoder(logger.dataOut)
+		return []domain.SkillManagerOption{domain.WithProgressEvents(func(event *domain.ProgressEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			_ = encoder.Encode(event)
+		})}, func() {}
+	case isTerminal(logger.dataOut) && os.Getenv("TERM") != "dumb":
+		live := &liveProgress{w: logger.dataOut}
+		// Log messages are written above the status lines, which would otherwise erase them when redrawn
+		logger.out = &liveLogWriter{live: live, w: logger.out}
+		logger.errOut = &liveLogWriter{live: live, w: logger.errOut}
+		return []domain.SkillManagerOption{domain.WithProgressEvents(live.handle)}, live.flush
+	default:
+		return nil, func() {}
+	}
+}
+
+// liveStatusWidth is the longest status line drawn, short enough not to wrap in common terminals,
+// as wrapped lines could not be erased when the status lines are redrawn.
+const liveStatusWidth = 79
+
+// liveProgress draws a status line for every skill in progress below the output of a terminal,
+// and prints the messages of each skill as one block above the status lines when the skill is done.
+type liveProgress struct {
+	w      io.Writer
+	skills []*liveSkill // Skills in progress, in the order they started
+	drawn  int          // Number of status lines on the screen
+	mu     sync.Mutex
+}
+
+// liveSkill is a skill in progress.
+type liveSkill struct {
+	status   *domain.ProgressEvent // Latest event with a percentage; nil before the installation starts
+	name     string
+	messages []string
+}
+
+// handle records event and redraws the status lines.
+func (p *liveProgress) handle(event *domain.ProgressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clear()
+
+	i := slices.IndexFunc(p.skills, func(skill *liveSkill) bool { return skill.name == event.Skill })
+	if i < 0 {
+		i = len(p.skills)
+		p.skills = append(p.skills, &liveSkill{name: event.Skill})
+	}
+	skill := p.skills[i]
+	skill.messages = append(skill.messages, event.Message)
+	if event.Percent != nil {
+		skill.status = event
+	}
+
+	if event.Phase == domain.PhaseDone {
+		p.print(skill)
+		p.skills = slices.Delete(p.skills, i, i+1)
+	}
+	p.draw()
+}
+
+// flush prints the messages of the skills that are not done, such as skills that failed, and erases the status lines.
+func (p *liveProgress) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clear()
+	for _, skill := range p.skills {
+		p.print(skill)
+	}
+	p.skills = nil
+}
+
+// print writes the messages of skill in one write.
+func (p *liveProgress) print(skill *liveSkill) {
+	if len(skill.messages) > 0 {
+		_, _ = io.WriteString(p.w, strings.Join(skill.messages, "\n")+"\n")
+	}
+}
+
+// draw writes a status line for every skill with a status.
+func (p *liveProgress) draw() {
+	var b strings.Builder
+	for _, skill := range p.skills {
+		if skill.status == nil {
+			continue
+		}
+		line := fmt.Sprintf("  %3d%% %-8s %s", *skill.status.Percent, skill.status.Phase, skill.name)
+		if runes := []rune(line); len(runes) > liveStatusWidth {
+			line = string(runes[:liveStatusWidth-1]) + "…"
+		}
+		b.WriteString(line + "\n")
+		p.drawn++
+	}
+	_, _ = io.WriteString(p.w, b.String())
+}
+
+// clear erases the status lines.
+func (p *liveProgress) clear() {
+	if p.drawn > 0 {
+		_, _ = fmt.Fprintf(p.w, "\x1b[%dA\x1b[J", p.drawn)
+		p.drawn = 0
+	}
+}
+
+// liveLogWriter writes to w above the status lines of live.
+type liveLogWriter struct {
+	live *liveProgress
+	w    io.Writer
+}
+
+func (l *liveLogWriter) Write(p []byte) (int, error) {
+	l.live.mu.Lock()
+	defer l.live.mu.Unlock()
+
+	l.live.clear()
+	defer l.live.draw()
+	return l.w.Write(p)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func progressEvent(skill string, phase domain.ProgressPhase, percent int, message string) *domain.ProgressEvent {
+	return &domain.ProgressEvent{Skill: skill, Phase: phase, Percent: &percent, Message: message}
+}
+
+func TestLiveProgress(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	live := &liveProgress{w: &out}
+
+	live.handle(progressEvent("a", domain.PhaseStart, 0, "Installing skill 'a'..."))
+	live.handle(progressEvent("b", domain.PhaseStart, 0, "Installing skill 'b'..."))
+	live.handle(progressEvent("a", domain.PhaseDone, 100, "Successfully installed skill 'a'"))
+	live.handle(&domain.ProgressEvent{Skill: "b", Phase: domain.PhaseWarning, Message: "WARNING: b"})
+
+	if live.drawn != 1 {
+		t.Errorf("drawn = %d, want a status line for b only", live.drawn)
+	}
+	if !strings.Contains(out.String(), "Installing skill 'a'...\nSuccessfully installed skill 'a'\n") {
+		t.Errorf("output %q should contain the messages of a as one block", out.String())
+	}
+	if strings.Contains(out.String(), "WARNING: b") {
+		t.Errorf("output %q should not contain the messages of b before it is done", out.String())
+	}
+
+	live.flush()
+	if live.drawn != 0 {
+		t.Errorf("drawn = %d after flush, want 0", live.drawn)
+	}
+	if !strings.HasSuffix(out.String(), "\x1b[1A\x1b[JInstalling skill 'b'...\nWARNING: b\n") {
+		t.Errorf("output %q should end with the status line erased and the messages of b", out.String())
+	}
+}
+
+func TestLiveLogWriter(t *testing.T) {
+	t.Parallel()
+
+	var out, log bytes.Buffer
+	live := &liveProgress{w: &out}
+	live.handle(progressEvent("a", domain.PhaseDownload, 10, "Downloading skill 'a'..."))
+
+	w := &liveLogWriter{live: live, w: &log}
+	if _, err := w.Write([]byte("message\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if log.String() != "message\n" {
+		t.Errorf("log = %q, want message", log.String())
+	}
+	if !strings.HasSuffix(out.String(), "\x1b[1A\x1b[J   10% download a\n") {
+		t.Errorf("output %q should end with the status line erased and redrawn", out.String())
+	}
+}
+
+func TestProgressOptions(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	logger := &Logger{out: &bytes.Buffer{}, dataOut: &out, errOut: &bytes.Buffer{}}
+	options, flush := progressOptions(logger, "json")
+	defer flush()
+	if len(options) != 1 {
+		t.Fatalf("progressOptions() returned %d options, want 1", len(options))
+	}
+
+	if options, _ := progressOptions(&Logger{dataOut: &bytes.Buffer{}}, "text"); len(options) != 0 {
+		t.Errorf("text progress to a non-terminal should be printed by the SkillManager, got %d options", len(options))
+	}
+}
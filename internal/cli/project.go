@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// standaloneCommands do not read the project configuration, or create it in the current directory,
+// so they run in the directory they are started in unless --config is given.
+var standaloneCommands = []string{"init", "scan", "explain", "keygen", "search", "store", "daemon", "publish"}
+
+// startDir is the directory the command was started in, when EnterProject changed to another one.
+var startDir string
+
+// EnterProject changes to the project directory before command runs, so that install targets and the
+// other relative paths in the configuration resolve the same way from any subdirectory of the project.
+// With configPath, that file is used and its directory is the project directory. Otherwise the
+// configuration, .skillspkg.toml or the file of profile in .skillspkg/, is looked up in the current
+// directory and its parents. Relative paths in the arguments of the command stay relative to the
+// directory it was started in: file arguments are made absolute by kong before, and the others are
+// resolved with projectPath.
+func EnterProject(configPath, profile, command string, verbose bool) error {
+	logger := NewLogger(verbose)
+
+	if configPath != "" {
+		dir := filepath.Dir(configPath)
+		if err := chdirProject(dir); err != nil {
+			return fmt.Errorf("failed to enter the project directory %s: %w", dir, err)
+		}
+		defaultConfigPath = filepath.Base(configPath)
+		logger.Verbose("Using configuration file %s", configPath)
+		return nil
+	}
+
+	if profile != "" {
+		path, err := domain.ProfileConfigPath(profile)
+		if err != nil {
+			return err
+		}
+		defaultConfigPath = path
+	}
+
+	if name, _, _ := strings.Cut(command, " "); slices.Contains(standaloneCommands, name) {
+		return nil
+	}
+	if _, err := os.Stat(defaultConfigPath); err == nil {
+		return nil
+	}
+
+	dir, err := domain.FindProject(".", defaultConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", defaultConfigPath, err)
+	}
+	if dir == "" {
+		// Commands report the missing configuration themselves
+		return nil
+	}
+	if err := chdirProject(dir); err != nil {
+		return fmt.Errorf("failed to enter the project directory %s: %w", dir, err)
+	}
+	logger.Verbose("Using configuration file %s", filepath.Join(dir, defaultConfigPath))
+
+	return nil
+}
+
+// chdirProject changes to the project directory dir, recording the directory it leaves in startDir.
+func chdirProject(dir string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	startDir = wd
+	return nil
+}
+
+// projectPath returns path, which is relative to the directory the command was started in, relative to
+// the project directory instead, so that it can be recorded in the configuration. Paths outside the
+// project are returned as absolute paths, and absolute paths and paths starting with ~ are kept.
+func projectPath(path string) string {
+	if startDir == "" || path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path
+	}
+	abs := filepath.Join(startDir, path)
+	wd, err := os.Getwd()
+	if err != nil {
+		return abs
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return abs
+}
+
+// projectFile returns projectPath of path when it names an existing file, and path otherwise, for the
+// arguments that are either files or references such as a repository or a key in a KMS.
+func projectFile(path string) string {
+	if startDir == "" || path == "" {
+		return path
+	}
+	if _, err := os.Stat(filepath.Join(startDir, path)); err != nil {
+		return path
+	}
+	return projectPath(path)
+}
+
+// newConfigManager returns the ConfigManager of the configuration at configPath, which downloads the
+// remote configurations it extends into the user cache directory, or on every load without one.
+func newConfigManager(configPath string, opts ...domain.ConfigManagerOption) *domain.ConfigManager {
+	var cache *domain.DownloadCache
+	if dirs, err := domain.ResolveUserDirs(); err == nil {
+		cache = domain.NewDownloadCache(dirs.DownloadCacheDir())
+	}
+	opts = append([]domain.ConfigManagerOption{
+		domain.WithBaseSources([]port.PackageManager{pkgmanager.Unprivileged(pkgmanager.NewGit())}, cache),
+		domain.WithConfigLogger(configLogger()),
+	}, opts...)
+	return domain.NewConfigManager(configPath, opts...)
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnterProject(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temporary directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "project", "src", "pkg"), 0o755); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "project", ".skillspkg"), 0o755); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+	for _, name := range []string{".skillspkg.toml", "team.toml", ".skillspkg/docs-team.toml"} {
+		if err := os.WriteFile(filepath.Join(root, "project", name), nil, 0o644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		configPath string
+		profile    string
+		command    string
+		wantDir    string
+		wantConfig string
+	}{
+		{name: "found in a parent", command: "install", wantDir: "project", wantConfig: ".skillspkg.toml"},
+		{name: "standalone command", command: "init", wantDir: "project/src/pkg", wantConfig: ".skillspkg.toml"},
+		{name: "config flag", configPath: filepath.Join(root, "project", "team.toml"), command: "target add <dir>", wantDir: "project", wantConfig: "team.toml"},
+		{name: "profile", profile: "docs-team", command: "install", wantDir: "project", wantConfig: filepath.Join(".skillspkg", "docs-team.toml")},
+		{name: "profile of standalone command", profile: "docs-team", command: "init", wantDir: "project/src/pkg", wantConfig: filepath.Join(".skillspkg", "docs-team.toml")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(filepath.Join(root, "project", "src", "pkg"))
+			t.Cleanup(func() { defaultConfigPath, startDir = ".skillspkg.toml", "" })
+
+			if err := EnterProject(tt.configPath, tt.profile, tt.command, false); err != nil {
+				t.Fatalf("EnterProject() error = %v", err)
+			}
+
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatalf("failed to get working directory: %v", err)
+			}
+			if want := filepath.Join(root, filepath.FromSlash(tt.wantDir)); wd != want {
+				t.Errorf("working directory = %s, want %s", wd, want)
+			}
+			if defaultConfigPath != tt.wantConfig {
+				t.Errorf("defaultConfigPath = %s, want %s", defaultConfigPath, tt.wantConfig)
+			}
+		})
+	}
+}
+
+// TestProjectPath tests that relative paths in arguments stay relative to the directory the command
+// was started in after EnterProject changes to the project directory.
+func TestProjectPath(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temporary directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "project", "src"), 0o755); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+	for _, name := range []string{".skillspkg.toml", "src/key"} {
+		if err := os.WriteFile(filepath.Join(root, "project", filepath.FromSlash(name)), nil, 0o644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	t.Chdir(filepath.Join(root, "project", "src"))
+	t.Cleanup(func() { defaultConfigPath, startDir = ".skillspkg.toml", "" })
+
+	if err := EnterProject("", "", "target add <dir>", false); err != nil {
+		t.Fatalf("EnterProject() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "skills", want: filepath.Join("src", "skills")},
+		{path: "..", want: "."},
+		{path: "../../other", want: filepath.Join(root, "other")},
+		{path: "/abs/skills", want: "/abs/skills"},
+		{path: "~/.claude/skills", want: "~/.claude/skills"},
+		{path: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := projectPath(tt.path); got != tt.want {
+			t.Errorf("projectPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	if got, want := projectFile("key"), filepath.Join("src", "key"); got != want {
+		t.Errorf("projectFile(key) = %q, want %q", got, want)
+	}
+	if got := projectFile("awskms:///alias/skills"); got != "awskms:///alias/skills" {
+		t.Errorf("projectFile() changed a key reference to %q", got)
+	}
+}
+
+func TestEnterProject_InvalidProfile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Cleanup(func() { defaultConfigPath = ".skillspkg.toml" })
+
+	if err := EnterProject("", "../other", "install", false); err == nil {
+		t.Error("EnterProject() should reject a profile that is not a file name")
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// PublishCmd represents the publish command
+type PublishCmd struct {
+	Dir        string `arg:"" optional:"" default:"." type:"path" help:"Skill directory to publish, containing SKILL.md (default: current directory)"`
+	Version    string `required:"" help:"Version to publish, such as v1.2.0; it names the git tag, the GitHub release, or the OCI tag"`
+	To         string `help:"Where to publish: git to push a tag, github to upload to a GitHub release, or oci to push to an OCI registry. Without it, the archive and manifest are only built" enum:",git,github,oci" default:""`
+	Repository string `help:"Where to publish to: the git remote (default: origin), the GitHub repository as owner/repo (default: the repository of origin), or the OCI repository, such as ghcr.io/org/skill" placeholder:"DEST"`
+	Out        string `help:"Directory to write the archive and manifest to" default:"dist" type:"path"`
+}
+
+// Run executes the publish command
+func (c *PublishCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.run(verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *PublishCmd) run(verbose bool) error {
+	return c.runWithDeps(NewLogger(verbose), service.NewDirhash(), pkgmanager.Publishers())
+}
+
+// runWithDeps validates and packs the skill, and publishes it with the publisher of the target (for testing)
+func (c *PublishCmd) runWithDeps(logger *Logger, hashService port.HashService, publishers []port.Publisher) error {
+	ctx := context.Background()
+
+	var publisher port.Publisher
+	if c.To != "" {
+		for _, p := range publishers {
+			if p.PublishTarget() == c.To {
+				publisher = p
+			}
+		}
+		if publisher == nil {
+			err := fmt.Errorf("unsupported publish target '%s'", c.To)
+			logger.Error("%v", err)
+			return err
+		}
+	}
+
+	logger.Verbose("Validating skill in %s", c.Dir)
+	artifact, err := domain.BuildSkillArtifact(ctx, c.Dir, c.Version, c.Out, hashService)
+	if err != nil {
+		if _, ok := errors.AsType[*domain.ErrorMalformedSkill](err); ok {
+			logger.Error("%v", err)
+			return err
+		}
+		logger.Error("Failed to pack skill in %s: %v", c.Dir, err)
+		return err
+	}
+	logger.Info("Packed skill '%s' %s", artifact.Name, artifact.Version)
+	logger.Info("  Archive:  %s (sha256 %s)", artifact.Archive, artifact.SHA256)
+	logger.Info("  Manifest: %s", artifact.Manifest)
+	logger.Info("  Hash:     %s", artifact.Hash)
+
+	if publisher == nil {
+		logger.Info("Use --to git, github, or oci to publish it")
+		return nil
+	}
+
+	logger.Verbose("Publishing to %s", c.To)
+	result, err := publisher.Publish(ctx, artifact, c.Repository)
+	if err != nil {
+		logger.Error("Failed to publish skill '%s' to %s: %v", artifact.Name, c.To, err)
+		return err
+	}
+
+	logger.Info("Successfully published skill '%s' %s: %s", artifact.Name, artifact.Version, result.Location)
+	logger.Info("Install it with:")
+	command := fmt.Sprintf("  skills-pkg add %s --source %s --url %s --version %s", artifact.Name, result.Source.Type, result.Source.URL, result.Version)
+	if result.SubDir != "" {
+		command += " --sub-dir " + result.SubDir
+	}
+	logger.Info("%s", command)
+	return nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// mockPublisher records the artifact it publishes and returns the archive source of a release.
+type mockPublisher struct {
+	artifact    *port.PublishArtifact
+	destination string
+}
+
+func (p *mockPublisher) PublishTarget() string {
+	return "github"
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, artifact *port.PublishArtifact, destination string) (*port.PublishResult, error) {
+	p.artifact, p.destination = artifact, destination
+	return &port.PublishResult{
+		Location: "https://github.com/org/skills/releases/tag/" + artifact.Version,
+		Source:   &port.Source{Type: "archive", URL: "https://github.com/org/skills/releases/download/{version}/code-review-{version}.tar.gz"},
+		Version:  artifact.Version,
+		SubDir:   ".",
+	}, nil
+}
+
+func TestPublishCmd(t *testing.T) {
+	tests := []struct {
+		name          string
+		skillMD       string
+		to            string
+		wantErr       bool
+		wantPublished bool
+		wantOutput    string
+	}{
+		{
+			name:       "build only",
+			skillMD:    "---\nname: code-review\ndescription: Reviews code.\n---\n",
+			wantOutput: "Use --to git, github, or oci to publish it",
+		},
+		{
+			name:          "publish",
+			skillMD:       "---\nname: code-review\ndescription: Reviews code.\n---\n",
+			to:            "github",
+			wantPublished: true,
+			wantOutput:    "skills-pkg add code-review --source archive --url https://github.com/org/skills/releases/download/{version}/code-review-{version}.tar.gz --version v1.0.0 --sub-dir .",
+		},
+		{
+			name:       "malformed skill",
+			skillMD:    "---\nname: code-review\n---\n",
+			to:         "github",
+			wantErr:    true,
+			wantOutput: "description is missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "code-review")
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(tt.skillMD), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			logger := &Logger{out: &buf, dataOut: &buf, errOut: &buf}
+			publisher := &mockPublisher{}
+			cmd := &PublishCmd{Dir: dir, Version: "v1.0.0", To: tt.to, Repository: "org/skills", Out: filepath.Join(t.TempDir(), "dist")}
+
+			err := cmd.runWithDeps(logger, &mockHashService{}, []port.Publisher{publisher})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithDeps() error = %v, wantErr %v\n%s", err, tt.wantErr, buf.String())
+			}
+			if !strings.Contains(buf.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", buf.String(), tt.wantOutput)
+			}
+			if published := publisher.artifact != nil; published != tt.wantPublished {
+				t.Errorf("published = %v, want %v", published, tt.wantPublished)
+			}
+			if tt.wantPublished && publisher.destination != "org/skills" {
+				t.Errorf("destination = %s, want org/skills", publisher.destination)
+			}
+			if tt.wantErr {
+				if code := domain.CodeOf(err); code == nil || code.Code != "SKP1506" {
+					t.Errorf("error code = %v, want SKP1506", code)
+				}
+				return
+			}
+			if _, err := os.Stat(filepath.Join(cmd.Out, "code-review-v1.0.0.tar.gz")); err != nil {
+				t.Errorf("archive was not written: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// RecommendCmd recommends skills from skills.sh for the languages, frameworks, and tools the project uses.
+type RecommendCmd struct {
+	Dir   string `help:"Project directory to inspect (defaults to the directory of the configuration)" type:"path"`
+	Limit int    `default:"3" help:"Maximum number of skills to recommend for each detected topic"`
+}
+
+// recommendation is a skill recommended for a topic of the project.
+type recommendation struct {
+	skill searchSkill
+	topic *domain.ProjectTopic
+}
+
+// Run executes the recommend command
+func (c *RecommendCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose), searchAPIBase)
+}
+
+// runWithLogger inspects the project and searches the registry at apiBase for each detected topic (for testing)
+func (c *RecommendCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger, apiBase string) error {
+	dir := c.Dir
+	if dir == "" {
+		dir = filepath.Dir(configPath)
+	}
+	limit := c.Limit
+	if limit <= 0 {
+		limit = 3
+	}
+
+	topics, err := domain.DetectProjectTopics(dir)
+	if err != nil {
+		logger.Error("Failed to inspect project: %v", err)
+		return err
+	}
+	if len(topics) == 0 {
+		logger.Info("No languages, frameworks, or tools detected in %s", dir)
+		logger.Info("Use 'skills-pkg search <query>' to find skills by keyword")
+		return nil
+	}
+	for _, topic := range topics {
+		logger.Verbose("Detected %s from %s", topic.Name, topic.Evidence)
+	}
+
+	// Skills in the configuration are already installed, so they are not recommended again
+	var configured []string
+	if config, err := newConfigManager(configPath).Load(ctx); err == nil {
+		for _, skill := range config.InstalledSkills() {
+			configured = append(configured, skill.Name)
+		}
+	} else if _, ok := errors.AsType[*domain.ErrorConfigNotFound](err); !ok {
+		logger.Error("Warning: failed to load configuration, configured skills may be recommended: %v", err)
+	}
+
+	search := &SearchCmd{}
+	var recommendations []*recommendation
+	for _, topic := range topics {
+		// Skills excluded below are made up for by asking for more results than needed
+		skills, err := search.fetchSkills(ctx, topic.Name, limit+len(configured), apiBase)
+		if err != nil {
+			logger.Error("Failed to search skills for %s: %v", topic.Name, err)
+			return err
+		}
+
+		count := 0
+		for _, skill := range skills {
+			if count >= limit {
+				break
+			}
+			if slices.Contains(configured, skill.SkillID) || slices.Contains(configured, skill.Name) ||
+				slices.ContainsFunc(recommendations, func(r *recommendation) bool {
+					return r.skill.Source == skill.Source && r.skill.SkillID == skill.SkillID
+				}) {
+				continue
+			}
+			recommendations = append(recommendations, &recommendation{skill: skill, topic: topic})
+			count++
+		}
+	}
+
+	names := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		names = append(names, topic.Name)
+	}
+	logger.Info("Detected: %s", strings.Join(names, ", "))
+
+	if len(recommendations) == 0 {
+		logger.Info("No new skills found for this project")
+		return nil
+	}
+
+	logger.Info("")
+	logger.Info("%-30s %-40s %-10s %s", "NAME", "SOURCE", "INSTALLS", "FOR")
+	logger.Info("%s", "--------------------------------------------------------------------------------")
+	for _, r := range recommendations {
+		logger.Info("%-30s %-40s %-10d %s (%s)", r.skill.Name, r.skill.Source, r.skill.Installs, r.topic.Name, r.topic.Evidence)
+	}
+
+	logger.Info("")
+	logger.Info("Total: %d recommendation(s)", len(recommendations))
+	first := recommendations[0].skill
+	logger.Info("Add one with 'skills-pkg add %s --url https://github.com/%s.git'", first.SkillID, first.Source)
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestRecommendCmd_runWithLogger(t *testing.T) {
+	t.Parallel()
+
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	if err := domain.NewConfigManager(configPath).AddSkill(context.Background(), &domain.Skill{
+		Name: "golang-pro", Source: "git", URL: "https://github.com/example/claude-skills.git", Version: "v1.0.0",
+	}); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(configPath), "go.mod"), []byte("module example.com/app\n"), 0o644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		_ = json.NewEncoder(w).Encode(searchResponse{Skills: []searchSkill{
+			{Name: "golang-pro", SkillID: "golang-pro", Source: "example/claude-skills", Installs: 100},
+			{Name: "go-testing", SkillID: "go-testing", Source: "example/go-skills", Installs: 42},
+		}})
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+	cmd := &RecommendCmd{Limit: 3}
+	if err := cmd.runWithLogger(context.Background(), configPath, logger, server.URL); err != nil {
+		t.Fatalf("runWithLogger() error = %v, output = %s", err, out.String())
+	}
+
+	if len(queries) != 1 || queries[0] != "go" {
+		t.Errorf("queries = %v, want [go]", queries)
+	}
+	output := out.String()
+	if strings.Contains(output, "golang-pro ") {
+		t.Errorf("output should not recommend the configured skill golang-pro, got:\n%s", output)
+	}
+	if !strings.Contains(output, "go-testing") || !strings.Contains(output, "go (go.mod)") {
+		t.Errorf("output should recommend go-testing for go.mod, got:\n%s", output)
+	}
+	if !strings.Contains(output, "skills-pkg add go-testing --url https://github.com/example/go-skills.git") {
+		t.Errorf("output should suggest how to add the recommendation, got:\n%s", output)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// RestoreCmd represents the restore command
+type RestoreCmd struct {
+	Skill   string `arg:"" help:"Name of the skill to restore from its backups"`
+	Version string `help:"Restore the newest backup of this version instead of the one before the installed version"`
+
+	backups *domain.Backups // Set by Run to the backups in the user state directory
+}
+
+// Run executes the restore command
+func (c *RestoreCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.backups = userBackups()
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *RestoreCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// runWithLogger puts the backed up copies of the skill back into the install targets (for testing)
+func (c *RestoreCmd) runWithLogger(configPath string, logger *Logger) error {
+	if c.backups == nil {
+		logger.Error("Backups are disabled, so there is nothing to restore")
+		logger.Error("Remove 'disabled = true' from the [backups] section of the user-level configuration to back up replaced skills")
+		return errors.New("backups are disabled")
+	}
+
+	logger.Info("Restoring skill '%s'", c.Skill)
+	logger.Verbose("Config path: %s", configPath)
+	logger.Verbose("Backup directory: %s", c.backups.Root())
+
+	skillManager := domain.NewSkillManager(newConfigManager(configPath), service.NewDirhash(), nil, domain.WithBackups(c.backups))
+
+	restored, err := skillManager.Restore(context.Background(), c.Skill, c.Version)
+	if err != nil {
+		c.handleRestoreError(logger, configPath, err)
+		return err
+	}
+
+	logger.Info("Successfully restored skill '%s' to version %s in %d install target(s)", c.Skill, restored[0].Version, len(restored))
+	return nil
+}
+
+// handleRestoreError reports why the skill could not be restored and what to do about it.
+func (c *RestoreCmd) handleRestoreError(logger *Logger, configPath string, err error) {
+	if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+		logger.Error("Configuration file not found at %s", err.Path)
+		logger.Error("Run 'skills-pkg init' to create a configuration file")
+		return
+	}
+
+	if err, ok := errors.AsType[*domain.ErrorNoBackup](err); ok {
+		logger.Error("%v", err)
+		logger.Error("Backups are saved when 'install', 'update', 'uninstall', or 'sync' replaces or removes an installed skill")
+		return
+	}
+
+	if handlePermissionError(logger, err) {
+		return
+	}
+
+	logger.Error("Failed to restore skill '%s': %v", c.Skill, err)
+	logger.Error("Check %s and file permissions and try again", configPath)
+}
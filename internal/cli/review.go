@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// ReviewCmd represents the review command group
+type ReviewCmd struct {
+	Approve ReviewApproveCmd `cmd:"" help:"Approve a version of a skill for installation"`
+	Block   ReviewBlockCmd   `cmd:"" help:"Block a version of a skill from being installed"`
+	List    ReviewListCmd    `cmd:"" help:"Show the trust level of the configured version of every skill"`
+}
+
+// ReviewApproveCmd represents the review approve command
+type ReviewApproveCmd struct {
+	Skill    string `arg:"" help:"Skill to approve as <skill>@<version>; without a version, the configured or locked version is approved"`
+	Note     string `help:"Note recorded with the review"`
+	Reviewer string `help:"Name recorded as the reviewer (default: the current user)"`
+}
+
+// ReviewBlockCmd represents the review block command
+type ReviewBlockCmd struct {
+	Skill    string `arg:"" help:"Skill to block as <skill>@<version>; without a version, the configured or locked version is blocked"`
+	Note     string `help:"Reason for blocking the version, shown when installing it is refused"`
+	Reviewer string `help:"Name recorded as the reviewer (default: the current user)"`
+}
+
+// ReviewListCmd represents the review list command
+type ReviewListCmd struct{}
+
+// Run executes the review approve command
+func (c *ReviewApproveCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger records the approval in the review file of the configuration at configPath (for testing)
+func (c *ReviewApproveCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger) error {
+	return recordReview(ctx, configPath, logger, c.Skill, domain.TrustApproved, c.Note, c.Reviewer)
+}
+
+// Run executes the review block command
+func (c *ReviewBlockCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger records the block in the review file of the configuration at configPath (for testing)
+func (c *ReviewBlockCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger) error {
+	return recordReview(ctx, configPath, logger, c.Skill, domain.TrustBlocked, c.Note, c.Reviewer)
+}
+
+// Run executes the review list command
+func (c *ReviewListCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	return c.runWithLogger(context.Background(), defaultConfigPath, NewLogger(verbose))
+}
+
+// runWithLogger prints the trust level of every configured skill (for testing)
+func (c *ReviewListCmd) runWithLogger(ctx context.Context, configPath string, logger *Logger) error {
+	config, err := newConfigManager(configPath).Load(ctx)
+	if err != nil {
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	reviews, err := domain.NewReviewManager(domain.ReviewPathFor(configPath)).Load(ctx)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	logger.Info("%-20s %-15s %-12s %s", "NAME", "VERSION", "TRUST", "REVIEWER")
+	for _, entry := range config.Skills {
+		for _, skill := range entry.InstalledSkills() {
+			version := reviewedVersion(skill, lock)
+			review := reviews.Find(skill.Name, version)
+			if version == "" || review == nil {
+				logger.Info("%-20s %-15s %-12s", skill.Name, version, domain.TrustUnreviewed)
+				continue
+			}
+			logger.Info("%-20s %-15s %-12s %s", skill.Name, version, review.Trust, review.Reviewer)
+		}
+	}
+	if config.RequireReview {
+		logger.Info("require_review is enabled: only approved versions are installed")
+	}
+
+	return nil
+}
+
+// recordReview records the trust level for the version of the skill that ref names as <skill>@<version>.
+// For entries with sub_dirs, the review is recorded for every member.
+func recordReview(ctx context.Context, configPath string, logger *Logger, ref string, trust domain.TrustLevel, note, reviewer string) error {
+	name, version, _ := strings.Cut(ref, "@")
+
+	config, err := newConfigManager(configPath).Load(ctx)
+	if err != nil {
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+	entry := config.FindSkillEntry(name)
+	if entry == nil {
+		err := &domain.ErrorSkillsNotFound{SkillNames: []string{name}}
+		logger.Error("Skill '%s' not found in configuration", name)
+		return err
+	}
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	skills := entry.InstalledSkills()
+	if version == "" {
+		version = reviewedVersion(skills[0], lock)
+		if version == "" {
+			err := fmt.Errorf("skill '%s' has no configured or locked version", name)
+			logger.Error("%v. Specify the version to review as %s@<version>", err, name)
+			return err
+		}
+	}
+	if reviewer == "" {
+		if current, err := user.Current(); err == nil {
+			reviewer = current.Username
+		}
+	}
+
+	reviewedAt := time.Now().UTC().Truncate(time.Second)
+	if err := domain.NewReviewManager(domain.ReviewPathFor(configPath)).Update(ctx, func(reviews *domain.ReviewFile) {
+		for _, skill := range skills {
+			reviews.Record(&domain.Review{
+				Skill:      skill.Name,
+				Version:    version,
+				Trust:      trust,
+				HashValue:  reviewedHash(skill, version, lock),
+				Reviewer:   reviewer,
+				Note:       note,
+				ReviewedAt: reviewedAt,
+			})
+		}
+	}); err != nil {
+		logger.Error("Failed to save review: %v", err)
+		return err
+	}
+
+	for _, skill := range skills {
+		logger.Info("Marked skill '%s' version %s as %s", skill.Name, version, trust)
+	}
+	if trust == domain.TrustApproved && !config.RequireReview {
+		logger.Info("Set 'require_review = true' in %s to refuse versions that have not been approved", configPath)
+	}
+
+	return nil
+}
+
+// reviewedVersion returns the version of the skill to review: the configured version, or the version
+// it resolved to in the lock file.
+func reviewedVersion(skill *domain.Skill, lock *domain.LockFile) string {
+	if skill.Version != "" {
+		return skill.Version
+	}
+	if locked := lock.FindSkill(skill.Name); locked != nil {
+		return locked.Version
+	}
+	return ""
+}
+
+// reviewedHash returns the known hash of the version of the skill, so that the review only holds for
+// the same content, or an empty string when the hash of the version is not known.
+func reviewedHash(skill *domain.Skill, version string, lock *domain.LockFile) string {
+	if skill.Version == version && skill.HashValue != "" {
+		return skill.HashValue
+	}
+	if locked := lock.FindSkill(skill.Name); locked != nil && locked.Version == version {
+		return locked.HashValue
+	}
+	return ""
+}
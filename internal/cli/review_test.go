@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestReviewApproveAndBlock(t *testing.T) {
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	if err := domain.NewConfigManager(configPath).Save(ctx, &domain.Config{
+		Skills: []*domain.Skill{
+			{Name: "pinned", Source: "git", URL: "https://github.com/example/pinned.git", Version: "v1.0.0", HashValue: "h1:pinned"},
+			{Name: "unpinned", Source: "git", URL: "https://github.com/example/unpinned.git"},
+		},
+		InstallTargets: []string{".claude/skills"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+
+	if err := (&ReviewApproveCmd{Skill: "pinned", Reviewer: "alice"}).runWithLogger(ctx, configPath, logger); err != nil {
+		t.Fatalf("review approve error = %v\noutput: %s", err, out.String())
+	}
+	if err := (&ReviewBlockCmd{Skill: "unpinned@v0.1.0", Note: "compromised"}).runWithLogger(ctx, configPath, logger); err != nil {
+		t.Fatalf("review block error = %v\noutput: %s", err, out.String())
+	}
+	// Skills without a configured or locked version need an explicit version
+	if err := (&ReviewApproveCmd{Skill: "unpinned"}).runWithLogger(ctx, configPath, logger); err == nil {
+		t.Error("review approve without a version succeeded for a skill without one")
+	}
+	if err := (&ReviewApproveCmd{Skill: "missing@v1.0.0"}).runWithLogger(ctx, configPath, logger); err == nil {
+		t.Error("review approve succeeded for a skill that is not configured")
+	}
+
+	reviews, err := domain.NewReviewManager(domain.ReviewPathFor(configPath)).Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review := reviews.Find("pinned", "v1.0.0"); review == nil || review.Trust != domain.TrustApproved || review.HashValue != "h1:pinned" || review.Reviewer != "alice" {
+		t.Errorf("review of pinned = %+v", review)
+	}
+	if review := reviews.Find("unpinned", "v0.1.0"); review == nil || review.Trust != domain.TrustBlocked || review.Note != "compromised" {
+		t.Errorf("review of unpinned = %+v", review)
+	}
+
+	out.Reset()
+	if err := (&ReviewListCmd{}).runWithLogger(ctx, configPath, logger); err != nil {
+		t.Fatalf("review list error = %v", err)
+	}
+	if !strings.Contains(out.String(), "approved") || !strings.Contains(out.String(), "unreviewed") {
+		t.Errorf("review list output = %s", out.String())
+	}
+}
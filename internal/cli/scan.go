@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// ScanCmd represents the scan command
+type ScanCmd struct {
+	Dir string `arg:"" help:"Directory to search for .skillspkg.toml files"`
+
+	Output string `kong:"-"` // Set by Run from the global --output flag: text or json
+}
+
+// Run executes the scan command
+func (c *ScanCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.Output = outputFlag(ctx)
+	return c.run(verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *ScanCmd) run(verbose bool) error {
+	return c.runWithLogger(NewLogger(verbose))
+}
+
+// runWithLogger scans the directory and prints the report (for testing)
+func (c *ScanCmd) runWithLogger(logger *Logger) error {
+	logger.Verbose("Scanning %s for %s files", c.Dir, domain.ConfigFileName)
+
+	report, err := domain.ScanProjects(context.Background(), c.Dir)
+	if err != nil {
+		logger.Error("Failed to scan %s: %v", c.Dir, err)
+		return err
+	}
+
+	if c.Output == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON output: %w", err)
+		}
+		_, err = fmt.Fprintln(logger.dataOut, string(data))
+		return err
+	}
+
+	for _, scanErr := range report.Errors {
+		logger.Error("Warning: skipped %s: %s", scanErr.Path, scanErr.Error)
+	}
+
+	if len(report.Projects) == 0 {
+		logger.Info("No %s files found under %s", domain.ConfigFileName, c.Dir)
+		return nil
+	}
+
+	logger.Info("Scanned %d project(s) under %s", len(report.Projects), c.Dir)
+	logger.Info("")
+	logger.Info("%-20s %-15s %-30s %s", "NAME", "SOURCE", "VERSION", "PROJECTS")
+
+	conflicting := 0
+	for _, skill := range report.Skills {
+		if len(skill.Versions) > 1 {
+			conflicting++
+		}
+		for i, version := range skill.Versions {
+			name, source := skill.Name, skill.Source
+			if i > 0 {
+				// Further versions of the same skill are listed below its first row
+				name, source = "", ""
+			}
+			logger.Info("%-20s %-15s %-30s %d", name, source, version.Version, len(version.Projects))
+			for _, project := range version.Projects {
+				logger.Verbose("  %s", project)
+			}
+		}
+	}
+
+	logger.Info("")
+	logger.Info("Total: %d skill(s) in %d project(s)", len(report.Skills), len(report.Projects))
+	if conflicting > 0 {
+		logger.Info("%d skill(s) are pinned to more than one version", conflicting)
+	}
+
+	return nil
+}
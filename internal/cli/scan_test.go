@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestScanCmd_Run(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, project := range []struct {
+		dir     string
+		version string
+	}{
+		{dir: "repo-a", version: "v1.0.0"},
+		{dir: "repo-b", version: "v2.0.0"},
+	} {
+		if err := os.MkdirAll(filepath.Join(root, project.dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		cm := domain.NewConfigManager(filepath.Join(root, project.dir, domain.ConfigFileName))
+		if err := cm.Initialize(context.Background(), nil); err != nil {
+			t.Fatalf("failed to initialize config: %v", err)
+		}
+		skill := &domain.Skill{Name: "shared", Source: "git", URL: "https://github.com/example/shared.git", Version: project.version}
+		if err := cm.AddSkill(context.Background(), skill); err != nil {
+			t.Fatalf("failed to add skill: %v", err)
+		}
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		cmd := &ScanCmd{Dir: root, Output: "text"}
+		if err := cmd.runWithLogger(&Logger{out: &buf, dataOut: &buf, errOut: &buf}); err != nil {
+			t.Fatalf("runWithLogger() error = %v", err)
+		}
+
+		output := buf.String()
+		for _, want := range []string{"Scanned 2 project(s)", "v1.0.0", "v2.0.0", "1 skill(s) are pinned to more than one version"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output should contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		var out, errOut bytes.Buffer
+		cmd := &ScanCmd{Dir: root, Output: "json"}
+		if err := cmd.runWithLogger(&Logger{out: &errOut, dataOut: &out, errOut: &errOut}); err != nil {
+			t.Fatalf("runWithLogger() error = %v", err)
+		}
+
+		var report domain.ScanReport
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+		}
+		if len(report.Projects) != 2 || len(report.Skills) != 1 || len(report.Skills[0].Versions) != 2 {
+			t.Errorf("report = %+v, want 2 projects sharing 1 skill in 2 versions", report)
+		}
+	})
+}
@@ -2,29 +2,47 @@ package cli
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/network"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"go.yaml.in/yaml/v3"
 )
 
 const (
 	searchAPIBase = "https://skills.sh"
 	searchLimit   = 10
 	rawGitHubBase = "https://raw.githubusercontent.com"
+	githubAPIBase = "https://api.github.com"
 )
 
-// SearchCmd searches for available skills on skills.sh.
+// SearchCmd searches skill indexes, such as skills.sh, and GitHub repository topics for skills.
 type SearchCmd struct {
-	Query string `arg:"" optional:"" help:"Search query for skills"`
-	Limit int    `default:"10" help:"Maximum number of results to show"`
+	Query       string   `arg:"" optional:"" help:"Search query for skills"`
+	Limit       int      `default:"10" help:"Maximum number of results to show from each index and topic"`
+	Index       []string `help:"Base URL of a skill index with the skills.sh search API, searched instead of the configured indexes (repeatable)" placeholder:"URL"`
+	GitHubTopic []string `name:"github-topic" help:"Search GitHub repositories with this topic as well, instead of the configured topics (repeatable)" placeholder:"TOPIC"`
+	Select      bool     `help:"Choose results to add to the configuration"`
+	Format      string   `default:"text" enum:"text,add" help:"Output format: text, or add to write the arguments of 'skills-pkg add' for each result to stdout, one line each"`
+
+	stdin         io.Reader // Answers to --select; defaults to the terminal
+	githubAPIBase string    // GitHub API to search topics with; defaults to api.github.com
+	allowRoot     bool      // Set from the global --allow-root flag
 }
 
 // searchSkill represents a skill returned by the skills.sh search API.
@@ -33,7 +51,11 @@ type searchSkill struct {
 	SkillID     string `json:"skillId"`
 	Source      string `json:"source"`
 	Description string `json:"-"`
+	Branch      string `json:"-"` // Branch that SKILL.md is read from; defaults to main
+	SubDir      string `json:"-"` // Directory of SKILL.md in the repository, once it has been found
 	Installs    int    `json:"installs"`
+	Stars       int    `json:"-"` // Stars of repositories found by topic, which have no install count
+	github      bool   // Found by GitHub topic search
 }
 
 // searchResponse is the top-level envelope returned by the skills.sh search API.
@@ -41,34 +63,125 @@ type searchResponse struct {
 	Skills []searchSkill `json:"skills"`
 }
 
+// githubSearchResponse is the part of the GitHub repository search response that is used.
+type githubSearchResponse struct {
+	Items []struct {
+		Name          string `json:"name"`
+		FullName      string `json:"full_name"`
+		Description   string `json:"description"`
+		DefaultBranch string `json:"default_branch"`
+		Stars         int    `json:"stargazers_count"`
+	} `json:"items"`
+}
+
+// addArgs returns the arguments of 'skills-pkg add' that add the skill.
+func (s *searchSkill) addArgs() []string {
+	return []string{s.SkillID, "--url", fmt.Sprintf("https://github.com/%s.git", s.Source), "--subdir", cmp.Or(s.SubDir, "skills/"+s.SkillID)}
+}
+
+// popularity returns the install count of the skill, or the stars of repositories found by topic.
+func (s *searchSkill) popularity() string {
+	if s.github {
+		return fmt.Sprintf("%d stars", s.Stars)
+	}
+	return strconv.Itoa(s.Installs)
+}
+
 func (c *SearchCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.allowRoot = allowRootFlag(ctx)
+
 	return c.runWithLogger(context.Background(), NewLogger(verbose))
 }
 
 func (c *SearchCmd) runWithLogger(ctx context.Context, logger *Logger) error {
-	return c.runWithLoggerAndBaseURLs(ctx, logger, searchAPIBase, rawGitHubBase)
+	indexes, topics := c.sources(logger)
+	return c.search(ctx, logger, indexes, topics, rawGitHubBase)
 }
 
 func (c *SearchCmd) runWithLoggerAndBaseURLs(ctx context.Context, logger *Logger, apiBase, rawBase string) error {
+	return c.search(ctx, logger, []string{apiBase}, c.GitHubTopic, rawBase)
+}
+
+// sources returns the indexes and GitHub topics to search. Those given by flags take the place of
+// those in the user-level configuration, and skills.sh is searched when no index is configured.
+func (c *SearchCmd) sources(logger *Logger) ([]string, []string) {
+	indexes, topics := c.Index, c.GitHubTopic
+	if len(indexes) == 0 || len(topics) == 0 {
+		if settings := configuredSearch(logger); settings != nil {
+			if len(indexes) == 0 {
+				indexes = settings.Indexes
+			}
+			if len(topics) == 0 {
+				topics = settings.GitHubTopics
+			}
+		}
+	}
+	if len(indexes) == 0 {
+		indexes = []string{searchAPIBase}
+	}
+	return indexes, topics
+}
+
+// configuredSearch returns the search settings of the user-level configuration, if any.
+func configuredSearch(logger *Logger) *domain.SearchSettings {
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		return nil
+	}
+	userConfig, err := domain.LoadUserConfig(dirs.ConfigFile())
+	if err != nil {
+		logger.Error("Warning: failed to load user configuration, searching the default index: %v", err)
+		return nil
+	}
+	return userConfig.Search
+}
+
+// search queries the indexes and GitHub topics, prints the results, and adds or prints the selected ones.
+func (c *SearchCmd) search(ctx context.Context, logger *Logger, indexes, topics []string, rawBase string) error {
 	limit := c.Limit
 	if limit <= 0 {
 		limit = searchLimit
 	}
 
-	logger.Verbose("Searching skills on skills.sh (query=%q, limit=%d)", c.Query, limit)
+	var skills []searchSkill
+	var errs []error
+	collect := func(source string, found []searchSkill, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+			return
+		}
+		for _, skill := range found {
+			if !slices.ContainsFunc(skills, func(s searchSkill) bool { return s.Source == skill.Source && s.SkillID == skill.SkillID }) {
+				skills = append(skills, skill)
+			}
+		}
+	}
+	for _, index := range indexes {
+		logger.Verbose("Searching skills on %s (query=%q, limit=%d)", index, c.Query, limit)
+		found, err := c.fetchSkills(ctx, c.Query, limit, index)
+		collect(index, found, err)
+	}
+	for _, topic := range topics {
+		logger.Verbose("Searching GitHub repositories with topic %s (query=%q, limit=%d)", topic, c.Query, limit)
+		found, err := c.fetchGitHubTopic(ctx, c.Query, topic, limit)
+		collect("GitHub topic "+topic, found, err)
+	}
 
-	skills, err := c.fetchSkills(ctx, c.Query, limit, apiBase)
-	if err != nil {
+	if len(errs) == len(indexes)+len(topics) {
+		err := errors.Join(errs...)
 		logger.Error("Failed to search skills: %v", err)
 		return err
 	}
+	for _, err := range errs {
+		logger.Error("Warning: failed to search %v", err)
+	}
 
 	if len(skills) == 0 {
 		logger.Info("No skills found")
@@ -82,98 +195,157 @@ func (c *SearchCmd) runWithLoggerAndBaseURLs(ctx context.Context, logger *Logger
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			skills[i].Description = c.fetchDescription(ctx, rawBase, skills[i].Source, skills[i].SkillID)
+			description, subDir := c.fetchDescription(ctx, rawBase, &skills[i])
+			skills[i].Description = cmp.Or(skills[i].Description, description)
+			skills[i].SubDir = subDir
 		}(i)
 	}
 	wg.Wait()
 
 	logger.Info("")
-	logger.Info("%-30s %-40s %-10s", "NAME", "SOURCE", "INSTALLS")
+	logger.Info("%-4s %-30s %-40s %-10s", "#", "NAME", "SOURCE", "INSTALLS")
 	logger.Info("%s", "--------------------------------------------------------------------------------")
 
-	for _, s := range skills {
-		logger.Info("%-30s %-40s %-10d", s.Name, s.Source, s.Installs)
+	for i, s := range skills {
+		logger.Info("%-4d %-30s %-40s %-10s", i+1, s.Name, s.Source, s.popularity())
 		if s.Description != "" {
-			logger.Info("  %s", s.Description)
+			logger.Info("     %s", s.Description)
 		}
 	}
 
 	logger.Info("")
 	logger.Info("Total: %d result(s)", len(skills))
 
+	selected := skills
+	if c.Select {
+		in := c.stdin
+		if in == nil {
+			in = confirmInput()
+		}
+		if in == nil {
+			err := errors.New("--select needs a terminal to choose results")
+			logger.Error("%v. Use --format add to pipe the results into 'skills-pkg add'", err)
+			return err
+		}
+
+		var err error
+		if selected, err = selectResults(logger, in, skills); err != nil {
+			logger.Error("%v", err)
+			return err
+		}
+		if len(selected) == 0 {
+			logger.Info("No skills selected")
+			return nil
+		}
+	}
+
+	switch {
+	case c.Format == "add":
+		for _, s := range selected {
+			_, _ = fmt.Fprintln(logger.dataOut, strings.Join(s.addArgs(), " "))
+		}
+	case c.Select:
+		for _, s := range selected {
+			add := &AddCmd{Name: s.SkillID, Source: "git", URL: fmt.Sprintf("https://github.com/%s.git", s.Source), SubDir: cmp.Or(s.SubDir, "skills/"+s.SkillID),
+				allowRoot: c.allowRoot, downloadCache: true}
+			if err := add.run(defaultConfigPath, logger.IsVerbose()); err != nil {
+				return err
+			}
+		}
+	default:
+		logger.Info("Add one with 'skills-pkg add %s', or choose results with --select", strings.Join(skills[0].addArgs(), " "))
+	}
+
 	return nil
 }
 
-// fetchDescription retrieves the description field from a skill's SKILL.md.
-// It first tries skills/{skillID}/SKILL.md (multi-skill repos), then falls back
-// to SKILL.md at the repository root (single-skill repos).
-func (c *SearchCmd) fetchDescription(ctx context.Context, rawBase, source, skillID string) string {
-	primaryURL := fmt.Sprintf("%s/%s/main/skills/%s/SKILL.md", rawBase, source, skillID)
-	if desc := c.tryFetchDescription(ctx, primaryURL); desc != "" {
-		return desc
+// selectResults asks which of the numbered results to add. The answer lists result numbers
+// separated by commas or spaces, or is "all"; an empty answer, or the end of the input, selects none.
+func selectResults(logger *Logger, in io.Reader, skills []searchSkill) ([]searchSkill, error) {
+	_, _ = fmt.Fprintf(logger.out, "Results to add (e.g. 1,3 or all): ")
+	answers := bufio.NewScanner(in)
+	if !answers.Scan() {
+		_, _ = fmt.Fprintln(logger.out)
+		return nil, nil
+	}
+
+	answer := strings.TrimSpace(answers.Text())
+	if strings.EqualFold(answer, "all") {
+		return skills, nil
+	}
+
+	var selected []searchSkill
+	for field := range strings.FieldsFuncSeq(answer, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(skills) {
+			return nil, fmt.Errorf("invalid selection %q: enter result numbers from 1 to %d", field, len(skills))
+		}
+		if skill := skills[n-1]; !slices.ContainsFunc(selected, func(s searchSkill) bool { return s.Source == skill.Source && s.SkillID == skill.SkillID }) {
+			selected = append(selected, skill)
+		}
+	}
+	return selected, nil
+}
+
+// fetchDescription retrieves the description field from a skill's SKILL.md and returns it with the
+// directory SKILL.md was found in. It first tries skills/{skillID}/SKILL.md (multi-skill repos),
+// then falls back to SKILL.md at the repository root (single-skill repos).
+func (c *SearchCmd) fetchDescription(ctx context.Context, rawBase string, skill *searchSkill) (string, string) {
+	branch := cmp.Or(skill.Branch, "main")
+	subDir := "skills/" + skill.SkillID
+	primaryURL := fmt.Sprintf("%s/%s/%s/%s/SKILL.md", rawBase, skill.Source, branch, subDir)
+	if desc, ok := c.tryFetchDescription(ctx, primaryURL); ok {
+		return desc, subDir
 	}
 
-	fallbackURL := fmt.Sprintf("%s/%s/main/SKILL.md", rawBase, source)
-	return c.tryFetchDescription(ctx, fallbackURL)
+	fallbackURL := fmt.Sprintf("%s/%s/%s/SKILL.md", rawBase, skill.Source, branch)
+	if desc, ok := c.tryFetchDescription(ctx, fallbackURL); ok {
+		return desc, "."
+	}
+	return "", ""
 }
 
 // tryFetchDescription fetches a SKILL.md from rawURL and extracts the description field value.
-func (c *SearchCmd) tryFetchDescription(ctx context.Context, rawURL string) string {
+// It reports whether the SKILL.md exists.
+func (c *SearchCmd) tryFetchDescription(ctx context.Context, rawURL string) (string, bool) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		return ""
+		return "", false
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := network.Client().Do(req)
 	if err != nil {
-		return ""
+		return "", false
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return ""
+		return "", false
 	}
 
-	return parseSkillMDDescription(resp.Body)
+	return parseSkillMDDescription(resp.Body), true
 }
 
 // parseSkillMDDescription parses an MDX/Markdown document and extracts the description
 // field from the YAML frontmatter block (delimited by ---). If no frontmatter is
-// present, it falls back to scanning all lines for a bare "description:" key.
+// present, it falls back to reading the whole file as bare YAML metadata.
 func parseSkillMDDescription(r io.Reader) string {
-	scanner := bufio.NewScanner(r)
-	if !scanner.Scan() {
-		return ""
-	}
-	firstLine := strings.TrimRight(scanner.Text(), "\r")
-
-	if firstLine == "---" {
-		// Frontmatter block: scan until closing --- or ...
-		for scanner.Scan() {
-			line := strings.TrimRight(scanner.Text(), "\r")
-			if line == "---" || line == "..." {
-				break
-			}
-			if after, ok := strings.CutPrefix(line, "description:"); ok {
-				return strings.TrimSpace(after)
-			}
-		}
+	content, err := io.ReadAll(r)
+	if err != nil {
 		return ""
 	}
 
-	// No frontmatter delimiter — treat the whole file as bare YAML metadata.
-	if after, ok := strings.CutPrefix(firstLine, "description:"); ok {
-		return strings.TrimSpace(after)
+	metadata, ok, err := domain.ParseSkillFrontmatter(content)
+	if !ok {
+		metadata = &domain.SkillFrontmatter{}
+		err = yaml.Unmarshal(content, metadata)
 	}
-	for scanner.Scan() {
-		line := scanner.Text()
-		if after, ok := strings.CutPrefix(line, "description:"); ok {
-			return strings.TrimSpace(after)
-		}
+	if err != nil {
+		return ""
 	}
-	return ""
+	return metadata.Description
 }
 
 func (c *SearchCmd) fetchSkills(ctx context.Context, query string, limit int, apiBase string) ([]searchSkill, error) {
@@ -192,7 +364,7 @@ func (c *SearchCmd) fetchSkills(ctx context.Context, query string, limit int, ap
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := network.Client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("search API request: %w", err)
 	}
@@ -211,3 +383,58 @@ func (c *SearchCmd) fetchSkills(ctx context.Context, query string, limit int, ap
 
 	return result.Skills, nil
 }
+
+// fetchGitHubTopic searches the GitHub repositories with the topic that match query, most starred first.
+// GITHUB_TOKEN is sent when it is set, which raises the rate limit of the search API.
+func (c *SearchCmd) fetchGitHubTopic(ctx context.Context, query, topic string, limit int) ([]searchSkill, error) {
+	apiURL, err := url.Parse(cmp.Or(c.githubAPIBase, githubAPIBase) + "/search/repositories")
+	if err != nil {
+		return nil, fmt.Errorf("parse API URL: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("q", strings.TrimSpace(query+" topic:"+topic))
+	params.Set("sort", "stars")
+	params.Set("per_page", strconv.Itoa(limit))
+	apiURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := network.Client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub search request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub search returned status %d", resp.StatusCode)
+	}
+
+	var result githubSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode GitHub search response: %w", err)
+	}
+
+	skills := make([]searchSkill, 0, len(result.Items))
+	for _, item := range result.Items {
+		skills = append(skills, searchSkill{
+			Name:        item.Name,
+			SkillID:     item.Name,
+			Source:      item.FullName,
+			Description: item.Description,
+			Branch:      item.DefaultBranch,
+			Stars:       item.Stars,
+			github:      true,
+		})
+	}
+	return skills, nil
+}
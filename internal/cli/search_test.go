@@ -59,6 +59,11 @@ func TestParseSkillMDDescription(t *testing.T) {
 			input: "---\r\ndescription: Windows line endings.\r\n---\r\n",
 			want:  "Windows line endings.",
 		},
+		{
+			name:  "frontmatter: byte order mark and quoted value",
+			input: "\ufeff---\ndescription: \"Use when: reviewing code\"\n---\n",
+			want:  "Use when: reviewing code",
+		},
 	}
 
 	for _, tt := range tests {
@@ -273,3 +278,84 @@ func TestSearchCmd_runWithLoggerAndBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchCmd_GitHubTopicAndSelect(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/search":
+			_ = json.NewEncoder(w).Encode(searchResponse{Skills: []searchSkill{
+				{Name: "golang-pro", SkillID: "golang-pro", Source: "example/claude-skills", Installs: 100},
+			}})
+		case "/search/repositories":
+			gotQuery = r.URL.Query().Get("q")
+			_, _ = fmt.Fprint(w, `{"items":[{"name":"go-review","full_name":"example/go-review","description":"Reviews Go code.","default_branch":"trunk","stargazers_count":12}]}`)
+		case "/example/go-review/trunk/SKILL.md":
+			_, _ = fmt.Fprint(w, "---\nname: go-review\n---\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cmd := &SearchCmd{
+		Query:         "go",
+		GitHubTopic:   []string{"agent-skills"},
+		Select:        true,
+		Format:        "add",
+		stdin:         strings.NewReader("2\n"),
+		githubAPIBase: server.URL,
+	}
+
+	var out, data bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &data, errOut: &out}
+	if err := cmd.runWithLoggerAndBaseURLs(context.Background(), logger, server.URL, server.URL); err != nil {
+		t.Fatalf("runWithLoggerAndBaseURLs() error = %v\noutput: %s", err, out.String())
+	}
+
+	if gotQuery != "go topic:agent-skills" {
+		t.Errorf("GitHub search query = %q, want %q", gotQuery, "go topic:agent-skills")
+	}
+	for _, want := range []string{"golang-pro", "go-review", "12 stars", "Reviews Go code."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output should contain %q, got: %s", want, out.String())
+		}
+	}
+	// The SKILL.md of the selected repository is at its root
+	if want := "go-review --url https://github.com/example/go-review.git --subdir .\n"; data.String() != want {
+		t.Errorf("add arguments = %q, want %q", data.String(), want)
+	}
+
+	cmd.stdin = strings.NewReader("3\n")
+	if err := cmd.runWithLoggerAndBaseURLs(context.Background(), logger, server.URL, server.URL); err == nil {
+		t.Error("selecting a result that does not exist succeeded")
+	}
+}
+
+func TestSearchCmd_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/search" {
+			_ = json.NewEncoder(w).Encode(searchResponse{Skills: []searchSkill{{Name: "go-tools", SkillID: "go-tools", Source: "example/go-tools"}}})
+			return
+		}
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	cmd := &SearchCmd{GitHubTopic: []string{"agent-skills"}, Format: "add", githubAPIBase: server.URL}
+	var out, data bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &data, errOut: &out}
+	if err := cmd.runWithLoggerAndBaseURLs(context.Background(), logger, server.URL, server.URL); err != nil {
+		t.Fatalf("runWithLoggerAndBaseURLs() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Warning: failed to search GitHub topic agent-skills") {
+		t.Errorf("output should warn about the failed topic search, got: %s", out.String())
+	}
+	if want := "go-tools --url https://github.com/example/go-tools.git --subdir skills/go-tools\n"; data.String() != want {
+		t.Errorf("add arguments = %q, want %q", data.String(), want)
+	}
+}
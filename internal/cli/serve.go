@@ -0,0 +1,596 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxRequestBodySize limits the size of request bodies accepted by the HTTP API.
+const maxRequestBodySize = 1 << 20
+
+// ServeCmd represents the serve command
+type ServeCmd struct {
+	HTTP    string `help:"Address to serve the HTTP API on, e.g. ':8080' for 127.0.0.1:8080; other hosts than loopback require --tls-cert" name:"http" placeholder:"ADDR" xor:"stdio-http"`
+	GRPC    string `help:"Address to serve the gRPC API on, e.g. ':9090' for 127.0.0.1:9090; other hosts than loopback require --tls-cert" name:"grpc" placeholder:"ADDR" xor:"stdio-grpc"`
+	Stdio   bool   `help:"Serve JSON-RPC 2.0 on stdin and stdout for editor extensions" xor:"stdio-http,stdio-grpc,stdio-metrics"`
+	Token   string `help:"Token that clients of the HTTP and gRPC APIs must send as 'Authorization: Bearer <token>'" env:"SKILLSPKG_SERVE_TOKEN"`
+	Metrics string `help:"Address to serve Prometheus metrics on at /metrics, e.g. ':9100' for 127.0.0.1:9100; other hosts than loopback require --tls-cert" name:"metrics" placeholder:"ADDR" xor:"stdio-metrics"`
+	TLSCert string `help:"Certificate file, in PEM, to serve the HTTP and gRPC APIs and the metrics over TLS with" name:"tls-cert" placeholder:"FILE" type:"existingfile" and:"tls"`
+	TLSKey  string `help:"Private key file, in PEM, of --tls-cert" name:"tls-key" placeholder:"FILE" type:"existingfile" and:"tls"`
+
+	allowRoot     bool // Set from the global --allow-root flag
+	downloadCache bool // Set by Run to reuse downloads from the user cache directory
+}
+
+// Run executes the serve command
+func (c *ServeCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+// It serves the network APIs until the process receives an interrupt or termination signal,
+// or JSON-RPC on stdio until stdin is closed.
+func (c *ServeCmd) run(configPath string, verbose bool) error {
+	logger := NewLogger(verbose)
+
+	if c.Stdio {
+		// Stdout carries the protocol, so progress and log messages must not be written to it
+		api := c.newAPIServer(configPath, logger, service.NewDirhash(), pkgmanager.All())
+		if err := c.serveStdio(context.Background(), api, os.Stdin, os.Stdout); err != nil {
+			logger.Error("%v", err)
+			return err
+		}
+		return nil
+	}
+
+	if c.HTTP == "" && c.GRPC == "" {
+		err := errors.New("no API to serve")
+		logger.Error("%v", err)
+		logger.Error("Specify --http, --grpc, or --stdio")
+		return err
+	}
+	if strings.TrimSpace(c.Token) == "" {
+		err := errors.New("the API token must not be empty")
+		logger.Error("%v", err)
+		logger.Error("Set a token with --token or SKILLSPKG_SERVE_TOKEN")
+		return err
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		logger.Error("%v", err)
+		logger.Error("Check that --tls-cert and --tls-key are a PEM certificate and its private key")
+		return err
+	}
+
+	api := c.newAPIServer(configPath, logger, service.NewDirhash(), pkgmanager.All())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	eg, ctx := errgroup.WithContext(ctx)
+
+	if c.HTTP != "" {
+		listener, err := listenAPI(c.HTTP, tlsConfig)
+		if err != nil {
+			logger.Error("Failed to listen on %s: %v", c.HTTP, err)
+			logger.Error("Check that the address is valid and not in use, and serve other hosts than loopback with --tls-cert and --tls-key")
+			return err
+		}
+		if tlsConfig != nil {
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		server := &http.Server{
+			Handler:           c.httpHandler(api),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		logger.Info("Serving the HTTP API on %s", listener.Addr())
+		eg.Go(func() error {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("HTTP server failed: %w", err)
+			}
+			return nil
+		})
+		eg.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		})
+	}
+
+	if c.Metrics != "" {
+		listener, err := listenMetrics(c.Metrics, tlsConfig)
+		if err != nil {
+			stop()
+			_ = eg.Wait()
+			logger.Error("Failed to listen on %s: %v", c.Metrics, err)
+			logger.Error("Check that the address is valid and not in use, and serve other hosts than loopback with --tls-cert and --tls-key")
+			return err
+		}
+		// Scrapers cannot send the token, so the metrics do not require it
+		server := newMetricsServer(metricsHandler(api.metrics.families))
+
+		logger.Info("Serving metrics on %s", listener.Addr())
+		eg.Go(func() error {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("metrics server failed: %w", err)
+			}
+			return nil
+		})
+		eg.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		})
+	}
+
+	if c.GRPC != "" {
+		listener, err := listenAPI(c.GRPC, tlsConfig)
+		if err != nil {
+			stop()
+			_ = eg.Wait()
+			logger.Error("Failed to listen on %s: %v", c.GRPC, err)
+			logger.Error("Check that the address is valid and not in use, and serve other hosts than loopback with --tls-cert and --tls-key")
+			return err
+		}
+		server := c.grpcServer(api, tlsConfig)
+
+		logger.Info("Serving the gRPC API on %s", listener.Addr())
+		eg.Go(func() error {
+			if err := server.Serve(listener); err != nil {
+				return fmt.Errorf("gRPC server failed: %w", err)
+			}
+			return nil
+		})
+		eg.Go(func() error {
+			<-ctx.Done()
+			server.GracefulStop()
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	logger.Info("Server stopped")
+
+	return nil
+}
+
+// tlsConfig returns the TLS configuration of the HTTP and gRPC APIs, or nil when --tls-cert is not set.
+func (c *ServeCmd) tlsConfig() (*tls.Config, error) {
+	if c.TLSCert == "" {
+		return nil, nil
+	}
+	certificate, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// listenAPI listens on the address of an API. An address without a host, such as ":8080", listens on
+// 127.0.0.1 only. Without TLS, clients would send the token in cleartext, so other addresses than
+// loopback are refused unless tlsConfig is set.
+func listenAPI(addr string, tlsConfig *tls.Config) (net.Listener, error) {
+	host, address, err := loopbackByDefault(addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil && !isLoopbackHost(host) {
+		return nil, fmt.Errorf("refusing to serve the API on %s without TLS, as the token would be sent in cleartext", addr)
+	}
+	return net.Listen("tcp", address)
+}
+
+// loopbackByDefault returns the host of addr and the address to listen on, which is addr with
+// 127.0.0.1 as its host when it has none.
+func loopbackByDefault(addr string) (host, address string, err error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return host, net.JoinHostPort(host, port), nil
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback IP address.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newAPIServer creates the implementation shared by the HTTP and gRPC APIs
+// for the configuration at configPath with the given dependencies (for testing).
+func (c *ServeCmd) newAPIServer(configPath string, logger *Logger, hashService port.HashService, packageManagers []port.PackageManager) *apiServer {
+	// The download cache is created here instead of by skillManagerOptions so that its hit rate can be reported
+	options := skillManagerOptions(c.allowRoot, false)
+	var downloadCache *domain.DownloadCache
+	if c.downloadCache {
+		if dirs, err := domain.ResolveUserDirs(); err == nil {
+			downloadCache = domain.NewDownloadCache(dirs.DownloadCacheDir())
+			options = append(options, domain.WithDownloadCache(downloadCache))
+		}
+		options = append(options, backupOptions()...)
+	}
+
+	return &apiServer{
+		configManager:   newConfigManager(configPath),
+		lockManager:     domain.NewLockManager(domain.LockPathFor(configPath)),
+		hashService:     hashService,
+		packageManagers: packageManagers,
+		options:         options,
+		logger:          logger,
+		metrics:         newAPIMetrics(downloadCache),
+	}
+}
+
+// httpHandler returns the HTTP API.
+func (c *ServeCmd) httpHandler(api *apiServer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/skills", api.handleList)
+	mux.HandleFunc("POST /v1/install", api.handleInstall)
+	mux.HandleFunc("POST /v1/update", api.handleUpdate)
+	mux.HandleFunc("POST /v1/verify", api.handleVerify)
+
+	return requireToken(c.Token, api.logger, mux)
+}
+
+// requireToken rejects requests that do not carry the token as a bearer token.
+func requireToken(token string, logger *Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validBearerToken(r.Header.Get("Authorization"), token) {
+			logger.Verbose("Rejected unauthenticated request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAPIError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+
+		logger.Verbose("%s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validBearerToken reports whether the Authorization header value carries token as a bearer token.
+func validBearerToken(authorization, token string) bool {
+	got, ok := strings.CutPrefix(authorization, "Bearer ")
+	// Compare digests so that the comparison takes the same time for tokens of any length
+	gotSum, wantSum := sha256.Sum256([]byte(got)), sha256.Sum256([]byte(token))
+	return ok && subtle.ConstantTimeCompare(gotSum[:], wantSum[:]) == 1
+}
+
+// apiServer implements the endpoints of the HTTP and gRPC APIs.
+// Requests are handled one at a time, as each of them may rewrite the configuration and the install targets.
+type apiServer struct {
+	configManager   *domain.ConfigManager
+	hashService     port.HashService
+	lockManager     *domain.LockManager
+	logger          *Logger
+	metrics         *apiMetrics
+	packageManagers []port.PackageManager
+	options         []domain.SkillManagerOption
+	mu              sync.Mutex
+}
+
+// apiSkillsRequest is the request body of the install and update endpoints.
+type apiSkillsRequest struct {
+	Skills []string `json:"skills"`            // Skill names; all skills when empty
+	DryRun bool     `json:"dry_run,omitempty"` // Only check for updates (update endpoint)
+}
+
+type apiSkill struct {
+	Name    string            `json:"name"`
+	Source  string            `json:"source"`
+	URL     string            `json:"url"`
+	Version string            `json:"version"`
+	Targets []*apiSkillTarget `json:"targets"`
+}
+
+type apiSkillTarget struct {
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+	Path        string     `json:"path"`
+	Status      string     `json:"status"`
+	Version     string     `json:"version,omitempty"`
+}
+
+type apiInstallResponse struct {
+	Installed []string `json:"installed"`
+}
+
+type apiVerifyResponse struct {
+	Results    []*apiVerifyResult `json:"results"`
+	Drifts     []*apiDrift        `json:"drifts"`
+	Total      int                `json:"total"`
+	Successful int                `json:"successful"`
+	Failed     int                `json:"failed"`
+}
+
+type apiVerifyResult struct {
+	SkillName  string `json:"skill_name"`
+	InstallDir string `json:"install_dir"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+	Match      bool   `json:"match"`
+	Drifted    bool   `json:"drifted"`
+}
+
+type apiDrift struct {
+	Kind       string `json:"kind"`
+	SkillName  string `json:"skill_name"`
+	Target     string `json:"target"`
+	Configured string `json:"configured"`
+	Locked     string `json:"locked"`
+}
+
+// listSkills returns the configured skills and their state in each install target.
+func (a *apiServer) listSkills(ctx context.Context) ([]*apiSkill, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	config, err := a.configManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := a.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	skills := []*apiSkill{}
+	for _, skill := range config.InstalledSkills() {
+		item := &apiSkill{Name: skill.Name, Source: skill.Source, URL: config.Sealed(skill.URL), Version: skill.Version, Targets: []*apiSkillTarget{}}
+		locked := lock.FindSkill(skill.Name)
+		for _, target := range config.InstallTargets {
+			status := locked.TargetStatus(target)
+			freshness, err := domain.CheckTargetFreshness(ctx, a.hashService, config, skill, status)
+			if err != nil {
+				a.logger.Verbose("Failed to check %s in %s: %v", skill.Name, target, err)
+				freshness = "unknown"
+			}
+
+			targetItem := &apiSkillTarget{Path: target, Status: string(freshness)}
+			if status != nil {
+				targetItem.Version = status.Version
+				targetItem.InstalledAt = &status.InstalledAt
+			}
+			item.Targets = append(item.Targets, targetItem)
+		}
+		skills = append(skills, item)
+	}
+
+	return skills, nil
+}
+
+// installSkills installs the named skills, or all skills when skillNames is empty,
+// and returns the names of the installed skills. Progress messages are written to progress
+// when it is not nil.
+func (a *apiServer) installSkills(ctx context.Context, skillNames []string, progress io.Writer) (_ []string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer func() { a.metrics.observe("install", err) }()
+
+	skillManager := a.skillManager(progress)
+	if len(skillNames) == 0 {
+		if err := skillManager.Install(ctx, ""); err != nil {
+			return nil, err
+		}
+		a.logger.Info("Installed all skills")
+		return []string{}, nil
+	}
+
+	installed := make([]string, 0, len(skillNames))
+	for _, skillName := range skillNames {
+		if err := skillManager.Install(ctx, skillName); err != nil {
+			return nil, err
+		}
+		installed = append(installed, skillName)
+	}
+	a.logger.Info("Installed skills: %v", installed)
+	return installed, nil
+}
+
+// updateSkills updates the named skills, or all skills when skillNames is empty.
+// Progress messages are written to progress when it is not nil.
+func (a *apiServer) updateSkills(ctx context.Context, skillNames []string, dryRun bool, progress io.Writer) ([]*domain.UpdateResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	results, err := a.skillManager(progress).Update(ctx, skillNames, &domain.UpdateOptions{DryRun: dryRun})
+	a.metrics.observe("update", err)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		a.logger.Info("%s", updateSummary(results))
+	}
+	return results, nil
+}
+
+// verifySkills verifies the installed skills against their recorded hashes.
+func (a *apiServer) verifySkills(ctx context.Context) (*domain.VerifySummary, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summary, err := domain.NewHashVerifier(a.configManager, a.hashService).VerifyAll(ctx)
+	a.metrics.observe("verify", err)
+	if err != nil {
+		return nil, err
+	}
+	a.metrics.observeVerify(summary)
+	return summary, nil
+}
+
+// skillManager creates a SkillManager that writes progress messages to progress when it is not nil.
+func (a *apiServer) skillManager(progress io.Writer) domain.SkillManager {
+	opts := a.options
+	if progress != nil {
+		opts = append(slices.Clone(opts), domain.WithProgressOutput(progress))
+	}
+	return domain.NewSkillManager(a.configManager, a.hashService, a.packageManagers, opts...)
+}
+
+// handleList reports the configured skills and their state in each install target.
+func (a *apiServer) handleList(w http.ResponseWriter, r *http.Request) {
+	skills, err := a.listSkills(r.Context())
+	if err != nil {
+		a.fail(w, r, err)
+		return
+	}
+
+	writeAPIResponse(w, http.StatusOK, map[string]any{"skills": skills})
+}
+
+// handleInstall installs the requested skills, or all skills when none are requested.
+func (a *apiServer) handleInstall(w http.ResponseWriter, r *http.Request) {
+	req, err := readAPIRequest(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	installed, err := a.installSkills(r.Context(), req.Skills, nil)
+	if err != nil {
+		a.fail(w, r, err)
+		return
+	}
+
+	writeAPIResponse(w, http.StatusOK, &apiInstallResponse{Installed: installed})
+}
+
+// handleUpdate updates the requested skills, or all skills when none are requested.
+// The response has the same format as 'skills-pkg update --dry-run --output json'.
+func (a *apiServer) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	req, err := readAPIRequest(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := a.updateSkills(r.Context(), req.Skills, req.DryRun, nil)
+	if err != nil {
+		a.fail(w, r, err)
+		return
+	}
+
+	writeAPIResponse(w, http.StatusOK, newDryRunOutput(results))
+}
+
+// handleVerify verifies the installed skills against their recorded hashes.
+func (a *apiServer) handleVerify(w http.ResponseWriter, r *http.Request) {
+	summary, err := a.verifySkills(r.Context())
+	if err != nil {
+		a.fail(w, r, err)
+		return
+	}
+
+	writeAPIResponse(w, http.StatusOK, newVerifyResponse(summary))
+}
+
+// newVerifyResponse converts a verification summary into its JSON-serializable form.
+func newVerifyResponse(summary *domain.VerifySummary) *apiVerifyResponse {
+	resp := &apiVerifyResponse{
+		Results:    make([]*apiVerifyResult, 0, len(summary.Results)),
+		Drifts:     make([]*apiDrift, 0, len(summary.Drifts)),
+		Total:      summary.TotalSkills,
+		Successful: summary.SuccessCount,
+		Failed:     summary.FailureCount,
+	}
+	for _, result := range summary.Results {
+		resp.Results = append(resp.Results, &apiVerifyResult{
+			SkillName:  result.SkillName,
+			InstallDir: result.InstallDir,
+			Expected:   result.Expected,
+			Actual:     result.Actual,
+			Match:      result.Match,
+			Drifted:    result.Drifted,
+		})
+	}
+	for _, drift := range summary.Drifts {
+		resp.Drifts = append(resp.Drifts, &apiDrift{
+			Kind:       string(drift.Kind),
+			SkillName:  drift.SkillName,
+			Target:     drift.Target,
+			Configured: drift.Configured,
+			Locked:     drift.Locked,
+		})
+	}
+
+	return resp
+}
+
+// fail logs the error of a request and writes it as the response.
+func (a *apiServer) fail(w http.ResponseWriter, r *http.Request, err error) {
+	a.logger.Error("%s %s failed: %v", r.Method, r.URL.Path, err)
+
+	status := http.StatusInternalServerError
+	if _, ok := errors.AsType[*domain.ErrorSkillsNotFound](err); ok {
+		status = http.StatusNotFound
+	}
+	writeAPIError(w, status, err)
+}
+
+// readAPIRequest decodes the optional JSON body of a request.
+func readAPIRequest(r *http.Request) (*apiSkillsRequest, error) {
+	var req apiSkillsRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxRequestBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	return &req, nil
+}
+
+// writeAPIResponse writes v as the JSON response body.
+func writeAPIResponse(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError writes err as a JSON error response.
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeAPIResponse(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+
+	skillspkgv1 "github.com/mazrean/skills-pkg/api/skillspkg/v1"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcServer returns the gRPC API, which requires the token on every call,
+// served over TLS when tlsConfig is not nil.
+func (c *ServeCmd) grpcServer(api *apiServer, tlsConfig *tls.Config) *grpc.Server {
+	authorize := func(ctx context.Context, method string) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, value := range md.Get("authorization") {
+			if validBearerToken(value, c.Token) {
+				api.logger.Verbose("gRPC %s", method)
+				return nil
+			}
+		}
+		api.logger.Verbose("Rejected unauthenticated gRPC call: %s", method)
+		return status.Error(codes.Unauthenticated, "missing or invalid API token")
+	}
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := authorize(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(options...)
+	skillspkgv1.RegisterSkillsServiceServer(server, &skillsService{api: api})
+
+	return server
+}
+
+// skillsService implements skillspkgv1.SkillsServiceServer on top of apiServer.
+type skillsService struct {
+	skillspkgv1.UnimplementedSkillsServiceServer
+
+	api *apiServer
+}
+
+// ListSkills returns the configured skills and their state in each install target.
+func (s *skillsService) ListSkills(ctx context.Context, _ *skillspkgv1.ListSkillsRequest) (*skillspkgv1.ListSkillsResponse, error) {
+	skills, err := s.api.listSkills(ctx)
+	if err != nil {
+		return nil, s.fail("ListSkills", err)
+	}
+
+	resp := &skillspkgv1.ListSkillsResponse{}
+	for _, skill := range skills {
+		item := &skillspkgv1.Skill{Name: skill.Name, Source: skill.Source, Url: skill.URL, Version: skill.Version}
+		for _, target := range skill.Targets {
+			targetItem := &skillspkgv1.SkillTarget{Path: target.Path, Status: target.Status, Version: target.Version}
+			if target.InstalledAt != nil {
+				targetItem.InstalledAt = timestamppb.New(*target.InstalledAt)
+			}
+			item.Targets = append(item.Targets, targetItem)
+		}
+		resp.Skills = append(resp.Skills, item)
+	}
+
+	return resp, nil
+}
+
+// Install installs skills, streaming progress messages before the result.
+func (s *skillsService) Install(req *skillspkgv1.InstallRequest, stream grpc.ServerStreamingServer[skillspkgv1.InstallResponse]) error {
+	progress := newProgressStream(func(message string) error {
+		return stream.Send(&skillspkgv1.InstallResponse{Event: &skillspkgv1.InstallResponse_Progress{
+			Progress: &skillspkgv1.Progress{Message: message},
+		}})
+	})
+
+	installed, err := s.api.installSkills(stream.Context(), req.GetSkills(), progress)
+	progress.Flush()
+	if err != nil {
+		return s.fail("Install", err)
+	}
+
+	return stream.Send(&skillspkgv1.InstallResponse{Event: &skillspkgv1.InstallResponse_Result{
+		Result: &skillspkgv1.InstallResult{Installed: installed},
+	}})
+}
+
+// Update updates skills, streaming progress messages before the result.
+func (s *skillsService) Update(req *skillspkgv1.UpdateRequest, stream grpc.ServerStreamingServer[skillspkgv1.UpdateResponse]) error {
+	progress := newProgressStream(func(message string) error {
+		return stream.Send(&skillspkgv1.UpdateResponse{Event: &skillspkgv1.UpdateResponse_Progress{
+			Progress: &skillspkgv1.Progress{Message: message},
+		}})
+	})
+
+	results, err := s.api.updateSkills(stream.Context(), req.GetSkills(), req.GetDryRun(), progress)
+	progress.Flush()
+	if err != nil {
+		return s.fail("Update", err)
+	}
+
+	result := &skillspkgv1.UpdateResult{}
+	for _, item := range newDryRunOutput(results).Updates {
+		update := &skillspkgv1.SkillUpdate{
+			SkillName:      item.SkillName,
+			CurrentVersion: item.CurrentVersion,
+			LatestVersion:  item.LatestVersion,
+			HasUpdate:      item.HasUpdate,
+			HeldBack:       item.HeldBack,
+			Canary:         item.Canary,
+		}
+		for _, fd := range item.FileDiffs {
+			update.FileDiffs = append(update.FileDiffs, &skillspkgv1.FileDiff{Path: fd.Path, Status: fd.Status, Patch: fd.Patch})
+		}
+		result.Updates = append(result.Updates, update)
+	}
+
+	return stream.Send(&skillspkgv1.UpdateResponse{Event: &skillspkgv1.UpdateResponse_Result{Result: result}})
+}
+
+// Verify verifies the installed skills against their recorded hashes.
+func (s *skillsService) Verify(ctx context.Context, _ *skillspkgv1.VerifyRequest) (*skillspkgv1.VerifyResponse, error) {
+	summary, err := s.api.verifySkills(ctx)
+	if err != nil {
+		return nil, s.fail("Verify", err)
+	}
+
+	resp := &skillspkgv1.VerifyResponse{
+		Total:      int32(summary.TotalSkills),
+		Successful: int32(summary.SuccessCount),
+		Failed:     int32(summary.FailureCount),
+	}
+	for _, result := range summary.Results {
+		resp.Results = append(resp.Results, &skillspkgv1.VerifyResult{
+			SkillName:  result.SkillName,
+			InstallDir: result.InstallDir,
+			Expected:   result.Expected,
+			Actual:     result.Actual,
+			Match:      result.Match,
+			Drifted:    result.Drifted,
+		})
+	}
+	for _, drift := range summary.Drifts {
+		resp.Drifts = append(resp.Drifts, &skillspkgv1.ConfigDrift{
+			Kind:       string(drift.Kind),
+			SkillName:  drift.SkillName,
+			Target:     drift.Target,
+			Configured: drift.Configured,
+			Locked:     drift.Locked,
+		})
+	}
+
+	return resp, nil
+}
+
+// fail logs the error of a call and converts it into a gRPC status.
+func (s *skillsService) fail(method string, err error) error {
+	s.api.logger.Error("gRPC %s failed: %v", method, err)
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	default:
+		if _, ok := errors.AsType[*domain.ErrorSkillsNotFound](err); ok {
+			code = codes.NotFound
+		}
+	}
+	return status.Error(code, err.Error())
+}
+
+// progressStream is an io.Writer that sends each line written to it as a progress message.
+// It is safe for concurrent use, as the SkillManager reports progress from parallel installs.
+type progressStream struct {
+	send func(message string) error
+	buf  []byte
+	mu   sync.Mutex
+}
+
+func newProgressStream(send func(message string) error) *progressStream {
+	return &progressStream{send: send}
+}
+
+// Write sends the complete lines in p and keeps the rest until the next write.
+// Failures to send are ignored: the client has gone away, and the operation is canceled with its context.
+func (p *progressStream) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		_ = p.send(string(p.buf[:i]))
+		p.buf = p.buf[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush sends the last line when it was not terminated by a newline.
+func (p *progressStream) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.buf) > 0 {
+		_ = p.send(string(p.buf))
+		p.buf = nil
+	}
+}
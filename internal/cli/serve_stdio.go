@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// JSON-RPC 2.0 error codes used by the stdio API.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	rpcSkillNotFound  = -32001 // A requested skill is not in the configuration
+)
+
+// rpcRequest is a JSON-RPC 2.0 request, or a notification when it has no id.
+type rpcRequest struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response. Exactly one of Result and Error is set.
+type rpcResponse struct {
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	JSONRPC string          `json:"jsonrpc"`
+}
+
+// rpcNotification is a JSON-RPC 2.0 notification sent by the server.
+type rpcNotification struct {
+	Params  any    `json:"params"`
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+}
+
+type rpcError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+// rpcConn reads and writes JSON-RPC messages framed with Content-Length headers,
+// as in the Language Server Protocol, so that editor extensions can use their LSP client libraries.
+type rpcConn struct {
+	reader *textproto.Reader
+	out    io.Writer
+	mu     sync.Mutex
+}
+
+func newRPCConn(in io.Reader, out io.Writer) *rpcConn {
+	return &rpcConn{reader: textproto.NewReader(bufio.NewReader(in)), out: out}
+}
+
+// read returns the body of the next message, or io.EOF when the input is closed.
+func (c *rpcConn) read() ([]byte, error) {
+	header, err := c.reader.ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read message header: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 || length > maxRequestBodySize {
+		return nil, fmt.Errorf("invalid Content-Length header %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader.R, body); err != nil {
+		return nil, fmt.Errorf("failed to read message body: %w", err)
+	}
+	return body, nil
+}
+
+// write sends a response or notification. It is safe for concurrent use.
+func (c *rpcConn) write(msg any) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.out.Write(body)
+	return err
+}
+
+// serveStdio serves JSON-RPC 2.0 on in and out until in is closed.
+// Requests are handled in order; install and update report progress with "skills/progress" notifications.
+func (c *ServeCmd) serveStdio(ctx context.Context, api *apiServer, in io.Reader, out io.Writer) error {
+	conn := newRPCConn(in, out)
+
+	for {
+		body, err := conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// The framing is broken, so the rest of the input cannot be read either
+			_ = conn.write(&rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			return err
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			_ = conn.write(&rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			_ = conn.write(&rpcResponse{JSONRPC: "2.0", ID: idOrNull(req.ID), Error: &rpcError{Code: rpcInvalidRequest, Message: "not a JSON-RPC 2.0 request"}})
+			continue
+		}
+
+		result, rpcErr := c.handleRPC(ctx, api, conn, &req)
+		// Notifications get no response
+		if req.ID == nil {
+			continue
+		}
+		resp := &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}
+		if rpcErr == nil && result == nil {
+			resp.Result = struct{}{}
+		}
+		if err := conn.write(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+}
+
+// handleRPC calls the method of the request and returns its result.
+func (c *ServeCmd) handleRPC(ctx context.Context, api *apiServer, conn *rpcConn, req *rpcRequest) (any, *rpcError) {
+	api.logger.Verbose("JSON-RPC %s", req.Method)
+
+	var params apiSkillsRequest
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+		}
+	}
+	progress := newProgressStream(func(message string) error {
+		return conn.write(&rpcNotification{JSONRPC: "2.0", Method: "skills/progress", Params: map[string]string{"message": message}})
+	})
+
+	var result any
+	var err error
+	switch req.Method {
+	case "skills/list":
+		var skills []*apiSkill
+		skills, err = api.listSkills(ctx)
+		result = map[string]any{"skills": skills}
+	case "skills/install":
+		var installed []string
+		installed, err = api.installSkills(ctx, params.Skills, progress)
+		progress.Flush()
+		result = &apiInstallResponse{Installed: installed}
+	case "skills/update":
+		var results []*domain.UpdateResult
+		results, err = api.updateSkills(ctx, params.Skills, params.DryRun, progress)
+		progress.Flush()
+		result = newDryRunOutput(results)
+	case "skills/verify":
+		var summary *domain.VerifySummary
+		summary, err = api.verifySkills(ctx)
+		if err == nil {
+			result = newVerifyResponse(summary)
+		}
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method '%s' not found", req.Method)}
+	}
+	if err != nil {
+		api.logger.Error("JSON-RPC %s failed: %v", req.Method, err)
+		code := rpcInternalError
+		if _, ok := errors.AsType[*domain.ErrorSkillsNotFound](err); ok {
+			code = rpcSkillNotFound
+		}
+		return nil, &rpcError{Code: code, Message: err.Error()}
+	}
+
+	return result, nil
+}
+
+// idOrNull returns the id of a request, or null when the request has none.
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -0,0 +1,497 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	skillspkgv1 "github.com/mazrean/skills-pkg/api/skillspkg/v1"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServeCmd_HTTP(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	installDir := filepath.Join(filepath.Dir(configPath), "install")
+
+	downloadDir := t.TempDir()
+	skill := &domain.Skill{Name: "test-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0", SubDir: "skills/test-skill"}
+	if err := os.MkdirAll(filepath.Join(downloadDir, skill.SubDir), 0o755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	if err := domain.NewConfigManager(configPath).AddSkill(context.Background(), skill); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+
+	var errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &errOut, errOut: &errOut}
+	cmd := &ServeCmd{Token: "secret"}
+	api := cmd.newAPIServer(configPath, logger, &mockHashService{}, []port.PackageManager{
+		&mockPackageManager{sourceType: "git", tmpDir: downloadDir},
+	})
+	server := httptest.NewServer(cmd.httpHandler(api))
+	defer server.Close()
+
+	// do sends a request to the API and decodes the JSON response into v
+	do := func(method, path, token, body string, v any) int {
+		t.Helper()
+		req, err := http.NewRequestWithContext(context.Background(), method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", method, path, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if v != nil {
+			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+				t.Fatalf("failed to decode response of %s %s: %v", method, path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	if status := do(http.MethodGet, "/v1/skills", "", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("request without token status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := do(http.MethodGet, "/v1/skills", "wrong", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("request with a wrong token status = %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	var apiErr map[string]string
+	if status := do(http.MethodPost, "/v1/install", "secret", `{"skills":["missing-skill"]}`, &apiErr); status != http.StatusNotFound || apiErr["error"] == "" {
+		t.Errorf("install of a missing skill status = %d, body = %v", status, apiErr)
+	}
+
+	var installed apiInstallResponse
+	if status := do(http.MethodPost, "/v1/install", "secret", `{"skills":["test-skill"]}`, &installed); status != http.StatusOK {
+		t.Fatalf("install status = %d, stderr = %s", status, errOut.String())
+	}
+	if len(installed.Installed) != 1 || installed.Installed[0] != "test-skill" {
+		t.Errorf("installed = %v, want [test-skill]", installed.Installed)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "test-skill")); err != nil {
+		t.Errorf("test-skill should be installed: %v", err)
+	}
+
+	var listed struct {
+		Skills []*apiSkill `json:"skills"`
+	}
+	if status := do(http.MethodGet, "/v1/skills", "secret", "", &listed); status != http.StatusOK {
+		t.Fatalf("list status = %d", status)
+	}
+	if len(listed.Skills) != 1 || len(listed.Skills[0].Targets) != 1 {
+		t.Fatalf("listed skills = %+v", listed.Skills)
+	}
+	if target := listed.Skills[0].Targets[0]; target.Path != installDir || target.Version != "v1.0.0" || target.Status != string(domain.TargetUpToDate) {
+		t.Errorf("listed target = %+v", target)
+	}
+
+	var verified apiVerifyResponse
+	if status := do(http.MethodPost, "/v1/verify", "secret", "", &verified); status != http.StatusOK {
+		t.Fatalf("verify status = %d", status)
+	}
+	if verified.Total != 1 || verified.Failed != 0 {
+		t.Errorf("verify response = %+v", verified)
+	}
+
+	if status := do(http.MethodPost, "/v1/update", "secret", `{"unknown":true}`, &apiErr); status != http.StatusBadRequest {
+		t.Errorf("update with an invalid body status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	rec := httptest.NewRecorder()
+	metricsHandler(api.metrics.families).ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`skillspkg_operations_total{operation="install"} 2`,
+		`skillspkg_operation_failures_total{operation="install"} 1`,
+		`skillspkg_operations_total{operation="verify"} 1`,
+		`skillspkg_verify_mismatches_total 0`,
+	} {
+		if !strings.Contains(rec.Body.String(), want+"\n") {
+			t.Errorf("metrics should contain %q, got:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+func TestServeCmd_GRPC(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	installDir := filepath.Join(filepath.Dir(configPath), "install")
+
+	downloadDir := t.TempDir()
+	skill := &domain.Skill{Name: "test-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0", SubDir: "skills/test-skill"}
+	if err := os.MkdirAll(filepath.Join(downloadDir, skill.SubDir), 0o755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	if err := domain.NewConfigManager(configPath).AddSkill(context.Background(), skill); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+
+	var errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &errOut, errOut: &errOut}
+	cmd := &ServeCmd{Token: "secret"}
+	server := cmd.grpcServer(cmd.newAPIServer(configPath, logger, &mockHashService{}, []port.PackageManager{
+		&mockPackageManager{sourceType: "git", tmpDir: downloadDir},
+	}), nil)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	client := skillspkgv1.NewSkillsServiceClient(conn)
+
+	if _, err := client.ListSkills(context.Background(), &skillspkgv1.ListSkillsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListSkills() without token error = %v, want Unauthenticated", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	stream, err := client.Install(ctx, &skillspkgv1.InstallRequest{Skills: []string{"test-skill"}})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	var progress []string
+	var result *skillspkgv1.InstallResult
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Install() stream error = %v, stderr = %s", err, errOut.String())
+		}
+		if p := resp.GetProgress(); p != nil {
+			progress = append(progress, p.GetMessage())
+		}
+		if r := resp.GetResult(); r != nil {
+			result = r
+		}
+	}
+	if !slices.Contains(progress, "Successfully installed skill 'test-skill'") {
+		t.Errorf("Install() progress = %q, want the installation message", progress)
+	}
+	if result == nil || !slices.Equal(result.GetInstalled(), []string{"test-skill"}) {
+		t.Errorf("Install() result = %v, want [test-skill]", result)
+	}
+
+	listed, err := client.ListSkills(ctx, &skillspkgv1.ListSkillsRequest{})
+	if err != nil {
+		t.Fatalf("ListSkills() error = %v", err)
+	}
+	if len(listed.GetSkills()) != 1 || len(listed.GetSkills()[0].GetTargets()) != 1 {
+		t.Fatalf("ListSkills() = %v", listed)
+	}
+	if target := listed.GetSkills()[0].GetTargets()[0]; target.GetPath() != installDir || target.GetStatus() != string(domain.TargetUpToDate) || target.GetInstalledAt() == nil {
+		t.Errorf("listed target = %v", target)
+	}
+
+	missing, err := client.Install(ctx, &skillspkgv1.InstallRequest{Skills: []string{"missing-skill"}})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	for err == nil {
+		_, err = missing.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Install() of a missing skill error = %v, want NotFound", err)
+	}
+}
+
+func TestServeCmd_Stdio(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	downloadDir := t.TempDir()
+	skill := &domain.Skill{Name: "test-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0", SubDir: "skills/test-skill"}
+	if err := os.MkdirAll(filepath.Join(downloadDir, skill.SubDir), 0o755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	if err := domain.NewConfigManager(configPath).AddSkill(context.Background(), skill); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+
+	var in bytes.Buffer
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"skills/install","params":{"skills":["test-skill"]}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"skills/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"skills/verify"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"skills/install","params":{"skills":["missing-skill"]}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"skills/unknown"}`,
+		`{"jsonrpc":"2.0","method":"skills/list"}`,
+	} {
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+
+	var out, errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &errOut, errOut: &errOut}
+	cmd := &ServeCmd{Stdio: true}
+	api := cmd.newAPIServer(configPath, logger, &mockHashService{}, []port.PackageManager{
+		&mockPackageManager{sourceType: "git", tmpDir: downloadDir},
+	})
+	if err := cmd.serveStdio(context.Background(), api, &in, &out); err != nil {
+		t.Fatalf("serveStdio() error = %v, stderr = %s", err, errOut.String())
+	}
+
+	type message struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	conn := newRPCConn(&out, io.Discard)
+	responses := map[string]*message{}
+	progress := 0
+	for {
+		body, err := conn.read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid message %s: %v", body, err)
+		}
+		if msg.Method == "skills/progress" {
+			progress++
+			continue
+		}
+		responses[string(msg.ID)] = &msg
+	}
+
+	if progress == 0 {
+		t.Error("install should report progress notifications")
+	}
+	if len(responses) != 5 {
+		t.Errorf("got %d responses, want 5 (notifications get no response)", len(responses))
+	}
+	if resp := responses["1"]; resp == nil || resp.Error != nil || !strings.Contains(string(resp.Result), `"test-skill"`) {
+		t.Errorf("install response = %+v", resp)
+	}
+	if resp := responses["2"]; resp == nil || !strings.Contains(string(resp.Result), `"up-to-date"`) {
+		t.Errorf("list response = %+v", resp)
+	}
+	if resp := responses["3"]; resp == nil || !strings.Contains(string(resp.Result), `"failed":0`) {
+		t.Errorf("verify response = %+v", resp)
+	}
+	if resp := responses["4"]; resp == nil || resp.Error == nil || resp.Error.Code != rpcSkillNotFound {
+		t.Errorf("install of a missing skill response = %+v", resp)
+	}
+	if resp := responses["5"]; resp == nil || resp.Error == nil || resp.Error.Code != rpcMethodNotFound {
+		t.Errorf("unknown method response = %+v", resp)
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1 and its key, and returns their paths.
+func writeTestCertificate(t *testing.T) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "skills-pkg"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certPath, keyPath = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func TestListenAPI(t *testing.T) {
+	certPath, keyPath := writeTestCertificate(t)
+	tlsConfig, err := (&ServeCmd{TLSCert: certPath, TLSKey: keyPath}).tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		addr      string
+		tlsConfig *tls.Config
+		wantHost  string
+		wantErr   bool
+	}{
+		{name: "no host listens on loopback", addr: ":0", wantHost: "127.0.0.1"},
+		{name: "localhost", addr: "localhost:0", wantHost: "127.0.0.1"},
+		{name: "all interfaces without TLS", addr: "0.0.0.0:0", wantErr: true},
+		{name: "all interfaces with TLS", addr: "0.0.0.0:0", tlsConfig: tlsConfig, wantHost: "0.0.0.0"},
+		{name: "no port", addr: "127.0.0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := listenAPI(tt.addr, tt.tlsConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listenAPI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer func() { _ = listener.Close() }()
+
+			host, _, _ := net.SplitHostPort(listener.Addr().String())
+			if tt.wantHost == "0.0.0.0" {
+				if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+					t.Errorf("listener address = %s, want all interfaces", listener.Addr())
+				}
+				return
+			}
+			if host != tt.wantHost {
+				t.Errorf("listener address = %s, want %s", listener.Addr(), tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestServeCmd_TLS(t *testing.T) {
+	certPath, keyPath := writeTestCertificate(t)
+	cmd := &ServeCmd{Token: "secret", TLSCert: certPath, TLSKey: keyPath}
+	tlsConfig, err := cmd.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+
+	listener, err := listenAPI("127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("listenAPI() error = %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(w, http.StatusOK, map[string]bool{"tls": r.TLS != nil})
+	})}
+	go func() { _ = server.Serve(tls.NewListener(listener, tlsConfig)) }()
+	defer func() { _ = server.Close() }()
+
+	pool := x509.NewCertPool()
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.AppendCertsFromPEM(certPEM)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + listener.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("GET over TLS error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var body map[string]bool
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || !body["tls"] {
+		t.Errorf("response = %v, %v, want a request over TLS", body, err)
+	}
+
+	if _, err := (&ServeCmd{TLSCert: keyPath, TLSKey: keyPath}).tlsConfig(); err == nil {
+		t.Error("tlsConfig() with a key as the certificate should fail")
+	}
+}
+
+func TestServeCmd_GRPCTLS(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	certPath, keyPath := writeTestCertificate(t)
+	cmd := &ServeCmd{Token: "secret", TLSCert: certPath, TLSKey: keyPath}
+	tlsConfig, err := cmd.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+	listener, err := listenAPI("127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("listenAPI() error = %v", err)
+	}
+	var errOut bytes.Buffer
+	logger := &Logger{out: &errOut, dataOut: &errOut, errOut: &errOut}
+	server := cmd.grpcServer(cmd.newAPIServer(configPath, logger, &mockHashService{}, nil), tlsConfig)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+
+	for _, tt := range []struct {
+		name  string
+		creds grpc.DialOption
+		want  codes.Code
+	}{
+		{name: "TLS", creds: grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool})), want: codes.OK},
+		{name: "cleartext", creds: grpc.WithTransportCredentials(insecure.NewCredentials()), want: codes.Unavailable},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := grpc.NewClient(listener.Addr().String(), tt.creds)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+			_, err = skillspkgv1.NewSkillsServiceClient(conn).ListSkills(ctx, &skillspkgv1.ListSkillsRequest{})
+			if code := status.Code(err); code != tt.want {
+				t.Errorf("ListSkills() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
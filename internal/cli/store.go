@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// StoreCmd represents the store command group
+type StoreCmd struct {
+	Prune StorePruneCmd `cmd:"" help:"Delete shared store entries that no project links to anymore"`
+}
+
+// StorePruneCmd represents the store prune command
+type StorePruneCmd struct{}
+
+// Run executes the store prune command
+func (c *StorePruneCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	logger := NewLogger(verbose)
+
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+
+	return c.runWithStore(domain.NewStore(dirs.StoreDir()), logger)
+}
+
+// runWithStore prunes the given store (for testing)
+func (c *StorePruneCmd) runWithStore(store *domain.Store, logger *Logger) error {
+	logger.Verbose("Pruning shared store at %s", store.Root())
+
+	removed, err := store.Prune()
+	for _, key := range removed {
+		logger.Verbose("Deleted store entry %s", key)
+	}
+	if err != nil {
+		logger.Error("Failed to prune shared store: %v", err)
+		return err
+	}
+
+	logger.Info("Deleted %d unused store entries", len(removed))
+
+	return nil
+}
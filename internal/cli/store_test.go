@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestStorePruneCmd_Run(t *testing.T) {
+	root := t.TempDir()
+
+	// An entry without any reference is unused
+	entry := filepath.Join(root, "entries", "h1-00")
+	if err := os.MkdirAll(entry, 0o755); err != nil {
+		t.Fatalf("failed to create store entry: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := &Logger{out: &buf, dataOut: &buf, errOut: &buf}
+	cmd := &StorePruneCmd{}
+
+	if err := cmd.runWithStore(domain.NewStore(root), logger); err != nil {
+		t.Fatalf("runWithStore() error = %v\noutput: %s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Deleted 1 unused store entries") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+	if _, err := os.Stat(entry); !os.IsNotExist(err) {
+		t.Errorf("unused entry should be deleted, stat error = %v", err)
+	}
+}
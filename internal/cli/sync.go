@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/agent"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// SyncCmd represents the sync command
+type SyncCmd struct {
+	CheckTargets bool `help:"Warn about install targets that do not look like the skills directory of an installed agent" name:"check-targets" default:"false"`
+	Concurrency  int  `help:"Maximum number of skills downloaded and installed at the same time (default 8)" env:"SKILLSPKG_CONCURRENCY" placeholder:"N"`
+
+	allowRoot     bool // Set from the global --allow-root flag
+	downloadCache bool // Set by Run to reuse downloads from the user cache directory
+}
+
+// Run executes the sync command
+func (c *SyncCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *SyncCmd) run(configPath string, verbose bool) error {
+	logger := NewLogger(verbose)
+	notifier := newOperationNotifier(logger)
+
+	logger.Info("Syncing install targets with configuration")
+
+	configManager := newConfigManager(configPath)
+	packageManagers := pkgmanager.All()
+
+	if c.CheckTargets {
+		if config, err := configManager.Load(context.Background()); err == nil {
+			warnTargets(logger, config.InstallTargets, agent.All())
+		}
+	}
+
+	opts := append(skillManagerOptions(c.allowRoot, c.downloadCache), domain.WithConcurrency(c.Concurrency))
+	skillManager := domain.NewSkillManager(configManager, service.NewDirhash(), packageManagers, opts...)
+
+	if err := c.sync(logger, skillManager); err != nil {
+		c.handleSyncError(logger, err)
+		notifier.completed("skills-pkg sync failed", err.Error())
+		return err
+	}
+
+	notifier.completed("skills-pkg sync", "Sync complete")
+	return nil
+}
+
+// sync installs missing and drifted skills, then removes installations
+// that are no longer in the configuration.
+func (c *SyncCmd) sync(logger *Logger, skillManager domain.SkillManager) error {
+	ctx := context.Background()
+
+	// Skills that are up to date in every target are skipped, so running sync repeatedly is cheap
+	logger.Verbose("Installing missing and drifted skills")
+	if err := skillManager.Install(ctx, ""); err != nil {
+		return err
+	}
+
+	logger.Verbose("Removing skills that are no longer in the configuration")
+	pruned, err := skillManager.Prune(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Sync complete: removed %d installation(s) no longer in the configuration", len(pruned))
+	return nil
+}
+
+// handleSyncError reports a failed sync with its cause and a recommended action.
+func (c *SyncCmd) handleSyncError(logger *Logger, err error) {
+	if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+		logger.Error("Configuration file not found at %s", err.Path)
+		logger.Error("Run 'skills-pkg init' to create a configuration file")
+		return
+	}
+
+	if handlePermissionError(logger, err) {
+		return
+	}
+
+	logger.Error("Failed to sync install targets: %v", err)
+	logger.Error("Check network connection, file permissions, and try again")
+}
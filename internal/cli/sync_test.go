@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestSyncCmd_Run(t *testing.T) {
+	t.Run("error: config file not found", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+
+		err := (&SyncCmd{}).run(configPath, false)
+		if _, ok := errors.AsType[*domain.ErrorConfigNotFound](err); !ok {
+			t.Errorf("run() error = %v, want *domain.ErrorConfigNotFound", err)
+		}
+	})
+
+	t.Run("removes skills no longer in configuration", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+		installDir := filepath.Join(tmpDir, "skills")
+		ctx := context.Background()
+
+		if err := domain.NewConfigManager(configPath).Initialize(ctx, []string{installDir}); err != nil {
+			t.Fatalf("failed to initialize config: %v", err)
+		}
+		for _, name := range []string{"removed-skill", "manual-skill"} {
+			if err := os.MkdirAll(filepath.Join(installDir, name), 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+		}
+		if err := domain.NewLockManager(domain.LockPathFor(configPath)).Update(ctx, func(lock *domain.LockFile) {
+			lock.RecordInstall("removed-skill", &domain.TargetStatus{Path: installDir})
+		}); err != nil {
+			t.Fatalf("failed to write lock file: %v", err)
+		}
+
+		if err := (&SyncCmd{}).run(configPath, false); err != nil {
+			t.Fatalf("run() error = %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(installDir, "removed-skill")); !os.IsNotExist(err) {
+			t.Errorf("removed-skill should have been deleted, stat error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(installDir, "manual-skill")); err != nil {
+			t.Errorf("manual-skill was not installed by skills-pkg and should be kept: %v", err)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/agent"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TargetCmd represents the target command group
+type TargetCmd struct {
+	Add     TargetAddCmd     `cmd:"" help:"Add install targets to configuration, optionally installing the configured skills into them"`
+	Remove  TargetRemoveCmd  `cmd:"" help:"Remove an install target from configuration, optionally deleting the skills installed in it"`
+	Migrate TargetMigrateCmd `cmd:"" help:"Move the skills installed in an install target to another directory and update configuration"`
+}
+
+// TargetAddCmd represents the target add command
+type TargetAddCmd struct {
+	Dir          []string `arg:"" optional:"" help:"Install target directory (can be specified multiple times)"`
+	Agent        []string `help:"Agent name to use default directory (can be specified multiple times)" short:"a" enum:"claude,claude-code,codex,cursor,copilot,github-copilot,goose,opencode,gemini,gemini-cli,amp,kimi-cli,replit,universal,factory,droid,antigravity,augment,openclaw,cline,codebuddy,command-code,continue,cortex,crush,junie,iflow-cli,kilo,kiro-cli,kode,mcpjam,mistral-vibe,mux,openhands,pi,qoder,qwen-code,roo,trae,trae-cn,windsurf,zencoder,neovate,pochi,adal"`
+	Global       bool     `help:"Use user-level directory instead of project-level directory (requires --agent)" short:"g" default:"false"`
+	Install      bool     `help:"Install the configured skills into the added targets" default:"false"`
+	CheckTargets bool     `help:"Warn about install targets that do not look like the skills directory of an installed agent" name:"check-targets" default:"false"`
+
+	allowRoot     bool // Set from the global --allow-root flag
+	downloadCache bool // Set by Run to reuse downloads from the user cache directory
+}
+
+// Run executes the target add command
+func (c *TargetAddCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+	for i, dir := range c.Dir {
+		c.Dir[i] = projectPath(dir)
+	}
+
+	hashService := service.NewDirhash()
+	packageManagers := pkgmanager.All()
+
+	return c.runWithDeps(defaultConfigPath, NewLogger(verbose), hashService, packageManagers)
+}
+
+// runWithDeps is the internal implementation with injectable dependencies for testing
+func (c *TargetAddCmd) runWithDeps(configPath string, logger *Logger, hashService port.HashService, packageManagers []port.PackageManager) error {
+	if len(c.Dir) == 0 && len(c.Agent) == 0 {
+		err := errors.New("no install targets specified")
+		logger.Error("%v", err)
+		logger.Error("Provide a directory or use --agent")
+		return err
+	}
+
+	// The agent directories are resolved as in init
+	targets, err := (&InitCmd{InstallDir: c.Dir, Agent: c.Agent, Global: c.Global}).buildInstallTargets(logger)
+	if err != nil {
+		logger.Error("Failed to build install targets: %v", err)
+		return err
+	}
+
+	if c.CheckTargets {
+		warnTargets(logger, targets, agent.All())
+	}
+
+	configManager := newConfigManager(configPath)
+	added, err := configManager.MergeInstallTargets(context.Background(), targets)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", e.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to update configuration: %v", err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+
+	if len(added) == 0 {
+		logger.Info("All install targets are already configured")
+		return nil
+	}
+	for _, target := range added {
+		logger.Info("Added install target '%s'", target)
+	}
+
+	if !c.Install {
+		logger.Info("Run 'skills-pkg install' to install the configured skills into the new targets")
+		return nil
+	}
+
+	// Skills that are up to date in the other targets are only copied into the new ones
+	logger.Info("Installing the configured skills into the new targets")
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, skillManagerOptions(c.allowRoot, c.downloadCache)...)
+	if err := skillManager.Install(context.Background(), ""); err != nil {
+		logger.Error("Failed to install skills: %v", err)
+		logger.Error("The install targets were added; run 'skills-pkg install' to retry")
+		handlePermissionError(logger, err)
+		return err
+	}
+	logger.Info("Successfully installed the configured skills")
+
+	return nil
+}
+
+// TargetRemoveCmd represents the target remove command
+type TargetRemoveCmd struct {
+	Dir   string `arg:"" help:"Install target directory to remove"`
+	Clean bool   `help:"Delete the skills that skills-pkg installed in the directory" default:"false"`
+
+	allowRoot bool // Set from the global --allow-root flag
+	backups   bool // Set by Run to back up the deleted skills in the user state directory
+}
+
+// Run executes the target remove command
+func (c *TargetRemoveCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+	c.backups = true
+
+	c.Dir = projectPath(c.Dir)
+	return c.runWithDeps(defaultConfigPath, NewLogger(verbose), service.NewDirhash())
+}
+
+// runWithDeps is the internal implementation with injectable dependencies for testing
+func (c *TargetRemoveCmd) runWithDeps(configPath string, logger *Logger, hashService port.HashService) error {
+	configManager := newConfigManager(configPath)
+	removed, err := configManager.RemoveInstallTarget(context.Background(), c.Dir)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", e.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		if e, ok := errors.AsType[*domain.ErrorInstallTargetNotFound](err); ok {
+			logger.Error("Install target '%s' not found in configuration", e.Target)
+			return err
+		}
+		logger.Error("Failed to update configuration: %v", err)
+		logger.Error("Check file permissions and try again")
+		return err
+	}
+	logger.Info("Removed install target '%s' from configuration", removed)
+
+	if !c.Clean {
+		logger.Info("Skills installed in %s were left in place. Use --clean to delete them", removed)
+		return nil
+	}
+
+	// Nothing is downloaded, so no package managers are needed
+	opts := skillManagerOptions(c.allowRoot, false)
+	if c.backups {
+		opts = append(opts, backupOptions()...)
+	}
+	skillManager := domain.NewSkillManager(configManager, hashService, nil, opts...)
+	pruned, err := skillManager.PruneTarget(context.Background(), removed)
+	if err != nil {
+		logger.Error("Failed to delete skills from %s: %v", removed, err)
+		handlePermissionError(logger, err)
+		return err
+	}
+	logger.Info("Deleted %d skill(s) from %s", len(pruned), removed)
+
+	return nil
+}
+
+// TargetMigrateCmd represents the target migrate command
+type TargetMigrateCmd struct {
+	From string `arg:"" help:"Install target directory to move the skills from"`
+	To   string `arg:"" help:"Directory to move the skills to, which replaces the install target in configuration"`
+
+	allowRoot bool // Set from the global --allow-root flag
+}
+
+// Run executes the target migrate command
+func (c *TargetMigrateCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.allowRoot = allowRootFlag(ctx)
+
+	c.From, c.To = projectPath(c.From), projectPath(c.To)
+	return c.runWithDeps(defaultConfigPath, NewLogger(verbose), service.NewDirhash())
+}
+
+// runWithDeps is the internal implementation with injectable dependencies for testing
+func (c *TargetMigrateCmd) runWithDeps(configPath string, logger *Logger, hashService port.HashService) error {
+	configManager := newConfigManager(configPath)
+	// Nothing is downloaded, so no package managers are needed
+	skillManager := domain.NewSkillManager(configManager, hashService, nil, skillManagerOptions(c.allowRoot, false)...)
+
+	logger.Verbose("Migrating install target %s to %s", c.From, c.To)
+	moved, err := skillManager.MigrateTarget(context.Background(), c.From, c.To)
+	if err != nil {
+		if e, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", e.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		if e, ok := errors.AsType[*domain.ErrorInstallTargetNotFound](err); ok {
+			logger.Error("Install target '%s' not found in configuration", e.Target)
+			return err
+		}
+		if e, ok := errors.AsType[*domain.ErrorInstallTargetExists](err); ok {
+			logger.Error("Install target '%s' already exists in configuration", e.Target)
+			return err
+		}
+		logger.Error("Failed to migrate install target: %v", err)
+		handlePermissionError(logger, err)
+		return err
+	}
+
+	logger.Info("Moved %d skill(s) from %s to %s", len(moved), c.From, c.To)
+	logger.Info("Install target '%s' was replaced with '%s' in configuration", c.From, c.To)
+
+	return nil
+}
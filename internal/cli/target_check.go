@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// maxTargetTypoDistance is the largest edit distance between an install target and a known
+// agent directory for the target to be reported as a likely typo.
+const maxTargetTypoDistance = 2
+
+// knownTarget is an install directory that skills-pkg knows to belong to an agent.
+type knownTarget struct {
+	path      string // Absolute path of the skills directory
+	agentRoot string // Directory that exists when the agent is installed; empty for project-level directories
+	agentName string
+}
+
+// knownTargets returns the user-level and project-level skills directories of the agents.
+func knownTargets(providers []port.AgentProvider) []knownTarget {
+	targets := make([]knownTarget, 0, 2*len(providers)+2)
+	for _, provider := range providers {
+		name := provider.AgentName()
+		if dir, err := provider.ResolveAgentDir(name); err == nil {
+			targets = append(targets, knownTarget{path: dir, agentRoot: filepath.Dir(dir), agentName: name})
+		}
+		if dir, err := filepath.Abs(provider.ProjectDir()); err == nil {
+			targets = append(targets, knownTarget{path: dir, agentName: name})
+		}
+	}
+
+	if dir, err := filepath.Abs("./.skills"); err == nil {
+		targets = append(targets, knownTarget{path: dir})
+	}
+	targets = append(targets, knownTarget{path: domain.SystemInstallDir})
+
+	return targets
+}
+
+// targetAgents returns a function that resolves the agents whose skills directory is an install target.
+func targetAgents(providers []port.AgentProvider) func(target string) []string {
+	known := knownTargets(providers)
+	return func(target string) []string {
+		if domain.IsRemoteTarget(target) {
+			return nil
+		}
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return nil
+		}
+
+		var agents []string
+		for _, k := range known {
+			if k.path == abs && k.agentName != "" && !slices.Contains(agents, k.agentName) {
+				agents = append(agents, k.agentName)
+			}
+		}
+		return agents
+	}
+}
+
+// targetWarnings checks that the install target looks like the skills directory of an installed agent,
+// to catch typos in paths before anything is installed. It returns a warning for each problem found.
+func targetWarnings(target string, providers []port.AgentProvider) []string {
+	if domain.IsRemoteTarget(target) {
+		return nil
+	}
+
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot resolve install target %s: %v", target, err)}
+	}
+
+	known := knownTargets(providers)
+	for _, k := range known {
+		if k.path != abs {
+			continue
+		}
+		if k.agentRoot != "" && !dirExists(k.agentRoot) {
+			return []string{fmt.Sprintf("install target %s belongs to %s, but %s does not exist. Is %s installed?", target, k.agentName, k.agentRoot, k.agentName)}
+		}
+		return nil
+	}
+
+	// Existing custom directories are assumed to be intentional
+	if dirExists(abs) {
+		return nil
+	}
+
+	closest, distance := "", maxTargetTypoDistance+1
+	for _, k := range known {
+		if d := domain.EditDistance(abs, k.path); d < distance {
+			closest, distance = k.path, d
+		}
+	}
+	if closest != "" {
+		return []string{fmt.Sprintf("install target %s is not a known agent skills directory. Did you mean %s?", target, closest)}
+	}
+
+	if parent := filepath.Dir(abs); !dirExists(parent) {
+		return []string{fmt.Sprintf("install target %s does not exist and neither does %s. Check the path for typos", target, parent)}
+	}
+
+	return nil
+}
+
+// warnTargets logs the warnings of targetWarnings for each install target.
+func warnTargets(logger *Logger, targets []string, providers []port.AgentProvider) {
+	for _, target := range targets {
+		for _, warning := range targetWarnings(target, providers) {
+			logger.Error("Warning: %s", warning)
+		}
+	}
+}
+
+// dirExists reports whether path is an existing directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
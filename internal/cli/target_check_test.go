@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// fakeAgent is an agent whose user-level skills directory is <home>/.fake/skills.
+type fakeAgent struct {
+	home string
+}
+
+func (a *fakeAgent) ResolveAgentDir(string) (string, error) {
+	return filepath.Join(a.home, ".fake", "skills"), nil
+}
+
+func (a *fakeAgent) AgentName() string {
+	return "fake"
+}
+
+func (a *fakeAgent) ProjectDir() string {
+	return ".fake/skills"
+}
+
+func TestTargetWarnings(t *testing.T) {
+	tests := []struct {
+		setupFunc   func(t *testing.T, home string)
+		name        string
+		target      string // Relative to home unless it has a scheme
+		wantContain string
+	}{
+		{
+			name:   "installed agent",
+			target: ".fake/skills",
+			setupFunc: func(t *testing.T, home string) {
+				t.Helper()
+				if err := os.MkdirAll(filepath.Join(home, ".fake"), 0o755); err != nil {
+					t.Fatalf("failed to create agent directory: %v", err)
+				}
+			},
+		},
+		{
+			name:        "agent not installed",
+			target:      ".fake/skills",
+			wantContain: "Is fake installed?",
+		},
+		{
+			name:        "typo in agent directory",
+			target:      ".fkae/skills",
+			wantContain: "Did you mean",
+		},
+		{
+			name:   "existing custom directory",
+			target: "custom/skills",
+			setupFunc: func(t *testing.T, home string) {
+				t.Helper()
+				if err := os.MkdirAll(filepath.Join(home, "custom", "skills"), 0o755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+			},
+		},
+		{
+			name:        "custom directory with missing parent",
+			target:      "nowhere/at/all",
+			wantContain: "Check the path for typos",
+		},
+		{
+			name:   "remote target is not checked",
+			target: "ssh://host/skills",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			if tt.setupFunc != nil {
+				tt.setupFunc(t, home)
+			}
+
+			target := tt.target
+			if !strings.Contains(target, "://") {
+				target = filepath.Join(home, target)
+			}
+
+			warnings := targetWarnings(target, []port.AgentProvider{&fakeAgent{home: home}})
+			if tt.wantContain == "" {
+				if len(warnings) != 0 {
+					t.Errorf("targetWarnings() = %v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.wantContain) {
+				t.Errorf("targetWarnings() = %v, want a warning containing %q", warnings, tt.wantContain)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestTargetAddCmd(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	existing := filepath.Join(tmpDir, "existing")
+	added := filepath.Join(tmpDir, "added")
+
+	configManager := domain.NewConfigManager(configPath)
+	config := &domain.Config{
+		InstallTargets: []string{existing},
+		Skills:         []*domain.Skill{{Name: "test-skill", Source: "git", URL: "https://example.com/repo.git"}},
+	}
+	if err := configManager.Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	mockDownloadDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(mockDownloadDir, "skills/test-skill"), 0o755); err != nil {
+		t.Fatalf("failed to create mock skill directory: %v", err)
+	}
+	packageManagers := []port.PackageManager{&mockPackageManager{sourceType: "git", tmpDir: mockDownloadDir}}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+	cmd := &TargetAddCmd{Dir: []string{existing + "/", added}, Install: true}
+	if err := cmd.runWithDeps(configPath, logger, &mockHashService{}, packageManagers); err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out.String())
+	}
+
+	targets, err := configManager.GetInstallTargets(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get install targets: %v", err)
+	}
+	if !slices.Equal(targets, []string{existing, added}) {
+		t.Errorf("install targets = %v, want %v", targets, []string{existing, added})
+	}
+	if _, err := os.Stat(filepath.Join(added, "test-skill")); err != nil {
+		t.Errorf("expected the skill to be installed into the added target: %v", err)
+	}
+
+	// Without targets, nothing is changed
+	if err := (&TargetAddCmd{}).runWithDeps(configPath, logger, &mockHashService{}, packageManagers); err == nil {
+		t.Error("expected error without install targets, got nil")
+	}
+}
+
+func TestTargetRemoveCmd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		dir           string
+		clean         bool
+		wantInstalled bool
+		wantErr       bool
+	}{
+		{
+			name:          "keeps installed skills",
+			dir:           "removed",
+			wantInstalled: true,
+		},
+		{
+			name:  "deletes installed skills with clean",
+			dir:   "removed/",
+			clean: true,
+		},
+		{
+			name:    "unknown target",
+			dir:     "unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			kept := filepath.Join(tmpDir, "kept")
+			removed := filepath.Join(tmpDir, "removed")
+
+			configManager := domain.NewConfigManager(configPath)
+			config := &domain.Config{
+				InstallTargets: []string{kept, removed},
+				Skills:         []*domain.Skill{{Name: "test-skill", Source: "git", URL: "https://example.com/repo.git"}},
+			}
+			if err := configManager.Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+			if err := os.MkdirAll(filepath.Join(removed, "test-skill"), 0o755); err != nil {
+				t.Fatalf("failed to create installed skill: %v", err)
+			}
+
+			var out bytes.Buffer
+			logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+			cmd := &TargetRemoveCmd{Dir: filepath.Join(tmpDir, tt.dir), Clean: tt.clean}
+			err := cmd.runWithDeps(configPath, logger, &mockHashService{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v\noutput:\n%s", err, out.String())
+			}
+
+			targets, err := configManager.GetInstallTargets(context.Background())
+			if err != nil {
+				t.Fatalf("failed to get install targets: %v", err)
+			}
+			if !slices.Equal(targets, []string{kept}) {
+				t.Errorf("install targets = %v, want %v", targets, []string{kept})
+			}
+			_, statErr := os.Stat(filepath.Join(removed, "test-skill"))
+			if installed := statErr == nil; installed != tt.wantInstalled {
+				t.Errorf("skill installed in removed target = %v, want %v", installed, tt.wantInstalled)
+			}
+		})
+	}
+}
+
+func TestTargetMigrateCmd(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	kept := filepath.Join(tmpDir, "kept")
+	oldDir := filepath.Join(tmpDir, "old")
+	newDir := filepath.Join(tmpDir, "new")
+
+	configManager := domain.NewConfigManager(configPath)
+	config := &domain.Config{
+		InstallTargets: []string{oldDir, kept},
+		Skills:         []*domain.Skill{{Name: "test-skill", Source: "git", URL: "https://example.com/repo.git"}},
+	}
+	if err := configManager.Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(oldDir, "test-skill"), 0o755); err != nil {
+		t.Fatalf("failed to create installed skill: %v", err)
+	}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+
+	// The new directory cannot already be an install target
+	if err := (&TargetMigrateCmd{From: oldDir, To: kept}).runWithDeps(configPath, logger, &mockHashService{}); err == nil {
+		t.Error("expected error when migrating into a configured target, got nil")
+	}
+
+	if err := (&TargetMigrateCmd{From: oldDir + "/", To: newDir}).runWithDeps(configPath, logger, &mockHashService{}); err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out.String())
+	}
+
+	targets, err := configManager.GetInstallTargets(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get install targets: %v", err)
+	}
+	if !slices.Equal(targets, []string{newDir, kept}) {
+		t.Errorf("install targets = %v, want %v", targets, []string{newDir, kept})
+	}
+	if _, err := os.Stat(filepath.Join(newDir, "test-skill")); err != nil {
+		t.Errorf("expected the skill to be moved to the new directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(oldDir, "test-skill")); !os.IsNotExist(err) {
+		t.Errorf("expected the skill to be removed from the old directory, stat error = %v", err)
+	}
+}
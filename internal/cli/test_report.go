@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// testReport is the result of a command, such as verify, in the shape of a test run, so that it can
+// be written in formats CI systems show as test results.
+type testReport struct {
+	name     string
+	suites   []*testSuite
+	duration time.Duration
+}
+
+// testSuite is a group of test cases, such as the skills in an install target.
+type testSuite struct {
+	name     string
+	cases    []*testCase
+	duration time.Duration
+}
+
+// testCase is a single check. A case with neither failure nor skipped passed.
+type testCase struct {
+	name     string
+	failure  string // Message of the failure
+	kind     string // Kind of the failure, such as "hash_mismatch"
+	details  string // Multi-line details of the failure
+	skipped  string // Reason the case was skipped
+	duration time.Duration
+}
+
+// writeTestReport writes the report in format, "junit" or "tap".
+func writeTestReport(w io.Writer, report *testReport, format string) error {
+	switch format {
+	case "junit":
+		return writeJUnit(w, report)
+	case "tap":
+		return writeTAP(w, report)
+	default:
+		return fmt.Errorf("unsupported report format '%s'", format)
+	}
+}
+
+// junitTestSuites is the root element of a JUnit XML report, as read by CI systems such as
+// Jenkins, GitLab CI, and GitHub Actions test reporters.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit writes the report as JUnit XML, with the suite name as the class name of its cases.
+func writeJUnit(w io.Writer, report *testReport) error {
+	root := &junitTestSuites{Name: report.name, Time: junitTime(report.duration)}
+	for _, suite := range report.suites {
+		s := &junitTestSuite{Name: suite.name, Time: junitTime(suite.duration)}
+		for _, tc := range suite.cases {
+			c := &junitTestCase{Name: tc.name, Classname: suite.name, Time: junitTime(tc.duration)}
+			switch {
+			case tc.failure != "":
+				c.Failure = &junitFailure{Message: tc.failure, Type: tc.kind, Text: tc.details}
+				s.Failures++
+			case tc.skipped != "":
+				c.Skipped = &junitSkipped{Message: tc.skipped}
+				s.Skipped++
+			}
+			s.Cases = append(s.Cases, c)
+			s.Tests++
+		}
+		root.Suites = append(root.Suites, s)
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+		root.Skipped += s.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// junitTime formats a duration in seconds, as JUnit reports do.
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// writeTAP writes the report in the Test Anything Protocol, version 13. Each case is a test point
+// named "<suite>: <case>", and failures carry a YAML diagnostic block.
+func writeTAP(w io.Writer, report *testReport) error {
+	total := 0
+	for _, suite := range report.suites {
+		total += len(suite.cases)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "TAP version 13\n1..%d\n", total)
+	n := 0
+	for _, suite := range report.suites {
+		for _, tc := range suite.cases {
+			n++
+			// '#' starts a directive in TAP, so it cannot appear in descriptions
+			description := strings.ReplaceAll(suite.name+": "+tc.name, "#", `\#`)
+			switch {
+			case tc.failure != "":
+				fmt.Fprintf(&b, "not ok %d - %s\n", n, description)
+				fmt.Fprintf(&b, "  ---\n  message: %s\n  severity: fail\n", strconv.Quote(tc.failure))
+				if tc.kind != "" {
+					fmt.Fprintf(&b, "  type: %s\n", tc.kind)
+				}
+				if tc.details != "" {
+					b.WriteString("  details: |\n")
+					for line := range strings.SplitSeq(strings.TrimRight(tc.details, "\n"), "\n") {
+						fmt.Fprintf(&b, "    %s\n", line)
+					}
+				}
+				fmt.Fprintf(&b, "  duration_ms: %d\n  ...\n", tc.duration.Milliseconds())
+			case tc.skipped != "":
+				fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", n, description, tc.skipped)
+			default:
+				fmt.Fprintf(&b, "ok %d - %s\n", n, description)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// verifyReport converts the verification summary into a report with one suite per install target
+// and one case per skill. Config drift is reported as skipped, or as a failure when strict is set,
+// as 'verify --strict' fails on it.
+func verifyReport(summary *domain.VerifySummary, strict bool) *testReport {
+	report := &testReport{name: "skills-pkg verify", duration: summary.Duration}
+
+	suites := make(map[string]*testSuite, len(summary.Targets))
+	for _, target := range summary.Targets {
+		suites[target.Target] = &testSuite{name: target.Target, duration: target.Duration}
+		report.suites = append(report.suites, suites[target.Target])
+	}
+
+	drift := func(tc *testCase, message, details string) {
+		if strict {
+			tc.failure, tc.kind, tc.details = message, "config_drift", details
+		} else {
+			tc.skipped = message
+		}
+	}
+
+	for _, result := range summary.Results {
+		suite, ok := suites[result.Target]
+		if !ok {
+			suite = &testSuite{name: result.Target}
+			suites[result.Target] = suite
+			report.suites = append(report.suites, suite)
+		}
+
+		tc := &testCase{name: result.SkillName, duration: result.Duration}
+		switch {
+		case result.Match:
+		case result.Drifted:
+			drift(tc, "Files match .skillspkg.lock, not the configuration", fmt.Sprintf("Expected: %s\nActual:   %s\n", result.Expected, result.Actual))
+		case result.Actual == "":
+			tc.failure, tc.kind, tc.details = "Skill is not installed or cannot be read", "hash_mismatch", fmt.Sprintf("Directory: %s\n", result.InstallDir)
+		default:
+			tc.failure, tc.kind, tc.details = "Hash mismatch", "hash_mismatch", fmt.Sprintf("Directory: %s\nExpected: %s\nActual:   %s\n", result.InstallDir, result.Expected, result.Actual)
+		}
+		suite.cases = append(suite.cases, tc)
+	}
+
+	// Installations the configuration no longer contains have no result of their own
+	removed := &testSuite{name: "removed installations"}
+	for _, d := range summary.Drifts {
+		if d.Kind != domain.DriftRemoved {
+			continue
+		}
+		tc := &testCase{name: d.SkillName + " (in " + d.Target + ")"}
+		drift(tc, "Removed from .skillspkg.toml, but still installed. Run 'skills-pkg sync' to remove it", "")
+		removed.cases = append(removed.cases, tc)
+	}
+	if len(removed.cases) > 0 {
+		report.suites = append(report.suites, removed)
+	}
+
+	return report
+}
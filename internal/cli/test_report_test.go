@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestWriteTAP(t *testing.T) {
+	t.Parallel()
+
+	report := &testReport{
+		name: "skills-pkg verify",
+		suites: []*testSuite{
+			{name: "./.claude/skills", cases: []*testCase{
+				{name: "intact"},
+				{name: "modified", failure: "Hash mismatch", kind: "hash_mismatch", details: "Expected: h1:a\nActual:   h1:b\n", duration: 12 * time.Millisecond},
+			}},
+			{name: "./#skills", cases: []*testCase{{name: "drifted", skipped: "Files match .skillspkg.lock, not the configuration"}}},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := writeTestReport(&out, report, "tap"); err != nil {
+		t.Fatalf("writeTestReport() error = %v", err)
+	}
+
+	want := `TAP version 13
+1..3
+ok 1 - ./.claude/skills: intact
+not ok 2 - ./.claude/skills: modified
+  ---
+  message: "Hash mismatch"
+  severity: fail
+  type: hash_mismatch
+  details: |
+    Expected: h1:a
+    Actual:   h1:b
+  duration_ms: 12
+  ...
+ok 3 - ./\#skills: drifted # SKIP Files match .skillspkg.lock, not the configuration
+`
+	if out.String() != want {
+		t.Errorf("writeTestReport() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestVerifyReport(t *testing.T) {
+	t.Parallel()
+
+	summary := &domain.VerifySummary{
+		Targets: []*domain.TargetVerifySummary{{Target: "skills"}, {Target: "empty"}},
+		Results: []*domain.VerifyResult{
+			{SkillName: "intact", Target: "skills", Match: true},
+			{SkillName: "drifted", Target: "skills", Drifted: true, Expected: "h1:a", Actual: "h1:b"},
+			{SkillName: "missing", Target: "skills", Expected: "h1:a"},
+		},
+		Drifts: []*domain.ConfigDrift{
+			{Kind: domain.DriftRemoved, SkillName: "removed", Target: "skills"},
+			{Kind: domain.DriftVersion, SkillName: "drifted", Target: "skills"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		strict       bool
+		wantFailures int
+		wantSkipped  int
+	}{
+		{name: "drift is skipped", wantFailures: 1, wantSkipped: 2},
+		{name: "drift fails with strict", strict: true, wantFailures: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			if err := writeTestReport(&out, verifyReport(summary, tt.strict), "junit"); err != nil {
+				t.Fatalf("writeTestReport() error = %v", err)
+			}
+			var report junitTestSuites
+			if err := xml.Unmarshal(out.Bytes(), &report); err != nil {
+				t.Fatalf("failed to parse JUnit report: %v\n%s", err, out.String())
+			}
+
+			// Targets without skills are reported as empty suites, and removed installations get a suite of their own
+			if len(report.Suites) != 3 || report.Suites[1].Tests != 0 || report.Suites[2].Name != "removed installations" {
+				t.Errorf("suites = %+v, want skills, empty, and removed installations", report.Suites)
+			}
+			if report.Tests != 4 || report.Failures != tt.wantFailures || report.Skipped != tt.wantSkipped {
+				t.Errorf("report has %d tests, %d failures, and %d skipped, want 4, %d, and %d:\n%s", report.Tests, report.Failures, report.Skipped, tt.wantFailures, tt.wantSkipped, out.String())
+			}
+		})
+	}
+}
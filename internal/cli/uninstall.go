@@ -4,19 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
+	"github.com/mazrean/skills-pkg/internal/adapter/remote"
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
-	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 // UninstallCmd represents the uninstall command
 type UninstallCmd struct {
 	SkillName string `arg:"" help:"Name of the skill to remove from configuration and all install targets"`
+	DryRun    bool   `help:"Show what would be removed from each install target without making changes" name:"dry-run" xor:"dry-run"`
+	Yes       bool   `help:"Remove the installed files without asking for confirmation" short:"y" xor:"dry-run"`
+
+	stdin   io.Reader // Set by Run when the user can answer confirmation prompts
+	backups bool      // Set by Run to back up the removed skill in the user state directory
 }
 
 // Run executes the uninstall command
@@ -26,11 +32,14 @@ func (c *UninstallCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.stdin = confirmInput()
+	c.backups = true
+
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -46,19 +55,37 @@ func (c *UninstallCmd) run(configPath string, verbose bool) error {
 	logger.Verbose("Config path: %s", configPath)
 
 	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
+	configManager := newConfigManager(configPath)
 
 	// Create HashService
 	hashService := service.NewDirhash()
 
 	// Create PackageManagers
-	packageManagers := []port.PackageManager{
-		pkgmanager.NewGit(),
-		pkgmanager.NewGoMod(),
-	}
+	packageManagers := pkgmanager.All()
 
 	// Create SkillManager
-	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers)
+	opts := []domain.SkillManagerOption{domain.WithRemoteInstallers(remote.NewSFTP())}
+	if c.backups {
+		opts = append(opts, backupOptions()...)
+	}
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, opts...)
+
+	if c.DryRun {
+		changes, err := skillManager.UninstallDryRun(context.Background(), c.SkillName)
+		if err != nil {
+			c.handleUninstallError(logger, c.SkillName, configPath, err)
+			return err
+		}
+		printDryRunChanges(logger, changes)
+		logger.Info("Skill '%s' would also be removed from %s and the lock file", c.SkillName, configPath)
+		return nil
+	}
+
+	if !c.Yes && c.stdin != nil {
+		if err := c.confirmRemoval(logger, skillManager, configPath); err != nil {
+			return err
+		}
+	}
 
 	// Execute uninstall (requirements 9.1, 9.2)
 	logger.Verbose("Removing skill from install targets and configuration")
@@ -73,6 +100,35 @@ func (c *UninstallCmd) run(configPath string, verbose bool) error {
 	return nil
 }
 
+// confirmRemoval lists the installed copies of the skill and asks before they are deleted.
+// It returns errCancelled when the user declines.
+func (c *UninstallCmd) confirmRemoval(logger *Logger, skillManager domain.SkillManager, configPath string) error {
+	changes, err := skillManager.UninstallDryRun(context.Background(), c.SkillName)
+	if err != nil {
+		c.handleUninstallError(logger, c.SkillName, configPath, err)
+		return err
+	}
+	// Nothing installed is deleted, so there is nothing to confirm
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var size int64
+	var skills []string
+	for _, change := range changes {
+		logger.Info("  - %s in %s (%s)", change.Skill, change.Target, dryRunSize(change.Size))
+		size += max(change.Size, 0)
+		skills = append(skills, change.Skill)
+	}
+	warnModified(logger, modifiedInstalls(configPath, skills))
+
+	if !confirm(logger, c.stdin, fmt.Sprintf("Delete %d installed copy(ies) of '%s' (%s)?", len(changes), c.SkillName, formatBytes(size))) {
+		logger.Info("Uninstall cancelled. Nothing was changed")
+		return errCancelled
+	}
+	return nil
+}
+
 // handleUninstallError handles different types of errors that can occur during skill uninstallation.
 // It provides appropriate error messages with causes and recommended actions.
 // Requirements: 9.3, 12.2, 12.3
@@ -96,6 +152,11 @@ func (c *UninstallCmd) handleUninstallError(logger *Logger, skillName string, co
 		return
 	}
 
+	// Install target owned by another user (e.g., a system-wide directory)
+	if handlePermissionError(logger, err) {
+		return
+	}
+
 	// File system or other errors - distinguish and report (requirements 12.2, 12.3)
 	logger.Error("Failed to uninstall skill '%s': %v", skillName, err)
 	logger.Error("Check file permissions and try again")
@@ -2,8 +2,10 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
@@ -17,6 +19,7 @@ func TestUninstallCmd_Run(t *testing.T) {
 		checkFunc func(t *testing.T, configPath string)
 		name      string
 		skillName string
+		dryRun    bool
 		wantErr   bool
 	}{
 		{
@@ -81,6 +84,45 @@ func TestUninstallCmd_Run(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "success: dry run leaves the skill installed",
+			skillName: "test-skill",
+			dryRun:    true,
+			setupFunc: func(t *testing.T) (string, func()) {
+				t.Helper()
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, ".skillspkg.toml")
+				installDir := filepath.Join(tempDir, "skills")
+
+				configManager := domain.NewConfigManager(configPath)
+				if err := configManager.Initialize(context.Background(), []string{installDir}); err != nil {
+					t.Fatalf("failed to initialize config: %v", err)
+				}
+				skill := &domain.Skill{Name: "test-skill", Source: "git", URL: "https://example.com/test.git", Version: "v1.0.0"}
+				if err := configManager.AddSkill(context.Background(), skill); err != nil {
+					t.Fatalf("failed to add test skill: %v", err)
+				}
+				if err := os.MkdirAll(filepath.Join(installDir, "test-skill"), 0o755); err != nil {
+					t.Fatalf("failed to create skill directory: %v", err)
+				}
+
+				return configPath, func() {}
+			},
+			wantErr: false,
+			checkFunc: func(t *testing.T, configPath string) {
+				t.Helper()
+				if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "skills", "test-skill")); err != nil {
+					t.Errorf("skill directory should be kept by a dry run: %v", err)
+				}
+				config, err := domain.NewConfigManager(configPath).Load(context.Background())
+				if err != nil {
+					t.Fatalf("failed to load config: %v", err)
+				}
+				if config.FindSkillByName("test-skill") == nil {
+					t.Errorf("skill should be kept in the configuration by a dry run")
+				}
+			},
+		},
 		{
 			name:      "error: non-existent skill",
 			skillName: "non-existent-skill",
@@ -191,6 +233,7 @@ func TestUninstallCmd_Run(t *testing.T) {
 
 			cmd := &UninstallCmd{
 				SkillName: tt.skillName,
+				DryRun:    tt.dryRun,
 			}
 
 			// Execute command directly using the internal run method for testing
@@ -212,3 +255,50 @@ func TestUninstallCmd_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestUninstallCmd_Confirmation(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name       string
+		answer     string
+		yes        bool
+		wantErr    error
+		wantRemove bool
+	}{
+		{name: "declined", answer: "n\n", wantErr: errCancelled},
+		{name: "no answer", answer: "", wantErr: errCancelled},
+		{name: "confirmed", answer: "y\n", wantRemove: true},
+		{name: "--yes skips the prompt", answer: "n\n", yes: true, wantRemove: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			configPath := filepath.Join(tempDir, ".skillspkg.toml")
+			skillDir := filepath.Join(tempDir, "skills", "test-skill")
+
+			configManager := domain.NewConfigManager(configPath)
+			if err := configManager.Initialize(context.Background(), []string{filepath.Join(tempDir, "skills")}); err != nil {
+				t.Fatalf("failed to initialize config: %v", err)
+			}
+			skill := &domain.Skill{Name: "test-skill", Source: "git", URL: "https://example.com/test.git", Version: "v1.0.0"}
+			if err := configManager.AddSkill(context.Background(), skill); err != nil {
+				t.Fatalf("failed to add test skill: %v", err)
+			}
+			if err := os.MkdirAll(skillDir, 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+
+			cmd := &UninstallCmd{SkillName: "test-skill", Yes: tt.yes, stdin: strings.NewReader(tt.answer)}
+			if err := cmd.run(configPath, false); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err := os.Stat(skillDir)
+			if removed := os.IsNotExist(err); removed != tt.wantRemove {
+				t.Errorf("skill directory removed = %v, want %v", removed, tt.wantRemove)
+			}
+		})
+	}
+}
@@ -5,21 +5,38 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
-	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 // UpdateCmd represents the update command
 type UpdateCmd struct {
-	Output string   `help:"Output format (text, json)" default:"text" enum:"text,json"`
-	Skills []string `arg:"" optional:"" help:"Skill names to update (if not specified, updates all skills to their latest versions)"`
-	DryRun bool     `help:"Show what would be updated without making changes" name:"dry-run"`
+	Skills      []string `arg:"" optional:"" help:"Skill names to update (if not specified, updates all skills to their latest versions)"`
+	Exclude     []string `help:"Skill names to leave untouched (repeatable)" placeholder:"SKILL"`
+	Source      []string `help:"Only update skills from these source types (repeatable)" aliases:"only-source" placeholder:"TYPE"`
+	DryRun      bool     `help:"Show what would be updated without making changes" name:"dry-run" xor:"promote"`
+	Major       bool     `help:"Apply updates of any size (default)" xor:"bump"`
+	Minor       bool     `help:"Only apply updates within the current major version" xor:"bump"`
+	Patch       bool     `help:"Only apply updates within the current minor version" xor:"bump"`
+	Canary      string   `help:"Install new versions only into this install target, leaving the others on the current version until --promote" placeholder:"TARGET" xor:"rollout"`
+	Promote     bool     `help:"Install the canary versions into every install target" xor:"rollout,promote"`
+	Yes         bool     `help:"Replace the installed files without asking for confirmation" short:"y"`
+	Progress    string   `help:"Progress output format: text, or json to stream one JSON event per line to standard output" enum:"text,json" default:"text"`
+	SignKey     string   `help:"Secret key to sign the updated lock file with when [signing] is configured" name:"sign-key" env:"SKILLSPKG_SIGNING_KEY" placeholder:"KEY"`
+	Concurrency int      `help:"Maximum number of skills downloaded and updated at the same time (default 8)" env:"SKILLSPKG_CONCURRENCY" placeholder:"N"`
+	WithDeps    bool     `help:"Add the skills that updated skills declare as dependencies in their SKILL.md to the configuration without asking" name:"with-dependencies"`
+
+	Output        string    `kong:"-"` // Set by Run from the global --output flag: text or json
+	allowRoot     bool      // Set from the global --allow-root flag
+	downloadCache bool      // Set by Run to reuse downloads from the user cache directory
+	stdin         io.Reader // Set by Run when the user can answer confirmation prompts
 }
 
 // Run executes the update command
@@ -29,11 +46,17 @@ func (c *UpdateCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.Output = outputFlag(ctx)
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+	c.stdin = confirmInput()
+	c.SignKey = projectFile(c.SignKey)
+
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -43,21 +66,27 @@ func (c *UpdateCmd) Run(ctx *kong.Context) error {
 func (c *UpdateCmd) run(configPath string, verbose bool) error {
 	// Create logger with verbose setting (requirement 12.4)
 	logger := NewLogger(verbose)
+	notifier := newOperationNotifier(logger)
 
 	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
+	configManager := newConfigManager(configPath)
 
 	// Create HashService
 	hashService := service.NewDirhash()
 
 	// Create PackageManagers
-	packageManagers := []port.PackageManager{
-		pkgmanager.NewGit(),
-		pkgmanager.NewGoMod(),
-	}
+	packageManagers := pkgmanager.All()
 
 	// Create SkillManager
-	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers)
+	progress, flushProgress := progressOptions(logger, c.Progress)
+	defer flushProgress()
+	managerOpts := append(skillManagerOptions(c.allowRoot, c.downloadCache), progress...)
+	managerOpts = append(managerOpts, domain.WithConcurrency(c.Concurrency), dependencyApproval(logger, c.stdin, c.WithDeps))
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, managerOpts...)
+
+	if c.Promote {
+		return c.promote(logger, notifier, skillManager, configPath)
+	}
 
 	// Display progress information (requirement 12.1)
 	if c.DryRun {
@@ -66,18 +95,42 @@ func (c *UpdateCmd) run(configPath string, verbose bool) error {
 		logger.Info("Updating skills: %v", c.Skills)
 	}
 
+	opts := &domain.UpdateOptions{
+		Exclude: c.Exclude,
+		Sources: c.Source,
+		MaxBump: c.maxBump(),
+		Canary:  c.Canary,
+		DryRun:  c.DryRun,
+	}
+	if !c.DryRun && !c.Yes && c.stdin != nil {
+		if err := c.confirmUpdates(logger, skillManager, configPath, opts, flushProgress); err != nil {
+			if !errors.Is(err, errCancelled) {
+				c.handleUpdateError(logger, configPath, err)
+			}
+			return err
+		}
+	}
+
 	// Determine what to update (requirements 7.1, 7.2)
 	var allResults []*domain.UpdateResult
 
-	results, err := skillManager.Update(context.Background(), c.Skills, c.DryRun)
+	results, err := skillManager.Update(context.Background(), c.Skills, opts)
 	if err != nil {
-		c.handleUpdateError(logger, err)
+		c.handleUpdateError(logger, configPath, err)
+		notifier.completed("skills-pkg update failed", err.Error())
 		return err
 	}
 	allResults = append(allResults, results...)
 
 	// Success message (requirement 12.1)
 	logger.Info("Update complete")
+	if !c.DryRun {
+		if err := signLockFile(context.Background(), configManager, c.SignKey, logger); err != nil {
+			notifier.completed("skills-pkg update failed", err.Error())
+			return err
+		}
+		notifier.completed("skills-pkg update", updateSummary(allResults))
+	}
 
 	switch c.Output {
 	case "json":
@@ -87,6 +140,102 @@ func (c *UpdateCmd) run(configPath string, verbose bool) error {
 	}
 }
 
+// confirmUpdates checks for updates and asks before the installed files of the skills with
+// updates are replaced. It returns errCancelled when the user declines.
+func (c *UpdateCmd) confirmUpdates(logger *Logger, skillManager domain.SkillManager, configPath string, opts *domain.UpdateOptions, flushProgress func()) error {
+	check := *opts
+	check.DryRun = true
+	results, err := skillManager.Update(context.Background(), c.Skills, &check)
+	flushProgress()
+	if err != nil {
+		return err
+	}
+
+	var skills []string
+	for _, r := range results {
+		if r.HeldBack || r.OldVersion == r.NewVersion {
+			continue
+		}
+		logger.Info("  %s: %s → %s", r.SkillName, r.OldVersion, r.NewVersion)
+		skills = append(skills, r.SkillName)
+	}
+	// Nothing installed is replaced, so there is nothing to confirm
+	if len(skills) == 0 {
+		return nil
+	}
+
+	// Members of entries with sub_dirs are replaced with their entry
+	if config, err := newConfigManager(configPath).Load(context.Background()); err == nil {
+		for _, name := range slices.Clone(skills) {
+			if entry := config.FindSkillByName(name); entry != nil {
+				for _, member := range entry.InstalledSkills() {
+					skills = append(skills, member.Name)
+				}
+			}
+		}
+	}
+	warnModified(logger, modifiedInstalls(configPath, skills))
+
+	if !confirm(logger, c.stdin, "Replace the installed files of these skills?") {
+		logger.Info("Update cancelled. Nothing was changed")
+		return errCancelled
+	}
+	return nil
+}
+
+// promote installs the canary versions recorded by 'update --canary' into every install target.
+func (c *UpdateCmd) promote(logger *Logger, notifier *operationNotifier, skillManager domain.SkillManager, configPath string) error {
+	logger.Info("Promoting canary versions: %v", c.Skills)
+
+	results, err := skillManager.Promote(context.Background(), c.Skills)
+	if err != nil {
+		c.handleUpdateError(logger, configPath, err)
+		notifier.completed("skills-pkg update failed", err.Error())
+		return err
+	}
+
+	if len(results) == 0 {
+		logger.Info("No canary versions to promote")
+		return nil
+	}
+	for _, r := range results {
+		logger.Info("  %s: %s → %s (promoted to all install targets)", r.SkillName, r.OldVersion, r.NewVersion)
+	}
+	if err := signLockFile(context.Background(), newConfigManager(configPath), c.SignKey, logger); err != nil {
+		notifier.completed("skills-pkg update failed", err.Error())
+		return err
+	}
+	notifier.completed("skills-pkg update", updateSummary(results))
+
+	return nil
+}
+
+// maxBump returns the version bump limit selected by --major, --minor, or --patch.
+func (c *UpdateCmd) maxBump() domain.VersionBump {
+	switch {
+	case c.Patch:
+		return domain.VersionBumpPatch
+	case c.Minor:
+		return domain.VersionBumpMinor
+	default:
+		return domain.VersionBumpMajor
+	}
+}
+
+// updateSummary describes applied updates in one line for notifications.
+func updateSummary(results []*domain.UpdateResult) string {
+	updated := make([]string, 0, len(results))
+	for _, r := range results {
+		if !r.HeldBack && r.OldVersion != r.NewVersion {
+			updated = append(updated, fmt.Sprintf("%s %s", r.SkillName, r.NewVersion))
+		}
+	}
+	if len(updated) == 0 {
+		return "All skills are up to date"
+	}
+	return fmt.Sprintf("Updated %d skill(s): %s", len(updated), strings.Join(updated, ", "))
+}
+
 // dryRunOutput is the JSON-serializable structure for dry-run results.
 type dryRunOutput struct {
 	Updates []*dryRunItem `json:"updates"`
@@ -97,7 +246,9 @@ type dryRunItem struct {
 	CurrentVersion string            `json:"current_version"`
 	LatestVersion  string            `json:"latest_version"`
 	FileDiffs      []*dryRunFileDiff `json:"file_diffs,omitempty"`
+	Canary         string            `json:"canary,omitempty"`
 	HasUpdate      bool              `json:"has_update"`
+	HeldBack       bool              `json:"held_back"`
 }
 
 type dryRunFileDiff struct {
@@ -108,9 +259,17 @@ type dryRunFileDiff struct {
 
 // printDryRunText prints human-readable dry-run results.
 func (c *UpdateCmd) printDryRunText(logger *Logger, results []*domain.UpdateResult) error {
-	updateCount := 0
+	updateCount, heldBackCount, canaryCount := 0, 0, 0
 	for _, r := range results {
-		if r.OldVersion != r.NewVersion {
+		if r.HeldBack {
+			logger.Info("  %s: %s → %s (held back, exceeds --%s)", r.SkillName, r.OldVersion, r.NewVersion, c.maxBump())
+			heldBackCount++
+			continue
+		}
+		if r.Canary != "" {
+			logger.Info("  %s: %s → %s (canary in %s)", r.SkillName, r.OldVersion, r.NewVersion, r.Canary)
+			canaryCount++
+		} else if r.OldVersion != r.NewVersion {
 			logger.Info("  %s: %s → %s (update available)", r.SkillName, r.OldVersion, r.NewVersion)
 			updateCount++
 		} else {
@@ -143,12 +302,32 @@ func (c *UpdateCmd) printDryRunText(logger *Logger, results []*domain.UpdateResu
 		logger.Info("%d skill(s) checked, %d update(s) available", total, updateCount)
 		logger.Info("Run 'skills-pkg update' to apply updates.")
 	}
+	if heldBackCount > 0 {
+		logger.Info("%d update(s) held back; run without --%s to apply them", heldBackCount, c.maxBump())
+	}
+	if canaryCount > 0 {
+		logger.Info("%d canary version(s) installed into %s; run 'skills-pkg update --promote' to install them into every install target", canaryCount, c.Canary)
+	}
 
 	return nil
 }
 
 // printDryRunJSON prints JSON dry-run results.
 func (c *UpdateCmd) printDryRunJSON(logger *Logger, results []*domain.UpdateResult) error {
+	data, err := json.MarshalIndent(newDryRunOutput(results), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON output: %w", err)
+	}
+	_, err = fmt.Fprintln(logger.dataOut, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+
+	return nil
+}
+
+// newDryRunOutput converts update results into their JSON-serializable form.
+func newDryRunOutput(results []*domain.UpdateResult) *dryRunOutput {
 	items := make([]*dryRunItem, 0, len(results))
 	for _, r := range results {
 		fileDiffs := make([]*dryRunFileDiff, 0, len(r.FileDiffs))
@@ -164,26 +343,19 @@ func (c *UpdateCmd) printDryRunJSON(logger *Logger, results []*domain.UpdateResu
 			CurrentVersion: r.OldVersion,
 			LatestVersion:  r.NewVersion,
 			HasUpdate:      r.OldVersion != r.NewVersion,
+			HeldBack:       r.HeldBack,
+			Canary:         r.Canary,
 			FileDiffs:      fileDiffs,
 		})
 	}
 
-	data, err := json.MarshalIndent(dryRunOutput{Updates: items}, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON output: %w", err)
-	}
-	_, err = fmt.Fprintln(logger.dataOut, string(data))
-	if err != nil {
-		return fmt.Errorf("failed to write JSON output: %w", err)
-	}
-
-	return nil
+	return &dryRunOutput{Updates: items}
 }
 
 // handleUpdateError handles different types of errors that can occur during skill update.
 // It provides appropriate error messages with causes and recommended actions.
 // Requirements: 12.2, 12.3
-func (c *UpdateCmd) handleUpdateError(logger *Logger, err error) {
+func (c *UpdateCmd) handleUpdateError(logger *Logger, configPath string, err error) {
 	// Configuration file not found
 	if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
 		logger.Error("Configuration file not found at %s", err.Path)
@@ -207,6 +379,30 @@ func (c *UpdateCmd) handleUpdateError(logger *Logger, err error) {
 		return
 	}
 
+	// Canary target that is not an install target
+	if err, ok := errors.AsType[*domain.ErrorInstallTargetNotFound](err); ok {
+		logger.Error("Install target %s not found in configuration", err.Target)
+		logger.Error("Pass one of the install_targets in %s to --canary", configPath)
+		return
+	}
+
+	// Promoting a skill without a canary
+	if err, ok := errors.AsType[*domain.ErrorNoCanary](err); ok {
+		logger.Error("Skill '%s' has no canary version to promote", err.SkillName)
+		logger.Error("Run 'skills-pkg update --canary <target> %s' to install a new version into one install target first", err.SkillName)
+		return
+	}
+
+	// Subdirectory removed or renamed in the latest version
+	if handleSubDirNotFound(logger, configPath, err) {
+		return
+	}
+
+	// Install target owned by another user (e.g., a system-wide directory)
+	if handlePermissionError(logger, err) {
+		return
+	}
+
 	// Network, file system, or other errors - distinguish and report (requirements 12.2, 12.3)
 	logger.Error("Failed to update skills: %v", err)
 	logger.Error("Check network connection, file permissions, and try again")
@@ -220,3 +220,30 @@ func TestUpdateCmd_DryRun_JSONOutput(t *testing.T) {
 		t.Errorf("expected has_update:false in JSON output:\n%s", out)
 	}
 }
+
+func TestUpdateCmd_HeldBackOutput(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newTestLogger()
+
+	cmd := &UpdateCmd{Patch: true}
+	results := []*domain.UpdateResult{
+		{SkillName: "skill-a", OldVersion: "v1.0.0", NewVersion: "v1.1.0", HeldBack: true},
+		{SkillName: "skill-b", OldVersion: "v1.0.0", NewVersion: "v1.0.1"},
+	}
+
+	if err := cmd.printDryRunText(logger, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "skill-a: v1.0.0 → v1.1.0 (held back, exceeds --patch)") {
+		t.Errorf("expected held back line for skill-a in output:\n%s", out)
+	}
+	if !strings.Contains(out, "1 update(s) available") {
+		t.Errorf("expected held back updates to be excluded from the update count:\n%s", out)
+	}
+	if !strings.Contains(out, "1 update(s) held back") {
+		t.Errorf("expected held back summary in output:\n%s", out)
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// UsageCmd represents the usage command
+type UsageCmd struct {
+	Transcripts []string      `help:"Directories of agent transcripts (*.jsonl) to scan. Defaults to usage.transcripts in the user configuration, or the Claude Code transcripts" placeholder:"DIR" type:"path"`
+	Since       time.Duration `help:"Only scan transcripts modified within this duration, e.g. '720h'"`
+	Unused      bool          `help:"Only report the skills no transcript references"`
+
+	Output string `kong:"-"` // Set by Run from the global --output flag: text or json
+}
+
+// Run executes the usage command
+func (c *UsageCmd) Run(ctx *kong.Context) error {
+	// Access verbose flag from the parsed CLI model using reflection
+	verbose := false
+	if model := ctx.Model; model != nil && model.Target.IsValid() {
+		// Get the "Verbose" field from the CLI struct
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
+		}
+	}
+
+	c.Output = outputFlag(ctx)
+	return c.run(defaultConfigPath, verbose)
+}
+
+// run is the internal implementation that can be called from tests with custom parameters
+func (c *UsageCmd) run(configPath string, verbose bool) error {
+	return c.runWithLogger(configPath, NewLogger(verbose))
+}
+
+// usageOutput is the JSON output of the usage command.
+type usageOutput struct {
+	Skills      []*usageOutputSkill `json:"skills"`
+	Transcripts int                 `json:"transcripts"`
+}
+
+type usageOutputSkill struct {
+	LastUsed   *time.Time `json:"last_used,omitempty"`
+	Name       string     `json:"name"`
+	References int        `json:"references"`
+}
+
+// runWithLogger reports how often the configured skills are referenced in agent transcripts (for testing)
+func (c *UsageCmd) runWithLogger(configPath string, logger *Logger) error {
+	config, err := newConfigManager(configPath).Load(context.Background())
+	if err != nil {
+		if err, ok := errors.AsType[*domain.ErrorConfigNotFound](err); ok {
+			logger.Error("Configuration file not found at %s", err.Path)
+			logger.Error("Run 'skills-pkg init' to create a configuration file")
+			return err
+		}
+		logger.Error("Failed to load configuration: %v", err)
+		return err
+	}
+
+	dirs := c.Transcripts
+	if len(dirs) == 0 {
+		dirs, err = c.configuredTranscriptDirs(logger)
+		if err != nil {
+			logger.Error("%v", err)
+			logger.Error("Specify the transcript directories with --transcripts")
+			return err
+		}
+	}
+
+	var since time.Time
+	if c.Since > 0 {
+		since = time.Now().Add(-c.Since)
+	}
+
+	skills := config.InstalledSkills()
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, skill.Name)
+	}
+
+	logger.Verbose("Scanning transcripts in %v", dirs)
+	report, err := domain.ScanSkillUsage(dirs, names, since)
+	if err != nil {
+		logger.Error("%v", err)
+		return err
+	}
+	if report.Transcripts == 0 {
+		logger.Error("Warning: no transcripts found in %v; every skill is reported as unused", dirs)
+	}
+
+	usages := report.Skills
+	if c.Unused {
+		usages = report.Unused()
+	}
+
+	if c.Output == "json" {
+		output := &usageOutput{Skills: make([]*usageOutputSkill, 0, len(usages)), Transcripts: report.Transcripts}
+		for _, usage := range usages {
+			item := &usageOutputSkill{Name: usage.SkillName, References: usage.References}
+			if !usage.LastUsed.IsZero() {
+				item.LastUsed = &usage.LastUsed
+			}
+			output.Skills = append(output.Skills, item)
+		}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON output: %w", err)
+		}
+		_, err = fmt.Fprintln(logger.dataOut, string(data))
+		return err
+	}
+
+	if c.Unused {
+		if len(usages) == 0 {
+			logger.Info("Every skill is referenced in %d transcript(s)", report.Transcripts)
+			return nil
+		}
+		for _, usage := range usages {
+			logger.Info("%s", usage.SkillName)
+		}
+		logger.Info("")
+		logger.Info("%d of %d skill(s) unused in %d transcript(s)", len(usages), len(report.Skills), report.Transcripts)
+		logger.Info("Run 'skills-pkg uninstall <name>' to remove a skill that is no longer needed")
+		return nil
+	}
+
+	logger.Info("%-30s %-12s %s", "NAME", "REFERENCES", "LAST USED")
+	for _, usage := range usages {
+		lastUsed := "never"
+		if !usage.LastUsed.IsZero() {
+			lastUsed = usage.LastUsed.Local().Format(time.DateTime)
+		}
+		logger.Info("%-30s %-12d %s", usage.SkillName, usage.References, lastUsed)
+	}
+	logger.Info("")
+	logger.Info("Scanned %d transcript(s)", report.Transcripts)
+
+	return nil
+}
+
+// configuredTranscriptDirs returns the transcript directories of the user-level configuration.
+func (c *UsageCmd) configuredTranscriptDirs(logger *Logger) ([]string, error) {
+	dirs, err := resolveUserDirs(logger)
+	if err != nil {
+		return nil, err
+	}
+	userConfig, err := domain.LoadUserConfig(dirs.ConfigFile())
+	if err != nil {
+		return nil, err
+	}
+	return userConfig.TranscriptDirs()
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestUsageCmd_Unused(t *testing.T) {
+	t.Parallel()
+
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	for _, name := range []string{"used-skill", "unused-skill"} {
+		skill := &domain.Skill{Name: name, Source: "git", URL: "https://github.com/example/" + name + ".git", Version: "v1.0.0"}
+		if err := domain.NewConfigManager(configPath).AddSkill(context.Background(), skill); err != nil {
+			t.Fatalf("failed to add skill: %v", err)
+		}
+	}
+
+	transcripts := t.TempDir()
+	if err := os.WriteFile(filepath.Join(transcripts, "session.jsonl"), []byte(`{"input":{"skill":"used-skill"}}`+"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	var out bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &out, errOut: &out}
+	cmd := &UsageCmd{Transcripts: []string{transcripts}, Unused: true}
+	if err := cmd.runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("runWithLogger() error = %v, output = %s", err, out.String())
+	}
+	if lines := strings.Split(out.String(), "\n"); !slices.Contains(lines, "unused-skill") || slices.Contains(lines, "used-skill") {
+		t.Errorf("output should list only unused-skill, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	cmd = &UsageCmd{Transcripts: []string{transcripts}, Output: "json"}
+	if err := cmd.runWithLogger(configPath, logger); err != nil {
+		t.Fatalf("runWithLogger() error = %v, output = %s", err, out.String())
+	}
+	var output usageOutput
+	if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+		t.Fatalf("failed to decode output: %v\n%s", err, out.String())
+	}
+	if output.Transcripts != 1 || len(output.Skills) != 2 {
+		t.Fatalf("output = %+v", output)
+	}
+	for _, skill := range output.Skills {
+		if used := skill.Name == "used-skill"; (skill.References == 1) != used || (skill.LastUsed != nil) != used {
+			t.Errorf("skill %s = %+v", skill.Name, skill)
+		}
+	}
+}
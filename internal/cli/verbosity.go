@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/alecthomas/kong"
+)
+
+// Verbosity is the level of the global --verbose flag, raised by each -v.
+type Verbosity int
+
+// Levels of Verbosity, each including the messages of the levels below it.
+const (
+	VerbosityVerbose Verbosity = 1 // -v: verbose messages of commands and debug messages of skills
+	VerbosityTrace   Verbosity = 2 // -vv: HTTP requests and git operations of the adapters
+	VerbosityDump    Verbosity = 3 // -vvv: headers of requests and responses, and output of git servers
+)
+
+// Decode raises the level by one for each flag without a value, and sets it to a value, such as
+// --verbose=2 or SKILLSPKG_VERBOSE=2. The values true and false set it to 1 and 0, and an empty value to 0.
+func (v *Verbosity) Decode(ctx *kong.DecodeContext) error {
+	if ctx.Scan.Peek().Type != kong.FlagValueToken {
+		*v++
+		return nil
+	}
+
+	token, err := ctx.Scan.PopValue("verbosity")
+	if err != nil {
+		return err
+	}
+	value := fmt.Sprint(token.Value)
+	if value == "" {
+		*v = 0
+		return nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		*v = 0
+		if b {
+			*v = VerbosityVerbose
+		}
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("expected a verbosity level such as 1, 2, or 3, or true or false, but got %q", value)
+	}
+	*v = Verbosity(n)
+	return nil
+}
+
+// IsBool tells kong that the flag takes no value, so that -vvv repeats it.
+func (v *Verbosity) IsBool() bool {
+	return true
+}
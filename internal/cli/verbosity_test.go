@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestVerbosity_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		want    Verbosity
+		wantErr bool
+	}{
+		{name: "not given", want: 0},
+		{name: "single flag", args: []string{"-v"}, want: VerbosityVerbose},
+		{name: "repeated short flags", args: []string{"-vvv"}, want: VerbosityDump},
+		{name: "repeated long flags", args: []string{"--verbose", "--verbose"}, want: VerbosityTrace},
+		{name: "level as a value", args: []string{"--verbose=2"}, want: VerbosityTrace},
+		{name: "boolean environment variable", env: "true", want: VerbosityVerbose},
+		{name: "level in the environment variable", env: "3", want: VerbosityDump},
+		{name: "invalid value", env: "loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SKILLSPKG_TEST_VERBOSE", tt.env)
+			var cli struct {
+				Verbose Verbosity `short:"v" env:"SKILLSPKG_TEST_VERBOSE"`
+			}
+			parser, err := kong.New(&cli)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = parser.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if !tt.wantErr && cli.Verbose != tt.want {
+				t.Errorf("Parse(%v) verbosity = %d, want %d", tt.args, cli.Verbose, tt.want)
+			}
+		})
+	}
+}
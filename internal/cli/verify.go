@@ -3,15 +3,26 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 // VerifyCmd represents the verify command
 type VerifyCmd struct {
+	Strict bool   `help:"Exit with a non-zero status when any skill fails verification, regardless of the hash_mismatch policy, or the configuration drifted from the lock file"`
+	Format string `help:"Output format: text, or junit or tap to also write a JUnit XML or TAP report to standard output for CI systems" enum:"text,junit,tap" default:"text"`
+
+	Output        string `kong:"-"` // Set by Run from the global --output flag: text or json
+	allowRoot     bool   // Set from the global --allow-root flag
+	downloadCache bool   // Set by Run to reuse downloads from the user cache directory
 }
 
 // Run executes the verify command
@@ -21,11 +32,15 @@ func (c *VerifyCmd) Run(ctx *kong.Context) error {
 	verbose := false
 	if model := ctx.Model; model != nil && model.Target.IsValid() {
 		// Get the "Verbose" field from the CLI struct
-		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Bool {
-			verbose = verboseField.Bool()
+		if verboseField := model.Target.FieldByName("Verbose"); verboseField.IsValid() && verboseField.Kind() == reflect.Int {
+			verbose = verboseField.Int() > 0
 		}
 	}
 
+	c.Output = outputFlag(ctx)
+	c.allowRoot = allowRootFlag(ctx)
+	c.downloadCache = true
+
 	return c.run(defaultConfigPath, verbose)
 }
 
@@ -42,12 +57,24 @@ func (c *VerifyCmd) run(configPath string, verbose bool) error {
 // runWithLogger executes the verify command with a custom logger (for testing)
 // Requirements: 5.4, 5.5, 5.6, 12.1, 12.2, 12.3
 func (c *VerifyCmd) runWithLogger(configPath string, logger *Logger) error {
+	return c.runWithPackageManagers(configPath, logger, pkgmanager.All())
+}
+
+// runWithPackageManagers executes the verify command, reinstalling skills with the given
+// package managers when the hash_mismatch policy is "reinstall" (for testing)
+func (c *VerifyCmd) runWithPackageManagers(configPath string, logger *Logger, packageManagers []port.PackageManager) error {
+	if c.Output == "json" && c.Format != "" && c.Format != "text" {
+		err := fmt.Errorf("--format %s cannot be combined with --output json, as both are written to standard output", c.Format)
+		logger.Error("%v", err)
+		return err
+	}
+
 	// Display progress information (requirement 12.1)
 	logger.Info("Verifying skill integrity...")
 	logger.Verbose("Loading configuration from %s", configPath)
 
 	// Create ConfigManager
-	configManager := domain.NewConfigManager(configPath)
+	configManager := newConfigManager(configPath)
 
 	// Create HashService
 	hashService := service.NewDirhash()
@@ -73,8 +100,31 @@ func (c *VerifyCmd) runWithLogger(configPath string, logger *Logger) error {
 		return err
 	}
 
+	// Repair failed installations when the policy asks for it
+	policy := c.policy(configManager)
+	if summary.FailureCount > 0 && policy == domain.HashMismatchReinstall {
+		summary, err = c.reinstallFailed(configManager, hashService, hashVerifier, summary, logger, packageManagers)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Format == "junit" || c.Format == "tap" {
+		if err := writeTestReport(logger.dataOut, verifyReport(summary, c.Strict), c.Format); err != nil {
+			logger.Error("Failed to write %s report: %v", c.Format, err)
+			return err
+		}
+	}
+
+	if c.Output == "json" {
+		if err := writeJSON(logger, newVerifyOutput(summary)); err != nil {
+			logger.Error("%v", err)
+			return err
+		}
+	}
+
 	// Check if there are no skills to verify
-	if summary.TotalSkills == 0 {
+	if summary.TotalSkills == 0 && len(summary.Drifts) == 0 {
 		logger.Info("")
 		logger.Info("No skills to verify")
 		logger.Info("Use 'skills-pkg add <name> --source <type> --url <url>' to add skills")
@@ -90,28 +140,202 @@ func (c *VerifyCmd) runWithLogger(configPath string, logger *Logger) error {
 	for _, result := range summary.Results {
 		if result.Match {
 			logger.Verbose("✓ %s (in %s): Hash verified", result.SkillName, result.InstallDir)
+		} else if result.Drifted {
+			logger.Verbose("≠ %s (in %s): Files match .skillspkg.lock, not the configuration", result.SkillName, result.InstallDir)
 		} else {
 			// Display warning for hash mismatch (requirement 5.5)
 			logger.Error("⚠ WARNING: Hash mismatch for skill '%s' in %s", result.SkillName, result.InstallDir)
 			logger.Error("  Expected: %s", result.Expected)
 			logger.Error("  Actual:   %s", result.Actual)
 			logger.Error("  The skill may have been tampered with or modified")
+			if result.Insecure {
+				logger.Error("  The skill is marked insecure, so the hash_mismatch policy does not apply to it")
+			}
 		}
 	}
 
 	// Display summary (requirement 5.6)
 	logger.Info("")
-	logger.Info("Verification complete:")
+	logger.Info("Verification complete in %s (%s hashed):", summary.Duration.Round(time.Millisecond), formatBytes(summary.BytesHashed))
 	logger.Info("  Total skills verified: %d", summary.TotalSkills)
 	logger.Info("  Successful: %d", summary.SuccessCount)
 	logger.Info("  Failed: %d", summary.FailureCount)
+	logger.Info("  Config drift: %d", len(summary.Drifts))
+	printVerifyTargets(logger, summary.Targets)
+
+	// Drift is reported apart from content changes: the files are as installed, but the configuration moved on
+	if len(summary.Drifts) > 0 {
+		logger.Info("")
+		logger.Error("⚠ Warning: .skillspkg.toml and .skillspkg.lock disagree on %d installation(s)", len(summary.Drifts))
+		for _, drift := range summary.Drifts {
+			printConfigDrift(logger, drift)
+		}
+	}
 
 	if summary.FailureCount > 0 {
 		logger.Info("")
 		logger.Error("⚠ Warning: %d skill(s) failed verification", summary.FailureCount)
 		logger.Error("This may indicate tampering or corruption")
 		logger.Error("Consider reinstalling the affected skills with 'skills-pkg install'")
+		newOperationNotifier(logger).alert("skills-pkg verify", fmt.Sprintf("%d skill(s) failed verification", summary.FailureCount))
+
+		if c.Strict || (policy != domain.HashMismatchWarn && enforcedFailures(summary) > 0) {
+			return &domain.ErrorVerificationFailed{FailureCount: summary.FailureCount}
+		}
+	}
+
+	if c.Strict && len(summary.Drifts) > 0 {
+		return &domain.ErrorConfigDrift{DriftCount: len(summary.Drifts)}
 	}
 
 	return nil
 }
+
+// verifyOutput is the JSON output of the verify command.
+type verifyOutput struct {
+	Skills     []*verifyOutputSkill `json:"skills"`
+	Drifts     []*verifyOutputDrift `json:"drifts"`
+	Total      int                  `json:"total"`
+	Successful int                  `json:"successful"`
+	Failed     int                  `json:"failed"`
+}
+
+// verifyOutputSkill is the verification of a skill in an install target in the JSON output of the verify command.
+type verifyOutputSkill struct {
+	Name         string `json:"name"`
+	Target       string `json:"target,omitempty"`
+	InstallDir   string `json:"install_dir"`
+	ExpectedHash string `json:"expected_hash"`
+	ActualHash   string `json:"actual_hash,omitempty"`
+	Status       string `json:"status"` // "ok", "mismatch", "missing", or "drifted"
+}
+
+// verifyOutputDrift is a disagreement between the configuration and the lock file in the JSON output of the verify command.
+type verifyOutputDrift struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Target     string `json:"target"`
+	Configured string `json:"configured,omitempty"`
+	Locked     string `json:"locked"`
+}
+
+// newVerifyOutput converts a verification summary into its JSON-serializable form.
+func newVerifyOutput(summary *domain.VerifySummary) *verifyOutput {
+	output := &verifyOutput{
+		Skills:     make([]*verifyOutputSkill, 0, len(summary.Results)),
+		Drifts:     make([]*verifyOutputDrift, 0, len(summary.Drifts)),
+		Total:      summary.TotalSkills,
+		Successful: summary.SuccessCount,
+		Failed:     summary.FailureCount,
+	}
+	for _, result := range summary.Results {
+		item := &verifyOutputSkill{Name: result.SkillName, Target: result.Target, InstallDir: result.InstallDir, ExpectedHash: result.Expected, ActualHash: result.Actual}
+		switch {
+		case result.Match:
+			item.Status = "ok"
+		case result.Drifted:
+			item.Status = "drifted"
+		case result.Actual == "":
+			item.Status = "missing"
+		default:
+			item.Status = "mismatch"
+		}
+		output.Skills = append(output.Skills, item)
+	}
+	for _, drift := range summary.Drifts {
+		output.Drifts = append(output.Drifts, &verifyOutputDrift{Kind: string(drift.Kind), Name: drift.SkillName, Target: drift.Target, Configured: drift.Configured, Locked: drift.Locked})
+	}
+	return output
+}
+
+// printVerifyTargets prints the verification results of each install target as a table.
+func printVerifyTargets(logger *Logger, targets []*domain.TargetVerifySummary) {
+	if len(targets) == 0 {
+		return
+	}
+
+	logger.Info("")
+	logger.Info("  %-40s %6s %6s %6s %7s %10s %8s", "TARGET", "SKILLS", "OK", "FAILED", "DRIFTED", "HASHED", "TIME")
+	for _, target := range targets {
+		logger.Info("  %-40s %6d %6d %6d %7d %10s %8s", target.Target, target.TotalSkills, target.SuccessCount, target.FailureCount, target.DriftCount, formatBytes(target.BytesHashed), target.Duration.Round(time.Millisecond))
+	}
+}
+
+// formatBytes formats a number of bytes with a binary unit, such as "1.5 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// printConfigDrift reports a disagreement between the configuration and the lock file with the command that resolves it.
+func printConfigDrift(logger *Logger, drift *domain.ConfigDrift) {
+	switch drift.Kind {
+	case domain.DriftVersion:
+		logger.Error("  %s (in %s): version %s in .skillspkg.toml, but %s is installed", drift.SkillName, drift.Target, drift.Configured, drift.Locked)
+		logger.Error("    Run 'skills-pkg install %s' to install the configured version", drift.SkillName)
+	case domain.DriftHash:
+		logger.Error("  %s (in %s): hash_value %s in .skillspkg.toml, but %s is installed", drift.SkillName, drift.Target, drift.Configured, drift.Locked)
+		logger.Error("    Restore hash_value, or run 'skills-pkg update %s' to record the hash of a new version", drift.SkillName)
+	case domain.DriftRemoved:
+		logger.Error("  %s (in %s): removed from .skillspkg.toml, but still installed", drift.SkillName, drift.Target)
+		logger.Error("    Run 'skills-pkg sync' to remove it")
+	}
+}
+
+// policy returns the hash_mismatch policy of the configuration.
+func (c *VerifyCmd) policy(configManager *domain.ConfigManager) string {
+	config, err := configManager.Load(context.Background())
+	if err != nil {
+		return domain.HashMismatchWarn
+	}
+	return config.EffectiveHashMismatch()
+}
+
+// enforcedFailures returns the number of failed verifications of skills that are not marked insecure,
+// which the hash_mismatch policy applies to.
+func enforcedFailures(summary *domain.VerifySummary) int {
+	count := 0
+	for _, result := range summary.Results {
+		if !result.Match && !result.Drifted && !result.Insecure {
+			count++
+		}
+	}
+	return count
+}
+
+// reinstallFailed reinstalls the skills that failed verification and verifies all skills again.
+func (c *VerifyCmd) reinstallFailed(configManager *domain.ConfigManager, hashService port.HashService, hashVerifier *domain.HashVerifier, summary *domain.VerifySummary, logger *Logger, packageManagers []port.PackageManager) (*domain.VerifySummary, error) {
+	var failed []string
+	for _, result := range summary.Results {
+		if !result.Match && !result.Drifted && !slices.Contains(failed, result.SkillName) {
+			failed = append(failed, result.SkillName)
+		}
+	}
+
+	ctx := context.Background()
+	skillManager := domain.NewSkillManager(configManager, hashService, packageManagers, skillManagerOptions(c.allowRoot, c.downloadCache)...)
+	for _, skillName := range failed {
+		logger.Info("Reinstalling skill '%s', which failed verification", skillName)
+		if err := skillManager.Install(ctx, skillName); err != nil {
+			logger.Error("Failed to reinstall skill '%s': %v", skillName, err)
+			handlePermissionError(logger, err)
+			return nil, err
+		}
+	}
+
+	logger.Verbose("Verifying all skills again after reinstalling %d skill(s)", len(failed))
+	summary, err := hashVerifier.VerifyAll(ctx)
+	if err != nil {
+		logger.Error("Failed to verify skills: %v", err)
+		return nil, err
+	}
+	return summary, nil
+}
@@ -3,6 +3,8 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"os"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 func TestVerifyCmd_Run(t *testing.T) {
@@ -55,8 +58,8 @@ func TestVerifyCmd_Run(t *testing.T) {
 
 				// Calculate actual hashes using the real hash service
 				hashService := service.NewDirhash()
-				hash1, _ := hashService.CalculateHash(context.Background(), skillDir1)
-				hash2, _ := hashService.CalculateHash(context.Background(), skillDir2)
+				hash1, _ := hashService.CalculateHash(context.Background(), skillDir1, port.HashAlgorithmH1)
+				hash2, _ := hashService.CalculateHash(context.Background(), skillDir2, port.HashAlgorithmH1)
 
 				// Add skills with correct hashes
 				skill1 := &domain.Skill{
@@ -263,3 +266,281 @@ func TestVerifyCmd_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyCmd_Run_MismatchPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		policy       string
+		strict       bool
+		wantErr      bool
+		wantRepaired bool
+	}{
+		{name: "warn: mismatch is reported with success", policy: "warn"},
+		{name: "warn with --strict: mismatch fails", policy: "warn", strict: true, wantErr: true},
+		{name: "fail: mismatch fails", policy: "fail", wantErr: true},
+		{name: "reinstall: modified skill is restored", policy: "reinstall", wantRepaired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			installDir := filepath.Join(tmpDir, "skills")
+			skillDir := filepath.Join(installDir, "skill1")
+			files := map[string]string{"SKILL.md": "# Skill\n"}
+
+			if err := os.MkdirAll(skillDir, 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(files["SKILL.md"]), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+			hash, err := service.NewDirhash().CalculateHash(context.Background(), skillDir, port.HashAlgorithmH1)
+			if err != nil {
+				t.Fatalf("failed to calculate hash: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Tampered\n"), 0o644); err != nil {
+				t.Fatalf("failed to modify test file: %v", err)
+			}
+
+			config := &domain.Config{
+				Skills:         []*domain.Skill{{Name: "skill1", Source: "git", URL: "https://github.com/example/skill1.git", Version: "v1.0.0", HashValue: hash.Value}},
+				InstallTargets: []string{installDir},
+				HashMismatch:   tt.policy,
+			}
+			if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &out, dataOut: &out, errOut: &errOut}
+			cmd := &VerifyCmd{Strict: tt.strict}
+			pm := &fixturePackageManager{sourceType: "git", tmpRoot: t.TempDir(), files: files}
+			err = cmd.runWithPackageManagers(configPath, logger, []port.PackageManager{pm})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithPackageManagers() error = %v, wantErr %v, stderr: %s", err, tt.wantErr, errOut.String())
+			}
+			if tt.wantErr {
+				if _, ok := errors.AsType[*domain.ErrorVerificationFailed](err); !ok {
+					t.Errorf("expected ErrorVerificationFailed, got %v", err)
+				}
+			}
+
+			content, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+			if err != nil {
+				t.Fatalf("failed to read SKILL.md: %v", err)
+			}
+			if repaired := string(content) == files["SKILL.md"]; repaired != tt.wantRepaired {
+				t.Errorf("SKILL.md = %q, repaired = %v, want %v", content, repaired, tt.wantRepaired)
+			}
+			if tt.wantRepaired && strings.Contains(errOut.String(), "failed verification") {
+				t.Errorf("expected no failures after reinstalling, stderr: %s", errOut.String())
+			}
+		})
+	}
+}
+
+func TestVerifyCmd_Run_ConfigDrift(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+	}{
+		{name: "drift is reported with success"},
+		{name: "drift fails with --strict", strict: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			installDir := filepath.Join(tmpDir, "skills")
+			skillDir := filepath.Join(installDir, "skill1")
+
+			if err := os.MkdirAll(skillDir, 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Skill\n"), 0o644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+			hash, err := service.NewDirhash().CalculateHash(context.Background(), skillDir, port.HashAlgorithmH1)
+			if err != nil {
+				t.Fatalf("failed to calculate hash: %v", err)
+			}
+
+			// The version was edited by hand without running install
+			config := &domain.Config{
+				Skills:         []*domain.Skill{{Name: "skill1", Source: "git", URL: "https://github.com/example/skill1.git", Version: "v2.0.0", HashValue: hash.Value}},
+				InstallTargets: []string{installDir},
+			}
+			if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+			err = domain.NewLockManager(domain.LockPathFor(configPath)).Update(context.Background(), func(lock *domain.LockFile) {
+				lock.RecordInstall("skill1", &domain.TargetStatus{Path: installDir, Version: "v1.0.0", HashValue: hash.Value})
+			})
+			if err != nil {
+				t.Fatalf("failed to write lock file: %v", err)
+			}
+
+			var out, errOut bytes.Buffer
+			logger := &Logger{out: &out, dataOut: &out, errOut: &errOut}
+			err = (&VerifyCmd{Strict: tt.strict}).runWithPackageManagers(configPath, logger, nil)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runWithPackageManagers() error = %v, wantErr %v, stderr: %s", err, tt.wantErr, errOut.String())
+			}
+			if tt.wantErr {
+				if _, ok := errors.AsType[*domain.ErrorConfigDrift](err); !ok {
+					t.Errorf("expected ErrorConfigDrift, got %v", err)
+				}
+			}
+			if !strings.Contains(errOut.String(), "version v2.0.0 in .skillspkg.toml, but v1.0.0 is installed") {
+				t.Errorf("expected the drift to be reported, stderr: %s", errOut.String())
+			}
+			if strings.Contains(errOut.String(), "failed verification") {
+				t.Errorf("expected no content failures, stderr: %s", errOut.String())
+			}
+		})
+	}
+}
+
+func TestVerifyCmd_Run_JUnit(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	installDir := filepath.Join(tmpDir, "skills")
+
+	config := &domain.Config{InstallTargets: []string{installDir}}
+	for _, name := range []string{"intact", "modified"} {
+		skillDir := filepath.Join(installDir, name)
+		if err := os.MkdirAll(skillDir, 0o755); err != nil {
+			t.Fatalf("failed to create skill directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Skill\n"), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		hash, err := service.NewDirhash().CalculateHash(context.Background(), skillDir, port.HashAlgorithmH1)
+		if err != nil {
+			t.Fatalf("failed to calculate hash: %v", err)
+		}
+		config.Skills = append(config.Skills, &domain.Skill{Name: name, Source: "git", URL: "https://github.com/example/" + name + ".git", Version: "v1.0.0", HashValue: hash.Value})
+	}
+	if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "modified", "SKILL.md"), []byte("# Tampered\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify skill: %v", err)
+	}
+
+	var out, dataOut bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &dataOut, errOut: &out}
+	if err := (&VerifyCmd{Format: "junit"}).runWithPackageManagers(configPath, logger, nil); err != nil {
+		t.Fatalf("runWithPackageManagers() error = %v, output: %s", err, out.String())
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(dataOut.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse JUnit report: %v\n%s", err, dataOut.String())
+	}
+	if report.Tests != 2 || report.Failures != 1 || len(report.Suites) != 1 {
+		t.Fatalf("report has %d tests, %d failures, and %d suites, want 2, 1, and 1:\n%s", report.Tests, report.Failures, len(report.Suites), dataOut.String())
+	}
+	suite := report.Suites[0]
+	if suite.Name != installDir || len(suite.Cases) != 2 || suite.Cases[0].Failure != nil || suite.Cases[1].Failure == nil {
+		t.Errorf("suite = %+v, want a failure for the modified skill only in %s", suite, installDir)
+	}
+
+	// The human-readable summary, with the breakdown by target, still goes to standard error
+	if !strings.Contains(out.String(), "TARGET") || !strings.Contains(out.String(), installDir) {
+		t.Errorf("expected the target table in the output, got: %s", out.String())
+	}
+}
+
+func TestVerifyCmd_Run_JSON(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	installDir := filepath.Join(tmpDir, "skills")
+
+	config := &domain.Config{InstallTargets: []string{installDir}}
+	for _, name := range []string{"intact", "modified"} {
+		skillDir := filepath.Join(installDir, name)
+		if err := os.MkdirAll(skillDir, 0o755); err != nil {
+			t.Fatalf("failed to create skill directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Skill\n"), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		hash, err := service.NewDirhash().CalculateHash(context.Background(), skillDir, port.HashAlgorithmH1)
+		if err != nil {
+			t.Fatalf("failed to calculate hash: %v", err)
+		}
+		config.Skills = append(config.Skills, &domain.Skill{Name: name, Source: "git", URL: "https://github.com/example/" + name + ".git", Version: "v1.0.0", HashValue: hash.Value})
+	}
+	if err := domain.NewConfigManager(configPath).Save(context.Background(), config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "modified", "SKILL.md"), []byte("# Tampered\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify skill: %v", err)
+	}
+
+	var out, dataOut bytes.Buffer
+	logger := &Logger{out: &out, dataOut: &dataOut, errOut: &out}
+	if err := (&VerifyCmd{Output: "json"}).runWithPackageManagers(configPath, logger, nil); err != nil {
+		t.Fatalf("runWithPackageManagers() error = %v, output: %s", err, out.String())
+	}
+
+	var output verifyOutput
+	if err := json.Unmarshal(dataOut.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, dataOut.String())
+	}
+	if output.Total != 2 || output.Successful != 1 || output.Failed != 1 || len(output.Skills) != 2 {
+		t.Fatalf("output = %+v, want 2 skills with 1 failure", output)
+	}
+	statuses := map[string]string{}
+	for _, skill := range output.Skills {
+		statuses[skill.Name] = skill.Status
+		if skill.ExpectedHash == "" {
+			t.Errorf("skill %s has no expected hash", skill.Name)
+		}
+	}
+	if statuses["intact"] != "ok" || statuses["modified"] != "mismatch" {
+		t.Errorf("statuses = %v, want intact ok and modified mismatch", statuses)
+	}
+
+	if err := (&VerifyCmd{Output: "json", Format: "junit"}).runWithPackageManagers(configPath, logger, nil); err == nil {
+		t.Error("expected an error for --format junit with --output json")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		want string
+		n    int64
+	}{
+		{n: 0, want: "0 B"},
+		{n: 1023, want: "1023 B"},
+		{n: 1536, want: "1.5 KiB"},
+		{n: 5 << 20, want: "5.0 MiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
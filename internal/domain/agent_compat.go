@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// AgentRequirement is an agent a skill declares support for in the agents field of its SKILL.md
+// frontmatter, optionally with a minimum version, e.g. "claude-code>=1.0.30".
+type AgentRequirement struct {
+	Agent      string
+	MinVersion string // Empty when any version is supported
+}
+
+// String returns the requirement as written in SKILL.md.
+func (r *AgentRequirement) String() string {
+	if r.MinVersion == "" {
+		return r.Agent
+	}
+	return r.Agent + ">=" + r.MinVersion
+}
+
+// ReadAgentRequirements returns the agents declared by the SKILL.md frontmatter of the skill in dir,
+// as a flow list (agents: [claude-code, "codex>=0.30"]) or a block list.
+// A skill without SKILL.md or without the agents field supports every agent and yields nil.
+func ReadAgentRequirements(dir string) ([]*AgentRequirement, error) {
+	metadata, err := readSkillMD(dir)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+
+	var requirements []*AgentRequirement
+	for _, value := range metadata.Agents {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		agent, minVersion, _ := strings.Cut(value, ">=")
+		requirements = append(requirements, &AgentRequirement{Agent: strings.TrimSpace(agent), MinVersion: strings.TrimSpace(minVersion)})
+	}
+	return requirements, nil
+}
+
+// agentIncompatibility returns why a skill with the requirements cannot be used by any of the agents
+// of an install target, or an empty string when one of them supports it. Targets of unknown agents are
+// compatible, and a minimum version is only enforced when the agent version of the target is known.
+func agentIncompatibility(requirements []*AgentRequirement, agents []string, agentVersion string) string {
+	if len(requirements) == 0 || len(agents) == 0 {
+		return ""
+	}
+
+	var tooOld []string
+	for _, requirement := range requirements {
+		if !slices.Contains(agents, requirement.Agent) {
+			continue
+		}
+		if requirement.MinVersion == "" || agentVersion == "" || compareAgentVersions(agentVersion, requirement.MinVersion) >= 0 {
+			return ""
+		}
+		tooOld = append(tooOld, requirement.String())
+	}
+
+	if len(tooOld) > 0 {
+		return fmt.Sprintf("requires %s, but the agent version is %s", strings.Join(tooOld, " or "), agentVersion)
+	}
+	supported := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		supported = append(supported, requirement.String())
+	}
+	return fmt.Sprintf("supports %s, not %s", strings.Join(supported, ", "), strings.Join(agents, " or "))
+}
+
+// compareAgentVersions compares two versions with or without a "v" prefix as semantic versions.
+// Versions that are not semantic versions compare as equal, so they never make a skill incompatible.
+func compareAgentVersions(a, b string) int {
+	a, b = "v"+strings.TrimPrefix(a, "v"), "v"+strings.TrimPrefix(b, "v")
+	if !semver.IsValid(a) || !semver.IsValid(b) {
+		return 0
+	}
+	return semver.Compare(a, b)
+}
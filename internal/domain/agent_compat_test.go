@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadAgentRequirements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "flow list", content: "---\nname: x\nagents: [claude-code, \"codex>=0.30\"]\n---\n", want: []string{"claude-code", "codex>=0.30"}},
+		{name: "block list", content: "---\nagents:\n  - claude-code>=1.0.0\n  - gemini-cli\ndescription: x\n---\n", want: []string{"claude-code>=1.0.0", "gemini-cli"}},
+		{name: "byte order mark and CRLF", content: "\ufeff---\r\nname: x\r\nagents:\r\n  - 'claude-code>=1.0.0'\r\n...\r\n", want: []string{"claude-code>=1.0.0"}},
+		{name: "no agents", content: "---\nname: x\n---\nagents: [codex]\n"},
+		{name: "no frontmatter", content: "# Skill\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("failed to write SKILL.md: %v", err)
+			}
+
+			requirements, err := ReadAgentRequirements(dir)
+			if err != nil {
+				t.Fatalf("ReadAgentRequirements() error = %v", err)
+			}
+			got := make([]string, 0, len(requirements))
+			for _, requirement := range requirements {
+				got = append(got, requirement.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadAgentRequirements() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ReadAgentRequirements()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAgentIncompatibility(t *testing.T) {
+	t.Parallel()
+
+	requirements := []*AgentRequirement{{Agent: "claude-code", MinVersion: "1.2.0"}, {Agent: "codex"}}
+	tests := []struct {
+		name         string
+		agents       []string
+		agentVersion string
+		compatible   bool
+	}{
+		{name: "supported agent", agents: []string{"claude", "claude-code"}, compatible: true},
+		{name: "new enough", agents: []string{"claude-code"}, agentVersion: "v1.10.0", compatible: true},
+		{name: "too old", agents: []string{"claude-code"}, agentVersion: "1.1.9"},
+		{name: "unparsable version", agents: []string{"claude-code"}, agentVersion: "nightly", compatible: true},
+		{name: "unsupported agent", agents: []string{"cursor"}},
+		{name: "unknown agent", compatible: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reason := agentIncompatibility(requirements, tt.agents, tt.agentVersion)
+			if (reason == "") != tt.compatible {
+				t.Errorf("agentIncompatibility() = %q, want compatible = %v", reason, tt.compatible)
+			}
+		})
+	}
+}
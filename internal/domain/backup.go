@@ -0,0 +1,381 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// DefaultBackupKeep is the number of backups kept for each skill and install target
+// when the [backups] section of the user-level configuration sets no keep.
+const DefaultBackupKeep = 5
+
+// backupMetadataFile is the file describing a backup, next to the backed up files.
+const backupMetadataFile = "backup.toml"
+
+// backupTimeFormat names backup directories after their creation time, so that they sort by age
+// even when a skill is backed up several times in a second.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// Backup is a copy of an installed skill that was saved before the skill was replaced or removed.
+type Backup struct {
+	CreatedAt  time.Time `toml:"created_at"`
+	Entry      *Skill    `toml:"entry,omitempty"`      // Configuration entry of the skill, to add it back after it was uninstalled
+	Skill      string    `toml:"skill"`                // Name of the installed skill
+	Target     string    `toml:"target"`               // Install target as written in .skillspkg.toml
+	TargetPath string    `toml:"target_path"`          // Absolute path of the install target
+	Dir        string    `toml:"dir,omitempty"`        // Directory name in the target when it differs from the skill name
+	Version    string    `toml:"version,omitempty"`    // Version that was installed
+	HashValue  string    `toml:"hash_value,omitempty"` // Hash that was recorded in the lock file
+	Path       string    `toml:"-"`                    // Directory holding the backed up files
+}
+
+// DirName returns the name of the skill's directory in the install target.
+func (b *Backup) DirName() string {
+	if b.Dir != "" {
+		return b.Dir
+	}
+	return b.Skill
+}
+
+// Backups keeps the installed copies of skills that were replaced or removed, so that they can be restored.
+// It keeps the newest backups of each skill and install target up to a limit, and optionally drops
+// backups older than a maximum age.
+//
+// Layout:
+//
+//	<root>/<skill>/<created>-<random>/backup.toml   metadata of the backup
+//	<root>/<skill>/<created>-<random>/files/        the backed up skill directory
+type Backups struct {
+	root   string
+	keep   int
+	maxAge time.Duration
+}
+
+// NewBackups creates Backups rooted at root that keeps keep backups of each skill and install target,
+// and none older than maxAge unless it is zero. A keep of zero or less keeps DefaultBackupKeep backups.
+func NewBackups(root string, keep int, maxAge time.Duration) *Backups {
+	if keep <= 0 {
+		keep = DefaultBackupKeep
+	}
+	return &Backups{root: root, keep: keep, maxAge: maxAge}
+}
+
+// Root returns the directory of the backups.
+func (b *Backups) Root() string {
+	return b.root
+}
+
+// Save moves the installed skill directory skillDir into a new backup described by backup,
+// and drops the backups that exceed the retention. The directory is moved when possible, and
+// copied otherwise, such as when the backups are on another file system.
+func (b *Backups) Save(skillDir string, backup *Backup) error {
+	return b.save(skillDir, backup, true)
+}
+
+// Copy saves a copy of the installed skill directory skillDir as a new backup described by backup,
+// leaving skillDir in place to be updated, and drops the backups that exceed the retention.
+func (b *Backups) Copy(skillDir string, backup *Backup) error {
+	return b.save(skillDir, backup, false)
+}
+
+// save creates the backup of skillDir, moving the directory when move is set.
+func (b *Backups) save(skillDir string, backup *Backup, move bool) error {
+	skillRoot := filepath.Join(b.root, backup.Skill)
+	if err := os.MkdirAll(skillRoot, installDirMode); err != nil {
+		return fmt.Errorf("failed to create backup directory %s: %w", skillRoot, err)
+	}
+
+	backup.CreatedAt = time.Now().UTC()
+	dir, err := os.MkdirTemp(skillRoot, backup.CreatedAt.Format(backupTimeFormat)+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	backup.Path = filepath.Join(dir, "files")
+
+	if !move || os.Rename(skillDir, backup.Path) != nil {
+		if err := CopyDir(skillDir, backup.Path, nil); err != nil {
+			_ = os.RemoveAll(dir)
+			return fmt.Errorf("failed to back up %s: %w", skillDir, err)
+		}
+	}
+
+	data, err := toml.Marshal(backup)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, backupMetadataFile), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write backup metadata: %w", err)
+	}
+
+	return b.prune(backup.Skill)
+}
+
+// List returns the backups of the skill, newest first. A skill without backups yields none.
+func (b *Backups) List(skillName string) ([]*Backup, error) {
+	skillRoot := filepath.Join(b.root, skillName)
+	entries, err := os.ReadDir(skillRoot)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory %s: %w", skillRoot, err)
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		dir := filepath.Join(skillRoot, entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, backupMetadataFile))
+		if err != nil {
+			// Backups that are being written, or were interrupted, have no metadata yet
+			continue
+		}
+		var backup Backup
+		if err := toml.Unmarshal(data, &backup); err != nil {
+			return nil, fmt.Errorf("failed to parse backup metadata in %s: %w", dir, err)
+		}
+		backup.Path = filepath.Join(dir, "files")
+		backups = append(backups, &backup)
+	}
+
+	// Directory names start with the creation time, so the newest come last
+	slices.Reverse(backups)
+	return backups, nil
+}
+
+// prune drops the backups of the skill beyond the newest keep for each install target,
+// and those older than the maximum age.
+func (b *Backups) prune(skillName string) error {
+	backups, err := b.List(skillName)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[string]int)
+	for _, backup := range backups {
+		kept[backup.TargetPath]++
+		if kept[backup.TargetPath] <= b.keep && (b.maxAge == 0 || time.Since(backup.CreatedAt) <= b.maxAge) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Dir(backup.Path)); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", filepath.Dir(backup.Path), err)
+		}
+	}
+	return nil
+}
+
+// backupInstall moves the skill directory dirName out of the local install target into a new backup
+// before it is replaced or removed.
+func (s *skillManagerImpl) backupInstall(ctx context.Context, config *Config, target, skillName, dirName string) error {
+	if s.backups == nil {
+		return nil
+	}
+	return s.saveBackup(ctx, config, target, skillName, dirName, s.backups.Save)
+}
+
+// backupInstalledCopy saves a copy of the skill directory dirName in the local install target
+// as a new backup before it is updated in place.
+func (s *skillManagerImpl) backupInstalledCopy(ctx context.Context, config *Config, target, skillName, dirName string) error {
+	if s.backups == nil {
+		return nil
+	}
+	return s.saveBackup(ctx, config, target, skillName, dirName, s.backups.Copy)
+}
+
+// saveBackup backs up the skill directory dirName with save, recording the installed version from the lock file
+// and the configuration entry of the skill. Links into the shared store, remote targets, and missing directories
+// are not backed up, as well as everything when backups are disabled.
+func (s *skillManagerImpl) saveBackup(ctx context.Context, config *Config, target, skillName, dirName string, save func(skillDir string, backup *Backup) error) error {
+	if s.backups == nil || IsRemoteTarget(target) {
+		return nil
+	}
+	skillDir := target + "/" + dirName
+	if info, err := s.fsys.Lstat(skillDir); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	backup := &Backup{Skill: skillName, Target: target, TargetPath: backupTargetPath(target)}
+	if dirName != skillName {
+		backup.Dir = dirName
+	}
+	status := lock.FindSkill(skillName).TargetStatus(target)
+	if status != nil {
+		backup.Version, backup.HashValue = status.Version, status.HashValue
+	}
+	if entry := config.FindSkillEntry(skillName); entry != nil {
+		saved := *entry
+		saved.Canary = nil
+		// Entries with sub_dirs are saved as they are, as their version is shared by every member
+		if status != nil && saved.Name == skillName {
+			saved.Version = status.Version
+			if saved.HashValue != "" {
+				saved.HashValue = status.HashValue
+			}
+		}
+		backup.Entry = &saved
+	}
+
+	if err := save(skillDir, backup); err != nil {
+		return err
+	}
+	s.emit(ctx, skillName, PhaseBackup, target, "Backed up skill '%s' in %s", skillName, target)
+	return nil
+}
+
+// Restore copies the newest matching backup of the skill into each local install target that has one,
+// backing up the copy it replaces, and records the restored version in the lock file and the configuration.
+// Without a version, the newest backup of another version than the installed one is restored, which undoes
+// the last update. A skill that was uninstalled is added back to the configuration.
+func (s *skillManagerImpl) Restore(ctx context.Context, skillName, version string) ([]*Backup, error) {
+	if s.backups == nil {
+		return nil, fmt.Errorf("backups are disabled in the user-level configuration")
+	}
+
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	backups, err := s.backups.List(skillName)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locked := lock.FindSkill(skillName)
+
+	var restored []*Backup
+	for _, target := range config.LocalInstallTargets() {
+		status := locked.TargetStatus(target)
+		i := slices.IndexFunc(backups, func(b *Backup) bool {
+			if b.TargetPath != backupTargetPath(target) {
+				return false
+			}
+			if version != "" {
+				return b.Version == version
+			}
+			return status == nil || b.Version != status.Version
+		})
+		if i < 0 {
+			continue
+		}
+		backup := backups[i]
+
+		if err := s.restoreBackup(ctx, config, target, backup, status); err != nil {
+			return nil, err
+		}
+		s.emit(ctx, backup.Skill, PhaseRestore, target, "Restored skill %s in %s", backupName(backup), target)
+		restored = append(restored, backup)
+	}
+
+	if len(restored) == 0 {
+		return nil, &ErrorNoBackup{SkillName: skillName, Version: version}
+	}
+
+	if entry := config.FindSkillEntry(skillName); entry == nil {
+		if restored[0].Entry != nil {
+			config.Skills = append(config.Skills, restored[0].Entry)
+		}
+	} else if entry.Name == skillName {
+		entry.Version = restored[0].Version
+		if entry.HashValue != "" {
+			entry.HashValue = restored[0].HashValue
+		}
+		entry.Canary = nil
+	}
+	if err := s.configManager.Save(ctx, config); err != nil {
+		return nil, fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	return restored, nil
+}
+
+// restoreBackup replaces the skill in the install target with the backed up files.
+// The files are copied next to the skill first and swapped in, so that the target is never left
+// without the skill, and the backup is kept to be restored again.
+func (s *skillManagerImpl) restoreBackup(ctx context.Context, config *Config, target string, backup *Backup, status *TargetStatus) error {
+	if err := os.MkdirAll(target, installDirMode); err != nil {
+		return fmt.Errorf("failed to create install target directory %s: %w", target, err)
+	}
+	staging, err := os.MkdirTemp(target, ".restore-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory in %s: %w", target, err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	staged := filepath.Join(staging, backup.DirName())
+	if err := CopyDir(backup.Path, staged, nil); err != nil {
+		return fmt.Errorf("failed to copy backup of skill '%s': %w", backup.Skill, err)
+	}
+
+	current := backup.DirName()
+	if status != nil {
+		current = status.DirName(backup.Skill)
+	}
+	if err := s.backupInstalledCopy(ctx, config, target, backup.Skill, current); err != nil {
+		return err
+	}
+
+	skillDir := target + "/" + backup.DirName()
+	previous := s.storeEntryOf(skillDir)
+	if err := replaceAtomically(staged, skillDir); err != nil {
+		return fmt.Errorf("failed to restore skill '%s' into %s: %w", backup.Skill, target, err)
+	}
+	if previous != "" {
+		if err := s.releaseStoreEntry(previous); err != nil {
+			return err
+		}
+	}
+	// The copy installed under another directory name, before install_as changed, is removed
+	if current != backup.DirName() {
+		if err := s.removeFromTarget(ctx, target, current); err != nil {
+			return err
+		}
+	}
+
+	return s.lockManager.Update(ctx, func(lock *LockFile) {
+		lock.RecordInstall(backup.Skill, &TargetStatus{
+			Path:        target,
+			Version:     backup.Version,
+			HashValue:   backup.HashValue,
+			Dir:         backup.Dir,
+			InstalledAt: time.Now().UTC().Truncate(time.Second),
+		})
+	})
+}
+
+// backupTargetPath returns the absolute path of the install target that backups are matched by,
+// as the backups of every project are kept together.
+func backupTargetPath(target string) string {
+	if abs, err := filepath.Abs(target); err == nil {
+		return abs
+	}
+	return filepath.Clean(target)
+}
+
+// backupName describes the backup for progress messages.
+func backupName(backup *Backup) string {
+	var b strings.Builder
+	b.WriteString(backup.Skill)
+	if backup.Version != "" {
+		b.WriteString(" " + backup.Version)
+	}
+	b.WriteString(" from " + backup.CreatedAt.Local().Format(time.DateTime))
+	return b.String()
+}
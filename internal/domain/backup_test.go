@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// writeInstalledSkill creates an installed skill directory whose SKILL.md holds content.
+func writeInstalledSkill(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create skill directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+}
+
+func TestBackups_SaveList(t *testing.T) {
+	tmpDir := t.TempDir()
+	backups := domain.NewBackups(filepath.Join(tmpDir, "backups"), 2, 0)
+	target := filepath.Join(tmpDir, "skills")
+	skillDir := filepath.Join(target, "test-skill")
+
+	for _, version := range []string{"v1.0.0", "v2.0.0", "v3.0.0"} {
+		writeInstalledSkill(t, skillDir, version)
+		if err := backups.Save(skillDir, &domain.Backup{Skill: "test-skill", Target: target, TargetPath: target, Version: version}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if _, err := os.Stat(skillDir); !os.IsNotExist(err) {
+			t.Fatalf("Save() should move the skill directory, stat error = %v", err)
+		}
+	}
+
+	// Another install target keeps its own backups
+	other := filepath.Join(tmpDir, "other", "test-skill")
+	writeInstalledSkill(t, other, "v1.0.0")
+	if err := backups.Save(other, &domain.Backup{Skill: "test-skill", Target: filepath.Dir(other), TargetPath: filepath.Dir(other), Version: "v1.0.0"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := backups.List("test-skill")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var versions []string
+	for _, backup := range got {
+		if backup.TargetPath == target {
+			versions = append(versions, backup.Version)
+		}
+	}
+	if len(versions) != 2 || versions[0] != "v3.0.0" || versions[1] != "v2.0.0" {
+		t.Errorf("List() versions in %s = %v, want the newest two [v3.0.0 v2.0.0]", target, versions)
+	}
+	if len(got) != 3 {
+		t.Errorf("List() = %d backups, want 3", len(got))
+	}
+
+	data, err := os.ReadFile(filepath.Join(got[0].Path, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("backup should contain SKILL.md: %v", err)
+	}
+	if string(data) != "v1.0.0" || got[0].TargetPath != filepath.Dir(other) {
+		t.Errorf("newest backup = %s of %s, want v1.0.0 of %s", data, got[0].TargetPath, filepath.Dir(other))
+	}
+
+	if got, err := backups.List("missing"); err != nil || len(got) != 0 {
+		t.Errorf("List() of a skill without backups = %v, %v, want none", got, err)
+	}
+}
+
+func TestBackups_MaxAge(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "backups")
+	skillDir := filepath.Join(tmpDir, "skills", "test-skill")
+
+	writeInstalledSkill(t, skillDir, "v1.0.0")
+	if err := domain.NewBackups(root, 0, 0).Save(skillDir, &domain.Backup{Skill: "test-skill", Version: "v1.0.0"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	writeInstalledSkill(t, skillDir, "v2.0.0")
+	if err := domain.NewBackups(root, 0, 10*time.Millisecond).Save(skillDir, &domain.Backup{Skill: "test-skill", Version: "v2.0.0"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := domain.NewBackups(root, 0, 0).List("test-skill")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Version != "v2.0.0" {
+		t.Errorf("List() = %d backups, want only the v2.0.0 backup younger than max_age", len(got))
+	}
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bannerStart opens the notice inserted into installed SKILL.md files when the banner is enabled,
+// and bannerEnd closes it. The notice is a single-line HTML comment, so it is hidden when the Markdown is rendered.
+const (
+	bannerStart = "<!-- skills-pkg: installed from "
+	bannerEnd   = " by skills-pkg. Do not edit this file: changes fail 'skills-pkg verify' and are overwritten by 'skills-pkg install'. -->"
+)
+
+// Banner returns the notice inserted into the installed SKILL.md of the skill.
+func Banner(skill *Skill, version string) string {
+	source := skill.URL
+	if version != "" {
+		source += "@" + version
+	}
+	// "--" cannot appear inside an HTML comment
+	source = strings.ReplaceAll(source, "--", "-")
+	return bannerStart + source + bannerEnd + "\n"
+}
+
+// InsertBanner returns the SKILL.md content with the banner placed after its YAML frontmatter,
+// or at the start when it has none, replacing a banner already there.
+// Content with unterminated frontmatter is returned unchanged.
+func InsertBanner(content []byte, banner string) []byte {
+	content = StripBanner(content)
+	offset, ok := bannerOffset(content)
+	if !ok {
+		return content
+	}
+
+	inserted := make([]byte, 0, len(content)+len(banner))
+	inserted = append(inserted, content[:offset]...)
+	inserted = append(inserted, banner...)
+	return append(inserted, content[offset:]...)
+}
+
+// StripBanner returns the SKILL.md content without the banner inserted by InsertBanner: the line after
+// the frontmatter, when it is exactly a banner. Hashes of installed skills are calculated without it when
+// the banner is enabled, so the banner never breaks verification.
+func StripBanner(content []byte) []byte {
+	offset, ok := bannerOffset(content)
+	if !ok {
+		return content
+	}
+	end := bytes.IndexByte(content[offset:], '\n')
+	if end < 0 || !isBanner(string(content[offset:offset+end])) {
+		return content
+	}
+
+	stripped := make([]byte, 0, len(content)-end-1)
+	stripped = append(stripped, content[:offset]...)
+	return append(stripped, content[offset+end+1:]...)
+}
+
+// isBanner reports whether the line is a banner as Banner writes it, for any source.
+func isBanner(line string) bool {
+	source, ok := strings.CutPrefix(line, bannerStart)
+	if !ok {
+		return false
+	}
+	source, ok = strings.CutSuffix(source, bannerEnd)
+	return ok && source != "" && !strings.Contains(source, "--")
+}
+
+// bannerOffset returns the offset of the line following the YAML frontmatter of SKILL.md content,
+// or 0 when it has none. It reports false when the frontmatter is not terminated by a line break.
+func bannerOffset(content []byte) (int, bool) {
+	start, _, end := frontmatterRange(content)
+	if start == 0 {
+		return 0, true
+	}
+	return end, end > 0
+}
+
+// injectBanner inserts the banner into SKILL.md of the installed skill directory.
+// Skills without a regular SKILL.md file are left unchanged.
+func injectBanner(skillDir string, skill *Skill, version string) error {
+	path := filepath.Join(skillDir, "SKILL.md")
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Read-only files are made writable for the update and restored afterwards
+	perm := info.Mode().Perm()
+	if perm&0o200 == 0 {
+		if err := os.Chmod(path, perm|0o200); err != nil {
+			return err
+		}
+		defer func() { _ = os.Chmod(path, perm) }()
+	}
+
+	return os.WriteFile(path, InsertBanner(content, Banner(skill, version)), perm)
+}
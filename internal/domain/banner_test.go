@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestBanner(t *testing.T) {
+	skill := &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git"}
+	banner := domain.Banner(skill, "v1.0.0")
+
+	if !strings.HasPrefix(banner, "<!-- skills-pkg: ") || !strings.HasSuffix(banner, " -->\n") || strings.Count(banner, "\n") != 1 {
+		t.Errorf("Banner() = %q, want a single-line HTML comment", banner)
+	}
+	if !strings.Contains(banner, "https://github.com/example/skills.git@v1.0.0") {
+		t.Errorf("Banner() = %q, want it to name the source and version", banner)
+	}
+	if strings.Contains(strings.TrimSuffix(strings.TrimPrefix(banner, "<!--"), "-->\n"), "--") {
+		t.Errorf("Banner() = %q contains -- inside the comment", banner)
+	}
+}
+
+func TestInsertBanner(t *testing.T) {
+	skill := &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git"}
+	banner := domain.Banner(skill, "v1.0.0")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "after frontmatter",
+			content: "---\nname: my-skill\n---\n# Skill\n",
+			want:    "---\nname: my-skill\n---\n" + banner + "# Skill\n",
+		},
+		{
+			name:    "after frontmatter with CRLF line endings",
+			content: "---\r\nname: my-skill\r\n---\r\n# Skill\r\n",
+			want:    "---\r\nname: my-skill\r\n---\r\n" + banner + "# Skill\r\n",
+		},
+		{
+			name:    "without frontmatter",
+			content: "# Skill\n",
+			want:    banner + "# Skill\n",
+		},
+		{
+			name:    "replaces an existing banner",
+			content: "---\nname: my-skill\n---\n" + domain.Banner(skill, "v0.9.0") + "# Skill\n",
+			want:    "---\nname: my-skill\n---\n" + banner + "# Skill\n",
+		},
+		{
+			name:    "unterminated frontmatter is unchanged",
+			content: "---\nname: my-skill\n",
+			want:    "---\nname: my-skill\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := domain.InsertBanner([]byte(tt.content), banner)
+			if string(got) != tt.want {
+				t.Errorf("InsertBanner() = %q, want %q", got, tt.want)
+			}
+			if stripped := domain.StripBanner(got); tt.name != "replaces an existing banner" && string(stripped) != tt.content {
+				t.Errorf("StripBanner(InsertBanner()) = %q, want %q", stripped, tt.content)
+			}
+		})
+	}
+}
+
+func TestStripBanner_KeepsComments(t *testing.T) {
+	banner := domain.Banner(&domain.Skill{URL: "https://github.com/example/skills.git"}, "v1.0.0")
+	for _, content := range []string{
+		// Only the banner at the position InsertBanner uses is removed
+		"# Skill\n" + banner,
+		// Comments that only start like a banner are part of the skill
+		"<!-- skills-pkg: not a banner -->\n# Skill\n",
+		strings.TrimSuffix(banner, " -->\n") + " Edited. -->\n# Skill\n",
+	} {
+		if got := domain.StripBanner([]byte(content)); string(got) != content {
+			t.Errorf("StripBanner() = %q, want %q", got, content)
+		}
+	}
+}
@@ -2,24 +2,388 @@
 // It defines the configuration structures, validation rules, and domain-level errors.
 package domain
 
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
 // Config represents the entire .skillspkg.toml configuration.
 // It manages the list of skills and their installation targets.
 // Requirements: 2.1, 2.2, 10.1
 type Config struct {
-	Skills         []*Skill `toml:"skills"`
-	InstallTargets []string `toml:"install_targets"`
+	Defaults       *Defaults                  `toml:"defaults,omitempty"`
+	Targets        map[string]*TargetSettings `toml:"targets,omitempty"`        // Per-target settings keyed by install target path
+	HashAlgorithm  string                     `toml:"hash_algorithm,omitempty"` // "h1" (default) or "n1"
+	Skills         []*Skill                   `toml:"skills"`
+	InstallTargets []string                   `toml:"install_targets"`
+	SharedStore    bool                       `toml:"shared_store,omitempty"`   // Link local targets to the machine-wide skill store instead of copying
+	Banner         bool                       `toml:"banner,omitempty"`         // Insert a "do not edit" notice into installed SKILL.md files
+	SkillMetadata  bool                       `toml:"skill_metadata,omitempty"` // Write a metadata file into installed skills
+	HashMismatch   string                     `toml:"hash_mismatch,omitempty"`  // "warn" (default), "fail", or "reinstall"
+	Policy         string                     `toml:"policy,omitempty"`         // CEL policy file evaluated before installing each skill, relative to the configuration file
+	// Scanner is a command run on every downloaded skill before it is installed, with the
+	// skill directory appended to its arguments. A non-zero exit status rejects the skill.
+	Scanner []string `toml:"scanner,omitempty"`
+	// HardenedExtraction extracts downloaded archives in a sandboxed child process
+	// to contain exploits of the archive parsers.
+	HardenedExtraction bool `toml:"hardened_extraction,omitempty"`
+	// Lint reports prompt-injection patterns, hidden Unicode characters, and broad tool
+	// permissions found in the Markdown of downloaded skills. Findings are warnings only.
+	Lint bool `toml:"lint,omitempty"`
+	// HiddenCharacters reports invisible Unicode characters and look-alike file names in downloaded
+	// skills: "warn" reports them, "strip" also removes the characters from the installed files.
+	HiddenCharacters string        `toml:"hidden_characters,omitempty"`
+	Symlinks         string        `toml:"symlinks,omitempty"`  // "flatten" (default) or "reject"
+	MaxDepth         int           `toml:"max_depth,omitempty"` // Deepest directory nesting allowed in a skill; DefaultMaxDepth when zero
+	Copy             *CopySettings `toml:"copy,omitempty"`
+	// RequireReview refuses to install versions of skills that have not been approved with
+	// 'skills-pkg review approve'. Blocked versions are refused regardless.
+	RequireReview bool `toml:"require_review,omitempty"`
+	// Signing requires the lock file to be signed with the secret key of a public key, so that
+	// installs are limited to the versions a maintainer signed.
+	Signing *SigningSettings `toml:"signing,omitempty"`
+	// Recipients are the public keys that 'skills-pkg encrypt' encrypts values to. Encrypted values
+	// can be used for skill URLs, and are decrypted with the secret key of any of the recipients.
+	Recipients []string `toml:"recipients,omitempty"`
+
+	// Extends lists configurations whose skills, install targets, and settings this configuration
+	// builds on: local files, or <repository>@<version> for the .skillspkg.toml of a Git repository.
+	Extends []string `toml:"extends,omitempty"`
+	// Org is the policy of the organization that 'skills-pkg org sync' applies: a local file, or
+	// <repository>@<version> for the skillspkg-org.toml of a Git repository.
+	Org string `toml:"org,omitempty"`
+
+	others otherPlatforms // Skills and install targets of other operating systems, written back on save
+	bases  *baseEntries   // Entries taken from the configurations in Extends, left out on save
+
+	// secrets are the encrypted values of the configuration by their plaintext, encrypted again on save
+	secrets map[string]string
+}
+
+// EffectiveHashAlgorithm returns the algorithm used for newly calculated skill hashes.
+// It returns port.HashAlgorithmH1 when no algorithm is configured.
+func (c *Config) EffectiveHashAlgorithm() string {
+	if c.HashAlgorithm == "" {
+		return port.HashAlgorithmH1
+	}
+	return c.HashAlgorithm
+}
+
+// Policies accepted by the hash_mismatch key.
+const (
+	HashMismatchWarn      = "warn"      // Report mismatches and continue
+	HashMismatchFail      = "fail"      // Fail install and verify on mismatches
+	HashMismatchReinstall = "reinstall" // Reinstall skills whose installed files fail verification
+)
+
+// Policies accepted by the hidden_characters key.
+const (
+	HiddenCharactersWarn  = "warn"  // Report hidden characters and look-alike file names
+	HiddenCharactersStrip = "strip" // Report them and remove the characters from the installed files
+)
+
+// Policies accepted by the symlinks key.
+const (
+	SymlinksFlatten = "flatten" // Install copies of the files and directories that links inside the skill point to
+	SymlinksReject  = "reject"  // Fail the installation of skills that contain symbolic links
+)
+
+// DefaultMaxDepth is the deepest directory nesting allowed in a skill when max_depth is not set.
+const DefaultMaxDepth = 16
+
+// EffectiveSymlinks returns the policy for symbolic links in skills.
+// It returns SymlinksFlatten when no policy is configured.
+func (c *Config) EffectiveSymlinks() string {
+	if c.Symlinks == "" {
+		return SymlinksFlatten
+	}
+	return c.Symlinks
+}
+
+// EffectiveMaxDepth returns the deepest directory nesting allowed in a skill.
+// It returns DefaultMaxDepth when no limit is configured.
+func (c *Config) EffectiveMaxDepth() int {
+	if c.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return c.MaxDepth
+}
+
+// SourceOf returns the source to download the skill from, with the options set by the configuration.
+// Encrypted values were decrypted when the configuration was loaded; the values that none of the
+// secret keys of the current user could decrypt cannot be used.
+func (c *Config) SourceOf(skill *Skill) (*port.Source, error) {
+	if IsEncrypted(skill.URL) {
+		return nil, fmt.Errorf("failed to decrypt the URL of skill '%s': %w", skill.Name, &ErrorNoSecretKey{})
+	}
+
+	source := &port.Source{Type: skill.Source, URL: skill.URL}
+	options := map[string]string{}
+	if c.HardenedExtraction {
+		options[port.SourceOptionHardenedExtraction] = "true"
+	}
+	if registry := c.Defaults.NpmRegistry(); registry != "" && skill.Source == "npm" {
+		if IsEncrypted(registry) {
+			return nil, fmt.Errorf("failed to decrypt defaults.npm.registry: %w", &ErrorNoSecretKey{})
+		}
+		options["registry"] = registry
+	}
+	if skill.SHA256 != "" {
+		options[port.SourceOptionSHA256] = skill.SHA256
+	}
+	if len(options) > 0 {
+		source.Options = options
+	}
+	return source, nil
+}
+
+// LocalDir returns the absolute path of the directory of a local skill, including its subdirectory.
+// Relative paths are relative to the working directory, which is the directory of the configuration file.
+func (c *Config) LocalDir(skill *Skill) (string, error) {
+	source, err := c.SourceOf(skill)
+	if err != nil {
+		return "", err
+	}
+	dir, err := filepath.Abs(filepath.Join(source.URL, skill.SubDir))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the directory of skill '%s': %w", skill.Name, err)
+	}
+	return dir, nil
+}
+
+// EffectiveHashMismatch returns the policy for hash mismatches.
+// It returns HashMismatchWarn when no policy is configured.
+func (c *Config) EffectiveHashMismatch() string {
+	if c.HashMismatch == "" {
+		return HashMismatchWarn
+	}
+	return c.HashMismatch
+}
+
+// HashMismatchFor returns the policy for hash mismatches of the skill.
+// Insecure skills always get HashMismatchWarn.
+func (c *Config) HashMismatchFor(skill *Skill) string {
+	if skill.Insecure {
+		return HashMismatchWarn
+	}
+	return c.EffectiveHashMismatch()
+}
+
+// Version strategies accepted by the [defaults.git] version key.
+const (
+	VersionStrategyHead      = "head"       // Latest commit on the default branch
+	VersionStrategyLatestTag = "latest-tag" // Latest semver tag, falling back to the default branch
+)
+
+// Defaults holds per-source-type settings applied when a skill has no explicit version.
+// A missing section keeps the built-in behavior of each source type.
+type Defaults struct {
+	Git   *GitDefaults   `toml:"git,omitempty"`
+	GoMod *GoModDefaults `toml:"go-mod,omitempty"`
+	Npm   *NpmDefaults   `toml:"npm,omitempty"`
+}
+
+// GitDefaults configures version resolution for git sources.
+type GitDefaults struct {
+	Version string `toml:"version,omitempty"` // "head" (default) or "latest-tag"
+}
+
+// GoModDefaults configures version resolution for go-mod sources.
+type GoModDefaults struct {
+	UseGoMod *bool `toml:"use_gomod,omitempty"` // Resolve the version from go.mod first (default: true)
+}
+
+// NpmDefaults configures the registry of npm sources.
+type NpmDefaults struct {
+	Registry string `toml:"registry,omitempty"` // Registry URL for private registries (default: https://registry.npmjs.org)
+}
+
+// NpmRegistry returns the configured registry for npm sources, or "" for the registry of the environment.
+func (d *Defaults) NpmRegistry() string {
+	if d == nil || d.Npm == nil {
+		return ""
+	}
+	return d.Npm.Registry
+}
+
+// GitVersionStrategy returns the configured version strategy for git sources.
+// It returns VersionStrategyHead when no strategy is configured.
+func (d *Defaults) GitVersionStrategy() string {
+	if d == nil || d.Git == nil || d.Git.Version == "" {
+		return VersionStrategyHead
+	}
+	return d.Git.Version
+}
+
+// UseGoMod reports whether go-mod sources without a version should be resolved from go.mod.
+// It returns true when no preference is configured.
+func (d *Defaults) UseGoMod() bool {
+	if d == nil || d.GoMod == nil || d.GoMod.UseGoMod == nil {
+		return true
+	}
+	return *d.GoMod.UseGoMod
+}
+
+// Validate validates the defaults section.
+// It returns ErrorInvalidVersionStrategy if an unknown strategy is configured.
+func (d *Defaults) Validate() error {
+	switch strategy := d.GitVersionStrategy(); strategy {
+	case VersionStrategyHead, VersionStrategyLatestTag:
+	default:
+		return &ErrorInvalidVersionStrategy{SourceType: "git", Strategy: strategy}
+	}
+
+	return nil
 }
 
 // Skill represents a single skill entry in the configuration.
 // It contains all metadata required for skill installation and verification.
 // Requirements: 2.2, 2.3, 2.4, 5.2, 11.4
 type Skill struct {
+	Name         string   `toml:"name"`
+	Source       string   `toml:"source"`                  // "git", "go-mod", "npm", "oci", "archive", "local"
+	URL          string   `toml:"url"`                     // Git URL, Go module path, npm package name, OCI repository, archive URL, directory path
+	Version      string   `toml:"version,omitempty"`       // Tag, commit hash, or semantic version
+	HashValue    string   `toml:"hash_value,omitempty"`    // Hash value with algorithm prefix (e.g., "h1:<base64>")
+	SHA256       string   `toml:"sha256,omitempty"`        // Hex SHA-256 digest of the file an archive source downloads
+	SubDir       string   `toml:"subdir,omitempty"`        // Subdirectory within the downloaded source (e.g., "skills/my-agent")
+	VerifyIgnore []string `toml:"verify_ignore,omitempty"` // Gitignore-style patterns of files changed at runtime, left out of the hash (e.g., "cache/**")
+	Preserve     []string `toml:"preserve,omitempty"`      // Gitignore-style patterns of files agents write into the installed skill, kept across updates and left out of the hash
+	InstallAs    string   `toml:"install_as,omitempty"`    // Directory name in the install targets; defaults to the skill name
+	// Priority orders installation: skills with a higher priority are installed first, and recorded in the
+	// skill metadata so that agents merging same-named resources of several skills can prefer them. Default 0.
+	Priority int `toml:"priority,omitempty"`
+	// SubDirs lists subdirectories or glob patterns (e.g., "skills/*") within the downloaded source,
+	// each installed as a separate skill named after its last path element from a single download.
+	SubDirs []string       `toml:"sub_dirs,omitempty"`
+	Members []*SkillMember `toml:"members,omitempty"` // Skills installed from SubDirs, recorded at installation
+	Canary  *SkillCanary   `toml:"canary,omitempty"`  // Newer version on trial in a single install target
+	// OS restricts the skill to the operating systems, named as in GOOS (e.g., ["darwin", "linux"]).
+	// On other operating systems the entry is ignored. An empty list installs the skill everywhere.
+	OS []string `toml:"os,omitempty"`
+	// Insecure exempts the skill from the integrity policies it cannot satisfy, such as an internal
+	// source that rewrites its releases: hash mismatches only warn under hash_mismatch = "fail", and
+	// frozen and signed installs accept versions other than the locked one. Every use is warned about.
+	Insecure bool `toml:"insecure,omitempty"`
+	// Link installs a local skill into the local install targets as symbolic links to its directory,
+	// so that edits apply without reinstalling. Only for the local source.
+	Link bool `toml:"link,omitempty"`
+	// DependsOn names the skills this skill builds on, which are installed before it. The dependencies
+	// that the depends_on field of its SKILL.md declares are added when it is installed.
+	DependsOn []string `toml:"depends_on,omitempty"`
+}
+
+// SkillCanary is a newer version of a skill installed into a single install target for trial.
+// It is recorded by 'update --canary' and replaces the skill's version in every install target
+// once 'update --promote' is run. The other install targets keep the skill's version until then.
+type SkillCanary struct {
+	Target    string `toml:"target"`               // Install target as written in .skillspkg.toml
+	Version   string `toml:"version"`              // Version installed in the target
+	HashValue string `toml:"hash_value,omitempty"` // Hash of the canary version
+}
+
+// SkillMember is a skill installed from one of the sub_dirs of a skill entry.
+type SkillMember struct {
 	Name      string `toml:"name"`
-	Source    string `toml:"source"`                 // "git", "go-mod"
-	URL       string `toml:"url"`                    // Git URL, Go module path
-	Version   string `toml:"version,omitempty"`      // Tag, commit hash, or semantic version
-	HashValue string `toml:"hash_value,omitempty"`   // Hash value with algorithm prefix (e.g., "h1:<base64>")
-	SubDir    string `toml:"subdir,omitempty"`       // Subdirectory within the downloaded source (e.g., "skills/my-agent")
+	SubDir    string `toml:"subdir"`
+	HashValue string `toml:"hash_value,omitempty"`
+}
+
+// DirName returns the name of the skill's directory in the install targets.
+func (s *Skill) DirName() string {
+	if s.InstallAs != "" {
+		return s.InstallAs
+	}
+	return s.Name
+}
+
+// ForTarget returns the skill as it is expected in the install target:
+// with the canary version in the canary target, and unchanged in the others.
+func (s *Skill) ForTarget(target string) *Skill {
+	if s.Canary == nil || s.Canary.Target != target {
+		return s
+	}
+
+	canary := *s
+	canary.Version = s.Canary.Version
+	canary.HashValue = s.Canary.HashValue
+	canary.Canary = nil
+	return &canary
+}
+
+// IsGroup reports whether the entry installs several skills from its sub_dirs.
+func (s *Skill) IsGroup() bool {
+	return len(s.SubDirs) > 0
+}
+
+// InstalledSkills returns the skills the entry installs: the entry itself, or one skill per recorded
+// member for entries with sub_dirs. Entries with sub_dirs that have not been installed yet return themselves.
+func (s *Skill) InstalledSkills() []*Skill {
+	if !s.IsGroup() || len(s.Members) == 0 {
+		return []*Skill{s}
+	}
+
+	skills := make([]*Skill, 0, len(s.Members))
+	for _, member := range s.Members {
+		skills = append(skills, s.memberSkill(member))
+	}
+	return skills
+}
+
+// memberSkill returns the member as a skill sharing the source and version of the entry.
+func (s *Skill) memberSkill(member *SkillMember) *Skill {
+	return &Skill{
+		Name:         member.Name,
+		Source:       s.Source,
+		URL:          s.URL,
+		Version:      s.Version,
+		HashValue:    member.HashValue,
+		SubDir:       member.SubDir,
+		VerifyIgnore: s.VerifyIgnore,
+		Preserve:     s.Preserve,
+		Priority:     s.Priority,
+		Insecure:     s.Insecure,
+	}
+}
+
+// membersMatchSubDirs reports whether the recorded members are the result of the current sub_dirs:
+// every pattern matches a member and every member matches a pattern.
+func (s *Skill) membersMatchSubDirs() bool {
+	if len(s.Members) == 0 {
+		return false
+	}
+
+	matches := func(pattern string, member *SkillMember) bool {
+		ok, err := path.Match(strings.Trim(pattern, "/"), member.SubDir)
+		return err == nil && ok
+	}
+	for _, pattern := range s.SubDirs {
+		if !slices.ContainsFunc(s.Members, func(member *SkillMember) bool { return matches(pattern, member) }) {
+			return false
+		}
+	}
+	for _, member := range s.Members {
+		if !slices.ContainsFunc(s.SubDirs, func(pattern string) bool { return matches(pattern, member) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// findMember returns the recorded member with the given name, or nil if there is none.
+func (s *Skill) findMember(name string) *SkillMember {
+	for _, member := range s.Members {
+		if member.Name == name {
+			return member
+		}
+	}
+	return nil
 }
 
 // Validate validates the skill configuration.
@@ -36,16 +400,114 @@ func (s *Skill) Validate() error {
 
 	// Validate source type (requirement 11.4)
 	validSources := map[string]bool{
-		"git":    true,
-		"go-mod": true,
+		"git":     true,
+		"go-mod":  true,
+		"npm":     true,
+		"oci":     true,
+		"archive": true,
+		"local":   true,
 	}
 	if !validSources[s.Source] {
 		return &ErrorInvalidSource{SourceType: s.Source}
 	}
 
+	if s.SubDir != "" && s.IsGroup() {
+		return &ErrorConflictingSubDirs{SkillName: s.Name}
+	}
+
+	if s.SHA256 != "" && s.Source != "archive" {
+		return &ErrorInvalidSHA256{SkillName: s.Name, Reason: "it is only supported by archive sources"}
+	}
+	if s.SHA256 != "" && !isHexSHA256(s.SHA256) {
+		return &ErrorInvalidSHA256{SkillName: s.Name, Reason: "it must be 64 lowercase hexadecimal digits"}
+	}
+	if s.Link && s.Source != "local" {
+		return &ErrorInvalidLink{SkillName: s.Name, Reason: "it is only supported by local sources"}
+	}
+	if s.Link && s.IsGroup() {
+		return &ErrorInvalidLink{SkillName: s.Name, Reason: "it cannot be combined with 'sub_dirs'"}
+	}
+
+	if value, ok := validatePlatforms(s.OS); !ok {
+		return &ErrorInvalidPlatform{Entry: "skill '" + s.Name + "'", Platform: value}
+	}
+
+	if s.InstallAs != "" {
+		if s.IsGroup() {
+			return &ErrorInvalidInstallAs{SkillName: s.Name, InstallAs: s.InstallAs, Reason: "it cannot be combined with 'sub_dirs', whose skills are named after their directories"}
+		}
+		if s.InstallAs == "." || s.InstallAs == ".." || strings.ContainsAny(s.InstallAs, `/\`) {
+			return &ErrorInvalidInstallAs{SkillName: s.Name, InstallAs: s.InstallAs, Reason: "it must be a single directory name"}
+		}
+	}
+
 	return nil
 }
 
+// isHexSHA256 reports whether s is a SHA-256 digest written as 64 lowercase hexadecimal digits.
+func isHexSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// HashExclude returns the patterns of the files left out of the skill's hash: those changed at runtime
+// listed in verify_ignore, and those written by agents listed in preserve.
+func (s *Skill) HashExclude() []string {
+	if len(s.Preserve) == 0 {
+		return s.VerifyIgnore
+	}
+	return slices.Concat(s.VerifyIgnore, s.Preserve)
+}
+
+// GeneratedFiles returns the patterns of the files skills-pkg writes into installed skills with the
+// configuration, which are not part of the skills: the metadata file when skill_metadata is enabled.
+func (c *Config) GeneratedFiles() []string {
+	if !c.SkillMetadata {
+		return nil
+	}
+	return []string{"/" + SkillMetadataFile}
+}
+
+// HashOptions returns what is left out of the hash of the skill installed with the configuration:
+// the files matching its verify_ignore and preserve patterns, the files skills-pkg generates,
+// and the banner when it is enabled.
+func (c *Config) HashOptions(skill *Skill) []port.HashOption {
+	opts := []port.HashOption{port.ExcludeFiles(slices.Concat(skill.HashExclude(), c.GeneratedFiles())...)}
+	if c.Banner {
+		opts = append(opts, port.StripBanner())
+	}
+	return opts
+}
+
+// Satisfies reports whether the skill fulfills the requested entry: it has the same source,
+// URL, and subdirectories, and the requested version, verify_ignore, and preserve patterns unless the
+// request leaves them open.
+func (s *Skill) Satisfies(requested *Skill) bool {
+	if s.Source != requested.Source || s.URL != requested.URL {
+		return false
+	}
+	if strings.Trim(s.SubDir, "/") != strings.Trim(requested.SubDir, "/") || !slices.Equal(s.SubDirs, requested.SubDirs) {
+		return false
+	}
+	if s.InstallAs != requested.InstallAs {
+		return false
+	}
+	if len(requested.VerifyIgnore) > 0 && !slices.Equal(s.VerifyIgnore, requested.VerifyIgnore) {
+		return false
+	}
+	if len(requested.Preserve) > 0 && !slices.Equal(s.Preserve, requested.Preserve) {
+		return false
+	}
+	return requested.Version == "" || s.Version == requested.Version
+}
+
 // FindSkillByName finds a skill by its name.
 // Returns nil if the skill is not found.
 // Requirements: 8.1, 9.3
@@ -58,6 +520,41 @@ func (c *Config) FindSkillByName(name string) *Skill {
 	return nil
 }
 
+// InstalledSkills returns the skills installed from all entries, expanding entries with sub_dirs into their members.
+func (c *Config) InstalledSkills() []*Skill {
+	var skills []*Skill
+	for _, skill := range c.Skills {
+		skills = append(skills, skill.InstalledSkills()...)
+	}
+	return skills
+}
+
+// FindInstalledSkill finds an installed skill by its name, including members of entries with sub_dirs.
+// Returns nil if the skill is not found.
+func (c *Config) FindInstalledSkill(name string) *Skill {
+	for _, skill := range c.InstalledSkills() {
+		if skill.Name == name {
+			return skill
+		}
+	}
+	return nil
+}
+
+// FindSkillEntry finds the entry that installs the skill with the given name:
+// the entry with that name, or the entry with sub_dirs the skill is a member of.
+// Returns nil if the skill is not found.
+func (c *Config) FindSkillEntry(name string) *Skill {
+	if skill := c.FindSkillByName(name); skill != nil {
+		return skill
+	}
+	for _, skill := range c.Skills {
+		if skill.findMember(name) != nil {
+			return skill
+		}
+	}
+	return nil
+}
+
 // HasSkill checks if a skill with the given name exists.
 // Requirements: 2.3
 func (c *Config) HasSkill(name string) bool {
@@ -68,6 +565,56 @@ func (c *Config) HasSkill(name string) bool {
 // It checks for duplicate skill names and validates each skill.
 // Requirements: 2.1, 2.2, 12.2, 12.3
 func (c *Config) Validate() error {
+	if err := c.Defaults.Validate(); err != nil {
+		return err
+	}
+
+	switch algorithm := c.EffectiveHashAlgorithm(); algorithm {
+	case port.HashAlgorithmH1, port.HashAlgorithmN1:
+	default:
+		return &ErrorInvalidHashAlgorithm{Algorithm: algorithm}
+	}
+
+	switch policy := c.EffectiveHashMismatch(); policy {
+	case HashMismatchWarn, HashMismatchFail, HashMismatchReinstall:
+	default:
+		return &ErrorInvalidHashMismatchPolicy{Policy: policy}
+	}
+
+	switch c.HiddenCharacters {
+	case "", HiddenCharactersWarn, HiddenCharactersStrip:
+	default:
+		return &ErrorInvalidHiddenCharactersPolicy{Policy: c.HiddenCharacters}
+	}
+
+	switch policy := c.EffectiveSymlinks(); policy {
+	case SymlinksFlatten, SymlinksReject:
+	default:
+		return &ErrorInvalidSymlinksPolicy{Policy: policy}
+	}
+
+	if c.MaxDepth < 0 {
+		return &ErrorInvalidMaxDepth{MaxDepth: c.MaxDepth}
+	}
+
+	for _, recipient := range c.Recipients {
+		if err := ValidateRecipient(recipient); err != nil {
+			return err
+		}
+	}
+
+	if c.Signing != nil {
+		if err := c.Signing.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, target := range slices.Sorted(maps.Keys(c.Targets)) {
+		if err := c.Targets[target].Validate(target); err != nil {
+			return err
+		}
+	}
+
 	// Check for duplicate skill names (requirement 2.2)
 	nameMap := make(map[string]bool)
 	for _, skill := range c.Skills {
@@ -76,11 +623,42 @@ func (c *Config) Validate() error {
 		}
 		nameMap[skill.Name] = true
 
+		// Members are installed next to other skills, so their names must be unique too
+		for _, member := range skill.Members {
+			if member.Name != skill.Name && nameMap[member.Name] {
+				return &ErrorSkillExists{SkillName: member.Name}
+			}
+			nameMap[member.Name] = true
+		}
+
 		// Validate each skill
 		if err := skill.Validate(); err != nil {
 			return err
 		}
 	}
 
+	// Skills of other platforms are written back on save, so they may only share a name with skills
+	// installed on other operating systems
+	for _, other := range c.others.skills {
+		if skill := c.FindSkillByName(other.value.Name); skill != nil && platformsOverlap(skill.OS, other.value.OS) {
+			return &ErrorSkillExists{SkillName: other.value.Name}
+		}
+	}
+
+	// Dependencies must be in the configuration and must not depend on each other in a cycle
+	if _, err := resolveDependencies(c, c.Skills); err != nil {
+		return err
+	}
+
+	// Skills installed into the same directory would overwrite each other
+	dirMap := make(map[string]string)
+	for _, skill := range c.InstalledSkills() {
+		dir := skill.DirName()
+		if other, ok := dirMap[dir]; ok {
+			return &ErrorInstallDirConflict{Dir: dir, SkillNames: []string{other, skill.Name}}
+		}
+		dirMap[dir] = skill.Name
+	}
+
 	return nil
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileDir is the directory of a project holding its configuration profiles, one <profile>.toml file each.
+const ProfileDir = ".skillspkg"
+
+// ProfileConfigPath returns the path of the configuration file of profile, relative to the project directory.
+// It returns ErrorInvalidProfile when profile is not a plain file name.
+func ProfileConfigPath(profile string) (string, error) {
+	if profile == "" || profile == "." || profile == ".." || strings.ContainsAny(profile, `/\`) {
+		return "", &ErrorInvalidProfile{Profile: profile}
+	}
+	return filepath.Join(ProfileDir, profile+".toml"), nil
+}
+
+// FindProject looks for the configuration file at configPath, relative to the project directory, in dir
+// and its parent directories, the way git looks for the repository, so that commands work from
+// subdirectories of the project. It returns the directory the configuration file was found in.
+// The search stops at the root of the Git repository that contains dir, as a configuration outside of
+// the repository belongs to another project. It returns "" when no configuration file is found.
+func FindProject(dir, configPath string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		info, err := os.Stat(filepath.Join(dir, configPath))
+		switch {
+		case err == nil && !info.IsDir():
+			return dir, nil
+		case err != nil && !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrPermission):
+			return "", err
+		}
+
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
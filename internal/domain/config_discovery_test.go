@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestFindProject(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	mkdir := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(path)), 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+	}
+	touch := func(path string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(path)), nil, 0o644); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+	}
+
+	touch(".skillspkg.toml")
+	mkdir("project/.git")
+	mkdir("project/src/pkg")
+	touch("project/.skillspkg.toml")
+	mkdir("repo/.git")
+	mkdir("repo/sub")
+	mkdir("plain/sub/dir")
+	mkdir("nested/.skillspkg.toml")
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: "project", want: "project"},
+		{dir: "project/src/pkg", want: "project"},
+		{dir: "plain/sub/dir", want: "."},
+		{dir: "repo/sub", want: ""}, // The search stops at the repository root
+		{dir: "nested", want: "."},  // Directories named like the configuration are skipped
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.FindProject(filepath.Join(root, filepath.FromSlash(tt.dir)), domain.ConfigFileName)
+			if err != nil {
+				t.Fatalf("FindProject() error = %v", err)
+			}
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(root, filepath.FromSlash(tt.want))
+			}
+			if got != want {
+				t.Errorf("FindProject() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestProfileConfigPath(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ProfileConfigPath("docs-team")
+	if err != nil || got != filepath.Join(".skillspkg", "docs-team.toml") {
+		t.Errorf("ProfileConfigPath() = %q, %v", got, err)
+	}
+
+	for _, profile := range []string{"", "..", "a/b"} {
+		if _, err := domain.ProfileConfigPath(profile); err == nil {
+			t.Errorf("ProfileConfigPath(%q) should fail", profile)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// configSettings are the top-level settings a configuration takes from the configurations it extends
+// unless it sets them itself. Skills, install targets, target settings, and recipients are merged instead.
+var configSettings = []struct {
+	key string
+	set func(dst, src *Config)
+}{
+	{"defaults", func(dst, src *Config) { dst.Defaults = src.Defaults }},
+	{"hash_algorithm", func(dst, src *Config) { dst.HashAlgorithm = src.HashAlgorithm }},
+	{"shared_store", func(dst, src *Config) { dst.SharedStore = src.SharedStore }},
+	{"banner", func(dst, src *Config) { dst.Banner = src.Banner }},
+	{"skill_metadata", func(dst, src *Config) { dst.SkillMetadata = src.SkillMetadata }},
+	{"hash_mismatch", func(dst, src *Config) { dst.HashMismatch = src.HashMismatch }},
+	{"policy", func(dst, src *Config) { dst.Policy = src.Policy }},
+	{"scanner", func(dst, src *Config) { dst.Scanner = src.Scanner }},
+	{"hardened_extraction", func(dst, src *Config) { dst.HardenedExtraction = src.HardenedExtraction }},
+	{"lint", func(dst, src *Config) { dst.Lint = src.Lint }},
+	{"hidden_characters", func(dst, src *Config) { dst.HiddenCharacters = src.HiddenCharacters }},
+	{"symlinks", func(dst, src *Config) { dst.Symlinks = src.Symlinks }},
+	{"max_depth", func(dst, src *Config) { dst.MaxDepth = src.MaxDepth }},
+	{"copy", func(dst, src *Config) { dst.Copy = src.Copy }},
+	{"require_review", func(dst, src *Config) { dst.RequireReview = src.RequireReview }},
+	{"signing", func(dst, src *Config) { dst.Signing = src.Signing }},
+	{"org", func(dst, src *Config) { dst.Org = src.Org }},
+}
+
+// baseEntries records what a configuration took from the configurations it extends, so that only
+// its own entries are written back when it is saved.
+type baseEntries struct {
+	own        *Config                    // The configuration as written in its file
+	inherited  *Config                    // The configuration as merged, to tell inherited settings that were changed
+	keys       []string                   // Settings taken from the bases
+	skills     map[*Skill]Skill           // Skills of the bases, with their contents when they were merged
+	targets    []string                   // Install targets of the bases that the configuration does not list itself
+	settings   map[string]*TargetSettings // Target settings of the bases that the configuration does not set itself
+	recipients []string                   // Recipients of the bases that the configuration does not list itself
+}
+
+// WithBaseSources downloads the remote configurations listed in extends with packageManagers, keeping
+// downloads of fixed versions in cache when it is not nil. Without it, only local files can be extended.
+func WithBaseSources(packageManagers []port.PackageManager, cache *DownloadCache) ConfigManagerOption {
+	return func(m *ConfigManager) {
+		m.basePackageManagers = packageManagers
+		m.baseCache = cache
+	}
+}
+
+// parseConfig parses the configuration in data, read from path, and merges the configurations it extends.
+// Skills and install targets of other platforms are set aside. The hashes of the remote configurations
+// it extends, directly or indirectly, are added to pins by their entries of extends.
+func (m *ConfigManager) parseConfig(ctx context.Context, path string, data []byte, visited []string, pins map[string]string) (*Config, error) {
+	var config Config
+	if err := toml.Unmarshal(data, &config); err != nil {
+		// TOML parse error - provide detailed error message (requirement 2.6)
+		return nil, fmt.Errorf("failed to parse configuration file at %s: %w. Ensure the file is valid TOML format", path, err)
+	}
+
+	// Encrypted values are decrypted here for every setting, and encrypted again when the configuration is saved
+	secrets, err := decryptSecrets(&config, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt a value in configuration file at %s: %w", path, err)
+	}
+	config.secrets = secrets
+
+	// Skills and install targets of other operating systems are written back on save
+	if err := config.selectPlatform(runtime.GOOS); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	if len(config.Extends) == 0 {
+		return &config, nil
+	}
+
+	var keys map[string]any
+	if err := toml.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file at %s: %w", path, err)
+	}
+
+	var base *Config
+	for _, ref := range config.Extends {
+		extended, err := m.loadBase(ctx, ref, filepath.Dir(path), append(visited, path), pins)
+		if err != nil {
+			return nil, err
+		}
+		if base == nil {
+			base = extended
+			continue
+		}
+		base = mergeConfig(base, extended, nil)
+	}
+
+	return mergeConfig(base, &config, keys), nil
+}
+
+// loadBase reads the configuration that ref names, relative to dir for local files.
+// visited are the configurations that extend it, to detect cycles.
+func (m *ConfigManager) loadBase(ctx context.Context, ref, dir string, visited []string, pins map[string]string) (*Config, error) {
+	path, err := m.resolveBase(ctx, ref, dir, ConfigFileName)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(visited, path) {
+		return nil, &ErrorInvalidExtends{Ref: ref, Reason: "it extends the configuration that extends it"}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ErrorInvalidExtends{Ref: ref, Reason: err.Error()}
+	}
+	base, err := m.parseConfig(ctx, path, data, visited, pins)
+	if err != nil {
+		return nil, err
+	}
+	if !isLocalBase(ref) {
+		if err := checkRemoteBase(ref, base); err != nil {
+			return nil, err
+		}
+		hash, err := hashBaseDir(filepath.Dir(path))
+		if err != nil {
+			return nil, &ErrorInvalidExtends{Ref: ref, Reason: err.Error()}
+		}
+		pins[ref] = hash
+	}
+
+	// Policies of a base are relative to its file
+	if base.Policy != "" && !filepath.IsAbs(base.Policy) {
+		base.Policy = filepath.Join(filepath.Dir(path), base.Policy)
+	}
+	// So are public key files
+	if base.Signing != nil && !filepath.IsAbs(base.Signing.PublicKey) {
+		key := filepath.Join(filepath.Dir(path), base.Signing.PublicKey)
+		if _, err := os.Stat(key); err == nil {
+			base.Signing.PublicKey = key
+		}
+	}
+	return base, nil
+}
+
+// checkRemoteBase checks that a remote configuration does not set what would let whoever can move its
+// version run commands or write files on every machine that extends it, or weaken the integrity checks:
+// the scanner command, install targets and their settings, the policy, the signing key, a hash_mismatch
+// other than "fail", a max_depth above the default, and insecure skills.
+func checkRemoteBase(ref string, base *Config) error {
+	var keys []string
+	if len(base.Scanner) > 0 {
+		keys = append(keys, "scanner")
+	}
+	if len(base.InstallTargets) > 0 {
+		keys = append(keys, "install_targets")
+	}
+	if len(base.Targets) > 0 {
+		keys = append(keys, "targets")
+	}
+	if base.Policy != "" {
+		keys = append(keys, "policy")
+	}
+	if base.Signing != nil {
+		keys = append(keys, "signing")
+	}
+	if base.HashMismatch != "" && base.HashMismatch != HashMismatchFail {
+		keys = append(keys, "hash_mismatch")
+	}
+	if base.MaxDepth > DefaultMaxDepth {
+		keys = append(keys, "max_depth")
+	}
+	if slices.ContainsFunc(base.Skills, func(skill *Skill) bool { return skill.Insecure }) {
+		keys = append(keys, "insecure skills")
+	}
+	if len(keys) > 0 {
+		return &ErrorInvalidExtends{Ref: ref, Reason: fmt.Sprintf("remote configurations cannot set %s; set them in the project itself", strings.Join(keys, ", "))}
+	}
+	return nil
+}
+
+// hashBaseDir returns the hash of the files of a downloaded remote configuration, leaving out .git.
+func hashBaseDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(h, "%x  %s\n", sum, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the configuration: %w", err)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// pinBases checks the hashes of the remote configurations in pins against the lock file, and records
+// those it does not have yet. Configurations that are no longer extended are removed from it.
+func (m *ConfigManager) pinBases(ctx context.Context, pins map[string]string) error {
+	lockManager := NewLockManager(LockPathFor(m.configPath))
+	lock, err := lockManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, ref := range slices.Sorted(maps.Keys(pins)) {
+		locked := lock.FindBase(ref)
+		if locked == nil {
+			changed = true
+			continue
+		}
+		if locked.HashValue != pins[ref] {
+			return &ErrorBaseChanged{Ref: ref, Locked: locked.HashValue, Actual: pins[ref]}
+		}
+	}
+	if slices.ContainsFunc(lock.Bases, func(base *LockedBase) bool { _, ok := pins[base.Ref]; return !ok }) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	return lockManager.Update(ctx, func(lock *LockFile) {
+		lock.Bases = nil
+		for _, ref := range slices.Sorted(maps.Keys(pins)) {
+			lock.Bases = append(lock.Bases, &LockedBase{Ref: ref, HashValue: pins[ref]})
+		}
+	})
+}
+
+// resolveBase returns the path of the configuration file that ref names.
+// Local files are relative to dir. Remote configurations, written as <repository>@<version>, are the
+// file called name at the root of the Git repository at that version.
+func (m *ConfigManager) resolveBase(ctx context.Context, ref, dir, name string) (string, error) {
+	if isLocalBase(ref) {
+		if filepath.IsAbs(ref) {
+			return filepath.Clean(ref), nil
+		}
+		return filepath.Abs(filepath.Join(dir, ref))
+	}
+
+	i := strings.LastIndex(ref, "@")
+	if i <= 0 || i == len(ref)-1 {
+		return "", &ErrorInvalidExtends{Ref: ref, Reason: "remote configurations are written as <repository>@<version>, and local files start with ./ or ../ or end in .toml"}
+	}
+	url, version := ref[:i], ref[i+1:]
+	if !strings.Contains(url, "://") && !strings.HasPrefix(url, "git@") {
+		url = "https://" + url
+	}
+	source := &port.Source{Type: "git", URL: url}
+
+	i = slices.IndexFunc(m.basePackageManagers, func(pm port.PackageManager) bool { return pm.SourceType() == source.Type })
+	if i < 0 {
+		return "", &ErrorInvalidExtends{Ref: ref, Reason: "remote configurations cannot be loaded by this command"}
+	}
+
+	if m.baseCache != nil {
+		if result, ok := m.baseCache.Get(source, version); ok {
+			return filepath.Join(result.Path, name), nil
+		}
+	}
+	result, err := m.basePackageManagers[i].Download(ctx, source, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to download configuration %s: %w", ref, err)
+	}
+	if m.baseCache != nil {
+		if result, err = m.baseCache.Put(source, version, result); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(result.Path, name), nil
+}
+
+// isLocalBase reports whether ref names a local configuration file rather than a remote one.
+func isLocalBase(ref string) bool {
+	return filepath.IsAbs(ref) || strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") || strings.HasSuffix(ref, ".toml")
+}
+
+// mergeConfig returns config merged onto base. Settings in keys, the keys set in the file of config,
+// are taken from config and the others from base; keys == nil takes every setting of config that is set.
+// Skills of config replace the skills of base with the same name, and install targets, target settings,
+// and recipients of both are combined.
+func mergeConfig(base, config *Config, keys map[string]any) *Config {
+	merged := *config
+	merged.secrets = maps.Clone(base.secrets)
+	if merged.secrets == nil {
+		merged.secrets = map[string]string{}
+	}
+	maps.Copy(merged.secrets, config.secrets)
+	entries := &baseEntries{own: config, skills: map[*Skill]Skill{}, settings: map[string]*TargetSettings{}}
+	for _, setting := range configSettings {
+		if keys != nil {
+			if _, ok := keys[setting.key]; ok {
+				continue
+			}
+		} else if !settingEqual(config, &Config{}, setting.set) {
+			continue
+		}
+		setting.set(&merged, base)
+		entries.keys = append(entries.keys, setting.key)
+	}
+	merged.Skills = nil
+	for _, skill := range base.Skills {
+		if config.FindSkillByName(skill.Name) != nil {
+			continue
+		}
+		merged.Skills = append(merged.Skills, skill)
+		entries.skills[skill] = *skill
+	}
+	merged.Skills = append(merged.Skills, config.Skills...)
+
+	merged.InstallTargets = nil
+	for _, target := range base.InstallTargets {
+		if !slices.Contains(config.InstallTargets, target) {
+			merged.InstallTargets = append(merged.InstallTargets, target)
+			entries.targets = append(entries.targets, target)
+		}
+	}
+	merged.InstallTargets = append(merged.InstallTargets, config.InstallTargets...)
+
+	if len(base.Targets) > 0 {
+		merged.Targets = maps.Clone(config.Targets)
+		if merged.Targets == nil {
+			merged.Targets = map[string]*TargetSettings{}
+		}
+		for target, settings := range base.Targets {
+			if _, ok := merged.Targets[target]; !ok {
+				merged.Targets[target] = settings
+				entries.settings[target] = settings
+			}
+		}
+	}
+
+	merged.Recipients = nil
+	for _, recipient := range base.Recipients {
+		if !slices.Contains(config.Recipients, recipient) {
+			merged.Recipients = append(merged.Recipients, recipient)
+			entries.recipients = append(entries.recipients, recipient)
+		}
+	}
+	merged.Recipients = append(merged.Recipients, config.Recipients...)
+
+	inherited := merged
+	entries.inherited = &inherited
+	merged.bases = entries
+	return &merged
+}
+
+// settingEqual reports whether the setting that set copies is the same in a and b.
+func settingEqual(a, b *Config, set func(dst, src *Config)) bool {
+	var x, y Config
+	set(&x, a)
+	set(&y, b)
+	return reflect.DeepEqual(x, y)
+}
+
+// withoutBases returns the configuration without the settings and entries it took from the
+// configurations it extends, as it is written to its file. Skills of a base that were changed,
+// such as by 'update', are written as well and replace the skill of the base from then on.
+func (c *Config) withoutBases() *Config {
+	if c.bases == nil {
+		return c
+	}
+
+	own := *c
+	own.bases = nil
+	for _, setting := range configSettings {
+		if slices.Contains(c.bases.keys, setting.key) && settingEqual(c, c.bases.inherited, setting.set) {
+			setting.set(&own, c.bases.own)
+		}
+	}
+	own.Skills = slices.DeleteFunc(slices.Clone(c.Skills), func(skill *Skill) bool {
+		original, ok := c.bases.skills[skill]
+		return ok && reflect.DeepEqual(*skill, original)
+	})
+	own.InstallTargets = slices.DeleteFunc(slices.Clone(c.InstallTargets), func(target string) bool {
+		return slices.Contains(c.bases.targets, target)
+	})
+	if len(c.bases.settings) > 0 {
+		own.Targets = maps.Clone(c.Targets)
+		maps.DeleteFunc(own.Targets, func(target string, settings *TargetSettings) bool {
+			return c.bases.settings[target] == settings
+		})
+	}
+	own.Recipients = slices.DeleteFunc(slices.Clone(c.Recipients), func(recipient string) bool {
+		return slices.Contains(c.bases.recipients, recipient)
+	})
+	return &own
+}
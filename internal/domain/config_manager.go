@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"slices"
 
+	"github.com/mazrean/skills-pkg/internal/port"
 	"github.com/pelletier/go-toml/v2"
 )
 
@@ -19,13 +22,31 @@ const (
 // It provides methods for initializing, loading, and saving configuration.
 // Requirements: 1.1-1.5, 2.1-2.6, 8.1-8.4, 10.1, 11.4
 type ConfigManager struct {
-	configPath string
+	logger              *slog.Logger   // Receives warnings about the configuration; os.Stderr by default
+	baseCache           *DownloadCache // Cache of remote configurations listed in extends; nil disables caching
+	configPath          string
+	basePackageManagers []port.PackageManager // Download remote configurations listed in extends
+}
+
+// ConfigManagerOption configures optional behavior of a ConfigManager.
+type ConfigManagerOption func(*ConfigManager)
+
+// WithConfigLogger logs warnings about the configuration, such as duplicate install targets,
+// to logger at the warning level instead of writing them to os.Stderr.
+func WithConfigLogger(logger *slog.Logger) ConfigManagerOption {
+	return func(m *ConfigManager) {
+		m.logger = logger
+	}
 }
 
 // NewConfigManager creates a new ConfigManager instance.
 // The configPath parameter specifies the path to the .skillspkg.toml file.
-func NewConfigManager(configPath string) *ConfigManager {
-	return &ConfigManager{configPath: configPath}
+func NewConfigManager(configPath string, opts ...ConfigManagerOption) *ConfigManager {
+	m := &ConfigManager{configPath: configPath, logger: slog.New(NewMessageHandler(os.Stderr, slog.LevelInfo))}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 // Initialize creates a new .skillspkg.toml file with the specified install directories.
@@ -47,6 +68,11 @@ func (m *ConfigManager) Initialize(ctx context.Context, installDirs []string) er
 		InstallTargets: installDirs,
 	}
 
+	// Profiles are created in a directory of their own
+	if err := os.MkdirAll(filepath.Dir(m.configPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", m.configPath, err)
+	}
+
 	// Use Save method to write the config file (requirement 1.1)
 	return m.Save(ctx, config)
 }
@@ -67,11 +93,26 @@ func (m *ConfigManager) Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("failed to read configuration file at %s: %w. Check file permissions", m.configPath, err)
 	}
 
-	// Parse TOML content
-	var config Config
-	if err := toml.Unmarshal(data, &config); err != nil {
-		// TOML parse error - provide detailed error message (requirement 2.6)
-		return nil, fmt.Errorf("failed to parse configuration file at %s: %w. Ensure the file is valid TOML format", m.configPath, err)
+	// Parse TOML content and merge the configurations it extends
+	path, err := filepath.Abs(m.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve configuration file path %s: %w", m.configPath, err)
+	}
+	pins := map[string]string{}
+	config, err := m.parseConfig(ctx, path, data, nil, pins)
+	if err != nil {
+		return nil, err
+	}
+	// Remote configurations in extends must have the content recorded in the lock file
+	if len(pins) > 0 {
+		if err := m.pinBases(ctx, pins); err != nil {
+			return nil, err
+		}
+	}
+
+	// Skills are installed into each directory once, however often it is listed
+	for _, d := range config.DedupeInstallTargets() {
+		m.logger.WarnContext(ctx, fmt.Sprintf("WARNING: install target %s in %s is the same directory as %s and is ignored. It is removed the next time the configuration is saved", d.Target, m.configPath, d.SameAs))
 	}
 
 	// Validate the loaded configuration
@@ -79,22 +120,26 @@ func (m *ConfigManager) Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // Save writes the configuration to the .skillspkg.toml file.
 // It provides detailed error messages for file system errors (requirement 12.2, 12.3).
 // Requirements: 2.1, 12.2, 12.3
 func (m *ConfigManager) Save(ctx context.Context, config *Config) error {
+	for _, d := range config.DedupeInstallTargets() {
+		m.logger.WarnContext(ctx, fmt.Sprintf("WARNING: install target %s is the same directory as %s and is not saved", d.Target, d.SameAs))
+	}
+
 	// Validate the configuration before saving
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
 	// Marshal config to TOML format
-	data, err := toml.Marshal(config)
+	data, err := EncodeConfig(config)
 	if err != nil {
-		return fmt.Errorf("failed to marshal configuration: %w", err)
+		return err
 	}
 
 	// Write config file
@@ -106,6 +151,20 @@ func (m *ConfigManager) Save(ctx context.Context, config *Config) error {
 	return nil
 }
 
+// EncodeConfig returns the configuration in the .skillspkg.toml format.
+// Skills and install targets of other operating systems set aside when it was loaded are included,
+// and the entries taken from the configurations it extends are not.
+// Values that were encrypted are written encrypted.
+func EncodeConfig(config *Config) ([]byte, error) {
+	encoded := config.withoutBases().withOtherPlatforms()
+	defer config.sealSecrets(encoded)()
+	data, err := toml.Marshal(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	return data, nil
+}
+
 // AddSkillToConfig adds a new skill entry to the configuration in memory.
 // It returns the updated Config without saving to file.
 // This is useful when you want to add a skill and perform additional operations
@@ -132,6 +191,40 @@ func (m *ConfigManager) AddSkillToConfig(ctx context.Context, skill *Skill) (*Co
 	// Add the skill to the config
 	config.Skills = append(config.Skills, skill)
 
+	// Reject skills that would be installed into the directory of another skill
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ReplaceSkillInConfig adds a skill entry to the configuration in memory, replacing the entry
+// with the same name in place if one exists.
+// It returns the updated Config without saving to file.
+func (m *ConfigManager) ReplaceSkillInConfig(ctx context.Context, skill *Skill) (*Config, error) {
+	// Validate the skill before adding
+	if err := skill.Validate(); err != nil {
+		return nil, fmt.Errorf("skill validation failed: %w", err)
+	}
+
+	// Load the current config
+	config, err := m.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if i := slices.IndexFunc(config.Skills, func(existing *Skill) bool { return existing.Name == skill.Name }); i >= 0 {
+		config.Skills[i] = skill
+	} else {
+		config.Skills = append(config.Skills, skill)
+	}
+
+	// Reject skills that would be installed into the directory of another skill
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -251,6 +344,58 @@ func (m *ConfigManager) GetInstallTargets(ctx context.Context) ([]string, error)
 	return config.InstallTargets, nil
 }
 
+// MergeInstallTargets adds the install targets that the configuration does not have yet, skipping
+// those that name the same directory as an existing target or an earlier one in targets.
+// The configuration is saved when any target is added. It returns the added targets.
+func (m *ConfigManager) MergeInstallTargets(ctx context.Context, targets []string) ([]string, error) {
+	config, err := m.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var added []string
+	for _, target := range targets {
+		if slices.ContainsFunc(config.InstallTargets, func(existing string) bool { return SameTarget(existing, target) }) {
+			continue
+		}
+		config.InstallTargets = append(config.InstallTargets, target)
+		added = append(added, target)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if err := m.Save(ctx, config); err != nil {
+		return nil, fmt.Errorf("failed to save configuration after adding install targets: %w", err)
+	}
+
+	return added, nil
+}
+
+// RemoveInstallTarget removes the install target that names the same directory as target from the
+// configuration, together with its settings, and saves it. It returns the target as it was written
+// in the configuration, or ErrorInstallTargetNotFound when no target names the directory.
+func (m *ConfigManager) RemoveInstallTarget(ctx context.Context, target string) (string, error) {
+	config, err := m.Load(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	i := slices.IndexFunc(config.InstallTargets, func(existing string) bool { return SameTarget(existing, target) })
+	if i < 0 {
+		return "", &ErrorInstallTargetNotFound{Target: target}
+	}
+	removed := config.InstallTargets[i]
+	config.InstallTargets = slices.Delete(config.InstallTargets, i, i+1)
+	delete(config.Targets, removed)
+
+	if err := m.Save(ctx, config); err != nil {
+		return "", fmt.Errorf("failed to save configuration after removing install target '%s': %w", removed, err)
+	}
+
+	return removed, nil
+}
+
 // AddInstallTarget adds a new install target directory to the configuration.
 // It returns ErrInstallTargetExists if the target already exists.
 func (m *ConfigManager) AddInstallTarget(ctx context.Context, target string) error {
@@ -1,10 +1,15 @@
 package domain_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
@@ -688,6 +693,259 @@ func TestConfigManager_UpdateSkill(t *testing.T) {
 	}
 }
 
+func TestConfigManager_ReplaceSkillInConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := domain.NewConfigManager(filepath.Join(tmpDir, ".skillspkg.toml"))
+	ctx := context.Background()
+	setupConfig := &domain.Config{
+		InstallTargets: []string{"~/.claude/skills"},
+		Skills: []*domain.Skill{
+			{Name: "first", Source: "git", URL: "https://github.com/test/first.git", Version: "v1.0.0", HashValue: "old-hash"},
+			{Name: "second", Source: "git", URL: "https://github.com/test/second.git", Version: "v1.0.0"},
+		},
+	}
+	if err := manager.Save(ctx, setupConfig); err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	config, err := manager.ReplaceSkillInConfig(ctx, &domain.Skill{Name: "first", Source: "go-mod", URL: "github.com/test/first", Version: "v2.0.0"})
+	if err != nil {
+		t.Fatalf("ConfigManager.ReplaceSkillInConfig() unexpected error = %v", err)
+	}
+	if len(config.Skills) != 2 {
+		t.Fatalf("expected 2 skills, got %d", len(config.Skills))
+	}
+	if got := config.Skills[0]; got.Name != "first" || got.Source != "go-mod" || got.Version != "v2.0.0" || got.HashValue != "" {
+		t.Errorf("expected the first skill to be replaced in place, got %+v", got)
+	}
+
+	config, err = manager.ReplaceSkillInConfig(ctx, &domain.Skill{Name: "third", Source: "git", URL: "https://github.com/test/third.git"})
+	if err != nil {
+		t.Fatalf("ConfigManager.ReplaceSkillInConfig() unexpected error = %v", err)
+	}
+	if len(config.Skills) != 3 || config.Skills[2].Name != "third" {
+		t.Errorf("expected a new skill to be appended, got %+v", config.Skills)
+	}
+
+	// The configuration is not saved
+	saved, err := manager.Load(ctx)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if saved.Skills[0].Source != "git" {
+		t.Errorf("expected the saved configuration to be unchanged, got %+v", saved.Skills[0])
+	}
+}
+
+func TestConfigManager_MergeInstallTargets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	manager := domain.NewConfigManager(configPath)
+	ctx := context.Background()
+	if err := manager.Initialize(ctx, []string{"./.claude/skills"}); err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	added, err := manager.MergeInstallTargets(ctx, []string{".claude/skills/", "./.codex/skills", ".codex/skills"})
+	if err != nil {
+		t.Fatalf("ConfigManager.MergeInstallTargets() unexpected error = %v", err)
+	}
+	if len(added) != 1 || added[0] != "./.codex/skills" {
+		t.Errorf("expected only ./.codex/skills to be added, got %v", added)
+	}
+
+	targets, err := manager.GetInstallTargets(ctx)
+	if err != nil {
+		t.Fatalf("failed to get install targets: %v", err)
+	}
+	if len(targets) != 2 || targets[0] != "./.claude/skills" || targets[1] != "./.codex/skills" {
+		t.Errorf("expected the new target to be saved after the existing one, got %v", targets)
+	}
+
+	added, err = manager.MergeInstallTargets(ctx, []string{"./.codex/skills"})
+	if err != nil || len(added) != 0 {
+		t.Errorf("expected nothing to be added again, got %v, %v", added, err)
+	}
+}
+
+func TestConfigManager_RemoveInstallTarget(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	manager := domain.NewConfigManager(configPath)
+	ctx := context.Background()
+	config := &domain.Config{
+		InstallTargets: []string{"./.claude/skills", "./.codex/skills"},
+		Targets:        map[string]*domain.TargetSettings{"./.codex/skills": {DirMode: "0755"}},
+	}
+	if err := manager.Save(ctx, config); err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	removed, err := manager.RemoveInstallTarget(ctx, ".codex/skills/")
+	if err != nil {
+		t.Fatalf("ConfigManager.RemoveInstallTarget() unexpected error = %v", err)
+	}
+	if removed != "./.codex/skills" {
+		t.Errorf("expected the target to be returned as written in the configuration, got %s", removed)
+	}
+
+	saved, err := manager.Load(ctx)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(saved.InstallTargets) != 1 || saved.InstallTargets[0] != "./.claude/skills" {
+		t.Errorf("expected only ./.claude/skills to remain, got %v", saved.InstallTargets)
+	}
+	if len(saved.Targets) != 0 {
+		t.Errorf("expected the settings of the removed target to be deleted, got %v", saved.Targets)
+	}
+
+	_, err = manager.RemoveInstallTarget(ctx, "./.codex/skills")
+	if _, ok := errors.AsType[*domain.ErrorInstallTargetNotFound](err); !ok {
+		t.Errorf("expected ErrorInstallTargetNotFound, got %v", err)
+	}
+}
+
+func TestConfigManager_DuplicateInstallTargets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	if err := os.WriteFile(configPath, []byte("install_targets = [\"./.skills\", \".skills/\"]\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	var warnings bytes.Buffer
+	manager := domain.NewConfigManager(configPath, domain.WithConfigLogger(slog.New(domain.NewMessageHandler(&warnings, slog.LevelInfo))))
+	config, err := manager.Load(context.Background())
+	if err != nil {
+		t.Fatalf("ConfigManager.Load() unexpected error = %v", err)
+	}
+	if len(config.InstallTargets) != 1 || config.InstallTargets[0] != "./.skills" {
+		t.Errorf("expected the duplicate install target to be ignored, got %v", config.InstallTargets)
+	}
+	if !strings.Contains(warnings.String(), "install target .skills/") {
+		t.Errorf("expected a warning about the duplicate install target, got %q", warnings.String())
+	}
+
+	// Saving writes the deduplicated targets
+	if err := manager.Save(context.Background(), config); err != nil {
+		t.Fatalf("ConfigManager.Save() unexpected error = %v", err)
+	}
+	warnings.Reset()
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("ConfigManager.Load() unexpected error = %v", err)
+	}
+	if warnings.Len() != 0 {
+		t.Errorf("expected no warnings after saving, got %q", warnings.String())
+	}
+}
+
+func TestConfigManager_PlatformConditions(t *testing.T) {
+	other := "plan9"
+	if runtime.GOOS == other {
+		other = "windows"
+	}
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	content := `install_targets = ["./.skills", "./.other", "./.both"]
+
+[targets."./.other"]
+os = ["` + other + `"]
+
+[targets."./.both"]
+os = ["` + other + `", "` + runtime.GOOS + `"]
+
+[[skills]]
+name = "other-only"
+source = "git"
+url = "https://example.com/other.git"
+os = ["` + other + `"]
+
+[[skills]]
+name = "everywhere"
+source = "git"
+url = "https://example.com/everywhere.git"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	manager := domain.NewConfigManager(configPath)
+	config, err := manager.Load(context.Background())
+	if err != nil {
+		t.Fatalf("ConfigManager.Load() unexpected error = %v", err)
+	}
+	if !slices.Equal(config.InstallTargets, []string{"./.skills", "./.both"}) {
+		t.Errorf("expected the install targets of this platform, got %v", config.InstallTargets)
+	}
+	if len(config.Skills) != 1 || config.Skills[0].Name != "everywhere" {
+		t.Errorf("expected only the skill of this platform, got %d skill(s)", len(config.Skills))
+	}
+
+	// Entries of other platforms are written back in place when the configuration is saved
+	config.Skills = append(config.Skills, &domain.Skill{Name: "added", Source: "git", URL: "https://example.com/added.git"})
+	if err := manager.Save(context.Background(), config); err != nil {
+		t.Fatalf("ConfigManager.Save() unexpected error = %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	var saved domain.Config
+	if err := toml.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("saved config is not valid TOML: %v", err)
+	}
+	if !slices.Equal(saved.InstallTargets, []string{"./.skills", "./.other", "./.both"}) {
+		t.Errorf("expected every install target to be saved in order, got %v", saved.InstallTargets)
+	}
+	var names []string
+	for _, skill := range saved.Skills {
+		names = append(names, skill.Name)
+	}
+	if !slices.Equal(names, []string{"other-only", "everywhere", "added"}) {
+		t.Errorf("expected every skill to be saved in order, got %v", names)
+	}
+}
+
+func TestConfigManager_AddSkillToConfig_OtherPlatform(t *testing.T) {
+	other := "plan9"
+	if runtime.GOOS == other {
+		other = "windows"
+	}
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	content := "install_targets = [\"./.skills\"]\n\n[[skills]]\nname = \"tool\"\nsource = \"git\"\nurl = \"https://example.com/other.git\"\nos = [\"" + other + "\"]\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	manager := domain.NewConfigManager(configPath)
+
+	// A skill installed everywhere would be installed next to the skill of the other platform there
+	_, err := manager.AddSkillToConfig(context.Background(), &domain.Skill{Name: "tool", Source: "git", URL: "https://example.com/tool.git"})
+	if _, ok := errors.AsType[*domain.ErrorSkillExists](err); !ok {
+		t.Errorf("expected ErrorSkillExists for a skill installed on every platform, got %v", err)
+	}
+
+	// Skills whose operating systems do not overlap may share a name
+	config, err := manager.AddSkillToConfig(context.Background(), &domain.Skill{Name: "tool", Source: "git", URL: "https://example.com/tool.git", OS: []string{runtime.GOOS}})
+	if err != nil {
+		t.Fatalf("AddSkillToConfig() unexpected error = %v", err)
+	}
+	if err := manager.Save(context.Background(), config); err != nil {
+		t.Fatalf("ConfigManager.Save() unexpected error = %v", err)
+	}
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Errorf("ConfigManager.Load() unexpected error = %v", err)
+	}
+}
+
+func TestConfigManager_InvalidPlatform(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".skillspkg.toml")
+	content := "install_targets = [\"./.skills\"]\n\n[[skills]]\nname = \"a\"\nsource = \"git\"\nurl = \"https://example.com/a.git\"\nos = [\"macos\"]\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := domain.NewConfigManager(configPath).Load(context.Background())
+	if _, ok := errors.AsType[*domain.ErrorInvalidPlatform](err); !ok {
+		t.Errorf("expected ErrorInvalidPlatform, got %v", err)
+	}
+}
+
 // TestConfigManager_RemoveSkill tests the RemoveSkill method of ConfigManager.
 // Requirements: 9.2
 func TestConfigManager_RemoveSkill(t *testing.T) {
@@ -2,6 +2,8 @@ package domain_test
 
 import (
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/mazrean/skills-pkg/internal/domain"
@@ -64,6 +66,42 @@ func TestSkill_Validate(t *testing.T) {
 				return ok
 			},
 		},
+		{
+			name: "valid archive source skill with sha256",
+			skill: &domain.Skill{
+				Name:   "test-skill",
+				Source: "archive",
+				URL:    "https://example.com/skill.tar.gz",
+				SHA256: strings.Repeat("ab", 32),
+			},
+			wantErrCheck: nil,
+		},
+		{
+			name: "sha256 of a git source skill",
+			skill: &domain.Skill{
+				Name:   "test-skill",
+				Source: "git",
+				URL:    "https://github.com/example/skill.git",
+				SHA256: strings.Repeat("ab", 32),
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidSHA256](err)
+				return ok
+			},
+		},
+		{
+			name: "malformed sha256",
+			skill: &domain.Skill{
+				Name:   "test-skill",
+				Source: "archive",
+				URL:    "https://example.com/skill.tar.gz",
+				SHA256: "ABC",
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidSHA256](err)
+				return ok
+			},
+		},
 		{
 			name: "empty name",
 			skill: &domain.Skill{
@@ -112,6 +150,76 @@ func TestSkill_Validate(t *testing.T) {
 	}
 }
 
+func TestSkill_Satisfies(t *testing.T) {
+	skill := &domain.Skill{
+		Name:    "my-skill",
+		Source:  "git",
+		URL:     "https://github.com/example/skills.git",
+		Version: "v1.0.0",
+		SubDir:  "skills/my-skill",
+	}
+
+	tests := []struct {
+		requested *domain.Skill
+		name      string
+		want      bool
+	}{
+		{
+			name:      "identical",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0", SubDir: "skills/my-skill"},
+			want:      true,
+		},
+		{
+			name:      "version left open",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", SubDir: "skills/my-skill/"},
+			want:      true,
+		},
+		{
+			name:      "different version",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v2.0.0", SubDir: "skills/my-skill"},
+			want:      false,
+		},
+		{
+			name:      "different URL",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/other/skills.git", Version: "v1.0.0", SubDir: "skills/my-skill"},
+			want:      false,
+		},
+		{
+			name:      "different verify_ignore patterns",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", SubDir: "skills/my-skill", VerifyIgnore: []string{"*.lock"}},
+			want:      false,
+		},
+		{
+			name:      "different preserve patterns",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", SubDir: "skills/my-skill", Preserve: []string{"state/"}},
+			want:      false,
+		},
+		{
+			name:      "different subdirectory",
+			requested: &domain.Skill{Name: "my-skill", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0", SubDir: "skills/other"},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := skill.Satisfies(tt.requested); got != tt.want {
+				t.Errorf("Skill.Satisfies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkill_HashExclude(t *testing.T) {
+	skill := &domain.Skill{Name: "my-skill", VerifyIgnore: []string{"*.lock"}, Preserve: []string{"state/"}}
+	if got := skill.HashExclude(); !slices.Equal(got, []string{"*.lock", "state/"}) {
+		t.Errorf("HashExclude() = %v, want the verify_ignore and preserve patterns", got)
+	}
+	if got := (&domain.Skill{Name: "my-skill"}).HashExclude(); len(got) != 0 {
+		t.Errorf("HashExclude() = %v, want none", got)
+	}
+}
+
 func TestConfig_FindSkillByName(t *testing.T) {
 	config := &domain.Config{
 		Skills: []*domain.Skill{
@@ -245,6 +353,154 @@ func TestConfig_Validate(t *testing.T) {
 				return ok
 			},
 		},
+		{
+			name: "subdir and sub_dirs together",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "skills", Source: "git", URL: "url", SubDir: "skills/a", SubDirs: []string{"skills/*"}},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorConflictingSubDirs](err)
+				return ok
+			},
+		},
+		{
+			name: "link with a non-local source",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "skill1", Source: "git", URL: "url", Link: true},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidLink](err)
+				return ok
+			},
+		},
+		{
+			name: "link with sub_dirs",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "skills", Source: "local", URL: "./skills", SubDirs: []string{"*"}, Link: true},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidLink](err)
+				return ok
+			},
+		},
+		{
+			name: "install_as with a path",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "deploy", Source: "git", URL: "url", InstallAs: "tools/deploy"},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidInstallAs](err)
+				return ok
+			},
+		},
+		{
+			name: "install_as with sub_dirs",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "skills", Source: "git", URL: "url", SubDirs: []string{"skills/*"}, InstallAs: "all"},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidInstallAs](err)
+				return ok
+			},
+		},
+		{
+			name: "install_as into the directory of another skill",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "deploy", Source: "git", URL: "url1"},
+					{Name: "other-deploy", Source: "git", URL: "url2", InstallAs: "deploy"},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInstallDirConflict](err)
+				return ok
+			},
+		},
+		{
+			name: "member named like another skill",
+			config: &domain.Config{
+				Skills: []*domain.Skill{
+					{Name: "skill1", Source: "git", URL: "url1"},
+					{Name: "skills", Source: "git", URL: "url2", SubDirs: []string{"skills/*"}, Members: []*domain.SkillMember{{Name: "skill1", SubDir: "skills/skill1"}}},
+				},
+				InstallTargets: []string{"/path/to/dir"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorSkillExists](err)
+				return ok
+			},
+		},
+		{
+			name: "invalid hash mismatch policy",
+			config: &domain.Config{
+				InstallTargets: []string{"/path/to/dir"},
+				HashMismatch:   "ignore",
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidHashMismatchPolicy](err)
+				return ok
+			},
+		},
+		{
+			name: "invalid hidden characters policy",
+			config: &domain.Config{
+				InstallTargets:   []string{"/path/to/dir"},
+				HiddenCharacters: "remove",
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidHiddenCharactersPolicy](err)
+				return ok
+			},
+		},
+		{
+			name: "invalid symlinks policy",
+			config: &domain.Config{
+				InstallTargets: []string{"/path/to/dir"},
+				Symlinks:       "follow",
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidSymlinksPolicy](err)
+				return ok
+			},
+		},
+		{
+			name: "negative max depth",
+			config: &domain.Config{
+				InstallTargets: []string{"/path/to/dir"},
+				MaxDepth:       -1,
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidMaxDepth](err)
+				return ok
+			},
+		},
+		{
+			name: "invalid recipient",
+			config: &domain.Config{
+				InstallTargets: []string{"/path/to/dir"},
+				Recipients:     []string{"age1qyqszqgpqyqszqgpqyqszqgpqyqszqgp"},
+			},
+			wantErrCheck: func(err error) bool {
+				_, ok := errors.AsType[*domain.ErrorInvalidRecipient](err)
+				return ok
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -262,3 +518,104 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaults(t *testing.T) {
+	useGoMod := false
+
+	tests := []struct {
+		defaults         *domain.Defaults
+		name             string
+		wantGitStrategy  string
+		wantUseGoMod     bool
+		wantValidateFail bool
+	}{
+		{
+			name:            "nil defaults keep built-in behavior",
+			defaults:        nil,
+			wantGitStrategy: domain.VersionStrategyHead,
+			wantUseGoMod:    true,
+		},
+		{
+			name: "latest-tag strategy and go.mod disabled",
+			defaults: &domain.Defaults{
+				Git:   &domain.GitDefaults{Version: domain.VersionStrategyLatestTag},
+				GoMod: &domain.GoModDefaults{UseGoMod: &useGoMod},
+			},
+			wantGitStrategy: domain.VersionStrategyLatestTag,
+			wantUseGoMod:    false,
+		},
+		{
+			name: "unknown git strategy",
+			defaults: &domain.Defaults{
+				Git: &domain.GitDefaults{Version: "newest"},
+			},
+			wantGitStrategy:  "newest",
+			wantUseGoMod:     true,
+			wantValidateFail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.defaults.GitVersionStrategy(); got != tt.wantGitStrategy {
+				t.Errorf("GitVersionStrategy() = %q, want %q", got, tt.wantGitStrategy)
+			}
+			if got := tt.defaults.UseGoMod(); got != tt.wantUseGoMod {
+				t.Errorf("UseGoMod() = %v, want %v", got, tt.wantUseGoMod)
+			}
+
+			err := (&domain.Config{Defaults: tt.defaults}).Validate()
+			if tt.wantValidateFail {
+				if _, ok := errors.AsType[*domain.ErrorInvalidVersionStrategy](err); !ok {
+					t.Errorf("Validate() error = %v, want ErrorInvalidVersionStrategy", err)
+				}
+			} else if err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_HashAlgorithm(t *testing.T) {
+	tests := []struct {
+		name             string
+		algorithm        string
+		wantAlgorithm    string
+		wantValidateFail bool
+	}{
+		{
+			name:          "default is h1",
+			algorithm:     "",
+			wantAlgorithm: "h1",
+		},
+		{
+			name:          "normalized n1",
+			algorithm:     "n1",
+			wantAlgorithm: "n1",
+		},
+		{
+			name:             "unknown algorithm",
+			algorithm:        "md5",
+			wantAlgorithm:    "md5",
+			wantValidateFail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &domain.Config{HashAlgorithm: tt.algorithm}
+			if got := config.EffectiveHashAlgorithm(); got != tt.wantAlgorithm {
+				t.Errorf("EffectiveHashAlgorithm() = %q, want %q", got, tt.wantAlgorithm)
+			}
+
+			err := config.Validate()
+			if tt.wantValidateFail {
+				if _, ok := errors.AsType[*domain.ErrorInvalidHashAlgorithm](err); !ok {
+					t.Errorf("Validate() error = %v, want ErrorInvalidHashAlgorithm", err)
+				}
+			} else if err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+		})
+	}
+}
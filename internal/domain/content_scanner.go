@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// checkContent checks the file tree of the downloaded skill in sourcePath, sanitizes it, and runs the
+// configured linter, policy, and scanner on the result. It returns the directory to install the skill
+// from, and is called before the skill is recorded in the configuration or copied to any install target.
+// The returned function removes the copies made on the way, once the skill has been installed from it.
+func (s *skillManagerImpl) checkContent(ctx context.Context, config *Config, sourcePath string, skill *Skill, version string) (string, func(), error) {
+	sourcePath, cleanupTree, err := s.checkTree(ctx, config, sourcePath, skill)
+	if err != nil {
+		return "", nil, err
+	}
+	sourcePath, cleanupSanitized, err := s.sanitizeContent(ctx, config, sourcePath, skill)
+	if err != nil {
+		cleanupTree()
+		return "", nil, err
+	}
+	cleanup := func() {
+		cleanupSanitized()
+		cleanupTree()
+	}
+	if err := s.checkReview(ctx, config, sourcePath, skill, version); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if config.Lint {
+		s.lintContent(ctx, sourcePath, skill)
+	}
+	if err := s.checkPolicy(ctx, config, sourcePath, skill, version); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := s.scanContent(ctx, config, sourcePath, skill, version); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return sourcePath, cleanup, nil
+}
+
+// scanContent runs the configured scanner command on the downloaded skill in sourcePath.
+// It returns ErrorContentRejected when the scanner rejects the skill, and nil when no scanner is configured.
+func (s *skillManagerImpl) scanContent(ctx context.Context, config *Config, sourcePath string, skill *Skill, version string) error {
+	if len(config.Scanner) == 0 {
+		return nil
+	}
+	if s.contentScanner == nil {
+		return fmt.Errorf("scanner %s is configured, but scanners cannot be run", config.Scanner[0])
+	}
+
+	s.emit(ctx, skill.Name, PhaseCheck, "", "Scanning skill '%s' with %s...", skill.Name, config.Scanner[0])
+	result, err := s.contentScanner.Scan(ctx, config.Scanner, &port.ScanTarget{
+		Dir:     sourcePath,
+		Name:    skill.Name,
+		Source:  skill.Source,
+		URL:     skill.URL,
+		Version: version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan skill '%s': %w", skill.Name, err)
+	}
+	if !result.Clean {
+		return &ErrorContentRejected{SkillName: skill.Name, Version: version, Scanner: config.Scanner[0], Output: result.Output}
+	}
+
+	return nil
+}
+
+// lintContent reports the lint findings in the downloaded skill in sourcePath as warnings.
+// Findings never fail the installation; a policy or scanner is the place to enforce them.
+func (s *skillManagerImpl) lintContent(ctx context.Context, sourcePath string, skill *Skill) {
+	findings, err := LintSkill(sourcePath)
+	if err != nil {
+		s.emit(ctx, skill.Name, PhaseWarning, "", "WARNING: Failed to lint skill '%s': %v", skill.Name, err)
+		return
+	}
+	for _, finding := range findings {
+		s.emit(ctx, skill.Name, PhaseWarning, "", "WARNING: skill '%s' %s", skill.Name, finding)
+	}
+}
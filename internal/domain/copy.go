@@ -0,0 +1,375 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// copyBufferSize is the size of the chunks files are copied in.
+// Chunks that are entirely zero are skipped when copying sparse files, so it is also the hole granularity.
+const copyBufferSize = 128 * 1024
+
+// CopyOptions controls how CopyFile and CopyDir copy files. The zero value copies file contents and permissions only.
+type CopyOptions struct {
+	// Progress is called with the number of bytes written after each chunk of a file is copied.
+	Progress func(n int64)
+	// PreserveTimes sets the modification time of copied files and directories to that of the source.
+	PreserveTimes bool
+	// PreserveXattrs copies extended attributes, on Linux and macOS. Attributes that the destination
+	// does not support or the process may not set are skipped.
+	PreserveXattrs bool
+	// Sync flushes every copied file to stable storage before it is closed.
+	Sync bool
+}
+
+// CopySettings is the [copy] table of the configuration, which controls how skills are copied into install targets.
+type CopySettings struct {
+	PreserveTimes  bool `toml:"preserve_times,omitempty"`  // Keep the modification times of the downloaded files
+	PreserveXattrs bool `toml:"preserve_xattrs,omitempty"` // Keep extended attributes on Linux and macOS
+	Fsync          bool `toml:"fsync,omitempty"`           // Flush each installed file to disk before continuing
+	Atomic         bool `toml:"atomic,omitempty"`          // Swap in updated skills as a whole instead of writing only the changed files
+}
+
+// AtomicUpdates reports whether installed skills are updated by swapping in a fully prepared copy.
+// It is safe to call on a nil receiver.
+func (s *CopySettings) AtomicUpdates() bool {
+	return s != nil && s.Atomic
+}
+
+// Options returns the options to copy skills into install targets with. It is safe to call on a nil receiver.
+func (s *CopySettings) Options() *CopyOptions {
+	if s == nil {
+		return &CopyOptions{}
+	}
+	return &CopyOptions{PreserveTimes: s.PreserveTimes, PreserveXattrs: s.PreserveXattrs, Sync: s.Fsync}
+}
+
+// CopyFile copies the regular file src to dst, streaming its contents so that files of any size can be copied.
+// dst is created with the permissions of src, subject to the umask, or truncated when it exists.
+// Holes of sparse files are kept as holes where the destination file system supports them.
+func CopyFile(src, dst string, opts *CopyOptions) (err error) {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if opts.Progress == nil && !isSparse(info) {
+		// Without a progress callback, io.Copy can use copy_file_range and similar system calls
+		if _, err := io.Copy(out, in); err != nil {
+			return err
+		}
+	} else if err := copyChunks(out, in, info.Size(), opts.Progress); err != nil {
+		return err
+	}
+
+	if opts.Sync {
+		if err := out.Sync(); err != nil {
+			return err
+		}
+	}
+	if opts.PreserveXattrs {
+		if err := copyXattrs(src, dst); err != nil {
+			return fmt.Errorf("failed to copy extended attributes of %s: %w", src, err)
+		}
+	}
+	if opts.PreserveTimes {
+		if err := os.Chtimes(dst, time.Time{}, info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyChunks copies size bytes from in to out in chunks, reporting each chunk to progress.
+// Chunks that are entirely zero are skipped over instead of written, which keeps sparse files sparse.
+func copyChunks(out, in *os.File, size int64, progress func(n int64)) error {
+	buf := make([]byte, copyBufferSize)
+	zero := make([]byte, copyBufferSize)
+	for {
+		n, readErr := io.ReadFull(in, buf)
+		if n > 0 {
+			if bytes.Equal(buf[:n], zero[:n]) {
+				if _, err := out.Seek(int64(n), io.SeekCurrent); err != nil {
+					return err
+				}
+			} else if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(int64(n))
+			}
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	// A trailing hole is not written, so the size is set explicitly
+	return out.Truncate(size)
+}
+
+// CopyDir recursively copies the skill directory src to dst, creating dst when it does not exist.
+// Paths excluded by the skill's ignore files are not copied, and symbolic links are
+// replaced by copies of their targets, which must be inside src.
+func CopyDir(src, dst string, opts *CopyOptions) error {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
+		return err
+	}
+
+	type dirTime struct {
+		path    string
+		modTime time.Time
+	}
+	dirTimes := []dirTime{{path: dst, modTime: srcInfo.ModTime()}}
+
+	err = walkSkillTree(src, func(rel string, info fs.FileInfo, _ bool) error {
+		dstPath := dst + "/" + rel
+		if !info.IsDir() {
+			return CopyFile(src+"/"+rel, dstPath, opts)
+		}
+
+		if err := os.MkdirAll(dstPath, info.Mode()); err != nil {
+			return err
+		}
+		if opts.PreserveXattrs {
+			if err := copyXattrs(src+"/"+rel, dstPath); err != nil {
+				return fmt.Errorf("failed to copy extended attributes of %s: %w", rel, err)
+			}
+		}
+		dirTimes = append(dirTimes, dirTime{path: dstPath, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Copying the contents changes the times of the directories, so they are set last, innermost first
+	if opts.PreserveTimes {
+		for _, dir := range slices.Backward(dirTimes) {
+			if err := os.Chtimes(dir.path, time.Time{}, dir.modTime); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SyncDir makes the directory dst match the directory src by moving the files of src that are missing
+// or differ in dst into it, and deleting the entries of dst that src does not have. Files with the same
+// contents and permissions are left untouched, so that updating a skill only writes what changed and
+// file watchers of the install target see as few events as possible. Entries of dst matched by preserve,
+// such as files agents write into skills, are kept as they are. src must be on the same file system as dst,
+// and is left with the files that were not moved. It returns the number of entries written or deleted.
+func SyncDir(src, dst string, preserve *SkillIgnore) (int, error) {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return 0, err
+	}
+	if err := syncMode(dst, srcInfo.Mode()); err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	synced := make(map[string]bool)
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == src {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		synced[rel] = true
+		dstPath := filepath.Join(dst, rel)
+
+		if preserve.Match(rel, d.IsDir()) {
+			if _, err := os.Lstat(dstPath); err == nil {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		same, err := sameEntry(path, dstPath, info)
+		if err != nil || same {
+			return err
+		}
+
+		// Directories are synced entry by entry; only their permissions are set here
+		if info.IsDir() {
+			if dstInfo, err := os.Lstat(dstPath); err == nil && dstInfo.IsDir() {
+				return syncMode(dstPath, info.Mode())
+			}
+		}
+		if err := os.RemoveAll(dstPath); err != nil {
+			return err
+		}
+		changed++
+		if info.IsDir() {
+			if err := os.Mkdir(dstPath, info.Mode().Perm()); err != nil {
+				return err
+			}
+			return syncMode(dstPath, info.Mode())
+		}
+		// WalkDir has already read the directory, so its entries can be moved away
+		return os.Rename(path, dstPath)
+	})
+	if err != nil {
+		return changed, err
+	}
+
+	err = filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dst {
+			return err
+		}
+		rel, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if synced[rel] {
+			return nil
+		}
+		if preserve.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+		changed++
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// sameEntry reports whether dst is already the same file, symbolic link, or directory as src, whose
+// information is info. Directories are the same when their permissions match, regardless of their entries.
+func sameEntry(src, dst string, info fs.FileInfo) (bool, error) {
+	dstInfo, err := os.Lstat(dst)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if dstInfo.Mode().Type() != info.Mode().Type() {
+		return false, nil
+	}
+
+	switch {
+	case info.IsDir():
+		return dstInfo.Mode().Perm() == info.Mode().Perm(), nil
+	case info.Mode()&fs.ModeSymlink != 0:
+		srcLink, err := os.Readlink(src)
+		if err != nil {
+			return false, err
+		}
+		dstLink, err := os.Readlink(dst)
+		return err == nil && srcLink == dstLink, nil
+	case !info.Mode().IsRegular():
+		return false, nil
+	}
+
+	if dstInfo.Mode().Perm() != info.Mode().Perm() || dstInfo.Size() != info.Size() {
+		return false, nil
+	}
+	return sameContent(src, dst)
+}
+
+// sameContent reports whether the regular files a and b have the same contents.
+func sameContent(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = fa.Close() }()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = fb.Close() }()
+
+	bufA := make([]byte, copyBufferSize)
+	bufB := make([]byte, copyBufferSize)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		if errors.Is(errA, io.EOF) || errors.Is(errA, io.ErrUnexpectedEOF) {
+			return errors.Is(errB, io.EOF) || errors.Is(errB, io.ErrUnexpectedEOF), nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+	}
+}
+
+// syncMode sets the permissions of the directory to those of mode unless it already has them.
+func syncMode(dir string, mode fs.FileMode) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm() == mode.Perm() {
+		return nil
+	}
+	return os.Chmod(dir, mode.Perm())
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package domain
+
+import "io/fs"
+
+// isSparse reports whether the file occupies fewer blocks on disk than its size requires.
+// Sparse files are only detected on Unix-like systems.
+func isSparse(_ fs.FileInfo) bool {
+	return false
+}
@@ -0,0 +1,204 @@
+package domain_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestCopyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+
+	// A sparse file with data at the start and the end
+	const size = 4 << 20
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("head"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("tail"), size-4); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(src, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	// The destination is truncated when it exists
+	if err := os.WriteFile(dst, bytes.Repeat([]byte("x"), size+10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var copied int64
+	opts := &domain.CopyOptions{Progress: func(n int64) { copied += n }, PreserveTimes: true, Sync: true}
+	if err := domain.CopyFile(src, dst, opts); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+
+	want, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("CopyFile() copied %d bytes that differ from the %d source bytes", len(got), len(want))
+	}
+	if copied != size {
+		t.Errorf("progress reported %d bytes, want %d", copied, size)
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(modTime) {
+		t.Errorf("modification time = %v, want %v", info.ModTime(), modTime)
+	}
+}
+
+func TestCopyDir_PreserveTimes(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	writeSkillFiles(t, src, map[string]string{"SKILL.md": "# Skill", "docs/guide.md": "guide"})
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, name := range []string{"docs/guide.md", "docs", "SKILL.md", "."} {
+		if err := os.Chtimes(filepath.Join(src, name), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := domain.CopyDir(src, dst, (&domain.CopySettings{PreserveTimes: true}).Options()); err != nil {
+		t.Fatalf("CopyDir() error = %v", err)
+	}
+
+	for _, name := range []string{"docs/guide.md", "docs", "SKILL.md", "."} {
+		info, err := os.Stat(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(modTime) {
+			t.Errorf("modification time of %s = %v, want %v", name, info.ModTime(), modTime)
+		}
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+
+	write := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatalf("failed to chmod %s: %v", path, err)
+		}
+	}
+	write(filepath.Join(src, "SKILL.md"), "same", 0o644)
+	write(filepath.Join(src, "changed.md"), "new", 0o644)
+	write(filepath.Join(src, "added", "file.md"), "added", 0o644)
+	write(filepath.Join(src, "script.sh"), "echo", 0o755)
+	write(filepath.Join(dst, "SKILL.md"), "same", 0o644)
+	write(filepath.Join(dst, "changed.md"), "old", 0o644)
+	write(filepath.Join(dst, "script.sh"), "echo", 0o644)
+	write(filepath.Join(dst, "removed.md"), "removed", 0o644)
+	write(filepath.Join(dst, "removed", "file.md"), "removed", 0o644)
+
+	unchanged, err := os.Stat(filepath.Join(dst, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to stat SKILL.md: %v", err)
+	}
+
+	changed, err := domain.SyncDir(src, dst, nil)
+	if err != nil {
+		t.Fatalf("SyncDir() error = %v", err)
+	}
+	// changed.md, added/, added/file.md, script.sh, removed.md, and removed/
+	if changed != 6 {
+		t.Errorf("SyncDir() changed %d entries, want 6", changed)
+	}
+
+	after, err := os.Stat(filepath.Join(dst, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to stat SKILL.md: %v", err)
+	}
+	if !os.SameFile(unchanged, after) {
+		t.Error("SyncDir() should leave files with the same contents untouched")
+	}
+	for path, want := range map[string]string{"changed.md": "new", "added/file.md": "added", "script.sh": "echo"} {
+		data, err := os.ReadFile(filepath.Join(dst, path))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", path, data, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dst, "script.sh")); err != nil || info.Mode().Perm() != 0o755 {
+		t.Errorf("script.sh should get the permissions of the source: %v, %v", info, err)
+	}
+	for _, path := range []string{"removed.md", "removed"} {
+		if _, err := os.Lstat(filepath.Join(dst, path)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been deleted, stat error = %v", path, err)
+		}
+	}
+}
+
+func TestSyncDir_Preserve(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	for path, content := range map[string]string{
+		"src/SKILL.md":         "new",
+		"src/notes.md":         "upstream",
+		"dst/SKILL.md":         "old",
+		"dst/notes.md":         "written by the agent",
+		"dst/state/cache.json": "{}",
+		"dst/stale.md":         "stale",
+	} {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	if _, err := domain.SyncDir(src, dst, domain.NewSkillIgnore([]string{"notes.md", "state/"})); err != nil {
+		t.Fatalf("SyncDir() error = %v", err)
+	}
+
+	for path, want := range map[string]string{"SKILL.md": "new", "notes.md": "written by the agent", "state/cache.json": "{}"} {
+		data, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(path)))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", path, data, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "stale.md")); !os.IsNotExist(err) {
+		t.Errorf("stale.md should have been deleted, stat error = %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package domain
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// isSparse reports whether the file occupies fewer blocks on disk than its size requires.
+func isSparse(info fs.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return int64(stat.Blocks)*512 < info.Size()
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// SkillDependency is a skill that another skill builds on, declared in the depends_on field of the
+// SKILL.md frontmatter of that skill, either by name or with where to install it from:
+//
+//	depends_on:
+//	  - changelog-helper
+//	  - name: git-helper
+//	    url: https://github.com/org/skills
+//	    subdir: skills/git-helper
+//	    version: v1.2.0
+//
+// A dependency given by name must be in the configuration of the project; one with a URL is added
+// to the configuration when it is not, so that composite skills bring the skills they build on along.
+type SkillDependency struct {
+	Name    string `yaml:"name"`
+	Source  string `yaml:"source"` // Source type, "git" when omitted
+	URL     string `yaml:"url"`
+	Version string `yaml:"version"`
+	SubDir  string `yaml:"subdir"`
+}
+
+// UnmarshalYAML reads a dependency written as its name or as a mapping.
+func (d *SkillDependency) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		d.Name = node.Value
+		return nil
+	}
+	type plain SkillDependency
+	return node.Decode((*plain)(d))
+}
+
+// Skill returns the configuration entry that installs the dependency.
+func (d *SkillDependency) Skill() *Skill {
+	source := d.Source
+	if source == "" {
+		source = "git"
+	}
+	return &Skill{
+		Name:    d.Name,
+		Source:  source,
+		URL:     d.URL,
+		Version: d.Version,
+		SubDir:  d.SubDir,
+	}
+}
+
+// ReadSkillDependencies returns the dependencies declared by the SKILL.md frontmatter of the skill in dir.
+// A skill without SKILL.md, frontmatter, or the depends_on field has none.
+func ReadSkillDependencies(dir string) ([]*SkillDependency, error) {
+	metadata, err := readSkillMD(dir)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+	for _, dependency := range metadata.DependsOn {
+		if dependency == nil || dependency.Name == "" {
+			return nil, fmt.Errorf("invalid depends_on in SKILL.md in %s: every dependency needs a name", dir)
+		}
+	}
+	return metadata.DependsOn, nil
+}
+
+// resolveDependencies returns the skills and the skills they depend on through depends_on, directly
+// or indirectly, split into waves: every skill is in a later wave than the skills it depends on, and
+// skills keep their order in the configuration within a wave. Skills without dependencies are all in
+// the first wave. A dependency on a member of an entry with sub_dirs is a dependency on the entry.
+func resolveDependencies(config *Config, skills []*Skill) ([][]*Skill, error) {
+	const (
+		visiting = 1
+		resolved = 2
+	)
+	state := make(map[*Skill]int)
+	wave := make(map[*Skill]int)
+	var path []string
+	var order []*Skill
+
+	var visit func(skill *Skill) error
+	visit = func(skill *Skill) error {
+		switch state[skill] {
+		case resolved:
+			return nil
+		case visiting:
+			start := slices.Index(path, skill.Name)
+			return &ErrorDependencyCycle{Cycle: append(slices.Clone(path[start:]), skill.Name)}
+		}
+		state[skill] = visiting
+		path = append(path, skill.Name)
+
+		for _, name := range skill.DependsOn {
+			dependency := config.FindSkillEntry(name)
+			if dependency == nil {
+				return &ErrorDependencyNotFound{SkillName: skill.Name, Dependency: name}
+			}
+			// Members of the same entry are installed together
+			if dependency == skill && name != skill.Name {
+				continue
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+			wave[skill] = max(wave[skill], wave[dependency]+1)
+		}
+
+		path = path[:len(path)-1]
+		state[skill] = resolved
+		order = append(order, skill)
+		return nil
+	}
+	for _, skill := range skills {
+		if err := visit(skill); err != nil {
+			return nil, err
+		}
+	}
+
+	// Skills are ordered as in the configuration, and those not in it, such as a skill being added, last
+	position := func(skill *Skill) int {
+		if i := slices.Index(config.Skills, skill); i >= 0 {
+			return i
+		}
+		return len(config.Skills)
+	}
+	slices.SortStableFunc(order, func(a, b *Skill) int {
+		return position(a) - position(b)
+	})
+
+	var waves [][]*Skill
+	for _, skill := range order {
+		for len(waves) <= wave[skill] {
+			waves = append(waves, nil)
+		}
+		waves[wave[skill]] = append(waves[wave[skill]], skill)
+	}
+	return waves, nil
+}
+
+// dependentsOf returns the names of the entries that depend on the entry or a skill it installs.
+func (c *Config) dependentsOf(entry *Skill) []string {
+	var dependents []string
+	for _, skill := range c.Skills {
+		if skill == entry {
+			continue
+		}
+		if slices.ContainsFunc(skill.DependsOn, func(name string) bool { return c.FindSkillEntry(name) == entry }) {
+			dependents = append(dependents, skill.Name)
+		}
+	}
+	return dependents
+}
@@ -0,0 +1,326 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestReadSkillDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		skillMD string
+		want    []SkillDependency
+		wantErr bool
+	}{
+		{
+			name:    "names and mappings",
+			skillMD: "---\nname: composite\ndepends_on:\n  - changelog-helper\n  - name: git-helper\n    url: https://github.com/org/skills\n    subdir: skills/git-helper\n    version: v1.2.0\n---\n",
+			want: []SkillDependency{
+				{Name: "changelog-helper"},
+				{Name: "git-helper", URL: "https://github.com/org/skills", SubDir: "skills/git-helper", Version: "v1.2.0"},
+			},
+		},
+		{
+			name:    "flow list",
+			skillMD: "---\nname: composite\ndepends_on: [a, b]\n---\n",
+			want:    []SkillDependency{{Name: "a"}, {Name: "b"}},
+		},
+		{
+			name:    "no dependencies",
+			skillMD: "---\nname: composite\n---\n",
+		},
+		{
+			name:    "no frontmatter",
+			skillMD: "# Composite\n",
+		},
+		{
+			name:    "dependency without a name",
+			skillMD: "---\nname: composite\ndepends_on:\n  - url: https://github.com/org/skills\n---\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(tt.skillMD), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadSkillDependencies(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadSkillDependencies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadSkillDependencies() = %d dependencies, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if *got[i] != tt.want[i] {
+					t.Errorf("dependency %d = %+v, want %+v", i, *got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		skills    []*Skill
+		install   []string // Names of the skills to install, or every skill when empty
+		wantWaves [][]string
+		wantCycle []string
+		wantErr   error
+	}{
+		{
+			name:      "no dependencies",
+			skills:    []*Skill{{Name: "a"}, {Name: "b"}},
+			wantWaves: [][]string{{"a", "b"}},
+		},
+		{
+			name:      "dependencies first",
+			skills:    []*Skill{{Name: "composite", DependsOn: []string{"helper", "base"}}, {Name: "helper", DependsOn: []string{"base"}}, {Name: "base"}, {Name: "other"}},
+			wantWaves: [][]string{{"base", "other"}, {"helper"}, {"composite"}},
+		},
+		{
+			name:      "single skill brings its dependencies",
+			skills:    []*Skill{{Name: "composite", DependsOn: []string{"helper"}}, {Name: "helper"}, {Name: "other"}},
+			install:   []string{"composite"},
+			wantWaves: [][]string{{"helper"}, {"composite"}},
+		},
+		{
+			name:      "dependency on a member",
+			skills:    []*Skill{{Name: "composite", DependsOn: []string{"lint"}}, {Name: "tools", SubDirs: []string{"skills/*"}, Members: []*SkillMember{{Name: "lint"}}}},
+			wantWaves: [][]string{{"tools"}, {"composite"}},
+		},
+		{
+			name:      "cycle",
+			skills:    []*Skill{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"c"}}, {Name: "c", DependsOn: []string{"a"}}},
+			wantCycle: []string{"a", "b", "c", "a"},
+		},
+		{
+			name:      "depends on itself",
+			skills:    []*Skill{{Name: "a", DependsOn: []string{"a"}}},
+			wantCycle: []string{"a", "a"},
+		},
+		{
+			name:    "missing dependency",
+			skills:  []*Skill{{Name: "a", DependsOn: []string{"missing"}}},
+			wantErr: &ErrorDependencyNotFound{SkillName: "a", Dependency: "missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Skills: tt.skills}
+			skills := config.Skills
+			if len(tt.install) > 0 {
+				skills = nil
+				for _, name := range tt.install {
+					skills = append(skills, config.FindSkillEntry(name))
+				}
+			}
+
+			waves, err := resolveDependencies(config, skills)
+			if tt.wantCycle != nil {
+				e, ok := errors.AsType[*ErrorDependencyCycle](err)
+				if !ok || !slices.Equal(e.Cycle, tt.wantCycle) {
+					t.Fatalf("resolveDependencies() error = %v, want a cycle %v", err, tt.wantCycle)
+				}
+				if code := CodeOf(err); code != CodeDependency {
+					t.Errorf("CodeOf() = %v, want %s", code, CodeDependency.Code)
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("resolveDependencies() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveDependencies() error = %v", err)
+			}
+
+			var got [][]string
+			for _, wave := range waves {
+				var names []string
+				for _, skill := range wave {
+					names = append(names, skill.Name)
+				}
+				got = append(got, names)
+			}
+			if !slices.EqualFunc(got, tt.wantWaves, slices.Equal) {
+				t.Errorf("resolveDependencies() = %v, want %v", got, tt.wantWaves)
+			}
+		})
+	}
+}
+
+// mockPackageManagerByURL downloads the directory of each URL and records the order of the downloads.
+type mockPackageManagerByURL struct {
+	dirs      map[string]string
+	mu        sync.Mutex
+	downloads []string
+}
+
+func (m *mockPackageManagerByURL) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.downloads = append(m.downloads, source.URL)
+	return &port.DownloadResult{Path: m.dirs[source.URL], Version: "v1.0.0"}, nil
+}
+
+func (m *mockPackageManagerByURL) GetLatestVersion(ctx context.Context, source *port.Source) (string, error) {
+	return "v1.0.0", nil
+}
+
+func (m *mockPackageManagerByURL) SourceType() string {
+	return "git"
+}
+
+func TestInstall_Dependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	pm := &mockPackageManagerByURL{dirs: map[string]string{}}
+	for name, skillMD := range map[string]string{
+		"composite": "---\nname: composite\ndepends_on:\n  - helper\n  - name: extra\n    url: https://example.com/extra.git\n---\n",
+		"helper":    "---\nname: helper\n---\n",
+		"extra":     "---\nname: extra\n---\n",
+	} {
+		dir := filepath.Join(tmpDir, "download", name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skillMD), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		pm.dirs["https://example.com/"+name+".git"] = dir
+	}
+
+	ctx := context.Background()
+	configManager := NewConfigManager(filepath.Join(tmpDir, ".skillspkg.toml"))
+	installDir := filepath.Join(tmpDir, "install")
+	config := &Config{
+		Skills: []*Skill{
+			{Name: "composite", Source: "git", URL: "https://example.com/composite.git", Version: "v1.0.0", DependsOn: []string{"helper"}},
+			{Name: "helper", Source: "git", URL: "https://example.com/helper.git", Version: "v1.0.0"},
+		},
+		InstallTargets: []string{installDir},
+	}
+	if err := configManager.Save(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skills that a SKILL.md adds to the configuration must be approved
+	var approved []string
+	approve := func(dependent string, dependency *Skill) bool {
+		approved = append(approved, dependent+"->"+dependency.Name)
+		return dependency.URL == "https://example.com/extra.git"
+	}
+	err := NewSkillManager(configManager, &mockHashService{}, []port.PackageManager{pm}).Install(ctx, "composite")
+	if e, ok := errors.AsType[*ErrorDependencyNotApproved](err); !ok || e.Dependency != "extra" {
+		t.Fatalf("Install() without approval error = %v, want ErrorDependencyNotApproved for extra", err)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "composite")); !os.IsNotExist(err) {
+		t.Errorf("skill composite was installed although its dependency was refused: %v", err)
+	}
+
+	// A dry run lists the skill it would add, without asking
+	changes, err := NewSkillManager(configManager, &mockHashService{}, []port.PackageManager{pm}).InstallDryRun(ctx, "composite")
+	if err != nil {
+		t.Fatalf("InstallDryRun() error = %v", err)
+	}
+	if !slices.ContainsFunc(changes, func(change *DryRunChange) bool {
+		return change.Action == DryRunAdd && change.Skill == "extra" && change.Version == "v1.0.0" && change.Reason == "composite"
+	}) {
+		t.Errorf("InstallDryRun() does not add extra at v1.0.0 for composite: %+v", changes)
+	}
+
+	var installed []string
+	var mu sync.Mutex
+	skillManager := NewSkillManager(configManager, &mockHashService{}, []port.PackageManager{pm}, WithDependencyApproval(approve), WithProgressEvents(func(event *ProgressEvent) {
+		if event.Phase == PhaseDone {
+			mu.Lock()
+			installed = append(installed, event.Skill)
+			mu.Unlock()
+		}
+	}))
+	if err := skillManager.Install(ctx, "composite"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !slices.Equal(approved, []string{"composite->extra"}) {
+		t.Errorf("approvals asked = %v, want [composite->extra]", approved)
+	}
+
+	// The dependencies are installed before the skill, whether configured or declared in its SKILL.md
+	if len(installed) != 3 || installed[2] != "composite" {
+		t.Errorf("installed = %v, want helper and extra before composite", installed)
+	}
+	for _, name := range []string{"composite", "helper", "extra"} {
+		if _, err := os.Stat(filepath.Join(installDir, name, "SKILL.md")); err != nil {
+			t.Errorf("skill %s was not installed: %v", name, err)
+		}
+	}
+
+	updated, err := configManager.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extra := updated.FindSkillByName("extra"); extra == nil || extra.URL != "https://example.com/extra.git" || extra.Source != "git" || extra.Version != "v1.0.0" {
+		t.Errorf("dependency declared in SKILL.md was not added to the configuration at its resolved version: %+v", extra)
+	}
+	if got := updated.FindSkillByName("composite").DependsOn; !slices.Equal(got, []string{"helper", "extra"}) {
+		t.Errorf("depends_on = %v, want [helper extra]", got)
+	}
+
+	// Skills that others depend on cannot be uninstalled
+	err = skillManager.Uninstall(ctx, "extra")
+	if e, ok := errors.AsType[*ErrorDependencyRequired](err); !ok || !slices.Equal(e.Dependents, []string{"composite"}) {
+		t.Errorf("Uninstall() error = %v, want ErrorDependencyRequired by composite", err)
+	}
+	if err := skillManager.Uninstall(ctx, "composite"); err != nil {
+		t.Errorf("Uninstall() of the dependent skill error = %v", err)
+	}
+	if err := skillManager.Uninstall(ctx, "extra"); err != nil {
+		t.Errorf("Uninstall() after removing the dependent skill error = %v", err)
+	}
+}
+
+func TestInstall_DeclaredDependencyCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	pm := &mockPackageManagerByURL{dirs: map[string]string{}}
+	for name, dependency := range map[string]string{"a": "b", "b": "a"} {
+		dir := filepath.Join(tmpDir, "download", name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		skillMD := "---\nname: " + name + "\ndepends_on:\n  - name: " + dependency + "\n    url: https://example.com/" + dependency + ".git\n---\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skillMD), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		pm.dirs["https://example.com/"+name+".git"] = dir
+	}
+
+	ctx := context.Background()
+	configManager := NewConfigManager(filepath.Join(tmpDir, ".skillspkg.toml"))
+	config := &Config{
+		Skills:         []*Skill{{Name: "a", Source: "git", URL: "https://example.com/a.git", Version: "v1.0.0"}},
+		InstallTargets: []string{filepath.Join(tmpDir, "install")},
+	}
+	if err := configManager.Save(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+
+	approve := WithDependencyApproval(func(string, *Skill) bool { return true })
+	err := NewSkillManager(configManager, &mockHashService{}, []port.PackageManager{pm}, approve).Install(ctx, "")
+	if e, ok := errors.AsType[*ErrorDependencyCycle](err); !ok || !slices.Equal(e.Cycle, []string{"b", "a", "b"}) {
+		t.Errorf("Install() error = %v, want a cycle b -> a -> b", err)
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// DownloadCache keeps downloaded sources in the user cache directory, so that skills
+// pinned to the same source and version are downloaded once across runs and projects.
+// Only downloads of a fixed revision are cached: a tag, a commit, or a module version.
+// Branches and unpinned versions resolve to a different revision over time and are always downloaded.
+//
+// Layout:
+//
+//	<root>/<key>/   downloaded files, where key is derived from the source type, URL, and version
+type DownloadCache struct {
+	root   string
+	hits   atomic.Int64
+	misses atomic.Int64
+	mu     sync.Mutex
+}
+
+// NewDownloadCache creates a new DownloadCache rooted at root.
+func NewDownloadCache(root string) *DownloadCache {
+	return &DownloadCache{root: root}
+}
+
+// Root returns the directory of the cache.
+func (c *DownloadCache) Root() string {
+	return c.root
+}
+
+// Stats returns the number of lookups of fixed revisions that were served from the cache,
+// and those that were not, since the cache was created.
+func (c *DownloadCache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// Get returns the cached download of the source at version.
+func (c *DownloadCache) Get(source *port.Source, version string) (*port.DownloadResult, bool) {
+	if !cacheableVersion(version) {
+		return nil, false
+	}
+
+	entry := filepath.Join(c.root, downloadCacheKey(source, version))
+	if info, err := os.Stat(entry); err != nil || !info.IsDir() {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.hits.Add(1)
+	return &port.DownloadResult{Path: entry, Version: version}, true
+}
+
+// Put copies the download of the source at version into the cache and returns the cached download.
+// Downloads that did not resolve to the requested version, such as branches, are returned unchanged.
+func (c *DownloadCache) Put(source *port.Source, version string, result *port.DownloadResult) (*port.DownloadResult, error) {
+	if !cacheableVersion(version) || result.Version != version || result.FromGoMod {
+		return result, nil
+	}
+
+	key := downloadCacheKey(source, version)
+	entry := filepath.Join(c.root, key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := os.Stat(entry); err == nil {
+		return &port.DownloadResult{Path: entry, Version: version}, nil
+	}
+
+	if err := os.MkdirAll(c.root, installDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create download cache directory %s: %w", c.root, err)
+	}
+
+	// Copy into a temporary directory first so that other processes never see a partial entry
+	tmp, err := os.MkdirTemp(c.root, ".tmp-"+key+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary download cache entry: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	// Entries are renamed into place, so their contents are flushed first to never expose a truncated file
+	if err := CopyDir(result.Path, tmp, &CopyOptions{Sync: true}); err != nil {
+		return nil, fmt.Errorf("failed to copy download into the cache: %w", err)
+	}
+	if err := os.Rename(tmp, entry); err != nil {
+		// Another process may have cached the same download in the meantime
+		if _, statErr := os.Stat(entry); statErr != nil {
+			return nil, fmt.Errorf("failed to add %s@%s to the download cache: %w", source.URL, version, err)
+		}
+	}
+
+	return &port.DownloadResult{Path: entry, Version: version}, nil
+}
+
+// cacheableVersion reports whether version may name a fixed revision.
+// Empty and "latest" versions are resolved at download time.
+func cacheableVersion(version string) bool {
+	return version != "" && version != "latest"
+}
+
+// downloadCacheKey derives the entry name from the source and version.
+func downloadCacheKey(source *port.Source, version string) string {
+	sum := sha256.Sum256([]byte(source.Type + "\x00" + source.URL + "\x00" + version))
+	return hex.EncodeToString(sum[:16])
+}
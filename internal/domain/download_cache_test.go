@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestDownloadCache_GetPut(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := domain.NewDownloadCache(filepath.Join(tmpDir, "cache"))
+	source := &port.Source{Type: "git", URL: "https://github.com/example/skills.git"}
+
+	download := filepath.Join(tmpDir, "download")
+	if err := os.MkdirAll(download, 0o755); err != nil {
+		t.Fatalf("failed to create download: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(download, "SKILL.md"), []byte("skill"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, ok := cache.Get(source, "v1.0.0"); ok {
+		t.Fatal("Get() on an empty cache should miss")
+	}
+
+	cached, err := cache.Put(source, "v1.0.0", &port.DownloadResult{Path: download, Version: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cached.Path, "SKILL.md")); err != nil {
+		t.Errorf("cached download should contain SKILL.md: %v", err)
+	}
+
+	got, ok := cache.Get(source, "v1.0.0")
+	if !ok {
+		t.Fatal("Get() should hit after Put()")
+	}
+	if got.Path != cached.Path || got.Version != "v1.0.0" {
+		t.Errorf("Get() = %+v, want %+v", got, cached)
+	}
+	if _, ok := cache.Get(&port.Source{Type: "git", URL: "https://github.com/example/other.git"}, "v1.0.0"); ok {
+		t.Error("Get() should miss for another source")
+	}
+	if hits, misses := cache.Stats(); hits != 1 || misses != 2 {
+		t.Errorf("Stats() = %d hits and %d misses, want 1 and 2", hits, misses)
+	}
+
+	tests := []struct {
+		result  *port.DownloadResult
+		name    string
+		version string
+	}{
+		{name: "branch", version: "main", result: &port.DownloadResult{Path: download, Version: "0123456789abcdef0123456789abcdef01234567"}},
+		{name: "latest", version: "latest", result: &port.DownloadResult{Path: download, Version: "latest"}},
+		{name: "go.mod", version: "v1.0.0", result: &port.DownloadResult{Path: download, Version: "v1.0.0", FromGoMod: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &port.Source{Type: "go-mod", URL: "example.com/" + tt.name}
+			result, err := cache.Put(source, tt.version, tt.result)
+			if err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if result.Path != download {
+				t.Errorf("Put() path = %s, want the download left in place", result.Path)
+			}
+			if _, ok := cache.Get(source, tt.version); ok {
+				t.Error("Get() should miss for a download that is not cached")
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DryRunAction is the kind of change a dry run of install or uninstall reports.
+type DryRunAction string
+
+const (
+	DryRunDownload  DryRunAction = "download"   // The skill is downloaded from its source
+	DryRunCopy      DryRunAction = "copy"       // The skill is copied into a target that does not have it
+	DryRunOverwrite DryRunAction = "overwrite"  // The skill replaces the copy in the target
+	DryRunRemove    DryRunAction = "remove"     // The skill is removed from the target
+	DryRunUpToDate  DryRunAction = "up-to-date" // The target already has the skill, so it is left untouched
+	DryRunSkip      DryRunAction = "skip"       // The target is left out, e.g. because its agent does not support the skill
+	DryRunAdd       DryRunAction = "add"        // The skill is added to the configuration, as the SKILL.md of another declares it as a dependency
+)
+
+// DryRunChange is a change that install or uninstall would make.
+// Sizes are in bytes, and -1 when they cannot be known, such as in remote install targets.
+type DryRunChange struct {
+	Action       DryRunAction
+	Skill        string
+	Target       string // Install target as written in .skillspkg.toml; empty for downloads
+	Version      string
+	Reason       string // Why a target is skipped, or the skill that depends on an added skill
+	Size         int64  // Size downloaded, copied, or removed
+	ReplacedSize int64  // Size of the copy an overwrite replaces
+}
+
+// InstallDryRun returns the changes Install would make for the skill, or for every skill when
+// skillName is empty. Skills that are not installed everywhere are downloaded to measure them,
+// but neither the install targets, the configuration, nor the lock file are changed.
+// Content checks such as the scanner and the policy are not run.
+func (s *skillManagerImpl) InstallDryRun(ctx context.Context, skillName string) ([]*DryRunChange, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	skills := config.Skills
+	if skillName != "" {
+		skill := config.FindSkillEntry(skillName)
+		if skill == nil {
+			return nil, &ErrorSkillsNotFound{SkillNames: []string{skillName}}
+		}
+		skills = []*Skill{skill}
+	}
+
+	// The skills they depend on are listed first, in the order they are installed,
+	// after the skills their SKILL.md declares that would be added to the configuration
+	configured := len(config.Skills)
+	waves, _, err := s.installOrder(ctx, config, skills, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	skills = slices.Concat(waves...)
+
+	if len(config.InstallTargets) == 0 {
+		return nil, fmt.Errorf("no install targets configured. Run 'skills-pkg init --install-dir <dir>' to configure install targets")
+	}
+
+	var changes []*DryRunChange
+	for _, added := range config.Skills[configured:] {
+		changes = append(changes, &DryRunChange{Action: DryRunAdd, Skill: added.Name, Version: added.Version, Reason: strings.Join(config.dependentsOf(added), ", "), Size: -1, ReplacedSize: -1})
+	}
+	for _, skill := range skills {
+		skillChanges, err := s.installSkillDryRun(ctx, config, skill)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, skillChanges...)
+	}
+	return changes, nil
+}
+
+// installSkillDryRun returns the changes InstallSingleSkill would make for the entry.
+func (s *skillManagerImpl) installSkillDryRun(ctx context.Context, config *Config, skill *Skill) ([]*DryRunChange, error) {
+	if s.isInstalledInAllTargets(ctx, config, skill) {
+		var changes []*DryRunChange
+		for _, installed := range skill.InstalledSkills() {
+			for _, target := range config.InstallTargets {
+				changes = append(changes, &DryRunChange{Action: DryRunUpToDate, Skill: installed.Name, Target: target, Version: installed.ForTarget(target).Version, Size: -1, ReplacedSize: -1})
+			}
+		}
+		return changes, nil
+	}
+
+	// The skills of the entry, with the directories they are installed from
+	downloadResult, skills, sourcePaths, err := s.fetchEntry(ctx, config, skill)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var downloadSize int64
+	var changes []*DryRunChange
+	for i, installed := range skills {
+		size, err := skillContentSize(sourcePaths[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure skill '%s': %w", installed.Name, err)
+		}
+		downloadSize += size
+
+		requirements, err := ReadAgentRequirements(sourcePaths[i])
+		if err != nil {
+			return nil, err
+		}
+		locked := lock.FindSkill(installed.Name)
+
+		for _, target := range config.InstallTargets {
+			change := &DryRunChange{Skill: installed.Name, Target: target, Version: downloadResult.Version, Size: size, ReplacedSize: -1}
+			changes = append(changes, change)
+
+			// The same conditions as copySkillToTargets
+			status := locked.TargetStatus(target)
+			reason := s.incompatibility(config, target, requirements)
+			switch {
+			case installed.Canary != nil && installed.Canary.Target == target:
+				change.Action, change.Version, change.Size = DryRunUpToDate, installed.Canary.Version, -1
+				continue
+			case s.isInstalledInTarget(ctx, config, installed, downloadResult.Version, status) && (status.Incompatible != "") == (reason != ""):
+				change.Action, change.Size = DryRunUpToDate, -1
+				continue
+			case reason != "":
+				change.Action, change.Reason, change.Size = DryRunSkip, reason, -1
+				continue
+			case IsRemoteTarget(target):
+				change.Action = DryRunCopy
+				continue
+			}
+
+			dir := installed.DirName()
+			if status != nil {
+				dir = status.DirName(installed.Name)
+			}
+			replaced, err := s.treeSize(filepath.Join(target, dir))
+			if err != nil {
+				return nil, err
+			}
+			if replaced < 0 {
+				change.Action = DryRunCopy
+				continue
+			}
+			change.Action, change.ReplacedSize = DryRunOverwrite, replaced
+		}
+	}
+
+	download := &DryRunChange{Action: DryRunDownload, Skill: skill.Name, Version: downloadResult.Version, Size: downloadSize, ReplacedSize: -1}
+	return append([]*DryRunChange{download}, changes...), nil
+}
+
+// UninstallDryRun returns the changes Uninstall would make for the skill without changing anything.
+func (s *skillManagerImpl) UninstallDryRun(ctx context.Context, skillName string) ([]*DryRunChange, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	skill := config.FindSkillByName(skillName)
+	if skill == nil {
+		return nil, &ErrorSkillsNotFound{SkillNames: []string{skillName}}
+	}
+	if dependents := config.dependentsOf(skill); len(dependents) > 0 {
+		return nil, &ErrorDependencyRequired{SkillName: skillName, Dependents: dependents}
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*DryRunChange
+	for _, target := range config.InstallTargets {
+		for _, installed := range skill.InstalledSkills() {
+			dir := installed.DirName()
+			status := lock.FindSkill(installed.Name).TargetStatus(target)
+			if status != nil {
+				dir = status.DirName(installed.Name)
+			}
+
+			change := &DryRunChange{Action: DryRunRemove, Skill: installed.Name, Target: target, Size: -1, ReplacedSize: -1}
+			if status != nil {
+				change.Version = status.Version
+			}
+			if !IsRemoteTarget(target) {
+				size, err := s.treeSize(filepath.Join(target, dir))
+				if err != nil {
+					return nil, err
+				}
+				// Nothing to remove from a target that does not have the skill
+				if size < 0 {
+					continue
+				}
+				change.Size = size
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// treeSize returns the total size of the files under path in the file system of the install targets,
+// or -1 when path does not exist. Symbolic links count with their own size.
+func (s *skillManagerImpl) treeSize(path string) (int64, error) {
+	info, err := s.fsys.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, nil
+		}
+		return 0, fmt.Errorf("failed to access %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+
+	entries, err := s.fsys.ReadDir(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read directory %s: %w", path, err)
+	}
+	var total int64
+	for _, entry := range entries {
+		size, err := s.treeSize(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return 0, err
+		}
+		total += max(size, 0)
+	}
+	return total, nil
+}
+
+// skillContentSize returns the total size of the files of the skill in dir that are installed,
+// leaving out the files excluded by .skillignore.
+func skillContentSize(dir string) (int64, error) {
+	files, err := ListSkillFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrorCode identifies a class of failures. Codes are stable across releases, so they can be
+// searched for and looked up with 'skills-pkg explain'.
+type ErrorCode struct {
+	Code      string // Identifier such as "SKP1203"
+	Summary   string
+	Retryable bool // Whether running the command again may succeed without changing anything
+}
+
+// Error codes, grouped by the hundreds digit: 10 configuration, 12 sources, 13 install targets,
+// 14 integrity, 15 content checks, and 19 failures that have no class of their own.
+var (
+	CodeConfigNotFound     = &ErrorCode{Code: "SKP1001", Summary: "Configuration file not found"}
+	CodeConfigExists       = &ErrorCode{Code: "SKP1002", Summary: "Configuration file already exists"}
+	CodeInvalidSetting     = &ErrorCode{Code: "SKP1003", Summary: "Invalid configuration setting"}
+	CodeInvalidSkill       = &ErrorCode{Code: "SKP1004", Summary: "Invalid skill entry"}
+	CodeDuplicate          = &ErrorCode{Code: "SKP1005", Summary: "Skill or install target already exists"}
+	CodeSkillNotFound      = &ErrorCode{Code: "SKP1006", Summary: "Skill not found in configuration"}
+	CodeConfigDrift        = &ErrorCode{Code: "SKP1007", Summary: "Configuration disagrees with the lock file"}
+	CodePlanStale          = &ErrorCode{Code: "SKP1008", Summary: "Saved plan is out of date"}
+	CodeNoSecretKey        = &ErrorCode{Code: "SKP1009", Summary: "No secret key can decrypt a configuration value"}
+	CodeOrgNonCompliant    = &ErrorCode{Code: "SKP1010", Summary: "Configuration does not comply with the organization policy"}
+	CodeDependency         = &ErrorCode{Code: "SKP1011", Summary: "Skill dependencies cannot be resolved"}
+	CodeInvalidSHA256      = &ErrorCode{Code: "SKP1012", Summary: "Invalid sha256 digest of a skill"}
+	CodeNetworkFailure     = &ErrorCode{Code: "SKP1201", Summary: "Network request failed", Retryable: true}
+	CodeAuthentication     = &ErrorCode{Code: "SKP1202", Summary: "Source requires authentication"}
+	CodeSourceNotFound     = &ErrorCode{Code: "SKP1203", Summary: "Repository, module, or version not found"}
+	CodeSubDirNotFound     = &ErrorCode{Code: "SKP1204", Summary: "Skill subdirectory not found in the source"}
+	CodeHostNotAllowed     = &ErrorCode{Code: "SKP1205", Summary: "Host is not allowed by the network policy"}
+	CodeTargetNotFound     = &ErrorCode{Code: "SKP1301", Summary: "Install target not found in configuration"}
+	CodeTargetNotWritable  = &ErrorCode{Code: "SKP1302", Summary: "Install target is not writable"}
+	CodeRootWriteToUserDir = &ErrorCode{Code: "SKP1303", Summary: "Refusing to write to another user's install target as root"}
+	CodeHashMismatch       = &ErrorCode{Code: "SKP1401", Summary: "Skill content does not match its recorded hash"}
+	CodeVerificationFailed = &ErrorCode{Code: "SKP1402", Summary: "Installed skills failed verification"}
+	CodeSignatureInvalid   = &ErrorCode{Code: "SKP1403", Summary: "Lock file signature is missing or invalid"}
+	CodeBaseChanged        = &ErrorCode{Code: "SKP1404", Summary: "Remote configuration in extends does not match the lock file"}
+	CodePolicyDenied       = &ErrorCode{Code: "SKP1501", Summary: "Policy denied installing a skill"}
+	CodeContentRejected    = &ErrorCode{Code: "SKP1502", Summary: "Scanner rejected a skill"}
+	CodeSymlink            = &ErrorCode{Code: "SKP1503", Summary: "Skill contains a symbolic link that cannot be installed"}
+	CodeMaxDepthExceeded   = &ErrorCode{Code: "SKP1504", Summary: "Skill is nested deeper than max_depth"}
+	CodeNotApproved        = &ErrorCode{Code: "SKP1505", Summary: "Skill version is blocked or has not been approved"}
+	CodeMalformedSkill     = &ErrorCode{Code: "SKP1506", Summary: "SKILL.md is missing or malformed"}
+	CodeInterrupted        = &ErrorCode{Code: "SKP1901", Summary: "Operation was interrupted or timed out", Retryable: true}
+	CodeUnexpected         = &ErrorCode{Code: "SKP1999", Summary: "Unexpected error"}
+)
+
+// errorCodes lists all error codes in ascending order.
+var errorCodes = []*ErrorCode{
+	CodeConfigNotFound,
+	CodeConfigExists,
+	CodeInvalidSetting,
+	CodeInvalidSkill,
+	CodeDuplicate,
+	CodeSkillNotFound,
+	CodeConfigDrift,
+	CodePlanStale,
+	CodeNoSecretKey,
+	CodeOrgNonCompliant,
+	CodeDependency,
+	CodeInvalidSHA256,
+	CodeNetworkFailure,
+	CodeAuthentication,
+	CodeSourceNotFound,
+	CodeSubDirNotFound,
+	CodeHostNotAllowed,
+	CodeTargetNotFound,
+	CodeTargetNotWritable,
+	CodeRootWriteToUserDir,
+	CodeHashMismatch,
+	CodeVerificationFailed,
+	CodeSignatureInvalid,
+	CodeBaseChanged,
+	CodePolicyDenied,
+	CodeContentRejected,
+	CodeSymlink,
+	CodeMaxDepthExceeded,
+	CodeNotApproved,
+	CodeMalformedSkill,
+	CodeInterrupted,
+	CodeUnexpected,
+}
+
+// errorCodeClassifiers map errors to their codes. The first match wins.
+var errorCodeClassifiers = []struct {
+	code  *ErrorCode
+	match func(error) bool
+}{
+	{CodeConfigNotFound, isErrorType[*ErrorConfigNotFound]},
+	{CodeConfigExists, isErrorType[*ErrorConfigExists]},
+	{CodeInvalidSetting, anyOf(
+		isErrorType[*ErrorInvalidHashAlgorithm],
+		isErrorType[*ErrorInvalidHashMismatchPolicy],
+		isErrorType[*ErrorInvalidHiddenCharactersPolicy],
+		isErrorType[*ErrorInvalidSymlinksPolicy],
+		isErrorType[*ErrorInvalidMaxDepth],
+		isErrorType[*ErrorInvalidVersionStrategy],
+		isErrorType[*ErrorInvalidTargetSetting],
+		isErrorType[*ErrorInvalidPlatform],
+		isErrorType[*ErrorInvalidRecipient],
+		isErrorType[*ErrorInvalidProfile],
+		isErrorType[*ErrorInvalidExtends],
+		isErrorType[*ErrorInvalidOrgPolicy],
+		isErrorType[*ErrorInvalidSigning],
+	)},
+	{CodeInvalidSkill, anyOf(
+		isErrorType[*ErrorInvalidSkill],
+		isErrorType[*ErrorInvalidSource],
+		isErrorType[*ErrorConflictingSubDirs],
+		isErrorType[*ErrorInvalidInstallAs],
+		isErrorType[*ErrorInvalidLink],
+		isErrorType[*ErrorInstallDirConflict],
+	)},
+	{CodeDuplicate, anyOf(isErrorType[*ErrorSkillExists], isErrorType[*ErrorInstallTargetExists])},
+	{CodeSkillNotFound, anyOf(isErrorType[*ErrorSkillsNotFound], isErrorType[*ErrorNoCanary], isErrorType[*ErrorNoBackup])},
+	{CodeConfigDrift, anyOf(isErrorType[*ErrorConfigDrift], isErrorType[*ErrorLockMismatch])},
+	{CodePlanStale, isErrorType[*ErrorPlanStale]},
+	{CodeNoSecretKey, isErrorType[*ErrorNoSecretKey]},
+	{CodeOrgNonCompliant, isErrorType[*ErrorOrgNonCompliant]},
+	{CodeDependency, anyOf(isErrorType[*ErrorDependencyNotFound], isErrorType[*ErrorDependencyNotApproved], isErrorType[*ErrorDependencyCycle], isErrorType[*ErrorDependencyRequired])},
+	{CodeInvalidSHA256, isErrorType[*ErrorInvalidSHA256]},
+	// The specific network failures are checked first, as they also wrap ErrNetworkFailure
+	{CodeHostNotAllowed, isErrorType[*ErrorHostNotAllowed]},
+	{CodeAuthentication, isError(ErrAuthenticationRequired)},
+	{CodeSourceNotFound, isError(ErrSourceNotFound)},
+	{CodeNetworkFailure, isError(ErrNetworkFailure)},
+	{CodeSubDirNotFound, isErrorType[*ErrorSubDirNotFound]},
+	{CodeTargetNotFound, isErrorType[*ErrorInstallTargetNotFound]},
+	{CodeTargetNotWritable, isErrorType[*ErrorTargetNotWritable]},
+	{CodeRootWriteToUserDir, isErrorType[*ErrorRootWriteToUserTarget]},
+	{CodeHashMismatch, isErrorType[*ErrorHashMismatch]},
+	{CodeVerificationFailed, isErrorType[*ErrorVerificationFailed]},
+	{CodeSignatureInvalid, isErrorType[*ErrorSignatureInvalid]},
+	{CodeBaseChanged, isErrorType[*ErrorBaseChanged]},
+	{CodePolicyDenied, isErrorType[*ErrorPolicyDenied]},
+	{CodeContentRejected, isErrorType[*ErrorContentRejected]},
+	{CodeSymlink, isErrorType[*ErrorSymlink]},
+	{CodeMaxDepthExceeded, isErrorType[*ErrorMaxDepthExceeded]},
+	{CodeNotApproved, isErrorType[*ErrorNotApproved]},
+	{CodeMalformedSkill, isErrorType[*ErrorMalformedSkill]},
+	{CodeInterrupted, anyOf(isError(context.Canceled), isError(context.DeadlineExceeded))},
+}
+
+// ErrorCodes returns all error codes in ascending order.
+func ErrorCodes() []*ErrorCode {
+	return errorCodes
+}
+
+// LookupErrorCode returns the error code with the identifier, ignoring case, or nil when there is none.
+func LookupErrorCode(code string) *ErrorCode {
+	for _, c := range errorCodes {
+		if strings.EqualFold(c.Code, code) {
+			return c
+		}
+	}
+	return nil
+}
+
+// CodeOf returns the error code of the class err belongs to. Errors without a class of their own
+// get CodeUnexpected, and a nil error gets nil.
+func CodeOf(err error) *ErrorCode {
+	if err == nil {
+		return nil
+	}
+	for _, classifier := range errorCodeClassifiers {
+		if classifier.match(err) {
+			return classifier.code
+		}
+	}
+	return CodeUnexpected
+}
+
+// isErrorType reports whether err wraps an error of type T.
+func isErrorType[T error](err error) bool {
+	_, ok := errors.AsType[T](err)
+	return ok
+}
+
+// isError returns a function reporting whether an error wraps target.
+func isError(target error) func(error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// anyOf returns a function reporting whether an error matches any of the functions.
+func anyOf(matches ...func(error) bool) func(error) bool {
+	return func(err error) bool {
+		for _, match := range matches {
+			if match(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestCodeOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want *domain.ErrorCode
+		name string
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "wrapped type", err: fmt.Errorf("failed to load: %w", &domain.ErrorConfigNotFound{Path: ".skillspkg.toml"}), want: domain.CodeConfigNotFound},
+		{name: "invalid setting", err: &domain.ErrorInvalidMaxDepth{MaxDepth: -1}, want: domain.CodeInvalidSetting},
+		{name: "invalid sha256", err: fmt.Errorf("skill validation failed: %w", &domain.ErrorInvalidSHA256{SkillName: "s", Reason: "bad"}), want: domain.CodeInvalidSHA256},
+		{name: "network failure", err: fmt.Errorf("%w: connection reset", domain.ErrNetworkFailure), want: domain.CodeNetworkFailure},
+		{name: "source not found", err: fmt.Errorf("%w: %w: module example.com/m does not exist", domain.ErrNetworkFailure, domain.ErrSourceNotFound), want: domain.CodeSourceNotFound},
+		{name: "authentication", err: fmt.Errorf("%w: %w: failed to clone", domain.ErrNetworkFailure, domain.ErrAuthenticationRequired), want: domain.CodeAuthentication},
+		{name: "interrupted", err: fmt.Errorf("download: %w", context.Canceled), want: domain.CodeInterrupted},
+		{name: "unclassified", err: errors.New("boom"), want: domain.CodeUnexpected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := domain.CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !domain.CodeNetworkFailure.Retryable || domain.CodeSourceNotFound.Retryable {
+		t.Error("only transient network failures should be retryable")
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	codes := domain.ErrorCodes()
+	if !slices.IsSortedFunc(codes, func(a, b *domain.ErrorCode) int { return strings.Compare(a.Code, b.Code) }) {
+		t.Error("ErrorCodes() is not in ascending order")
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if seen[code.Code] {
+			t.Errorf("duplicate error code %s", code.Code)
+		}
+		seen[code.Code] = true
+		if got := domain.LookupErrorCode(code.Code); got != code {
+			t.Errorf("LookupErrorCode(%s) = %v", code.Code, got)
+		}
+	}
+
+	if got := domain.LookupErrorCode("skp1203"); got != domain.CodeSourceNotFound {
+		t.Errorf("LookupErrorCode() ignoring case = %v, want %v", got, domain.CodeSourceNotFound)
+	}
+	if got := domain.LookupErrorCode("SKP0000"); got != nil {
+		t.Errorf("LookupErrorCode() of an unknown code = %v, want nil", got)
+	}
+}
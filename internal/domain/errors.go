@@ -49,9 +49,9 @@ type ErrorInvalidSource struct {
 
 func (e *ErrorInvalidSource) Error() string {
 	if e.SourceType == "" {
-		return "source type is empty. Supported types: git, go-mod"
+		return "source type is empty. Supported types: git, go-mod, npm, oci, archive, local"
 	}
-	return fmt.Sprintf("source type '%s' is not supported. Supported types: git, go-mod", e.SourceType)
+	return fmt.Sprintf("source type '%s' is not supported. Supported types: git, go-mod, npm, oci, archive, local", e.SourceType)
 }
 
 type ErrorInvalidSkill struct {
@@ -62,6 +62,137 @@ func (e *ErrorInvalidSkill) Error() string {
 	return fmt.Sprintf("invalid skill configuration: field '%s' is required", e.FieldName)
 }
 
+type ErrorConflictingSubDirs struct {
+	SkillName string
+}
+
+func (e *ErrorConflictingSubDirs) Error() string {
+	return fmt.Sprintf("invalid skill configuration: skill '%s' sets both 'subdir' and 'sub_dirs'; use only one of them", e.SkillName)
+}
+
+type ErrorInvalidSHA256 struct {
+	SkillName string
+	Reason    string
+}
+
+func (e *ErrorInvalidSHA256) Error() string {
+	return fmt.Sprintf("invalid skill configuration: 'sha256' of skill '%s' is invalid: %s", e.SkillName, e.Reason)
+}
+
+type ErrorInvalidLink struct {
+	SkillName string
+	Reason    string
+}
+
+func (e *ErrorInvalidLink) Error() string {
+	return fmt.Sprintf("invalid skill configuration: 'link' of skill '%s' is invalid: %s", e.SkillName, e.Reason)
+}
+
+type ErrorDependencyNotFound struct {
+	SkillName  string
+	Dependency string
+	Declared   bool // Whether the dependency is declared by the SKILL.md of the skill rather than in the configuration
+}
+
+func (e *ErrorDependencyNotFound) Error() string {
+	if e.Declared {
+		return fmt.Sprintf("skill '%s' depends on '%s' in its SKILL.md, which does not say where to install it from. Add it with 'skills-pkg add %s' first", e.SkillName, e.Dependency, e.Dependency)
+	}
+	return fmt.Sprintf("invalid skill configuration: skill '%s' depends on '%s', which is not in the configuration. Add it with 'skills-pkg add %s', or remove it from 'depends_on'", e.SkillName, e.Dependency, e.Dependency)
+}
+
+type ErrorDependencyNotApproved struct {
+	SkillName  string
+	Dependency string
+	URL        string
+}
+
+func (e *ErrorDependencyNotApproved) Error() string {
+	return fmt.Sprintf("skill '%s' depends on '%s' from %s in its SKILL.md, which is not in the configuration. Review the source, then install again with --with-dependencies, or add it with 'skills-pkg add %s --url %s'", e.SkillName, e.Dependency, e.URL, e.Dependency, e.URL)
+}
+
+type ErrorDependencyCycle struct {
+	Cycle []string // Skills in the cycle, starting and ending with the same skill
+}
+
+func (e *ErrorDependencyCycle) Error() string {
+	return fmt.Sprintf("skills depend on each other in a cycle: %s. Remove one of the dependencies from 'depends_on'", strings.Join(e.Cycle, " -> "))
+}
+
+type ErrorDependencyRequired struct {
+	SkillName  string
+	Dependents []string
+}
+
+func (e *ErrorDependencyRequired) Error() string {
+	return fmt.Sprintf("skill '%s' cannot be uninstalled because other skills depend on it: %s. Uninstall them first, or remove '%s' from their 'depends_on'", e.SkillName, strings.Join(e.Dependents, ", "), e.SkillName)
+}
+
+type ErrorInvalidInstallAs struct {
+	SkillName string
+	InstallAs string
+	Reason    string
+}
+
+func (e *ErrorInvalidInstallAs) Error() string {
+	return fmt.Sprintf("invalid skill configuration: 'install_as' of skill '%s' is '%s', but %s", e.SkillName, e.InstallAs, e.Reason)
+}
+
+type ErrorInstallDirConflict struct {
+	Dir        string
+	SkillNames []string
+}
+
+func (e *ErrorInstallDirConflict) Error() string {
+	return fmt.Sprintf("invalid skill configuration: skills '%s' would all be installed into the directory '%s'. Set 'install_as' to give them different directories", strings.Join(e.SkillNames, "', '"), e.Dir)
+}
+
+type ErrorInstallTargetNotFound struct {
+	Target string
+}
+
+func (e *ErrorInstallTargetNotFound) Error() string {
+	return fmt.Sprintf("install target '%s' not found in configuration", e.Target)
+}
+
+type ErrorNoCanary struct {
+	SkillName string
+}
+
+func (e *ErrorNoCanary) Error() string {
+	return fmt.Sprintf("skill '%s' has no canary version to promote", e.SkillName)
+}
+
+type ErrorNoBackup struct {
+	SkillName string
+	Version   string
+}
+
+func (e *ErrorNoBackup) Error() string {
+	if e.Version != "" {
+		return fmt.Sprintf("no backup of skill '%s' version %s in the install targets", e.SkillName, e.Version)
+	}
+	return fmt.Sprintf("no backup of skill '%s' with another version than the installed one in the install targets", e.SkillName)
+}
+
+type ErrorSubDirNotFound struct {
+	SkillName   string
+	SubDir      string
+	Suggestions []string // Existing directories with a similar path, closest first
+}
+
+func (e *ErrorSubDirNotFound) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("subdirectory '%s' not found in downloaded skill '%s'. It may have been removed or renamed upstream", e.SubDir, e.SkillName)
+	}
+
+	quotedDirs := make([]string, 0, len(e.Suggestions))
+	for _, dir := range e.Suggestions {
+		quotedDirs = append(quotedDirs, fmt.Sprintf("'%s'", dir))
+	}
+	return fmt.Sprintf("subdirectory '%s' not found in downloaded skill '%s'. Did you mean %s?", e.SubDir, e.SkillName, strings.Join(quotedDirs, ", "))
+}
+
 type ErrorInstallTargetExists struct {
 	Target string
 }
@@ -70,10 +201,319 @@ func (e *ErrorInstallTargetExists) Error() string {
 	return fmt.Sprintf("install target '%s' already exists in configuration", e.Target)
 }
 
+type ErrorInvalidVersionStrategy struct {
+	SourceType string
+	Strategy   string
+}
+
+func (e *ErrorInvalidVersionStrategy) Error() string {
+	return fmt.Sprintf("version strategy '%s' is not supported for source type '%s'. Supported strategies: head, latest-tag", e.Strategy, e.SourceType)
+}
+
+type ErrorInvalidHashAlgorithm struct {
+	Algorithm string
+}
+
+func (e *ErrorInvalidHashAlgorithm) Error() string {
+	return fmt.Sprintf("hash algorithm '%s' is not supported. Supported algorithms: h1, n1", e.Algorithm)
+}
+
+type ErrorInvalidHashMismatchPolicy struct {
+	Policy string
+}
+
+func (e *ErrorInvalidHashMismatchPolicy) Error() string {
+	return fmt.Sprintf("hash_mismatch policy '%s' is not supported. Supported policies: warn, fail, reinstall", e.Policy)
+}
+
+type ErrorInvalidHiddenCharactersPolicy struct {
+	Policy string
+}
+
+func (e *ErrorInvalidHiddenCharactersPolicy) Error() string {
+	return fmt.Sprintf("hidden_characters policy '%s' is not supported. Supported policies: warn, strip", e.Policy)
+}
+
+type ErrorInvalidSymlinksPolicy struct {
+	Policy string
+}
+
+func (e *ErrorInvalidSymlinksPolicy) Error() string {
+	return fmt.Sprintf("symlinks policy '%s' is not supported. Supported policies: flatten, reject", e.Policy)
+}
+
+type ErrorInvalidMaxDepth struct {
+	MaxDepth int
+}
+
+func (e *ErrorInvalidMaxDepth) Error() string {
+	return fmt.Sprintf("max_depth %d is invalid. It must be a positive number, or 0 for the default of %d", e.MaxDepth, DefaultMaxDepth)
+}
+
+type ErrorInvalidProfile struct {
+	Profile string
+}
+
+func (e *ErrorInvalidProfile) Error() string {
+	return fmt.Sprintf("profile '%s' is invalid. Profile names are file names in %s/ without the .toml extension", e.Profile, ProfileDir)
+}
+
+type ErrorInvalidExtends struct {
+	Ref    string
+	Reason string
+}
+
+func (e *ErrorInvalidExtends) Error() string {
+	return fmt.Sprintf("configuration '%s' in extends cannot be loaded: %s", e.Ref, e.Reason)
+}
+
+type ErrorBaseChanged struct {
+	Ref    string
+	Locked string
+	Actual string
+}
+
+func (e *ErrorBaseChanged) Error() string {
+	return fmt.Sprintf("configuration '%s' in extends has changed since it was recorded in the lock file: its files hash to %s instead of %s. Its version may have been moved to other content; review the change, and remove the entry of '%s' from the [[bases]] of the lock file to accept it", e.Ref, e.Actual, e.Locked, e.Ref)
+}
+
+type ErrorInvalidOrgPolicy struct {
+	Ref    string
+	Reason string
+}
+
+func (e *ErrorInvalidOrgPolicy) Error() string {
+	return fmt.Sprintf("organization policy '%s' cannot be loaded: %s", e.Ref, e.Reason)
+}
+
+type ErrorOrgNonCompliant struct {
+	Missing []string // Required skills that are missing or from another source
+	Banned  []string // Skills from banned sources
+}
+
+func (e *ErrorOrgNonCompliant) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("required skills %s are missing or from another source", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Banned) > 0 {
+		problems = append(problems, fmt.Sprintf("skills %s are from banned sources", strings.Join(e.Banned, ", ")))
+	}
+	return "configuration does not comply with the organization policy: " + strings.Join(problems, "; ") + ". Run 'skills-pkg org sync' to apply it"
+}
+
+type ErrorSymlink struct {
+	SkillName string // Empty when the skill is not known, as when filling the download cache
+	Path      string // Slash-separated path of the link relative to the skill directory
+	Reason    string
+}
+
+func (e *ErrorSymlink) Error() string {
+	if e.SkillName == "" {
+		return fmt.Sprintf("symbolic link %s cannot be installed: %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("symbolic link %s in skill '%s' cannot be installed: %s", e.Path, e.SkillName, e.Reason)
+}
+
+type ErrorMaxDepthExceeded struct {
+	SkillName string
+	Path      string // Slash-separated path of the directory relative to the skill directory
+	MaxDepth  int
+}
+
+func (e *ErrorMaxDepthExceeded) Error() string {
+	return fmt.Sprintf("directory %s in skill '%s' is nested deeper than the maximum depth of %d. Set max_depth in .skillspkg.toml to allow deeper skills", e.Path, e.SkillName, e.MaxDepth)
+}
+
+// ErrorMalformedSkill reports the problems that keep a directory from being published as a skill.
+type ErrorMalformedSkill struct {
+	Dir      string
+	Problems []string
+}
+
+func (e *ErrorMalformedSkill) Error() string {
+	return fmt.Sprintf("%s is not a valid skill: %s. See https://agentskills.io/specification for the format of SKILL.md", e.Dir, strings.Join(e.Problems, "; "))
+}
+
+type ErrorHashMismatch struct {
+	SkillName string
+	Location  string // Installed directory, or the downloaded version
+	Expected  string
+	Actual    string
+}
+
+func (e *ErrorHashMismatch) Error() string {
+	return fmt.Sprintf("hash mismatch for skill '%s' in %s: expected %s, got %s", e.SkillName, e.Location, e.Expected, e.Actual)
+}
+
+type ErrorVerificationFailed struct {
+	FailureCount int
+}
+
+func (e *ErrorVerificationFailed) Error() string {
+	return fmt.Sprintf("%d skill installation(s) failed verification", e.FailureCount)
+}
+
+type ErrorConfigDrift struct {
+	DriftCount int
+}
+
+func (e *ErrorConfigDrift) Error() string {
+	return fmt.Sprintf("configuration disagrees with the lock file on %d installation(s)", e.DriftCount)
+}
+
+type ErrorNotApproved struct {
+	SkillName string
+	Version   string
+	Trust     TrustLevel
+	Reason    string
+}
+
+func (e *ErrorNotApproved) Error() string {
+	if e.Trust == TrustBlocked {
+		if e.Reason == "" {
+			return fmt.Sprintf("skill '%s' version %s is blocked", e.SkillName, e.Version)
+		}
+		return fmt.Sprintf("skill '%s' version %s is blocked: %s", e.SkillName, e.Version, e.Reason)
+	}
+	if e.Reason == "" {
+		return fmt.Sprintf("skill '%s' version %s has not been approved. Review it and run 'skills-pkg review approve %s@%s'", e.SkillName, e.Version, e.SkillName, e.Version)
+	}
+	return fmt.Sprintf("skill '%s' version %s is not approved: %s. Review it and run 'skills-pkg review approve %s@%s'", e.SkillName, e.Version, e.Reason, e.SkillName, e.Version)
+}
+
+type ErrorInvalidSigning struct {
+	Reason string
+}
+
+func (e *ErrorInvalidSigning) Error() string {
+	return fmt.Sprintf("invalid [signing] settings: %s", e.Reason)
+}
+
+type ErrorSignatureInvalid struct {
+	Path   string
+	Reason string
+}
+
+func (e *ErrorSignatureInvalid) Error() string {
+	return fmt.Sprintf("lock file signature %s is invalid: %s. Ask a maintainer to sign the lock file with 'skills-pkg lock sign'", e.Path, e.Reason)
+}
+
+type ErrorLockMismatch struct {
+	SkillName string
+	Reason    string
+}
+
+func (e *ErrorLockMismatch) Error() string {
+	return fmt.Sprintf("refusing to install skill '%s' with a frozen lock file: %s. Run 'skills-pkg install' without --frozen to resolve it again", e.SkillName, e.Reason)
+}
+
+type ErrorInvalidTargetSetting struct {
+	Target string
+	Key    string
+	Value  string
+}
+
+func (e *ErrorInvalidTargetSetting) Error() string {
+	return fmt.Sprintf("invalid %s '%s' for install target %s", e.Key, e.Value, e.Target)
+}
+
+type ErrorInvalidPlatform struct {
+	Entry    string
+	Platform string
+}
+
+func (e *ErrorInvalidPlatform) Error() string {
+	return fmt.Sprintf("invalid os '%s' for %s. Use operating system names as in GOOS, such as darwin, linux, or windows", e.Platform, e.Entry)
+}
+
+type ErrorTargetNotWritable struct {
+	Err    error
+	Target string
+}
+
+func (e *ErrorTargetNotWritable) Error() string {
+	return fmt.Sprintf("permission denied writing to install target %s: %v", e.Target, e.Err)
+}
+
+func (e *ErrorTargetNotWritable) Unwrap() error {
+	return e.Err
+}
+
+type ErrorRootWriteToUserTarget struct {
+	Target   string
+	OwnerUID int
+}
+
+func (e *ErrorRootWriteToUserTarget) Error() string {
+	return fmt.Sprintf("refusing to write to install target %s as root: it is owned by uid %d", e.Target, e.OwnerUID)
+}
+
+type ErrorPlanStale struct {
+	Path string
+}
+
+func (e *ErrorPlanStale) Error() string {
+	return fmt.Sprintf("plan %s is stale: the configuration or the install targets changed after it was created", e.Path)
+}
+
+type ErrorPolicyDenied struct {
+	SkillName string
+	Version   string
+	Reason    string
+}
+
+func (e *ErrorPolicyDenied) Error() string {
+	return fmt.Sprintf("policy denied installing skill '%s' version %s: %s", e.SkillName, e.Version, e.Reason)
+}
+
+type ErrorContentRejected struct {
+	SkillName string
+	Version   string
+	Scanner   string
+	Output    string
+}
+
+func (e *ErrorContentRejected) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("scanner %s rejected skill '%s' version %s", e.Scanner, e.SkillName, e.Version)
+	}
+	return fmt.Sprintf("scanner %s rejected skill '%s' version %s: %s", e.Scanner, e.SkillName, e.Version, e.Output)
+}
+
+type ErrorInvalidRecipient struct {
+	Recipient string
+}
+
+func (e *ErrorInvalidRecipient) Error() string {
+	return fmt.Sprintf("invalid recipient '%s'. Recipients are printed by 'skills-pkg keygen' and start with %s", e.Recipient, RecipientPrefix)
+}
+
+type ErrorNoSecretKey struct{}
+
+func (e *ErrorNoSecretKey) Error() string {
+	return fmt.Sprintf("none of the available secret keys can decrypt the value. Set %s, or add the key to the file printed by 'skills-pkg env %s'", SecretKeyEnv, SecretKeyFileEnv)
+}
+
+type ErrorHostNotAllowed struct {
+	Host   string
+	Reason string
+}
+
+func (e *ErrorHostNotAllowed) Error() string {
+	return fmt.Sprintf("connecting to %s is not allowed by the [network] settings: %s", e.Host, e.Reason)
+}
+
 // Sentinel errors for domain-level error identification.
 var (
 	// ErrNetworkFailure indicates that a network request failed.
 	ErrNetworkFailure = errors.New("network request failed")
+	// ErrAuthenticationRequired indicates that a source rejected the request for missing or invalid credentials.
+	// It is wrapped together with ErrNetworkFailure.
+	ErrAuthenticationRequired = errors.New("authentication required")
+	// ErrSourceNotFound indicates that a repository, module, or version does not exist.
+	// It is wrapped together with ErrNetworkFailure.
+	ErrSourceNotFound = errors.New("source not found")
 )
 
 // IsNetworkError checks if an error is a network-related error.
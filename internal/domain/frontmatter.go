@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// byteOrderMark may precede the frontmatter of SKILL.md files written by editors on Windows.
+var byteOrderMark = []byte("\ufeff")
+
+// ToolList is the allowed-tools field of SKILL.md: a list of tools written as a comma or space
+// separated string or as a YAML sequence.
+type ToolList struct {
+	Tools []string
+	Line  int // Line of the value within the frontmatter, counted from 1
+}
+
+// UnmarshalYAML reads a string or a sequence of strings.
+func (l *ToolList) UnmarshalYAML(node *yaml.Node) error {
+	l.Line = node.Line
+	switch node.Kind {
+	case yaml.ScalarNode:
+		l.Tools = splitAllowedTools(node.Value)
+		return nil
+	case yaml.SequenceNode:
+		var tools []string
+		if err := node.Decode(&tools); err != nil {
+			return err
+		}
+		l.Tools = tools
+		return nil
+	default:
+		return fmt.Errorf("line %d: allowed-tools must be a string or a list of strings", node.Line)
+	}
+}
+
+// frontmatterRange locates the YAML frontmatter at the start of SKILL.md content: it starts at start,
+// after the opening '---' line, and stops at stop, where the closing '---' or '...' line begins, which
+// ends at end. A byte order mark before the opening line and CRLF line endings are accepted.
+// start is 0 when the content has no frontmatter, and end is 0 when the frontmatter is not closed.
+func frontmatterRange(content []byte) (start, stop, end int) {
+	offset := 0
+	if bytes.HasPrefix(content, byteOrderMark) {
+		offset = len(byteOrderMark)
+	}
+	switch {
+	case bytes.HasPrefix(content[offset:], []byte("---\n")):
+		start = offset + len("---\n")
+	case bytes.HasPrefix(content[offset:], []byte("---\r\n")):
+		start = offset + len("---\r\n")
+	default:
+		return 0, 0, 0
+	}
+
+	for pos := start; pos < len(content); {
+		n := bytes.IndexByte(content[pos:], '\n')
+		if n < 0 {
+			break
+		}
+		next := pos + n + 1
+		if line := string(bytes.TrimRight(content[pos:next], "\r\n")); line == "---" || line == "..." {
+			return start, pos, next
+		}
+		pos = next
+	}
+	return start, 0, 0
+}
+
+// skillFrontmatter returns the YAML frontmatter of the SKILL.md content: the lines between the opening
+// '---' line and the closing one. It reports false when the content does not start with frontmatter.
+func skillFrontmatter(content []byte) ([]byte, bool) {
+	start, stop, end := frontmatterRange(content)
+	if start == 0 || end == 0 {
+		return nil, false
+	}
+	return content[start:stop], true
+}
+
+// ParseSkillFrontmatter parses the YAML frontmatter at the start of SKILL.md content.
+// It reports false when the content does not start with frontmatter.
+func ParseSkillFrontmatter(content []byte) (*SkillFrontmatter, bool, error) {
+	frontmatter, ok := skillFrontmatter(content)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var metadata SkillFrontmatter
+	if err := yaml.Unmarshal(frontmatter, &metadata); err != nil {
+		return nil, true, err
+	}
+	return &metadata, true, nil
+}
+
+// SkillFrontmatterLines returns the number of lines taken by the frontmatter at the start of SKILL.md
+// content, including the opening and closing lines, or 0 when it has none.
+func SkillFrontmatterLines(content []byte) int {
+	start, _, end := frontmatterRange(content)
+	if start == 0 || end == 0 {
+		return 0
+	}
+	return bytes.Count(content[:end], []byte("\n"))
+}
+
+// readSkillMD parses the frontmatter of SKILL.md of the skill in dir.
+// A skill without SKILL.md or without frontmatter yields nil.
+func readSkillMD(dir string) (*SkillFrontmatter, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read SKILL.md in %s: %w", dir, err)
+	}
+
+	metadata, _, err := ParseSkillFrontmatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the frontmatter of SKILL.md in %s: %w", dir, err)
+	}
+	return metadata, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/mazrean/skills-pkg/internal/port"
 )
@@ -12,21 +14,60 @@ import (
 // It contains details about the verification including expected and actual hash values.
 // Requirements: 5.4, 5.5
 type VerifyResult struct {
-	SkillName  string // Name of the skill being verified
-	InstallDir string // Installation directory path
-	Expected   string // Expected hash value from configuration
-	Actual     string // Actual hash value calculated from directory
-	Match      bool   // Whether the hashes match
+	SkillName  string        // Name of the skill being verified
+	Target     string        // Install target as written in the configuration; empty for Verify
+	InstallDir string        // Installation directory path
+	Expected   string        // Expected hash value from configuration
+	Actual     string        // Actual hash value calculated from directory
+	Match      bool          // Whether the hashes match
+	Drifted    bool          // The hashes differ because of config drift, and the files match the lock file
+	Insecure   bool          // The skill is marked insecure, so a mismatch is not enforced by the hash_mismatch policy
+	Bytes      int64         // Number of content bytes hashed
+	Duration   time.Duration // Time spent hashing the directory
+}
+
+// DriftKind is the kind of disagreement between .skillspkg.toml and .skillspkg.lock.
+type DriftKind string
+
+const (
+	DriftVersion DriftKind = "version" // The configured version differs from the installed version
+	DriftHash    DriftKind = "hash"    // The configured hash differs from the hash of the installed version
+	DriftRemoved DriftKind = "removed" // The lock file records an installation the configuration no longer contains
+)
+
+// ConfigDrift describes a skill installation whose lock file entry disagrees with the configuration,
+// as after editing .skillspkg.toml by hand without running install, update, or sync.
+type ConfigDrift struct {
+	Kind       DriftKind
+	SkillName  string
+	Target     string // Install target as written in the lock file
+	Configured string // Version or hash in .skillspkg.toml; empty for removed installations
+	Locked     string // Version or hash in .skillspkg.lock
 }
 
 // VerifySummary represents the summary of verifying all skills.
 // It provides statistics about the verification results.
 // Requirements: 5.6
 type VerifySummary struct {
-	Results      []*VerifyResult // Detailed results for each skill
-	TotalSkills  int             // Total number of skills verified
-	SuccessCount int             // Number of skills with matching hashes
-	FailureCount int             // Number of skills with mismatching hashes
+	Results      []*VerifyResult        // Detailed results for each skill
+	Drifts       []*ConfigDrift         // Disagreements between the configuration and the lock file
+	Targets      []*TargetVerifySummary // Breakdown by local install target, in configuration order
+	TotalSkills  int                    // Total number of skills verified
+	SuccessCount int                    // Number of skills with matching hashes
+	FailureCount int                    // Number of skills with mismatching hashes, excluding drifted ones
+	BytesHashed  int64                  // Number of content bytes hashed in all install targets
+	Duration     time.Duration          // Time spent verifying
+}
+
+// TargetVerifySummary is the part of a VerifySummary for a single install target.
+type TargetVerifySummary struct {
+	Target       string // Install target as written in the configuration
+	TotalSkills  int
+	SuccessCount int
+	FailureCount int
+	DriftCount   int // Number of skills whose hashes differ only because of config drift
+	BytesHashed  int64
+	Duration     time.Duration
 }
 
 // HashVerifier manages hash verification for skills.
@@ -35,6 +76,7 @@ type VerifySummary struct {
 type HashVerifier struct {
 	configManager *ConfigManager
 	hashService   port.HashService
+	lockManager   *LockManager
 }
 
 // NewHashVerifier creates a new HashVerifier instance.
@@ -44,6 +86,7 @@ func NewHashVerifier(configManager *ConfigManager, hashService port.HashService)
 	return &HashVerifier{
 		configManager: configManager,
 		hashService:   hashService,
+		lockManager:   NewLockManager(LockPathFor(configManager.configPath)),
 	}
 }
 
@@ -59,31 +102,42 @@ func (v *HashVerifier) Verify(ctx context.Context, skillName string, installDir
 	}
 
 	// Find the skill in configuration
-	skill := config.FindSkillByName(skillName)
+	skill := config.FindInstalledSkill(skillName)
 	if skill == nil {
 		return nil, &ErrorSkillsNotFound{SkillNames: []string{skillName}}
 	}
 
+	return v.verify(ctx, config, skill, installDir)
+}
+
+// verify compares the hash of the skill with the actual hash of installDir.
+func (v *HashVerifier) verify(ctx context.Context, config *Config, skill *Skill, installDir string) (*VerifyResult, error) {
 	// Calculate actual hash of the skill directory
-	hashResult, err := v.hashService.CalculateHash(ctx, installDir)
+	start := time.Now()
+	hashResult, err := v.hashService.CalculateHash(ctx, installDir, port.HashAlgorithmOf(skill.HashValue), config.HashOptions(skill)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate hash for skill '%s' in directory %s: %w", skillName, installDir, err)
+		return nil, fmt.Errorf("failed to calculate hash for skill '%s' in directory %s: %w", skill.Name, installDir, err)
 	}
 
 	// Compare expected and actual hashes
 	match := skill.HashValue == hashResult.Value
 
 	return &VerifyResult{
-		SkillName:  skillName,
+		SkillName:  skill.Name,
 		InstallDir: installDir,
 		Expected:   skill.HashValue,
 		Actual:     hashResult.Value,
 		Match:      match,
+		Insecure:   skill.Insecure,
+		Bytes:      hashResult.Bytes,
+		Duration:   time.Since(start),
 	}, nil
 }
 
 // VerifyAll verifies the hashes of all skills in all installation target directories.
 // It returns a summary containing statistics and detailed results for each verification.
+// It also compares the configuration with the lock file. Installations whose files match
+// the lock file but not the drifted configuration are reported as drift instead of failures.
 // Requirements: 5.4, 5.6
 func (v *HashVerifier) VerifyAll(ctx context.Context) (*VerifySummary, error) {
 	// Load configuration
@@ -92,6 +146,8 @@ func (v *HashVerifier) VerifyAll(ctx context.Context) (*VerifySummary, error) {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	start := time.Now()
+
 	// Get installation target directories
 	installTargets, err := v.configManager.GetInstallTargets(ctx)
 	if err != nil {
@@ -106,36 +162,81 @@ func (v *HashVerifier) VerifyAll(ctx context.Context) (*VerifySummary, error) {
 		Results:      []*VerifyResult{},
 	}
 
-	// If there are no skills, return empty summary
-	if len(config.Skills) == 0 {
-		return summary, nil
+	lock, err := v.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Local install targets get a breakdown, even when no skill is configured
+	targetSummaries := make(map[string]*TargetVerifySummary, len(installTargets))
+	for _, installTarget := range installTargets {
+		if IsRemoteTarget(installTarget) {
+			continue
+		}
+		targetSummaries[installTarget] = &TargetVerifySummary{Target: installTarget}
+		summary.Targets = append(summary.Targets, targetSummaries[installTarget])
 	}
 
 	// Verify each skill in each installation target
-	for _, skill := range config.Skills {
+	for _, skill := range config.InstalledSkills() {
+		locked := lock.FindSkill(skill.Name)
 		for _, installTarget := range installTargets {
-			// Construct the skill directory path
-			skillDir := filepath.Join(installTarget, skill.Name)
+			status := locked.TargetStatus(installTarget)
+			// The canary target is expected to hold the canary version
+			expected := skill.ForTarget(installTarget)
+			drift := configDrift(expected, status)
+			if drift != nil {
+				summary.Drifts = append(summary.Drifts, drift)
+			}
+
+			// Remote targets cannot be hashed locally, and targets of agents that do not support the skill have no copy
+			if IsRemoteTarget(installTarget) || (drift == nil && status != nil && status.Incompatible != "") {
+				continue
+			}
+
+			// Construct the skill directory path, where the lock file shows it was installed
+			dirName := skill.DirName()
+			if status != nil {
+				dirName = status.DirName(skill.Name)
+			}
+			skillDir := filepath.Join(installTarget, dirName)
 
 			// Verify the skill
-			result, err := v.Verify(ctx, skill.Name, skillDir)
+			result, err := v.verify(ctx, config, expected, skillDir)
 			if err != nil {
 				// If verification fails (e.g., directory doesn't exist), record as failure
 				result = &VerifyResult{
 					SkillName:  skill.Name,
 					InstallDir: skillDir,
-					Expected:   skill.HashValue,
+					Expected:   expected.HashValue,
 					Actual:     "",
 					Match:      false,
+					Insecure:   skill.Insecure,
 				}
 			}
+			result.Target = installTarget
+
+			// Files left as installed are not tampered with, even though the configuration moved on
+			if !result.Match && drift != nil && result.Actual != "" {
+				result.Drifted = v.matchesLock(ctx, config, skill, skillDir, status, result.Actual)
+			}
 
 			// Update summary statistics
+			targetSummary := targetSummaries[installTarget]
 			summary.TotalSkills++
-			if result.Match {
+			targetSummary.TotalSkills++
+			summary.BytesHashed += result.Bytes
+			targetSummary.BytesHashed += result.Bytes
+			targetSummary.Duration += result.Duration
+			switch {
+			case result.Match:
 				summary.SuccessCount++
-			} else {
+				targetSummary.SuccessCount++
+			case result.Drifted:
+				targetSummary.DriftCount++
+			default:
 				summary.FailureCount++
+				targetSummary.FailureCount++
 			}
 
 			// Add result to the list
@@ -143,5 +244,55 @@ func (v *HashVerifier) VerifyAll(ctx context.Context) (*VerifySummary, error) {
 		}
 	}
 
+	// Installations of removed skills and from removed install targets, following the rules of SkillManager.Prune
+	for _, locked := range lock.Skills {
+		configured := config.FindInstalledSkill(locked.Name) != nil
+		for _, status := range locked.Targets {
+			if configured && slices.Contains(installTargets, status.Path) {
+				continue
+			}
+			summary.Drifts = append(summary.Drifts, &ConfigDrift{
+				Kind:      DriftRemoved,
+				SkillName: locked.Name,
+				Target:    status.Path,
+				Locked:    status.Version,
+			})
+		}
+	}
+
+	summary.Duration = time.Since(start)
 	return summary, nil
 }
+
+// configDrift returns the disagreement between the configured skill and its lock file status,
+// or nil if they agree or the skill is not recorded in the install target.
+func configDrift(skill *Skill, status *TargetStatus) *ConfigDrift {
+	if status == nil {
+		return nil
+	}
+
+	switch {
+	case skill.Version != "" && status.Version != "" && skill.Version != status.Version:
+		return &ConfigDrift{Kind: DriftVersion, SkillName: skill.Name, Target: status.Path, Configured: skill.Version, Locked: status.Version}
+	case skill.HashValue != "" && status.HashValue != "" && skill.HashValue != status.HashValue:
+		return &ConfigDrift{Kind: DriftHash, SkillName: skill.Name, Target: status.Path, Configured: skill.HashValue, Locked: status.HashValue}
+	}
+
+	return nil
+}
+
+// matchesLock reports whether the files in skillDir match the hash recorded in the lock file.
+// actual is the hash already calculated with the algorithm of the configured hash.
+func (v *HashVerifier) matchesLock(ctx context.Context, config *Config, skill *Skill, skillDir string, status *TargetStatus, actual string) bool {
+	if status.HashValue == "" {
+		return false
+	}
+	if port.HashAlgorithmOf(status.HashValue) != port.HashAlgorithmOf(actual) {
+		hashResult, err := v.hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmOf(status.HashValue), config.HashOptions(skill)...)
+		if err != nil {
+			return false
+		}
+		actual = hashResult.Value
+	}
+	return actual == status.HashValue
+}
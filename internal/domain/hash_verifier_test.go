@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/mazrean/skills-pkg/internal/adapter/filesystem"
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
 	"github.com/mazrean/skills-pkg/internal/port"
@@ -112,7 +113,7 @@ func TestHashVerifier_Verify(t *testing.T) {
 
 			// Calculate the expected hash
 			hashService := service.NewDirhash()
-			expectedHash, err := hashService.CalculateHash(ctx, skillDir)
+			expectedHash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 			if err != nil {
 				t.Fatalf("failed to calculate expected hash: %v", err)
 			}
@@ -173,6 +174,54 @@ func TestHashVerifier_Verify(t *testing.T) {
 	}
 }
 
+// TestHashVerifier_VerifyFileSystem tests that skills are verified in the file system of the HashService.
+func TestHashVerifier_VerifyFileSystem(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	skillDir := filepath.Join(tmpDir, "skills", "test-skill")
+
+	fsys := filesystem.NewMemory()
+	if err := fsys.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatalf("failed to create skill directory: %v", err)
+	}
+	if err := fsys.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Skill\n"), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	hashService := service.NewDirhashFS(fsys)
+	expectedHash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
+	if err != nil {
+		t.Fatalf("failed to calculate expected hash: %v", err)
+	}
+
+	configManager := domain.NewConfigManager(filepath.Join(tmpDir, ".skillspkg.toml"))
+	if err := configManager.Initialize(ctx, []string{filepath.Join(tmpDir, "skills")}); err != nil {
+		t.Fatalf("failed to initialize config: %v", err)
+	}
+	if err := configManager.AddSkill(ctx, &domain.Skill{Name: "test-skill", Source: "git", URL: "https://github.com/test/test-skill.git", Version: "v1.0.0", HashValue: expectedHash.Value}); err != nil {
+		t.Fatalf("failed to add test skill: %v", err)
+	}
+	verifier := domain.NewHashVerifier(configManager, hashService)
+
+	result, err := verifier.Verify(ctx, "test-skill", skillDir)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !result.Match {
+		t.Errorf("Verify() = %+v, want a match", result)
+	}
+
+	if err := fsys.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Changed\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+	result, err = verifier.Verify(ctx, "test-skill", skillDir)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Match {
+		t.Error("Verify() matched after the file changed in the file system")
+	}
+}
+
 func TestHashVerifier_VerifyAll(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -245,7 +294,7 @@ func TestHashVerifier_VerifyAll(t *testing.T) {
 				}
 
 				// Calculate hash
-				hash, err := hashService.CalculateHash(ctx, skillDir)
+				hash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 				if err != nil {
 					t.Fatalf("failed to calculate hash: %v", err)
 				}
@@ -353,6 +402,22 @@ func TestHashVerifier_VerifyAll(t *testing.T) {
 				t.Errorf("expected %d results, got: %d", tt.wantTotalSkills, len(summary.Results))
 			}
 
+			// All skills are in the single install target, so its breakdown matches the totals
+			if len(summary.Targets) != 1 {
+				t.Fatalf("expected 1 target breakdown, got: %d", len(summary.Targets))
+			}
+			if target := summary.Targets[0]; target.Target != tmpDir || target.TotalSkills != tt.wantTotalSkills ||
+				target.SuccessCount != tt.wantSuccessCount || target.FailureCount != tt.wantFailureCount || target.BytesHashed != summary.BytesHashed {
+				t.Errorf("target breakdown = %+v, want the totals of the summary in %s", target, tmpDir)
+			}
+			var bytesHashed int64
+			for _, result := range summary.Results {
+				bytesHashed += result.Bytes
+			}
+			if summary.BytesHashed != bytesHashed || (tt.wantTotalSkills > 0 && bytesHashed == 0) {
+				t.Errorf("expected %d bytes hashed in total, got: %d", bytesHashed, summary.BytesHashed)
+			}
+
 			// Verify specific failed skill if expected
 			if tt.wantFailedSkillName != "" {
 				foundFailedSkill := false
@@ -377,3 +442,78 @@ func TestHashVerifier_VerifyAll(t *testing.T) {
 		})
 	}
 }
+
+func TestHashVerifier_VerifyAll_ConfigDrift(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	target := filepath.Join(tmpDir, "skills")
+	hashService := service.NewDirhash()
+
+	hashes := make(map[string]string)
+	for _, name := range []string{"bumped", "tampered"} {
+		skillDir := filepath.Join(target, name)
+		if err := os.MkdirAll(skillDir, 0o755); err != nil {
+			t.Fatalf("failed to create skill directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(name), 0o644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		hash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
+		if err != nil {
+			t.Fatalf("failed to calculate hash: %v", err)
+		}
+		hashes[name] = hash.Value
+	}
+	if err := os.WriteFile(filepath.Join(target, "tampered", "SKILL.md"), []byte("edited"), 0o644); err != nil {
+		t.Fatalf("failed to modify skill file: %v", err)
+	}
+
+	// "bumped" had its version and hash edited by hand without running update
+	configManager := domain.NewConfigManager(configPath)
+	err := configManager.Save(ctx, &domain.Config{
+		InstallTargets: []string{target},
+		Skills: []*domain.Skill{
+			{Name: "bumped", Source: "git", URL: "https://example.com/bumped.git", Version: "v2.0.0", HashValue: "h1:edited"},
+			{Name: "tampered", Source: "git", URL: "https://example.com/tampered.git", Version: "v1.0.0", HashValue: hashes["tampered"]},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	err = domain.NewLockManager(domain.LockPathFor(configPath)).Update(ctx, func(lock *domain.LockFile) {
+		lock.RecordInstall("bumped", &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: hashes["bumped"]})
+		lock.RecordInstall("tampered", &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: hashes["tampered"]})
+		lock.RecordInstall("removed", &domain.TargetStatus{Path: target, Version: "v0.1.0"})
+	})
+	if err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	summary, err := domain.NewHashVerifier(configManager, hashService).VerifyAll(ctx)
+	if err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+
+	if summary.TotalSkills != 2 || summary.SuccessCount != 0 || summary.FailureCount != 1 {
+		t.Errorf("summary = %d total, %d successful, %d failed, want 2, 0, 1", summary.TotalSkills, summary.SuccessCount, summary.FailureCount)
+	}
+	for _, result := range summary.Results {
+		if wantDrifted := result.SkillName == "bumped"; result.Drifted != wantDrifted {
+			t.Errorf("result for %s Drifted = %v, want %v", result.SkillName, result.Drifted, wantDrifted)
+		}
+	}
+
+	want := []domain.ConfigDrift{
+		{Kind: domain.DriftVersion, SkillName: "bumped", Target: target, Configured: "v2.0.0", Locked: "v1.0.0"},
+		{Kind: domain.DriftRemoved, SkillName: "removed", Target: target, Locked: "v0.1.0"},
+	}
+	if len(summary.Drifts) != len(want) {
+		t.Fatalf("Drifts = %d, want %d", len(summary.Drifts), len(want))
+	}
+	for i, drift := range summary.Drifts {
+		if *drift != want[i] {
+			t.Errorf("Drifts[%d] = %+v, want %+v", i, *drift, want[i])
+		}
+	}
+}
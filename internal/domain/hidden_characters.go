@@ -0,0 +1,276 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// hiddenRuneNames names the hidden characters that are most often used to disguise text.
+var hiddenRuneNames = map[rune]string{
+	0x00AD: "SOFT HYPHEN",
+	0x200B: "ZERO WIDTH SPACE",
+	0x200C: "ZERO WIDTH NON-JOINER",
+	0x200D: "ZERO WIDTH JOINER",
+	0x200E: "LEFT-TO-RIGHT MARK",
+	0x200F: "RIGHT-TO-LEFT MARK",
+	0x202A: "LEFT-TO-RIGHT EMBEDDING",
+	0x202B: "RIGHT-TO-LEFT EMBEDDING",
+	0x202C: "POP DIRECTIONAL FORMATTING",
+	0x202D: "LEFT-TO-RIGHT OVERRIDE",
+	0x202E: "RIGHT-TO-LEFT OVERRIDE",
+	0x2060: "WORD JOINER",
+	0x2066: "LEFT-TO-RIGHT ISOLATE",
+	0x2067: "RIGHT-TO-LEFT ISOLATE",
+	0x2068: "FIRST STRONG ISOLATE",
+	0x2069: "POP DIRECTIONAL ISOLATE",
+	0xFEFF: "ZERO WIDTH NO-BREAK SPACE",
+}
+
+// confusableScripts are scripts whose letters look like Latin letters.
+// A file name that mixes letters of more than one of them is likely meant to impersonate another name.
+var confusableScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{name: "Latin", table: unicode.Latin},
+	{name: "Cyrillic", table: unicode.Cyrillic},
+	{name: "Greek", table: unicode.Greek},
+	{name: "Armenian", table: unicode.Armenian},
+	{name: "Cherokee", table: unicode.Cherokee},
+}
+
+// HiddenCharacterFinding is a hidden character in a file of a skill, or a suspicious file name.
+type HiddenCharacterFinding struct {
+	File    string // Slash-separated path relative to the skill directory
+	Message string
+	Line    int // Zero for findings in the file name
+}
+
+func (f *HiddenCharacterFinding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s", f.File, f.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+}
+
+// FindHiddenCharacters reports the zero-width and bidirectional control characters in the text files
+// of the skill in dir, and the file names that contain such characters or mix look-alike scripts.
+// Binary files are not inspected, and a byte order mark at the start of a file is not reported.
+func FindHiddenCharacters(dir string) ([]*HiddenCharacterFinding, error) {
+	files, err := ListSkillFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []*HiddenCharacterFinding
+	for _, file := range files {
+		if message := suspiciousFileName(file); message != "" {
+			findings = append(findings, &HiddenCharacterFinding{File: file, Message: message})
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, err
+		}
+		if !isText(data) {
+			continue
+		}
+
+		for i, line := range strings.Split(string(bytes.TrimPrefix(data, []byte("\ufeff"))), "\n") {
+			hidden := hiddenRunes(line)
+			if len(hidden) == 0 {
+				continue
+			}
+			names := make([]string, 0, len(hidden))
+			for _, r := range hidden {
+				names = append(names, describeRune(r))
+			}
+			findings = append(findings, &HiddenCharacterFinding{File: file, Line: i + 1, Message: "contains " + strings.Join(names, ", ")})
+		}
+	}
+
+	return findings, nil
+}
+
+// StripHiddenCharacters removes the characters reported by FindHiddenCharacters from the text files
+// of the skill in dir, and returns the number of files changed. File names are left as they are.
+func StripHiddenCharacters(dir string) (int, error) {
+	files, err := ListSkillFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return changed, err
+		}
+		if !isText(data) {
+			continue
+		}
+
+		bom := bytes.HasPrefix(data, []byte("\ufeff"))
+		text := string(bytes.TrimPrefix(data, []byte("\ufeff")))
+		stripped := removeHiddenRunes(text)
+		if stripped == text {
+			continue
+		}
+		if bom {
+			stripped = "\ufeff" + stripped
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return changed, err
+		}
+		if err := os.WriteFile(path, []byte(stripped), info.Mode().Perm()); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	return changed, nil
+}
+
+// hiddenRunes returns the hidden characters in s, in order.
+// Joiners inside emoji sequences and tag characters of flag emoji are part of the emoji and not reported.
+func hiddenRunes(s string) []rune {
+	var hidden []rune
+	forEachHiddenRune(s, func(_ int, r rune) { hidden = append(hidden, r) })
+	return hidden
+}
+
+// removeHiddenRunes returns s without the characters reported by hiddenRunes.
+func removeHiddenRunes(s string) string {
+	var b strings.Builder
+	last := 0
+	forEachHiddenRune(s, func(offset int, r rune) {
+		b.WriteString(s[last:offset])
+		last = offset + utf8.RuneLen(r)
+	})
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// forEachHiddenRune calls fn with the byte offset of each hidden character in s.
+func forEachHiddenRune(s string, fn func(offset int, r rune)) {
+	runes := []rune(s)
+	offset := 0
+	inFlag := false
+	for i, r := range runes {
+		switch {
+		case r == 0x1F3F4: // Waving black flag, which starts subdivision flags such as England's
+			inFlag = true
+		case inFlag && r >= 0xE0020 && r <= 0xE007F:
+			// Tag characters spelling the subdivision of the flag
+		case r == 0x200D && i > 0 && i < len(runes)-1 && isEmojiRune(runes[i-1]) && isEmojiRune(runes[i+1]):
+			// Zero width joiner combining emoji, as in family and profession emoji
+		default:
+			inFlag = false
+			if IsHiddenRune(r) {
+				fn(offset, r)
+			}
+		}
+		offset += utf8.RuneLen(r)
+	}
+}
+
+// isEmojiRune reports whether r can be part of an emoji sequence joined by a zero width joiner.
+func isEmojiRune(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || r == 0xFE0F || unicode.Is(unicode.So, r)
+}
+
+// describeRune returns the code point of r with its name when it is known.
+func describeRune(r rune) string {
+	if name, ok := hiddenRuneNames[r]; ok {
+		return fmt.Sprintf("U+%04X %s", r, name)
+	}
+	return fmt.Sprintf("U+%04X", r)
+}
+
+// suspiciousFileName returns why the slash-separated path file looks like it impersonates another name,
+// or an empty string when it does not.
+func suspiciousFileName(file string) string {
+	for _, name := range strings.Split(file, "/") {
+		if hidden := hiddenRunes(name); len(hidden) > 0 {
+			return fmt.Sprintf("file name %q contains %s", name, describeRune(hidden[0]))
+		}
+
+		var scripts []string
+		for _, r := range name {
+			if r >= 0xFF01 && r <= 0xFF5E {
+				return fmt.Sprintf("file name %q contains fullwidth character %q", name, r)
+			}
+			for _, script := range confusableScripts {
+				if unicode.Is(script.table, r) && !slices.Contains(scripts, script.name) {
+					scripts = append(scripts, script.name)
+				}
+			}
+		}
+		if len(scripts) > 1 {
+			return fmt.Sprintf("file name %q mixes look-alike %s letters", name, strings.Join(scripts, " and "))
+		}
+	}
+	return ""
+}
+
+// isText reports whether data looks like UTF-8 text rather than a binary file.
+func isText(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	return utf8.Valid(data) && bytes.IndexByte(head, 0) < 0
+}
+
+// sanitizeContent reports the hidden characters in the downloaded skill in sourcePath when
+// hidden_characters is set, and returns the directory to install the skill from. With the strip
+// policy, that is a copy without the characters, so the download and the download cache are unchanged.
+// The returned function removes the copy, and must be called once the directory is no longer used.
+func (s *skillManagerImpl) sanitizeContent(ctx context.Context, config *Config, sourcePath string, skill *Skill) (string, func(), error) {
+	if config.HiddenCharacters == "" {
+		return sourcePath, func() {}, nil
+	}
+
+	findings, err := FindHiddenCharacters(sourcePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to inspect skill '%s' for hidden characters: %w", skill.Name, err)
+	}
+	strip := false
+	for _, finding := range findings {
+		s.emit(ctx, skill.Name, PhaseWarning, "", "WARNING: skill '%s' %s", skill.Name, finding)
+		strip = strip || finding.Line > 0
+	}
+	if config.HiddenCharacters != HiddenCharactersStrip || !strip {
+		return sourcePath, func() {}, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "skills-pkg-sanitized-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create directory for sanitized skill '%s': %w", skill.Name, err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	// A subdirectory keeps the permissions of the skill directory, which the temporary directory does not have
+	sanitized := filepath.Join(tmp, "skill")
+	if err := CopyDir(sourcePath, sanitized, nil); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to copy skill '%s' for sanitizing: %w", skill.Name, err)
+	}
+	changed, err := StripHiddenCharacters(sanitized)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to strip hidden characters from skill '%s': %w", skill.Name, err)
+	}
+	s.emit(ctx, skill.Name, PhaseCheck, "", "Removed hidden characters from %d file(s) of skill '%s'", changed, skill.Name)
+
+	return sanitized, cleanup, nil
+}
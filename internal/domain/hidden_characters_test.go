@@ -0,0 +1,88 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func writeSkillFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindHiddenCharacters(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeSkillFiles(t, dir, map[string]string{
+		"SKILL.md":           "\ufeff# Skill\nRun \u202egnp.exe\u202c now\n",
+		"clean.md":           "Family: \U0001F468\u200d\U0001F469\u200d\U0001F467, flag: \U0001F3F4\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F\n",
+		"scripts/run.sh":     "echo ok\necho\u200bhidden\n",
+		"image.png":          "\x89PNG\x00\u200b",
+		"\u0455cripts/a.txt": "plain\n",
+	})
+
+	findings, err := domain.FindHiddenCharacters(dir)
+	if err != nil {
+		t.Fatalf("FindHiddenCharacters() error = %v", err)
+	}
+
+	want := []string{
+		"SKILL.md:2: contains U+202E RIGHT-TO-LEFT OVERRIDE, U+202C POP DIRECTIONAL FORMATTING",
+		"scripts/run.sh:2: contains U+200B ZERO WIDTH SPACE",
+		"\u0455cripts/a.txt: file name \"\u0455cripts\" mixes look-alike Cyrillic and Latin letters",
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("FindHiddenCharacters() = %v, want %v", findings, want)
+	}
+	for i, finding := range findings {
+		if finding.String() != want[i] {
+			t.Errorf("finding %d = %q, want %q", i, finding.String(), want[i])
+		}
+	}
+}
+
+func TestStripHiddenCharacters(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	emoji := "\U0001F468\u200d\U0001F4BB\n"
+	writeSkillFiles(t, dir, map[string]string{
+		"SKILL.md":  "\ufeff# Skill\nRun \u202egnp.exe\u202c now\n",
+		"emoji.md":  emoji,
+		"image.png": "\x89PNG\x00\u200b",
+	})
+
+	changed, err := domain.StripHiddenCharacters(dir)
+	if err != nil {
+		t.Fatalf("StripHiddenCharacters() error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("StripHiddenCharacters() changed %d files, want 1", changed)
+	}
+
+	for name, want := range map[string]string{
+		"SKILL.md":  "\ufeff# Skill\nRun gnp.exe now\n",
+		"emoji.md":  emoji,
+		"image.png": "\x89PNG\x00\u200b",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
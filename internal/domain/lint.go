@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Lint rules reported in LintFinding.Rule.
+const (
+	LintPromptInjection  = "prompt-injection"  // Text that tries to override the agent's instructions
+	LintHiddenUnicode    = "hidden-unicode"    // Invisible or bidirectional control characters
+	LintBroadPermissions = "broad-permissions" // allowed-tools that grant unrestricted tool use
+)
+
+// LintFinding is a suspicious line in a skill's Markdown.
+type LintFinding struct {
+	File    string // Slash-separated path relative to the skill directory
+	Rule    string
+	Message string
+	Line    int
+}
+
+func (f *LintFinding) String() string {
+	return fmt.Sprintf("%s:%d: [%s] %s", f.File, f.Line, f.Rule, f.Message)
+}
+
+// promptInjectionPatterns are phrases commonly used to hijack an agent, matched case-insensitively.
+var promptInjectionPatterns = []struct {
+	re      *regexp.Regexp
+	message string
+}{
+	{
+		re:      regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(the\s+)?(previous|prior|above|earlier|other)\s+(instructions|prompts|rules|messages)`),
+		message: "asks the agent to ignore its other instructions",
+	},
+	{
+		re:      regexp.MustCompile(`(?i)\b(reveal|print|show|output|repeat)\s+(your\s+|the\s+)?(system\s+prompt|hidden\s+instructions)`),
+		message: "asks the agent to reveal its system prompt",
+	},
+	{
+		re:      regexp.MustCompile(`(?i)\b(without|don'?t|do\s+not|never)\s+(asking|ask|telling|tell|informing|inform|notifying|notify|mentioning|mention)(ing)?\s+(it\s+to\s+|this\s+to\s+)?(the\s+)?user`),
+		message: "asks the agent to act without the user's knowledge",
+	},
+	{
+		re:      regexp.MustCompile(`(?i)\b(curl|wget)\b[^|\n]*\|\s*(sudo\s+)?(ba|z)?sh\b`),
+		message: "pipes a downloaded script into a shell",
+	},
+	{
+		re:      regexp.MustCompile(`(?i)\bbase64\s+(-d|--decode)\b[^|\n]*\|\s*(sudo\s+)?(ba|z)?sh\b`),
+		message: "pipes decoded data into a shell",
+	},
+	{
+		re:      regexp.MustCompile(`(?i)\b(send|upload|post|exfiltrate)\b.{0,40}\b(api[\s_-]?keys?|credentials|secrets|tokens|passwords|\.env|ssh\s+keys?)\b`),
+		message: "asks the agent to send credentials or secrets",
+	},
+}
+
+// broadToolPatterns are allowed-tools entries that grant unrestricted use of powerful tools.
+var broadToolPatterns = []struct {
+	re      *regexp.Regexp
+	message string
+}{
+	{re: regexp.MustCompile(`^\*$`), message: "allows every tool"},
+	{re: regexp.MustCompile(`^Bash(\(\s*\*?\s*(:\s*\*)?\s*\))?$`), message: "allows any shell command; restrict it, e.g. Bash(git status:*)"},
+	{re: regexp.MustCompile(`^(Write|Edit)(\(\s*(\*\*?|/\*\*)\s*\))?$`), message: "allows writing any file; restrict it to the paths the skill needs"},
+}
+
+// LintSkill scans the Markdown files of the skill in dir for prompt-injection patterns,
+// hidden Unicode characters, and broad tool permissions in the SKILL.md frontmatter.
+// Findings are heuristics: they point at text worth reviewing, not at proven attacks.
+func LintSkill(dir string) ([]*LintFinding, error) {
+	files, err := ListSkillFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []*LintFinding
+	for _, file := range files {
+		if ext := strings.ToLower(path.Ext(file)); ext != ".md" && ext != ".mdx" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, lintMarkdown(file, data)...)
+	}
+
+	return findings, nil
+}
+
+// lintMarkdown returns the findings in a single Markdown file.
+func lintMarkdown(file string, data []byte) []*LintFinding {
+	var findings []*LintFinding
+	// A byte order mark at the start of the file is harmless
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+
+		if r, ok := firstHiddenRune(line); ok {
+			findings = append(findings, &LintFinding{File: file, Line: lineNo, Rule: LintHiddenUnicode, Message: fmt.Sprintf("contains invisible character U+%04X", r)})
+		}
+		for _, pattern := range promptInjectionPatterns {
+			if pattern.re.MatchString(line) {
+				findings = append(findings, &LintFinding{File: file, Line: lineNo, Rule: LintPromptInjection, Message: pattern.message})
+			}
+		}
+	}
+
+	// Tool permissions are only declared in the frontmatter of SKILL.md
+	if path.Base(file) != "SKILL.md" {
+		return findings
+	}
+	metadata, ok, err := ParseSkillFrontmatter(data)
+	if !ok || err != nil {
+		return findings
+	}
+	// Lines of the frontmatter are counted from the line after the opening '---'
+	lineNo := metadata.AllowedTools.Line + 1
+	for _, tool := range metadata.AllowedTools.Tools {
+		for _, pattern := range broadToolPatterns {
+			if pattern.re.MatchString(tool) {
+				findings = append(findings, &LintFinding{File: file, Line: lineNo, Rule: LintBroadPermissions, Message: fmt.Sprintf("%s %s", tool, pattern.message)})
+			}
+		}
+	}
+
+	return findings
+}
+
+// splitAllowedTools splits an allowed-tools value, written as a comma or space separated list
+// or a YAML flow sequence, into tool entries. Commas and spaces inside parentheses are kept.
+func splitAllowedTools(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	var tools []string
+	var current strings.Builder
+	depth := 0
+	flush := func() {
+		if tool := strings.Trim(strings.TrimSpace(current.String()), `"'`); tool != "" {
+			tools = append(tools, tool)
+		}
+		current.Reset()
+	}
+	for _, r := range value {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case (r == ',' || r == ' ') && depth == 0:
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+
+	return tools
+}
+
+// firstHiddenRune returns the first hidden character in s, as reported by hiddenRunes.
+// Such characters hide text from human reviewers while agents still read it.
+func firstHiddenRune(s string) (rune, bool) {
+	if hidden := hiddenRunes(s); len(hidden) > 0 {
+		return hidden[0], true
+	}
+	return 0, false
+}
+
+// IsHiddenRune reports whether r is invisible when rendered or reorders the text around it.
+func IsHiddenRune(r rune) bool {
+	switch {
+	case r >= 0x200B && r <= 0x200F: // Zero-width space, joiners, and directional marks
+		return true
+	case r >= 0x202A && r <= 0x202E: // Bidirectional embeddings and overrides
+		return true
+	case r >= 0x2060 && r <= 0x2064: // Word joiner and invisible operators
+		return true
+	case r >= 0x2066 && r <= 0x2069: // Bidirectional isolates
+		return true
+	case r == 0xFEFF, r == 0x00AD, r == 0x180E: // Byte order mark, soft hyphen, Mongolian vowel separator
+		return true
+	case r >= 0xE0000 && r <= 0xE007F: // Tag characters, used to smuggle ASCII text
+		return true
+	}
+	return false
+}
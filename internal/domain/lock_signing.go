@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// Signing methods accepted by the method key of the [signing] table.
+const (
+	SigningMinisign = "minisign" // Ed25519 signatures compatible with the minisign tool
+	SigningCosign   = "cosign"   // Signatures made and checked by the cosign tool
+)
+
+// attestationHeader is the first line of the content signed for a lock file.
+const attestationHeader = "skills-pkg lock attestation v1\n"
+
+// SigningSettings is the [signing] table of the configuration. With it, the lock file must carry a
+// signature made with the secret key of PublicKey, and installs are limited to the signed versions.
+type SigningSettings struct {
+	Method string `toml:"method,omitempty"` // "minisign" (default) or "cosign"
+	// PublicKey verifies the signatures: a key file relative to the configuration file, the
+	// minisign public key itself, or anything cosign accepts as a key, such as a KMS URI.
+	PublicKey string `toml:"public_key"`
+}
+
+// EffectiveMethod returns the signing method. It returns SigningMinisign when no method is configured.
+func (s *SigningSettings) EffectiveMethod() string {
+	if s.Method == "" {
+		return SigningMinisign
+	}
+	return s.Method
+}
+
+// Validate checks the signing method and that a public key is configured.
+func (s *SigningSettings) Validate() error {
+	switch method := s.EffectiveMethod(); method {
+	case SigningMinisign, SigningCosign:
+	default:
+		return &ErrorInvalidSigning{Reason: fmt.Sprintf("unknown method '%s'. Use \"minisign\" or \"cosign\"", method)}
+	}
+	if s.PublicKey == "" {
+		return &ErrorInvalidSigning{Reason: "public_key is not set"}
+	}
+	return nil
+}
+
+// Attestation returns the content that is signed for the lock file: the source, URL, version, and
+// hash that every skill resolved to, one skill per line in name order. The install targets are left
+// out, as every machine records its own, so installing the signed versions keeps the signature valid.
+// Insecure skills are listed without their version and hash, which may change without a new signature.
+// Remote configurations in extends follow the skills with their hashes.
+func (l *LockFile) Attestation() []byte {
+	skills := slices.Clone(l.Skills)
+	slices.SortFunc(skills, func(a, b *LockedSkill) int { return cmp.Compare(a.Name, b.Name) })
+
+	var b bytes.Buffer
+	b.WriteString(attestationHeader)
+	for _, skill := range skills {
+		if skill.Insecure {
+			fmt.Fprintf(&b, "%s\tinsecure\n", skill.Name)
+			continue
+		}
+		if skill.Version == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%s\n", skill.Name, skill.Source, skill.URL, skill.Version, skill.HashValue)
+	}
+	bases := slices.Clone(l.Bases)
+	slices.SortFunc(bases, func(a, b *LockedBase) int { return cmp.Compare(a.Ref, b.Ref) })
+	for _, base := range bases {
+		fmt.Fprintf(&b, "extends\t%s\t%s\n", base.Ref, base.HashValue)
+	}
+	return b.Bytes()
+}
+
+// LockSigner signs the lock file of a configuration and verifies its signature.
+type LockSigner struct {
+	configPath  string
+	lockManager *LockManager
+	signers     []port.Signer
+}
+
+// NewLockSigner creates a new LockSigner for the lock file of the configuration of configManager,
+// signing with the signer of the configured method.
+func NewLockSigner(configManager *ConfigManager, signers []port.Signer) *LockSigner {
+	return &LockSigner{
+		configPath:  configManager.configPath,
+		lockManager: NewLockManager(LockPathFor(configManager.configPath)),
+		signers:     signers,
+	}
+}
+
+// SignaturePath returns the path of the signature of the lock file for the configured signing method,
+// such as .skillspkg.lock.minisig.
+func (s *LockSigner) SignaturePath(settings *SigningSettings) (string, error) {
+	signer, err := s.signer(settings)
+	if err != nil {
+		return "", err
+	}
+	return LockPathFor(s.configPath) + signer.SignatureExt(), nil
+}
+
+// Sign signs the lock file with the secret key that key refers to and writes the signature next to it.
+// It returns the path of the signature.
+func (s *LockSigner) Sign(ctx context.Context, settings *SigningSettings, key string) (string, error) {
+	if key == "" {
+		return "", errors.New("no signing key is given")
+	}
+	signer, err := s.signer(settings)
+	if err != nil {
+		return "", err
+	}
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := signer.Sign(ctx, lock.Attestation(), key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign lock file: %w", err)
+	}
+
+	path := LockPathFor(s.configPath) + signer.SignatureExt()
+	if err := os.WriteFile(path, signature, configFileMode); err != nil {
+		return "", fmt.Errorf("failed to write lock file signature to %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// Verify checks that the lock file carries a valid signature made with the secret key of the
+// configured public key. It returns ErrorSignatureInvalid when the signature is missing or does not
+// verify, for example because the lock file changed after it was signed.
+func (s *LockSigner) Verify(ctx context.Context, settings *SigningSettings) error {
+	signer, err := s.signer(settings)
+	if err != nil {
+		return err
+	}
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := LockPathFor(s.configPath) + signer.SignatureExt()
+	signature, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &ErrorSignatureInvalid{Path: path, Reason: "the lock file is not signed"}
+		}
+		return fmt.Errorf("failed to read lock file signature at %s: %w", path, err)
+	}
+
+	if err := signer.Verify(ctx, lock.Attestation(), signature, s.publicKey(settings)); err != nil {
+		if errors.Is(err, port.ErrSignatureInvalid) {
+			return &ErrorSignatureInvalid{Path: path, Reason: err.Error()}
+		}
+		return fmt.Errorf("failed to verify lock file signature: %w", err)
+	}
+	return nil
+}
+
+// publicKey returns the configured public key, resolving key files relative to the configuration file.
+func (s *LockSigner) publicKey(settings *SigningSettings) string {
+	if filepath.IsAbs(settings.PublicKey) {
+		return settings.PublicKey
+	}
+	path := filepath.Join(filepath.Dir(s.configPath), settings.PublicKey)
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return settings.PublicKey
+}
+
+func (s *LockSigner) signer(settings *SigningSettings) (port.Signer, error) {
+	if settings == nil {
+		return nil, &ErrorInvalidSigning{Reason: "no [signing] table is configured"}
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	for _, signer := range s.signers {
+		if signer.Method() == settings.EffectiveMethod() {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("signing method %s is not available", settings.EffectiveMethod())
+}
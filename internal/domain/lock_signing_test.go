@@ -0,0 +1,119 @@
+package domain_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// fakeSigner signs messages with a checksum bound to the key name.
+type fakeSigner struct{}
+
+func (fakeSigner) Method() string       { return domain.SigningMinisign }
+func (fakeSigner) SignatureExt() string { return ".minisig" }
+
+func (fakeSigner) Sign(_ context.Context, message []byte, key string) ([]byte, error) {
+	return fmt.Appendf(nil, "%s:%x", key, sha256.Sum256(message)), nil
+}
+
+func (fakeSigner) Verify(_ context.Context, message, signature []byte, publicKey string) error {
+	if !bytes.Equal(signature, fmt.Appendf(nil, "%s:%x", publicKey, sha256.Sum256(message))) {
+		return fmt.Errorf("%w: mismatch", port.ErrSignatureInvalid)
+	}
+	return nil
+}
+
+func TestLockSigner_SignAndVerify(t *testing.T) {
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), domain.ConfigFileName)
+	configManager := domain.NewConfigManager(configPath)
+	lockManager := domain.NewLockManager(domain.LockPathFor(configPath))
+	signer := domain.NewLockSigner(configManager, []port.Signer{fakeSigner{}})
+	settings := &domain.SigningSettings{PublicKey: "team"}
+
+	skill := &domain.Skill{Name: "skill", Source: "git", URL: "https://github.com/example/skill", HashValue: "h1:abc"}
+	if err := lockManager.Update(ctx, func(lock *domain.LockFile) { lock.RecordResolved(skill, "v1.0.0") }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := signer.Verify(ctx, settings); !isSignatureInvalid(err) {
+		t.Errorf("Verify() of an unsigned lock file error = %v, want ErrorSignatureInvalid", err)
+	}
+
+	path, err := signer.Sign(ctx, settings, "team")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if want := domain.LockPathFor(configPath) + ".minisig"; path != want {
+		t.Errorf("Sign() path = %s, want %s", path, want)
+	}
+	if err := signer.Verify(ctx, settings); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	// Installs on other machines record their own targets without invalidating the signature
+	if err := lockManager.Update(ctx, func(lock *domain.LockFile) {
+		lock.RecordInstall("skill", &domain.TargetStatus{Path: "/elsewhere", Version: "v1.0.0", InstalledAt: time.Now()})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Verify(ctx, settings); err != nil {
+		t.Errorf("Verify() after recording an install target error = %v", err)
+	}
+
+	if err := signer.Verify(ctx, &domain.SigningSettings{PublicKey: "someone-else"}); !isSignatureInvalid(err) {
+		t.Errorf("Verify() with another key error = %v, want ErrorSignatureInvalid", err)
+	}
+
+	if err := lockManager.Update(ctx, func(lock *domain.LockFile) { lock.RecordResolved(skill, "v2.0.0") }); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Verify(ctx, settings); !isSignatureInvalid(err) {
+		t.Errorf("Verify() after changing a resolved version error = %v, want ErrorSignatureInvalid", err)
+	}
+
+	// Insecure skills are signed without their version, but marking a skill insecure changes the signed content
+	skill.Insecure = true
+	if err := lockManager.Update(ctx, func(lock *domain.LockFile) { lock.RecordResolved(skill, "v2.0.0") }); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Verify(ctx, settings); !isSignatureInvalid(err) {
+		t.Errorf("Verify() after marking a skill insecure error = %v, want ErrorSignatureInvalid", err)
+	}
+	if _, err := signer.Sign(ctx, settings, "team"); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if err := lockManager.Update(ctx, func(lock *domain.LockFile) { lock.RecordResolved(skill, "v3.0.0") }); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Verify(ctx, settings); err != nil {
+		t.Errorf("Verify() after changing the version of an insecure skill error = %v", err)
+	}
+}
+
+func TestSigningSettings_Validate(t *testing.T) {
+	for _, settings := range []*domain.SigningSettings{
+		{Method: "gpg", PublicKey: "key.pub"},
+		{Method: domain.SigningCosign},
+	} {
+		if _, ok := errors.AsType[*domain.ErrorInvalidSigning](settings.Validate()); !ok {
+			t.Errorf("Validate(%+v) = %v, want ErrorInvalidSigning", settings, settings.Validate())
+		}
+	}
+	if err := (&domain.SigningSettings{PublicKey: "key.pub"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func isSignatureInvalid(err error) bool {
+	_, ok := errors.AsType[*domain.ErrorSignatureInvalid](err)
+	return ok
+}
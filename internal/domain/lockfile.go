@@ -0,0 +1,320 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// LockFileName is the name of the lock file written next to .skillspkg.toml.
+const LockFileName = ".skillspkg.lock"
+
+// lockFileVersion is the format version written to new lock files.
+const lockFileVersion = 1
+
+// LockFile records where each skill has been installed, and the version, hash, and source each skill
+// resolved to when it was last installed. Unlike .skillspkg.toml, it describes the state of the install
+// targets on this machine and is updated by install, update, and uninstall. 'install --frozen' refuses
+// to install anything but the resolved versions, so committing it gives reproducible installs.
+type LockFile struct {
+	Skills  []*LockedSkill `toml:"skills"`
+	Bases   []*LockedBase  `toml:"bases,omitempty"`
+	Version int            `toml:"version"`
+}
+
+// LockedBase records the content hash of a remote configuration in extends when it was first loaded,
+// so that a version moved to other content, such as a retagged tag, is noticed.
+type LockedBase struct {
+	Ref       string `toml:"ref"`        // The entry of extends, <repository>@<version>
+	HashValue string `toml:"hash_value"` // Hash of the files of the repository at the version
+}
+
+// LockedSkill holds the resolved source and the install state of a single skill.
+type LockedSkill struct {
+	Name      string          `toml:"name"`
+	Source    string          `toml:"source,omitempty"`     // Source type the skill was installed from
+	URL       string          `toml:"url,omitempty"`        // Source URL as written in .skillspkg.toml
+	Version   string          `toml:"version,omitempty"`    // Version the skill resolved to
+	HashValue string          `toml:"hash_value,omitempty"` // Hash of the downloaded skill; empty for versions from go.mod, which go.sum verifies
+	Insecure  bool            `toml:"insecure,omitempty"`   // The skill is exempt from the integrity policies; its version and hash are not signed
+	Targets   []*TargetStatus `toml:"targets"`
+}
+
+// TargetStatus records a skill installation in a single install target.
+type TargetStatus struct {
+	InstalledAt time.Time `toml:"installed_at"`         // When the skill was last copied to the target
+	Path        string    `toml:"path"`                 // Install target as written in .skillspkg.toml
+	Version     string    `toml:"version,omitempty"`    // Resolved version that was installed
+	HashValue   string    `toml:"hash_value,omitempty"` // Hash of the installed files
+	Dir         string    `toml:"dir,omitempty"`        // Directory name of the skill in the target, when it differs from the skill name
+	// Incompatible is why the skill was not installed into the target, when the agent of the
+	// target is not among the agents the skill supports.
+	Incompatible string `toml:"incompatible,omitempty"`
+}
+
+// DirName returns the name of the directory the skill was installed into.
+func (s *TargetStatus) DirName(skillName string) string {
+	if s.Dir != "" {
+		return s.Dir
+	}
+	return skillName
+}
+
+// FindSkill returns the locked entry for the skill, or nil if it has none.
+func (l *LockFile) FindSkill(name string) *LockedSkill {
+	for _, skill := range l.Skills {
+		if skill.Name == name {
+			return skill
+		}
+	}
+	return nil
+}
+
+// ResolutionMismatch returns why the entry does not record a resolution of the configured skill, or an
+// empty string when it does: the skill must have the recorded source and URL, and a pinned version must
+// be the resolved one. Skills without a version, such as those resolved from go.mod, match any version.
+// Entries written by older versions without a resolved version are compared with the installed versions.
+func (s *LockedSkill) ResolutionMismatch(skill *Skill) string {
+	switch {
+	case s.Source != "" && s.Source != skill.Source:
+		return fmt.Sprintf("source %s is configured, but the lock file has %s", skill.Source, s.Source)
+	// Encrypted URLs change whenever they are re-encrypted, so only plaintext URLs are compared
+	case s.URL != "" && s.URL != skill.URL && !IsEncrypted(s.URL) && !IsEncrypted(skill.URL):
+		return fmt.Sprintf("URL %s is configured, but the lock file has %s", skill.URL, s.URL)
+	case skill.Version == "":
+		return ""
+	case s.Version != "":
+		if s.Version != skill.Version {
+			return fmt.Sprintf("version %s is configured, but the lock file has %s", skill.Version, s.Version)
+		}
+		return ""
+	}
+	for _, target := range s.Targets {
+		if target.Version != "" && target.Version != skill.Version {
+			return fmt.Sprintf("version %s is configured, but %s is installed in %s", skill.Version, target.Version, target.Path)
+		}
+	}
+	return ""
+}
+
+// TargetStatus returns the status recorded for the install target, or nil if it has none.
+// It is safe to call on a nil LockedSkill.
+func (s *LockedSkill) TargetStatus(target string) *TargetStatus {
+	if s == nil {
+		return nil
+	}
+	for _, status := range s.Targets {
+		if status.Path == target {
+			return status
+		}
+	}
+	return nil
+}
+
+// FindBase returns the recorded remote configuration of extends, or nil when it is not recorded.
+func (l *LockFile) FindBase(ref string) *LockedBase {
+	for _, base := range l.Bases {
+		if base.Ref == ref {
+			return base
+		}
+	}
+	return nil
+}
+
+// RecordInstall stores the status of a skill installation, replacing any previous
+// status for the same skill and install target.
+func (l *LockFile) RecordInstall(skillName string, status *TargetStatus) {
+	skill := l.FindSkill(skillName)
+	if skill == nil {
+		skill = &LockedSkill{Name: skillName}
+		l.Skills = append(l.Skills, skill)
+	}
+
+	skill.Targets = slices.DeleteFunc(skill.Targets, func(s *TargetStatus) bool {
+		return s.Path == status.Path
+	})
+	skill.Targets = append(skill.Targets, status)
+}
+
+// RecordResolved stores the source, version, and hash the skill resolved to.
+func (l *LockFile) RecordResolved(skill *Skill, version string) {
+	locked := l.FindSkill(skill.Name)
+	if locked == nil {
+		locked = &LockedSkill{Name: skill.Name}
+		l.Skills = append(l.Skills, locked)
+	}
+
+	locked.Source = skill.Source
+	locked.URL = skill.URL
+	locked.Version = version
+	locked.HashValue = skill.HashValue
+	locked.Insecure = skill.Insecure
+}
+
+// RemoveInstall deletes the recorded installation of the skill in the install target,
+// and the skill's entry once no installations remain.
+func (l *LockFile) RemoveInstall(skillName, target string) {
+	skill := l.FindSkill(skillName)
+	if skill == nil {
+		return
+	}
+
+	skill.Targets = slices.DeleteFunc(skill.Targets, func(s *TargetStatus) bool {
+		return s.Path == target
+	})
+	if len(skill.Targets) == 0 {
+		l.RemoveSkill(skillName)
+	}
+}
+
+// RemoveSkill deletes all recorded installations of the skill.
+func (l *LockFile) RemoveSkill(skillName string) {
+	l.Skills = slices.DeleteFunc(l.Skills, func(s *LockedSkill) bool {
+		return s.Name == skillName
+	})
+}
+
+// LockManager reads and writes the lock file.
+// Updates are serialized so that skills installed concurrently do not overwrite each other's entries.
+type LockManager struct {
+	lockPath string
+	mu       sync.Mutex
+}
+
+// NewLockManager creates a new LockManager for the lock file at lockPath.
+func NewLockManager(lockPath string) *LockManager {
+	return &LockManager{lockPath: lockPath}
+}
+
+// LockPathFor returns the path of the lock file belonging to the configuration file at configPath.
+// Configuration files with another name than .skillspkg.toml, such as profiles, have a lock file of
+// the same name with the .lock extension, so that configurations in the same directory do not share one.
+func LockPathFor(configPath string) string {
+	dir, name := filepath.Split(configPath)
+	if name == ConfigFileName {
+		return filepath.Join(dir, LockFileName)
+	}
+	return filepath.Join(dir, strings.TrimSuffix(name, filepath.Ext(name))+".lock")
+}
+
+// Load reads the lock file. A missing lock file is treated as empty.
+func (m *LockManager) Load(ctx context.Context) (*LockFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.load()
+}
+
+// Update loads the lock file, applies fn, and writes the result back.
+func (m *LockManager) Update(ctx context.Context, fn func(lock *LockFile)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	fn(lock)
+
+	return m.save(lock)
+}
+
+func (m *LockManager) load() (*LockFile, error) {
+	data, err := os.ReadFile(m.lockPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &LockFile{Version: lockFileVersion}, nil
+		}
+		return nil, fmt.Errorf("failed to read lock file at %s: %w. Check file permissions", m.lockPath, err)
+	}
+
+	var lock LockFile
+	if err := toml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file at %s: %w. Delete it and run 'skills-pkg install' to recreate it", m.lockPath, err)
+	}
+
+	return &lock, nil
+}
+
+func (m *LockManager) save(lock *LockFile) error {
+	lock.Version = lockFileVersion
+
+	data, err := toml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	if err := os.WriteFile(m.lockPath, data, configFileMode); err != nil {
+		return fmt.Errorf("failed to write lock file to %s: %w. Check file permissions and directory existence", m.lockPath, err)
+	}
+
+	return nil
+}
+
+// TargetFreshness describes whether the copy of a skill in an install target
+// matches the configuration.
+type TargetFreshness string
+
+const (
+	TargetUpToDate     TargetFreshness = "up-to-date"
+	TargetOutdated     TargetFreshness = "outdated"
+	TargetModified     TargetFreshness = "modified"
+	TargetNotInstalled TargetFreshness = "not-installed"
+	TargetIncompatible TargetFreshness = "incompatible" // Left out because the agent of the target does not support the skill
+)
+
+// CheckTargetFreshness compares the recorded installation of a skill in an install target
+// with the skill's configured version and, for local targets, the files on disk.
+func CheckTargetFreshness(ctx context.Context, hashService port.HashService, config *Config, skill *Skill, status *TargetStatus) (TargetFreshness, error) {
+	if status == nil {
+		return TargetNotInstalled, nil
+	}
+	skill = skill.ForTarget(status.Path)
+
+	if skill.Version != "" && status.Version != skill.Version {
+		return TargetOutdated, nil
+	}
+	if skill.HashValue != "" && status.HashValue != skill.HashValue {
+		return TargetOutdated, nil
+	}
+	if status.DirName(skill.Name) != skill.DirName() {
+		return TargetOutdated, nil
+	}
+	if status.Incompatible != "" {
+		return TargetIncompatible, nil
+	}
+
+	// Remote targets cannot be hashed locally, so the recorded state is trusted
+	if IsRemoteTarget(status.Path) || status.HashValue == "" {
+		return TargetUpToDate, nil
+	}
+
+	skillDir := filepath.Join(status.Path, skill.DirName())
+	if _, err := os.Stat(skillDir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return TargetNotInstalled, nil
+		}
+		return "", fmt.Errorf("failed to access %s: %w", skillDir, err)
+	}
+
+	hashResult, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmOf(status.HashValue), config.HashOptions(skill)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate hash for %s: %w", skillDir, err)
+	}
+	if hashResult.Value != status.HashValue {
+		return TargetModified, nil
+	}
+
+	return TargetUpToDate, nil
+}
@@ -0,0 +1,207 @@
+package domain_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestLockManager_UpdateAndLoad(t *testing.T) {
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), domain.LockFileName)
+	lockManager := domain.NewLockManager(lockPath)
+
+	// A missing lock file is treated as empty
+	lock, err := lockManager.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(lock.Skills) != 0 {
+		t.Fatalf("Load() skills = %d, want 0", len(lock.Skills))
+	}
+
+	installedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	err = lockManager.Update(ctx, func(lock *domain.LockFile) {
+		lock.RecordInstall("skill-a", &domain.TargetStatus{Path: "/a", Version: "v1.0.0", HashValue: "h1:old", InstalledAt: installedAt})
+		lock.RecordInstall("skill-a", &domain.TargetStatus{Path: "/b", Version: "v1.0.0", HashValue: "h1:old", InstalledAt: installedAt})
+		lock.RecordInstall("skill-a", &domain.TargetStatus{Path: "/a", Version: "v1.1.0", HashValue: "h1:new", InstalledAt: installedAt})
+		lock.RecordInstall("skill-b", &domain.TargetStatus{Path: "/a", Version: "v2.0.0", InstalledAt: installedAt})
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	err = lockManager.Update(ctx, func(lock *domain.LockFile) {
+		lock.RemoveSkill("skill-b")
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	lock, err = lockManager.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if lock.FindSkill("skill-b") != nil {
+		t.Error("skill-b should have been removed from the lock file")
+	}
+
+	locked := lock.FindSkill("skill-a")
+	if locked == nil {
+		t.Fatal("skill-a not found in lock file")
+	}
+	if len(locked.Targets) != 2 {
+		t.Fatalf("skill-a targets = %d, want 2", len(locked.Targets))
+	}
+
+	status := locked.TargetStatus("/a")
+	if status == nil {
+		t.Fatal("status for /a not found")
+	}
+	if status.Version != "v1.1.0" || status.HashValue != "h1:new" {
+		t.Errorf("status for /a = %+v, want the latest install", status)
+	}
+	if !status.InstalledAt.Equal(installedAt) {
+		t.Errorf("InstalledAt = %v, want %v", status.InstalledAt, installedAt)
+	}
+
+	if got := lock.FindSkill("missing").TargetStatus("/a"); got != nil {
+		t.Errorf("TargetStatus() on missing skill = %+v, want nil", got)
+	}
+}
+
+func TestLockFile_RemoveInstall(t *testing.T) {
+	lock := &domain.LockFile{}
+	lock.RecordInstall("skill-a", &domain.TargetStatus{Path: "/a"})
+	lock.RecordInstall("skill-a", &domain.TargetStatus{Path: "/b"})
+
+	lock.RemoveInstall("skill-a", "/a")
+	if got := lock.FindSkill("skill-a"); got == nil || len(got.Targets) != 1 || got.Targets[0].Path != "/b" {
+		t.Fatalf("after removing /a, skill-a = %+v, want only /b", got)
+	}
+
+	lock.RemoveInstall("skill-a", "/b")
+	if lock.FindSkill("skill-a") != nil {
+		t.Error("skill-a should be removed once it has no installations")
+	}
+
+	// Removing unknown installations is a no-op
+	lock.RemoveInstall("missing", "/a")
+}
+
+func TestLockPathFor(t *testing.T) {
+	got := domain.LockPathFor(filepath.Join("project", ".skillspkg.toml"))
+	want := filepath.Join("project", domain.LockFileName)
+	if got != want {
+		t.Errorf("LockPathFor() = %s, want %s", got, want)
+	}
+}
+
+func TestCheckTargetFreshness(t *testing.T) {
+	ctx := context.Background()
+	hashService := service.NewDirhash()
+
+	tests := []struct {
+		skill  *domain.Skill
+		status func(target, hash string) *domain.TargetStatus
+		modify func(t *testing.T, skillDir string)
+		name   string
+		want   domain.TargetFreshness
+	}{
+		{
+			name:  "unchanged installation is up to date",
+			skill: &domain.Skill{Name: "my-skill", Version: "v1.0.0"},
+			status: func(target, hash string) *domain.TargetStatus {
+				return &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: hash}
+			},
+			want: domain.TargetUpToDate,
+		},
+		{
+			name:  "no recorded installation",
+			skill: &domain.Skill{Name: "my-skill", Version: "v1.0.0"},
+			status: func(string, string) *domain.TargetStatus {
+				return nil
+			},
+			want: domain.TargetNotInstalled,
+		},
+		{
+			name:  "different version is outdated",
+			skill: &domain.Skill{Name: "my-skill", Version: "v2.0.0"},
+			status: func(target, hash string) *domain.TargetStatus {
+				return &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: hash}
+			},
+			want: domain.TargetOutdated,
+		},
+		{
+			name:  "edited files are modified",
+			skill: &domain.Skill{Name: "my-skill", Version: "v1.0.0"},
+			status: func(target, hash string) *domain.TargetStatus {
+				return &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: hash}
+			},
+			modify: func(t *testing.T, skillDir string) {
+				t.Helper()
+				if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("edited"), 0o644); err != nil {
+					t.Fatalf("failed to modify file: %v", err)
+				}
+			},
+			want: domain.TargetModified,
+		},
+		{
+			name:  "deleted skill directory is not installed",
+			skill: &domain.Skill{Name: "my-skill", Version: "v1.0.0"},
+			status: func(target, hash string) *domain.TargetStatus {
+				return &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: hash}
+			},
+			modify: func(t *testing.T, skillDir string) {
+				t.Helper()
+				if err := os.RemoveAll(skillDir); err != nil {
+					t.Fatalf("failed to remove skill: %v", err)
+				}
+			},
+			want: domain.TargetNotInstalled,
+		},
+		{
+			name:  "remote target trusts the recorded version",
+			skill: &domain.Skill{Name: "my-skill", Version: "v1.0.0"},
+			status: func(_, hash string) *domain.TargetStatus {
+				return &domain.TargetStatus{Path: "ssh://host/skills", Version: "v1.0.0", HashValue: hash}
+			},
+			want: domain.TargetUpToDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := t.TempDir()
+			skillDir := filepath.Join(target, tt.skill.Name)
+			if err := os.MkdirAll(skillDir, 0o755); err != nil {
+				t.Fatalf("failed to create skill directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("skill"), 0o644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			hash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
+			if err != nil {
+				t.Fatalf("failed to calculate hash: %v", err)
+			}
+
+			if tt.modify != nil {
+				tt.modify(t, skillDir)
+			}
+
+			got, err := domain.CheckTargetFreshness(ctx, hashService, &domain.Config{}, tt.skill, tt.status(target, hash.Value))
+			if err != nil {
+				t.Fatalf("CheckTargetFreshness() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckTargetFreshness() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// MirrorIndexFileName is the name of the file that lists the skills in a mirror.
+const MirrorIndexFileName = "skills-pkg-mirror.toml"
+
+// MirroredSkill is a version of a skill that Mirror wrote to a mirror, or could not.
+type MirroredSkill struct {
+	SkillName string
+	Version   string
+	Path      string // Directory of the version in the mirror; empty when it was not mirrored
+	Reason    string // Why the version was not mirrored; empty when it was
+	Existing  bool   // The mirror already had the version, so it was not downloaded again
+}
+
+// MirrorIndex is the content of the index file of a mirror. It lists every version in the mirror,
+// including those of other projects mirrored into the same directory.
+type MirrorIndex struct {
+	Skills  []*MirrorEntry `toml:"skills"`
+	Version int            `toml:"version"`
+}
+
+// MirrorEntry is a version of a skill in a mirror.
+type MirrorEntry struct {
+	Name    string `toml:"name"`
+	Source  string `toml:"source"`
+	URL     string `toml:"url"` // As configured, so encrypted URLs stay encrypted
+	Version string `toml:"version"`
+	Dir     string `toml:"dir"` // Directory of the downloaded files, relative to the mirror
+}
+
+// Mirror downloads the configured version, the canary version, and the version recorded in the lock
+// file of every skill into dir, in the layout of the download cache. Installs that read it with
+// WithMirror need no access to the sources. Versions that are already in the mirror are kept.
+func (s *skillManagerImpl) Mirror(ctx context.Context, dir string) ([]*MirroredSkill, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := loadMirrorIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror := NewDownloadCache(dir)
+	var mirrored []*MirroredSkill
+	for _, skill := range config.Skills {
+		versions := mirrorVersions(config, skill, lock)
+		if len(versions) == 0 {
+			mirrored = append(mirrored, &MirroredSkill{SkillName: skill.Name, Reason: "no pinned version; run 'skills-pkg install' to record the version it resolves to in .skillspkg.lock"})
+			continue
+		}
+
+		pm, err := s.selectPackageManager(skill.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select package manager for skill '%s': %w", skill.Name, err)
+		}
+		source, err := config.SourceOf(skill)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, version := range versions {
+			entry := &MirroredSkill{SkillName: skill.Name, Version: version}
+			mirrored = append(mirrored, entry)
+
+			if existing, ok := mirror.Get(source, version); ok {
+				entry.Path, entry.Existing = existing.Path, true
+			} else {
+				s.emit(ctx, skill.Name, PhaseDownload, "", "Downloading skill '%s' at %s...", skill.Name, version)
+				result, err := s.download(ctx, pm, source, version)
+				if err != nil {
+					return nil, fmt.Errorf("failed to download skill '%s' at %s: %w. Check your network connection and source URL", skill.Name, version, err)
+				}
+				stored, err := mirror.Put(source, version, result)
+				if err != nil {
+					return nil, err
+				}
+				if stored.Path == result.Path {
+					// Branches resolve to another commit over time, so installs download them again
+					entry.Reason = fmt.Sprintf("%s resolved to %s; install with --frozen to use the commit recorded in .skillspkg.lock", version, result.Version)
+					continue
+				}
+				entry.Path = stored.Path
+			}
+
+			index.record(&MirrorEntry{Name: skill.Name, Source: skill.Source, URL: config.Sealed(skill.URL), Version: version, Dir: filepath.Base(entry.Path)})
+		}
+	}
+
+	if err := saveMirrorIndex(dir, index); err != nil {
+		return nil, err
+	}
+	return mirrored, nil
+}
+
+// mirrorVersions returns the versions of the skill that installs download: the configured version, the
+// canary version, and the version recorded in the lock file while the source is unchanged.
+func mirrorVersions(config *Config, skill *Skill, lock *LockFile) []string {
+	var versions []string
+	add := func(version string) {
+		if cacheableVersion(version) && !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+
+	add(skill.Version)
+	if skill.Canary != nil {
+		add(skill.Canary.Version)
+	}
+	if locked := lock.FindSkill(skill.Name); locked != nil && locked.Source == skill.Source && locked.URL == config.Sealed(skill.URL) {
+		add(locked.Version)
+	}
+	return versions
+}
+
+// record adds the entry to the index, replacing an entry for the same source and version.
+func (i *MirrorIndex) record(entry *MirrorEntry) {
+	i.Skills = slices.DeleteFunc(i.Skills, func(e *MirrorEntry) bool {
+		return e.Dir == entry.Dir && e.Name == entry.Name
+	})
+	i.Skills = append(i.Skills, entry)
+}
+
+func loadMirrorIndex(dir string) (*MirrorIndex, error) {
+	path := filepath.Join(dir, MirrorIndexFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &MirrorIndex{Version: 1}, nil
+		}
+		return nil, fmt.Errorf("failed to read mirror index at %s: %w", path, err)
+	}
+
+	var index MirrorIndex
+	if err := toml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse mirror index at %s: %w", path, err)
+	}
+	return &index, nil
+}
+
+func saveMirrorIndex(dir string, index *MirrorIndex) error {
+	slices.SortFunc(index.Skills, func(a, b *MirrorEntry) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+
+	data, err := toml.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to encode mirror index: %w", err)
+	}
+	if err := os.MkdirAll(dir, installDirMode); err != nil {
+		return fmt.Errorf("failed to create mirror directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, MirrorIndexFileName)
+	if err := os.WriteFile(path, data, configFileMode); err != nil {
+		return fmt.Errorf("failed to write mirror index to %s: %w", path, err)
+	}
+	return nil
+}
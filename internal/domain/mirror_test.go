@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestMirror(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+	mirrorDir := filepath.Join(tmpDir, "mirror")
+	downloadDir := filepath.Join(tmpDir, "download")
+	if err := os.MkdirAll(filepath.Join(downloadDir, "skills", "pinned"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(downloadDir, "skills", "pinned", "SKILL.md"), []byte("# pinned\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	configManager := NewConfigManager(configPath)
+	if err := configManager.Save(ctx, &Config{
+		Skills: []*Skill{
+			{Name: "pinned", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0", SubDir: "skills/pinned",
+				Canary: &SkillCanary{Version: "v1.1.0", Target: filepath.Join(tmpDir, "install")}},
+			{Name: "locked", Source: "git", URL: "https://github.com/example/locked.git"},
+			{Name: "unresolved", Source: "git", URL: "https://github.com/example/unresolved.git"},
+		},
+		InstallTargets: []string{filepath.Join(tmpDir, "install")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewLockManager(LockPathFor(configPath)).Update(ctx, func(lock *LockFile) {
+		lock.RecordResolved(&Skill{Name: "locked", Source: "git", URL: "https://github.com/example/locked.git"}, "0123abcd")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := &mockPackageManagerWithDownload{sourceType: "git", downloadResult: &port.DownloadResult{Path: downloadDir}}
+	versionedPM := &versionedPackageManager{mockPackageManagerWithDownload: pm}
+	skillManager := NewSkillManager(configManager, &mockHashService{}, []port.PackageManager{versionedPM})
+
+	mirrored, err := skillManager.Mirror(ctx, mirrorDir)
+	if err != nil {
+		t.Fatalf("Mirror() error = %v", err)
+	}
+
+	var got []string
+	for _, m := range mirrored {
+		if m.Reason != "" {
+			got = append(got, m.SkillName+" skipped")
+			continue
+		}
+		got = append(got, m.SkillName+"@"+m.Version)
+		if _, err := os.Stat(m.Path); err != nil {
+			t.Errorf("mirrored %s@%s is missing: %v", m.SkillName, m.Version, err)
+		}
+	}
+	want := []string{"pinned@v1.0.0", "pinned@v1.1.0", "locked@0123abcd", "unresolved skipped"}
+	if len(got) != len(want) {
+		t.Fatalf("Mirror() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Mirror()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	index, err := loadMirrorIndex(mirrorDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Skills) != 3 {
+		t.Errorf("mirror index has %d entries, want 3", len(index.Skills))
+	}
+
+	// Mirroring again keeps what is in the mirror
+	if mirrored, err = skillManager.Mirror(ctx, mirrorDir); err != nil {
+		t.Fatalf("Mirror() again error = %v", err)
+	}
+	if !mirrored[0].Existing || pm.downloads.Load() != 3 {
+		t.Errorf("Mirror() again downloaded versions in the mirror (%d downloads)", pm.downloads.Load())
+	}
+
+	// Installing from the mirror needs no download
+	offline := &mockPackageManagerWithDownload{sourceType: "git", downloadError: errors.New("network is unreachable")}
+	if err := configManager.Save(ctx, &Config{
+		Skills:         []*Skill{{Name: "pinned", Source: "git", URL: "https://github.com/example/skills.git", Version: "v1.0.0", SubDir: "skills/pinned"}},
+		InstallTargets: []string{filepath.Join(tmpDir, "install")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	skillManager = NewSkillManager(configManager, &mockHashService{}, []port.PackageManager{offline}, WithMirror(mirrorDir))
+	if err := skillManager.Install(ctx, ""); err != nil {
+		t.Fatalf("Install() from the mirror error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "install", "pinned", "SKILL.md")); err != nil {
+		t.Errorf("skill installed from the mirror is missing: %v", err)
+	}
+}
+
+// versionedPackageManager downloads the requested version.
+type versionedPackageManager struct {
+	*mockPackageManagerWithDownload
+}
+
+func (m *versionedPackageManager) Download(ctx context.Context, source *port.Source, version string) (*port.DownloadResult, error) {
+	result, err := m.mockPackageManagerWithDownload.Download(ctx, source, version)
+	if err != nil {
+		return nil, err
+	}
+	return &port.DownloadResult{Path: result.Path, Version: version}, nil
+}
@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NetworkSettings configures the downloads of every source type.
+type NetworkSettings struct {
+	LimitRate string `toml:"limit_rate,omitempty"` // Bandwidth of all downloads together, such as "500K" or "2M" bytes per second
+	// AllowedHosts are the hosts downloads may always connect to: host names, "*." wildcards
+	// for the subdomains of a domain, IP addresses, and CIDR ranges of IPv4 or IPv6 addresses.
+	AllowedHosts []string `toml:"allowed_hosts,omitempty"`
+	// DeniedHosts are the hosts downloads must not connect to, unless they are allowed, in the same forms.
+	DeniedHosts []string `toml:"denied_hosts,omitempty"`
+	// DenyByDefault refuses every host that is not allowed.
+	DenyByDefault bool `toml:"deny_by_default,omitempty"`
+	// UserAgent replaces the User-Agent header of every request, "skills-pkg/<version>" by default.
+	UserAgent string `toml:"user_agent,omitempty"`
+	// Headers are added to the requests that do not set them themselves, for proxies and private
+	// registries that route or audit requests by header. They are keyed by the host names, "*."
+	// wildcards, or URL prefixes of the requests they are added to, so other hosts never see them.
+	Headers map[string]map[string]string `toml:"headers,omitempty"`
+}
+
+// ValidateHeaders checks that user_agent and the names and values of headers can be sent in requests.
+func (s *NetworkSettings) ValidateHeaders() error {
+	if !isHeaderValue(s.UserAgent) {
+		return fmt.Errorf("invalid user_agent %q: it must not contain line breaks or control characters", s.UserAgent)
+	}
+	for match, headers := range s.Headers {
+		if err := validateHeaderMatch(match); err != nil {
+			return err
+		}
+		for name, value := range headers {
+			if !isHeaderName(name) {
+				return fmt.Errorf("invalid headers entry %q of %q: not a valid header name", name, match)
+			}
+			if strings.EqualFold(name, "User-Agent") {
+				return fmt.Errorf("invalid headers entry \"User-Agent\" of %q: set user_agent instead", match)
+			}
+			if !isHeaderValue(value) {
+				return fmt.Errorf("invalid value of headers entry %q of %q: it must not contain line breaks or control characters", name, match)
+			}
+		}
+	}
+	return nil
+}
+
+// validateHeaderMatch checks that a key of headers is a host name, a "*." wildcard, or an http or https URL prefix.
+func validateHeaderMatch(match string) error {
+	if strings.Contains(match, "://") {
+		if u, err := url.Parse(match); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid headers key %q: URL prefixes must be http or https URLs with a host", match)
+		}
+		return nil
+	}
+	host := strings.TrimPrefix(match, "*.")
+	if host == "" || strings.ContainsAny(host, "/*:@ ") {
+		return fmt.Errorf("invalid headers key %q: use a host name, such as \"registry.example.com\", a wildcard, such as \"*.example.com\", or a URL prefix", match)
+	}
+	return nil
+}
+
+// isHeaderName reports whether s is a token, which header names are made of.
+func isHeaderName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && !strings.ContainsRune("!#$%&'*+-.^_`|~", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHeaderValue reports whether s has no control characters other than tabs.
+func isHeaderValue(s string) bool {
+	for _, r := range s {
+		if (r < ' ' && r != '\t') || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// Policy returns the network policy of the settings, or nil when they restrict no host.
+func (s *NetworkSettings) Policy() (*NetworkPolicy, error) {
+	if len(s.AllowedHosts) == 0 && len(s.DeniedHosts) == 0 && !s.DenyByDefault {
+		return nil, nil
+	}
+
+	allowed, err := parseHostRules("allowed_hosts", s.AllowedHosts)
+	if err != nil {
+		return nil, err
+	}
+	denied, err := parseHostRules("denied_hosts", s.DeniedHosts)
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkPolicy{allowed: allowed, denied: denied, denyByDefault: s.DenyByDefault}, nil
+}
+
+// NetworkPolicy decides which hosts downloads may connect to. Allowed hosts are checked first,
+// then denied hosts; other hosts are refused only with deny_by_default.
+type NetworkPolicy struct {
+	allowed       hostRules
+	denied        hostRules
+	denyByDefault bool
+}
+
+// MatchesAddresses reports whether the policy has address ranges, so that the addresses of host
+// names must be resolved to check them.
+func (p *NetworkPolicy) MatchesAddresses() bool {
+	return len(p.allowed.prefixes) > 0 || len(p.denied.prefixes) > 0
+}
+
+// Check returns an ErrorHostNotAllowed when the policy refuses connecting to host, which resolves to addrs.
+// A host is allowed by its address ranges only when all of its addresses are in them.
+func (p *NetworkPolicy) Check(host string, addrs []netip.Addr) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if p.allowed.matchName(host) || p.allowed.containsAll(addrs) {
+		return nil
+	}
+	if p.denied.matchName(host) || p.denied.containsAny(addrs) {
+		return &ErrorHostNotAllowed{Host: host, Reason: "it is in denied_hosts"}
+	}
+	if p.denyByDefault {
+		return &ErrorHostNotAllowed{Host: host, Reason: "deny_by_default is set and it is not in allowed_hosts"}
+	}
+	return nil
+}
+
+// hostRules are the host names, wildcards, and address ranges of allowed_hosts or denied_hosts.
+type hostRules struct {
+	names    []string // Lowercase host names, or "*.<domain>" for the subdomains of a domain
+	prefixes []netip.Prefix
+}
+
+// parseHostRules parses the entries of the setting key.
+func parseHostRules(key string, entries []string) (hostRules, error) {
+	var rules hostRules
+	for _, entry := range entries {
+		value := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(entry)), ".")
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			rules.prefixes = append(rules.prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(strings.Trim(value, "[]")); err == nil {
+			addr = addr.Unmap()
+			rules.prefixes = append(rules.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		if !isHostPattern(value) {
+			return hostRules{}, fmt.Errorf("invalid %s entry %q: expected a host name such as github.com, a wildcard such as *.example.com, an IP address, or a CIDR range such as 10.0.0.0/8", key, entry)
+		}
+		rules.names = append(rules.names, value)
+	}
+	return rules, nil
+}
+
+// isHostPattern reports whether s is a host name, optionally starting with the "*." wildcard.
+func isHostPattern(s string) bool {
+	name := strings.TrimPrefix(s, "*.")
+	if name == "" {
+		return false
+	}
+	for label := range strings.SplitSeq(name, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchName reports whether host is one of the names, or a subdomain of a wildcard.
+func (r hostRules) matchName(host string) bool {
+	for _, name := range r.names {
+		if domain, ok := strings.CutPrefix(name, "*"); ok {
+			if strings.HasSuffix(host, domain) {
+				return true
+			}
+		} else if host == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll reports whether there are addresses and all of them are in the ranges.
+func (r hostRules) containsAll(addrs []netip.Addr) bool {
+	if len(addrs) == 0 || len(r.prefixes) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if !r.contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAny reports whether any of the addresses is in the ranges.
+func (r hostRules) containsAny(addrs []netip.Addr) bool {
+	for _, addr := range addrs {
+		if r.contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r hostRules) contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range r.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// byteRateUnits are the multipliers of the suffixes of ParseByteRate, in the units of curl --limit-rate.
+var byteRateUnits = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+}
+
+// ParseByteRate parses a bandwidth in bytes per second, such as "100000", "500K", "1.5M", or "1G".
+// The suffixes are case-insensitive and binary, like those of curl --limit-rate.
+func ParseByteRate(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+	value = strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(value), "/S"), "B")
+
+	unit := ""
+	if n := len(value); n > 0 && (value[n-1] < '0' || value[n-1] > '9') && value[n-1] != '.' {
+		value, unit = value[:n-1], value[n-1:]
+	}
+	multiplier, ok := byteRateUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid rate %q: unknown unit %q, use K, M, or G", s, unit)
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: expected a positive number of bytes per second, such as 500K or 2M", s)
+	}
+	return int64(n * multiplier), nil
+}
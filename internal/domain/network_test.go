@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestParseByteRate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "100000", want: 100000},
+		{input: "500K", want: 500 << 10},
+		{input: "500k", want: 500 << 10},
+		{input: "1.5M", want: 3 << 19},
+		{input: "2MB/s", want: 2 << 20},
+		{input: "1G", want: 1 << 30},
+		{input: "", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "-1M", wantErr: true},
+		{input: "10T", wantErr: true},
+		{input: "fast", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseByteRate(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseByteRate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseByteRate(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkSettings_Policy(t *testing.T) {
+	settings := &domain.NetworkSettings{
+		AllowedHosts:  []string{"github.com", "*.githubusercontent.com", "10.1.0.0/16", "2001:db8::/32"},
+		DeniedHosts:   []string{"10.0.0.0/8", "blocked.example.com"},
+		DenyByDefault: true,
+	}
+	policy, err := settings.Policy()
+	if err != nil {
+		t.Fatalf("Policy() error = %v", err)
+	}
+
+	tests := []struct {
+		host    string
+		addrs   []netip.Addr
+		allowed bool
+	}{
+		{host: "github.com", allowed: true},
+		{host: "GitHub.com.", allowed: true},
+		{host: "raw.githubusercontent.com", allowed: true},
+		{host: "githubusercontent.com", allowed: false},
+		{host: "git.internal", addrs: []netip.Addr{netip.MustParseAddr("10.1.2.3")}, allowed: true},
+		{host: "git.internal", addrs: []netip.Addr{netip.MustParseAddr("10.1.2.3"), netip.MustParseAddr("10.2.0.1")}, allowed: false},
+		{host: "2001:db8::1", addrs: []netip.Addr{netip.MustParseAddr("2001:db8::1")}, allowed: true},
+		{host: "10.2.0.1", addrs: []netip.Addr{netip.MustParseAddr("10.2.0.1")}, allowed: false},
+		{host: "blocked.example.com", allowed: false},
+		{host: "registry.npmjs.org", allowed: false},
+	}
+	for _, tt := range tests {
+		err := policy.Check(tt.host, tt.addrs)
+		if _, denied := errors.AsType[*domain.ErrorHostNotAllowed](err); denied == tt.allowed || (err != nil && !denied) {
+			t.Errorf("Check(%q, %v) error = %v, want allowed %v", tt.host, tt.addrs, err, tt.allowed)
+		}
+	}
+
+	// Without deny_by_default, only the denied hosts are refused
+	settings.DenyByDefault = false
+	policy, err = settings.Policy()
+	if err != nil {
+		t.Fatalf("Policy() error = %v", err)
+	}
+	if err := policy.Check("registry.npmjs.org", nil); err != nil {
+		t.Errorf("Check() of a host that is not denied error = %v", err)
+	}
+	if err := policy.Check("blocked.example.com", nil); err == nil {
+		t.Error("Check() of a denied host succeeded")
+	}
+
+	if policy, err := (&domain.NetworkSettings{LimitRate: "1M"}).Policy(); policy != nil || err != nil {
+		t.Errorf("Policy() without host settings = %v, %v, want nil", policy, err)
+	}
+	for _, entry := range []string{"https://github.com", "exa mple.com", "*.", "10.0.0.0/33"} {
+		if _, err := (&domain.NetworkSettings{AllowedHosts: []string{entry}}).Policy(); err == nil {
+			t.Errorf("Policy() with allowed host %q succeeded", entry)
+		}
+	}
+}
+
+func TestNetworkSettings_ValidateHeaders(t *testing.T) {
+	valid := &domain.NetworkSettings{
+		UserAgent: "acme-ci/1.0 (skills-pkg)",
+		Headers: map[string]map[string]string{
+			"proxy.example.com":                   {"X-Proxy-Route": "skills"},
+			"*.example.com":                       {"X-Audit-Id": "team\tplatform"},
+			"https://npm.example.com/artifactory": {"X-Audit-Id": "npm"},
+		},
+	}
+	if err := valid.ValidateHeaders(); err != nil {
+		t.Errorf("ValidateHeaders() error = %v", err)
+	}
+
+	for name, settings := range map[string]*domain.NetworkSettings{
+		"user agent with a line break": {UserAgent: "agent\r\nX-Injected: 1"},
+		"header name with a space":     {Headers: map[string]map[string]string{"example.com": {"X Proxy": "1"}}},
+		"empty header name":            {Headers: map[string]map[string]string{"example.com": {"": "1"}}},
+		"header value with a newline":  {Headers: map[string]map[string]string{"example.com": {"X-Proxy": "a\nb"}}},
+		"user agent header":            {Headers: map[string]map[string]string{"example.com": {"user-agent": "agent"}}},
+		"empty host":                   {Headers: map[string]map[string]string{"": {"X-Proxy": "1"}}},
+		"host with a path":             {Headers: map[string]map[string]string{"example.com/api": {"X-Proxy": "1"}}},
+		"URL prefix of another scheme": {Headers: map[string]map[string]string{"ftp://example.com/": {"X-Proxy": "1"}}},
+	} {
+		if err := settings.ValidateHeaders(); err == nil {
+			t.Errorf("ValidateHeaders() with %s succeeded", name)
+		}
+	}
+}
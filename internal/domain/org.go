@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// OrgPolicyFileName is the name of the organization policy at the root of a Git repository.
+const OrgPolicyFileName = "skillspkg-org.toml"
+
+// OrgPolicy is a configuration fragment that an organization publishes for all of its projects.
+type OrgPolicy struct {
+	// BannedSources are the sources that projects must not install skills from: source types such as
+	// "npm", or URL patterns such as "github.com/untrusted" or "github.com/*/experimental-*".
+	// Patterns match without the scheme, user, and .git suffix, and also match every URL below them.
+	BannedSources []string `toml:"banned_sources,omitempty"`
+	Skills        []*Skill `toml:"skills,omitempty"` // Skills that every project must have
+}
+
+// BannedSkill is a skill of a project whose source the organization policy bans.
+type BannedSkill struct {
+	Name    string
+	URL     string
+	Pattern string // Entry of banned_sources that matches the skill
+}
+
+// OrgSyncResult reports how OrgPolicy.Apply changed a configuration.
+type OrgSyncResult struct {
+	Added    []string       // Required skills added to the configuration
+	Replaced []string       // Skills replaced by the required skill of the same name from another source
+	Removed  []*BannedSkill // Skills removed because their source is banned
+}
+
+// Changed reports whether the configuration did not comply with the policy.
+func (r *OrgSyncResult) Changed() bool {
+	return len(r.Added) > 0 || len(r.Replaced) > 0 || len(r.Removed) > 0
+}
+
+// LoadOrgPolicy reads the organization policy that ref names: a local file relative to the directory
+// of the configuration file, or <repository>@<version> for the skillspkg-org.toml of a Git repository.
+func (m *ConfigManager) LoadOrgPolicy(ctx context.Context, ref string) (*OrgPolicy, error) {
+	file, err := m.resolveBase(ctx, ref, filepath.Dir(m.configPath), OrgPolicyFileName)
+	if err != nil {
+		if invalid, ok := errors.AsType[*ErrorInvalidExtends](err); ok {
+			return nil, &ErrorInvalidOrgPolicy{Ref: ref, Reason: invalid.Reason}
+		}
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, &ErrorInvalidOrgPolicy{Ref: ref, Reason: err.Error()}
+	}
+
+	var policy OrgPolicy
+	if err := toml.Unmarshal(data, &policy); err != nil {
+		return nil, &ErrorInvalidOrgPolicy{Ref: ref, Reason: fmt.Sprintf("failed to parse %s: %v", file, err)}
+	}
+
+	for _, skill := range policy.Skills {
+		if err := skill.Validate(); err != nil {
+			return nil, &ErrorInvalidOrgPolicy{Ref: ref, Reason: err.Error()}
+		}
+		if pattern, banned := policy.bans(skill.Source, skill.URL); banned {
+			return nil, &ErrorInvalidOrgPolicy{Ref: ref, Reason: fmt.Sprintf("required skill '%s' is from the banned source %s", skill.Name, pattern)}
+		}
+	}
+
+	return &policy, nil
+}
+
+// Apply makes config comply with the policy: skills from banned sources are removed, and the required
+// skills the configuration lacks are added. A skill with the name of a required skill but another
+// source is replaced by the required skill; one with the same source keeps its version and settings.
+// Encrypted URLs are decrypted to be checked against the banned sources.
+func (p *OrgPolicy) Apply(config *Config) (*OrgSyncResult, error) {
+	result := &OrgSyncResult{}
+
+	var skills []*Skill
+	for _, skill := range config.Skills {
+		source, err := config.SourceOf(skill)
+		if err != nil {
+			return nil, err
+		}
+		if pattern, banned := p.bans(source.Type, source.URL); banned {
+			result.Removed = append(result.Removed, &BannedSkill{Name: skill.Name, URL: config.Sealed(skill.URL), Pattern: pattern})
+			continue
+		}
+		skills = append(skills, skill)
+	}
+
+	for _, required := range p.Skills {
+		i := slices.IndexFunc(skills, func(skill *Skill) bool { return skill.Name == required.Name })
+		switch {
+		case i < 0:
+			skill := *required
+			skills = append(skills, &skill)
+			result.Added = append(result.Added, required.Name)
+		case skills[i].Source != required.Source || normalizeSourceURL(skills[i].URL) != normalizeSourceURL(required.URL):
+			skill := *required
+			skills[i] = &skill
+			result.Replaced = append(result.Replaced, required.Name)
+		}
+	}
+
+	config.Skills = skills
+	return result, nil
+}
+
+// bans returns the entry of banned_sources that matches a skill from url of sourceType.
+func (p *OrgPolicy) bans(sourceType, url string) (string, bool) {
+	url = normalizeSourceURL(url)
+	for _, pattern := range p.BannedSources {
+		if pattern == sourceType {
+			return pattern, true
+		}
+		normalized := normalizeSourceURL(pattern)
+		if normalized == "" {
+			continue
+		}
+		// Patterns also match the URLs below the URLs they match
+		for prefix := url; prefix != ""; {
+			if matched, _ := path.Match(normalized, prefix); matched {
+				return pattern, true
+			}
+			i := strings.LastIndex(prefix, "/")
+			if i < 0 {
+				break
+			}
+			prefix = prefix[:i]
+		}
+	}
+	return "", false
+}
+
+// normalizeSourceURL returns url without the scheme, user, .git suffix, and trailing slashes, with the
+// host and path of SCP-like Git URLs such as git@github.com:org/repo.git separated by a slash.
+func normalizeSourceURL(url string) string {
+	_, rest, hasScheme := strings.Cut(url, "://")
+	if hasScheme {
+		url = rest
+	}
+	// A user before the host, as in https://token@host/repo and git@host:repo
+	if i := strings.Index(url, "@"); i > 0 && !strings.Contains(url[:i], "/") {
+		url = url[i+1:]
+		if !hasScheme {
+			url = strings.Replace(url, ":", "/", 1)
+		}
+	}
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	return strings.ToLower(url)
+}
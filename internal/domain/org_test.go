@@ -0,0 +1,113 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestOrgPolicy_Apply(t *testing.T) {
+	t.Parallel()
+
+	policy := &domain.OrgPolicy{
+		BannedSources: []string{"npm", "github.com/untrusted", "github.com/*/experimental-*"},
+		Skills: []*domain.Skill{
+			{Name: "security-review", Source: "git", URL: "https://github.com/org/skills.git", SubDir: "security-review"},
+			{Name: "style", Source: "git", URL: "https://github.com/org/style.git"},
+			{Name: "kept", Source: "git", URL: "https://github.com/org/kept.git"},
+		},
+	}
+	config := &domain.Config{
+		InstallTargets: []string{"./skills"},
+		Skills: []*domain.Skill{
+			{Name: "own", Source: "git", URL: "https://github.com/team/own.git"},
+			{Name: "from-npm", Source: "npm", URL: "some-skill"},
+			{Name: "untrusted", Source: "git", URL: "git@github.com:Untrusted/repo.git"},
+			{Name: "experiment", Source: "git", URL: "https://github.com/team/experimental-tool"},
+			{Name: "style", Source: "git", URL: "https://github.com/fork/style.git"},
+			{Name: "kept", Source: "git", URL: "https://github.com/org/kept", Version: "v2.0.0"},
+		},
+	}
+
+	result, err := policy.Apply(config)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	var removed []string
+	for _, banned := range result.Removed {
+		removed = append(removed, banned.Name+":"+banned.Pattern)
+	}
+	if want := []string{"from-npm:npm", "untrusted:github.com/untrusted", "experiment:github.com/*/experimental-*"}; !slices.Equal(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if want := []string{"security-review"}; !slices.Equal(result.Added, want) {
+		t.Errorf("added = %v, want %v", result.Added, want)
+	}
+	if want := []string{"style"}; !slices.Equal(result.Replaced, want) {
+		t.Errorf("replaced = %v, want %v", result.Replaced, want)
+	}
+
+	var names []string
+	for _, skill := range config.Skills {
+		names = append(names, skill.Name)
+	}
+	if want := []string{"own", "style", "kept", "security-review"}; !slices.Equal(names, want) {
+		t.Errorf("skills = %v, want %v", names, want)
+	}
+	if kept := config.FindSkillByName("kept"); kept.Version != "v2.0.0" {
+		t.Errorf("kept version = %q, a required skill from the same source should keep its version", kept.Version)
+	}
+
+	again, err := policy.Apply(config)
+	if err != nil {
+		t.Fatalf("Apply() again error = %v", err)
+	}
+	if again.Changed() {
+		t.Errorf("Apply() again = %+v, want no changes", again)
+	}
+}
+
+func TestConfigManager_LoadOrgPolicy(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeConfigFile(t, filepath.Join(root, "org", "skillspkg-org.toml"), `banned_sources = ["npm"]
+
+[[skills]]
+name = "security-review"
+source = "git"
+url = "https://github.com/org/skills.git"
+`)
+	writeConfigFile(t, filepath.Join(root, "org", "contradicting.toml"), `banned_sources = ["github.com/org"]
+
+[[skills]]
+name = "security-review"
+source = "git"
+url = "https://github.com/org/skills.git"
+`)
+	m := domain.NewConfigManager(filepath.Join(root, "project", ".skillspkg.toml"))
+
+	policy, err := m.LoadOrgPolicy(context.Background(), "../org/skillspkg-org.toml")
+	if err != nil {
+		t.Fatalf("LoadOrgPolicy() error = %v", err)
+	}
+	if len(policy.Skills) != 1 || !slices.Equal(policy.BannedSources, []string{"npm"}) {
+		t.Errorf("LoadOrgPolicy() = %+v", policy)
+	}
+
+	for _, ref := range []string{"../org/contradicting.toml", "../org/missing.toml", "github.com/org/policy@v1"} {
+		if _, err := m.LoadOrgPolicy(context.Background(), ref); !isInvalidOrgPolicy(err) {
+			t.Errorf("LoadOrgPolicy(%s) error = %v, want ErrorInvalidOrgPolicy", ref, err)
+		}
+	}
+}
+
+func isInvalidOrgPolicy(err error) bool {
+	_, ok := errors.AsType[*domain.ErrorInvalidOrgPolicy](err)
+	return ok
+}
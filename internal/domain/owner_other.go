@@ -0,0 +1,17 @@
+//go:build !unix
+
+package domain
+
+import "io/fs"
+
+// isRoot reports whether the process runs with root privileges.
+// Root detection is only supported on Unix-like systems.
+func isRoot() bool {
+	return false
+}
+
+// fileOwner returns the user and group owning the file.
+// File ownership is only supported on Unix-like systems.
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package domain
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// isRoot reports whether the process runs with root privileges.
+func isRoot() bool {
+	return os.Geteuid() == 0
+}
+
+// fileOwner returns the user and group owning the file.
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
@@ -0,0 +1,116 @@
+//go:build unix
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// userUID is the owner given to install targets that belong to a regular user.
+const userUID = 1000
+
+// TestInstall_AsRoot tests that root refuses to write into a user-owned install target in the
+// home directory unless allowed, and then gives the installed files to the target's owner.
+func TestInstall_AsRoot(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires running as root")
+	}
+
+	tests := []struct {
+		name        string
+		opts        []SkillManagerOption
+		outsideHome bool
+		wantRefus   bool
+	}{
+		{
+			name:      "refused by default",
+			wantRefus: true,
+		},
+		{
+			name: "allowed with WithAllowRoot",
+			opts: []SkillManagerOption{WithAllowRoot()},
+		},
+		{
+			name:        "project outside the home directory",
+			outsideHome: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".skillspkg.toml")
+			home := filepath.Join(tmpDir, "home")
+			installDir := filepath.Join(home, ".claude", "skills")
+			t.Setenv("SUDO_USER", "")
+			t.Setenv("HOME", home)
+			if tt.outsideHome {
+				// A workspace checked out by another user, as in a CI container
+				t.Setenv("HOME", filepath.Join(tmpDir, "root"))
+			}
+			downloadDir := filepath.Join(tmpDir, "download")
+
+			if err := os.MkdirAll(home, 0o755); err != nil {
+				t.Fatalf("Failed to create home directory: %v", err)
+			}
+			if err := os.Chown(home, userUID, userUID); err != nil {
+				t.Fatalf("Failed to change owner of home directory: %v", err)
+			}
+			if err := os.MkdirAll(downloadDir, 0o755); err != nil {
+				t.Fatalf("Failed to create download directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(downloadDir, "SKILL.md"), []byte("skill"), 0o644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			configManager := NewConfigManager(configPath)
+			ctx := context.Background()
+			config := &Config{
+				Skills:         []*Skill{{Name: "test-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0"}},
+				InstallTargets: []string{installDir},
+			}
+			if err := configManager.Save(ctx, config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			pm := &mockPackageManagerWithDownload{
+				sourceType:     "git",
+				downloadResult: &port.DownloadResult{Path: downloadDir, Version: "v1.0.0"},
+			}
+			skillManager := NewSkillManager(configManager, &mockHashServiceWithCustom{}, []port.PackageManager{pm}, tt.opts...)
+
+			err := skillManager.Install(ctx, "test-skill")
+			if tt.wantRefus {
+				if _, ok := errors.AsType[*ErrorRootWriteToUserTarget](err); !ok {
+					t.Fatalf("Install() error = %v, want ErrorRootWriteToUserTarget", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			for _, path := range []string{
+				filepath.Join(home, ".claude"),
+				installDir,
+				filepath.Join(installDir, "test-skill"),
+				filepath.Join(installDir, "test-skill", "SKILL.md"),
+			} {
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatalf("Failed to stat %s: %v", path, err)
+				}
+				if uid := info.Sys().(*syscall.Stat_t).Uid; uid != userUID {
+					t.Errorf("owner of %s = %d, want %d", path, uid, userUID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// PackOptions configures how a skill archive is written.
+type PackOptions struct {
+	// Prefix is the directory every entry is placed under (e.g., "my-skill").
+	Prefix string
+	// Reproducible makes the archive byte-identical for identical skill contents
+	// by fixing timestamps, sorting entries, normalizing modes and dropping ownership.
+	Reproducible bool
+	// Exclude lists gitignore-style patterns of further files left out of the archive.
+	Exclude []string
+}
+
+// reproducibleModTime is the timestamp recorded for every entry of a reproducible archive.
+var reproducibleModTime = time.Unix(0, 0).UTC()
+
+// PackSkill writes the files of the skill directory as a gzip-compressed tar archive to w.
+// Files excluded by the skill's .gitignore/.skillignore or by opts.Exclude are not included.
+func PackSkill(w io.Writer, skillDir string, opts PackOptions) error {
+	files, err := ListSkillFiles(skillDir)
+	if err != nil {
+		return fmt.Errorf("failed to list files in %s: %w", skillDir, err)
+	}
+	if ignore := NewSkillIgnore(opts.Exclude); ignore != nil {
+		files = slices.DeleteFunc(files, func(name string) bool {
+			return ignore.Match(name, false)
+		})
+	}
+	sort.Strings(files)
+
+	gw := gzip.NewWriter(w)
+	if !opts.Reproducible {
+		gw.ModTime = time.Now()
+	}
+	tw := tar.NewWriter(gw)
+
+	for _, name := range files {
+		if err := writeTarEntry(tw, filepath.Join(skillDir, filepath.FromSlash(name)), path.Join(opts.Prefix, name), opts.Reproducible); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	return nil
+}
+
+// writeTarEntry adds a single regular file to the archive.
+func writeTarEntry(tw *tar.Writer, src, name string, reproducible bool) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to access %s: %w", src, err)
+	}
+
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     info.Size(),
+		Mode:     int64(info.Mode().Perm()),
+		ModTime:  info.ModTime(),
+	}
+	if reproducible {
+		// Only the executable bit is meaningful across platforms
+		header.Mode = 0o644
+		if info.Mode().Perm()&0o111 != 0 {
+			header.Mode = 0o755
+		}
+		header.ModTime = reproducibleModTime
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive header for %s: %w", name, err)
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package domain_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestPackSkill(t *testing.T) {
+	tests := []struct {
+		files        map[string]string
+		name         string
+		exclude      []string
+		wantEntries  []string
+		reproducible bool
+		wantSame     bool
+	}{
+		{
+			name: "reproducible archives are byte-identical",
+			files: map[string]string{
+				"SKILL.md":       "skill",
+				"scripts/run.sh": "echo",
+				"a.txt":          "a",
+			},
+			wantEntries:  []string{"my-skill/SKILL.md", "my-skill/a.txt", "my-skill/scripts/run.sh"},
+			reproducible: true,
+			wantSame:     true,
+		},
+		{
+			name: "default archives record file timestamps",
+			files: map[string]string{
+				"SKILL.md": "skill",
+			},
+			wantEntries:  []string{"my-skill/SKILL.md"},
+			reproducible: false,
+			wantSame:     false,
+		},
+		{
+			name: "ignored files are not packed",
+			files: map[string]string{
+				".skillignore": "*.log\n",
+				"SKILL.md":     "skill",
+				"debug.log":    "log",
+			},
+			wantEntries:  []string{"my-skill/.skillignore", "my-skill/SKILL.md"},
+			reproducible: true,
+			wantSame:     true,
+		},
+		{
+			name: "excluded files are not packed",
+			files: map[string]string{
+				"SKILL.md":        "skill",
+				".skillspkg.json": "{}",
+			},
+			exclude:      []string{"/.skillspkg.json"},
+			wantEntries:  []string{"my-skill/SKILL.md"},
+			reproducible: true,
+			wantSame:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Write the same contents twice with different timestamps and modes
+			dir1 := writePackFixture(t, tt.files, 0o644, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+			dir2 := writePackFixture(t, tt.files, 0o600, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+			opts := domain.PackOptions{Prefix: "my-skill", Reproducible: tt.reproducible, Exclude: tt.exclude}
+			var buf1, buf2 bytes.Buffer
+			if err := domain.PackSkill(&buf1, dir1, opts); err != nil {
+				t.Fatalf("PackSkill() error = %v", err)
+			}
+			if err := domain.PackSkill(&buf2, dir2, opts); err != nil {
+				t.Fatalf("PackSkill() error = %v", err)
+			}
+
+			if got := bytes.Equal(buf1.Bytes(), buf2.Bytes()); got != tt.wantSame {
+				t.Errorf("archives identical = %v, want %v", got, tt.wantSame)
+			}
+
+			entries := readTarEntries(t, buf1.Bytes())
+			if !slices.Equal(entries, tt.wantEntries) {
+				t.Errorf("archive entries = %v, want %v", entries, tt.wantEntries)
+			}
+		})
+	}
+}
+
+func writePackFixture(t *testing.T, files map[string]string, mode os.FileMode, modTime time.Time) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set file times: %v", err)
+		}
+	}
+
+	return dir
+}
+
+func readTarEntries(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to open gzip stream: %v", err)
+	}
+	tr := tar.NewReader(gr)
+
+	var entries []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar entry: %v", err)
+		}
+		entries = append(entries, header.Name)
+	}
+
+	return entries
+}
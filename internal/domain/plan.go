@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// planFileVersion is the format version written to new plan files.
+const planFileVersion = 1
+
+// PlanAction is the kind of change a plan makes to a skill in an install target.
+type PlanAction string
+
+const (
+	PlanInstall PlanAction = "install" // The skill is not installed in the target
+	PlanUpdate  PlanAction = "update"  // The target holds another version or hash than the configuration
+	PlanRepair  PlanAction = "repair"  // Files in the target were modified after installation
+	PlanRemove  PlanAction = "remove"  // The skill or the target is no longer in the configuration
+)
+
+// PlannedChange is a single change to a skill in an install target.
+type PlannedChange struct {
+	Action      PlanAction `toml:"action"`
+	Skill       string     `toml:"skill"`
+	Target      string     `toml:"target"`                 // Install target as written in .skillspkg.toml
+	FromVersion string     `toml:"from_version,omitempty"` // Version recorded in the lock file
+	ToVersion   string     `toml:"to_version,omitempty"`   // Version pinned in the configuration
+}
+
+// Plan lists the changes that bring the install targets in line with the configuration.
+// It is computed from the configuration, the lock file, and the files in local install targets.
+type Plan struct {
+	CreatedAt  time.Time        `toml:"created_at"`
+	ConfigHash string           `toml:"config_hash"` // SHA-256 of the configuration file the plan was computed from
+	Changes    []*PlannedChange `toml:"changes"`
+	Version    int              `toml:"version"`
+}
+
+// Count returns the number of changes with the given action.
+func (p *Plan) Count(action PlanAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Skills returns the names of the skills to install, update, or repair, in plan order.
+func (p *Plan) Skills() []string {
+	var names []string
+	for _, change := range p.Changes {
+		if change.Action != PlanRemove && !slices.Contains(names, change.Skill) {
+			names = append(names, change.Skill)
+		}
+	}
+	return names
+}
+
+// Matches reports whether the plan was computed from the same configuration as other
+// and contains the same changes.
+func (p *Plan) Matches(other *Plan) bool {
+	return p.ConfigHash == other.ConfigHash && slices.EqualFunc(p.Changes, other.Changes, func(a, b *PlannedChange) bool {
+		return *a == *b
+	})
+}
+
+// Planner computes plans for the configuration managed by a ConfigManager.
+type Planner struct {
+	configManager *ConfigManager
+	hashService   port.HashService
+	lockManager   *LockManager
+}
+
+// NewPlanner creates a new Planner.
+// The hashService is used to detect modified files in local install targets.
+func NewPlanner(configManager *ConfigManager, hashService port.HashService) *Planner {
+	return &Planner{
+		configManager: configManager,
+		hashService:   hashService,
+		lockManager:   NewLockManager(LockPathFor(configManager.configPath)),
+	}
+}
+
+// Plan compares the configuration with the lock file and the install targets and returns the pending changes.
+// Changes are ordered by skill and install target as listed in the configuration, followed by removals.
+// Removals follow the same rules as SkillManager.Prune.
+func (p *Planner) Plan(ctx context.Context) (*Plan, error) {
+	config, err := p.configManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(p.configManager.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	lock, err := p.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Version:    planFileVersion,
+		ConfigHash: "sha256:" + hex.EncodeToString(sum[:]),
+	}
+
+	for _, skill := range config.InstalledSkills() {
+		locked := lock.FindSkill(skill.Name)
+		for _, target := range config.InstallTargets {
+			status := locked.TargetStatus(target)
+			freshness, err := CheckTargetFreshness(ctx, p.hashService, config, skill, status)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check skill '%s' in %s: %w", skill.Name, target, err)
+			}
+
+			change := &PlannedChange{Skill: skill.Name, Target: target, ToVersion: skill.ForTarget(target).Version}
+			switch freshness {
+			case TargetNotInstalled:
+				change.Action = PlanInstall
+			case TargetOutdated:
+				change.Action = PlanUpdate
+			case TargetModified:
+				change.Action = PlanRepair
+			default:
+				continue
+			}
+			if status != nil {
+				change.FromVersion = status.Version
+			}
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+
+	for _, locked := range lock.Skills {
+		configured := config.FindInstalledSkill(locked.Name) != nil
+		for _, status := range locked.Targets {
+			if configured && slices.Contains(config.InstallTargets, status.Path) {
+				continue
+			}
+			plan.Changes = append(plan.Changes, &PlannedChange{
+				Action:      PlanRemove,
+				Skill:       locked.Name,
+				Target:      status.Path,
+				FromVersion: status.Version,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// WritePlan writes the plan to a file at path, recording the current time as its creation time.
+func WritePlan(path string, plan *Plan) error {
+	plan.Version = planFileVersion
+	plan.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	data, err := toml.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write plan file at %s: %w. Check file permissions", path, err)
+	}
+
+	return nil
+}
+
+// ReadPlan reads a plan file written by WritePlan.
+func ReadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("plan file not found at %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("failed to read plan file at %s: %w", path, err)
+	}
+
+	var plan Plan
+	if err := toml.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file at %s: %w", path, err)
+	}
+	if plan.Version != planFileVersion {
+		return nil, fmt.Errorf("unsupported plan file version %d in %s", plan.Version, path)
+	}
+
+	return &plan, nil
+}
@@ -0,0 +1,124 @@
+package domain_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/adapter/service"
+	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestPlanner_Plan(t *testing.T) {
+	ctx := context.Background()
+	hashService := service.NewDirhash()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".skillspkg.toml")
+	target := filepath.Join(dir, "skills")
+	oldTarget := filepath.Join(dir, "old-skills")
+
+	// installSkill writes the skill into the target and returns its hash
+	installSkill := func(name string) string {
+		t.Helper()
+		skillDir := filepath.Join(target, name)
+		if err := os.MkdirAll(skillDir, 0o755); err != nil {
+			t.Fatalf("failed to create skill directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(name), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		hash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
+		if err != nil {
+			t.Fatalf("failed to calculate hash: %v", err)
+		}
+		return hash.Value
+	}
+	currentHash := installSkill("current")
+	outdatedHash := installSkill("outdated")
+	modifiedHash := installSkill("modified")
+	if err := os.WriteFile(filepath.Join(target, "modified", "SKILL.md"), []byte("edited"), 0o644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+
+	configManager := domain.NewConfigManager(configPath)
+	err := configManager.Save(ctx, &domain.Config{
+		InstallTargets: []string{target},
+		Skills: []*domain.Skill{
+			{Name: "current", Source: "git", URL: "https://example.com/current.git", Version: "v1.0.0", HashValue: currentHash},
+			{Name: "missing", Source: "git", URL: "https://example.com/missing.git", Version: "v1.0.0"},
+			{Name: "outdated", Source: "git", URL: "https://example.com/outdated.git", Version: "v2.0.0"},
+			{Name: "modified", Source: "git", URL: "https://example.com/modified.git", Version: "v1.0.0", HashValue: modifiedHash},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	err = domain.NewLockManager(domain.LockPathFor(configPath)).Update(ctx, func(lock *domain.LockFile) {
+		lock.RecordInstall("current", &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: currentHash})
+		lock.RecordInstall("outdated", &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: outdatedHash})
+		lock.RecordInstall("modified", &domain.TargetStatus{Path: target, Version: "v1.0.0", HashValue: modifiedHash})
+		lock.RecordInstall("current", &domain.TargetStatus{Path: oldTarget, Version: "v1.0.0"})
+		lock.RecordInstall("removed", &domain.TargetStatus{Path: target, Version: "v0.1.0"})
+	})
+	if err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	planner := domain.NewPlanner(configManager, hashService)
+	plan, err := planner.Plan(ctx)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := []domain.PlannedChange{
+		{Action: domain.PlanInstall, Skill: "missing", Target: target, ToVersion: "v1.0.0"},
+		{Action: domain.PlanUpdate, Skill: "outdated", Target: target, FromVersion: "v1.0.0", ToVersion: "v2.0.0"},
+		{Action: domain.PlanRepair, Skill: "modified", Target: target, FromVersion: "v1.0.0", ToVersion: "v1.0.0"},
+		{Action: domain.PlanRemove, Skill: "current", Target: oldTarget, FromVersion: "v1.0.0"},
+		{Action: domain.PlanRemove, Skill: "removed", Target: target, FromVersion: "v0.1.0"},
+	}
+	got := make([]domain.PlannedChange, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		got = append(got, *change)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Plan() changes = %+v, want %+v", got, want)
+	}
+	if skills := plan.Skills(); !slices.Equal(skills, []string{"missing", "outdated", "modified"}) {
+		t.Errorf("Skills() = %v", skills)
+	}
+
+	// A plan survives a round trip through its file and still matches the current state
+	planPath := filepath.Join(dir, "skills.plan")
+	if err := domain.WritePlan(planPath, plan); err != nil {
+		t.Fatalf("WritePlan() error = %v", err)
+	}
+	saved, err := domain.ReadPlan(planPath)
+	if err != nil {
+		t.Fatalf("ReadPlan() error = %v", err)
+	}
+	if !saved.Matches(plan) {
+		t.Errorf("saved plan %+v does not match %+v", saved, plan)
+	}
+
+	// Editing the configuration makes the saved plan stale
+	config, err := configManager.Load(ctx)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	config.Skills = config.Skills[:1]
+	if err := configManager.Save(ctx, config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	current, err := planner.Plan(ctx)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if saved.Matches(current) {
+		t.Error("saved plan should not match after the configuration changed")
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import "slices"
+
+// knownPlatforms are the operating systems accepted in the os field of skills and install targets,
+// named as in GOOS.
+var knownPlatforms = []string{
+	"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js",
+	"linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows",
+}
+
+// platformEntry is a skill or install target of another platform, with its position in the configuration.
+type platformEntry[T any] struct {
+	value T
+	index int
+}
+
+// otherPlatforms holds the skills and install targets whose os field excludes the current platform.
+// They are left out of the configuration while it is used and written back where they were when it is saved.
+type otherPlatforms struct {
+	skills  []platformEntry[*Skill]
+	targets []platformEntry[string]
+}
+
+// matchesPlatform reports whether an entry restricted to the operating systems applies to the platform.
+// An entry without restrictions applies to every platform.
+func matchesPlatform(platforms []string, platform string) bool {
+	return len(platforms) == 0 || slices.Contains(platforms, platform)
+}
+
+// platformsOverlap reports whether two entries restricted to the operating systems apply to a platform in common.
+func platformsOverlap(a, b []string) bool {
+	return len(a) == 0 || len(b) == 0 || slices.ContainsFunc(a, func(platform string) bool {
+		return slices.Contains(b, platform)
+	})
+}
+
+// validatePlatforms returns the first operating system that is not a known GOOS value, if any.
+func validatePlatforms(platforms []string) (string, bool) {
+	for _, platform := range platforms {
+		if !slices.Contains(knownPlatforms, platform) {
+			return platform, false
+		}
+	}
+	return "", true
+}
+
+// selectPlatform sets aside the skills and install targets that do not apply to the platform,
+// so that a configuration shared between operating systems only installs what each one supports.
+func (c *Config) selectPlatform(platform string) error {
+	skills := make([]*Skill, 0, len(c.Skills))
+	for i, skill := range c.Skills {
+		if value, ok := validatePlatforms(skill.OS); !ok {
+			return &ErrorInvalidPlatform{Entry: "skill '" + skill.Name + "'", Platform: value}
+		}
+		if matchesPlatform(skill.OS, platform) {
+			skills = append(skills, skill)
+			continue
+		}
+		c.others.skills = append(c.others.skills, platformEntry[*Skill]{value: skill, index: i})
+	}
+
+	targets := make([]string, 0, len(c.InstallTargets))
+	for i, target := range c.InstallTargets {
+		var platforms []string
+		if settings := c.TargetSettingsFor(target); settings != nil {
+			platforms = settings.OS
+		}
+		if value, ok := validatePlatforms(platforms); !ok {
+			return &ErrorInvalidPlatform{Entry: "install target " + target, Platform: value}
+		}
+		if matchesPlatform(platforms, platform) {
+			targets = append(targets, target)
+			continue
+		}
+		c.others.targets = append(c.others.targets, platformEntry[string]{value: target, index: i})
+	}
+
+	c.Skills = skills
+	c.InstallTargets = targets
+	return nil
+}
+
+// withOtherPlatforms returns the configuration with the skills and install targets of other
+// platforms put back at their original positions, as it is written to .skillspkg.toml.
+func (c *Config) withOtherPlatforms() *Config {
+	if len(c.others.skills) == 0 && len(c.others.targets) == 0 {
+		return c
+	}
+
+	merged := *c
+	merged.others = otherPlatforms{}
+	merged.Skills = restoreEntries(c.Skills, c.others.skills)
+	merged.InstallTargets = restoreEntries(c.InstallTargets, c.others.targets)
+	return &merged
+}
+
+// restoreEntries inserts the entries of other platforms into the current ones at their original positions.
+// Entries whose position is past the end, after current entries were removed, are appended.
+func restoreEntries[T any](current []T, others []platformEntry[T]) []T {
+	restored := slices.Clone(current)
+	for _, other := range others {
+		restored = slices.Insert(restored, min(other.index, len(restored)), other.value)
+	}
+	return restored
+}
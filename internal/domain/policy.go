@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// licenseFiles are the files a license is detected from when SKILL.md declares none.
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"}
+
+// licenseSignatures maps phrases from the beginning of common license texts to SPDX identifiers.
+// They are matched in order, so more specific phrases come first.
+var licenseSignatures = []struct {
+	phrase string
+	spdx   string
+}{
+	{phrase: "apache license", spdx: "Apache-2.0"},
+	{phrase: "mit license", spdx: "MIT"},
+	{phrase: "permission is hereby granted, free of charge", spdx: "MIT"},
+	{phrase: "gnu affero general public license", spdx: "AGPL-3.0"},
+	{phrase: "gnu lesser general public license", spdx: "LGPL-3.0"},
+	{phrase: "gnu general public license", spdx: "GPL-3.0"},
+	{phrase: "mozilla public license", spdx: "MPL-2.0"},
+	{phrase: "isc license", spdx: "ISC"},
+	{phrase: "neither the name", spdx: "BSD-3-Clause"},
+	{phrase: "redistribution and use in source and binary forms", spdx: "BSD-2-Clause"},
+	{phrase: "creative commons attribution 4.0", spdx: "CC-BY-4.0"},
+	{phrase: "this is free and unencumbered software", spdx: "Unlicense"},
+}
+
+// checkPolicy evaluates the configured policy against the downloaded skill in sourcePath.
+// It returns ErrorPolicyDenied when the policy denies the skill, and nil when no policy is configured.
+func (s *skillManagerImpl) checkPolicy(ctx context.Context, config *Config, sourcePath string, skill *Skill, version string) error {
+	if config.Policy == "" {
+		return nil
+	}
+
+	policy, err := s.loadPolicy(config.Policy)
+	if err != nil {
+		return err
+	}
+
+	input, err := newPolicyInput(sourcePath, skill, version)
+	if err != nil {
+		return fmt.Errorf("failed to inspect skill '%s' for the policy: %w", skill.Name, err)
+	}
+
+	reason, err := policy.Evaluate(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policy %s for skill '%s': %w", config.Policy, skill.Name, err)
+	}
+	if reason != "" {
+		return &ErrorPolicyDenied{SkillName: skill.Name, Version: version, Reason: reason}
+	}
+
+	return nil
+}
+
+// loadPolicy compiles the policy file once per SkillManager.
+// The path is relative to the directory of the configuration file.
+func (s *skillManagerImpl) loadPolicy(path string) (port.Policy, error) {
+	s.policyMu.Lock()
+	defer s.policyMu.Unlock()
+
+	if s.policyEngine == nil {
+		return nil, fmt.Errorf("policy %s is configured, but no policy engine is available", path)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(s.configManager.configPath), path)
+	}
+	if policy, ok := s.policies[path]; ok {
+		return policy, nil
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	policy, err := s.policyEngine.Compile(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+
+	s.policies[path] = policy
+	return policy, nil
+}
+
+// newPolicyInput describes the skill in dir to a policy.
+func newPolicyInput(dir string, skill *Skill, version string) (*port.PolicyInput, error) {
+	files, err := ListSkillFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	size, err := skillContentSize(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.PolicyInput{
+		Name:    skill.Name,
+		Source:  skill.Source,
+		URL:     skill.URL,
+		Version: version,
+		License: detectLicense(dir),
+		Files:   files,
+		Size:    size,
+	}, nil
+}
+
+// detectLicense returns the license field of the SKILL.md frontmatter, or the SPDX identifier
+// of a recognized license file. It returns an empty string when the license is unknown.
+func detectLicense(dir string) string {
+	if license := skillMDLicense(dir); license != "" {
+		return license
+	}
+
+	for _, name := range licenseFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		// The license name and its distinctive clauses are at the beginning of the text
+		head := strings.ToLower(strings.Join(strings.Fields(string(data[:min(len(data), 2048)])), " "))
+		for _, signature := range licenseSignatures {
+			if strings.Contains(head, signature.phrase) {
+				return signature.spdx
+			}
+		}
+	}
+
+	return ""
+}
+
+// skillMDLicense returns the license field of the SKILL.md frontmatter of the skill in dir.
+func skillMDLicense(dir string) string {
+	metadata, err := readSkillMD(dir)
+	if err != nil || metadata == nil {
+		return ""
+	}
+	return metadata.License
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is the number of skills processed at the same time when WithConcurrency is not given.
+const DefaultConcurrency = 8
+
+// priorityGroups returns the indexes of skills grouped by priority, highest priority first.
+// Within a group, skills keep their order in the configuration.
+func priorityGroups(skills []*Skill) [][]int {
+	indexes := make([]int, len(skills))
+	for i := range skills {
+		indexes[i] = i
+	}
+	slices.SortStableFunc(indexes, func(a, b int) int {
+		return cmp.Compare(skills[b].Priority, skills[a].Priority)
+	})
+
+	var groups [][]int
+	for i, index := range indexes {
+		if i == 0 || skills[index].Priority != skills[indexes[i-1]].Priority {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], index)
+	}
+	return groups
+}
+
+// forEachByPriority calls fn for every skill, one priority group after another.
+// Skills of the same priority are processed concurrently, at most s.concurrency at a time so that
+// large configurations do not flood the servers they download from. Each skill logs its progress
+// messages to a buffer that is flushed as one block when the skill is done, so that the messages of
+// several skills do not interleave.
+func (s *skillManagerImpl) forEachByPriority(ctx context.Context, skills []*Skill, fn func(ctx context.Context, i int, skill *Skill) error) error {
+	for _, group := range priorityGroups(skills) {
+		eg, egCtx := errgroup.WithContext(ctx)
+		if s.concurrency > 0 {
+			eg.SetLimit(s.concurrency)
+		}
+		for _, i := range group {
+			eg.Go(func() error {
+				if len(skills) == 1 {
+					return fn(egCtx, i, skills[i])
+				}
+
+				out := &skillOutput{}
+				defer s.flush(egCtx, out)
+				return fn(context.WithValue(egCtx, skillOutputKey{}, out), i, skills[i])
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPriorityGroups(t *testing.T) {
+	t.Parallel()
+
+	skills := []*Skill{
+		{Name: "a"},
+		{Name: "b", Priority: 10},
+		{Name: "c", Priority: -1},
+		{Name: "d"},
+		{Name: "e", Priority: 10},
+	}
+
+	got := priorityGroups(skills)
+	want := [][]int{{1, 4}, {0, 3}, {2}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("priorityGroups() = %v, want %v", got, want)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestForEachByPriority(t *testing.T) {
+	t.Parallel()
+
+	out := &lockedBuffer{}
+	s := &skillManagerImpl{logger: slog.New(NewMessageHandler(out, slog.LevelInfo))}
+	skills := []*Skill{{Name: "low"}, {Name: "high", Priority: 1}, {Name: "other"}}
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	err := s.forEachByPriority(context.Background(), skills, func(ctx context.Context, i int, skill *Skill) error {
+		if skills[i] != skill {
+			t.Errorf("skill %d = %s, want %s", i, skill.Name, skills[i].Name)
+		}
+		mu.Lock()
+		order = append(order, skill.Name)
+		mu.Unlock()
+		for line := range 3 {
+			s.log(ctx, slog.LevelInfo, fmt.Sprintf("%s %d", skill.Name, line))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEachByPriority() error = %v", err)
+	}
+
+	if order[0] != "high" {
+		t.Errorf("first processed skill = %s, want high", order[0])
+	}
+
+	// The messages of each skill are written as one block
+	for _, name := range []string{"low", "high", "other"} {
+		block := fmt.Sprintf("%[1]s 0\n%[1]s 1\n%[1]s 2\n", name)
+		if !strings.Contains(out.buf.String(), block) {
+			t.Errorf("output %q does not contain the messages of %s as one block", out.buf.String(), name)
+		}
+	}
+	if !strings.HasPrefix(out.buf.String(), "high 0\n") {
+		t.Errorf("output %q should start with the messages of high", out.buf.String())
+	}
+}
+
+func TestForEachByPriority_SingleSkillUnbuffered(t *testing.T) {
+	t.Parallel()
+
+	s := &skillManagerImpl{logger: slog.New(NewMessageHandler(io.Discard, slog.LevelInfo))}
+	err := s.forEachByPriority(context.Background(), []*Skill{{Name: "only"}}, func(ctx context.Context, _ int, _ *Skill) error {
+		if ctx.Value(skillOutputKey{}) != nil {
+			t.Error("a single skill should write its messages directly")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEachByPriority() error = %v", err)
+	}
+}
+
+func TestForEachByPriority_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	s := &skillManagerImpl{logger: slog.New(NewMessageHandler(io.Discard, slog.LevelInfo))}
+	WithConcurrency(2)(s)
+	skills := make([]*Skill, 6)
+	for i := range skills {
+		skills[i] = &Skill{Name: fmt.Sprintf("skill-%d", i)}
+	}
+
+	var running, peak atomic.Int32
+	err := s.forEachByPriority(context.Background(), skills, func(ctx context.Context, _ int, _ *Skill) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEachByPriority() error = %v", err)
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("skills processed at the same time = %d, want at most 2", got)
+	}
+}
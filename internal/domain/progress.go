@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ProgressPhase is the step of an operation on a skill that a ProgressEvent reports.
+type ProgressPhase string
+
+// Phases of installing a skill, in order, followed by the phases of other operations.
+const (
+	PhaseStart    ProgressPhase = "start"    // The skill is being installed
+	PhaseDownload ProgressPhase = "download" // The skill is being downloaded
+	PhaseCheck    ProgressPhase = "check"    // The download is checked by the linter, policy, and scanner
+	PhaseHash     ProgressPhase = "hash"     // The hash of the download is calculated
+	PhaseCopy     ProgressPhase = "copy"     // The skill is copied into the install targets
+	PhaseVerify   ProgressPhase = "verify"   // The installed copies are verified
+	PhaseDone     ProgressPhase = "done"     // The skill is installed or already up to date
+	PhaseWarning  ProgressPhase = "warning"  // Something deserves attention but the operation continues
+	PhaseBackup   ProgressPhase = "backup"   // An installed copy was backed up
+	PhaseRestore  ProgressPhase = "restore"  // A backup was restored
+	PhaseRemove   ProgressPhase = "remove"   // The skill is being removed
+	PhaseMove     ProgressPhase = "move"     // The skill was moved to another install target
+)
+
+// phasePercents are the share of the installation of a skill that is complete when a phase starts.
+var phasePercents = map[ProgressPhase]int{
+	PhaseStart:    0,
+	PhaseDownload: 10,
+	PhaseCheck:    40,
+	PhaseHash:     50,
+	PhaseCopy:     60,
+	PhaseVerify:   90,
+	PhaseDone:     100,
+}
+
+// ProgressEvent reports a step of an operation on a skill.
+type ProgressEvent struct {
+	Skill   string        `json:"skill"`
+	Phase   ProgressPhase `json:"phase"`
+	Target  string        `json:"target,omitempty"` // Install target the event is about, if any
+	Message string        `json:"message"`          // Human-readable description of the event
+	// Percent estimates how much of the installation of the skill is complete.
+	// It is nil for events that are not a phase of an installation.
+	Percent *int `json:"percent,omitempty"`
+}
+
+// emit reports a progress event about skill to the progress handler, or logs its message
+// to the logger when no handler is set. Warnings are logged at the warning level, and the
+// other phases at the info level, so that a logger at the warning level only shows warnings.
+func (s *skillManagerImpl) emit(ctx context.Context, skill string, phase ProgressPhase, target string, format string, args ...any) {
+	event := &ProgressEvent{
+		Skill:   skill,
+		Phase:   phase,
+		Target:  target,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if percent, ok := phasePercents[phase]; ok {
+		event.Percent = &percent
+	}
+
+	if s.progressHandler != nil {
+		s.progressHandler(event)
+		return
+	}
+
+	level := slog.LevelInfo
+	if phase == PhaseWarning {
+		level = slog.LevelWarn
+	}
+	attrs := []any{slog.String("skill", skill), slog.String("phase", string(phase))}
+	if target != "" {
+		attrs = append(attrs, slog.String("target", target))
+	}
+	s.log(ctx, level, event.Message, attrs...)
+}
+
+// copyProgressMinSize is the smallest skill whose copy reports its progress; smaller skills are copied
+// too quickly for it to matter.
+const copyProgressMinSize = 1 << 20
+
+// copyProgress returns the progress callback of copying the skill in sourcePath into target, which
+// emits a copy event every quarter of the skill copied. It returns nil without a progress handler,
+// as copies without a callback are faster and the log would only repeat the copy messages, and for
+// skills smaller than copyProgressMinSize.
+func (s *skillManagerImpl) copyProgress(ctx context.Context, skill, target, sourcePath string) func(n int64) {
+	if s.progressHandler == nil {
+		return nil
+	}
+	total, err := skillContentSize(sourcePath)
+	if err != nil || total < copyProgressMinSize {
+		return nil
+	}
+
+	var copied int64
+	reported := 0
+	return func(n int64) {
+		copied += n
+		// The last quarter is reported by the messages of the installed copy
+		if quarter := min(int(copied*4/total), 3); quarter > reported {
+			reported = quarter
+			s.emit(ctx, skill, PhaseCopy, target, "Copied %d%% of skill '%s' to %s", quarter*25, skill, target)
+		}
+	}
+}
+
+// debug logs a message at the debug level, which is only shown with --verbose.
+func (s *skillManagerImpl) debug(ctx context.Context, format string, args ...any) {
+	s.log(ctx, slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// log passes a record to the handler of the logger, or buffers it when ctx carries the
+// progress buffer of a skill.
+func (s *skillManagerImpl) log(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if !s.logger.Enabled(ctx, level) {
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, msg, 0)
+	record.Add(attrs...)
+
+	if out, ok := ctx.Value(skillOutputKey{}).(*skillOutput); ok {
+		out.add(record)
+		return
+	}
+	_ = s.logger.Handler().Handle(ctx, record)
+}
+
+// flush passes the records buffered in out to the handler of the logger as one block.
+func (s *skillManagerImpl) flush(ctx context.Context, out *skillOutput) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	for _, record := range out.drain() {
+		_ = s.logger.Handler().Handle(ctx, record)
+	}
+}
+
+// skillOutputKey is the context key of the progress buffer of a skill.
+type skillOutputKey struct{}
+
+// skillOutput buffers the log records of a skill.
+// It is safe for concurrent use, as install targets of a skill are processed in parallel.
+type skillOutput struct {
+	records []slog.Record
+	mu      sync.Mutex
+}
+
+func (o *skillOutput) add(record slog.Record) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.records = append(o.records, record)
+}
+
+// drain returns the buffered records and empties the buffer.
+func (o *skillOutput) drain() []slog.Record {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	records := o.records
+	o.records = nil
+	return records
+}
+
+// MessageHandler is a slog.Handler that writes the message of every record at or above its level
+// on its own line, leaving out the time, level, and attributes, for progress output meant for people.
+type MessageHandler struct {
+	w     io.Writer
+	level slog.Leveler
+	mu    *sync.Mutex
+}
+
+// NewMessageHandler creates a new MessageHandler that writes the messages of records at or above level to w.
+func NewMessageHandler(w io.Writer, level slog.Leveler) *MessageHandler {
+	return &MessageHandler{w: w, level: level, mu: &sync.Mutex{}}
+}
+
+// Enabled reports whether records at level are written.
+func (h *MessageHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes the message of the record.
+func (h *MessageHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, record.Message)
+	return err
+}
+
+// WithAttrs returns h, as attributes are not written.
+func (h *MessageHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+// WithGroup returns h, as attributes are not written.
+func (h *MessageHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ProjectTopic is a language, framework, or tool used by a project, such as "go" or "react".
+// Topics are used as search queries for skills relevant to the project.
+type ProjectTopic struct {
+	Name     string // Search query, e.g. "typescript"
+	Evidence string // File that revealed the topic, relative to the project directory
+}
+
+// projectMarker reveals a topic by the presence of a file or a directory.
+type projectMarker struct {
+	pattern string // Glob pattern relative to the project directory
+	topic   string
+}
+
+// projectMarkers are checked in order, so that the topics of the main language come first.
+var projectMarkers = []projectMarker{
+	{pattern: "go.mod", topic: "go"},
+	{pattern: "Cargo.toml", topic: "rust"},
+	{pattern: "package.json", topic: "javascript"},
+	{pattern: "tsconfig.json", topic: "typescript"},
+	{pattern: "pyproject.toml", topic: "python"},
+	{pattern: "requirements.txt", topic: "python"},
+	{pattern: "setup.py", topic: "python"},
+	{pattern: "Gemfile", topic: "ruby"},
+	{pattern: "pom.xml", topic: "java"},
+	{pattern: "build.gradle", topic: "java"},
+	{pattern: "build.gradle.kts", topic: "kotlin"},
+	{pattern: "Package.swift", topic: "swift"},
+	{pattern: "composer.json", topic: "php"},
+	{pattern: "*.csproj", topic: "dotnet"},
+	{pattern: "*.sln", topic: "dotnet"},
+	{pattern: "Dockerfile", topic: "docker"},
+	{pattern: "compose.yaml", topic: "docker"},
+	{pattern: "docker-compose.yml", topic: "docker"},
+	{pattern: "*.tf", topic: "terraform"},
+	{pattern: ".github/workflows", topic: "github-actions"},
+}
+
+// packageJSONTopics maps npm dependencies to the frameworks and tools they reveal.
+var packageJSONTopics = []struct {
+	dependency string
+	topic      string
+}{
+	{dependency: "typescript", topic: "typescript"},
+	{dependency: "react", topic: "react"},
+	{dependency: "next", topic: "nextjs"},
+	{dependency: "vue", topic: "vue"},
+	{dependency: "svelte", topic: "svelte"},
+	{dependency: "@angular/core", topic: "angular"},
+	{dependency: "express", topic: "express"},
+	{dependency: "tailwindcss", topic: "tailwind"},
+	{dependency: "@playwright/test", topic: "playwright"},
+	{dependency: "vitest", topic: "vitest"},
+	{dependency: "jest", topic: "jest"},
+}
+
+// pythonFrameworks are the Python frameworks recognized in requirements.txt and pyproject.toml.
+var pythonFrameworks = []string{"django", "fastapi", "flask", "pytest"}
+
+// DetectProjectTopics returns the languages, frameworks, and tools used by the project in dir,
+// judging by well-known files at its root. Each topic is reported once, with the first file that revealed it.
+func DetectProjectTopics(dir string) ([]*ProjectTopic, error) {
+	var topics []*ProjectTopic
+	add := func(name, evidence string) {
+		if !slices.ContainsFunc(topics, func(t *ProjectTopic) bool { return t.Name == name }) {
+			topics = append(topics, &ProjectTopic{Name: name, Evidence: evidence})
+		}
+	}
+
+	for _, marker := range projectMarkers {
+		matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(marker.pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid project marker %s: %w", marker.pattern, err)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		evidence, err := filepath.Rel(dir, matches[0])
+		if err != nil {
+			evidence = matches[0]
+		}
+		add(marker.topic, filepath.ToSlash(evidence))
+	}
+
+	dependencies, err := packageJSONDependencies(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range packageJSONTopics {
+		if _, ok := dependencies[t.dependency]; ok {
+			add(t.topic, "package.json")
+		}
+	}
+
+	for _, file := range []string{"requirements.txt", "pyproject.toml"} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		content := strings.ToLower(string(data))
+		for _, framework := range pythonFrameworks {
+			if strings.Contains(content, framework) {
+				add(framework, file)
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "Gemfile")); err == nil && strings.Contains(string(data), "rails") {
+		add("rails", "Gemfile")
+	}
+
+	return topics, nil
+}
+
+// packageJSONDependencies returns the dependencies and devDependencies of a package.json.
+// A missing file has no dependencies.
+func packageJSONDependencies(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	dependencies := make(map[string]string, len(pkg.Dependencies)+len(pkg.DevDependencies))
+	maps.Copy(dependencies, pkg.Dependencies)
+	maps.Copy(dependencies, pkg.DevDependencies)
+	return dependencies, nil
+}
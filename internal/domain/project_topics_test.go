@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectProjectTopics(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                   "module example.com/app\n",
+		"package.json":             `{"dependencies":{"react":"^19.0.0"},"devDependencies":{"typescript":"^5.0.0"}}`,
+		"requirements.txt":         "Django==5.0\n",
+		"main.tf":                  "",
+		".github/workflows/ci.yml": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	topics, err := DetectProjectTopics(dir)
+	if err != nil {
+		t.Fatalf("DetectProjectTopics() error = %v", err)
+	}
+
+	want := []ProjectTopic{
+		{Name: "go", Evidence: "go.mod"},
+		{Name: "javascript", Evidence: "package.json"},
+		{Name: "python", Evidence: "requirements.txt"},
+		{Name: "terraform", Evidence: "main.tf"},
+		{Name: "github-actions", Evidence: ".github/workflows"},
+		{Name: "typescript", Evidence: "package.json"},
+		{Name: "react", Evidence: "package.json"},
+		{Name: "django", Evidence: "requirements.txt"},
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("DetectProjectTopics() = %d topics, want %d: %v", len(topics), len(want), topics)
+	}
+	for i, topic := range topics {
+		if *topic != want[i] {
+			t.Errorf("topics[%d] = %+v, want %+v", i, *topic, want[i])
+		}
+	}
+
+	if topics, err := DetectProjectTopics(t.TempDir()); err != nil || len(topics) != 0 {
+		t.Errorf("DetectProjectTopics() of an empty directory = %v, %v", topics, err)
+	}
+}
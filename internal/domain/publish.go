@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// Limits of the SKILL.md frontmatter fields, as set by the Agent Skills specification.
+const (
+	maxSkillNameLength          = 64
+	maxSkillDescriptionLength   = 1024
+	maxSkillCompatibilityLength = 500
+)
+
+// skillNamePattern matches skill names: lowercase letters and digits in words separated by single hyphens.
+var skillNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// SkillFrontmatter is the YAML frontmatter of SKILL.md.
+type SkillFrontmatter struct {
+	Name          string             `yaml:"name"`
+	Description   string             `yaml:"description"`
+	License       string             `yaml:"license"`
+	Compatibility string             `yaml:"compatibility"`
+	Metadata      map[string]string  `yaml:"metadata"`
+	Agents        []string           `yaml:"agents"`        // Agents the skill supports, see ReadAgentRequirements
+	AllowedTools  ToolList           `yaml:"allowed-tools"` // Tools the skill may use without asking
+	DependsOn     []*SkillDependency `yaml:"depends_on"`    // Skills this skill builds on, see ReadSkillDependencies
+}
+
+// SkillManifest describes a published skill archive. It is written next to the archive, so that
+// consumers can check the archive and what it installs before adding the skill.
+type SkillManifest struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	License     string   `json:"license,omitempty"`
+	Hash        string   `json:"hash"`   // Content hash of the skill files, as recorded in hash_value when installed
+	SHA256      string   `json:"sha256"` // Hex SHA-256 digest of the archive, as checked by the sha256 field of archive sources
+	Files       []string `json:"files"`
+}
+
+// ReadSkillFrontmatter checks that dir is a skill as the Agent Skills specification defines it and
+// returns its metadata: SKILL.md must start with YAML frontmatter whose name matches the directory
+// name and whose description is set, within the lengths the specification allows.
+// Every problem found is reported at once in an ErrorMalformedSkill.
+func ReadSkillFrontmatter(dir string) (*SkillFrontmatter, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve skill directory %s: %w", dir, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(absDir, "SKILL.md"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &ErrorMalformedSkill{Dir: dir, Problems: []string{"SKILL.md is missing"}}
+		}
+		return nil, fmt.Errorf("failed to read SKILL.md in %s: %w", dir, err)
+	}
+
+	frontmatter, ok := skillFrontmatter(content)
+	if !ok {
+		return nil, &ErrorMalformedSkill{Dir: dir, Problems: []string{"SKILL.md does not start with YAML frontmatter between '---' lines"}}
+	}
+
+	var metadata SkillFrontmatter
+	if err := yaml.Unmarshal(frontmatter, &metadata); err != nil {
+		return nil, &ErrorMalformedSkill{Dir: dir, Problems: []string{fmt.Sprintf("the frontmatter of SKILL.md is not valid: %v", err)}}
+	}
+
+	var problems []string
+	dirName := filepath.Base(absDir)
+	switch {
+	case metadata.Name == "":
+		problems = append(problems, "name is missing")
+	case len(metadata.Name) > maxSkillNameLength:
+		problems = append(problems, fmt.Sprintf("name is longer than %d characters", maxSkillNameLength))
+	case !skillNamePattern.MatchString(metadata.Name):
+		problems = append(problems, fmt.Sprintf("name %q must be lowercase letters, digits, and single hyphens between them", metadata.Name))
+	case metadata.Name != dirName:
+		problems = append(problems, fmt.Sprintf("name %q does not match the directory name %q", metadata.Name, dirName))
+	}
+	switch {
+	case metadata.Description == "":
+		problems = append(problems, "description is missing")
+	case len([]rune(metadata.Description)) > maxSkillDescriptionLength:
+		problems = append(problems, fmt.Sprintf("description is longer than %d characters", maxSkillDescriptionLength))
+	}
+	if len([]rune(metadata.Compatibility)) > maxSkillCompatibilityLength {
+		problems = append(problems, fmt.Sprintf("compatibility is longer than %d characters", maxSkillCompatibilityLength))
+	}
+	if len(problems) > 0 {
+		return nil, &ErrorMalformedSkill{Dir: dir, Problems: problems}
+	}
+
+	return &metadata, nil
+}
+
+// BuildSkillArtifact checks the skill in dir and packs it for publishing into outDir: a reproducible
+// archive, <name>-<version>.tar.gz, with the files under a directory named after the skill, and its
+// manifest, <name>-<version>.json. Files excluded by the skill's ignore files are left out, and the
+// content hash is calculated over the files in the archive.
+func BuildSkillArtifact(ctx context.Context, dir, version, outDir string, hashService port.HashService) (*port.PublishArtifact, error) {
+	metadata, err := ReadSkillFrontmatter(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The files are staged, so that the hash and the archive cover exactly the files that are published
+	staging, err := os.MkdirTemp("", "skills-pkg-publish-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+	stagedDir := filepath.Join(staging, metadata.Name)
+	if err := CopyDir(dir, stagedDir, nil); err != nil {
+		return nil, fmt.Errorf("failed to copy skill %s: %w", dir, err)
+	}
+
+	files, err := ListSkillFiles(stagedDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", dir, err)
+	}
+	hashResult, err := hashService.CalculateHash(ctx, stagedDir, port.HashAlgorithmH1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate hash for %s: %w", dir, err)
+	}
+
+	if err := os.MkdirAll(outDir, installDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", outDir, err)
+	}
+	base := filepath.Join(outDir, fmt.Sprintf("%s-%s", metadata.Name, version))
+	archive := base + ".tar.gz"
+	digest, err := writeSkillArchive(archive, stagedDir, metadata.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &SkillManifest{
+		Name:        metadata.Name,
+		Version:     version,
+		Description: metadata.Description,
+		License:     metadata.License,
+		Hash:        hashResult.Value,
+		SHA256:      digest,
+		Files:       files,
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	manifestPath := base + ".json"
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest %s: %w", manifestPath, err)
+	}
+
+	return &port.PublishArtifact{
+		Name:        metadata.Name,
+		Description: metadata.Description,
+		Version:     version,
+		Dir:         dir,
+		Archive:     archive,
+		Manifest:    manifestPath,
+		Hash:        hashResult.Value,
+		SHA256:      digest,
+	}, nil
+}
+
+// writeSkillArchive writes the reproducible archive of the skill in dir to path, with its files under
+// a directory named prefix, and returns the hex SHA-256 digest of the archive.
+func writeSkillArchive(path, dir, prefix string) (digest string, err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close archive %s: %w", path, closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	hash := sha256.New()
+	if err := PackSkill(io.MultiWriter(f, hash), dir, PackOptions{Prefix: prefix, Reproducible: true}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadSkillFrontmatter(t *testing.T) {
+	tests := []struct {
+		name         string
+		dir          string
+		skillMD      string
+		wantProblems []string
+	}{
+		{
+			name:    "valid skill",
+			dir:     "code-review",
+			skillMD: "---\nname: code-review\ndescription: Reviews code.\nlicense: MIT\n---\n# Code review\n",
+		},
+		{
+			name:         "missing SKILL.md",
+			dir:          "code-review",
+			wantProblems: []string{"SKILL.md is missing"},
+		},
+		{
+			name:         "no frontmatter",
+			dir:          "code-review",
+			skillMD:      "# Code review\n",
+			wantProblems: []string{"does not start with YAML frontmatter"},
+		},
+		{
+			name:         "every problem is reported",
+			dir:          "code-review",
+			skillMD:      "---\nname: Code_Review\n---\n",
+			wantProblems: []string{"must be lowercase letters", "description is missing"},
+		},
+		{
+			name:         "name does not match the directory",
+			dir:          "review",
+			skillMD:      "---\nname: code-review\ndescription: Reviews code.\n---\n",
+			wantProblems: []string{`does not match the directory name "review"`},
+		},
+		{
+			name:         "description too long",
+			dir:          "code-review",
+			skillMD:      "---\nname: code-review\ndescription: " + strings.Repeat("a", maxSkillDescriptionLength+1) + "\n---\n",
+			wantProblems: []string{"description is longer than 1024 characters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), tt.dir)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.skillMD != "" {
+				if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(tt.skillMD), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			metadata, err := ReadSkillFrontmatter(dir)
+			if len(tt.wantProblems) == 0 {
+				if err != nil {
+					t.Fatalf("ReadSkillFrontmatter() error = %v", err)
+				}
+				if metadata.Name != "code-review" || metadata.Description != "Reviews code." || metadata.License != "MIT" {
+					t.Errorf("ReadSkillFrontmatter() = %+v", metadata)
+				}
+				return
+			}
+
+			e, ok := errors.AsType[*ErrorMalformedSkill](err)
+			if !ok {
+				t.Fatalf("ReadSkillFrontmatter() error = %v, want ErrorMalformedSkill", err)
+			}
+			if len(e.Problems) != len(tt.wantProblems) {
+				t.Fatalf("Problems = %q, want %d problems", e.Problems, len(tt.wantProblems))
+			}
+			for i, want := range tt.wantProblems {
+				if !strings.Contains(e.Problems[i], want) {
+					t.Errorf("Problems[%d] = %q, want it to contain %q", i, e.Problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildSkillArtifact(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "code-review")
+	for name, content := range map[string]string{
+		"SKILL.md":          "---\nname: code-review\ndescription: Reviews code.\n---\n# Code review\n",
+		"scripts/lint.sh":   "echo lint\n",
+		"notes/draft.md":    "draft\n",
+		".skillignore":      "notes/\n",
+		"references/api.md": "api\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outDir := filepath.Join(t.TempDir(), "dist")
+
+	artifact, err := BuildSkillArtifact(context.Background(), dir, "v1.0.0", outDir, &mockHashService{})
+	if err != nil {
+		t.Fatalf("BuildSkillArtifact() error = %v", err)
+	}
+	if artifact.Name != "code-review" || artifact.Version != "v1.0.0" || artifact.Hash != "mockHash123" {
+		t.Errorf("BuildSkillArtifact() = %+v", artifact)
+	}
+	if artifact.Archive != filepath.Join(outDir, "code-review-v1.0.0.tar.gz") {
+		t.Errorf("Archive = %s", artifact.Archive)
+	}
+
+	archive, err := os.ReadFile(artifact.Archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); got != artifact.SHA256 {
+		t.Errorf("SHA256 = %s, want the digest of the archive %s", artifact.SHA256, got)
+	}
+
+	gz, err := gzip.NewReader(strings.NewReader(string(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			entries = append(entries, header.Name)
+		}
+	}
+	slices.Sort(entries)
+	if !slices.Contains(entries, "code-review/SKILL.md") || !slices.Contains(entries, "code-review/scripts/lint.sh") {
+		t.Errorf("archive entries = %q, want the files under code-review/", entries)
+	}
+	if slices.Contains(entries, "code-review/notes/draft.md") {
+		t.Errorf("archive entries = %q, want ignored files left out", entries)
+	}
+
+	data, err := os.ReadFile(artifact.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest SkillManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Name != "code-review" || manifest.SHA256 != artifact.SHA256 || manifest.Hash != "mockHash123" {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if slices.Contains(manifest.Files, "notes/draft.md") || !slices.Contains(manifest.Files, "SKILL.md") {
+		t.Errorf("manifest files = %q", manifest.Files)
+	}
+}
+
+func TestBuildSkillArtifact_MalformedSkill(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "code-review")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	outDir := filepath.Join(t.TempDir(), "dist")
+
+	_, err := BuildSkillArtifact(context.Background(), dir, "v1.0.0", outDir, &mockHashService{})
+	if code := CodeOf(err); code == nil || code.Code != "SKP1506" {
+		t.Errorf("BuildSkillArtifact() error = %v, want code SKP1506", err)
+	}
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Errorf("output directory was created for a malformed skill")
+	}
+}
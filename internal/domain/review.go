@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ReviewFileName is the name of the review file written next to .skillspkg.lock.
+const ReviewFileName = ".skillspkg.reviews"
+
+// reviewFileVersion is the format version written to new review files.
+const reviewFileVersion = 1
+
+// TrustLevel is the outcome of reviewing a version of a skill.
+type TrustLevel string
+
+const (
+	TrustUnreviewed TrustLevel = "unreviewed" // No one has reviewed the version yet
+	TrustApproved   TrustLevel = "approved"   // The version may be installed
+	TrustBlocked    TrustLevel = "blocked"    // The version must never be installed
+)
+
+// ReviewFile records which versions of skills have been approved or blocked.
+// Unlike the lock file, it is meant to be committed, so that a team shares its reviews.
+type ReviewFile struct {
+	Reviews []*Review `toml:"reviews"`
+	Version int       `toml:"version"`
+}
+
+// Review is the trust decided for a version of a skill.
+type Review struct {
+	ReviewedAt time.Time  `toml:"reviewed_at"`
+	Skill      string     `toml:"skill"`
+	Version    string     `toml:"version"`
+	Trust      TrustLevel `toml:"trust"`
+	HashValue  string     `toml:"hash_value,omitempty"` // Hash of the reviewed content, when it was known
+	Reviewer   string     `toml:"reviewer,omitempty"`
+	Note       string     `toml:"note,omitempty"`
+}
+
+// Find returns the review of the version of the skill, or nil if it has none.
+func (f *ReviewFile) Find(skill, version string) *Review {
+	for _, review := range f.Reviews {
+		if review.Skill == skill && review.Version == version {
+			return review
+		}
+	}
+	return nil
+}
+
+// Record stores the review, replacing any previous review of the same version of the skill.
+func (f *ReviewFile) Record(review *Review) {
+	f.Reviews = slices.DeleteFunc(f.Reviews, func(r *Review) bool {
+		return r.Skill == review.Skill && r.Version == review.Version
+	})
+	f.Reviews = append(f.Reviews, review)
+}
+
+// ReviewManager reads and writes the review file.
+type ReviewManager struct {
+	reviewPath string
+	mu         sync.Mutex
+}
+
+// NewReviewManager creates a new ReviewManager for the review file at reviewPath.
+func NewReviewManager(reviewPath string) *ReviewManager {
+	return &ReviewManager{reviewPath: reviewPath}
+}
+
+// ReviewPathFor returns the path of the review file belonging to the configuration file at configPath.
+// Like lock files, configuration files with another name than .skillspkg.toml have a review file of
+// the same name with the .reviews extension.
+func ReviewPathFor(configPath string) string {
+	dir, name := filepath.Split(configPath)
+	if name == ConfigFileName {
+		return filepath.Join(dir, ReviewFileName)
+	}
+	return filepath.Join(dir, strings.TrimSuffix(name, filepath.Ext(name))+".reviews")
+}
+
+// Load reads the review file. A missing review file is treated as empty.
+func (m *ReviewManager) Load(ctx context.Context) (*ReviewFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.load()
+}
+
+// Update loads the review file, applies fn, and writes the result back.
+func (m *ReviewManager) Update(ctx context.Context, fn func(reviews *ReviewFile)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reviews, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	fn(reviews)
+	reviews.Version = reviewFileVersion
+
+	data, err := toml.Marshal(reviews)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review file: %w", err)
+	}
+	if err := os.WriteFile(m.reviewPath, data, configFileMode); err != nil {
+		return fmt.Errorf("failed to write review file to %s: %w. Check file permissions and directory existence", m.reviewPath, err)
+	}
+
+	return nil
+}
+
+func (m *ReviewManager) load() (*ReviewFile, error) {
+	data, err := os.ReadFile(m.reviewPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &ReviewFile{Version: reviewFileVersion}, nil
+		}
+		return nil, fmt.Errorf("failed to read review file at %s: %w. Check file permissions", m.reviewPath, err)
+	}
+
+	var reviews ReviewFile
+	if err := toml.Unmarshal(data, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to parse review file at %s: %w", m.reviewPath, err)
+	}
+
+	return &reviews, nil
+}
+
+// checkReview refuses to install a version of the skill that is blocked, or, with require_review,
+// that has not been approved. An approval recorded with a hash only holds for the same content,
+// so that a version whose upstream content changed after the review has to be reviewed again.
+func (s *skillManagerImpl) checkReview(ctx context.Context, config *Config, sourcePath string, skill *Skill, version string) error {
+	reviews, err := s.reviewManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	review := reviews.Find(skill.Name, version)
+	switch {
+	case review != nil && review.Trust == TrustBlocked:
+		return &ErrorNotApproved{SkillName: skill.Name, Version: version, Trust: TrustBlocked, Reason: review.Note}
+	case !config.RequireReview:
+		return nil
+	case review == nil || review.Trust != TrustApproved:
+		return &ErrorNotApproved{SkillName: skill.Name, Version: version, Trust: TrustUnreviewed}
+	case review.HashValue == "":
+		return nil
+	}
+
+	hashResult, err := s.hashService.CalculateHash(ctx, sourcePath, port.HashAlgorithmOf(review.HashValue), config.HashOptions(skill)...)
+	if err != nil {
+		return fmt.Errorf("failed to calculate hash for skill '%s': %w", skill.Name, err)
+	}
+	if hashResult.Value != review.HashValue {
+		return &ErrorNotApproved{SkillName: skill.Name, Version: version, Trust: TrustUnreviewed, Reason: fmt.Sprintf("its content changed after it was approved (hash %s, approved %s)", hashResult.Value, review.HashValue)}
+	}
+
+	return nil
+}
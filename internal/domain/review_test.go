@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+func TestInstall_RequireReview(t *testing.T) {
+	tests := []struct {
+		review        *Review
+		name          string
+		requireReview bool
+		wantErr       bool
+	}{
+		{
+			name: "unreviewed version without require_review",
+		},
+		{
+			name:          "unreviewed version with require_review",
+			requireReview: true,
+			wantErr:       true,
+		},
+		{
+			name:          "approved version",
+			review:        &Review{Trust: TrustApproved, HashValue: "abcd1234"},
+			requireReview: true,
+		},
+		{
+			name:          "approved version without a hash",
+			review:        &Review{Trust: TrustApproved},
+			requireReview: true,
+		},
+		{
+			name:          "content changed after approval",
+			review:        &Review{Trust: TrustApproved, HashValue: "efgh5678"},
+			requireReview: true,
+			wantErr:       true,
+		},
+		{
+			name:    "blocked version without require_review",
+			review:  &Review{Trust: TrustBlocked, Note: "compromised"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tmpDir := t.TempDir()
+			configPath := tmpDir + "/.skillspkg.toml"
+			installDir := tmpDir + "/install"
+			downloadDir := tmpDir + "/download"
+			if err := os.MkdirAll(downloadDir, 0o755); err != nil {
+				t.Fatalf("Failed to create download directory: %v", err)
+			}
+
+			configManager := NewConfigManager(configPath)
+			if err := configManager.Save(ctx, &Config{
+				Skills:         []*Skill{{Name: "test-skill", Source: "git", URL: "https://github.com/example/skill.git", Version: "v1.0.0"}},
+				InstallTargets: []string{installDir},
+				RequireReview:  tt.requireReview,
+			}); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+			if tt.review != nil {
+				if err := NewReviewManager(ReviewPathFor(configPath)).Update(ctx, func(reviews *ReviewFile) {
+					review := *tt.review
+					review.Skill = "test-skill"
+					review.Version = "v1.0.0"
+					reviews.Record(&review)
+				}); err != nil {
+					t.Fatalf("Failed to write review file: %v", err)
+				}
+			}
+
+			pm := &mockPackageManagerWithDownload{
+				sourceType:     "git",
+				downloadResult: &port.DownloadResult{Path: downloadDir, Version: "v1.0.0"},
+			}
+			hashService := &mockHashServiceWithCustom{hashResult: &port.HashResult{Value: "abcd1234"}}
+			skillManager := NewSkillManager(configManager, hashService, []port.PackageManager{pm})
+
+			err := skillManager.Install(ctx, "test-skill")
+			if tt.wantErr != isErrorType[*ErrorNotApproved](err) {
+				t.Errorf("Install() error = %v, want ErrorNotApproved: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Install() error = %v", err)
+			}
+			if _, statErr := os.Stat(installDir + "/test-skill"); (statErr == nil) == tt.wantErr {
+				t.Errorf("skill installed = %v, want %v", statErr == nil, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReviewFile_Record(t *testing.T) {
+	reviews := &ReviewFile{}
+	reviews.Record(&Review{Skill: "a", Version: "v1.0.0", Trust: TrustApproved})
+	reviews.Record(&Review{Skill: "a", Version: "v2.0.0", Trust: TrustApproved})
+	reviews.Record(&Review{Skill: "a", Version: "v1.0.0", Trust: TrustBlocked})
+
+	if len(reviews.Reviews) != 2 {
+		t.Errorf("reviews = %d, want the review of v1.0.0 to be replaced", len(reviews.Reviews))
+	}
+	if review := reviews.Find("a", "v1.0.0"); review == nil || review.Trust != TrustBlocked {
+		t.Errorf("Find(a, v1.0.0) = %+v, want blocked", review)
+	}
+	if review := reviews.Find("b", "v1.0.0"); review != nil {
+		t.Errorf("Find(b, v1.0.0) = %+v, want nil", review)
+	}
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ConfigFileName is the name of the project configuration file.
+const ConfigFileName = ".skillspkg.toml"
+
+// scanSkipDirs are directories that never hold project configurations of their own.
+var scanSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// ScanReport aggregates the skills configured by the projects under a directory.
+type ScanReport struct {
+	Projects []*ScannedProject `json:"projects"`
+	Skills   []*ScannedSkill   `json:"skills"`
+	Errors   []*ScanError      `json:"errors,omitempty"`
+}
+
+// ScannedProject is a configuration file found by the scan and the skills it configures.
+type ScannedProject struct {
+	Path   string          `json:"path"` // Configuration file, relative to the scanned directory
+	Skills []*ProjectSkill `json:"skills"`
+}
+
+// ProjectSkill is a skill as configured by a single project.
+type ProjectSkill struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	URL     string `json:"url"`
+	Version string `json:"version"`
+}
+
+// ScannedSkill is a skill source used by one or more projects, with the versions in use.
+type ScannedSkill struct {
+	Name     string                 `json:"name"`
+	Source   string                 `json:"source"`
+	URL      string                 `json:"url"`
+	Versions []*ScannedSkillVersion `json:"versions"`
+}
+
+// ScannedSkillVersion is a version of a skill and the projects that pin it.
+type ScannedSkillVersion struct {
+	Version  string   `json:"version"`
+	Projects []string `json:"projects"`
+}
+
+// ScanError is a configuration file that could not be loaded.
+type ScanError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// ScanProjects walks root and aggregates the skills of every configuration file found.
+// Configuration files that cannot be loaded are recorded in the report instead of failing the scan,
+// so that a single broken repository does not hide the rest of the inventory.
+func ScanProjects(ctx context.Context, root string) (*ScanReport, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	report := &ScanReport{}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are reported and skipped
+			report.Errors = append(report.Errors, &ScanError{Path: relPath(root, path), Error: err.Error()})
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if d.IsDir() {
+			if path != root && scanSkipDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != ConfigFileName || !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel := relPath(root, path)
+		config, err := NewConfigManager(path).Load(ctx)
+		if err != nil {
+			report.Errors = append(report.Errors, &ScanError{Path: rel, Error: err.Error()})
+			return nil
+		}
+
+		project := &ScannedProject{Path: rel, Skills: []*ProjectSkill{}}
+		for _, skill := range config.InstalledSkills() {
+			project.Skills = append(project.Skills, &ProjectSkill{
+				Name:    skill.Name,
+				Source:  skill.Source,
+				URL:     skill.URL,
+				Version: skill.Version,
+			})
+		}
+		report.Projects = append(report.Projects, project)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Skills = aggregateScannedSkills(report.Projects)
+	if report.Projects == nil {
+		report.Projects = []*ScannedProject{}
+	}
+
+	return report, nil
+}
+
+// aggregateScannedSkills groups the skills of the projects by name and source,
+// sorted by name with versions sorted by version string.
+func aggregateScannedSkills(projects []*ScannedProject) []*ScannedSkill {
+	type skillKey struct{ name, source, url string }
+
+	skills := make(map[skillKey]*ScannedSkill)
+	for _, project := range projects {
+		for _, skill := range project.Skills {
+			key := skillKey{name: skill.Name, source: skill.Source, url: skill.URL}
+			scanned, ok := skills[key]
+			if !ok {
+				scanned = &ScannedSkill{Name: skill.Name, Source: skill.Source, URL: skill.URL}
+				skills[key] = scanned
+			}
+
+			i := slices.IndexFunc(scanned.Versions, func(v *ScannedSkillVersion) bool { return v.Version == skill.Version })
+			if i < 0 {
+				scanned.Versions = append(scanned.Versions, &ScannedSkillVersion{Version: skill.Version})
+				i = len(scanned.Versions) - 1
+			}
+			scanned.Versions[i].Projects = append(scanned.Versions[i].Projects, project.Path)
+		}
+	}
+
+	result := make([]*ScannedSkill, 0, len(skills))
+	for _, skill := range skills {
+		slices.SortFunc(skill.Versions, func(a, b *ScannedSkillVersion) int { return cmp.Compare(a.Version, b.Version) })
+		result = append(result, skill)
+	}
+	slices.SortFunc(result, func(a, b *ScannedSkill) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Source, b.Source), cmp.Compare(a.URL, b.URL))
+	})
+
+	return result
+}
+
+// relPath returns path relative to root with forward slashes, or path itself when it is not under root.
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestScanProjects(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeProject := func(dir string, skills ...*domain.Skill) {
+		t.Helper()
+		cm := domain.NewConfigManager(filepath.Join(root, dir, domain.ConfigFileName))
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := cm.Initialize(context.Background(), nil); err != nil {
+			t.Fatalf("failed to initialize config: %v", err)
+		}
+		for _, skill := range skills {
+			if err := cm.AddSkill(context.Background(), skill); err != nil {
+				t.Fatalf("failed to add skill: %v", err)
+			}
+		}
+	}
+
+	writeProject("repo-a", &domain.Skill{Name: "shared", Source: "git", URL: "https://github.com/example/shared.git", Version: "v1.0.0"})
+	writeProject("group/repo-b",
+		&domain.Skill{Name: "shared", Source: "git", URL: "https://github.com/example/shared.git", Version: "v1.1.0"},
+		&domain.Skill{Name: "only-b", Source: "go-mod", URL: "github.com/example/only-b", Version: "v0.1.0"},
+	)
+	// Configurations in dependency directories are not projects of their own
+	writeProject("repo-a/node_modules/pkg", &domain.Skill{Name: "ignored", Source: "git", URL: "https://github.com/example/ignored.git", Version: "v1.0.0"})
+	// Broken configurations are reported without failing the scan
+	if err := os.MkdirAll(filepath.Join(root, "broken"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "broken", domain.ConfigFileName), []byte("skills = ["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := domain.ScanProjects(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanProjects() error = %v", err)
+	}
+
+	if len(report.Projects) != 2 {
+		t.Fatalf("len(Projects) = %d, want 2", len(report.Projects))
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Path != "broken/"+domain.ConfigFileName {
+		t.Errorf("Errors = %+v, want the broken configuration", report.Errors)
+	}
+
+	if len(report.Skills) != 2 {
+		t.Fatalf("len(Skills) = %d, want 2", len(report.Skills))
+	}
+	if got := report.Skills[0].Name; got != "only-b" {
+		t.Errorf("Skills[0].Name = %q, want %q", got, "only-b")
+	}
+	shared := report.Skills[1]
+	if len(shared.Versions) != 2 || shared.Versions[0].Version != "v1.0.0" || shared.Versions[1].Version != "v1.1.0" {
+		t.Fatalf("shared versions = %+v, want v1.0.0 and v1.1.0", shared.Versions)
+	}
+	if got := shared.Versions[1].Projects; len(got) != 1 || got[0] != "group/repo-b/"+domain.ConfigFileName {
+		t.Errorf("projects of shared v1.1.0 = %v, want [group/repo-b/%s]", got, domain.ConfigFileName)
+	}
+}
+
+func TestScanProjects_NotADirectory(t *testing.T) {
+	t.Parallel()
+
+	if _, err := domain.ScanProjects(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ScanProjects() of a missing directory should fail")
+	}
+}
@@ -0,0 +1,420 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Prefixes of the text forms of secret keys and recipients.
+const (
+	SecretKeyPrefix = "SKILLSPKG-SECRET-KEY-"
+	RecipientPrefix = "skillspkg-pub-"
+)
+
+// Environment variables that provide the secret keys decrypting configuration values.
+const (
+	SecretKeyEnv     = "SKILLSPKG_SECRET_KEY"      // Secret keys separated by whitespace, e.g. from a CI secret
+	SecretKeyFileEnv = "SKILLSPKG_SECRET_KEY_FILE" // File of secret keys, replacing the user key file
+)
+
+// encryptedPrefix and encryptedSuffix enclose encrypted configuration values. The first field inside
+// is the version of the format, which changes whenever values are encrypted differently, so that a
+// version of skills-pkg that does not know the format reports it instead of misreading the value.
+const (
+	encryptedPrefix  = "ENC["
+	encryptedSuffix  = "]"
+	encryptedVersion = "v1"
+	secretKeySize    = 32
+	secretNonceSize  = 24
+	encryptedDataKey = secretKeySize + box.AnonymousOverhead
+)
+
+// SecretKey decrypts configuration values encrypted to its recipient.
+type SecretKey struct {
+	public  [secretKeySize]byte
+	private [secretKeySize]byte
+}
+
+// GenerateSecretKey returns a new random secret key.
+func GenerateSecretKey() (*SecretKey, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	return &SecretKey{public: *public, private: *private}, nil
+}
+
+// ParseSecretKey parses the text form of a secret key, as returned by String.
+func ParseSecretKey(s string) (*SecretKey, error) {
+	private, ok := parseKey(s, SecretKeyPrefix)
+	if !ok {
+		return nil, errors.New("invalid secret key: it must be a key generated by 'skills-pkg keygen'")
+	}
+	key := &SecretKey{private: private}
+	public, err := publicKeyOf(&key.private)
+	if err != nil {
+		return nil, err
+	}
+	key.public = public
+	return key, nil
+}
+
+// String returns the text form of the secret key.
+func (k *SecretKey) String() string {
+	return SecretKeyPrefix + base64.RawURLEncoding.EncodeToString(k.private[:])
+}
+
+// Recipient returns the text form of the public key that values are encrypted to for this key.
+func (k *SecretKey) Recipient() string {
+	return RecipientPrefix + base64.RawURLEncoding.EncodeToString(k.public[:])
+}
+
+// parseKey decodes a 32-byte key in the text form with the prefix.
+func parseKey(s, prefix string) ([secretKeySize]byte, bool) {
+	var key [secretKeySize]byte
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(s), prefix)
+	if !ok {
+		return key, false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) != secretKeySize {
+		return key, false
+	}
+	copy(key[:], decoded)
+	return key, true
+}
+
+// publicKeyOf returns the public key of the private key.
+func publicKeyOf(private *[secretKeySize]byte) ([secretKeySize]byte, error) {
+	var public [secretKeySize]byte
+	derived, err := curve25519.X25519(private[:], curve25519.Basepoint)
+	if err != nil {
+		return public, fmt.Errorf("invalid secret key: %w", err)
+	}
+	copy(public[:], derived)
+	return public, nil
+}
+
+// ValidateRecipient returns ErrorInvalidRecipient unless s is the text form of a recipient.
+func ValidateRecipient(s string) error {
+	if _, ok := parseKey(s, RecipientPrefix); !ok {
+		return &ErrorInvalidRecipient{Recipient: s}
+	}
+	return nil
+}
+
+// IsEncrypted reports whether the configuration value was encrypted with EncryptValue.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix) && strings.HasSuffix(value, encryptedSuffix)
+}
+
+// EncryptValue encrypts plaintext so that the secret key of any of the recipients can decrypt it.
+// The result can be used in place of the plaintext in .skillspkg.toml.
+//
+// A random data key encrypts the value with secretbox, and is sealed to each recipient with an
+// anonymous NaCl box, so the value can be shared by a team without sharing a single secret key.
+func EncryptValue(plaintext string, recipients []string) (string, error) {
+	if len(recipients) == 0 {
+		return "", errors.New("no recipients to encrypt to: add a recipient from 'skills-pkg keygen' to recipients in .skillspkg.toml")
+	}
+
+	var dataKey [secretKeySize]byte
+	if _, err := rand.Read(dataKey[:]); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	fields := make([]string, 0, len(recipients)+1)
+	for _, recipient := range recipients {
+		public, ok := parseKey(recipient, RecipientPrefix)
+		if !ok {
+			return "", &ErrorInvalidRecipient{Recipient: recipient}
+		}
+		sealed, err := box.SealAnonymous(nil, dataKey[:], &public, rand.Reader)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt data key: %w", err)
+		}
+		fields = append(fields, base64.RawStdEncoding.EncodeToString(sealed))
+	}
+
+	var nonce [secretNonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	data := secretbox.Seal(nonce[:], []byte(plaintext), &nonce, &dataKey)
+	fields = append(fields, base64.RawStdEncoding.EncodeToString(data))
+
+	return encryptedPrefix + encryptedVersion + "," + strings.Join(fields, ",") + encryptedSuffix, nil
+}
+
+// DecryptValue returns the plaintext of a value encrypted with EncryptValue, using the first of
+// keys that it was encrypted to. Values that are not encrypted are returned unchanged.
+func DecryptValue(value string, keys []*SecretKey) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	fields := strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix), ",")
+	if fields[0] != encryptedVersion {
+		return "", fmt.Errorf("value is encrypted in format %s, which this version of skills-pkg cannot decrypt. Upgrade skills-pkg", fields[0])
+	}
+	fields = fields[1:]
+	if len(fields) < 2 {
+		return "", errors.New("malformed encrypted value")
+	}
+	data, err := base64.RawStdEncoding.DecodeString(fields[len(fields)-1])
+	if err != nil || len(data) < secretNonceSize+secretbox.Overhead {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	for _, field := range fields[:len(fields)-1] {
+		sealed, err := base64.RawStdEncoding.DecodeString(field)
+		if err != nil || len(sealed) != encryptedDataKey {
+			return "", errors.New("malformed encrypted value")
+		}
+		for _, key := range keys {
+			opened, ok := box.OpenAnonymous(nil, sealed, &key.public, &key.private)
+			if !ok {
+				continue
+			}
+
+			var dataKey [secretKeySize]byte
+			var nonce [secretNonceSize]byte
+			copy(dataKey[:], opened)
+			copy(nonce[:], data)
+			plaintext, ok := secretbox.Open(nil, data[secretNonceSize:], &nonce, &dataKey)
+			if !ok {
+				return "", errors.New("encrypted value was modified")
+			}
+			return string(plaintext), nil
+		}
+	}
+
+	return "", &ErrorNoSecretKey{}
+}
+
+// LoadSecretKeys returns the secret keys of the current user: those in SKILLSPKG_SECRET_KEY, and
+// those in the file named by SKILLSPKG_SECRET_KEY_FILE or, when it is not set, the user key file.
+// A missing key file yields no keys.
+func LoadSecretKeys() ([]*SecretKey, error) {
+	var keys []*SecretKey
+	for field := range strings.FieldsSeq(os.Getenv(SecretKeyEnv)) {
+		key, err := ParseSecretKey(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", SecretKeyEnv, err)
+		}
+		keys = append(keys, key)
+	}
+
+	dirs, err := ResolveUserDirs()
+	if err != nil {
+		return nil, err
+	}
+	fileKeys, err := readSecretKeyFile(dirs.SecretKeyFile())
+	if err != nil {
+		return nil, err
+	}
+
+	return append(keys, fileKeys...), nil
+}
+
+// readSecretKeyFile parses a file with a secret key on each line. Empty lines and lines starting with '#' are skipped.
+func readSecretKeyFile(path string) ([]*SecretKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read secret key file %s: %w", path, err)
+	}
+
+	var keys []*SecretKey
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, err := ParseSecretKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// decryptSecrets decrypts every encrypted string reachable from v through exported fields, slices,
+// and maps, in place, and returns the encrypted values by their plaintext. Unless strict is set,
+// values that none of the secret keys can decrypt are left encrypted, so that commands that do not
+// use them still work; using them reports ErrorNoSecretKey. The keys are only loaded when needed.
+func decryptSecrets(v any, strict bool) (map[string]string, error) {
+	secrets := map[string]string{}
+	var keys []*SecretKey
+	loaded := false
+	err := replaceStrings(reflect.ValueOf(v), func(value string) (string, error) {
+		if !IsEncrypted(value) {
+			return value, nil
+		}
+		if !loaded {
+			var err error
+			if keys, err = LoadSecretKeys(); err != nil {
+				return "", err
+			}
+			loaded = true
+		}
+		plaintext, err := DecryptValue(value, keys)
+		if err != nil {
+			if _, ok := errors.AsType[*ErrorNoSecretKey](err); ok && !strict {
+				return value, nil
+			}
+			return "", err
+		}
+		if plaintext != "" {
+			secrets[plaintext] = value
+		}
+		return plaintext, nil
+	})
+	return secrets, err
+}
+
+// replaceStrings replaces every settable string reachable from v through exported fields, slices,
+// and maps with the result of replace.
+func replaceStrings(v reflect.Value, replace func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return replaceStrings(v.Elem(), replace)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := replaceStrings(v.Field(i), replace); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := replaceStrings(v.Index(i), replace); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map elements cannot be set in place, so a copy is replaced and stored again
+		for iter := v.MapRange(); iter.Next(); {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := replaceStrings(elem, replace); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		replaced, err := replace(v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(replaced)
+	}
+	return nil
+}
+
+// Sealed returns the encrypted form of value when it is the plaintext of an encrypted value of the
+// configuration, and value otherwise, for the files and output that keep such values encrypted.
+func (c *Config) Sealed(value string) string {
+	if sealed, ok := c.secrets[value]; ok {
+		return sealed
+	}
+	return value
+}
+
+// SealedSkill returns the skill with its URL in the form Sealed returns, for the lock file and
+// the files written into installed skills.
+func (c *Config) SealedSkill(skill *Skill) *Skill {
+	url := c.Sealed(skill.URL)
+	if url == skill.URL {
+		return skill
+	}
+	sealed := *skill
+	sealed.URL = url
+	return &sealed
+}
+
+// Encrypt encrypts value to the recipients wherever it appears in the configuration when it is saved.
+func (c *Config) Encrypt(value string, recipients []string) error {
+	encrypted, err := EncryptValue(value, recipients)
+	if err != nil {
+		return err
+	}
+	if c.secrets == nil {
+		c.secrets = map[string]string{}
+	}
+	c.secrets[value] = encrypted
+	return nil
+}
+
+// ReencryptSecrets encrypts every encrypted value of the configuration again to the recipients, and
+// returns the number of values that are saved encrypted. It returns ErrorNoSecretKey when a value
+// could not be decrypted when the configuration was loaded.
+func (c *Config) ReencryptSecrets(recipients []string) (int, error) {
+	saved := map[string]bool{}
+	err := replaceStrings(reflect.ValueOf(c.withoutBases().withOtherPlatforms()), func(value string) (string, error) {
+		if IsEncrypted(value) {
+			return "", &ErrorNoSecretKey{}
+		}
+		if _, ok := c.secrets[value]; ok {
+			saved[value] = true
+		}
+		return value, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for plaintext := range saved {
+		if err := c.Encrypt(plaintext, recipients); err != nil {
+			return 0, err
+		}
+	}
+	return len(saved), nil
+}
+
+// sealSecrets replaces the plaintext of the encrypted values in config, a configuration to encode,
+// with the encrypted values, and returns the function that puts the plaintext back.
+func (c *Config) sealSecrets(config *Config) func() {
+	if len(c.secrets) == 0 {
+		return func() {}
+	}
+	plaintexts := make(map[string]string, len(c.secrets))
+	for plaintext, sealed := range c.secrets {
+		plaintexts[sealed] = plaintext
+	}
+	_ = replaceStrings(reflect.ValueOf(config), func(value string) (string, error) {
+		return c.Sealed(value), nil
+	})
+	return func() {
+		_ = replaceStrings(reflect.ValueOf(config), func(value string) (string, error) {
+			if plaintext, ok := plaintexts[value]; ok {
+				return plaintext, nil
+			}
+			return value, nil
+		})
+	}
+}
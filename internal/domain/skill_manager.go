@@ -3,12 +3,19 @@ package domain
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mazrean/skills-pkg/internal/port"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -35,11 +42,42 @@ type SkillManager interface {
 	InstallSingleSkill(ctx context.Context, config *Config, skill *Skill, saveConfig bool) error
 
 	// Update updates the specified skill. If skillNames is empty, updates all skills.
-	// When dryRun is true, only checks for available updates without applying changes.
-	Update(ctx context.Context, skillNames []string, dryRun bool) ([]*UpdateResult, error)
+	// opts filters the skills and limits version changes; when opts.DryRun is true,
+	// only checks for available updates without applying changes.
+	Update(ctx context.Context, skillNames []string, opts *UpdateOptions) ([]*UpdateResult, error)
+
+	// InstallDryRun returns the changes Install would make without changing anything.
+	// Skills that need to be installed are downloaded to report their size.
+	InstallDryRun(ctx context.Context, skillName string) ([]*DryRunChange, error)
 
 	// Uninstall removes the specified skill.
 	Uninstall(ctx context.Context, skillName string) error
+
+	// UninstallDryRun returns the changes Uninstall would make without changing anything.
+	UninstallDryRun(ctx context.Context, skillName string) ([]*DryRunChange, error)
+
+	// Prune removes installations recorded in the lock file that the configuration no longer contains.
+	Prune(ctx context.Context) ([]*PrunedInstall, error)
+
+	// PruneTarget removes the skills that skills-pkg installed into the install target,
+	// such as one that was just removed from the configuration.
+	PruneTarget(ctx context.Context, target string) ([]*PrunedInstall, error)
+
+	// MigrateTarget moves the skills that skills-pkg installed into the configured install target
+	// from to the directory to, and replaces the target in the configuration.
+	MigrateTarget(ctx context.Context, from, to string) ([]*PrunedInstall, error)
+
+	// Promote installs the canary versions recorded by Update into every install target.
+	// If skillNames is empty, promotes every skill with a canary.
+	Promote(ctx context.Context, skillNames []string) ([]*UpdateResult, error)
+
+	// Restore puts back the backup of the skill saved before it was last replaced or removed,
+	// or the newest backup of version when it is not empty, and returns the restored backups.
+	Restore(ctx context.Context, skillName, version string) ([]*Backup, error)
+
+	// Mirror downloads every pinned version of the configured skills into the directory dir,
+	// in the layout that WithMirror reads, and returns the versions of each skill.
+	Mirror(ctx context.Context, dir string) ([]*MirroredSkill, error)
 }
 
 // FileDiffStatus represents the change status of a file.
@@ -66,15 +104,194 @@ type UpdateResult struct {
 	OldVersion string      // Previous version
 	NewVersion string      // New version after update
 	FileDiffs  []*FileDiff // File-level diffs (populated in dry-run mode only)
+	Canary     string      // Install target that received NewVersion as a canary; empty when every target did
+	HeldBack   bool        // NewVersion exceeds UpdateOptions.MaxBump and was not applied
+}
+
+// PrunedInstall represents an installation removed by Prune, or moved by MigrateTarget.
+type PrunedInstall struct {
+	SkillName string // Name of the removed skill
+	Target    string // Install target the skill was removed from
+	Dir       string // Directory name of the skill in the target
 }
 
 // skillManagerImpl is the concrete implementation of SkillManager.
 // It integrates ConfigManager, HashService, and PackageManager implementations.
 // Requirements: 11.4, 11.5, 12.2, 12.3
 type skillManagerImpl struct {
-	configManager   *ConfigManager
-	hashService     port.HashService
-	packageManagers []port.PackageManager
+	configManager    *ConfigManager
+	hashService      port.HashService
+	lockManager      *LockManager
+	reviewManager    *ReviewManager
+	packageManagers  []port.PackageManager
+	remoteInstallers []port.RemoteInstaller
+	store            *Store
+	storeOnce        sync.Once
+	storeErr         error
+	downloadCache    *DownloadCache
+	mirror           *DownloadCache // Downloads written by Mirror, read before the download cache
+	backups          *Backups
+	downloads        map[string]*pendingDownload // Downloads of this SkillManager by source and version
+	downloadsMu      sync.Mutex
+	logger           *slog.Logger // Receives progress messages; info and above to os.Stdout by default
+	flushMu          sync.Mutex   // Keeps the buffered messages of a skill together
+	progressHandler  func(*ProgressEvent)
+	policyEngine     port.PolicyEngine
+	policies         map[string]port.Policy // Compiled policies by path
+	policyMu         sync.Mutex
+	contentScanner   port.ContentScanner
+	fsys             port.FileSystem // File system of the local install targets
+	targetAgents     func(target string) []string
+	concurrency      int // Skills processed at the same time
+	allowRoot        bool
+	frozen           bool // Install only the versions resolved in the lock file
+	// Decides whether a skill that a SKILL.md declares as a dependency is added to the configuration
+	approveDependency func(dependent string, dependency *Skill) bool
+}
+
+// pendingDownload is a download shared by every skill with the same source and version.
+// done is closed once result and err are set.
+type pendingDownload struct {
+	done   chan struct{}
+	result *port.DownloadResult
+	err    error
+}
+
+// SkillManagerOption configures optional dependencies of a SkillManager.
+type SkillManagerOption func(*skillManagerImpl)
+
+// WithRemoteInstallers registers installers for remote install targets (e.g., ssh://).
+// Without a matching installer, installing to a remote target fails.
+func WithRemoteInstallers(installers ...port.RemoteInstaller) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.remoteInstallers = append(s.remoteInstallers, installers...)
+	}
+}
+
+// WithAllowRoot permits a process running as root to install into targets owned by other users.
+// The installed files are then given to the owner of the target instead of root.
+func WithAllowRoot() SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.allowRoot = true
+	}
+}
+
+// WithFrozenLock makes installs use the versions resolved in the lock file and refuse skills
+// whose source, version, or downloaded content do not match it.
+func WithFrozenLock() SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.frozen = true
+	}
+}
+
+// WithDependencyApproval lets approve decide whether a skill that the SKILL.md of dependent declares as a
+// dependency, and the configuration does not have, is added to it. Without it, such skills are refused.
+func WithDependencyApproval(approve func(dependent string, dependency *Skill) bool) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.approveDependency = approve
+	}
+}
+
+// WithConcurrency limits the number of skills that are downloaded, checked, hashed, and copied at the
+// same time to n. Values below 1 use DefaultConcurrency.
+func WithConcurrency(n int) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		if n < 1 {
+			n = DefaultConcurrency
+		}
+		s.concurrency = n
+	}
+}
+
+// WithStore sets the shared store used when shared_store is enabled.
+// Without it, the store in the user data directory is used.
+func WithStore(store *Store) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.store = store
+	}
+}
+
+// WithDownloadCache reuses downloads of fixed revisions from the cache across runs and projects.
+// Without it, downloads are only shared by the skills installed by the same SkillManager.
+func WithDownloadCache(cache *DownloadCache) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.downloadCache = cache
+	}
+}
+
+// WithMirror takes downloads of fixed revisions from the mirror that Mirror wrote to dir before the
+// download cache and the network, so that skills can be installed without access to their sources.
+func WithMirror(dir string) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.mirror = NewDownloadCache(dir)
+	}
+}
+
+// WithBackups moves installed skills into backups before they are replaced or removed, so that Restore can put them back.
+// Without it, replaced and removed skills are deleted.
+func WithBackups(backups *Backups) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.backups = backups
+	}
+}
+
+// WithProgressOutput writes progress messages to w instead of os.Stdout.
+// It is a shorthand for WithLogger with a MessageHandler at the info level.
+func WithProgressOutput(w io.Writer) SkillManagerOption {
+	return WithLogger(slog.New(NewMessageHandler(w, slog.LevelInfo)))
+}
+
+// WithLogger logs progress messages to logger: warnings at the warning level, the phases of an
+// operation at the info level, and details such as where a download came from at the debug level.
+// The handler of logger must be safe for concurrent use, as skills and install targets are processed in parallel.
+func WithLogger(logger *slog.Logger) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.logger = logger
+	}
+}
+
+// WithProgressEvents passes progress events to handler instead of logging their messages.
+// handler must be safe for concurrent use, as skills and install targets are processed in parallel.
+func WithProgressEvents(handler func(*ProgressEvent)) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.progressHandler = handler
+	}
+}
+
+// WithPolicyEngine compiles the policy configured with the policy key using engine.
+// Without it, installing fails when a policy is configured.
+func WithPolicyEngine(engine port.PolicyEngine) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.policyEngine = engine
+	}
+}
+
+// WithContentScanner runs the scanner command configured with the scanner key using scanner.
+// Without it, installing fails when a scanner is configured.
+func WithContentScanner(scanner port.ContentScanner) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.contentScanner = scanner
+	}
+}
+
+// WithTargetAgents resolves the agents that read an install target, so that skills declaring the
+// agents they support are left out of the targets of other agents. The agent setting of a target takes precedence.
+// Without it, only targets with the agent setting are checked.
+func WithTargetAgents(resolve func(target string) []string) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.targetAgents = resolve
+	}
+}
+
+// WithFileSystem inspects and removes the skills in local install targets through fsys instead of the
+// local file system, for uninstall, target migrate, and dry runs. Installing streams files, links them
+// into the shared store, and changes their owners, which only the local file system supports, so
+// installs into local targets fail with another fsys. Verification reads skills through the HashService,
+// which service.NewDirhashFS points at the same file system.
+func WithFileSystem(fsys port.FileSystem) SkillManagerOption {
+	return func(s *skillManagerImpl) {
+		s.fsys = fsys
+	}
 }
 
 // NewSkillManager creates a new SkillManager instance.
@@ -85,12 +302,119 @@ func NewSkillManager(
 	configManager *ConfigManager,
 	hashService port.HashService,
 	packageManagers []port.PackageManager,
+	opts ...SkillManagerOption,
 ) SkillManager {
-	return &skillManagerImpl{
+	s := &skillManagerImpl{
 		configManager:   configManager,
 		hashService:     hashService,
+		lockManager:     NewLockManager(LockPathFor(configManager.configPath)),
+		reviewManager:   NewReviewManager(ReviewPathFor(configManager.configPath)),
 		packageManagers: packageManagers,
+		downloads:       make(map[string]*pendingDownload),
+		logger:          slog.New(NewMessageHandler(os.Stdout, slog.LevelInfo)),
+		policies:        make(map[string]port.Policy),
+		fsys:            port.OSFileSystem{},
+		concurrency:     DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// sharedStore returns the shared store, resolving the default location on first use.
+func (s *skillManagerImpl) sharedStore() (*Store, error) {
+	s.storeOnce.Do(func() {
+		if s.store != nil {
+			return
+		}
+		dirs, err := ResolveUserDirs()
+		if err != nil {
+			s.storeErr = err
+			return
+		}
+		s.store = NewStore(dirs.StoreDir())
+	})
+	return s.store, s.storeErr
+}
+
+// download downloads the source at version once for all skills that reference it.
+// Concurrent calls for the same source and version wait for the first download, and
+// downloads of fixed revisions are taken from and added to the download cache when one is set.
+func (s *skillManagerImpl) download(ctx context.Context, pm port.PackageManager, source *port.Source, version string) (*port.DownloadResult, error) {
+	key := source.Type + "\x00" + source.URL + "\x00" + version
+
+	s.downloadsMu.Lock()
+	if pending, ok := s.downloads[key]; ok {
+		s.downloadsMu.Unlock()
+		select {
+		case <-pending.done:
+			return pending.result, pending.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	pending := &pendingDownload{done: make(chan struct{})}
+	s.downloads[key] = pending
+	s.downloadsMu.Unlock()
+
+	defer close(pending.done)
+	pending.result, pending.err = s.downloadUncached(ctx, pm, source, version)
+	if pending.err != nil {
+		// Let a later call retry instead of reporting a failure that may be transient
+		s.downloadsMu.Lock()
+		delete(s.downloads, key)
+		s.downloadsMu.Unlock()
+	}
+
+	return pending.result, pending.err
+}
+
+// downloadUncached downloads the source at version, using the mirror and the download cache when they are set.
+func (s *skillManagerImpl) downloadUncached(ctx context.Context, pm port.PackageManager, source *port.Source, version string) (*port.DownloadResult, error) {
+	if s.mirror != nil {
+		if mirrored, ok := s.mirror.Get(source, version); ok {
+			s.debug(ctx, "Using the mirrored download of %s %s", source.URL, version)
+			return mirrored, nil
+		}
+	}
+
+	// Local directories are read in place, and their files change without a new version to cache
+	if s.downloadCache == nil || source.Type == "local" {
+		s.debug(ctx, "Downloading %s %s", source.URL, version)
+		return pm.Download(ctx, source, version)
+	}
+
+	if cached, ok := s.downloadCache.Get(source, version); ok {
+		s.debug(ctx, "Using the cached download of %s %s", source.URL, version)
+		return cached, nil
+	}
+
+	s.debug(ctx, "Downloading %s %s", source.URL, version)
+
+	result, err := pm.Download(ctx, source, version)
+	if err != nil {
+		return nil, err
 	}
+
+	cached, err := s.downloadCache.Put(source, version, result)
+	if err != nil {
+		// The download itself succeeded, so install from it without caching
+		return result, nil
+	}
+
+	return cached, nil
+}
+
+// selectRemoteInstaller selects the installer for a remote install target based on its URL scheme.
+func (s *skillManagerImpl) selectRemoteInstaller(target string) (port.RemoteInstaller, error) {
+	scheme, _, _ := strings.Cut(target, "://")
+	for _, installer := range s.remoteInstallers {
+		if installer.Scheme() == scheme {
+			return installer, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported install target %s: no installer for scheme '%s'", target, scheme)
 }
 
 // selectPackageManager selects the appropriate package manager based on the source type.
@@ -131,7 +455,8 @@ func (s *skillManagerImpl) Install(ctx context.Context, skillName string) error
 		skillsToInstall = config.Skills
 	} else {
 		// Install specific skill (Requirement 6.2)
-		skill := config.FindSkillByName(skillName)
+		// Members of entries with sub_dirs are installed with their entry
+		skill := config.FindSkillEntry(skillName)
 		if skill == nil {
 			// Requirement 6.3, 12.2, 12.3
 			return &ErrorSkillsNotFound{SkillNames: []string{skillName}}
@@ -139,17 +464,19 @@ func (s *skillManagerImpl) Install(ctx context.Context, skillName string) error
 		skillsToInstall = []*Skill{skill}
 	}
 
-	// Install skills concurrently using errgroup
-	eg, egCtx := errgroup.WithContext(ctx)
-	for _, skill := range skillsToInstall {
-		eg.Go(func() error {
-			return s.InstallSingleSkill(egCtx, config, skill, false)
-		})
+	// The skills they depend on are installed first, including those their SKILL.md declares
+	waves, _, err := s.installOrder(ctx, config, skillsToInstall, nil, false)
+	if err != nil {
+		return err
 	}
 
-	// Wait for all installations to complete
-	if err := eg.Wait(); err != nil {
-		return err
+	// Install skills wave by wave, by priority within a wave, and concurrently within the same priority
+	for _, wave := range waves {
+		if err := s.forEachByPriority(ctx, wave, func(ctx context.Context, _ int, skill *Skill) error {
+			return s.installSkill(ctx, config, skill, false)
+		}); err != nil {
+			return err
+		}
 	}
 
 	// Save configuration once after all skills are installed
@@ -161,42 +488,369 @@ func (s *skillManagerImpl) Install(ctx context.Context, skillName string) error
 }
 
 // copySkillToTargets copies a skill to all install target directories concurrently.
-// It creates missing directories automatically and handles errors appropriately.
+// It creates missing directories automatically and applies per-target settings.
+// Targets where the lock file shows the same version already installed with unmodified files are skipped,
+// and the installation in each other target is recorded in the lock file.
 // Requirements: 3.4, 4.4, 6.6, 10.2, 10.5, 12.2, 12.3
-func (s *skillManagerImpl) copySkillToTargets(sourcePath, skillName string, installTargets []string) error {
+func (s *skillManagerImpl) copySkillToTargets(ctx context.Context, config *Config, sourcePath string, skill *Skill, version string) error {
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+	locked := lock.FindSkill(skill.Name)
+
+	// Linked local skills point every local target at their directory instead
+	var linkDir string
+	if skill.Link {
+		linkDir, err = config.LocalDir(skill)
+		if err != nil {
+			return err
+		}
+	}
+
+	// With the shared store, the skill is stored once and linked into every local target
+	var entry string
+	if config.SharedStore && len(config.LocalInstallTargets()) > 0 && linkDir == "" {
+		entry, err = s.addToStore(ctx, config, sourcePath, skill)
+		if err != nil {
+			return err
+		}
+	}
+
+	requirements, err := ReadAgentRequirements(sourcePath)
+	if err != nil {
+		return err
+	}
+
 	var eg errgroup.Group
 
-	for _, target := range installTargets {
+	for _, target := range config.InstallTargets {
 		eg.Go(func() error {
-			// Create skill directory in target (Requirement 6.6)
-			skillDir := target + "/" + skillName
+			// The canary version stays in its target until it is promoted
+			if skill.Canary != nil && skill.Canary.Target == target {
+				s.emit(ctx, skill.Name, PhaseCopy, target, "Skill '%s' is kept at canary version %s in %s", skill.Name, skill.Canary.Version, target)
+				return nil
+			}
 
-			// Remove existing skill directory if it exists
-			if err := os.RemoveAll(skillDir); err != nil {
-				return fmt.Errorf("failed to remove existing skill directory at %s: %w", skillDir, err)
+			// Targets skipped as incompatible are installed once their agent supports the skill
+			reason := s.incompatibility(config, target, requirements)
+			if status := locked.TargetStatus(target); s.isInstalledInTarget(ctx, config, skill, version, status) && (status.Incompatible != "") == (reason != "") {
+				s.emit(ctx, skill.Name, PhaseCopy, target, "Skill '%s' is already up to date in %s", skill.Name, target)
+				return nil
 			}
 
-			// Create parent directory if it doesn't exist (Requirement 6.6)
-			if err := os.MkdirAll(target, installDirMode); err != nil {
-				return fmt.Errorf("failed to create install target directory %s: %w", target, err)
+			if reason != "" {
+				s.emit(ctx, skill.Name, PhaseWarning, target, "WARNING: Skipping %s for skill '%s': the skill %s", target, skill.Name, reason)
+				// A version installed before the skill dropped support for the agent is removed
+				if status := locked.TargetStatus(target); status != nil && status.Incompatible == "" {
+					if err := s.backupInstall(ctx, config, target, skill.Name, status.DirName(skill.Name)); err != nil {
+						return err
+					}
+					if err := s.removeFromTarget(ctx, target, status.DirName(skill.Name)); err != nil {
+						return err
+					}
+				}
+				return s.lockManager.Update(ctx, func(lock *LockFile) {
+					lock.RecordInstall(skill.Name, &TargetStatus{
+						Path:         target,
+						Version:      version,
+						HashValue:    skill.HashValue,
+						Dir:          skill.InstallAs,
+						InstalledAt:  time.Now().UTC().Truncate(time.Second),
+						Incompatible: reason,
+					})
+				})
 			}
 
-			// Copy skill directory
-			if err := copyDir(sourcePath, skillDir); err != nil {
-				return fmt.Errorf("failed to copy skill to %s: %w", skillDir, err)
+			// The skill moves to another directory when install_as changed
+			if status := locked.TargetStatus(target); status != nil && status.DirName(skill.Name) != skill.DirName() {
+				if err := s.backupInstall(ctx, config, target, skill.Name, status.DirName(skill.Name)); err != nil {
+					return err
+				}
+				if err := s.removeFromTarget(ctx, target, status.DirName(skill.Name)); err != nil {
+					return err
+				}
 			}
 
-			return nil
+			hashValue := skill.HashValue
+			installedAt := time.Now().UTC().Truncate(time.Second)
+			if IsRemoteTarget(target) {
+				if err := s.installToRemoteTarget(ctx, target, sourcePath, skill.DirName()); err != nil {
+					return err
+				}
+			} else {
+				if _, ok := s.fsys.(port.OSFileSystem); !ok {
+					return fmt.Errorf("cannot install skill '%s' into %s: installing needs the local file system", skill.Name, target)
+				}
+
+				// Create skill directory in target (Requirement 6.6)
+				skillDir := target + "/" + skill.DirName()
+				ownerUID, ownerGID, existing, hasOwner := targetOwner(target)
+				previous := s.storeEntryOf(skillDir)
+
+				// Create parent directory if it doesn't exist (Requirement 6.6)
+				if err := os.MkdirAll(target, installDirMode); err != nil {
+					return fmt.Errorf("failed to create install target directory %s: %w", target, err)
+				}
+
+				if linkDir != "" {
+					if err := s.backupInstalledCopy(ctx, config, target, skill.Name, skill.DirName()); err != nil {
+						return err
+					}
+					if err := linkToTarget(linkDir, skillDir); err != nil {
+						return err
+					}
+				} else if entry != "" {
+					if err := s.backupInstalledCopy(ctx, config, target, skill.Name, skill.DirName()); err != nil {
+						return err
+					}
+					// The link replaces an existing skill directory in a single step
+					if err := s.store.Link(entry, skillDir); err != nil {
+						return err
+					}
+					if err := applyTargetSettings(skillDir, config.TargetSettingsFor(target)); err != nil {
+						return fmt.Errorf("failed to apply settings for install target %s: %w", target, err)
+					}
+				} else if err := s.copyToTarget(ctx, config, target, sourcePath, skill, version, installedAt); err != nil {
+					return err
+				}
+
+				// Delete the previously linked store entry once no project uses it anymore
+				if previous != "" && previous != entry {
+					if err := s.releaseStoreEntry(previous); err != nil {
+						return err
+					}
+				}
+
+				// Avoid leaving root-owned files in a target that belongs to another user
+				if hasOwner && isRoot() && ownerUID != 0 {
+					if err := chownInstalled(skillDir, target, existing, ownerUID, ownerGID); err != nil {
+						return err
+					}
+				}
+
+				// Skills without a hash in the configuration (go.mod versions) still record
+				// the installed hash so that local modifications can be detected
+				if hashValue == "" {
+					hashResult, err := s.hashService.CalculateHash(ctx, skillDir, config.EffectiveHashAlgorithm(), config.HashOptions(skill)...)
+					if err != nil {
+						return fmt.Errorf("failed to calculate hash for %s: %w", skillDir, err)
+					}
+					hashValue = hashResult.Value
+				}
+			}
+
+			return s.lockManager.Update(ctx, func(lock *LockFile) {
+				lock.RecordInstall(skill.Name, &TargetStatus{
+					Path:        target,
+					Version:     version,
+					HashValue:   hashValue,
+					Dir:         skill.InstallAs,
+					InstalledAt: installedAt,
+				})
+			})
 		})
 	}
 
 	return eg.Wait()
 }
 
+// copyToTarget copies the skill into the local install target with the banner, metadata, and target settings.
+// The copy is prepared next to the installed skill first. A skill already installed in the target is then
+// updated in place by writing only the files that changed, which is faster than copying every file and
+// spares file watchers of the target from seeing the whole skill deleted and recreated. With atomic copies,
+// the prepared copy is swapped in as a whole instead, so that agents never see a mix of both versions.
+// Files matching the skill's preserve patterns are kept either way.
+func (s *skillManagerImpl) copyToTarget(ctx context.Context, config *Config, target, sourcePath string, skill *Skill, version string, installedAt time.Time) error {
+	skillDir := target + "/" + skill.DirName()
+	staging, err := os.MkdirTemp(target, "."+skill.DirName()+".update-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory in %s: %w", target, err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	staged := staging + "/" + skill.DirName()
+	opts := config.Copy.Options()
+	opts.Progress = s.copyProgress(ctx, skill.Name, target, sourcePath)
+	if err := CopyDir(sourcePath, staged, opts); err != nil {
+		return fmt.Errorf("failed to copy skill to %s: %w", skillDir, err)
+	}
+	// Linked store entries are shared with other projects, so only copies get the banner
+	if config.Banner {
+		if err := injectBanner(staged, skill, version); err != nil {
+			return fmt.Errorf("failed to insert banner into %s: %w", skillDir, err)
+		}
+	}
+	if config.SkillMetadata {
+		if err := writeSkillMetadata(staged, config.SealedSkill(skill), version, installedAt); err != nil {
+			return fmt.Errorf("failed to write skill metadata into %s: %w", skillDir, err)
+		}
+	}
+	if err := applyTargetSettings(staged, config.TargetSettingsFor(target)); err != nil {
+		return fmt.Errorf("failed to apply settings for install target %s: %w", target, err)
+	}
+
+	preserve := NewSkillIgnore(skill.Preserve)
+	if info, err := os.Lstat(skillDir); err == nil && info.IsDir() {
+		if err := s.backupInstalledCopy(ctx, config, target, skill.Name, skill.DirName()); err != nil {
+			return err
+		}
+		if !config.Copy.AtomicUpdates() {
+			if _, err := SyncDir(staged, skillDir, preserve); err != nil {
+				return fmt.Errorf("failed to update skill directory at %s: %w", skillDir, err)
+			}
+			return nil
+		}
+		if err := copyPreserved(skillDir, staged, preserve); err != nil {
+			return fmt.Errorf("failed to keep preserved files of %s: %w", skillDir, err)
+		}
+	}
+
+	// The prepared copy replaces the installed skill, or a link into the shared store, in a single step
+	if err := replaceAtomically(staged, skillDir); err != nil {
+		return fmt.Errorf("failed to copy skill to %s: %w", skillDir, err)
+	}
+	return nil
+}
+
+// linkToTarget replaces the installed skill directory with a symbolic link to the directory of a local skill
+// in a single step. The files of the directory are left as they are, so the banner, the skill metadata, and
+// the target settings are not applied to them.
+func linkToTarget(dir, skillDir string) error {
+	staging, err := os.MkdirTemp(filepath.Dir(skillDir), "."+filepath.Base(skillDir)+".link-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory next to %s: %w", skillDir, err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	staged := filepath.Join(staging, filepath.Base(skillDir))
+	if err := os.Symlink(dir, staged); err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w. Set link = false to copy the skill instead", skillDir, dir, err)
+	}
+	if err := replaceAtomically(staged, skillDir); err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", skillDir, dir, err)
+	}
+	return nil
+}
+
+// addToStore adds the downloaded skill to the shared store and returns the entry directory.
+func (s *skillManagerImpl) addToStore(ctx context.Context, config *Config, sourcePath string, skill *Skill) (string, error) {
+	store, err := s.sharedStore()
+	if err != nil {
+		return "", err
+	}
+
+	hashValue := skill.HashValue
+	if hashValue == "" {
+		hashResult, err := s.hashService.CalculateHash(ctx, sourcePath, config.EffectiveHashAlgorithm(), config.HashOptions(skill)...)
+		if err != nil {
+			return "", fmt.Errorf("failed to calculate hash for %s: %w", sourcePath, err)
+		}
+		hashValue = hashResult.Value
+	}
+
+	return store.Add(sourcePath, hashValue)
+}
+
+// storeEntryOf returns the shared store entry that the installed skill directory links to,
+// or an empty string when it is not a link into the store.
+func (s *skillManagerImpl) storeEntryOf(skillDir string) string {
+	info, err := s.fsys.Lstat(skillDir)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return ""
+	}
+	store, err := s.sharedStore()
+	if err != nil {
+		return ""
+	}
+	return store.EntryOf(skillDir)
+}
+
+// releaseStoreEntry deletes the shared store entry if no install target links to it anymore.
+func (s *skillManagerImpl) releaseStoreEntry(entry string) error {
+	store, err := s.sharedStore()
+	if err != nil {
+		return err
+	}
+	if _, err := store.Release(entry); err != nil {
+		return fmt.Errorf("failed to release shared store entry: %w", err)
+	}
+	return nil
+}
+
+// isInstalledInTarget reports whether the lock file status shows that the given version of the skill
+// is already installed in the target and its files have not been modified since.
+func (s *skillManagerImpl) isInstalledInTarget(ctx context.Context, config *Config, skill *Skill, version string, status *TargetStatus) bool {
+	if status == nil || version == "" || status.Version != version {
+		return false
+	}
+
+	freshness, err := CheckTargetFreshness(ctx, s.hashService, config, skill, status)
+	return err == nil && (freshness == TargetUpToDate || freshness == TargetIncompatible)
+}
+
+// incompatibility returns why the skill with the agent requirements cannot be installed into the target,
+// or an empty string when it can.
+func (s *skillManagerImpl) incompatibility(config *Config, target string, requirements []*AgentRequirement) string {
+	if len(requirements) == 0 {
+		return ""
+	}
+
+	var agents []string
+	var agentVersion string
+	if settings := config.TargetSettingsFor(target); settings != nil && settings.Agent != "" {
+		agents, agentVersion = []string{settings.Agent}, settings.AgentVersion
+	} else if s.targetAgents != nil {
+		agents = s.targetAgents(target)
+		if settings != nil {
+			agentVersion = settings.AgentVersion
+		}
+	}
+	return agentIncompatibility(requirements, agents, agentVersion)
+}
+
+// installToRemoteTarget uploads the non-ignored files of a skill to a remote install target.
+func (s *skillManagerImpl) installToRemoteTarget(ctx context.Context, target, sourcePath, skillName string) error {
+	installer, err := s.selectRemoteInstaller(target)
+	if err != nil {
+		return err
+	}
+
+	files, err := ListSkillFiles(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to list files in %s: %w", sourcePath, err)
+	}
+
+	if err := installer.Install(ctx, target, skillName, sourcePath, files); err != nil {
+		return fmt.Errorf("failed to install skill to %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// checkTargetsWritable checks that every install target can be written by the current user
+// and, when running as root, that no target belongs to another user unless allowed.
+func (s *skillManagerImpl) checkTargetsWritable(installTargets []string) error {
+	for _, target := range installTargets {
+		if IsRemoteTarget(target) {
+			continue
+		}
+		if err := CheckTargetWritable(target); err != nil {
+			return err
+		}
+		if !s.allowRoot {
+			if err := CheckTargetOwnership(target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // verifyInstalledSkill verifies the hash of an installed skill in all target directories concurrently.
 // It returns an error if any verification fails.
 // Requirements: 6.4, 6.5
-func (s *skillManagerImpl) verifyInstalledSkill(ctx context.Context, skill *Skill, installTargets []string) error {
+func (s *skillManagerImpl) verifyInstalledSkill(ctx context.Context, config *Config, skill *Skill, installTargets []string) error {
 	// Skip verification if HashValue is empty (e.g., when using go.mod version)
 	// In this case, integrity is verified by go.sum
 	if skill.HashValue == "" {
@@ -207,17 +861,18 @@ func (s *skillManagerImpl) verifyInstalledSkill(ctx context.Context, skill *Skil
 
 	for _, target := range installTargets {
 		eg.Go(func() error {
-			skillDir := target + "/" + skill.Name
+			skillDir := target + "/" + skill.DirName()
+			expected := skill.ForTarget(target)
 
 			// Calculate hash of installed skill
-			hashResult, err := s.hashService.CalculateHash(egCtx, skillDir)
+			hashResult, err := s.hashService.CalculateHash(egCtx, skillDir, port.HashAlgorithmOf(expected.HashValue), config.HashOptions(skill)...)
 			if err != nil {
 				return fmt.Errorf("failed to calculate hash for verification in %s: %w", skillDir, err)
 			}
 
 			// Compare with expected hash
-			if hashResult.Value != skill.HashValue {
-				return fmt.Errorf("hash mismatch in %s: expected %s, got %s", skillDir, skill.HashValue, hashResult.Value)
+			if hashResult.Value != expected.HashValue {
+				return &ErrorHashMismatch{SkillName: skill.Name, Location: skillDir, Expected: expected.HashValue, Actual: hashResult.Value}
 			}
 
 			return nil
@@ -227,76 +882,252 @@ func (s *skillManagerImpl) verifyInstalledSkill(ctx context.Context, skill *Skil
 	return eg.Wait()
 }
 
-// copyDir recursively copies a directory from src to dst.
-// It creates the destination directory if it doesn't exist.
-func copyDir(src, dst string) error {
-	// Get source directory info
-	srcInfo, err := os.Stat(src)
+// InstallSingleSkill installs a single skill after the skills it depends on, those in its depends_on
+// and those its SKILL.md declares.
+// If saveConfig is true, saves the configuration after updating skill metadata.
+// This method is public to allow external callers (like add command) to install a single skill.
+// Requirements: 3.3, 3.4, 4.3, 4.4, 5.3, 6.2, 6.4, 6.5, 6.6, 10.2, 10.5, 12.1, 12.2, 12.3
+func (s *skillManagerImpl) InstallSingleSkill(ctx context.Context, config *Config, skill *Skill, saveConfig bool) error {
+	waves, changed, err := s.installOrder(ctx, config, []*Skill{skill}, nil, false)
 	if err != nil {
 		return err
 	}
+	for _, dependency := range slices.Concat(waves...) {
+		if dependency == skill {
+			continue
+		}
+		if err := s.installSkill(ctx, config, dependency, false); err != nil {
+			return fmt.Errorf("failed to install skill '%s', which skill '%s' depends on: %w", dependency.Name, skill.Name, err)
+		}
+	}
 
-	// Create destination directory
-	if mkdirErr := os.MkdirAll(dst, srcInfo.Mode()); mkdirErr != nil {
-		return mkdirErr
+	if err := s.installSkill(ctx, config, skill, saveConfig); err != nil {
+		return err
 	}
+	if changed && saveConfig {
+		if err := s.configManager.Save(ctx, config); err != nil {
+			return fmt.Errorf("failed to save configuration after resolving dependencies: %w", err)
+		}
+	}
+	return nil
+}
 
-	// Read source directory entries
-	entries, err := os.ReadDir(src)
+// installOrder returns the skills and the skills they depend on in the waves they are installed in, after
+// adding the dependencies that their SKILL.md declares to their depends_on with resolveDeclaredDependencies.
+// It reports whether the configuration changed.
+func (s *skillManagerImpl) installOrder(ctx context.Context, config *Config, skills []*Skill, copies map[*Skill][]string, dryRun bool) ([][]*Skill, bool, error) {
+	waves, err := resolveDependencies(config, skills)
 	if err != nil {
-		return err
+		return nil, false, err
+	}
+	changed, err := s.resolveDeclaredDependencies(ctx, config, slices.Concat(waves...), copies, dryRun)
+	if err != nil {
+		return nil, false, err
 	}
+	if !changed {
+		return waves, false, nil
+	}
+	waves, err = resolveDependencies(config, skills)
+	return waves, true, err
+}
 
-	// Copy each entry
-	for _, entry := range entries {
-		srcPath := src + "/" + entry.Name()
-		dstPath := dst + "/" + entry.Name()
+// resolveDeclaredDependencies adds the dependencies that the SKILL.md of the skills declares, and theirs
+// in turn, to the depends_on of the skills, so that they are installed first. SKILL.md is read from the
+// copy the skill is installed from: copies has the directories of the skills fetched already, skills that
+// are up to date in every install target are read from their installed copy, and the others are fetched.
+//
+// Dependencies missing from the configuration are added to it, pinned to the version they resolve to,
+// when SKILL.md says where to install them from and the addition is approved, or always in a dry run,
+// which only reports them. It reports whether the configuration changed, and must not run concurrently
+// with other changes to config.
+func (s *skillManagerImpl) resolveDeclaredDependencies(ctx context.Context, config *Config, skills []*Skill, copies map[*Skill][]string, dryRun bool) (bool, error) {
+	queue := slices.Clone(skills)
+	visited := make(map[*Skill]bool)
+	for _, skill := range skills {
+		visited[skill] = true
+	}
 
-		if entry.IsDir() {
-			// Recursively copy subdirectory
-			if err := copyDir(srcPath, dstPath); err != nil {
-				return err
+	changed := false
+	for len(queue) > 0 {
+		skill := queue[0]
+		queue = queue[1:]
+
+		dirs, ok := copies[skill]
+		if !ok {
+			var err error
+			if dirs, err = s.skillCopies(ctx, config, skill); err != nil {
+				return false, err
 			}
-		} else {
-			// Copy file
-			if err := copyFile(srcPath, dstPath); err != nil {
-				return err
+		}
+		var declared []*SkillDependency
+		for _, dir := range dirs {
+			dependencies, err := ReadSkillDependencies(dir)
+			if err != nil {
+				return false, fmt.Errorf("failed to read the dependencies of skill '%s': %w", skill.Name, err)
+			}
+			declared = append(declared, dependencies...)
+		}
+
+		for _, dependency := range declared {
+			entry := config.FindSkillEntry(dependency.Name)
+			if entry == nil {
+				if dependency.URL == "" {
+					return false, &ErrorDependencyNotFound{SkillName: skill.Name, Dependency: dependency.Name, Declared: true}
+				}
+				entry = dependency.Skill()
+				if err := entry.Validate(); err != nil {
+					return false, fmt.Errorf("invalid dependency '%s' in the SKILL.md of skill '%s': %w", dependency.Name, skill.Name, err)
+				}
+				if !dryRun && (s.approveDependency == nil || !s.approveDependency(skill.Name, entry)) {
+					return false, &ErrorDependencyNotApproved{SkillName: skill.Name, Dependency: entry.Name, URL: entry.URL}
+				}
+
+				// The dependency is pinned to the version its own dependencies are read from
+				downloadResult, _, dirs, err := s.fetchEntry(ctx, config, entry)
+				if err != nil {
+					return false, fmt.Errorf("failed to resolve skill '%s', which skill '%s' depends on: %w", entry.Name, skill.Name, err)
+				}
+				if entry.Version == "" && !downloadResult.FromGoMod {
+					entry.Version = downloadResult.Version
+				}
+				if copies == nil {
+					copies = make(map[*Skill][]string)
+				}
+				copies[entry] = dirs
+				config.Skills = append(config.Skills, entry)
+				changed = true
+				s.emit(ctx, entry.Name, PhaseStart, "", "Adding skill '%s' %s, which skill '%s' depends on", entry.Name, entry.Version, skill.Name)
+			}
+			if !slices.Contains(skill.DependsOn, dependency.Name) && (entry != skill || dependency.Name == skill.Name) {
+				skill.DependsOn = append(skill.DependsOn, dependency.Name)
+				changed = true
+				if _, err := resolveDependencies(config, []*Skill{skill}); err != nil {
+					return false, err
+				}
+			}
+
+			if !visited[entry] {
+				visited[entry] = true
+				queue = append(queue, entry)
 			}
 		}
 	}
+	return changed, nil
+}
 
-	return nil
+// skillCopies returns the directories that the skills of the entry are installed from: their copies in
+// the first local install target that has them when the entry is up to date everywhere, and those of a
+// download of the entry otherwise. Up-to-date skills installed only into remote targets have none.
+func (s *skillManagerImpl) skillCopies(ctx context.Context, config *Config, entry *Skill) ([]string, error) {
+	if s.isInstalledInAllTargets(ctx, config, entry) {
+		var dirs []string
+		for _, skill := range entry.InstalledSkills() {
+			for _, target := range config.LocalInstallTargets() {
+				dir := filepath.Join(target, skill.DirName())
+				if _, err := os.Stat(dir); err == nil {
+					dirs = append(dirs, dir)
+					break
+				}
+			}
+		}
+		return dirs, nil
+	}
+
+	_, _, dirs, err := s.fetchEntry(ctx, config, entry)
+	return dirs, err
 }
 
-// copyFile copies a single file from src to dst.
-func copyFile(src, dst string) error {
-	// Read source file
-	data, err := os.ReadFile(src)
+// fetchEntry downloads the entry at the version it is installed at, and returns the download with the
+// skills of the entry and the directories in it that they are installed from.
+func (s *skillManagerImpl) fetchEntry(ctx context.Context, config *Config, skill *Skill) (*port.DownloadResult, []*Skill, []string, error) {
+	pm, err := s.selectPackageManager(skill.Source)
 	if err != nil {
-		return err
+		return nil, nil, nil, fmt.Errorf("failed to select package manager for skill '%s': %w", skill.Name, err)
 	}
-
-	// Get source file info for permissions
-	srcInfo, err := os.Stat(src)
+	source, err := config.SourceOf(skill)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 
-	// Write destination file
-	if err := os.WriteFile(dst, data, srcInfo.Mode()); err != nil {
-		return err
+	// The same version as installSkill
+	version := skill.Version
+	switch {
+	case version != "":
+	case s.frozen && !skill.Insecure && (source.Type != "go-mod" || !config.Defaults.UseGoMod()):
+		locked, err := s.frozenResolution(ctx, skill)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		version = locked.Version
+	default:
+		version, err = s.resolveDefaultVersion(ctx, config, pm, source)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to resolve default version for skill '%s': %w", skill.Name, err)
+		}
 	}
 
-	return nil
+	downloadResult, err := s.download(ctx, pm, source, version)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to download skill '%s': %w. Check your network connection and source URL", skill.Name, err)
+	}
+
+	skills := []*Skill{skill}
+	sourcePaths := []string{downloadResult.Path}
+	if skill.IsGroup() {
+		members, err := expandSubDirs(config, skill, downloadResult.Path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		skills, sourcePaths = skills[:0], sourcePaths[:0]
+		for _, member := range members {
+			skills = append(skills, skill.memberSkill(member))
+			sourcePaths = append(sourcePaths, filepath.Join(downloadResult.Path, filepath.FromSlash(member.SubDir)))
+		}
+	} else if skill.SubDir != "" {
+		sourcePaths[0] = filepath.Join(downloadResult.Path, filepath.FromSlash(skill.SubDir))
+		if _, err := os.Stat(sourcePaths[0]); err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil, nil, &ErrorSubDirNotFound{SkillName: skill.Name, SubDir: skill.SubDir, Suggestions: suggestSubDirs(downloadResult.Path, skill.SubDir)}
+			}
+			return nil, nil, nil, fmt.Errorf("failed to access subdirectory '%s' in skill '%s': %w", skill.SubDir, skill.Name, err)
+		}
+	}
+	return downloadResult, skills, sourcePaths, nil
 }
 
-// InstallSingleSkill installs a single skill.
+// installSkill installs a single skill, without the skills it depends on.
 // If saveConfig is true, saves the configuration after updating skill metadata.
-// This method is public to allow external callers (like add command) to install a single skill.
-// Requirements: 3.3, 3.4, 4.3, 4.4, 5.3, 6.2, 6.4, 6.5, 6.6, 10.2, 10.5, 12.1, 12.2, 12.3
-func (s *skillManagerImpl) InstallSingleSkill(ctx context.Context, config *Config, skill *Skill, saveConfig bool) error {
+func (s *skillManagerImpl) installSkill(ctx context.Context, config *Config, skill *Skill, saveConfig bool) error {
+	// Fast path: nothing to download or copy when every target already has the pinned version
+	if s.isInstalledInAllTargets(ctx, config, skill) {
+		if err := s.recordMissingResolutions(ctx, config, skill); err != nil {
+			return err
+		}
+		s.emit(ctx, skill.Name, PhaseDone, "", "Skill '%s' is already up to date", skill.Name)
+		return nil
+	}
+
 	// Progress information (Requirement 12.1)
-	fmt.Printf("Installing skill '%s' from %s...\n", skill.Name, skill.Source)
+	s.emit(ctx, skill.Name, PhaseStart, "", "Installing skill '%s' from %s...", skill.Name, skill.Source)
+	if skill.Insecure {
+		s.emit(ctx, skill.Name, PhaseWarning, "", "WARNING: Skill '%s' is marked insecure: hash mismatches only warn, and frozen and signed lock files do not limit its version. Remove 'insecure' once its source can satisfy the integrity policies.", skill.Name)
+	}
+
+	// Frozen installs are limited to the source and version resolved in the lock file,
+	// except for insecure skills, which are resolved again
+	var locked *LockedSkill
+	if s.frozen && !skill.Insecure {
+		var err error
+		locked, err = s.frozenResolution(ctx, skill)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Fail before downloading when an install target cannot be written
+	if err := s.checkTargetsWritable(config.InstallTargets); err != nil {
+		return err
+	}
 
 	// Select appropriate package manager (Requirement 11.4)
 	pm, err := s.selectPackageManager(skill.Source)
@@ -305,17 +1136,39 @@ func (s *skillManagerImpl) InstallSingleSkill(ctx context.Context, config *Confi
 	}
 
 	// Create source from skill
-	source := &port.Source{
-		Type: skill.Source,
-		URL:  skill.URL,
+	source, err := config.SourceOf(skill)
+	if err != nil {
+		return err
+	}
+
+	// Apply the configured default version strategy when no version is pinned
+	// Frozen installs download the locked version instead, except for versions from go.mod
+	version := skill.Version
+	switch {
+	case version != "":
+	case locked != nil && (source.Type != "go-mod" || !config.Defaults.UseGoMod()):
+		version = locked.Version
+	default:
+		version, err = s.resolveDefaultVersion(ctx, config, pm, source)
+		if err != nil {
+			return fmt.Errorf("failed to resolve default version for skill '%s': %w", skill.Name, err)
+		}
 	}
 
 	// Download skill (Requirements 3.3, 4.3)
-	fmt.Printf("Downloading skill '%s' version %s...\n", skill.Name, skill.Version)
-	downloadResult, err := pm.Download(ctx, source, skill.Version)
+	s.emit(ctx, skill.Name, PhaseDownload, "", "Downloading skill '%s' version %s...", skill.Name, version)
+	downloadResult, err := s.download(ctx, pm, source, version)
 	if err != nil {
 		return fmt.Errorf("failed to download skill '%s': %w. Check your network connection and source URL", skill.Name, err)
 	}
+	if locked != nil && downloadResult.Version != locked.Version {
+		return &ErrorLockMismatch{SkillName: skill.Name, Reason: fmt.Sprintf("version %s resolved, but the lock file has %s", downloadResult.Version, locked.Version)}
+	}
+
+	// Every skill of an entry with sub_dirs is installed from this download
+	if skill.IsGroup() {
+		return s.installSkillGroup(ctx, config, skill, downloadResult, saveConfig)
+	}
 
 	// Determine the source path to use for installation and hash calculation
 	sourcePath := downloadResult.Path
@@ -326,24 +1179,185 @@ func (s *skillManagerImpl) InstallSingleSkill(ctx context.Context, config *Confi
 		// Verify that the subdirectory exists
 		if _, statErr := os.Stat(sourcePath); statErr != nil {
 			if os.IsNotExist(statErr) {
-				return fmt.Errorf("subdirectory '%s' not found in downloaded skill '%s'. Available content is in: %s", skill.SubDir, skill.Name, downloadResult.Path)
+				return &ErrorSubDirNotFound{SkillName: skill.Name, SubDir: skill.SubDir, Suggestions: suggestSubDirs(downloadResult.Path, skill.SubDir)}
 			}
 			return fmt.Errorf("failed to access subdirectory '%s' in skill '%s': %w", skill.SubDir, skill.Name, statErr)
 		}
-		fmt.Printf("Using subdirectory '%s' from downloaded content...\n", skill.SubDir)
+		s.emit(ctx, skill.Name, PhaseDownload, "", "Using subdirectory '%s' from downloaded content...", skill.SubDir)
+	}
+
+	// Check the content before the skill is recorded in the configuration
+	sourcePath, cleanup, err := s.checkContent(ctx, config, sourcePath, skill, downloadResult.Version)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := s.recordDownloadHash(ctx, config, skill, sourcePath, downloadResult); err != nil {
+		return err
+	}
+	if err := s.checkLockedHash(ctx, config, skill, sourcePath, locked); err != nil {
+		return err
+	}
+
+	// Save updated configuration if requested (Requirement 5.3)
+	if saveConfig {
+		if err := s.configManager.Save(ctx, config); err != nil {
+			return fmt.Errorf("failed to save configuration after hash calculation: %w", err)
+		}
+	}
+
+	// Get install targets (Requirement 6.2)
+	if len(config.InstallTargets) == 0 {
+		return fmt.Errorf("no install targets configured. Run 'skills-pkg init --install-dir <dir>' to configure install targets")
+	}
+
+	return s.installToTargets(ctx, config, sourcePath, skill, downloadResult.Version)
+}
+
+// installSkillGroup installs every skill matched by the sub_dirs of the entry from a single download
+// and records them as the entry's members.
+func (s *skillManagerImpl) installSkillGroup(ctx context.Context, config *Config, group *Skill, downloadResult *port.DownloadResult, saveConfig bool) error {
+	members, err := expandSubDirs(config, group, downloadResult.Path)
+	if err != nil {
+		return err
+	}
+
+	skills := make([]*Skill, 0, len(members))
+	sourcePaths := make([]string, 0, len(members))
+	for _, member := range members {
+		skill := group.memberSkill(member)
+		sourcePath, cleanup, err := s.checkContent(ctx, config, downloadResult.Path+"/"+member.SubDir, skill, downloadResult.Version)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		// Compare with the hash recorded for the member of the same name at the previous installation
+		if previous := group.findMember(member.Name); previous != nil {
+			skill.HashValue = previous.HashValue
+		}
+		if err := s.recordDownloadHash(ctx, config, skill, sourcePath, downloadResult); err != nil {
+			return err
+		}
+		if s.frozen && !skill.Insecure {
+			lock, err := s.lockManager.Load(ctx)
+			if err != nil {
+				return err
+			}
+			if err := s.checkLockedHash(ctx, config, skill, sourcePath, lock.FindSkill(skill.Name)); err != nil {
+				return err
+			}
+		}
+		member.HashValue = skill.HashValue
+		skills = append(skills, skill)
+		sourcePaths = append(sourcePaths, sourcePath)
+	}
+	group.Version = skills[0].Version
+	group.HashValue = ""
+	group.Members = members
+
+	if saveConfig {
+		if err := s.configManager.Save(ctx, config); err != nil {
+			return fmt.Errorf("failed to save configuration after hash calculation: %w", err)
+		}
+	}
+
+	if len(config.InstallTargets) == 0 {
+		return fmt.Errorf("no install targets configured. Run 'skills-pkg init --install-dir <dir>' to configure install targets")
+	}
+
+	for i, skill := range skills {
+		if err := s.installToTargets(ctx, config, sourcePaths[i], skill, downloadResult.Version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expandSubDirs returns the members matched by the sub_dirs of the entry in the downloaded source.
+// Each pattern must match at least one directory, and the members must not share a name
+// with each other or with another entry.
+func expandSubDirs(config *Config, group *Skill, root string) ([]*SkillMember, error) {
+	var members []*SkillMember
+	seen := make(map[string]string)
+	for _, pattern := range group.SubDirs {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(strings.Trim(pattern, "/"))))
+		if err != nil {
+			return nil, fmt.Errorf("invalid sub_dirs pattern '%s' in skill '%s': %w", pattern, group.Name, err)
+		}
+
+		matched := false
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", match, err)
+			}
+			subDir := filepath.ToSlash(rel)
+			matched = true
+
+			name := path.Base(subDir)
+			if previous, ok := seen[name]; ok {
+				if previous == subDir {
+					continue
+				}
+				return nil, &ErrorSkillExists{SkillName: name}
+			}
+			if other := config.FindSkillByName(name); other != nil && other != group {
+				return nil, &ErrorSkillExists{SkillName: name}
+			}
+			seen[name] = subDir
+			members = append(members, &SkillMember{Name: name, SubDir: subDir})
+		}
+		if !matched && !hasGlobMeta(pattern) {
+			return nil, &ErrorSubDirNotFound{SkillName: group.Name, SubDir: pattern, Suggestions: suggestSubDirs(root, pattern)}
+		}
+		if !matched {
+			return nil, fmt.Errorf("sub_dirs pattern '%s' matched no directory in downloaded skill '%s'. Available content is in: %s", pattern, group.Name, root)
+		}
 	}
 
+	return members, nil
+}
+
+// hasGlobMeta reports whether the sub_dirs pattern contains glob metacharacters.
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// recordDownloadHash sets the version and hash of the skill from the downloaded source.
+// Content that differs from the recorded hash of the same version is handled by the hash_mismatch policy.
+func (s *skillManagerImpl) recordDownloadHash(ctx context.Context, config *Config, skill *Skill, sourcePath string, downloadResult *port.DownloadResult) error {
 	// Calculate hash only if not from go.mod (Requirement 5.3)
 	// When version is resolved from go.mod, rely on go.sum for integrity verification
 	if !downloadResult.FromGoMod {
-		// Update version
-		skill.Version = downloadResult.Version
-
-		fmt.Printf("Calculating hash for skill '%s'...\n", skill.Name)
-		hashResult, err := s.hashService.CalculateHash(ctx, sourcePath)
+		s.emit(ctx, skill.Name, PhaseHash, "", "Calculating hash for skill '%s'...", skill.Name)
+		hashResult, err := s.hashService.CalculateHash(ctx, sourcePath, config.EffectiveHashAlgorithm(), config.HashOptions(skill)...)
 		if err != nil {
 			return fmt.Errorf("failed to calculate hash for skill '%s': %w", skill.Name, err)
 		}
+
+		// Different content for the recorded version means the upstream changed what the version points to
+		if skill.HashValue != "" && skill.Version == downloadResult.Version &&
+			port.HashAlgorithmOf(skill.HashValue) == config.EffectiveHashAlgorithm() && hashResult.Value != skill.HashValue {
+			mismatch := &ErrorHashMismatch{
+				SkillName: skill.Name,
+				Location:  fmt.Sprintf("version %s downloaded from %s", downloadResult.Version, skill.URL),
+				Expected:  skill.HashValue,
+				Actual:    hashResult.Value,
+			}
+			if config.HashMismatchFor(skill) == HashMismatchFail {
+				return mismatch
+			}
+			s.emit(ctx, skill.Name, PhaseWarning, "", "WARNING: %v. The upstream content of the version has changed; the new hash is recorded.", mismatch)
+		}
+
+		// Update version and hash
+		skill.Version = downloadResult.Version
 		skill.HashValue = hashResult.Value
 	} else {
 		// Clear version and hash values when using go.mod version
@@ -353,124 +1367,364 @@ func (s *skillManagerImpl) InstallSingleSkill(ctx context.Context, config *Confi
 		skill.HashValue = ""
 	}
 
-	// Save updated configuration if requested (Requirement 5.3)
-	if saveConfig {
-		if err := s.configManager.Save(ctx, config); err != nil {
-			return fmt.Errorf("failed to save configuration after hash calculation: %w", err)
+	return nil
+}
+
+// frozenResolution returns the lock file entry of a skill for a frozen install, and an error when the
+// lock file has no resolved version for the skill or the configuration asks for another source or version.
+// Entries with sub_dirs are checked against the entry of their first member.
+func (s *skillManagerImpl) frozenResolution(ctx context.Context, skill *Skill) (*LockedSkill, error) {
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := lock.FindSkill(skill.InstalledSkills()[0].Name)
+	if locked == nil || locked.Version == "" {
+		return nil, &ErrorLockMismatch{SkillName: skill.Name, Reason: "the lock file has no resolved version for it"}
+	}
+	if reason := locked.ResolutionMismatch(skill); reason != "" {
+		return nil, &ErrorLockMismatch{SkillName: skill.Name, Reason: reason}
+	}
+
+	return locked, nil
+}
+
+// checkLockedHash returns an error when the hash of a downloaded skill, at sourcePath, differs from the
+// hash resolved in its lock file entry. When the lock file was written with another hash algorithm than
+// the configured one, the skill is hashed again with the algorithm of the lock file. It is a no-op
+// without an entry, or when either hash is missing.
+func (s *skillManagerImpl) checkLockedHash(ctx context.Context, config *Config, skill *Skill, sourcePath string, locked *LockedSkill) error {
+	if locked == nil || locked.HashValue == "" || skill.HashValue == "" {
+		return nil
+	}
+
+	actual := skill.HashValue
+	if algorithm := port.HashAlgorithmOf(locked.HashValue); algorithm != port.HashAlgorithmOf(actual) {
+		hashResult, err := s.hashService.CalculateHash(ctx, sourcePath, algorithm, config.HashOptions(skill)...)
+		if err != nil {
+			return fmt.Errorf("failed to calculate the %s hash of skill '%s' resolved in the lock file: %w", algorithm, skill.Name, err)
+		}
+		actual = hashResult.Value
+	}
+	if actual == locked.HashValue {
+		return nil
+	}
+	return &ErrorHashMismatch{
+		SkillName: skill.Name,
+		Location:  fmt.Sprintf("version %s resolved in the lock file", locked.Version),
+		Expected:  locked.HashValue,
+		Actual:    actual,
+	}
+}
+
+// recordMissingResolutions records the configured version and hash of an installed skill in the
+// lock file when its entry has no resolved version, as in lock files written by older versions.
+func (s *skillManagerImpl) recordMissingResolutions(ctx context.Context, config *Config, skill *Skill) error {
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var missing []*Skill
+	for _, installed := range skill.InstalledSkills() {
+		if locked := lock.FindSkill(installed.Name); locked == nil || locked.Version == "" {
+			missing = append(missing, installed)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return s.lockManager.Update(ctx, func(lock *LockFile) {
+		for _, installed := range missing {
+			lock.RecordResolved(config.SealedSkill(installed), installed.Version)
+		}
+	})
+}
+
+// installToTargets copies the downloaded skill to all install targets and verifies the copies.
+// The version and hash the skill resolved to are recorded in the lock file first.
+func (s *skillManagerImpl) installToTargets(ctx context.Context, config *Config, sourcePath string, skill *Skill, version string) error {
+	if err := s.lockManager.Update(ctx, func(lock *LockFile) {
+		lock.RecordResolved(config.SealedSkill(skill), version)
+	}); err != nil {
+		return err
+	}
+
+	// Install to all targets (Requirements 3.4, 4.4, 10.2, 10.5, 6.6)
+	s.emit(ctx, skill.Name, PhaseCopy, "", "Installing skill '%s' to %d target(s)...", skill.Name, len(config.InstallTargets))
+	if err := s.copySkillToTargets(ctx, config, sourcePath, skill, version); err != nil {
+		return fmt.Errorf("failed to copy skill '%s' to install targets: %w. Check file permissions", skill.Name, err)
+	}
+
+	// Verify hash after installation (Requirements 6.4, 6.5)
+	s.emit(ctx, skill.Name, PhaseVerify, "", "Verifying installation of skill '%s'...", skill.Name)
+	// Remote targets cannot be hashed locally and are verified on the remote machine,
+	// and targets of agents that do not support the skill have nothing to verify
+	localTargets, err := s.installedTargets(ctx, skill, config.LocalInstallTargets())
+	if err != nil {
+		return err
+	}
+	if err := s.verifyInstalledSkill(ctx, config, skill, localTargets); err != nil {
+		if config.HashMismatchFor(skill) == HashMismatchFail {
+			return fmt.Errorf("hash verification failed for skill '%s': %w", skill.Name, err)
+		}
+		// Show warning but continue (Requirement 6.5, 12.1, 12.2)
+		s.emit(ctx, skill.Name, PhaseWarning, "", "WARNING: Hash verification failed for skill '%s': %v. The skill may have been tampered with during installation.", skill.Name, err)
+	}
+
+	s.emit(ctx, skill.Name, PhaseDone, "", "Successfully installed skill '%s'", skill.Name)
+	return nil
+}
+
+// installedTargets returns the targets the skill was installed into, leaving out those of
+// incompatible agents according to the lock file.
+func (s *skillManagerImpl) installedTargets(ctx context.Context, skill *Skill, targets []string) ([]string, error) {
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locked := lock.FindSkill(skill.Name)
+
+	return slices.DeleteFunc(slices.Clone(targets), func(target string) bool {
+		status := locked.TargetStatus(target)
+		return status != nil && status.Incompatible != ""
+	}), nil
+}
+
+// isInstalledInAllTargets reports whether the lock file shows the skill's pinned version and hash
+// installed in every install target with unmodified files.
+// Skills without a pinned version and hash (e.g., go.mod versions) always need to be resolved, so they never match.
+func (s *skillManagerImpl) isInstalledInAllTargets(ctx context.Context, config *Config, skill *Skill) bool {
+	// Entries with sub_dirs match when the recorded members still follow sub_dirs and all of them match
+	if skill.IsGroup() {
+		if skill.Version == "" || !skill.membersMatchSubDirs() {
+			return false
+		}
+		for _, member := range skill.InstalledSkills() {
+			if !s.isInstalledInAllTargets(ctx, config, member) {
+				return false
+			}
 		}
+		return true
 	}
 
-	// Get install targets (Requirement 6.2)
-	installTargets := config.InstallTargets
-	if len(installTargets) == 0 {
-		return fmt.Errorf("no install targets configured. Run 'skills-pkg init --install-dir <dir>' to configure install targets")
+	if skill.Version == "" || skill.HashValue == "" || len(config.InstallTargets) == 0 {
+		return false
 	}
 
-	// Install to all targets (Requirements 3.4, 4.4, 10.2, 10.5, 6.6)
-	fmt.Printf("Installing skill '%s' to %d target(s)...\n", skill.Name, len(installTargets))
-	if err := s.copySkillToTargets(sourcePath, skill.Name, installTargets); err != nil {
-		return fmt.Errorf("failed to copy skill '%s' to install targets: %w. Check file permissions", skill.Name, err)
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return false
 	}
+	locked := lock.FindSkill(skill.Name)
 
-	// Verify hash after installation (Requirements 6.4, 6.5)
-	fmt.Printf("Verifying installation of skill '%s'...\n", skill.Name)
-	if err := s.verifyInstalledSkill(ctx, skill, installTargets); err != nil {
-		// Show warning but continue (Requirement 6.5, 12.1, 12.2)
-		fmt.Printf("WARNING: Hash verification failed for skill '%s': %v. The skill may have been tampered with during installation.\n", skill.Name, err)
+	for _, target := range config.InstallTargets {
+		expected := skill.ForTarget(target)
+		if !s.isInstalledInTarget(ctx, config, expected, expected.Version, locked.TargetStatus(target)) {
+			return false
+		}
 	}
 
-	fmt.Printf("Successfully installed skill '%s'\n", skill.Name)
-	return nil
+	return true
+}
+
+// resolveDefaultVersion returns the version to request from the package manager
+// for a skill without an explicit version, according to the [defaults] section.
+// An empty result leaves the choice to the package manager's built-in behavior.
+func (s *skillManagerImpl) resolveDefaultVersion(ctx context.Context, config *Config, pm port.PackageManager, source *port.Source) (string, error) {
+	switch source.Type {
+	case "git":
+		if config.Defaults.GitVersionStrategy() == VersionStrategyLatestTag {
+			return pm.GetLatestVersion(ctx, source)
+		}
+	case "go-mod":
+		if !config.Defaults.UseGoMod() {
+			return "latest", nil
+		}
+	}
+
+	return "", nil
 }
 
 // Update updates the specified skill to the latest version.
 // If skillName is empty, it updates all skills from the configuration.
-// When dryRun is true, only checks for available updates without applying any changes.
+// Skills rejected by the exclude and source filters of opts are skipped and not reported.
+// When opts.DryRun is true, only checks for available updates without applying any changes.
 // Requirements: 5.3, 7.1, 7.2, 7.5, 7.6, 12.1, 12.2, 12.3
-func (s *skillManagerImpl) Update(ctx context.Context, skillNames []string, dryRun bool) ([]*UpdateResult, error) {
+func (s *skillManagerImpl) Update(ctx context.Context, skillNames []string, opts *UpdateOptions) ([]*UpdateResult, error) {
 	// Load configuration (Requirement 7.1)
 	config, err := s.configManager.Load(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	if canary := opts.canary(); canary != "" && !slices.Contains(config.InstallTargets, canary) {
+		return nil, &ErrorInstallTargetNotFound{Target: canary}
+	}
+
 	// Determine which skills to update (Requirements 7.1, 7.2)
 	var skillsToUpdate []*Skill
 	for _, skillName := range skillNames {
-		skill := config.FindSkillByName(skillName)
+		skill := config.FindSkillEntry(skillName)
 		if skill == nil {
 			// Requirement 12.2, 12.3
 			return nil, &ErrorSkillsNotFound{SkillNames: []string{skillName}}
 		}
-		skillsToUpdate = append(skillsToUpdate, skill)
+		if !slices.Contains(skillsToUpdate, skill) {
+			skillsToUpdate = append(skillsToUpdate, skill)
+		}
 	}
 	if len(skillNames) == 0 {
 		// Update all skills (Requirement 7.1)
 		skillsToUpdate = config.Skills
 	}
+	skillsToUpdate = slices.DeleteFunc(slices.Clone(skillsToUpdate), func(skill *Skill) bool {
+		return !opts.selects(skill)
+	})
 
-	// Process skills concurrently using errgroup
+	// Check skills by priority, concurrently within the same priority
 	results := make([]*UpdateResult, len(skillsToUpdate))
-	eg, egCtx := errgroup.WithContext(ctx)
-	for i, skill := range skillsToUpdate {
-		eg.Go(func() error {
-			result, err := s.updateSingleSkill(egCtx, config, skill, dryRun)
-			if err != nil {
-				return err
-			}
-			results[i] = result
+	newPaths := make([]string, len(skillsToUpdate))
+	if err := s.forEachByPriority(ctx, skillsToUpdate, func(ctx context.Context, i int, skill *Skill) error {
+		result, newPath, err := s.checkSingleSkillUpdate(ctx, config, skill, opts)
+		if err != nil {
+			return fmt.Errorf("failed to check single skill update for skill '%s': %w", skill.Name, err)
+		}
+		results[i], newPaths[i] = result, newPath
 
-			return nil
-		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if opts.dryRun() {
+		return results, nil
 	}
 
-	// Wait for all operations to complete
-	if err := eg.Wait(); err != nil {
-		return nil, err
+	// New versions may build on skills that are not installed yet, which are installed before them;
+	// canaries are only trialed
+	if opts.canary() == "" {
+		if err := s.installUpdateDependencies(ctx, config, skillsToUpdate, results, newPaths); err != nil {
+			return nil, err
+		}
 	}
 
-	// Save configuration only when not in dry-run mode
-	if !dryRun {
-		if err := s.configManager.Save(ctx, config); err != nil {
-			return nil, fmt.Errorf("failed to save configuration: %w", err)
+	// Apply the updates by priority, concurrently within the same priority
+	if err := s.forEachByPriority(ctx, skillsToUpdate, func(ctx context.Context, i int, skill *Skill) error {
+		result, err := s.updateSingleSkill(ctx, config, skill, opts, results[i], newPaths[i])
+		if err != nil {
+			return err
 		}
+		results[i] = result
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.configManager.Save(ctx, config); err != nil {
+		return nil, fmt.Errorf("failed to save configuration: %w", err)
 	}
 
 	return results, nil
 }
 
-// updateSingleSkill updates a single skill to the latest version.
-// If saveConfig is true, saves the configuration after updating skill metadata.
-// Requirements: 5.3, 7.1, 7.2, 7.5, 7.6, 12.1, 12.2, 12.3
-func (s *skillManagerImpl) updateSingleSkill(ctx context.Context, config *Config, skill *Skill, dryRun bool) (*UpdateResult, error) {
-	updateResult, newPath, err := s.checkSingleSkillUpdate(ctx, config, skill)
+// installUpdateDependencies installs the skills that the updated skills depend on, reading the
+// dependencies their SKILL.md declares from the new versions in newPaths.
+func (s *skillManagerImpl) installUpdateDependencies(ctx context.Context, config *Config, skills []*Skill, results []*UpdateResult, newPaths []string) error {
+	copies := make(map[*Skill][]string)
+	for i, skill := range skills {
+		if newPaths[i] == "" || results[i].HeldBack {
+			continue
+		}
+		copies[skill] = []string{newPaths[i]}
+		if skill.IsGroup() {
+			members, err := expandSubDirs(config, skill, newPaths[i])
+			if err != nil {
+				return err
+			}
+			copies[skill] = nil
+			for _, member := range members {
+				copies[skill] = append(copies[skill], filepath.Join(newPaths[i], filepath.FromSlash(member.SubDir)))
+			}
+		}
+	}
+
+	waves, _, err := s.installOrder(ctx, config, skills, copies, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check single skill update for skill '%s': %w", skill.Name, err)
+		return err
+	}
+	for _, wave := range waves {
+		wave = slices.DeleteFunc(wave, func(skill *Skill) bool { return slices.Contains(skills, skill) })
+		if err := s.forEachByPriority(ctx, wave, func(ctx context.Context, _ int, skill *Skill) error {
+			return s.installSkill(ctx, config, skill, false)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateSingleSkill applies the update of a single skill that checkSingleSkillUpdate found, whose new
+// version was downloaded to newPath.
+// Requirements: 5.3, 7.1, 7.2, 7.5, 7.6, 12.1, 12.2, 12.3
+func (s *skillManagerImpl) updateSingleSkill(ctx context.Context, config *Config, skill *Skill, opts *UpdateOptions, updateResult *UpdateResult, newPath string) (*UpdateResult, error) {
+	if updateResult.HeldBack {
+		return updateResult, nil
+	}
+
+	// Only the canary target receives the new version
+	if canary := opts.canary(); canary != "" {
+		return s.installCanary(ctx, config, skill, updateResult, newPath, canary)
+	}
+
+	if err := s.checkTargetsWritable(config.InstallTargets); err != nil {
+		return nil, err
 	}
 
-	if dryRun {
-		// In dry-run mode, we only check for updates and return the result without applying changes
+	// The update supersedes a canary, so every target receives the new version
+	skill.Canary = nil
+
+	// Entries with sub_dirs install all their skills from the new version
+	if skill.IsGroup() {
+		downloadResult := &port.DownloadResult{Path: newPath, Version: updateResult.NewVersion, FromGoMod: skill.Version == ""}
+		if err := s.installSkillGroup(ctx, config, skill, downloadResult, false); err != nil {
+			return nil, fmt.Errorf("failed to install updated skill '%s': %w", skill.Name, err)
+		}
 		return updateResult, nil
 	}
 
+	newPath, cleanup, err := s.checkContent(ctx, config, newPath, skill, updateResult.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	// Calculate hash only if not from go.mod (Requirement 5.3, 7.5)
 	// When version is resolved from go.mod, rely on go.sum for integrity verification
 	if skill.Version != "" {
 		// Update version
 		skill.Version = updateResult.NewVersion
 
-		hashResult, err := s.hashService.CalculateHash(ctx, newPath)
+		hashResult, err := s.hashService.CalculateHash(ctx, newPath, config.EffectiveHashAlgorithm(), config.HashOptions(skill)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate hash for skill '%s': %w", skill.Name, err)
 		}
 		skill.HashValue = hashResult.Value
 	}
 
+	if err := s.lockManager.Update(ctx, func(lock *LockFile) {
+		lock.RecordResolved(config.SealedSkill(skill), updateResult.NewVersion)
+	}); err != nil {
+		return nil, err
+	}
+
 	// Get install targets
 	installTargets := config.InstallTargets
 	if len(installTargets) > 0 {
 		// Install to all targets (Requirements 10.2, 10.5)
-		if err := s.copySkillToTargets(newPath, skill.Name, installTargets); err != nil {
+		if err := s.copySkillToTargets(ctx, config, newPath, skill, updateResult.NewVersion); err != nil {
 			// Filesystem error handling (Requirement 12.2, 12.3)
 			return nil, fmt.Errorf("failed to copy updated skill '%s' to install targets: %w. Check file permissions", skill.Name, err)
 		}
@@ -480,17 +1734,119 @@ func (s *skillManagerImpl) updateSingleSkill(ctx context.Context, config *Config
 	return updateResult, nil
 }
 
+// installCanary installs the updated skill into the canary target only and records the new version
+// as the skill's canary. The configured version stays in the other install targets until it is promoted.
+func (s *skillManagerImpl) installCanary(ctx context.Context, config *Config, skill *Skill, updateResult *UpdateResult, newPath, target string) (*UpdateResult, error) {
+	if skill.IsGroup() {
+		return nil, fmt.Errorf("skill '%s' installs several skills from sub_dirs and cannot be updated as a canary", skill.Name)
+	}
+	if updateResult.NewVersion == skill.Version {
+		return updateResult, nil
+	}
+
+	if err := s.checkTargetsWritable([]string{target}); err != nil {
+		return nil, err
+	}
+	newPath, cleanup, err := s.checkContent(ctx, config, newPath, skill, updateResult.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	hashResult, err := s.hashService.CalculateHash(ctx, newPath, config.EffectiveHashAlgorithm(), config.HashOptions(skill)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate hash for skill '%s': %w", skill.Name, err)
+	}
+	skill.Canary = &SkillCanary{Target: target, Version: updateResult.NewVersion, HashValue: hashResult.Value}
+
+	canaryConfig := *config
+	canaryConfig.InstallTargets = []string{target}
+	if err := s.copySkillToTargets(ctx, &canaryConfig, newPath, skill.ForTarget(target), updateResult.NewVersion); err != nil {
+		return nil, fmt.Errorf("failed to copy canary of skill '%s' to %s: %w. Check file permissions", skill.Name, target, err)
+	}
+
+	updateResult.Canary = target
+	return updateResult, nil
+}
+
+// Promote installs the canary version of each skill into every install target and makes it the
+// configured version. The canary target already holds it and is skipped by the installation.
+func (s *skillManagerImpl) Promote(ctx context.Context, skillNames []string) ([]*UpdateResult, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var skillsToPromote []*Skill
+	for _, skillName := range skillNames {
+		skill := config.FindSkillByName(skillName)
+		if skill == nil {
+			return nil, &ErrorSkillsNotFound{SkillNames: []string{skillName}}
+		}
+		if skill.Canary == nil {
+			return nil, &ErrorNoCanary{SkillName: skillName}
+		}
+		skillsToPromote = append(skillsToPromote, skill)
+	}
+	if len(skillNames) == 0 {
+		for _, skill := range config.Skills {
+			if skill.Canary != nil {
+				skillsToPromote = append(skillsToPromote, skill)
+			}
+		}
+	}
+
+	results := make([]*UpdateResult, len(skillsToPromote))
+	for i, skill := range skillsToPromote {
+		results[i] = &UpdateResult{SkillName: skill.Name, OldVersion: skill.Version, NewVersion: skill.Canary.Version}
+		skill.Version = skill.Canary.Version
+		skill.HashValue = skill.Canary.HashValue
+		skill.Canary = nil
+	}
+	if err := s.forEachByPriority(ctx, skillsToPromote, func(ctx context.Context, _ int, skill *Skill) error {
+		return s.installSkill(ctx, config, skill, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.configManager.Save(ctx, config); err != nil {
+		return nil, fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	return results, nil
+}
+
+// newestAllowedVersion returns the newest version of the skill within the allowed version bump,
+// when its package manager can list versions, and latestVersion otherwise or when there is none.
+func (s *skillManagerImpl) newestAllowedVersion(ctx context.Context, pm port.PackageManager, source *port.Source, skill *Skill, opts *UpdateOptions, latestVersion string) (string, error) {
+	lister, ok := pm.(port.VersionLister)
+	if !ok {
+		return latestVersion, nil
+	}
+
+	versions, err := lister.ListVersions(ctx, source)
+	if err != nil {
+		return "", fmt.Errorf("failed to list versions for skill '%s': %w", skill.Name, err)
+	}
+	if version := opts.newestAllowed(skill.Version, versions); version != "" {
+		return version, nil
+	}
+	return latestVersion, nil
+}
+
 // checkSingleSkillUpdate checks the latest available version for a single skill,
 // downloads it, and computes file-level diffs against the currently installed files.
-func (s *skillManagerImpl) checkSingleSkillUpdate(ctx context.Context, config *Config, skill *Skill) (*UpdateResult, string, error) {
+// When the latest version exceeds the allowed version bump, it checks the newest version within it instead,
+// and returns a held-back result without downloading when there is none.
+func (s *skillManagerImpl) checkSingleSkillUpdate(ctx context.Context, config *Config, skill *Skill, opts *UpdateOptions) (*UpdateResult, string, error) {
 	pm, err := s.selectPackageManager(skill.Source)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to select package manager for skill '%s': %w", skill.Name, err)
 	}
 
-	source := &port.Source{
-		Type: skill.Source,
-		URL:  skill.URL,
+	source, err := config.SourceOf(skill)
+	if err != nil {
+		return nil, "", err
 	}
 
 	latestVersion, err := pm.GetLatestVersion(ctx, source)
@@ -501,8 +1857,23 @@ func (s *skillManagerImpl) checkSingleSkillUpdate(ctx context.Context, config *C
 		return nil, "", fmt.Errorf("failed to get latest version for skill '%s': %w", skill.Name, err)
 	}
 
+	if !opts.allowsBump(skill.Version, latestVersion) {
+		latestVersion, err = s.newestAllowedVersion(ctx, pm, source, skill, opts, latestVersion)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if !opts.allowsBump(skill.Version, latestVersion) {
+		return &UpdateResult{
+			SkillName:  skill.Name,
+			OldVersion: skill.Version,
+			NewVersion: latestVersion,
+			HeldBack:   true,
+		}, "", nil
+	}
+
 	// Download the latest version to compute file diffs
-	downloadResult, err := pm.Download(ctx, source, latestVersion)
+	downloadResult, err := s.download(ctx, pm, source, latestVersion)
 	if err != nil {
 		if IsNetworkError(err) {
 			return nil, "", fmt.Errorf("failed to download skill '%s': %w. Check your network connection and source URL", skill.Name, err)
@@ -515,13 +1886,15 @@ func (s *skillManagerImpl) checkSingleSkillUpdate(ctx context.Context, config *C
 		newPath = filepath.Join(downloadResult.Path, skill.SubDir)
 		if _, statErr := os.Stat(newPath); statErr != nil {
 			if os.IsNotExist(statErr) {
-				return nil, "", fmt.Errorf("subdirectory '%s' not found in downloaded skill '%s'", skill.SubDir, skill.Name)
+				return nil, "", &ErrorSubDirNotFound{SkillName: skill.Name, SubDir: skill.SubDir, Suggestions: suggestSubDirs(downloadResult.Path, skill.SubDir)}
 			}
 			return nil, "", fmt.Errorf("failed to access subdirectory '%s' in skill '%s': %w", skill.SubDir, skill.Name, statErr)
 		}
 	}
 
-	if len(config.InstallTargets) == 0 {
+	// Entries with sub_dirs are not installed under their own name, so there is nothing to compare against
+	localTargets := config.LocalInstallTargets()
+	if len(localTargets) == 0 || skill.IsGroup() {
 		return &UpdateResult{
 			SkillName:  skill.Name,
 			OldVersion: skill.Version,
@@ -532,12 +1905,12 @@ func (s *skillManagerImpl) checkSingleSkillUpdate(ctx context.Context, config *C
 
 	// Resolve installed path from the first install target
 	oldPath := ""
-	candidate := filepath.Join(config.InstallTargets[0], skill.Name)
+	candidate := filepath.Join(localTargets[0], skill.DirName())
 	if _, statErr := os.Stat(candidate); statErr == nil {
 		oldPath = candidate
 	}
 
-	fileDiffs, err := computeFileDiffs(oldPath, newPath)
+	fileDiffs, err := computeFileDiffs(oldPath, newPath, config.GeneratedFiles())
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to compute file diffs for skill '%s': %w", skill.Name, err)
 	}
@@ -550,15 +1923,29 @@ func (s *skillManagerImpl) checkSingleSkillUpdate(ctx context.Context, config *C
 	}, newPath, nil
 }
 
-// computeFileDiffs returns the file-level diff between oldDir and newDir.
+// DiffSkillDirs returns the file-level diff between two installed copies of a skill.
+// Files only in dirA are reported as removed and files only in dirB as added.
+// Files matching the exclude patterns are not compared.
+func DiffSkillDirs(dirA, dirB string, exclude ...string) ([]*FileDiff, error) {
+	for _, dir := range []string{dirA, dirB} {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("failed to access skill directory %s: %w", dir, err)
+		}
+	}
+
+	return computeFileDiffs(dirA, dirB, exclude)
+}
+
+// computeFileDiffs returns the file-level diff between oldDir and newDir, leaving out the files
+// matching the exclude patterns.
 // If oldDir is empty or does not exist, all files in newDir are treated as added.
-func computeFileDiffs(oldDir, newDir string) ([]*FileDiff, error) {
-	oldFiles, err := collectFiles(oldDir)
+func computeFileDiffs(oldDir, newDir string, exclude []string) ([]*FileDiff, error) {
+	oldFiles, err := collectFiles(oldDir, exclude)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read old files: %w", err)
 	}
 
-	newFiles, err := collectFiles(newDir)
+	newFiles, err := collectFiles(newDir, exclude)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read new files: %w", err)
 	}
@@ -592,8 +1979,9 @@ func computeFileDiffs(oldDir, newDir string) ([]*FileDiff, error) {
 }
 
 // collectFiles walks dir and returns a map of relative path → file content.
+// Files excluded by the skill's ignore files or matching the exclude patterns are skipped.
 // Returns an empty map if dir is empty or does not exist.
-func collectFiles(dir string) (map[string]string, error) {
+func collectFiles(dir string, exclude []string) (map[string]string, error) {
 	files := make(map[string]string)
 	if dir == "" {
 		return files, nil
@@ -602,25 +1990,24 @@ func collectFiles(dir string) (map[string]string, error) {
 		return files, nil
 	}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
-		rel, err := filepath.Rel(dir, path)
-		if err != nil {
-			return err
+	relPaths, err := ListSkillFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	ignore := NewSkillIgnore(exclude)
+
+	for _, rel := range relPaths {
+		if ignore.Match(rel, false) {
+			continue
 		}
-		data, err := os.ReadFile(path)
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
 		if err != nil {
-			return err
+			return nil, err
 		}
-		files[rel] = string(data)
-		return nil
-	})
-	return files, err
+		files[filepath.FromSlash(rel)] = string(data)
+	}
+
+	return files, nil
 }
 
 // isBinaryContent reports whether content contains null bytes (binary heuristic).
@@ -633,7 +2020,7 @@ func lineDiff(oldContent, newContent string) string {
 	dmp := diffmatchpatch.New()
 	chars1, chars2, lineArray := dmp.DiffLinesToChars(oldContent, newContent)
 	diffs := dmp.DiffMain(chars1, chars2, false)
-	dmp.DiffCharsToLines(diffs, lineArray)
+	diffs = dmp.DiffCharsToLines(diffs, lineArray)
 
 	var sb strings.Builder
 	for _, d := range diffs {
@@ -661,7 +2048,7 @@ func lineDiff(oldContent, newContent string) string {
 // Requirements: 9.1, 9.2, 9.3, 9.4, 12.2
 func (s *skillManagerImpl) Uninstall(ctx context.Context, skillName string) error {
 	// Progress information (Requirement 12.1)
-	fmt.Printf("Uninstalling skill '%s'...\n", skillName)
+	s.emit(ctx, skillName, PhaseRemove, "", "Uninstalling skill '%s'...", skillName)
 
 	// Load configuration (Requirement 9.2)
 	config, err := s.configManager.Load(ctx)
@@ -676,17 +2063,35 @@ func (s *skillManagerImpl) Uninstall(ctx context.Context, skillName string) erro
 		return &ErrorSkillsNotFound{SkillNames: []string{skillName}}
 	}
 
+	// Skills that build on it would be left without it
+	if dependents := config.dependentsOf(skill); len(dependents) > 0 {
+		return &ErrorDependencyRequired{SkillName: skillName, Dependents: dependents}
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Remove skill from all install target directories (Requirement 9.1)
+	// Entries with sub_dirs remove every skill installed from them
+	installed := skill.InstalledSkills()
 	installTargets := config.InstallTargets
 	for _, target := range installTargets {
-		skillDir := target + "/" + skillName
-
-		// Remove skill directory if it exists
-		if err := os.RemoveAll(skillDir); err != nil {
-			// Filesystem error handling (Requirement 12.2, 12.3)
-			return fmt.Errorf("failed to remove skill directory at %s: %w. Check file permissions", skillDir, err)
+		for _, installedSkill := range installed {
+			// The lock file knows the directory of installations made before install_as changed
+			dir := installedSkill.DirName()
+			if status := lock.FindSkill(installedSkill.Name).TargetStatus(target); status != nil {
+				dir = status.DirName(installedSkill.Name)
+			}
+			if err := s.backupInstall(ctx, config, target, installedSkill.Name, dir); err != nil {
+				return err
+			}
+			if err := s.removeFromTarget(ctx, target, dir); err != nil {
+				return err
+			}
+			s.emit(ctx, installedSkill.Name, PhaseRemove, target, "Removed skill '%s' from %s", installedSkill.Name, target)
 		}
-		fmt.Printf("Removed skill '%s' from %s\n", skillName, target)
 	}
 
 	// Remove skill from configuration (Requirement 9.2)
@@ -694,7 +2099,147 @@ func (s *skillManagerImpl) Uninstall(ctx context.Context, skillName string) erro
 		return fmt.Errorf("failed to remove skill from configuration: %w", err)
 	}
 
+	if err := s.lockManager.Update(ctx, func(lock *LockFile) {
+		for _, installedSkill := range installed {
+			lock.RemoveSkill(installedSkill.Name)
+		}
+	}); err != nil {
+		return fmt.Errorf("failed to remove skill from lock file: %w", err)
+	}
+
 	// Success message (Requirement 9.4, 12.2)
-	fmt.Printf("Successfully uninstalled skill '%s'\n", skillName)
+	s.emit(ctx, skillName, PhaseRemove, "", "Successfully uninstalled skill '%s'", skillName)
+	return nil
+}
+
+// Prune removes installations recorded in the lock file whose skill was removed from the configuration
+// or whose install target is no longer listed in it, and returns the removed installations.
+// Only directories recorded in the lock file are removed, so skills installed by other means are left untouched.
+func (s *skillManagerImpl) Prune(ctx context.Context) ([]*PrunedInstall, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []*PrunedInstall
+	for _, locked := range lock.Skills {
+		configured := config.FindInstalledSkill(locked.Name) != nil
+		for _, status := range locked.Targets {
+			if configured && slices.Contains(config.InstallTargets, status.Path) {
+				continue
+			}
+			pruned = append(pruned, &PrunedInstall{SkillName: locked.Name, Target: status.Path, Dir: status.DirName(locked.Name)})
+		}
+	}
+
+	if err := s.removeInstalls(ctx, config, pruned); err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// PruneTarget removes the skills that skills-pkg installed into the install target, as found by
+// installsIn. Other directories in the target are left untouched. The target does not need to be
+// in the configuration.
+func (s *skillManagerImpl) PruneTarget(ctx context.Context, target string) ([]*PrunedInstall, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pruned := s.installsIn(config, lock, target)
+	if err := s.removeInstalls(ctx, config, pruned); err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// installsIn returns the skills recorded in the lock file as installed into the install target,
+// and the configured skills found in it, whose installations may predate the lock file.
+func (s *skillManagerImpl) installsIn(config *Config, lock *LockFile, target string) []*PrunedInstall {
+	var installs []*PrunedInstall
+	for _, locked := range lock.Skills {
+		for _, status := range locked.Targets {
+			if SameTarget(status.Path, target) {
+				installs = append(installs, &PrunedInstall{SkillName: locked.Name, Target: status.Path, Dir: status.DirName(locked.Name)})
+			}
+		}
+	}
+	for _, skill := range config.InstalledSkills() {
+		if slices.ContainsFunc(installs, func(p *PrunedInstall) bool { return p.SkillName == skill.Name }) {
+			continue
+		}
+		// Remote targets cannot be checked cheaply; removing a missing skill from them is a no-op
+		if !IsRemoteTarget(target) {
+			if _, err := s.fsys.Lstat(target + "/" + skill.DirName()); err != nil {
+				continue
+			}
+		}
+		installs = append(installs, &PrunedInstall{SkillName: skill.Name, Target: target, Dir: skill.DirName()})
+	}
+	return installs
+}
+
+// removeInstalls backs up and deletes the installations from their install targets and the lock file.
+func (s *skillManagerImpl) removeInstalls(ctx context.Context, config *Config, pruned []*PrunedInstall) error {
+	for _, p := range pruned {
+		if err := s.backupInstall(ctx, config, p.Target, p.SkillName, p.Dir); err != nil {
+			return err
+		}
+		if err := s.removeFromTarget(ctx, p.Target, p.Dir); err != nil {
+			return err
+		}
+		if err := s.lockManager.Update(ctx, func(lock *LockFile) {
+			lock.RemoveInstall(p.SkillName, p.Target)
+		}); err != nil {
+			return fmt.Errorf("failed to remove skill '%s' from lock file: %w", p.SkillName, err)
+		}
+		s.emit(ctx, p.SkillName, PhaseRemove, p.Target, "Removed skill '%s' from %s", p.SkillName, p.Target)
+	}
+
+	return nil
+}
+
+// removeFromTarget deletes the skill directory dirName from the install target, releasing its shared store entry.
+// A skill that is not installed in the target is ignored.
+func (s *skillManagerImpl) removeFromTarget(ctx context.Context, target, dirName string) error {
+	if IsRemoteTarget(target) {
+		installer, err := s.selectRemoteInstaller(target)
+		if err != nil {
+			return err
+		}
+		if err := installer.Remove(ctx, target, dirName); err != nil {
+			return fmt.Errorf("failed to remove skill from %s: %w", target, err)
+		}
+		return nil
+	}
+
+	skillDir := target + "/" + dirName
+	entry := s.storeEntryOf(skillDir)
+
+	// Remove skill directory if it exists
+	if err := s.fsys.RemoveAll(skillDir); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return &ErrorTargetNotWritable{Target: target, Err: err}
+		}
+		// Filesystem error handling (Requirement 12.2, 12.3)
+		return fmt.Errorf("failed to remove skill directory at %s: %w. Check file permissions", skillDir, err)
+	}
+	if entry != "" {
+		if err := s.releaseStoreEntry(entry); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
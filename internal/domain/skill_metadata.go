@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SkillMetadataFile is the name of the file written into installed skills when skill_metadata is enabled.
+// It is not part of the skill, so hashes, packs, and file listings leave it out.
+const SkillMetadataFile = ".skillspkg.json"
+
+// SkillMetadata describes an installed skill for agents and wrapper scripts,
+// so that they can report which skill versions were active during a session.
+type SkillMetadata struct {
+	InstalledAt time.Time `json:"installed_at"`
+	Name        string    `json:"name"`
+	Source      string    `json:"source"`
+	URL         string    `json:"url"`
+	Version     string    `json:"version"`
+	Priority    int       `json:"priority,omitempty"`
+}
+
+// writeSkillMetadata writes the metadata file into the installed skill directory.
+func writeSkillMetadata(skillDir string, skill *Skill, version string, installedAt time.Time) error {
+	data, err := json.MarshalIndent(&SkillMetadata{
+		Name:        skill.Name,
+		Source:      skill.Source,
+		URL:         skill.URL,
+		Version:     version,
+		InstalledAt: installedAt,
+		Priority:    skill.Priority,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(skillDir, SkillMetadataFile), append(data, '\n'), 0o644)
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// walkSkillTree calls fn for every file and directory of the skill in root that is not excluded by
+// its ignore files, with directories before their contents. Symbolic links are followed, and fn is
+// called with the information of their targets and link set to true.
+//
+// A link fails the walk with ErrorSymlink when its target does not exist, is outside the skill,
+// contains the link, or is a directory already reached through another link. The last two keep
+// the walk finite, so skills with links can always be copied without following them endlessly.
+func walkSkillTree(root string, fn func(rel string, info fs.FileInfo, link bool) error) error {
+	ignore, err := LoadSkillIgnore(root)
+	if err != nil {
+		return err
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+
+	w := &skillTreeWalker{realRoot: realRoot, ignore: ignore, linkedDirs: make(map[string]string), fn: fn}
+	return w.walk(root, "", []string{realRoot})
+}
+
+type skillTreeWalker struct {
+	ignore     *SkillIgnore
+	linkedDirs map[string]string // Directories reached through links, mapped to the first link
+	fn         func(rel string, info fs.FileInfo, link bool) error
+	realRoot   string
+}
+
+// walk walks the directory dir at rel within the skill. ancestors are the resolved paths of dir and its parents.
+func (w *skillTreeWalker) walk(dir, rel string, ancestors []string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(dir, entry.Name())
+		entryRel := path.Join(rel, entry.Name())
+		link := entry.Type()&fs.ModeSymlink != 0
+
+		info, err := os.Stat(entryPath)
+		if err != nil {
+			if link && errors.Is(err, fs.ErrNotExist) {
+				return &ErrorSymlink{Path: entryRel, Reason: "its target does not exist"}
+			}
+			return err
+		}
+		if w.ignore.Match(entryRel, info.IsDir()) {
+			continue
+		}
+
+		realPath := filepath.Join(ancestors[len(ancestors)-1], entry.Name())
+		if link {
+			realPath, err = w.checkLink(entryPath, entryRel, info, ancestors)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := w.fn(entryRel, info, link); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if err := w.walk(entryPath, entryRel, append(slices.Clip(ancestors), realPath)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLink returns the resolved target of the link at entryPath, or ErrorSymlink when it cannot be followed.
+func (w *skillTreeWalker) checkLink(entryPath, entryRel string, info fs.FileInfo, ancestors []string) (string, error) {
+	target, err := filepath.EvalSymlinks(entryPath)
+	if err != nil {
+		return "", err
+	}
+
+	if rel, err := filepath.Rel(w.realRoot, target); err != nil || !filepath.IsLocal(rel) {
+		return "", &ErrorSymlink{Path: entryRel, Reason: fmt.Sprintf("its target %s is outside the skill", target)}
+	}
+
+	if info.IsDir() {
+		if slices.Contains(ancestors, target) {
+			return "", &ErrorSymlink{Path: entryRel, Reason: "it points to a directory that contains it"}
+		}
+		if first, ok := w.linkedDirs[target]; ok {
+			return "", &ErrorSymlink{Path: entryRel, Reason: fmt.Sprintf("its target is already linked from %s", first)}
+		}
+		w.linkedDirs[target] = entryRel
+	}
+
+	return target, nil
+}
+
+// checkTree enforces the symlinks policy and max_depth on the downloaded skill in sourcePath, and
+// returns the directory to install the skill from. When links are flattened, that is a copy with
+// the links replaced by their targets, so hashes and verification see the files as installed.
+// The returned function removes the copy, and must be called once the directory is no longer used.
+func (s *skillManagerImpl) checkTree(ctx context.Context, config *Config, sourcePath string, skill *Skill) (string, func(), error) {
+	maxDepth := config.EffectiveMaxDepth()
+	links := 0
+	err := walkSkillTree(sourcePath, func(rel string, info fs.FileInfo, link bool) error {
+		if link {
+			if config.EffectiveSymlinks() == SymlinksReject {
+				return &ErrorSymlink{Path: rel, Reason: "symbolic links are rejected by the symlinks setting"}
+			}
+			links++
+		}
+		if info.IsDir() && strings.Count(rel, "/")+1 > maxDepth {
+			return &ErrorMaxDepthExceeded{SkillName: skill.Name, Path: rel, MaxDepth: maxDepth}
+		}
+		return nil
+	})
+	if e, ok := errors.AsType[*ErrorSymlink](err); ok {
+		e.SkillName = skill.Name
+		return "", nil, e
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if links == 0 {
+		return sourcePath, func() {}, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "skills-pkg-flattened-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create directory for flattened skill '%s': %w", skill.Name, err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	// A subdirectory keeps the permissions of the skill directory, which the temporary directory does not have
+	flattened := filepath.Join(tmp, "skill")
+	if err := CopyDir(sourcePath, flattened, nil); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to flatten symbolic links of skill '%s': %w", skill.Name, err)
+	}
+	s.emit(ctx, skill.Name, PhaseCheck, "", "Replaced %d symbolic link(s) in skill '%s' with copies of their targets", links, skill.Name)
+
+	return flattened, cleanup, nil
+}
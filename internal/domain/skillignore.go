@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// SkillIgnoreFileName is the name of the file listing paths excluded from a skill.
+const SkillIgnoreFileName = ".skillignore"
+
+// ignoreFileNames lists the root-level files whose gitignore-style patterns
+// exclude paths from a skill. Patterns from later files take precedence.
+var ignoreFileNames = []string{".gitignore", SkillIgnoreFileName}
+
+// SkillIgnore matches paths that are excluded from a skill directory.
+// Excluded paths are neither copied to install targets nor included in hash calculation.
+// A nil *SkillIgnore matches nothing.
+type SkillIgnore struct {
+	matcher gitignore.Matcher
+}
+
+// LoadSkillIgnore reads the .gitignore and .skillignore files at the root of dir.
+// It returns nil when neither file exists.
+func LoadSkillIgnore(dir string) (*SkillIgnore, error) {
+	return LoadSkillIgnoreFS(port.OSFileSystem{}, dir)
+}
+
+// LoadSkillIgnoreFS is LoadSkillIgnore on the file system fsys.
+func LoadSkillIgnoreFS(fsys port.FileSystem, dir string) (*SkillIgnore, error) {
+	var patterns []gitignore.Pattern
+	for _, name := range ignoreFileNames {
+		filePatterns, err := readIgnorePatterns(fsys, filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, filePatterns...)
+	}
+
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	return &SkillIgnore{matcher: gitignore.NewMatcher(patterns)}, nil
+}
+
+// NewSkillIgnore returns a SkillIgnore matching the gitignore-style patterns.
+// It returns nil when there are no patterns.
+func NewSkillIgnore(patterns []string) *SkillIgnore {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	parsed := make([]gitignore.Pattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		parsed = append(parsed, gitignore.ParsePattern(pattern, nil))
+	}
+	return &SkillIgnore{matcher: gitignore.NewMatcher(parsed)}
+}
+
+// readIgnorePatterns parses a gitignore-style file. A missing file yields no patterns.
+func readIgnorePatterns(fsys port.FileSystem, path string) ([]gitignore.Pattern, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", path, err)
+	}
+
+	return patterns, nil
+}
+
+// Match reports whether the slash-separated path relative to the skill root is excluded.
+func (i *SkillIgnore) Match(relPath string, isDir bool) bool {
+	if i == nil {
+		return false
+	}
+	return i.matcher.Match(strings.Split(filepath.ToSlash(relPath), "/"), isDir)
+}
+
+// ListSkillFiles returns the slash-separated relative paths of all regular files in dir
+// that are not excluded by the skill's ignore files, in lexical order.
+// dir may be a symbolic link to the skill directory, as created for skills in the shared store.
+func ListSkillFiles(dir string) ([]string, error) {
+	return ListSkillFilesFS(port.OSFileSystem{}, dir)
+}
+
+// ListSkillFilesFS is ListSkillFiles on the file system fsys.
+func ListSkillFilesFS(fsys port.FileSystem, dir string) ([]string, error) {
+	ignore, err := LoadSkillIgnoreFS(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	var walk func(path, rel string) error
+	walk = func(path, rel string) error {
+		// Reading the directory follows dir itself when it is a link
+		entries, err := fsys.ReadDir(path)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			entryRel := filepath.Join(rel, entry.Name())
+			if ignore.Match(entryRel, entry.IsDir()) {
+				continue
+			}
+			if entry.IsDir() {
+				if err := walk(filepath.Join(path, entry.Name()), entryRel); err != nil {
+					return err
+				}
+				continue
+			}
+			files = append(files, filepath.ToSlash(entryRel))
+		}
+		return nil
+	}
+	if err := walk(dir, ""); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
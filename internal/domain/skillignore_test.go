@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestListSkillFiles(t *testing.T) {
+	tests := []struct {
+		files map[string]string
+		name  string
+		want  []string
+	}{
+		{
+			name: "no ignore files",
+			files: map[string]string{
+				"SKILL.md":          "skill",
+				"scripts/run.sh":    "echo",
+				"references/doc.md": "doc",
+			},
+			want: []string{"SKILL.md", "references/doc.md", "scripts/run.sh"},
+		},
+		{
+			name: "skillignore excludes files and directories",
+			files: map[string]string{
+				".skillignore":     "*.log\ndev/\n# comment\n",
+				"SKILL.md":         "skill",
+				"debug.log":        "log",
+				"dev/fixture.json": "{}",
+				"nested/trace.log": "log",
+			},
+			want: []string{".skillignore", "SKILL.md"},
+		},
+		{
+			name: "skillignore negation overrides gitignore",
+			files: map[string]string{
+				".gitignore":   "*.tmp\n",
+				".skillignore": "!keep.tmp\n",
+				"SKILL.md":     "skill",
+				"drop.tmp":     "tmp",
+				"keep.tmp":     "tmp",
+			},
+			want: []string{".gitignore", ".skillignore", "SKILL.md", "keep.tmp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := domain.ListSkillFiles(dir)
+			if err != nil {
+				t.Fatalf("ListSkillFiles() error = %v", err)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListSkillFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Store is a machine-wide, content-addressed store of skill files.
+// When shared_store is enabled, each distinct skill content is kept once in the store and
+// install targets of every project receive a symbolic link to it.
+//
+// Layout:
+//
+//	<root>/entries/<key>/      skill files, where key is derived from the content hash
+//	<root>/refs/<key>/<id>     one file per link to the entry, containing the link path
+//
+// A reference is only counted while its link still points to the entry, so links removed
+// by hand or deleted projects never keep an entry alive.
+type Store struct {
+	root string
+	mu   sync.Mutex
+}
+
+// NewStore creates a new Store rooted at root.
+func NewStore(root string) *Store {
+	return &Store{root: root}
+}
+
+// Root returns the directory of the store.
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Add stores the files of the skill directory src, whose content hash is hashValue,
+// and returns the entry directory. Content that is already stored is reused.
+func (s *Store) Add(src, hashValue string) (string, error) {
+	key, err := storeKey(hashValue)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := filepath.Join(s.root, "entries", key)
+	if _, err := os.Stat(entry); err == nil {
+		return entry, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(entry), installDirMode); err != nil {
+		return "", fmt.Errorf("failed to create store directory %s: %w", filepath.Dir(entry), err)
+	}
+
+	// Copy into a temporary directory first so that other processes never see a partial entry
+	tmp, err := os.MkdirTemp(filepath.Dir(entry), ".tmp-"+key+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary store entry: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	// Entries are renamed into place, so their contents are flushed first to never expose a truncated file
+	if err := CopyDir(src, tmp, &CopyOptions{Sync: true}); err != nil {
+		return "", fmt.Errorf("failed to copy skill into the store: %w", err)
+	}
+	if err := os.Rename(tmp, entry); err != nil {
+		// Another process may have stored the same content in the meantime
+		if _, statErr := os.Stat(entry); statErr == nil {
+			return entry, nil
+		}
+		return "", fmt.Errorf("failed to add entry %s to the store: %w", key, err)
+	}
+
+	return entry, nil
+}
+
+// Link creates a symbolic link at linkPath to the store entry and records the reference.
+// The link is created next to linkPath and swapped in, so that a skill already installed at
+// linkPath is replaced without the path ever being missing.
+func (s *Store) Link(entry, linkPath string) error {
+	absLink, err := filepath.Abs(linkPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", linkPath, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staging, err := os.MkdirTemp(filepath.Dir(linkPath), "."+filepath.Base(linkPath)+".link-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory next to %s: %w", linkPath, err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	staged := filepath.Join(staging, filepath.Base(linkPath))
+	if err := os.Symlink(entry, staged); err != nil {
+		return fmt.Errorf("failed to link %s to the shared store: %w. Set shared_store = false to copy skills instead", linkPath, err)
+	}
+	if err := replaceAtomically(staged, linkPath); err != nil {
+		return fmt.Errorf("failed to link %s to the shared store: %w", linkPath, err)
+	}
+
+	refDir := filepath.Join(s.root, "refs", filepath.Base(entry))
+	if err := os.MkdirAll(refDir, installDirMode); err != nil {
+		return fmt.Errorf("failed to create store reference directory %s: %w", refDir, err)
+	}
+	sum := sha256.Sum256([]byte(absLink))
+	if err := os.WriteFile(filepath.Join(refDir, hex.EncodeToString(sum[:8])), []byte(absLink), configFileMode); err != nil {
+		return fmt.Errorf("failed to record store reference for %s: %w", linkPath, err)
+	}
+
+	return nil
+}
+
+// EntryOf returns the store entry that linkPath links to, or an empty string when
+// linkPath is not a link into the store.
+func (s *Store) EntryOf(linkPath string) string {
+	dest, err := os.Readlink(linkPath)
+	if err != nil {
+		return ""
+	}
+	if filepath.Dir(dest) != filepath.Join(s.root, "entries") {
+		return ""
+	}
+	return dest
+}
+
+// Release drops references to the store entry whose links no longer point to it,
+// and deletes the entry when no references remain. It reports whether the entry was deleted.
+func (s *Store) Release(entry string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.release(filepath.Base(entry))
+}
+
+// Prune releases every entry in the store and returns the keys of the deleted entries.
+func (s *Store) Prune() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.root, "entries"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read store %s: %w", s.root, err)
+	}
+
+	var removed []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".tmp-") {
+			continue
+		}
+		deleted, err := s.release(e.Name())
+		if err != nil {
+			return removed, err
+		}
+		if deleted {
+			removed = append(removed, e.Name())
+		}
+	}
+
+	return removed, nil
+}
+
+func (s *Store) release(key string) (bool, error) {
+	entry := filepath.Join(s.root, "entries", key)
+	refDir := filepath.Join(s.root, "refs", key)
+
+	refs, err := os.ReadDir(refDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to read store references %s: %w", refDir, err)
+	}
+
+	live := 0
+	for _, ref := range refs {
+		refPath := filepath.Join(refDir, ref.Name())
+		linkPath, err := os.ReadFile(refPath)
+		if err == nil {
+			if dest, err := os.Readlink(string(linkPath)); err == nil && dest == entry {
+				live++
+				continue
+			}
+		}
+		if err := os.Remove(refPath); err != nil {
+			return false, fmt.Errorf("failed to remove stale store reference %s: %w", refPath, err)
+		}
+	}
+	if live > 0 {
+		return false, nil
+	}
+
+	if err := os.RemoveAll(entry); err != nil {
+		return false, fmt.Errorf("failed to remove store entry %s: %w", entry, err)
+	}
+	if err := os.RemoveAll(refDir); err != nil {
+		return false, fmt.Errorf("failed to remove store references %s: %w", refDir, err)
+	}
+
+	return true, nil
+}
+
+// storeKey derives the entry name from a content hash such as "h1:<base64>".
+func storeKey(hashValue string) (string, error) {
+	algorithm, encoded, ok := strings.Cut(hashValue, ":")
+	if !ok || algorithm == "" {
+		return "", fmt.Errorf("invalid hash value '%s' for the shared store", hashValue)
+	}
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid hash value '%s' for the shared store: %w", hashValue, err)
+	}
+	return algorithm + "-" + hex.EncodeToString(sum), nil
+}
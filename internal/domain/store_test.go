@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+// storeHash is a valid content hash used as the address of store entries in tests.
+const storeHash = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+func TestStore_AddLinkRelease(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require extra privileges on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	store := domain.NewStore(filepath.Join(tmpDir, "store"))
+
+	src := filepath.Join(tmpDir, "src")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("skill"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	entry, err := store.Add(src, storeHash)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if again, err := store.Add(src, storeHash); err != nil || again != entry {
+		t.Fatalf("second Add() = %s, %v, want the existing entry %s", again, err, entry)
+	}
+
+	linkA := filepath.Join(tmpDir, "project-a", "my-skill")
+	linkB := filepath.Join(tmpDir, "project-b", "my-skill")
+	for _, link := range []string{linkA, linkB} {
+		if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+			t.Fatalf("failed to create target: %v", err)
+		}
+		if err := store.Link(entry, link); err != nil {
+			t.Fatalf("Link() error = %v", err)
+		}
+		if got := store.EntryOf(link); got != entry {
+			t.Errorf("EntryOf(%s) = %s, want %s", link, got, entry)
+		}
+		if data, err := os.ReadFile(filepath.Join(link, "SKILL.md")); err != nil || string(data) != "skill" {
+			t.Errorf("linked SKILL.md = %q, %v", data, err)
+		}
+	}
+
+	// The entry is kept while another link still uses it
+	if err := os.Remove(linkA); err != nil {
+		t.Fatalf("failed to remove link: %v", err)
+	}
+	deleted, err := store.Release(entry)
+	if err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if deleted {
+		t.Fatal("Release() deleted an entry that is still linked")
+	}
+
+	// A link replaced by a regular directory no longer counts as a reference
+	if err := os.Remove(linkB); err != nil {
+		t.Fatalf("failed to remove link: %v", err)
+	}
+	if err := os.MkdirAll(linkB, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	removed, err := store.Prune()
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 1 {
+		t.Errorf("Prune() removed = %v, want 1 entry", removed)
+	}
+	if _, err := os.Stat(entry); !os.IsNotExist(err) {
+		t.Errorf("entry should have been deleted, stat error = %v", err)
+	}
+}
+
+func TestStore_AddInvalidHash(t *testing.T) {
+	store := domain.NewStore(t.TempDir())
+	if _, err := store.Add(t.TempDir(), "mockHash123"); err == nil {
+		t.Error("Add() with a malformed hash should fail")
+	}
+}
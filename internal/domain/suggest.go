@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxSubDirSuggestions is the number of similar directories suggested for a missing subdirectory.
+const maxSubDirSuggestions = 3
+
+// EditDistance returns the Levenshtein distance between a and b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// suggestSubDirs returns the directories below root whose path is closest to subDir, closest first.
+// Directories that differ only in case or were moved with the same name rank first,
+// followed by paths within a small edit distance. Hidden directories such as .git are skipped.
+func suggestSubDirs(root, subDir string) []string {
+	want := strings.ToLower(strings.Trim(subDir, "/"))
+	maxDistance := max(2, len([]rune(want))/3)
+
+	type candidate struct {
+		path     string
+		distance int
+	}
+	var candidates []candidate
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || p == root {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		got := strings.ToLower(rel)
+		distance := EditDistance(got, want)
+		if distance > 0 && path.Base(got) == path.Base(want) {
+			// Moved to another parent directory
+			distance = 1
+		}
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{path: rel, distance: distance})
+		}
+		return nil
+	})
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.path, b.path)
+	})
+
+	suggestions := make([]string, 0, min(len(candidates), maxSubDirSuggestions))
+	for _, c := range candidates[:min(len(candidates), maxSubDirSuggestions)] {
+		suggestions = append(suggestions, c.path)
+	}
+	return suggestions
+}
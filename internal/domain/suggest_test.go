@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "codex", b: "codex", want: 0},
+		{a: "codex", b: "codx", want: 1},
+		{a: ".claude", b: ".cluade", want: 2},
+		{a: "", b: "abc", want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestSubDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"skills/Code-Review", "skills/code-reviews", "skills/testing", "archive/pdf-tools", ".git/skills/pdf-tools"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		subDir string
+		want   []string
+	}{
+		{name: "case-only rename", subDir: "skills/code-review", want: []string{"skills/Code-Review", "skills/code-reviews"}},
+		{name: "moved to another directory", subDir: "skills/pdf-tools", want: []string{"archive/pdf-tools"}},
+		{name: "typo", subDir: "skills/testng/", want: []string{"skills/testing"}},
+		{name: "removed", subDir: "skills/deployment", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestSubDirs(root, tt.subDir); !slices.Equal(got, tt.want) {
+				t.Errorf("suggestSubDirs(%q) = %v, want %v", tt.subDir, got, tt.want)
+			}
+		})
+	}
+}
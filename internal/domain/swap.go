@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// replaceAtomically replaces path, a skill directory or a link into the shared store, with staged,
+// a fully prepared sibling on the same file system, so that agents reading path see either the old
+// or the new skill and never a partial copy. On Linux and macOS the two are exchanged in a single
+// step and path never disappears. Elsewhere, path is missing for the moment between two renames.
+// staged holds the old contents afterwards, and the caller deletes it.
+func replaceAtomically(staged, path string) error {
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return os.Rename(staged, path)
+	}
+
+	if err := exchangePaths(staged, path); err == nil {
+		return nil
+	}
+
+	// The file system or platform cannot exchange paths, so the old contents are moved aside first
+	old := staged + ".old"
+	if err := os.Rename(path, old); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", path, err)
+	}
+	if err := os.Rename(staged, path); err != nil {
+		_ = os.Rename(old, path)
+		return err
+	}
+	return os.Rename(old, staged)
+}
+
+// copyPreserved copies the entries of the installed skill directory matched by preserve into the prepared
+// copy staged, replacing the files of the new version at the same paths.
+func copyPreserved(skillDir, staged string, preserve *SkillIgnore) error {
+	if preserve == nil {
+		return nil
+	}
+	return filepath.WalkDir(skillDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == skillDir {
+			return err
+		}
+		rel, err := filepath.Rel(skillDir, path)
+		if err != nil {
+			return err
+		}
+		if !preserve.Match(rel, d.IsDir()) {
+			return nil
+		}
+
+		dst := filepath.Join(staged, rel)
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), installDirMode); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if err := CopyDir(path, dst, nil); err != nil {
+				return err
+			}
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return CopyFile(path, dst, nil)
+	})
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "golang.org/x/sys/unix"
+
+// exchangePaths atomically swaps the entries at a and b, which must both exist.
+func exchangePaths(a, b string) error {
+	return unix.RenameatxNp(unix.AT_FDCWD, a, unix.AT_FDCWD, b, unix.RENAME_SWAP)
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package domain
+
+import "errors"
+
+// exchangePaths atomically swaps the entries at a and b. Only Linux and macOS can swap paths.
+func exchangePaths(_, _ string) error {
+	return errors.New("exchanging paths is not supported on this platform")
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceAtomically(t *testing.T) {
+	t.Parallel()
+
+	write := func(t *testing.T, path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		setup   func(t *testing.T, path string)
+		name    string
+		wantOld string
+	}{
+		{name: "missing", setup: func(*testing.T, string) {}},
+		{name: "directory", setup: func(t *testing.T, path string) { write(t, filepath.Join(path, "SKILL.md"), "old") }, wantOld: "old"},
+		{name: "link", setup: func(t *testing.T, path string) {
+			write(t, filepath.Join(filepath.Dir(path), "entry", "SKILL.md"), "old")
+			if err := os.Symlink(filepath.Join(filepath.Dir(path), "entry"), path); err != nil {
+				t.Fatalf("failed to create link: %v", err)
+			}
+		}, wantOld: "old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "skill")
+			staged := filepath.Join(dir, "staging", "skill")
+			tt.setup(t, path)
+			write(t, filepath.Join(staged, "SKILL.md"), "new")
+
+			if err := replaceAtomically(staged, path); err != nil {
+				t.Fatalf("replaceAtomically() error = %v", err)
+			}
+
+			if info, err := os.Lstat(path); err != nil || !info.IsDir() {
+				t.Fatalf("%s should be the staged directory: %v, %v", path, info, err)
+			}
+			if data, err := os.ReadFile(filepath.Join(path, "SKILL.md")); err != nil || string(data) != "new" {
+				t.Errorf("SKILL.md = %q, %v, want new", data, err)
+			}
+			if tt.wantOld == "" {
+				if _, err := os.Lstat(staged); !os.IsNotExist(err) {
+					t.Errorf("nothing should be left at %s, stat error = %v", staged, err)
+				}
+				return
+			}
+			if data, err := os.ReadFile(filepath.Join(staged, "SKILL.md")); err != nil || string(data) != tt.wantOld {
+				t.Errorf("staged SKILL.md = %q, %v, want the replaced %q", data, err, tt.wantOld)
+			}
+		})
+	}
+}
+
+func TestCopyPreserved(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, "skill")
+	staged := filepath.Join(dir, "staged")
+	for path, content := range map[string]string{
+		"skill/SKILL.md":         "old",
+		"skill/notes.md":         "written by the agent",
+		"skill/state/cache.json": "{}",
+		"staged/SKILL.md":        "new",
+		"staged/notes.md":        "upstream",
+	} {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	if err := copyPreserved(skillDir, staged, NewSkillIgnore([]string{"notes.md", "state/"})); err != nil {
+		t.Fatalf("copyPreserved() error = %v", err)
+	}
+
+	for path, want := range map[string]string{"SKILL.md": "new", "notes.md": "written by the agent", "state/cache.json": "{}"} {
+		data, err := os.ReadFile(filepath.Join(staged, filepath.FromSlash(path)))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", path, data, err, want)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SystemInstallDir is the install target used for system-wide installs on Unix-like systems.
+// It is shared by all users of the machine and typically requires elevated privileges to write.
+const SystemInstallDir = "/usr/local/share/skills-pkg/skills"
+
+// TargetSettings holds per-install-target settings, keyed by the target path in the
+// [targets] table. They are mainly used for shared, system-wide targets.
+type TargetSettings struct {
+	Owner    string `toml:"owner,omitempty"`     // User that owns installed files (e.g., "root")
+	Group    string `toml:"group,omitempty"`     // Group that owns installed files (e.g., "staff")
+	FileMode string `toml:"file_mode,omitempty"` // Octal permissions of installed files (e.g., "0644")
+	DirMode  string `toml:"dir_mode,omitempty"`  // Octal permissions of installed directories (e.g., "0755")
+	// Agent names the agent that reads the target, for targets skills-pkg does not recognize.
+	// Skills that declare the agents they support are only installed into targets of those agents.
+	Agent        string `toml:"agent,omitempty"`
+	AgentVersion string `toml:"agent_version,omitempty"` // Version of the agent, checked against the minimum versions skills declare
+	// OS restricts the target to the operating systems, named as in GOOS (e.g., ["windows"]).
+	// On other operating systems the target is ignored.
+	OS []string `toml:"os,omitempty"`
+}
+
+// Validate checks that the permission settings of the install target are valid octal modes
+// and that its operating systems are known. It returns ErrorInvalidTargetSetting or ErrorInvalidPlatform otherwise.
+func (s *TargetSettings) Validate(target string) error {
+	if s == nil {
+		return nil
+	}
+	if _, err := parseMode(s.FileMode); err != nil {
+		return &ErrorInvalidTargetSetting{Target: target, Key: "file_mode", Value: s.FileMode}
+	}
+	if _, err := parseMode(s.DirMode); err != nil {
+		return &ErrorInvalidTargetSetting{Target: target, Key: "dir_mode", Value: s.DirMode}
+	}
+	if value, ok := validatePlatforms(s.OS); !ok {
+		return &ErrorInvalidPlatform{Entry: "install target " + target, Platform: value}
+	}
+	return nil
+}
+
+// parseMode parses an octal permission string such as "0644". An empty string yields 0.
+func parseMode(value string) (fs.FileMode, error) {
+	if value == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	if mode&^uint64(fs.ModePerm) != 0 {
+		return 0, fmt.Errorf("mode %s has bits outside of the permission bits", value)
+	}
+	return fs.FileMode(mode), nil
+}
+
+// TargetSettingsFor returns the settings configured for the install target.
+// It returns nil when the target has no settings.
+func (c *Config) TargetSettingsFor(target string) *TargetSettings {
+	return c.Targets[target]
+}
+
+// IsRemoteTarget reports whether the install target is a URL on another machine
+// (e.g., ssh://user@host/path) rather than a local directory.
+func IsRemoteTarget(target string) bool {
+	scheme, _, ok := strings.Cut(target, "://")
+	return ok && scheme != "" && !strings.ContainsAny(scheme, `/\.`)
+}
+
+// SameTarget reports whether two install targets, as written in the configuration, name the same
+// directory. Local paths are compared as absolute paths, so "./.skills", ".skills/", and its
+// absolute path are the same, and existing directories are also the same when they are one
+// directory reached through a symbolic link or a case-insensitive file system.
+func SameTarget(a, b string) bool {
+	if IsRemoteTarget(a) || IsRemoteTarget(b) {
+		return a == b
+	}
+
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	if absA == absB {
+		return true
+	}
+
+	infoA, errA := os.Stat(absA)
+	infoB, errB := os.Stat(absB)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// DuplicateTarget is an install target that names the same directory as an earlier one.
+type DuplicateTarget struct {
+	Target string
+	SameAs string
+}
+
+// DedupeInstallTargets removes the install targets that name the same directory as an earlier
+// target, which would otherwise receive every skill twice and fail verification twice.
+// Settings of a removed target are kept for the remaining one unless it has its own.
+// It returns the removed targets.
+func (c *Config) DedupeInstallTargets() []DuplicateTarget {
+	var duplicates []DuplicateTarget
+	kept := make([]string, 0, len(c.InstallTargets))
+	for _, target := range c.InstallTargets {
+		i := slices.IndexFunc(kept, func(k string) bool { return SameTarget(k, target) })
+		if i < 0 {
+			kept = append(kept, target)
+			continue
+		}
+
+		duplicates = append(duplicates, DuplicateTarget{Target: target, SameAs: kept[i]})
+		if settings, ok := c.Targets[target]; ok {
+			if _, exists := c.Targets[kept[i]]; !exists {
+				c.Targets[kept[i]] = settings
+			}
+			delete(c.Targets, target)
+		}
+	}
+
+	if len(duplicates) > 0 {
+		c.InstallTargets = kept
+	}
+	return duplicates
+}
+
+// LocalInstallTargets returns the install targets that are local directories.
+func (c *Config) LocalInstallTargets() []string {
+	targets := make([]string, 0, len(c.InstallTargets))
+	for _, target := range c.InstallTargets {
+		if !IsRemoteTarget(target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// CheckTargetWritable checks that the current user can create files in the install target.
+// When the target does not exist yet, its nearest existing ancestor is checked instead.
+// It returns ErrorTargetNotWritable when permission is denied.
+func CheckTargetWritable(target string) error {
+	dir := target
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("install target %s is not a directory: %s is a file", target, dir)
+			}
+			break
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return &ErrorTargetNotWritable{Target: target, Err: err}
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to access install target %s: %w", target, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	// Probe by creating a file, which also accounts for ACLs and read-only mounts
+	f, err := os.CreateTemp(dir, ".skills-pkg-write-check-*")
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return &ErrorTargetNotWritable{Target: target, Err: err}
+		}
+		return fmt.Errorf("failed to check write access to install target %s: %w", target, err)
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+
+	return nil
+}
+
+// CheckTargetOwnership checks that a process running as root does not write into an install target
+// in the home directory of the invoking user owned by another user, such as ~/.claude/skills, which
+// would leave root-owned files behind. Targets outside that home directory, such as a project checked
+// out by another user in a CI container, are not checked.
+// When the target does not exist yet, its nearest existing ancestor is checked instead.
+// It returns ErrorRootWriteToUserTarget when the check fails, and nil when not running as root.
+func CheckTargetOwnership(target string) error {
+	if !isRoot() || !inUserHome(target) {
+		return nil
+	}
+
+	uid, _, _, ok := targetOwner(target)
+	if ok && uid != 0 {
+		return &ErrorRootWriteToUserTarget{Target: target, OwnerUID: uid}
+	}
+	return nil
+}
+
+// inUserHome reports whether the target is in the home directory of the user running the command:
+// $HOME, or the home directory of SUDO_USER when the command runs through sudo.
+func inUserHome(target string) bool {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+
+	var homes []string
+	if home, err := os.UserHomeDir(); err == nil {
+		homes = append(homes, home)
+	}
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		if u, err := user.Lookup(name); err == nil && u.HomeDir != "" {
+			homes = append(homes, u.HomeDir)
+		}
+	}
+	for _, home := range homes {
+		home = filepath.Clean(home)
+		if home == string(filepath.Separator) {
+			continue
+		}
+		if target == home || strings.HasPrefix(target, home+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetOwner returns the owner of the install target, or of its nearest existing ancestor
+// when the target does not exist yet, along with that existing path.
+func targetOwner(target string) (uid, gid int, existing string, ok bool) {
+	dir := filepath.Clean(target)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			uid, gid, ok = fileOwner(info)
+			return uid, gid, dir, ok
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return 0, 0, "", false
+		}
+		dir = parent
+	}
+}
+
+// chownInstalled gives the installed skill directory, and the directories created for the target below
+// existing, to the given user and group.
+func chownInstalled(skillDir, target, existing string, uid, gid int) error {
+	err := filepath.WalkDir(skillDir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, uid, gid)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change owner of %s: %w", skillDir, err)
+	}
+
+	for dir := filepath.Clean(target); dir != existing && strings.HasPrefix(dir, existing); dir = filepath.Dir(dir) {
+		if err := os.Lchown(dir, uid, gid); err != nil {
+			return fmt.Errorf("failed to change owner of %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// applyTargetSettings applies the ownership and permission settings of the install target
+// to an installed skill directory.
+func applyTargetSettings(skillDir string, settings *TargetSettings) error {
+	if settings == nil {
+		return nil
+	}
+
+	uid, gid, err := settings.ownerIDs()
+	if err != nil {
+		return err
+	}
+	fileMode, err := parseMode(settings.FileMode)
+	if err != nil {
+		return fmt.Errorf("invalid file_mode '%s': %w", settings.FileMode, err)
+	}
+	dirMode, err := parseMode(settings.DirMode)
+	if err != nil {
+		return fmt.Errorf("invalid dir_mode '%s': %w", settings.DirMode, err)
+	}
+	if uid == -1 && gid == -1 && fileMode == 0 && dirMode == 0 {
+		return nil
+	}
+
+	var entries []fs.DirEntry
+	var paths []string
+	err = filepath.WalkDir(skillDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		entries = append(entries, d)
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Apply in reverse so that a restrictive dir_mode does not block access to the directory's contents
+	for i := len(paths) - 1; i >= 0; i-- {
+		path, entry := paths[i], entries[i]
+
+		if uid != -1 || gid != -1 {
+			if err := os.Lchown(path, uid, gid); err != nil {
+				if errors.Is(err, fs.ErrPermission) {
+					return &ErrorTargetNotWritable{Target: skillDir, Err: err}
+				}
+				return fmt.Errorf("failed to change owner of %s: %w", path, err)
+			}
+		}
+
+		// Symbolic links have no permissions of their own
+		mode := fileMode
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			continue
+		case entry.IsDir():
+			mode = dirMode
+		}
+		if mode == 0 {
+			continue
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return &ErrorTargetNotWritable{Target: skillDir, Err: err}
+			}
+			return fmt.Errorf("failed to change permissions of %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// ownerIDs resolves the configured owner and group to numeric ids.
+// Ids that are not configured are returned as -1, which leaves them unchanged.
+func (s *TargetSettings) ownerIDs() (uid, gid int, err error) {
+	uid, gid = -1, -1
+
+	if s.Owner != "" {
+		owner, err := user.Lookup(s.Owner)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to look up user '%s': %w", s.Owner, err)
+		}
+		if uid, err = strconv.Atoi(owner.Uid); err != nil {
+			return 0, 0, fmt.Errorf("user '%s' has no numeric id on this platform: %w", s.Owner, err)
+		}
+	}
+
+	if s.Group != "" {
+		group, err := user.LookupGroup(s.Group)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to look up group '%s': %w", s.Group, err)
+		}
+		if gid, err = strconv.Atoi(group.Gid); err != nil {
+			return 0, 0, fmt.Errorf("group '%s' has no numeric id on this platform: %w", s.Group, err)
+		}
+	}
+
+	return uid, gid, nil
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+)
+
+// MigrateTarget moves the skills that skills-pkg installed into the configured install target from,
+// as found by installsIn, to the directory to. The configuration and the lock file are then updated
+// to name to instead of from, keeping the target's settings. Other directories in from are left
+// untouched.
+//
+// Every destination is checked before anything is moved, and skills that were already moved are
+// moved back when a later one fails, so a failed migration leaves the installation as it was.
+func (s *skillManagerImpl) MigrateTarget(ctx context.Context, from, to string) ([]*PrunedInstall, error) {
+	config, err := s.configManager.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	i := slices.IndexFunc(config.InstallTargets, func(target string) bool { return SameTarget(target, from) })
+	if i < 0 {
+		return nil, &ErrorInstallTargetNotFound{Target: from}
+	}
+	from = config.InstallTargets[i]
+	if slices.ContainsFunc(config.InstallTargets, func(target string) bool { return SameTarget(target, to) }) {
+		return nil, &ErrorInstallTargetExists{Target: to}
+	}
+	if IsRemoteTarget(from) || IsRemoteTarget(to) {
+		return nil, fmt.Errorf("cannot migrate between '%s' and '%s': remote install targets are not supported. Use 'target add --install' and 'target remove --clean' instead", from, to)
+	}
+
+	lock, err := s.lockManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	installs := s.installsIn(config, lock, from)
+	for _, install := range installs {
+		dst := to + "/" + install.Dir
+		if _, err := s.fsys.Lstat(dst); err == nil {
+			return nil, fmt.Errorf("cannot move skill '%s' to %s: the directory already exists. Move or delete it and try again", install.SkillName, dst)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to check %s: %w", dst, err)
+		}
+	}
+
+	if err := s.fsys.MkdirAll(to, installDirMode); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, &ErrorTargetNotWritable{Target: to, Err: err}
+		}
+		return nil, fmt.Errorf("failed to create install target directory %s: %w", to, err)
+	}
+
+	for moved, install := range installs {
+		if err := s.moveInstall(from+"/"+install.Dir, to+"/"+install.Dir); err != nil {
+			// Move the skills back, so that the configuration still matches the installation
+			for _, back := range slices.Backward(installs[:moved]) {
+				if err := s.moveInstall(to+"/"+back.Dir, from+"/"+back.Dir); err != nil {
+					s.emit(ctx, back.SkillName, PhaseWarning, from, "WARNING: failed to move skill '%s' back to %s: %v", back.SkillName, from, err)
+				}
+			}
+			if errors.Is(err, fs.ErrPermission) {
+				return nil, &ErrorTargetNotWritable{Target: to, Err: err}
+			}
+			return nil, fmt.Errorf("failed to move skill '%s' to %s: %w", install.SkillName, to, err)
+		}
+		s.emit(ctx, install.SkillName, PhaseMove, to, "Moved skill '%s' to %s", install.SkillName, to)
+	}
+
+	config.InstallTargets[i] = to
+	if settings, ok := config.Targets[from]; ok {
+		delete(config.Targets, from)
+		config.Targets[to] = settings
+	}
+	if err := s.configManager.Save(ctx, config); err != nil {
+		return nil, fmt.Errorf("failed to save configuration after migrating install target '%s': %w", from, err)
+	}
+
+	if err := s.lockManager.Update(ctx, func(lock *LockFile) {
+		for _, locked := range lock.Skills {
+			for _, status := range locked.Targets {
+				if SameTarget(status.Path, from) {
+					status.Path = to
+				}
+			}
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update lock file: %w", err)
+	}
+
+	return installs, nil
+}
+
+// moveInstall moves the installed skill at src to dst. Links into the shared store are recreated,
+// so that the store keeps counting them, and directories that cannot be renamed, as across file
+// systems, are copied.
+func (s *skillManagerImpl) moveInstall(src, dst string) error {
+	if entry := s.storeEntryOf(src); entry != "" {
+		if err := s.store.Link(entry, dst); err != nil {
+			return err
+		}
+		return s.fsys.RemoveAll(src)
+	}
+
+	if err := s.fsys.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := CopyDir(src, dst, nil); err != nil {
+		_ = s.fsys.RemoveAll(dst)
+		return err
+	}
+	return s.fsys.RemoveAll(src)
+}
@@ -0,0 +1,231 @@
+package domain_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestCheckTargetWritable(t *testing.T) {
+	tests := []struct {
+		setupFunc    func(t *testing.T) string
+		name         string
+		wantErr      bool
+		wantNotWrite bool
+	}{
+		{
+			name: "existing writable directory",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return t.TempDir()
+			},
+		},
+		{
+			name: "missing target under writable parent",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				return filepath.Join(t.TempDir(), "a", "b", "skills")
+			},
+		},
+		{
+			name: "target is a file",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				path := filepath.Join(t.TempDir(), "skills")
+				if err := os.WriteFile(path, []byte("file"), 0o644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+				return path
+			},
+			wantErr: true,
+		},
+		{
+			name: "read-only parent directory",
+			setupFunc: func(t *testing.T) string {
+				t.Helper()
+				if os.Geteuid() == 0 {
+					t.Skip("permission checks are bypassed when running as root")
+				}
+				parent := t.TempDir()
+				if err := os.Chmod(parent, 0o555); err != nil {
+					t.Fatalf("failed to chmod: %v", err)
+				}
+				t.Cleanup(func() {
+					_ = os.Chmod(parent, 0o755)
+				})
+				return filepath.Join(parent, "skills")
+			},
+			wantErr:      true,
+			wantNotWrite: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.setupFunc(t)
+
+			err := domain.CheckTargetWritable(target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckTargetWritable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := errors.AsType[*domain.ErrorTargetNotWritable](err); ok != tt.wantNotWrite {
+				t.Errorf("CheckTargetWritable() error = %v, want ErrorTargetNotWritable: %v", err, tt.wantNotWrite)
+			}
+
+			// The probe file must not be left behind
+			if entries, readErr := os.ReadDir(target); readErr == nil && len(entries) != 0 {
+				t.Errorf("target should be left empty, found %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestConfig_TargetSettingsFor(t *testing.T) {
+	config := &domain.Config{
+		InstallTargets: []string{domain.SystemInstallDir, "./.skills"},
+		Targets: map[string]*domain.TargetSettings{
+			domain.SystemInstallDir: {Group: "staff"},
+		},
+	}
+
+	if got := config.TargetSettingsFor(domain.SystemInstallDir); got == nil || got.Group != "staff" {
+		t.Errorf("TargetSettingsFor(%q) = %+v, want group staff", domain.SystemInstallDir, got)
+	}
+	if got := config.TargetSettingsFor("./.skills"); got != nil {
+		t.Errorf("TargetSettingsFor(%q) = %+v, want nil", "./.skills", got)
+	}
+}
+
+func TestTargetSettings_Validate(t *testing.T) {
+	tests := []struct {
+		settings *domain.TargetSettings
+		name     string
+		wantKey  string
+	}{
+		{name: "nil settings", settings: nil},
+		{name: "valid modes", settings: &domain.TargetSettings{FileMode: "0644", DirMode: "755"}},
+		{name: "group only", settings: &domain.TargetSettings{Group: "staff"}},
+		{name: "non-octal file mode", settings: &domain.TargetSettings{FileMode: "0689"}, wantKey: "file_mode"},
+		{name: "symbolic dir mode", settings: &domain.TargetSettings{DirMode: "rwxr-xr-x"}, wantKey: "dir_mode"},
+		{name: "setuid bit", settings: &domain.TargetSettings{FileMode: "4755"}, wantKey: "file_mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate("/target")
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			invalidErr, ok := errors.AsType[*domain.ErrorInvalidTargetSetting](err)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ErrorInvalidTargetSetting", err)
+			}
+			if invalidErr.Key != tt.wantKey {
+				t.Errorf("ErrorInvalidTargetSetting.Key = %s, want %s", invalidErr.Key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestIsRemoteTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{target: "ssh://deploy@example.com/srv/skills", want: true},
+		{target: "./.claude/skills", want: false},
+		{target: "/usr/local/share/skills-pkg/skills", want: false},
+		{target: `C:\Users\me\.claude\skills`, want: false},
+		{target: "./weird.dir://skills", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := domain.IsRemoteTarget(tt.target); got != tt.want {
+				t.Errorf("IsRemoteTarget(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameTarget(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "./.skills", b: ".skills/", want: true},
+		{a: "./.skills", b: mustAbs(t, ".skills"), want: true},
+		{a: "./.claude/skills", b: "./.codex/skills", want: false},
+		{a: "ssh://deploy@example.com/srv/skills", b: "ssh://deploy@example.com/srv/skills", want: true},
+		{a: "ssh://deploy@example.com/srv/skills", b: "ssh://deploy@example.com/srv/skills/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := domain.SameTarget(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameTarget(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameTarget_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "skills")
+	link := filepath.Join(dir, "link")
+	if err := os.Mkdir(real, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+
+	if !domain.SameTarget(real, link) {
+		t.Errorf("SameTarget(%q, %q) = false, want true", real, link)
+	}
+}
+
+func TestConfig_DedupeInstallTargets(t *testing.T) {
+	config := &domain.Config{
+		InstallTargets: []string{"./.skills", "./.claude/skills", ".skills/", mustAbs(t, ".claude/skills")},
+		Targets: map[string]*domain.TargetSettings{
+			".skills/": {DirMode: "0755"},
+		},
+	}
+
+	duplicates := config.DedupeInstallTargets()
+
+	want := []domain.DuplicateTarget{
+		{Target: ".skills/", SameAs: "./.skills"},
+		{Target: mustAbs(t, ".claude/skills"), SameAs: "./.claude/skills"},
+	}
+	if !slices.Equal(duplicates, want) {
+		t.Errorf("DedupeInstallTargets() = %v, want %v", duplicates, want)
+	}
+	if !slices.Equal(config.InstallTargets, []string{"./.skills", "./.claude/skills"}) {
+		t.Errorf("InstallTargets = %v, want the first of each directory", config.InstallTargets)
+	}
+	if settings := config.Targets["./.skills"]; settings == nil || settings.DirMode != "0755" {
+		t.Errorf("expected the settings of the removed target to move to the kept one, got %v", config.Targets)
+	}
+	if _, ok := config.Targets[".skills/"]; ok {
+		t.Error("expected the settings of the removed target to be deleted")
+	}
+}
+
+func mustAbs(t *testing.T, path string) string {
+	t.Helper()
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abs
+}
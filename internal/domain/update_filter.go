@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// VersionBump limits how far Update may move a skill's version.
+type VersionBump string
+
+const (
+	// VersionBumpMajor allows any update. It is the default.
+	VersionBumpMajor VersionBump = "major"
+	// VersionBumpMinor allows updates within the same major version.
+	VersionBumpMinor VersionBump = "minor"
+	// VersionBumpPatch allows updates within the same major and minor version.
+	VersionBumpPatch VersionBump = "patch"
+)
+
+// UpdateOptions controls which skills Update processes and how.
+// A nil *UpdateOptions updates all selected skills without restrictions.
+type UpdateOptions struct {
+	MaxBump VersionBump // Largest allowed version change; empty allows any change
+	Exclude []string    // Names of skills to leave untouched
+	Sources []string    // Only update skills from these source types; empty allows all
+	Canary  string      // Install only into this install target and record the new version as the skill's canary
+	DryRun  bool        // Only check for updates without applying changes
+}
+
+// selects reports whether the skill passes the exclude and source filters.
+func (o *UpdateOptions) selects(skill *Skill) bool {
+	if o == nil {
+		return true
+	}
+	if slices.Contains(o.Exclude, skill.Name) {
+		return false
+	}
+	return len(o.Sources) == 0 || slices.Contains(o.Sources, skill.Source)
+}
+
+// allowsBump reports whether updating from oldVersion to newVersion stays within MaxBump.
+// Versions that are not semantic versions (e.g., commit hashes) can only be updated without a limit.
+func (o *UpdateOptions) allowsBump(oldVersion, newVersion string) bool {
+	if o == nil || o.MaxBump == "" || o.MaxBump == VersionBumpMajor || oldVersion == newVersion {
+		return true
+	}
+	oldVersion, newVersion = semverOf(oldVersion), semverOf(newVersion)
+	if !semver.IsValid(oldVersion) || !semver.IsValid(newVersion) {
+		return false
+	}
+
+	if semver.Major(oldVersion) != semver.Major(newVersion) {
+		return false
+	}
+	if o.MaxBump == VersionBumpPatch {
+		return semver.MajorMinor(oldVersion) == semver.MajorMinor(newVersion)
+	}
+	return true
+}
+
+// newestAllowed returns the newest of versions that is newer than oldVersion and within MaxBump,
+// or "" when there is none. Prereleases are only considered when oldVersion is a prerelease.
+func (o *UpdateOptions) newestAllowed(oldVersion string, versions []string) string {
+	newest := ""
+	for _, version := range versions {
+		v := semverOf(version)
+		if !semver.IsValid(v) || semver.Compare(v, semverOf(oldVersion)) <= 0 || !o.allowsBump(oldVersion, version) {
+			continue
+		}
+		if semver.Prerelease(v) != "" && semver.Prerelease(semverOf(oldVersion)) == "" {
+			continue
+		}
+		if newest == "" || semver.Compare(v, semverOf(newest)) > 0 {
+			newest = version
+		}
+	}
+	return newest
+}
+
+// semverOf returns version with the "v" prefix that semantic versions need for comparison,
+// since npm versions and OCI tags are often written without it.
+func semverOf(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+func (o *UpdateOptions) dryRun() bool {
+	return o != nil && o.DryRun
+}
+
+func (o *UpdateOptions) canary() string {
+	if o == nil {
+		return ""
+	}
+	return o.Canary
+}
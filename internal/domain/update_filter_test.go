@@ -0,0 +1,102 @@
+package domain
+
+import "testing"
+
+func TestUpdateOptions_Selects(t *testing.T) {
+	tests := []struct {
+		opts  *UpdateOptions
+		skill *Skill
+		name  string
+		want  bool
+	}{
+		{
+			name:  "nil options select every skill",
+			skill: &Skill{Name: "a", Source: "git"},
+			want:  true,
+		},
+		{
+			name:  "excluded skill",
+			opts:  &UpdateOptions{Exclude: []string{"a"}},
+			skill: &Skill{Name: "a", Source: "git"},
+			want:  false,
+		},
+		{
+			name:  "matching source",
+			opts:  &UpdateOptions{Sources: []string{"go-mod", "git"}},
+			skill: &Skill{Name: "a", Source: "git"},
+			want:  true,
+		},
+		{
+			name:  "other source",
+			opts:  &UpdateOptions{Sources: []string{"go-mod"}},
+			skill: &Skill{Name: "a", Source: "git"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.selects(tt.skill); got != tt.want {
+				t.Errorf("selects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateOptions_AllowsBump(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBump    VersionBump
+		oldVersion string
+		newVersion string
+		want       bool
+	}{
+		{name: "no limit", maxBump: "", oldVersion: "v1.0.0", newVersion: "v2.0.0", want: true},
+		{name: "major allows major", maxBump: VersionBumpMajor, oldVersion: "v1.0.0", newVersion: "v2.0.0", want: true},
+		{name: "minor allows minor", maxBump: VersionBumpMinor, oldVersion: "v1.0.0", newVersion: "v1.3.0", want: true},
+		{name: "minor rejects major", maxBump: VersionBumpMinor, oldVersion: "v1.0.0", newVersion: "v2.0.0", want: false},
+		{name: "patch allows patch", maxBump: VersionBumpPatch, oldVersion: "v1.2.0", newVersion: "v1.2.5", want: true},
+		{name: "patch rejects minor", maxBump: VersionBumpPatch, oldVersion: "v1.2.0", newVersion: "v1.3.0", want: false},
+		{name: "non-semver is rejected when limited", maxBump: VersionBumpPatch, oldVersion: "abc123", newVersion: "def456", want: false},
+		{name: "unchanged version is always allowed", maxBump: VersionBumpPatch, oldVersion: "abc123", newVersion: "abc123", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &UpdateOptions{MaxBump: tt.maxBump}
+			if got := opts.allowsBump(tt.oldVersion, tt.newVersion); got != tt.want {
+				t.Errorf("allowsBump(%s, %s) = %v, want %v", tt.oldVersion, tt.newVersion, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateOptions_NewestAllowed(t *testing.T) {
+	versions := []string{"v2.0.0", "v1.2.9", "v1.3.0", "v1.2.3"}
+
+	tests := []struct {
+		name    string
+		maxBump VersionBump
+		want    string
+	}{
+		{name: "minor picks the newest minor", maxBump: VersionBumpMinor, want: "v1.3.0"},
+		{name: "patch picks the newest patch", maxBump: VersionBumpPatch, want: "v1.2.9"},
+		{name: "major picks the newest", maxBump: VersionBumpMajor, want: "v2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &UpdateOptions{MaxBump: tt.maxBump}
+			if got := opts.newestAllowed("v1.2.3", versions); got != tt.want {
+				t.Errorf("newestAllowed(v1.2.3) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := (&UpdateOptions{MaxBump: VersionBumpPatch}).newestAllowed("v1.2.9", versions); got != "" {
+		t.Errorf("newestAllowed(v1.2.9) = %q, want no version", got)
+	}
+	if got := (&UpdateOptions{MaxBump: VersionBumpPatch}).newestAllowed("1.2.3", []string{"1.2.4", "1.2.5-beta.1"}); got != "1.2.4" {
+		t.Errorf("newestAllowed(1.2.3) = %q, want 1.2.4", got)
+	}
+}
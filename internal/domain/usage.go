@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SkillUsage is how often a skill is referenced in agent transcripts.
+type SkillUsage struct {
+	LastUsed   time.Time // Modification time of the newest transcript referencing the skill; zero when unused
+	SkillName  string
+	References int // Number of transcript lines referencing the skill
+}
+
+// UsageReport is the result of scanning agent transcripts for references to skills.
+type UsageReport struct {
+	Skills      []*SkillUsage // In the order of the scanned skill names
+	Transcripts int           // Number of transcripts scanned
+}
+
+// Unused returns the skills that no scanned transcript references.
+func (r *UsageReport) Unused() []*SkillUsage {
+	var unused []*SkillUsage
+	for _, usage := range r.Skills {
+		if usage.References == 0 {
+			unused = append(unused, usage)
+		}
+	}
+	return unused
+}
+
+// ScanSkillUsage counts the references to the skills in the agent transcripts (*.jsonl files) below dirs,
+// such as the Claude Code transcripts in ~/.claude/projects. A transcript line references a skill when
+// it invokes the skill with the Skill tool ("skill": "<name>") or names a file in its directory (skills/<name>/).
+// Transcripts last modified before since are skipped unless since is zero, and missing directories are ignored.
+func ScanSkillUsage(dirs []string, skillNames []string, since time.Time) (*UsageReport, error) {
+	report := &UsageReport{Skills: make([]*SkillUsage, 0, len(skillNames))}
+	usages := make(map[string]*SkillUsage, len(skillNames))
+	for _, name := range skillNames {
+		usage := &SkillUsage{SkillName: name}
+		report.Skills = append(report.Skills, usage)
+		usages[name] = usage
+	}
+	if len(skillNames) == 0 {
+		return report, nil
+	}
+
+	pattern := skillReferencePattern(skillNames)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && path == dir {
+					return fs.SkipAll
+				}
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != ".jsonl" {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !since.IsZero() && info.ModTime().Before(since) {
+				return nil
+			}
+
+			referenced, err := scanTranscript(path, pattern)
+			if err != nil {
+				return err
+			}
+			report.Transcripts++
+			for name, count := range referenced {
+				usage := usages[name]
+				usage.References += count
+				if info.ModTime().After(usage.LastUsed) {
+					usage.LastUsed = info.ModTime()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcripts in %s: %w", dir, err)
+		}
+	}
+
+	return report, nil
+}
+
+// skillReferencePattern matches the references to any of the skills, capturing the skill name.
+func skillReferencePattern(skillNames []string) *regexp.Regexp {
+	names := slices.Clone(skillNames)
+	// Longer names first, so that a name is not matched by another name it starts with
+	slices.SortFunc(names, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	for i, name := range names {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	alternation := strings.Join(names, "|")
+
+	return regexp.MustCompile(`"skill"\s*:\s*"(` + alternation + `)"|skills(?:/|\\\\)(` + alternation + `)(?:/|\\\\|")`)
+}
+
+// scanTranscript returns the number of lines of the transcript that reference each skill.
+func scanTranscript(path string, pattern *regexp.Regexp) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	referenced := make(map[string]int)
+	// Transcript lines embed whole tool results, so they are read without a length limit
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		seen := make(map[string]bool)
+		for _, match := range pattern.FindAllSubmatch(line, -1) {
+			name := string(match[1])
+			if name == "" {
+				name = string(match[2])
+			}
+			if !seen[name] {
+				seen[name] = true
+				referenced[name]++
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return referenced, nil
+			}
+			return nil, fmt.Errorf("failed to read transcript %s: %w", path, err)
+		}
+	}
+}
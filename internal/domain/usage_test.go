@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanSkillUsage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTranscript := func(name, content string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write transcript: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set modification time: %v", err)
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+	writeTranscript("project-a/1.jsonl", `{"type":"tool_use","name":"Skill","input":{"skill": "go-test"}}
+{"type":"tool_use","name":"Read","input":{"file_path":"/repo/.claude/skills/go-test/SKILL.md"}} {"file_path":"/repo/.claude/skills/go-test/ref.md"}
+{"type":"text","text":"go is a language"}
+`, now.Add(-time.Hour))
+	writeTranscript("project-b/2.jsonl", `{"input":{"file_path":"C:\\repo\\.claude\\skills\\go\\SKILL.md"}}`, now)
+	writeTranscript("project-b/old.jsonl", `{"input":{"skill":"docs"}}`, now.Add(-48*time.Hour))
+	writeTranscript("project-b/notes.txt", `{"input":{"skill":"docs"}}`, now)
+
+	report, err := ScanSkillUsage([]string{dir, filepath.Join(dir, "missing")}, []string{"go", "go-test", "docs"}, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("ScanSkillUsage() error = %v", err)
+	}
+
+	if report.Transcripts != 2 {
+		t.Errorf("Transcripts = %d, want 2", report.Transcripts)
+	}
+	want := []SkillUsage{
+		{SkillName: "go", References: 1, LastUsed: now},
+		{SkillName: "go-test", References: 2, LastUsed: now.Add(-time.Hour)},
+		{SkillName: "docs"},
+	}
+	if len(report.Skills) != len(want) {
+		t.Fatalf("Skills = %d, want %d", len(report.Skills), len(want))
+	}
+	for i, usage := range report.Skills {
+		if usage.SkillName != want[i].SkillName || usage.References != want[i].References || !usage.LastUsed.Equal(want[i].LastUsed) {
+			t.Errorf("Skills[%d] = %+v, want %+v", i, *usage, want[i])
+		}
+	}
+
+	unused := report.Unused()
+	if len(unused) != 1 || unused[0].SkillName != "docs" {
+		t.Errorf("Unused() = %v, want [docs]", unused)
+	}
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// defaultNotificationMinDuration is used when notifications are enabled without min_duration.
+const defaultNotificationMinDuration = 10 * time.Second
+
+// UserConfig holds per-user settings that apply to every project.
+// It is read from config.toml in the skills-pkg user configuration directory.
+type UserConfig struct {
+	Notifications *NotificationSettings `toml:"notifications,omitempty"`
+	Bootstrap     *Skill                `toml:"bootstrap,omitempty"` // Skill that init installs in place of managing-skills, e.g. from a fork or mirror
+	Usage         *UsageSettings        `toml:"usage,omitempty"`
+	Backups       *BackupSettings       `toml:"backups,omitempty"`
+	Search        *SearchSettings       `toml:"search,omitempty"`
+	Network       *NetworkSettings      `toml:"network,omitempty"`
+}
+
+// SearchSettings configures the skill indexes that 'skills-pkg search' queries.
+type SearchSettings struct {
+	Indexes      []string `toml:"indexes,omitempty"`       // Base URLs of servers with the skills.sh search API; defaults to https://skills.sh
+	GitHubTopics []string `toml:"github_topics,omitempty"` // Topics of GitHub repositories that are searched as well, e.g. "agent-skills"
+}
+
+// BackupSettings configures the backups of installed skills that are replaced or removed.
+type BackupSettings struct {
+	MaxAge   string `toml:"max_age,omitempty"`  // Older backups are dropped (e.g., "720h"); kept regardless of age by default
+	Keep     int    `toml:"keep,omitempty"`     // Backups kept for each skill and install target; defaults to 5
+	Disabled bool   `toml:"disabled,omitempty"` // Delete replaced and removed skills instead of backing them up
+}
+
+// UsageSettings configures where 'skills-pkg usage' reads agent transcripts from.
+type UsageSettings struct {
+	Transcripts []string `toml:"transcripts,omitempty"` // Directories of *.jsonl transcripts; defaults to the Claude Code transcripts
+}
+
+// NotificationSettings configures desktop notifications.
+type NotificationSettings struct {
+	MinDuration string `toml:"min_duration,omitempty"` // Operations finishing sooner are not reported (e.g., "30s"); defaults to 10s
+	Enabled     bool   `toml:"enabled"`                // Send desktop notifications
+}
+
+// LoadUserConfig reads the user-level configuration file. A missing file yields an empty configuration.
+func LoadUserConfig(path string) (*UserConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &UserConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read user configuration file at %s: %w", path, err)
+	}
+
+	var config UserConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse user configuration file at %s: %w. Ensure the file is valid TOML format", path, err)
+	}
+	// The user configuration is never written back, so its encrypted values are only decrypted
+	if _, err := decryptSecrets(&config, true); err != nil {
+		return nil, fmt.Errorf("failed to decrypt a value in user configuration file at %s: %w", path, err)
+	}
+
+	if config.Notifications != nil && config.Notifications.MinDuration != "" {
+		if _, err := time.ParseDuration(config.Notifications.MinDuration); err != nil {
+			return nil, fmt.Errorf("invalid notifications.min_duration in %s: %w", path, err)
+		}
+	}
+
+	if config.Backups != nil {
+		if config.Backups.Keep < 0 {
+			return nil, fmt.Errorf("invalid backups.keep in %s: must not be negative", path)
+		}
+		if config.Backups.MaxAge != "" {
+			if _, err := time.ParseDuration(config.Backups.MaxAge); err != nil {
+				return nil, fmt.Errorf("invalid backups.max_age in %s: %w", path, err)
+			}
+		}
+	}
+
+	if config.Search != nil {
+		for _, index := range config.Search.Indexes {
+			if u, err := url.Parse(index); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return nil, fmt.Errorf("invalid search.indexes in %s: %q is not an http or https URL", path, index)
+			}
+		}
+	}
+
+	if config.Network != nil {
+		if config.Network.LimitRate != "" {
+			if _, err := ParseByteRate(config.Network.LimitRate); err != nil {
+				return nil, fmt.Errorf("invalid network.limit_rate in %s: %w", path, err)
+			}
+		}
+		if _, err := config.Network.Policy(); err != nil {
+			return nil, fmt.Errorf("invalid [network] settings in %s: %w", path, err)
+		}
+		if err := config.Network.ValidateHeaders(); err != nil {
+			return nil, fmt.Errorf("invalid [network] settings in %s: %w", path, err)
+		}
+	}
+
+	if config.Bootstrap != nil {
+		if err := config.Bootstrap.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid bootstrap in %s: %w", path, err)
+		}
+	}
+
+	return &config, nil
+}
+
+// TranscriptDirs returns the directories of agent transcripts that usage reports are based on.
+// Without configured directories, it returns the Claude Code transcripts in $CLAUDE_CONFIG_DIR/projects,
+// or ~/.claude/projects when CLAUDE_CONFIG_DIR is not set.
+// It is safe to call on a nil UserConfig.
+func (c *UserConfig) TranscriptDirs() ([]string, error) {
+	if c != nil && c.Usage != nil && len(c.Usage.Transcripts) > 0 {
+		return c.Usage.Transcripts, nil
+	}
+
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return []string{filepath.Join(dir, "projects")}, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return []string{filepath.Join(home, ".claude", "projects")}, nil
+}
+
+// NotificationsEnabled reports whether desktop notifications are turned on.
+// It is safe to call on a nil UserConfig.
+func (c *UserConfig) NotificationsEnabled() bool {
+	return c != nil && c.Notifications != nil && c.Notifications.Enabled
+}
+
+// NotificationMinDuration returns how long an operation must run before its completion is notified.
+func (c *UserConfig) NotificationMinDuration() time.Duration {
+	if c == nil || c.Notifications == nil || c.Notifications.MinDuration == "" {
+		return defaultNotificationMinDuration
+	}
+
+	// The value is validated in LoadUserConfig
+	d, err := time.ParseDuration(c.Notifications.MinDuration)
+	if err != nil {
+		return defaultNotificationMinDuration
+	}
+	return d
+}
+
+// BackupsEnabled reports whether replaced and removed skills are backed up, which they are unless disabled.
+// It is safe to call on a nil UserConfig.
+func (c *UserConfig) BackupsEnabled() bool {
+	return c == nil || c.Backups == nil || !c.Backups.Disabled
+}
+
+// BackupKeep returns the number of backups kept for each skill and install target.
+func (c *UserConfig) BackupKeep() int {
+	if c == nil || c.Backups == nil || c.Backups.Keep == 0 {
+		return DefaultBackupKeep
+	}
+	return c.Backups.Keep
+}
+
+// BackupMaxAge returns the age past which backups are dropped, or zero to keep them regardless of age.
+func (c *UserConfig) BackupMaxAge() time.Duration {
+	if c == nil || c.Backups == nil || c.Backups.MaxAge == "" {
+		return 0
+	}
+
+	// The value is validated in LoadUserConfig
+	d, err := time.ParseDuration(c.Backups.MaxAge)
+	if err != nil {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,120 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestLoadUserConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		wantMinDuration time.Duration
+		noFile          bool
+		wantEnabled     bool
+		wantErr         bool
+	}{
+		{
+			name:            "missing file disables notifications",
+			noFile:          true,
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:            "enabled with default duration",
+			content:         "[notifications]\nenabled = true\n",
+			wantEnabled:     true,
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:            "custom duration",
+			content:         "[notifications]\nenabled = true\nmin_duration = \"1m\"\n",
+			wantEnabled:     true,
+			wantMinDuration: time.Minute,
+		},
+		{
+			name:    "invalid duration",
+			content: "[notifications]\nenabled = true\nmin_duration = \"soon\"\n",
+			wantErr: true,
+		},
+		{
+			name:            "bootstrap skill",
+			content:         "[bootstrap]\nname = \"team-skills\"\nsource = \"git\"\nurl = \"https://git.example.com/skills\"\n",
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:            "backups retention",
+			content:         "[backups]\nkeep = 2\nmax_age = \"720h\"\n",
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:    "invalid backup max age",
+			content: "[backups]\nmax_age = \"30d\"\n",
+			wantErr: true,
+		},
+		{
+			name:            "search indexes",
+			content:         "[search]\nindexes = [\"https://skills.example.com\"]\ngithub_topics = [\"agent-skills\"]\n",
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:    "search index without scheme",
+			content: "[search]\nindexes = [\"skills.example.com\"]\n",
+			wantErr: true,
+		},
+		{
+			name:            "network bandwidth limit",
+			content:         "[network]\nlimit_rate = \"2M\"\n",
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:            "network host policy",
+			content:         "[network]\nallowed_hosts = [\"github.com\", \"10.0.0.0/8\", \"fd00::/8\"]\ndeny_by_default = true\n",
+			wantMinDuration: 10 * time.Second,
+		},
+		{
+			name:    "invalid network allowed host",
+			content: "[network]\nallowed_hosts = [\"https://github.com\"]\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid network bandwidth limit",
+			content: "[network]\nlimit_rate = \"fast\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "bootstrap skill without url",
+			content: "[bootstrap]\nname = \"team-skills\"\nsource = \"git\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if !tt.noFile {
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatalf("failed to write user config: %v", err)
+				}
+			}
+
+			config, err := domain.LoadUserConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadUserConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := config.NotificationsEnabled(); got != tt.wantEnabled {
+				t.Errorf("NotificationsEnabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if got := config.NotificationMinDuration(); got != tt.wantMinDuration {
+				t.Errorf("NotificationMinDuration() = %v, want %v", got, tt.wantMinDuration)
+			}
+		})
+	}
+}
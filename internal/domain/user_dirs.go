@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// appDirName is the name of the skills-pkg subdirectory in each base directory.
+const appDirName = "skills-pkg"
+
+// UserDirs holds the per-user directories where skills-pkg keeps its own files.
+// They follow the XDG Base Directory Specification: XDG_CONFIG_HOME, XDG_DATA_HOME,
+// XDG_CACHE_HOME, and XDG_STATE_HOME are honored on every platform, and the platform's conventional
+// directories are used when they are not set.
+type UserDirs struct {
+	Config string // User-level configuration such as config.toml
+	Data   string // Data that must be kept, such as the shared skill store
+	Cache  string // Data that can be deleted at any time
+	State  string // Data that persists between runs but is not configuration, such as logs
+	Temp   string // Base directory for temporary downloads
+}
+
+// ResolveUserDirs returns the directories of the current user.
+func ResolveUserDirs() (*UserDirs, error) {
+	configHome, err := baseDir("XDG_CONFIG_HOME", os.UserConfigDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user config directory: %w", err)
+	}
+	dataHome, err := baseDir("XDG_DATA_HOME", defaultDataHome)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user data directory: %w", err)
+	}
+	cacheHome, err := baseDir("XDG_CACHE_HOME", os.UserCacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user cache directory: %w", err)
+	}
+	stateHome, err := baseDir("XDG_STATE_HOME", defaultStateHome)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user state directory: %w", err)
+	}
+
+	temp := os.Getenv("SKILLSPKG_TEMP_DIR")
+	if temp == "" {
+		temp = os.TempDir()
+	}
+
+	return &UserDirs{
+		Config: filepath.Join(configHome, appDirName),
+		Data:   filepath.Join(dataHome, appDirName),
+		Cache:  filepath.Join(cacheHome, appDirName),
+		State:  filepath.Join(stateHome, appDirName),
+		Temp:   temp,
+	}, nil
+}
+
+// ConfigFile returns the path of the user-level configuration file.
+func (d *UserDirs) ConfigFile() string {
+	return filepath.Join(d.Config, "config.toml")
+}
+
+// SecretKeyFile returns the file of secret keys that decrypt encrypted configuration values.
+// It is the file named by SKILLSPKG_SECRET_KEY_FILE when that is set.
+func (d *UserDirs) SecretKeyFile() string {
+	if path := os.Getenv(SecretKeyFileEnv); path != "" {
+		return path
+	}
+	return filepath.Join(d.Config, "keys.txt")
+}
+
+// StoreDir returns the directory of the shared skill store.
+func (d *UserDirs) StoreDir() string {
+	return filepath.Join(d.Data, "store")
+}
+
+// DownloadCacheDir returns the directory that keeps downloaded sources for reuse.
+func (d *UserDirs) DownloadCacheDir() string {
+	return filepath.Join(d.Cache, "downloads")
+}
+
+// DaemonSocket returns the path of the socket that 'skills-pkg daemon' listens on.
+func (d *UserDirs) DaemonSocket() string {
+	return filepath.Join(d.Cache, "daemon.sock")
+}
+
+// LogDir returns the directory that receives logs of scheduled jobs.
+func (d *UserDirs) LogDir() string {
+	return filepath.Join(d.State, "logs")
+}
+
+// HistoryFile returns the file that records the recent commands and their errors for 'debug bundle'.
+func (d *UserDirs) HistoryFile() string {
+	return filepath.Join(d.State, "history.jsonl")
+}
+
+// LastOperationLog returns the file that keeps the messages of the last command for 'debug bundle'.
+func (d *UserDirs) LastOperationLog() string {
+	return filepath.Join(d.State, "last-operation.log")
+}
+
+// BackupDir returns the directory that keeps backups of replaced and removed skills.
+func (d *UserDirs) BackupDir() string {
+	return filepath.Join(d.State, "backups")
+}
+
+// baseDir returns the directory named by the environment variable, or the platform default.
+// Relative paths are ignored, as required by the XDG Base Directory Specification.
+func baseDir(envVar string, platformDefault func() (string, error)) (string, error) {
+	if dir := os.Getenv(envVar); dir != "" && filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	return platformDefault()
+}
+
+// defaultDataHome returns the platform's directory for application data.
+func defaultDataHome() (string, error) {
+	return platformHome(filepath.Join(".local", "share"))
+}
+
+// defaultStateHome returns the platform's directory for persistent application state.
+func defaultStateHome() (string, error) {
+	return platformHome(filepath.Join(".local", "state"))
+}
+
+// platformHome returns the Application Support directory on macOS and %LocalAppData% on Windows,
+// and the given directory below the home directory on other platforms.
+func platformHome(homeRel string) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		// ~/Library/Application Support
+		return os.UserConfigDir()
+	case "windows":
+		// %LocalAppData%
+		return os.UserCacheDir()
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, homeRel), nil
+}
+
+// MigrateLegacyUserDirs moves files from the locations used by earlier versions of skills-pkg
+// into dirs, and returns a description of each move. Files that already exist at the new
+// location are left untouched, so running it repeatedly is safe.
+//
+// Earlier versions kept the user-level configuration in the platform's config directory
+// regardless of XDG_CONFIG_HOME, and scheduled job logs in the cache directory.
+func MigrateLegacyUserDirs(dirs *UserDirs) ([]string, error) {
+	var moved []string
+
+	if configDir, err := os.UserConfigDir(); err == nil {
+		legacy := filepath.Join(configDir, appDirName, "config.toml")
+		ok, err := moveIfAbsent(legacy, dirs.ConfigFile())
+		if err != nil {
+			return moved, err
+		}
+		if ok {
+			moved = append(moved, fmt.Sprintf("%s -> %s", legacy, dirs.ConfigFile()))
+		}
+	}
+
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		logs, err := filepath.Glob(filepath.Join(cacheDir, appDirName, "*.log"))
+		if err != nil {
+			return moved, fmt.Errorf("failed to find legacy log files: %w", err)
+		}
+		for _, legacy := range logs {
+			path := filepath.Join(dirs.LogDir(), filepath.Base(legacy))
+			ok, err := moveIfAbsent(legacy, path)
+			if err != nil {
+				return moved, err
+			}
+			if ok {
+				moved = append(moved, fmt.Sprintf("%s -> %s", legacy, path))
+			}
+		}
+	}
+
+	return moved, nil
+}
+
+// moveIfAbsent renames from to to when from exists and to does not.
+// It reports whether the file was moved.
+func moveIfAbsent(from, to string) (bool, error) {
+	if samePath(from, to) {
+		return false, nil
+	}
+	if _, err := os.Lstat(from); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to access %s: %w", from, err)
+	}
+	if _, err := os.Lstat(to); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), installDirMode); err != nil {
+		return false, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return false, fmt.Errorf("failed to move %s to %s: %w", from, to, err)
+	}
+
+	return true, nil
+}
+
+// samePath reports whether two paths refer to the same location.
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
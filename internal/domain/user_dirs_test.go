@@ -0,0 +1,115 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/domain"
+)
+
+func TestResolveUserDirs(t *testing.T) {
+	configHome := t.TempDir()
+	dataHome := t.TempDir()
+	cacheHome := t.TempDir()
+	stateHome := t.TempDir()
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	t.Setenv("SKILLSPKG_TEMP_DIR", tempDir)
+
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		t.Fatalf("ResolveUserDirs() error = %v", err)
+	}
+
+	want := &domain.UserDirs{
+		Config: filepath.Join(configHome, "skills-pkg"),
+		Data:   filepath.Join(dataHome, "skills-pkg"),
+		Cache:  filepath.Join(cacheHome, "skills-pkg"),
+		State:  filepath.Join(stateHome, "skills-pkg"),
+		Temp:   tempDir,
+	}
+	if *dirs != *want {
+		t.Errorf("ResolveUserDirs() = %+v, want %+v", dirs, want)
+	}
+	if got := dirs.ConfigFile(); got != filepath.Join(configHome, "skills-pkg", "config.toml") {
+		t.Errorf("ConfigFile() = %s", got)
+	}
+	if got := dirs.StoreDir(); got != filepath.Join(dataHome, "skills-pkg", "store") {
+		t.Errorf("StoreDir() = %s", got)
+	}
+	if got := dirs.LogDir(); got != filepath.Join(stateHome, "skills-pkg", "logs") {
+		t.Errorf("LogDir() = %s", got)
+	}
+}
+
+func TestResolveUserDirs_IgnoresRelativePaths(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		t.Fatalf("ResolveUserDirs() error = %v", err)
+	}
+	if !filepath.IsAbs(dirs.State) {
+		t.Errorf("State = %s, want an absolute path", dirs.State)
+	}
+}
+
+func TestMigrateLegacyUserDirs(t *testing.T) {
+	cacheHome := t.TempDir()
+	stateHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	dirs, err := domain.ResolveUserDirs()
+	if err != nil {
+		t.Fatalf("ResolveUserDirs() error = %v", err)
+	}
+
+	// Earlier versions wrote scheduled job logs to the cache directory
+	legacyDir := filepath.Join(cacheHome, "skills-pkg")
+	if err := os.MkdirAll(legacyDir, 0o755); err != nil {
+		t.Fatalf("failed to create legacy directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(legacyDir, "job-a.log"), []byte("old a"), 0o644); err != nil {
+		t.Fatalf("failed to write legacy log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(legacyDir, "job-b.log"), []byte("old b"), 0o644); err != nil {
+		t.Fatalf("failed to write legacy log: %v", err)
+	}
+
+	// A log that already exists at the new location is kept
+	if err := os.MkdirAll(dirs.LogDir(), 0o755); err != nil {
+		t.Fatalf("failed to create log directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dirs.LogDir(), "job-b.log"), []byte("new b"), 0o644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	moved, err := domain.MigrateLegacyUserDirs(dirs)
+	if err != nil {
+		t.Fatalf("MigrateLegacyUserDirs() error = %v", err)
+	}
+	if len(moved) != 1 {
+		t.Errorf("MigrateLegacyUserDirs() moved = %v, want 1 entry", moved)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dirs.LogDir(), "job-a.log")); err != nil || string(data) != "old a" {
+		t.Errorf("job-a.log = %q, %v, want moved legacy log", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, "job-a.log")); !os.IsNotExist(err) {
+		t.Errorf("legacy job-a.log should have been moved, stat error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dirs.LogDir(), "job-b.log")); err != nil || string(data) != "new b" {
+		t.Errorf("job-b.log = %q, %v, want existing log kept", data, err)
+	}
+
+	// Running again is a no-op
+	moved, err = domain.MigrateLegacyUserDirs(dirs)
+	if err != nil || len(moved) != 0 {
+		t.Errorf("second MigrateLegacyUserDirs() = %v, %v, want nothing moved", moved, err)
+	}
+}
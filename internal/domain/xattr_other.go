@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package domain
+
+// copyXattrs copies the extended attributes of src to dst.
+// Extended attributes are only copied on Linux and macOS.
+func copyXattrs(_, _ string) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build linux || darwin
+
+package domain
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// copyXattrs copies the extended attributes of src to dst. Attributes that dst does not
+// support or that the process may not set, such as those in the trusted namespace, are skipped.
+func copyXattrs(src, dst string) error {
+	size, err := unix.Listxattr(src, nil)
+	if err != nil {
+		if errors.Is(err, unix.ENOTSUP) {
+			return nil
+		}
+		return err
+	}
+	if size == 0 {
+		return nil
+	}
+
+	names := make([]byte, size)
+	size, err = unix.Listxattr(src, names)
+	if err != nil {
+		return err
+	}
+
+	for name := range bytes.SplitSeq(names[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+
+		valueSize, err := unix.Getxattr(src, string(name), nil)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, valueSize)
+		valueSize, err = unix.Getxattr(src, string(name), value)
+		if err != nil {
+			return err
+		}
+
+		if err := unix.Setxattr(dst, string(name), value[:valueSize], 0); err != nil {
+			if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.EACCES) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
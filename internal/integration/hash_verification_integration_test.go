@@ -8,6 +8,7 @@ import (
 
 	"github.com/mazrean/skills-pkg/internal/adapter/service"
 	"github.com/mazrean/skills-pkg/internal/domain"
+	"github.com/mazrean/skills-pkg/internal/port"
 )
 
 // TestHashVerificationIntegration tests the integration of hash verification with file system.
@@ -77,7 +78,7 @@ func TestHashVerificationIntegration(t *testing.T) {
 				}
 
 				hashVerifier := domain.NewHashVerifier(configManager, hashService)
-				hashResult, err := hashService.CalculateHash(ctx, skillDir)
+				hashResult, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 				if err != nil {
 					t.Fatalf("CalculateHash failed: %v", err)
 				}
@@ -124,7 +125,7 @@ func TestHashVerificationIntegration(t *testing.T) {
 
 				ctx := context.Background()
 				hashService := service.NewDirhash()
-				originalHash, err := hashService.CalculateHash(ctx, skillDir)
+				originalHash, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 				if err != nil {
 					t.Fatalf("CalculateHash failed: %v", err)
 				}
@@ -204,7 +205,7 @@ func TestHashVerificationIntegration(t *testing.T) {
 						t.Fatalf("Failed to create test file: %v", writeErr)
 					}
 
-					hashResult, hashErr := hashService.CalculateHash(ctx, skillDir)
+					hashResult, hashErr := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 					if hashErr != nil {
 						t.Fatalf("CalculateHash failed: %v", hashErr)
 					}
@@ -297,12 +298,12 @@ func TestHashVerificationIntegration(t *testing.T) {
 				return ctx, nil, hashService, skillDir
 			},
 			testFunc: func(t *testing.T, ctx context.Context, configManager *domain.ConfigManager, hashService *service.Dirhash, skillDir string) {
-				hash1, err := hashService.CalculateHash(ctx, skillDir)
+				hash1, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 				if err != nil {
 					t.Fatalf("First CalculateHash failed: %v", err)
 				}
 
-				hash2, err := hashService.CalculateHash(ctx, skillDir)
+				hash2, err := hashService.CalculateHash(ctx, skillDir, port.HashAlgorithmH1)
 				if err != nil {
 					t.Fatalf("Second CalculateHash failed: %v", err)
 				}
@@ -0,0 +1,80 @@
+package port
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FileSystem is the abstraction interface for the file system that installed skills are hashed, inspected,
+// and removed in. Installing skills always writes to the local file system.
+// Names are paths of the operating system, as accepted by the os package, so an implementation can
+// stand in for the local file system without translating paths. Implementations other than
+// OSFileSystem, such as in-memory file systems for tests, need not support symbolic links.
+type FileSystem interface {
+	// Open opens the named file for reading. Directories implement fs.ReadDirFile.
+	Open(name string) (fs.File, error)
+	// Stat returns the information of the named file, following symbolic links.
+	Stat(name string) (fs.FileInfo, error)
+	// Lstat returns the information of the named file without following a symbolic link.
+	Lstat(name string) (fs.FileInfo, error)
+	// ReadDir returns the entries of the named directory sorted by name.
+	ReadDir(name string) ([]fs.DirEntry, error)
+	// ReadFile returns the contents of the named file.
+	ReadFile(name string) ([]byte, error)
+	// WriteFile writes data to the named file, creating it with perm if it does not exist.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// MkdirAll creates the directory and any missing parents.
+	MkdirAll(path string, perm fs.FileMode) error
+	// RemoveAll removes the path and anything it contains. A missing path is not an error.
+	RemoveAll(path string) error
+	// Rename moves oldpath to newpath, replacing newpath if it is a file.
+	Rename(oldpath, newpath string) error
+}
+
+// OSFileSystem is the FileSystem of the operating system.
+type OSFileSystem struct{}
+
+// Open opens the named file with os.Open.
+func (OSFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+// Stat returns the information of the named file with os.Stat.
+func (OSFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// Lstat returns the information of the named file with os.Lstat.
+func (OSFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+// ReadDir reads the named directory with os.ReadDir.
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// ReadFile reads the named file with os.ReadFile.
+func (OSFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// WriteFile writes the named file with os.WriteFile.
+func (OSFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// MkdirAll creates the directory with os.MkdirAll.
+func (OSFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// RemoveAll removes the path with os.RemoveAll.
+func (OSFileSystem) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+// Rename moves oldpath to newpath with os.Rename.
+func (OSFileSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
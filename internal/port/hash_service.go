@@ -1,15 +1,70 @@
 package port
 
-import "context"
+import (
+	"context"
+	"strings"
+)
+
+// Hash algorithm identifiers. They are used as the prefix of HashResult.Value.
+const (
+	// HashAlgorithmH1 hashes raw file names and contents (golang.org/x/mod/sumdb/dirhash Hash1).
+	HashAlgorithmH1 = "h1"
+	// HashAlgorithmN1 is Hash1 over contents normalized for cross-platform stability:
+	// CRLF line endings in text files are converted to LF before hashing.
+	HashAlgorithmN1 = "n1"
+)
+
+// HashAlgorithmOf returns the algorithm identifier of a hash value (e.g., "h1" for "h1:<base64>").
+// It returns HashAlgorithmH1 when the value has no recognizable prefix.
+func HashAlgorithmOf(value string) string {
+	if algorithm, _, ok := strings.Cut(value, ":"); ok && algorithm != "" {
+		return algorithm
+	}
+	return HashAlgorithmH1
+}
 
 // HashService is the abstraction interface for calculating directory hashes.
 // It provides hash calculation for skill integrity verification.
 // Requirements: 5.1
 type HashService interface {
-	// CalculateHash calculates the hash of a directory.
-	// The hash includes both file names and file contents recursively.
-	// Returns an error if the directory does not exist or cannot be read.
-	CalculateHash(ctx context.Context, dirPath string) (*HashResult, error)
+	// CalculateHash calculates the hash of a directory using the given algorithm.
+	// The hash includes both file names and file contents recursively,
+	// except for what the options leave out.
+	// An empty algorithm selects HashAlgorithmH1.
+	// Returns an error if the directory does not exist or cannot be read, or the algorithm is unknown.
+	CalculateHash(ctx context.Context, dirPath string, algorithm string, opts ...HashOption) (*HashResult, error)
+}
+
+// HashOptions are what CalculateHash leaves out of the hash of a directory.
+type HashOptions struct {
+	Exclude     []string // Gitignore-style patterns of files that are not hashed
+	StripBanner bool     // The banner line that skills-pkg inserts into installed SKILL.md files is not hashed
+}
+
+// HashOption configures HashOptions.
+type HashOption func(*HashOptions)
+
+// ExcludeFiles leaves the files matching the gitignore-style patterns out of the hash.
+func ExcludeFiles(patterns ...string) HashOption {
+	return func(o *HashOptions) {
+		o.Exclude = append(o.Exclude, patterns...)
+	}
+}
+
+// StripBanner leaves the banner line that skills-pkg inserts into installed SKILL.md files out of the hash.
+func StripBanner() HashOption {
+	return func(o *HashOptions) {
+		o.StripBanner = true
+	}
+}
+
+// NewHashOptions returns the HashOptions configured by opts.
+func NewHashOptions(opts ...HashOption) *HashOptions {
+	options := &HashOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
 }
 
 // HashResult represents the result of a hash calculation.
@@ -17,4 +72,5 @@ type HashService interface {
 // Requirements: 5.2
 type HashResult struct {
 	Value string // Hash value with algorithm prefix (e.g., "h1:<base64>")
+	Bytes int64  // Number of content bytes hashed
 }
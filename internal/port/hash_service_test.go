@@ -56,7 +56,7 @@ func TestHashResultStructure(t *testing.T) {
 // mockHashService is a mock implementation of HashService for testing.
 type mockHashService struct{}
 
-func (m *mockHashService) CalculateHash(ctx context.Context, dirPath string) (*port.HashResult, error) {
+func (m *mockHashService) CalculateHash(ctx context.Context, dirPath string, algorithm string, opts ...port.HashOption) (*port.HashResult, error) {
 	return &port.HashResult{
 		Value: "h1:mockhash",
 	}, nil
@@ -0,0 +1,10 @@
+package port
+
+import "context"
+
+// Notifier is the abstraction interface for showing notifications to the user
+// outside the terminal, such as desktop notifications.
+type Notifier interface {
+	// Notify shows a notification with the given title and message.
+	Notify(ctx context.Context, title, message string) error
+}
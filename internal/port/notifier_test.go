@@ -0,0 +1,34 @@
+package port_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TestNotifierInterface verifies that the Notifier interface contract
+// can be satisfied by a mock implementation.
+func TestNotifierInterface(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "interface_contract",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Verify that a mock implementation satisfies the interface
+			var _ port.Notifier = &mockNotifier{}
+		})
+	}
+}
+
+// mockNotifier is a mock implementation of Notifier for testing.
+type mockNotifier struct{}
+
+func (m *mockNotifier) Notify(ctx context.Context, title, message string) error {
+	return nil
+}
@@ -0,0 +1,10 @@
+package port
+
+import "context"
+
+// Opener is the abstraction interface for opening a directory in the file manager
+// or a URL in the web browser.
+type Opener interface {
+	// Open opens the local path or URL with the user's default application.
+	Open(ctx context.Context, location string) error
+}
@@ -0,0 +1,34 @@
+package port_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TestOpenerInterface verifies that the Opener interface contract
+// can be satisfied by a mock implementation.
+func TestOpenerInterface(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "interface_contract",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Verify that a mock implementation satisfies the interface
+			var _ port.Opener = &mockOpener{}
+		})
+	}
+}
+
+// mockOpener is a mock implementation of Opener for testing.
+type mockOpener struct{}
+
+func (m *mockOpener) Open(ctx context.Context, location string) error {
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 )
 
 // PackageManager is the abstraction interface for downloading skills from various sources.
-// It supports Git repositories and Go Module proxy.
+// It supports Git repositories, Go Module proxy, the npm registry, OCI registries, and archive URLs.
 // Requirements: 11.1, 11.3
 type PackageManager interface {
 	// Download downloads the skill from the source.
@@ -18,17 +18,32 @@ type PackageManager interface {
 	// GetLatestVersion retrieves the latest version of the skill.
 	GetLatestVersion(ctx context.Context, source *Source) (string, error)
 
-	// SourceType returns the type of the source (git, go-mod).
+	// SourceType returns the type of the source (git, go-mod, npm, oci, archive, local).
 	SourceType() string
 }
 
+// VersionLister is implemented by the package managers that can list the versions of a source,
+// so that updates limited to minor or patch versions can pick the newest version within the limit.
+type VersionLister interface {
+	// ListVersions returns the versions of the skill, in no particular order.
+	ListVersions(ctx context.Context, source *Source) ([]string, error)
+}
+
+// SourceOptionHardenedExtraction is the Source option that asks for downloaded archives
+// to be extracted in a sandboxed process. Its value is "true" when enabled.
+const SourceOptionHardenedExtraction = "hardened_extraction"
+
+// SourceOptionSHA256 is the Source option that pins the hex SHA-256 digest of the file
+// an archive source downloads.
+const SourceOptionSHA256 = "sha256"
+
 // Source represents the source location for a skill.
 // It contains the type, URL, and optional parameters.
 // Requirements: 2.3, 2.4, 11.4
 type Source struct {
 	Options map[string]string // Optional parameters (e.g., registry URL)
-	Type    string            // "git", "go-mod"
-	URL     string            // Git URL, Go module path
+	Type    string            // "git", "go-mod", "npm", "oci", "archive", "local"
+	URL     string            // Git URL, Go module path, npm package name, OCI repository, archive URL, directory path
 }
 
 // Validate validates the source configuration.
@@ -44,11 +59,15 @@ func (s *Source) Validate() error {
 
 	// Validate source type
 	validTypes := map[string]bool{
-		"git":    true,
-		"go-mod": true,
+		"git":     true,
+		"go-mod":  true,
+		"npm":     true,
+		"oci":     true,
+		"archive": true,
+		"local":   true,
 	}
 	if !validTypes[s.Type] {
-		return errors.New("invalid source type: must be git or go-mod")
+		return errors.New("invalid source type: must be git, go-mod, npm, oci, archive, or local")
 	}
 
 	return nil
@@ -0,0 +1,27 @@
+package port
+
+import "context"
+
+// PolicyEngine is the abstraction interface for policy languages that decide
+// whether a skill may be installed.
+type PolicyEngine interface {
+	// Compile parses and checks a policy. Errors describe the location of the problem in the source.
+	Compile(source string) (Policy, error)
+}
+
+// Policy is a compiled policy.
+type Policy interface {
+	// Evaluate returns the reason the skill is denied, or an empty string when it is allowed.
+	Evaluate(ctx context.Context, input *PolicyInput) (string, error)
+}
+
+// PolicyInput describes a downloaded skill to a policy.
+type PolicyInput struct {
+	Name    string
+	Source  string // "git", "go-mod", "npm", "oci", "archive", "local"
+	URL     string
+	Version string
+	License string   // License declared in SKILL.md or detected from a license file; empty when unknown
+	Files   []string // Slash-separated paths of the skill's files
+	Size    int64    // Total size of the files in bytes
+}
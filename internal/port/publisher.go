@@ -0,0 +1,34 @@
+package port
+
+import "context"
+
+// PublishArtifact is a skill packed for publishing: a reproducible archive of its files and the
+// manifest that describes it.
+type PublishArtifact struct {
+	Name        string // Skill name from SKILL.md
+	Description string // Skill description from SKILL.md
+	Version     string // Version being published, such as "v1.2.0"
+	Dir         string // Skill directory the artifact was built from
+	Archive     string // Path of the <name>-<version>.tar.gz archive, with the files under a <name> directory
+	Manifest    string // Path of the <name>-<version>.json manifest
+	Hash        string // Content hash of the skill files
+	SHA256      string // Hex SHA-256 digest of the archive
+}
+
+// PublishResult tells where a skill was published and how consumers install it.
+type PublishResult struct {
+	Location string  // Where the artifact was published, such as a release URL or an OCI reference
+	Source   *Source // Source that installs the published skill
+	Version  string  // Version to install from Source
+	SubDir   string  // Directory of the skill within the downloaded source
+}
+
+// Publisher is the abstraction interface for the places skills are published to, so that they can
+// be installed from a source type, such as a git tag or an OCI registry.
+type Publisher interface {
+	// PublishTarget returns the name of the target, as given to 'skills-pkg publish --to'.
+	PublishTarget() string
+	// Publish pushes the artifact to destination, whose meaning depends on the target, such as a git
+	// remote or an OCI repository. An empty destination selects the target's default, if it has one.
+	Publish(ctx context.Context, artifact *PublishArtifact, destination string) (*PublishResult, error)
+}
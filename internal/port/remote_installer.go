@@ -0,0 +1,17 @@
+package port
+
+import "context"
+
+// RemoteInstaller is the abstraction interface for install targets on other machines.
+// Remote targets are written as URLs (e.g., ssh://user@host/path) in install_targets.
+type RemoteInstaller interface {
+	// Scheme returns the URL scheme handled by the installer (e.g., "ssh").
+	Scheme() string
+
+	// Install replaces the skill directory <target>/<skillName> with the given files from sourceDir.
+	// files are slash-separated paths relative to sourceDir.
+	Install(ctx context.Context, target, skillName, sourceDir string, files []string) error
+
+	// Remove deletes the skill directory <target>/<skillName> if it exists.
+	Remove(ctx context.Context, target, skillName string) error
+}
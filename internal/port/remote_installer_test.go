@@ -0,0 +1,42 @@
+package port_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TestRemoteInstallerInterface verifies that the RemoteInstaller interface contract
+// can be satisfied by a mock implementation.
+func TestRemoteInstallerInterface(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "interface_contract",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Verify that a mock implementation satisfies the interface
+			var _ port.RemoteInstaller = &mockRemoteInstaller{}
+		})
+	}
+}
+
+// mockRemoteInstaller is a mock implementation of RemoteInstaller for testing.
+type mockRemoteInstaller struct{}
+
+func (m *mockRemoteInstaller) Scheme() string {
+	return "mock"
+}
+
+func (m *mockRemoteInstaller) Install(ctx context.Context, target, skillName, sourceDir string, files []string) error {
+	return nil
+}
+
+func (m *mockRemoteInstaller) Remove(ctx context.Context, target, skillName string) error {
+	return nil
+}
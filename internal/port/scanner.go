@@ -0,0 +1,27 @@
+package port
+
+import "context"
+
+// ContentScanner is the abstraction interface for external scanners, such as antivirus
+// or data loss prevention tools, that inspect downloaded skills before they are installed.
+type ContentScanner interface {
+	// Scan runs the scanner command on the directory of the target.
+	// A scanner that rejects the content yields a result that is not clean;
+	// an error is only returned when the scanner could not be run.
+	Scan(ctx context.Context, command []string, target *ScanTarget) (*ScanResult, error)
+}
+
+// ScanTarget is a downloaded skill to be scanned.
+type ScanTarget struct {
+	Dir     string // Directory of the downloaded skill
+	Name    string
+	Source  string
+	URL     string
+	Version string
+}
+
+// ScanResult is the verdict of a scanner.
+type ScanResult struct {
+	Output string // Output of the scanner, explaining a rejection
+	Clean  bool
+}
@@ -0,0 +1,32 @@
+package port
+
+import "context"
+
+// Schedule intervals supported by every Scheduler.
+const (
+	ScheduleHourly = "hourly"
+	ScheduleDaily  = "daily"
+	ScheduleWeekly = "weekly"
+)
+
+// ScheduledJob describes a command that a Scheduler runs periodically as the current user.
+type ScheduledJob struct {
+	Name     string   // Identifier of the job, unique per user (e.g., "skills-pkg-autoupdate-1a2b3c4d")
+	WorkDir  string   // Directory the command runs in
+	Interval string   // One of ScheduleHourly, ScheduleDaily, or ScheduleWeekly
+	LogPath  string   // File that receives the command's stdout and stderr
+	Command  []string // Executable path followed by its arguments
+}
+
+// Scheduler is the abstraction interface for user-level job schedulers
+// such as launchd, systemd timers, and cron.
+type Scheduler interface {
+	// Name returns the name of the scheduler (e.g., "systemd").
+	Name() string
+
+	// Install registers the job, replacing any existing job with the same name.
+	Install(ctx context.Context, job *ScheduledJob) error
+
+	// Remove unregisters the job with the given name. Removing a job that does not exist is not an error.
+	Remove(ctx context.Context, name string) error
+}
@@ -0,0 +1,42 @@
+package port_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mazrean/skills-pkg/internal/port"
+)
+
+// TestSchedulerInterface verifies that the Scheduler interface contract
+// can be satisfied by a mock implementation.
+func TestSchedulerInterface(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "interface_contract",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Verify that a mock implementation satisfies the interface
+			var _ port.Scheduler = &mockScheduler{}
+		})
+	}
+}
+
+// mockScheduler is a mock implementation of Scheduler for testing.
+type mockScheduler struct{}
+
+func (m *mockScheduler) Name() string {
+	return "mock"
+}
+
+func (m *mockScheduler) Install(ctx context.Context, job *port.ScheduledJob) error {
+	return nil
+}
+
+func (m *mockScheduler) Remove(ctx context.Context, name string) error {
+	return nil
+}
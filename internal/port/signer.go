@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSignatureInvalid is wrapped by the errors of Signer.Verify when the signature does not verify,
+// as opposed to errors that prevent checking it, such as a missing tool.
+var ErrSignatureInvalid = errors.New("signature is invalid")
+
+// Signer is the abstraction interface for signature tools, such as minisign and cosign,
+// that sign lock files and verify their signatures.
+type Signer interface {
+	// Method returns the name of the signing method, as used in the configuration.
+	Method() string
+	// SignatureExt returns the extension of signature files, such as ".minisig".
+	SignatureExt() string
+	// Sign signs message with the secret key that key refers to and returns the signature file content.
+	Sign(ctx context.Context, message []byte, key string) ([]byte, error)
+	// Verify checks the signature of message with the public key that publicKey refers to.
+	Verify(ctx context.Context, message, signature []byte, publicKey string) error
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/mazrean/skills-pkg/internal/adapter/pkgmanager"
 	"github.com/mazrean/skills-pkg/internal/cli"
 )
 
@@ -14,12 +16,49 @@ var CLI struct {
 	Uninstall        cli.UninstallCmd        `cmd:"" help:"Remove a skill from configuration and install targets"`
 	Add              cli.AddCmd              `cmd:"" help:"Add a skill to configuration and install it"`
 	Install          cli.InstallCmd          `cmd:"" help:"Install skills from configuration"`
-	Search           cli.SearchCmd           `cmd:"" help:"Search for available skills on skills.sh"`
+	Sync             cli.SyncCmd             `cmd:"" help:"Make install targets match the configuration: install missing, repair drifted, and remove orphaned skills"`
+	Plan             cli.PlanCmd             `cmd:"" help:"Show the installs, updates, and removals that would bring install targets in line with the configuration"`
+	Apply            cli.ApplyCmd            `cmd:"" help:"Execute a plan saved by 'plan --out'"`
+	Search           cli.SearchCmd           `cmd:"" help:"Search skill indexes such as skills.sh, and GitHub repository topics, for skills"`
+	Recommend        cli.RecommendCmd        `cmd:"" help:"Recommend skills from skills.sh for the languages, frameworks, and tools the project uses"`
 	AddInstallTarget cli.AddInstallTargetCmd `cmd:"" name:"add-install-target" help:"Add an install target directory to configuration"`
+	Target           cli.TargetCmd           `cmd:"" help:"Add or remove install targets, optionally installing or deleting skills in them"`
 	Init             cli.InitCmd             `cmd:"" help:"Initialize project with .skillspkg.toml configuration file"`
 	Update           cli.UpdateCmd           `cmd:"" help:"Update skills to latest versions"`
+	Restore          cli.RestoreCmd          `cmd:"" help:"Put back the copy of a skill saved before it was last updated or removed"`
 	SetupCI          cli.SetupCICmd          `cmd:"" name:"setup-ci" help:"Set up CI configuration for automated skill updates"`
-	Verbose          bool                    `help:"Enable verbose logging" short:"v" env:"SKILLSPKG_VERBOSE" default:"false"`
+	Pack             cli.PackCmd             `cmd:"" help:"Pack an installed skill into a tar.gz archive"`
+	Publish          cli.PublishCmd          `cmd:"" help:"Validate a skill directory, pack it with a manifest, and publish it as a git tag, GitHub release, or OCI artifact"`
+	Containerize     cli.ContainerizeCmd     `cmd:"" help:"Generate a Dockerfile or devcontainer snippet that installs the project's skills"`
+	Export           cli.ExportCmd           `cmd:"" help:"Export the skill set as a chezmoi script or home-manager module to reproduce it on other machines"`
+	Mirror           cli.MirrorCmd           `cmd:"" help:"Download every pinned version of the configured skills into a directory for installs without network access"`
+	Nix              cli.NixCmd              `cmd:"" help:"Generate a Nix expression that pins every skill by URL and hash"`
+	Bazel            cli.BazelCmd            `cmd:"" help:"Generate Bazel http_archive rules that pin every skill by URL and sha256"`
+	Autoupdate       cli.AutoupdateCmd       `cmd:"" help:"Manage scheduled automatic skill updates"`
+	Env              cli.EnvCmd              `cmd:"" help:"Print the files and directories skills-pkg uses"`
+	Keygen           cli.KeygenCmd           `cmd:"" help:"Generate a secret key for decrypting encrypted configuration values"`
+	Encrypt          cli.EncryptCmd          `cmd:"" help:"Encrypt a value, such as a private skill URL, for use in .skillspkg.toml"`
+	Explain          cli.ExplainCmd          `cmd:"" help:"Explain an error code, configuration key, or source type without leaving the terminal"`
+	Store            cli.StoreCmd            `cmd:"" help:"Manage the machine-wide shared skill store"`
+	Org              cli.OrgCmd              `cmd:"" help:"Apply the skill policy that an organization publishes for all of its projects"`
+	Review           cli.ReviewCmd           `cmd:"" help:"Approve or block versions of skills, and require approval before they are installed"`
+	Lock             cli.LockCmd             `cmd:"" help:"Sign .skillspkg.lock and verify its signature"`
+	Open             cli.OpenCmd             `cmd:"" help:"Open an installed skill in the file manager, or its upstream page with --web"`
+	Cat              cli.CatCmd              `cmd:"" help:"Print a file of an installed skill, SKILL.md by default"`
+	DiffTargets      cli.DiffTargetsCmd      `cmd:"" name:"diff-targets" help:"Compare the copies of a skill in two install targets"`
+	Serve            cli.ServeCmd            `cmd:"" help:"Serve an HTTP API to list, install, update, and verify skills remotely"`
+	Scan             cli.ScanCmd             `cmd:"" help:"Report the skills and versions used by every project under a directory"`
+	Usage            cli.UsageCmd            `cmd:"" help:"Report how often installed skills are referenced in agent transcripts, or only the unused ones"`
+	Debug            cli.DebugCmd            `cmd:"" help:"Collect information for bug reports"`
+	Daemon           cli.DaemonCmd           `cmd:"" help:"Keep the latest versions of skills and their downloads warm in the background for 'list --outdated' and 'update'"`
+	Onboard          cli.OnboardCmd          `cmd:"" default:"1" hidden:"" help:"Set up skills-pkg for the project with guided prompts"`
+	Verbose          cli.Verbosity           `help:"Enable verbose logging; repeat for more detail: -vv traces HTTP requests and git operations, and -vvv also dumps their headers and output" short:"v" env:"SKILLSPKG_VERBOSE"`
+	Quiet            bool                    `help:"Print only the warnings among the progress messages of skills" short:"q" env:"SKILLSPKG_QUIET" default:"false"`
+	AllowRoot        bool                    `help:"Allow installing into targets in the home directory owned by other users when running as root" name:"allow-root" env:"SKILLSPKG_ALLOW_ROOT" default:"false"`
+	Config           string                  `help:"Project configuration file to use instead of the .skillspkg.toml found in the current directory or its parents" env:"SKILLSPKG_CONFIG" type:"path" placeholder:"FILE" xor:"config"`
+	Profile          string                  `help:"Use the configuration profile in .skillspkg/<profile>.toml instead of .skillspkg.toml" env:"SKILLSPKG_PROFILE" xor:"config"`
+	Output           string                  `help:"Output format: text, or json to write the results of list, verify, install, update, and the other commands with structured results to standard output as JSON" enum:"text,json" default:"text" env:"SKILLSPKG_OUTPUT"`
+	LimitRate        string                  `help:"Limit the bandwidth of all downloads together, in bytes per second with an optional K, M, or G suffix, such as 500K" name:"limit-rate" env:"SKILLSPKG_LIMIT_RATE" placeholder:"RATE"`
 }
 
 // Version information (will be injected by GoReleaser via ldflags)
@@ -30,6 +69,15 @@ var (
 )
 
 func main() {
+	// Archives of hardened downloads are extracted by a sandboxed copy of this executable
+	if pkgmanager.IsExtractionSandbox() {
+		os.Exit(pkgmanager.RunExtractionSandbox())
+	}
+	// Under sudo, skills are downloaded by a copy of this executable running as the user who ran sudo
+	if pkgmanager.IsDownloadHelper() {
+		os.Exit(pkgmanager.RunDownloadHelper())
+	}
+
 	ctx := kong.Parse(&CLI,
 		kong.Name("skills-pkg"),
 		kong.Description("Agent Skills package manager for Claude Code and Codex CLI"),
@@ -43,14 +91,39 @@ func main() {
 		}),
 		kong.Vars{
 			"version": version,
+			"commit":  commit,
+			"date":    date,
 		},
 	)
 
+	// The messages of the command are kept for 'debug bundle'
+	cli.StartOperationLog()
+	start := time.Now()
+
+	// Commands run in the project directory, wherever in the project they are started
+	if err := cli.EnterProject(CLI.Config, CLI.Profile, ctx.Command(), CLI.Verbose > 0); err != nil {
+		cli.ReportError(os.Stderr, err)
+		cli.RecordOperation(ctx.Command(), version, start, err)
+		os.Exit(1)
+	}
+
+	// Downloads of every source type share the bandwidth limit
+	if err := cli.ConfigureNetwork(CLI.LimitRate, version, CLI.Verbose); err != nil {
+		cli.ReportError(os.Stderr, err)
+		cli.RecordOperation(ctx.Command(), version, start, err)
+		os.Exit(1)
+	}
+
+	// Progress messages of skills are filtered by level
+	cli.ConfigureLogging(CLI.Verbose > 0, CLI.Quiet)
+
 	// Execute the selected command
 	err := ctx.Run()
+	cli.RecordOperation(ctx.Command(), version, start, err)
 
 	// Handle exit codes according to requirements 12.5 and 12.6
 	if err != nil {
+		cli.ReportError(os.Stderr, err)
 		// Non-zero exit code for errors (requirement 12.6)
 		os.Exit(1)
 	}